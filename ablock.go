@@ -0,0 +1,338 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom/varintf"
+)
+
+// ABEntryType is the type byte of an Admin Block Entry.
+type ABEntryType byte
+
+// Admin Block Entry types.
+const (
+	ABEntryMinuteNumber ABEntryType = iota
+	ABEntryDBSignature
+	ABEntryRevealMatryoshkaHash
+	ABEntryAddReplaceMatryoshkaHash
+	ABEntryIncreaseServerCount
+	ABEntryAddFederatedServer
+	ABEntryAddAuditServer
+	ABEntryRemoveFederatedServer
+	ABEntryAddFederatedServerSigningKey
+	ABEntryAddFederatedServerBitcoinAnchorKey
+	ABEntryServerFault
+	ABEntryCoinbaseDescriptor
+	ABEntryCoinbaseDescriptorCancel
+	ABEntryAddFactoidAddress
+	ABEntryAddFactoidEfficiency
+)
+
+// abEntryFixedSizes are the sizes of the Admin Block Entry types that do not
+// encode their size, not including the type byte.
+var abEntryFixedSizes = map[ABEntryType]int{
+	ABEntryMinuteNumber:                       1,
+	ABEntryDBSignature:                        32 + 32 + 64,
+	ABEntryRevealMatryoshkaHash:               32 + 32,
+	ABEntryAddReplaceMatryoshkaHash:           32 + 32,
+	ABEntryIncreaseServerCount:                1,
+	ABEntryAddFederatedServer:                 32 + 4,
+	ABEntryAddAuditServer:                     32 + 4,
+	ABEntryRemoveFederatedServer:              32 + 4,
+	ABEntryAddFederatedServerSigningKey:       32 + 1 + 32 + 4,
+	ABEntryAddFederatedServerBitcoinAnchorKey: 32 + 1 + 1 + 20,
+}
+
+// serverFaultHeaderSize is the size of a ServerFault Admin Block Entry
+// preceding its signatures, not including the type byte.
+const serverFaultHeaderSize = 6 + // Timestamp
+	32 + // ServerID
+	32 + // AuditServerID
+	1 + // VMIndex
+	4 + // DBHeight
+	4 + // Height
+	4 // Signature Count
+
+// serverFaultSignatureSize is the size of each signature in a ServerFault.
+const serverFaultSignatureSize = 32 + 64
+
+// ABEntry is an Admin Block Entry. Data holds the raw data following the Type
+// byte.
+type ABEntry struct {
+	Type ABEntryType
+	Data Bytes
+}
+
+// ABlock represents a Factom Admin Block.
+type ABlock struct {
+	// Computed Fields
+	LookupHash        *Bytes32
+	BackReferenceHash *Bytes32
+
+	// Header Fields
+	PrevBackReferenceHash *Bytes32
+	Height                uint32
+
+	// Expansion is the expansion space in the ABlock header.
+	Expansion Bytes
+
+	// Body Fields
+	Entries []ABEntry
+
+	// marshalBinaryCache is the binary data of the ABlock. It is cached by
+	// UnmarshalBinary so it can be re-used by MarshalBinary.
+	marshalBinaryCache []byte
+}
+
+// ClearMarshalBinaryCache discards the cached MarshalBinary data.
+//
+// Subsequent calls to MarshalBinary will re-construct the data from the fields
+// of the ABlock.
+func (ab *ABlock) ClearMarshalBinaryCache() {
+	ab.marshalBinaryCache = nil
+}
+
+// IsPopulated returns true if ab has already been successfully populated by a
+// call to Get.
+func (ab ABlock) IsPopulated() bool {
+	return ab.LookupHash != nil &&
+		ab.BackReferenceHash != nil &&
+		ab.PrevBackReferenceHash != nil
+}
+
+// Get queries factomd for the Admin Block at ab.LookupHash, if not nil, or
+// otherwise at ab.Height.
+func (ab *ABlock) Get(ctx context.Context, c *Client) error {
+	if ab.IsPopulated() {
+		return nil
+	}
+
+	if ab.LookupHash != nil {
+		params := struct {
+			Hash *Bytes32 `json:"hash"`
+		}{Hash: ab.LookupHash}
+		var result struct {
+			Data Bytes `json:"data"`
+		}
+		if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+			return err
+		}
+		return ab.UnmarshalBinary(result.Data)
+	}
+
+	params := struct {
+		Height uint32 `json:"height"`
+	}{ab.Height}
+	var result struct {
+		RawData Bytes `json:"rawdata"`
+	}
+	if err := c.FactomdRequest(ctx, "ablock-by-height", params, &result); err != nil {
+		return err
+	}
+	return ab.UnmarshalBinary(result.RawData)
+}
+
+// ABlockHeaderMinSize is the minimum expected ABlock Header Size.
+const ABlockHeaderMinSize = 32 + // Admin Block ChainID
+	32 + // PrevBackReferenceHash
+	4 + // DB Height
+	1 + // Header Expansion size (varint)
+	0 + // Header Expansion Area (Min 0)
+	4 + // Entry Count
+	4 // Body Size
+
+// UnmarshalBinary unmarshals raw ABlock data and populates ab.LookupHash and
+// ab.BackReferenceHash. If ab.LookupHash is populated, it is verified. The
+// following format is expected for data.
+//
+// Header
+//
+//	[Admin Block ChainID (Bytes32{31:0x0a})] +
+//	[PrevBackReferenceHash (Bytes32)] +
+//	[DB Height (4 bytes)] +
+//	[Header Expansion size (varint)] +
+//	[Header Expansion Area (Bytes)] +
+//	[Entry Count (4 bytes)] +
+//	[Body Size (4 bytes)]
+//
+// Body
+//
+//	[Entry 0 Type (1 byte)] + [Entry 0 Data (Bytes)] +
+//	... +
+//	[Entry N Type (1 byte)] + [Entry N Data (Bytes)]
+//
+// https://github.com/FactomProject/FactomDocs/blob/master/factomDataStructureDetails.md#administrative-block
+func (ab *ABlock) UnmarshalBinary(data []byte) error {
+	if len(data) < ABlockHeaderMinSize {
+		return fmt.Errorf("insufficient length")
+	}
+
+	if bytes.Compare(data[:32], aBlockChainID[:]) != 0 {
+		return fmt.Errorf("invalid admin chainid")
+	}
+
+	i := 32
+
+	ab.PrevBackReferenceHash = new(Bytes32)
+	i += copy(ab.PrevBackReferenceHash[:], data[i:])
+
+	ab.Height = binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+
+	expansionSize, read := varintf.Decode(data[i:])
+	if read < 0 {
+		return fmt.Errorf("expansion size is not a valid varint")
+	}
+	i += read
+
+	if len(data[i:]) < 8 || expansionSize > uint64(len(data[i:])-8) {
+		return fmt.Errorf("expansion size is larger than remaining data")
+	}
+	ab.Expansion = data[i : i+int(expansionSize)]
+	i += int(expansionSize)
+
+	entryCount := binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+	bodySize := binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+
+	if uint64(bodySize) != uint64(len(data[i:])) {
+		return fmt.Errorf("invalid body size")
+	}
+	if uint64(entryCount) > uint64(bodySize) {
+		return fmt.Errorf("unreasonable entry count")
+	}
+
+	ab.Entries = make([]ABEntry, entryCount)
+	for c := range ab.Entries {
+		if i >= len(data) {
+			return fmt.Errorf("insufficient length")
+		}
+		e := &ab.Entries[c]
+		e.Type = ABEntryType(data[i])
+		i++
+		size, err := abEntrySize(e.Type, data[i:])
+		if err != nil {
+			return fmt.Errorf("entry %v: %w", c, err)
+		}
+		e.Data = data[i : i+size]
+		i += size
+	}
+	if i != len(data) {
+		return fmt.Errorf("invalid body size")
+	}
+
+	lookupHash := Bytes32(sha256Sum(data))
+	if ab.LookupHash == nil {
+		ab.LookupHash = &lookupHash
+	} else if lookupHash != *ab.LookupHash {
+		return fmt.Errorf("invalid lookup hash")
+	}
+	backRefHash := sha512Sum(data)
+	ab.BackReferenceHash = new(Bytes32)
+	copy(ab.BackReferenceHash[:], backRefHash[:])
+
+	ab.marshalBinaryCache = data
+
+	return nil
+}
+
+// abEntrySize returns the size of the data of an Admin Block Entry of type t
+// at the start of data.
+func abEntrySize(t ABEntryType, data []byte) (int, error) {
+	var size int
+	if fixed, ok := abEntryFixedSizes[t]; ok {
+		size = fixed
+	} else if t == ABEntryServerFault {
+		if len(data) < serverFaultHeaderSize {
+			return 0, fmt.Errorf("insufficient length")
+		}
+		count := binary.BigEndian.Uint32(data[serverFaultHeaderSize-4:])
+		if uint64(count)*serverFaultSignatureSize > uint64(len(data)) {
+			return 0, fmt.Errorf("unreasonable signature count")
+		}
+		size = serverFaultHeaderSize + int(count)*serverFaultSignatureSize
+	} else {
+		// All other types, including unknown types, are prefixed by
+		// their size.
+		bodySize, read := varintf.Decode(data)
+		if read < 0 {
+			return 0, fmt.Errorf("entry size is not a valid varint")
+		}
+		if bodySize > uint64(len(data[read:])) {
+			return 0, fmt.Errorf("entry size is larger than remaining data")
+		}
+		size = read + int(bodySize)
+	}
+	if size > len(data) {
+		return 0, fmt.Errorf("insufficient length")
+	}
+	return size, nil
+}
+
+// MarshalBinary marshals the ABlock into its binary form. If the ABlock was
+// orignally Unmarshaled, then the cached data is re-used, so this is
+// efficient. See ClearMarshalBinaryCache.
+func (ab ABlock) MarshalBinary() ([]byte, error) {
+	if ab.marshalBinaryCache != nil {
+		return ab.marshalBinaryCache, nil
+	}
+
+	if ab.PrevBackReferenceHash == nil {
+		return nil, fmt.Errorf("not populated")
+	}
+
+	var bodySize int
+	for _, e := range ab.Entries {
+		bodySize += 1 + len(e.Data)
+	}
+
+	expansionSize := varintf.Encode(uint64(len(ab.Expansion)))
+	data := make([]byte, ABlockHeaderMinSize-1+
+		len(expansionSize)+len(ab.Expansion)+bodySize)
+
+	var i int
+	i += copy(data[i:], aBlockChainID[:])
+	i += copy(data[i:], ab.PrevBackReferenceHash[:])
+	binary.BigEndian.PutUint32(data[i:], ab.Height)
+	i += 4
+	i += copy(data[i:], expansionSize)
+	i += copy(data[i:], ab.Expansion)
+	binary.BigEndian.PutUint32(data[i:], uint32(len(ab.Entries)))
+	i += 4
+	binary.BigEndian.PutUint32(data[i:], uint32(bodySize))
+	i += 4
+
+	for _, e := range ab.Entries {
+		data[i] = byte(e.Type)
+		i++
+		i += copy(data[i:], e.Data)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testABlock() ABlock {
+	serverFault := make([]byte, 6+32+32+1+4+4+4+96)
+	binary.BigEndian.PutUint32(serverFault[6+32+32+1+4+4:], 1)
+	prev := Bytes32{1, 2, 3}
+	return ABlock{
+		PrevBackReferenceHash: &prev,
+		Height:                1001,
+		Entries: []ABEntry{
+			{Type: ABEntryDBSignature, Data: make([]byte, 128)},
+			{Type: ABEntryServerFault, Data: serverFault},
+			CoinbaseDescriptor{Outputs: []AddressAmount{
+				{Address: make([]byte, 32), Amount: 500},
+			}}.ABEntry(),
+			// Unknown types are size prefixed.
+			{Type: 0x20, Data: []byte{2, 0xaa, 0xbb}},
+		},
+	}
+}
+
+func TestABlockMarshalBinary(t *testing.T) {
+	ab := testABlock()
+	data, err := ab.MarshalBinary()
+	require.NoError(t, err)
+
+	var ab2 ABlock
+	require.NoError(t, ab2.UnmarshalBinary(data))
+	assert := assert.New(t)
+	assert.True(ab2.IsPopulated())
+	assert.Equal(ab.Height, ab2.Height)
+	assert.Equal(ab.PrevBackReferenceHash, ab2.PrevBackReferenceHash)
+	assert.Equal(ab.Entries, ab2.Entries)
+	assert.Equal(Bytes32(sha256.Sum256(data)), *ab2.LookupHash)
+
+	data2, err := ab2.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(data, data2)
+
+	// A populated LookupHash is verified.
+	ab3 := ABlock{LookupHash: &Bytes32{}}
+	assert.EqualError(ab3.UnmarshalBinary(data), "invalid lookup hash")
+}
+
+func TestABlockUnmarshalBinaryInvalid(t *testing.T) {
+	data, err := testABlock().MarshalBinary()
+	require.NoError(t, err)
+	for _, test := range []struct {
+		Name  string
+		Data  []byte
+		Error string
+	}{{
+		Name:  "no data",
+		Error: "insufficient length",
+	}, {
+		Name:  "chainid",
+		Data:  append([]byte{0x0b}, data[1:]...),
+		Error: "invalid admin chainid",
+	}, {
+		Name:  "truncated",
+		Data:  data[:len(data)-1],
+		Error: "invalid body size",
+	}, {
+		Name:  "trailing data",
+		Data:  append(append([]byte{}, data...), 0),
+		Error: "invalid body size",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			var ab ABlock
+			assert.EqualError(t, ab.UnmarshalBinary(test.Data), test.Error)
+		})
+	}
+}
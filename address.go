@@ -23,9 +23,13 @@
 package factom
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql/driver"
+	"fmt"
 
 	"crypto/ed25519"
 )
@@ -184,6 +188,40 @@ func (adr EsAddress) MarshalText() ([]byte, error) {
 	return adr.payload().MarshalTextWithPrefix(adr.PrefixBytes())
 }
 
+// Value implements driver.Valuer by returning adr.String().
+func (adr FAAddress) Value() (driver.Value, error) {
+	return adr.payload().ValueWithPrefix(adr.PrefixBytes())
+}
+
+// Value implements driver.Valuer by returning adr.String().
+func (adr ECAddress) Value() (driver.Value, error) {
+	return adr.payload().ValueWithPrefix(adr.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of adr.
+func (adr *FAAddress) Scan(v interface{}) error {
+	return adr.payload().ScanWithPrefix(v,
+		adr.PrefixString(), adr.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of adr.
+func (adr *FsAddress) Scan(v interface{}) error {
+	return adr.payload().ScanWithPrefix(v,
+		adr.PrefixString(), adr.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of adr.
+func (adr *ECAddress) Scan(v interface{}) error {
+	return adr.payload().ScanWithPrefix(v,
+		adr.PrefixString(), adr.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of adr.
+func (adr *EsAddress) Scan(v interface{}) error {
+	return adr.payload().ScanWithPrefix(v,
+		adr.PrefixString(), adr.PrefixBytes())
+}
+
 const adrStrLen = 52
 
 // GenerateFsAddress generates a secure random private Factoid address using
@@ -230,48 +268,102 @@ func NewEsAddress(adrStr string) (adr EsAddress, err error) {
 	return
 }
 
+// NewFsAddressFromPrivateKey returns the FsAddress for priv.
+func NewFsAddressFromPrivateKey(priv ed25519.PrivateKey) (adr FsAddress, err error) {
+	err = setPrivateKey(adr[:], priv)
+	return
+}
+
+// NewEsAddressFromPrivateKey returns the EsAddress for priv.
+func NewEsAddressFromPrivateKey(priv ed25519.PrivateKey) (adr EsAddress, err error) {
+	err = setPrivateKey(adr[:], priv)
+	return
+}
+
+// setPrivateKey copies the seed of priv into key, after checking that the
+// public key of priv corresponds to its seed.
+func setPrivateKey(key []byte, priv ed25519.PrivateKey) error {
+	if len(priv) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid private key length")
+	}
+	seed := priv.Seed()
+	if !bytes.Equal(ed25519.NewKeyFromSeed(seed), priv) {
+		return fmt.Errorf("invalid private key")
+	}
+	copy(key, seed)
+	return nil
+}
+
+// NewFAAddressFromRCDHash returns the FAAddress for the RCD with the given
+// hash.
+func NewFAAddressFromRCDHash(hash Bytes32) FAAddress {
+	return FAAddress(hash)
+}
+
+// NewFAAddressFromPublicKey returns the FAAddress for the RCD Type 1 of pub.
+func NewFAAddressFromPublicKey(pub ed25519.PublicKey) (FAAddress, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return FAAddress{}, fmt.Errorf("invalid public key length")
+	}
+	rcd := append(RCD{byte(RCDType01)}, pub...)
+	return rcd.FAAddress(), nil
+}
+
+// NewECAddressFromPublicKey returns the ECAddress for pub.
+func NewECAddressFromPublicKey(pub ed25519.PublicKey) (adr ECAddress, err error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return adr, fmt.Errorf("invalid public key length")
+	}
+	copy(adr[:], pub)
+	return
+}
+
 // Set attempts to parse adrStr into adr.
 func (adr *FAAddress) Set(adrStr string) error {
-	return adr.payload().SetWithPrefix(adrStr, adr.PrefixString())
+	return adr.payload().SetWithPrefixBytes(adrStr,
+		adr.PrefixString(), adr.PrefixBytes())
 }
 
 // Set attempts to parse adrStr into adr.
 func (adr *FsAddress) Set(adrStr string) error {
-	return adr.payload().SetWithPrefix(adrStr, adr.PrefixString())
+	return adr.payload().SetWithPrefixBytes(adrStr,
+		adr.PrefixString(), adr.PrefixBytes())
 }
 
 // Set attempts to parse adrStr into adr.
 func (adr *ECAddress) Set(adrStr string) error {
-	return adr.payload().SetWithPrefix(adrStr, adr.PrefixString())
+	return adr.payload().SetWithPrefixBytes(adrStr,
+		adr.PrefixString(), adr.PrefixBytes())
 }
 
 // Set attempts to parse adrStr into adr.
 func (adr *EsAddress) Set(adrStr string) error {
-	return adr.payload().SetWithPrefix(adrStr, adr.PrefixString())
+	return adr.payload().SetWithPrefixBytes(adrStr,
+		adr.PrefixString(), adr.PrefixBytes())
 }
 
 // UnmarshalText decodes a string with a human readable public Factoid address
 // into adr.
 func (adr *FAAddress) UnmarshalText(text []byte) error {
-	return adr.payload().UnmarshalTextWithPrefix(text, adr.PrefixString())
+	return adr.Set(string(text))
 }
 
 // UnmarshalText decodes a string with a human readable secret Factoid address
 // into adr.
 func (adr *FsAddress) UnmarshalText(text []byte) error {
-	return adr.payload().UnmarshalTextWithPrefix(text, adr.PrefixString())
+	return adr.Set(string(text))
 }
 
 // UnmarshalText decodes a string with a human readable public Entry Credit
 // address into adr.
 func (adr *ECAddress) UnmarshalText(text []byte) error {
-	return adr.payload().UnmarshalTextWithPrefix(text, adr.PrefixString())
+	return adr.Set(string(text))
 }
 
 // UnmarshalText decodes a string with a human readable secret Entry Credit
 // address into adr.
 func (adr *EsAddress) UnmarshalText(text []byte) error {
-	return adr.payload().UnmarshalTextWithPrefix(text, adr.PrefixString())
+	return adr.Set(string(text))
 }
 
 // GetFsAddress queries factom-walletd for the FsAddress corresponding to adr.
@@ -373,11 +465,11 @@ func (c *Client) getBalance(ctx context.Context, method, adrStr string) (uint64,
 	params := struct {
 		Address string `json:"address"`
 	}{Address: adrStr}
-	var result struct{ Balance uint64 }
+	var result struct{ Balance Amount }
 	if err := c.FactomdRequest(ctx, method, params, &result); err != nil {
 		return 0, err
 	}
-	return result.Balance, nil
+	return uint64(result.Balance), nil
 }
 
 // Remove adr from factom-walletd. WARNING: THIS IS DESTRUCTIVE.
@@ -430,6 +522,17 @@ func (adr FsAddress) Sign(msg []byte) []byte {
 	return ed25519.Sign(adr.PrivateKey(), msg)
 }
 
+// Sign the msg.
+func (adr EsAddress) Sign(msg []byte) []byte {
+	return ed25519.Sign(adr.PrivateKey(), msg)
+}
+
+// RCDHash returns the hash of the RCD for adr, which is the raw address
+// without the prefix or checksum.
+func (adr FAAddress) RCDHash() Bytes32 {
+	return Bytes32(adr)
+}
+
 // PublicKey returns the ed25519.PublicKey for adr.
 func (adr ECAddress) PublicKey() ed25519.PublicKey {
 	return adr[:]
@@ -454,3 +557,19 @@ func (adr FsAddress) PrivateKey() ed25519.PrivateKey {
 func (adr EsAddress) PrivateKey() ed25519.PrivateKey {
 	return ed25519.NewKeyFromSeed(adr[:])
 }
+
+// Signer returns the ed25519.PrivateKey for adr as a crypto.Signer, for use
+// with the standard library and other packages that sign with a
+// crypto.Signer. FsAddress cannot implement crypto.Signer itself because its
+// Sign method signs for RCD Type 1.
+func (adr FsAddress) Signer() crypto.Signer {
+	return adr.PrivateKey()
+}
+
+// Signer returns the ed25519.PrivateKey for adr as a crypto.Signer, for use
+// with the standard library and other packages that sign with a
+// crypto.Signer. EsAddress cannot implement crypto.Signer itself because its
+// Sign method signs commits.
+func (adr EsAddress) Signer() crypto.Signer {
+	return adr.PrivateKey()
+}
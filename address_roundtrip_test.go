@@ -0,0 +1,222 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/addressvectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addressKey is implemented by pointers to all Address and IDKey types.
+type addressKey interface {
+	fmt.Stringer
+	Set(string) error
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+	sql.Scanner
+}
+
+// payloadOf returns the 32 byte payload of adr.
+func payloadOf(adr addressKey) []byte {
+	v := reflect.ValueOf(adr).Elem()
+	payload := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(payload), v)
+	return payload
+}
+
+// setPayload sets the 32 byte payload of adr.
+func setPayload(adr addressKey, payload []byte) {
+	reflect.Copy(reflect.ValueOf(adr).Elem(), reflect.ValueOf(payload))
+}
+
+// addressKeyTypes maps human readable prefixes to a constructor for the
+// corresponding type.
+var addressKeyTypes = map[string]func() addressKey{
+	"FA":  func() addressKey { return new(FAAddress) },
+	"Fs":  func() addressKey { return new(FsAddress) },
+	"EC":  func() addressKey { return new(ECAddress) },
+	"Es":  func() addressKey { return new(EsAddress) },
+	"id1": func() addressKey { return new(ID1Key) },
+	"sk1": func() addressKey { return new(SK1Key) },
+	"id2": func() addressKey { return new(ID2Key) },
+	"sk2": func() addressKey { return new(SK2Key) },
+	"id3": func() addressKey { return new(ID3Key) },
+	"sk3": func() addressKey { return new(SK3Key) },
+	"id4": func() addressKey { return new(ID4Key) },
+	"sk4": func() addressKey { return new(SK4Key) },
+}
+
+// public returns the public address or key of the private key priv.
+func public(priv addressKey) addressKey {
+	switch priv := priv.(type) {
+	case *FsAddress:
+		pub := priv.FAAddress()
+		return &pub
+	case *EsAddress:
+		pub := priv.ECAddress()
+		return &pub
+	case *SK1Key:
+		pub := priv.ID1Key()
+		return &pub
+	case *SK2Key:
+		pub := priv.ID2Key()
+		return &pub
+	case *SK3Key:
+		pub := priv.ID3Key()
+		return &pub
+	case *SK4Key:
+		pub := priv.ID4Key()
+		return &pub
+	}
+	panic(fmt.Sprintf("not a private key type: %T", priv))
+}
+
+// testRoundTrip asserts that adr round trips through its string, payload,
+// JSON, and SQL encodings.
+func testRoundTrip(t *testing.T, adr addressKey, newAdr func() addressKey) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// string
+	str := adr.String()
+	fromStr := newAdr()
+	require.NoError(fromStr.Set(str))
+	assert.Equal(adr, fromStr, "string")
+
+	// payload
+	payload := payloadOf(adr)
+	require.Len(payload, 32)
+	fromPayload := newAdr()
+	setPayload(fromPayload, payload)
+	assert.Equal(adr, fromPayload, "payload")
+	assert.Equal(str, fromPayload.String())
+
+	// JSON
+	data, err := json.Marshal(adr)
+	require.NoError(err)
+	assert.Equal(fmt.Sprintf("%q", str), string(data))
+	fromJSON := newAdr()
+	require.NoError(json.Unmarshal(data, fromJSON))
+	assert.Equal(adr, fromJSON, "JSON")
+
+	// SQL, from a string column, which drivers may return as []byte.
+	if valuer, ok := adr.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		require.NoError(err)
+		assert.Equal(str, value, "SQL value")
+	}
+	fromSQL := newAdr()
+	require.NoError(fromSQL.Scan(str))
+	assert.Equal(adr, fromSQL, "SQL string")
+	fromSQL = newAdr()
+	require.NoError(fromSQL.Scan([]byte(str)))
+	assert.Equal(adr, fromSQL, "SQL []byte")
+}
+
+func TestAddressVectors(t *testing.T) {
+	for _, v := range addressvectors.Vectors {
+		v := v
+		t.Run(v.Private, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			newPriv := addressKeyTypes[v.PrivateType]
+			newPub := addressKeyTypes[v.PublicType]
+			require.NotNil(newPriv)
+			require.NotNil(newPub)
+
+			priv := newPriv()
+			require.NoError(priv.Set(v.Private))
+			assert.Equal(v.PrivatePayload,
+				hex.EncodeToString(payloadOf(priv)))
+			_, ok := priv.(driver.Valuer)
+			assert.False(ok, "private keys must not be driver.Valuers")
+
+			pub := public(priv)
+			assert.Equal(v.Public, pub.String())
+			assert.Equal(v.PublicPayload,
+				hex.EncodeToString(payloadOf(pub)))
+			_, ok = pub.(driver.Valuer)
+			assert.True(ok)
+
+			if rcder, ok := priv.(interface{ RCD() RCD }); ok {
+				assert.Equal(v.RCD, hex.EncodeToString(rcder.RCD()))
+				assert.Equal(v.PublicPayload,
+					rcder.RCD().Hash().String())
+			} else {
+				assert.Empty(v.RCD)
+			}
+
+			testRoundTrip(t, priv, newPriv)
+			testRoundTrip(t, pub, newPub)
+		})
+	}
+}
+
+func TestAddressRoundTrip(t *testing.T) {
+	for prefix, newAdr := range addressKeyTypes {
+		prefix, newAdr := prefix, newAdr
+		t.Run(prefix, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				var payload [32]byte
+				_, err := rand.Read(payload[:])
+				require.NoError(t, err)
+				adr := newAdr()
+				setPayload(adr, payload[:])
+				testRoundTrip(t, adr, newAdr)
+			}
+		})
+	}
+}
+
+func TestAddressScan(t *testing.T) {
+	assert := assert.New(t)
+	var fa FAAddress
+	assert.EqualError(fa.Scan(make([]byte, 32)), "invalid length")
+	assert.EqualError(fa.Scan(1), "invalid type: int")
+	assert.EqualError(fa.Scan(FsAddressStr), "invalid prefix")
+	assert.EqualError(fa.Scan([]byte(FsAddressStr)), "invalid prefix")
+
+	// The human readable prefix is correct, but the prefix bytes are not.
+	adr, err := NewFAAddress(FAAddressStr)
+	require.NoError(t, err)
+	str := EncodePrefixed([]byte{0x5f, 0xb2}, adr[:])
+	require.Equal(t, "FA", str[:2])
+	assert.EqualError(fa.Scan(str), "invalid prefix")
+	assert.EqualError(fa.Scan([]byte(str)), "invalid prefix")
+	assert.Equal(FAAddress{}, fa)
+
+	require.NoError(t, fa.Scan([]byte(FAAddressStr)))
+	assert.Equal(adr, fa)
+}
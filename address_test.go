@@ -24,6 +24,9 @@ package factom
 
 import (
 	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -131,6 +134,16 @@ var addressUnmarshalJSONTests = []addressUnmarshalJSONTest{{
 	Name: "invalid prefix",
 	Data: fmt.Sprintf("%q", FsAddressStr),
 	Err:  "invalid prefix",
+}, {
+	// The string prefix is "FA" but the prefix bytes are not.
+	Name: "invalid prefix bytes",
+	Data: fmt.Sprintf("%q", func() string {
+		adr, _ := NewFAAddress(FAAddressStr)
+		return adr.payload().StringWithPrefix([]byte{0x5f, 0xb2})
+	}()),
+	Err:    "invalid prefix",
+	Adr:    new(FAAddress),
+	ExpAdr: new(FAAddress),
 }, {
 	Name:   "invalid symbol/FA",
 	Data:   fmt.Sprintf("%q", FAAddressStr[0:len(FAAddressStr)-1]+"0"),
@@ -216,7 +229,7 @@ func TestAddress(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	c := NewClient()
+	c := IntegrationClient(t)
 	t.Run("Save/Fs", func(t *testing.T) {
 		err := fs.Save(nil, c)
 		assert.NoError(t, err)
@@ -242,7 +255,7 @@ func TestAddress(t *testing.T) {
 			assert.Equal(uint64(0), balance)
 		})
 	}
-	fundedEC, _ := NewECAddress("EC1zANmWuEMYoH6VizJg6uFaEdi8Excn1VbLN99KRuxh3GSvB7YQ")
+	fundedEC := Integration.Es.ECAddress()
 	t.Run("GetBalance/"+fundedEC.String(), func(t *testing.T) {
 		balance, err := fundedEC.GetBalance(nil, c)
 		assert := assert.New(t)
@@ -259,3 +272,58 @@ func TestAddress(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestNewAddressFromPublicKey(t *testing.T) {
+	assert := assert.New(t)
+	fs, err := GenerateFsAddress()
+	assert.NoError(err)
+	es, err := GenerateEsAddress()
+	assert.NoError(err)
+
+	fa, err := NewFAAddressFromPublicKey(fs.PublicKey())
+	assert.NoError(err)
+	assert.Equal(fs.FAAddress(), fa)
+	assert.Equal(fa, NewFAAddressFromRCDHash(fa.RCDHash()))
+	assert.Equal(fs.RCD().Hash(), fa.RCDHash())
+
+	ec, err := NewECAddressFromPublicKey(es.PublicKey())
+	assert.NoError(err)
+	assert.Equal(es.ECAddress(), ec)
+	assert.Equal(es.PublicKey(), ec.PublicKey())
+
+	_, err = NewFAAddressFromPublicKey(fs.PublicKey()[1:])
+	assert.Error(err)
+	_, err = NewECAddressFromPublicKey(nil)
+	assert.Error(err)
+}
+
+func TestAddressPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+	fs, err := GenerateFsAddress()
+	assert.NoError(err)
+	es, err := GenerateEsAddress()
+	assert.NoError(err)
+
+	adr, err := NewFsAddressFromPrivateKey(fs.PrivateKey())
+	assert.NoError(err)
+	assert.Equal(fs, adr)
+	ecAdr, err := NewEsAddressFromPrivateKey(es.PrivateKey())
+	assert.NoError(err)
+	assert.Equal(es, ecAdr)
+
+	_, err = NewFsAddressFromPrivateKey(fs.PrivateKey()[1:])
+	assert.Error(err)
+	priv := es.PrivateKey()
+	priv[ed25519.PrivateKeySize-1]++
+	_, err = NewEsAddressFromPrivateKey(priv)
+	assert.Error(err)
+
+	msg := []byte("hello")
+	signer := fs.Signer()
+	assert.Equal(fs.PublicKey(), signer.Public())
+	sig, err := signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	assert.NoError(err)
+	assert.Equal(fs.Sign(msg), sig)
+	assert.True(ed25519.Verify(es.Signer().Public().(ed25519.PublicKey),
+		msg, es.Sign(msg)))
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AddressURIScheme is the URI scheme that NormalizeAddress strips from
+// addresses, such as in "factom:FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTueb".
+const AddressURIScheme = "factom"
+
+// ErrorAddressCharacter is returned by NormalizeAddress for a character that
+// can never appear in an address.
+type ErrorAddressCharacter struct {
+	// Char is the invalid character.
+	Char rune
+
+	// Position is the index of Char, counted in characters from the start
+	// of the original input, so that it may be highlighted.
+	Position int
+}
+
+// Error implements error.
+func (err ErrorAddressCharacter) Error() string {
+	return fmt.Sprintf("invalid character %q at position %v",
+		err.Char, err.Position)
+}
+
+// NormalizeAddress leniently cleans up a human readable address entered by a
+// user, and returns it in the form accepted by the strict parsers, such as
+// NewFAAddress, which remain the default.
+//
+// All whitespace, including between characters, is removed. A leading
+// AddressURIScheme, matched case insensitively with or without "//", and any
+// URI query, which starts with '?', are stripped. Since base58 is case
+// sensitive, the case of the address itself is never changed.
+//
+// If any remaining character is not in the base58 alphabet, an
+// ErrorAddressCharacter is returned. The prefix, length, and checksum are not
+// checked.
+func NormalizeAddress(input string) (string, error) {
+	runes := []rune(input)
+	pos := 0
+	for pos < len(runes) && unicode.IsSpace(runes[pos]) {
+		pos++
+	}
+
+	scheme := []rune(AddressURIScheme + ":")
+	if len(runes)-pos >= len(scheme) && strings.EqualFold(
+		string(runes[pos:pos+len(scheme)]), string(scheme)) {
+		pos += len(scheme)
+		if len(runes)-pos >= 2 && string(runes[pos:pos+2]) == "//" {
+			pos += 2
+		}
+	}
+
+	var adr strings.Builder
+	for ; pos < len(runes); pos++ {
+		r := runes[pos]
+		if r == '?' {
+			break
+		}
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if r > unicode.MaxASCII ||
+			strings.IndexByte(base58Alphabet, byte(r)) < 0 {
+			return "", ErrorAddressCharacter{Char: r, Position: pos}
+		}
+		adr.WriteRune(r)
+	}
+	return adr.String(), nil
+}
@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	const adr = "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTueb"
+	for _, test := range []struct {
+		Name  string
+		Input string
+		Err   string
+	}{{
+		Name:  "canonical",
+		Input: adr,
+	}, {
+		Name:  "whitespace",
+		Input: " \t" + adr[:20] + " " + adr[20:40] + "\n" + adr[40:] + "\n",
+	}, {
+		Name:  "uri",
+		Input: "factom:" + adr,
+	}, {
+		Name:  "uri case insensitive",
+		Input: "  Factom://" + adr + "?amount=1",
+	}, {
+		Name:  "invalid character",
+		Input: " factom:" + adr[:10] + "0" + adr[11:],
+		Err:   `invalid character '0' at position 18`,
+	}, {
+		Name:  "non ascii",
+		Input: "FA2Pé",
+		Err:   `invalid character 'é' at position 4`,
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			normalized, err := NormalizeAddress(test.Input)
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, adr, normalized)
+			_, err = NewFAAddress(normalized)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Factom-Asset-Tokens/base58"
+)
+
+// base58Alphabet is the alphabet of the base58 encoding used by all human
+// readable addresses.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// addressStrLen is the length of all human readable addresses, including the
+// two character prefix.
+const addressStrLen = 52
+
+// ErrorInvalidAddress is returned by WithAddressCorrections when an address
+// failed to parse, but there are valid addresses that differ from it by a
+// single character edit.
+type ErrorInvalidAddress struct {
+	// Address is the address that failed to parse.
+	Address string
+
+	// Err is the error returned when parsing Address.
+	Err error
+
+	// Corrections are the valid addresses, in sorted order, that differ
+	// from Address by a single character edit.
+	Corrections []string
+}
+
+// Error implements error.
+func (err ErrorInvalidAddress) Error() string {
+	return fmt.Sprintf("%v: %v suggested corrections",
+		err.Err, len(err.Corrections))
+}
+
+// Unwrap returns err.Err.
+func (err ErrorInvalidAddress) Unwrap() error {
+	return err.Err
+}
+
+// WithAddressCorrections returns an ErrorInvalidAddress with the
+// SuggestAddressCorrections for adrStr, if err is not nil and there are any.
+// Otherwise err is returned unchanged.
+//
+// This is intended to wrap the error from parsing a mistyped address, such as
+// from NewFAAddress, so that wallet UIs can suggest corrections.
+//
+//	adr, err := factom.NewFAAddress(adrStr)
+//	err = factom.WithAddressCorrections(err, adrStr)
+func WithAddressCorrections(err error, adrStr string) error {
+	if err == nil {
+		return nil
+	}
+	corrections := SuggestAddressCorrections(adrStr)
+	if len(corrections) == 0 {
+		return err
+	}
+	return ErrorInvalidAddress{Address: adrStr, Err: err,
+		Corrections: corrections}
+}
+
+// SuggestAddressCorrections returns all valid human readable addresses that
+// differ from adrStr by a single character substitution, insertion, deletion,
+// or transposition of adjacent characters, after the two character prefix.
+// The prefix of adrStr must be that of an FAAddress, FsAddress, ECAddress, or
+// EsAddress, and is never changed.
+//
+// An address with a valid checksum has no corrections.
+func SuggestAddressCorrections(adrStr string) []string {
+	if len(adrStr) < addressStrLen-1 || len(adrStr) > addressStrLen+1 {
+		return nil
+	}
+	prefix := adrStr[:2]
+	switch prefix {
+	case FAAddress{}.PrefixString(), FsAddress{}.PrefixString(),
+		ECAddress{}.PrefixString(), EsAddress{}.PrefixString():
+	default:
+		return nil
+	}
+	if len(adrStr) == addressStrLen && isValidAddress(adrStr) {
+		return nil
+	}
+
+	found := make(map[string]struct{})
+	try := func(candidate []byte) {
+		if str := string(candidate); isValidAddress(str) {
+			found[str] = struct{}{}
+		}
+	}
+
+	body := adrStr[len(prefix):]
+	switch len(adrStr) {
+	case addressStrLen:
+		candidate := []byte(adrStr)
+		for i := len(prefix); i < len(candidate); i++ {
+			orig := candidate[i]
+			for j := 0; j < len(base58Alphabet); j++ {
+				if base58Alphabet[j] == orig {
+					continue
+				}
+				candidate[i] = base58Alphabet[j]
+				try(candidate)
+			}
+			candidate[i] = orig
+		}
+		for i := len(prefix); i < len(candidate)-1; i++ {
+			if candidate[i] == candidate[i+1] {
+				continue
+			}
+			candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+			try(candidate)
+			candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		}
+	case addressStrLen - 1:
+		// Insert each character at each position.
+		for i := 0; i <= len(body); i++ {
+			for j := 0; j < len(base58Alphabet); j++ {
+				try([]byte(prefix + body[:i] +
+					base58Alphabet[j:j+1] + body[i:]))
+			}
+		}
+	case addressStrLen + 1:
+		// Delete each character.
+		for i := 0; i < len(body); i++ {
+			try([]byte(prefix + body[:i] + body[i+1:]))
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+	corrections := make([]string, 0, len(found))
+	for str := range found {
+		corrections = append(corrections, str)
+	}
+	sort.Strings(corrections)
+	return corrections
+}
+
+// isValidAddress returns true if str has a valid base58check encoding with
+// the prefix bytes of the address type of its prefix string.
+func isValidAddress(str string) bool {
+	if len(str) != addressStrLen {
+		return false
+	}
+	var prefix Bytes
+	switch str[:2] {
+	case FAAddress{}.PrefixString():
+		prefix = FAAddress{}.PrefixBytes()
+	case FsAddress{}.PrefixString():
+		prefix = FsAddress{}.PrefixBytes()
+	case ECAddress{}.PrefixString():
+		prefix = ECAddress{}.PrefixBytes()
+	case EsAddress{}.PrefixString():
+		prefix = EsAddress{}.PrefixBytes()
+	default:
+		return false
+	}
+	_, version, err := base58.CheckDecode(str, len(prefix))
+	return err == nil && string(version) == string(prefix)
+}
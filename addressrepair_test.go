@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/base58"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestAddressCorrections(t *testing.T) {
+	const valid = "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTueb"
+	for _, test := range []struct {
+		Name    string
+		Address string
+		None    bool
+	}{{
+		Name:    "substitution",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTuec",
+	}, {
+		Name:    "invalid character",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTu0b",
+	}, {
+		Name:    "transposition",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTeub",
+	}, {
+		Name:    "deletion",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTub",
+	}, {
+		Name:    "insertion",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTuebb",
+	}, {
+		Name:    "valid",
+		Address: valid,
+		None:    true,
+	}, {
+		Name:    "unknown prefix",
+		Address: "FX2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTuec",
+		None:    true,
+	}, {
+		Name:    "too short",
+		Address: "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nT",
+		None:    true,
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			corrections := SuggestAddressCorrections(test.Address)
+			if test.None {
+				assert.Empty(t, corrections)
+				return
+			}
+			assert.Contains(t, corrections, valid)
+			for _, adr := range corrections {
+				_, err := NewFAAddress(adr)
+				assert.NoError(t, err, adr)
+			}
+		})
+	}
+}
+
+func TestWithAddressCorrections(t *testing.T) {
+	assert := assert.New(t)
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	valid := []byte(fs.String())
+	valid[10], valid[11] = valid[11], valid[10]
+	if valid[10] == valid[11] {
+		valid[10] = '1'
+	}
+	mistyped := string(valid)
+
+	_, err = NewFsAddress(mistyped)
+	require.Equal(t, base58.ErrChecksum, err)
+	err = WithAddressCorrections(err, mistyped)
+	var repair ErrorInvalidAddress
+	require.True(t, errors.As(err, &repair))
+	assert.True(errors.Is(err, base58.ErrChecksum))
+	assert.Equal(mistyped, repair.Address)
+	assert.Contains(repair.Corrections, fs.String())
+
+	assert.NoError(WithAddressCorrections(nil, mistyped))
+	otherErr := errors.New("other")
+	assert.Equal(otherErr, WithAddressCorrections(otherErr, "FA"))
+}
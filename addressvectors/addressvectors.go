@@ -0,0 +1,208 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package addressvectors provides test vectors for all Factom address and
+// identity key types.
+//
+// Each Vector relates a private key to its public address or key, in both
+// human readable and raw payload form. The same vectors are available in
+// vectors.json so that implementations in other languages can validate
+// against them.
+package addressvectors
+
+// Vector is a private key and the public address or key derived from it.
+type Vector struct {
+	// PrivateType is the human readable prefix of Private: "Fs", "Es",
+	// "sk1", "sk2", "sk3", or "sk4".
+	PrivateType string `json:"private_type"`
+	Private     string `json:"private"`
+	// PrivatePayload is the hex encoded 32 byte ed25519 seed.
+	PrivatePayload string `json:"private_payload"`
+
+	// PublicType is the human readable prefix of Public: "FA", "EC",
+	// "id1", "id2", "id3", or "id4".
+	PublicType string `json:"public_type"`
+	Public     string `json:"public"`
+	// PublicPayload is the hex encoded 32 byte payload of Public. This is
+	// the ed25519 public key for EC addresses, and the sha256d hash of
+	// the RCD for all other types.
+	PublicPayload string `json:"public_payload"`
+
+	// RCD is the hex encoded Type 1 RCD, or empty for EC addresses.
+	RCD string `json:"rcd"`
+}
+
+// Vectors are the address and key test vectors. They are identical to the
+// contents of vectors.json.
+var Vectors = []Vector{{
+	PrivateType:    "Fs",
+	Private:        "Fs1ipNRjEXcWj8RUn1GRLMJYVoPFBL1yw9rn6sCxWGcxciC4HdPd",
+	PrivatePayload: "34ee70110fce5e2bab82b012087b802e78cacf6e950957436811d64f24f7ec14",
+	PublicType:     "FA",
+	Public:         "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTueb",
+	PublicPayload:  "37bd0f217ea091bb295f7c7c124b19dacaa13baba0f1d573b66bbd38f1b575c7",
+	RCD:            "013c1ff1dc8f60ad24155549fcbc21a48ce0e24a85d7af2dba98c6f2c227421520",
+}, {
+	PrivateType:    "Fs",
+	Private:        "Fs3E9gV6DXsYzf7Fqx1fVBQPQXV695eP3k5XbmHEZVRLkMdD9qCK",
+	PrivatePayload: "fb3b471b1dcdadfeb856bd0b02d8bf49ace0edd372a3d9f2a95b78ec12a324d6",
+	PublicType:     "FA",
+	Public:         "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q",
+	PublicPayload:  "646f3e8750c550e4582eca5047546ffef89c13a175985e320232bacac81cc428",
+	RCD:            "01718b5edd2914acc2e4677f336c1a32736e5e9bde13663e6413894f57ec272e28",
+}, {
+	PrivateType:    "Fs",
+	Private:        "Fs1KWJrpLdfucvmYwN2nWrwepLn8ercpMbzXshd1g8zyhKXLVLWj",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "FA",
+	Public:         "FA1zT4aFpEvcnPqPCigB3fvGu4Q4mTXY22iiuV69DqE1pNhdF2MC",
+	PublicPayload:  "031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+	RCD:            "013b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+}, {
+	PrivateType:    "Fs",
+	Private:        "Fs3GFV6GNV6ar4b8eGcQWpGFbFtkNWKfEPdbywmha8ez5p7XMJyk",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "FA",
+	Public:         "FA2egPv9XfBthJVBGRJF3oPuCypPFZPwHGtT41SWXktQqqra9LZm",
+	PublicPayload:  "59eb0c63b4a8293de6bbd543cf3f83910a72922a84d80ee83666528c0f1b802f",
+	RCD:            "0176a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+}, {
+	PrivateType:    "Es",
+	Private:        "Es2tFRhAqHnydaygVAR6zbpWTQXUDaXy1JHWJugQXnYavS8ssQQE",
+	PrivatePayload: "3c611e75849a6f509b7481a5fe4b011fc948137f218a0b7eae29be4b01f07ce2",
+	PublicType:     "EC",
+	Public:         "EC2Pawhv7uAiKFQeLgaqfRhzk5o9uPVY8Ehjh8DnLXENosvYTT26",
+	PublicPayload:  "52ba51221f2c1ae7767c83ea0de7533dac7ee9c0d3415db13fc69976c8b2364c",
+	RCD:            "",
+}, {
+	PrivateType:    "Es",
+	Private:        "Es2Rf7iM6PdsqfYCo3D1tnAR65SkLENyWJG1deUzpRMQmbh9F3eG",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "EC",
+	Public:         "EC2DKSYyRcNWf7RS963VFYgMExoHRYLHVeCfQ9PGPmNzwrcmgm2r",
+	PublicPayload:  "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+	RCD:            "",
+}, {
+	PrivateType:    "Es",
+	Private:        "Es4NQHwo8F4Z4oMnVwndtjV1rzZN3t5pP5u5jtdgiR1RA6FH4Tmc",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "EC",
+	Public:         "EC2fQ2N3gWqJfek7myawtgRA7UZYeAgUpj2Mg1WdMUh1Z7bESqfA",
+	PublicPayload:  "76a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+	RCD:            "",
+}, {
+	PrivateType:    "sk1",
+	Private:        "sk13iLKJfxNQg8vpSmjacEgEQAnXkn7rbjd5ewexc1Un5wVPa7KTk",
+	PrivatePayload: "f84a80f204c8e5e4369a80336919f55885d0b093505d84b80d12f9c08b81cd5e",
+	PublicType:     "id1",
+	Public:         "id12K4tCXKcJJYxJmZ1UY9EuKPvtGVAjo32xySMKNUahbmRcsqFgW",
+	PublicPayload:  "3f2b77bca02392c95149dc769a78bc758b1037b6a546011b163af0d492b1bcc0",
+	RCD:            "0125b0e7fd5e68b4dec40ca0cd2db66be84c02fe6404b696c396e3909079820f61",
+}, {
+	PrivateType:    "sk1",
+	Private:        "sk11pz4AG9XgB1eNVkbppYAWsgyg7sftDXqBASsagKJqvVRKYodCU",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "id1",
+	Public:         "id11rcoR2ApgD9X5QN7EVEna5JAfGD7oLq2NpYy4CvfbpCtPWxycv",
+	PublicPayload:  "031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+	RCD:            "013b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+}, {
+	PrivateType:    "sk1",
+	Private:        "sk13mjEPiBP6rEnC5TWQSY7qUTtnjbKb4QcpEZ7jNDJVvsupCg9DV",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "id1",
+	Public:         "id12Wr8kutEwV4RjCRorZEv3hc65ahDfk6GYYhVQaEbGDEMWPcnXA",
+	PublicPayload:  "59eb0c63b4a8293de6bbd543cf3f83910a72922a84d80ee83666528c0f1b802f",
+	RCD:            "0176a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+}, {
+	PrivateType:    "sk2",
+	Private:        "sk22UaDys2Mzg2pUCsToo9aKgxubJFnZN5Bc2LXfV59VxMvXXKwXa",
+	PrivatePayload: "2bb967a78b081fafef17818c2a4c2ba8dbefcd89664ff18f6ba926b55e00b601",
+	PublicType:     "id2",
+	Public:         "id22pNvsaMWf9qxWFrmfQpwFJiKQoWfKmBwVgQtdvqVZuqzGmrFNY",
+	PublicPayload:  "58190cd60b8a3dd32f3e836e8f1f0b13e9ca1afff16416806c798f8d944c2c72",
+	RCD:            "0180a5aa01ac2301406a9983a4bd3928ba3f155f4e7283b2e4cabdf040576dbbfe",
+}, {
+	PrivateType:    "sk2",
+	Private:        "sk229KM7j76STogyvuoDSWn8rvT6bRB1VoSMHgC5KD8W88E26iQM3",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "id2",
+	Public:         "id22Ax6NV8PSVwZgqXJd7DQC4Xe5jkcvd6dYwnHYqpVG1qh3aF6be",
+	PublicPayload:  "031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+	RCD:            "013b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+}, {
+	PrivateType:    "sk2",
+	Private:        "sk2464XMB8ws92poWcho4WjTThNDD8piLgDzMnSE178A8WiU46gJy",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "id2",
+	Public:         "id22qBRiNqohmrULdb1FBDXfgqZW4Eio2MsifvouD8QvQsABvpou4",
+	PublicPayload:  "59eb0c63b4a8293de6bbd543cf3f83910a72922a84d80ee83666528c0f1b802f",
+	RCD:            "0176a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+}, {
+	PrivateType:    "sk3",
+	Private:        "sk32Xyo9kmjtNqRUfRd3ZhU56NZd8M1nR61tdBaCLSQRdhUCk4yiM",
+	PrivatePayload: "09d51ae7cc0dbc597356ab1ada078457277875c81989c5db0ae6f4bf86ccea5f",
+	PublicType:     "id3",
+	Public:         "id33pRgpm8ufXNGxtW7n5FgdGP6afXKjU4LfVmgfC8Yaq6LyYq2wA",
+	PublicPayload:  "b246833125481636108cedc2961338c1368c41c73e2c6e016e224dfe41f0ac23",
+	RCD:            "0119adb78e13244e0b2ad40e2f28274a06f7d173938a2c90401fcac0eea84703fe",
+}, {
+	PrivateType:    "sk3",
+	Private:        "sk32Tee5C4fCkbjbN4zc4VPkr9vX4xg8n53XQuWZx6xAKm2cAP7gv",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "id3",
+	Public:         "id32VHPKx5xCnjcJGgW1jC1p3m7WDJ83uNEj51c3UiJvDUVe7vKUe",
+	PublicPayload:  "031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+	RCD:            "013b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+}, {
+	PrivateType:    "sk3",
+	Private:        "sk34QPpJe6WdRpsQwmuBgVM5SvqdggKqcwqAV1kidzwpL9X86sVi9",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "id3",
+	Public:         "id339WifqoNU4eWx4kCdoC9Hg52vXnDvJdUtoA8Pr2EacVxpi9xCK",
+	PublicPayload:  "59eb0c63b4a8293de6bbd543cf3f83910a72922a84d80ee83666528c0f1b802f",
+	RCD:            "0176a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+}, {
+	PrivateType:    "sk4",
+	Private:        "sk43eMusQuvvChoGNn1VZZwbAH8BtKJSZNC7ZWoz1Vc4Y3greLA45",
+	PrivatePayload: "72644033bdd70b8fec7aa1fea50b0c5f7dfadb1bce76aa15d9564bf71c62b160",
+	PublicType:     "id4",
+	Public:         "id42vYqBB63eoSz8DHozEwtCaLbEwvBTG9pWgD3D5CCaHWy1gCjF5",
+	PublicPayload:  "12db35739303a13861c14862424e90f116a594eaee25811955423dce33e500b6",
+	RCD:            "011a776b346022aa512425eed8ae4ce53ba07c99a1d4b13f51e7f14137c10a1305",
+}, {
+	PrivateType:    "sk4",
+	Private:        "sk42myw2f2Dy3PnCoEBzgU1NqPPwYWBG4LehY8q4azmpXPqGY6Bqu",
+	PrivatePayload: "0000000000000000000000000000000000000000000000000000000000000000",
+	PublicType:     "id4",
+	Public:         "id42ocgHR3Wy5XeuhqhQMAdS2zavgqdBBdquCEvY7c8aR7JNw1Gnm",
+	PublicPayload:  "031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+	RCD:            "013b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+}, {
+	PrivateType:    "sk4",
+	Private:        "sk44ij7G745Picv2Nw6aJTxhSAK4ADpxuDSLcF5DGtmUXnKs6XT1F",
+	PrivatePayload: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	PublicType:     "id4",
+	Public:         "id43Tr1dJkwEMSZZVuQ2RAkufJWM1Kj3au64vPStUv4Ep8mUhcgSg",
+	PublicPayload:  "59eb0c63b4a8293de6bbd543cf3f83910a72922a84d80ee83666528c0f1b802f",
+	RCD:            "0176a1592044a6e4f511265bca73a604d90b0529d1df602be30a19a9257660d1f5",
+}}
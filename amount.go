@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Amount is an amount of factoshis or Entry Credits.
+//
+// Amounts are marshaled to and unmarshaled from JSON as integers without ever
+// being converted to a float64, so values above 2^53, which cannot be exactly
+// represented by a float64, are never silently rounded. Amounts may be
+// unmarshaled from either a JSON number or a JSON string containing a number,
+// for compatibility with APIs designed for JavaScript consumers.
+//
+// Amount is used for the amounts in the JSON responses of factomd and
+// factom-walletd. Amounts decoded from binary data, such as
+// AddressAmount.Amount, are plain uint64s.
+//
+// Use AmountString to marshal amounts as JSON strings.
+type Amount uint64
+
+// String returns the base 10 representation of a.
+func (a Amount) String() string {
+	return strconv.FormatUint(uint64(a), 10)
+}
+
+// MarshalJSON encodes a as a JSON number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a JSON string containing a number
+// into a. The number must be a non-negative integer without a fraction or
+// exponent that fits in a uint64. A JSON null leaves a unchanged.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 1 && data[0] == '"' {
+		data = bytes.TrimSuffix(data[1:], []byte(`"`))
+	}
+	return a.set(json.Number(data))
+}
+
+// set parses n into a without any intermediate float64 conversion.
+func (a *Amount) set(n json.Number) error {
+	x, err := strconv.ParseUint(string(n), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %q", string(n))
+	}
+	*a = Amount(x)
+	return nil
+}
+
+// Number returns a as a json.Number.
+func (a Amount) Number() json.Number {
+	return json.Number(a.String())
+}
+
+// ParseAmount parses n, as obtained from a json.Decoder with UseNumber, into
+// an Amount.
+func ParseAmount(n json.Number) (Amount, error) {
+	var a Amount
+	err := a.set(n)
+	return a, err
+}
+
+// AmountString is an Amount that is marshaled to JSON as a string, for
+// JavaScript consumers that decode all JSON numbers into a float64.
+type AmountString Amount
+
+// String returns the base 10 representation of a.
+func (a AmountString) String() string {
+	return Amount(a).String()
+}
+
+// MarshalJSON encodes a as a JSON string.
+func (a AmountString) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a JSON string containing a number
+// into a. See Amount.UnmarshalJSON.
+func (a *AmountString) UnmarshalJSON(data []byte) error {
+	return (*Amount)(a).UnmarshalJSON(data)
+}
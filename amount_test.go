@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxFloatInt is 2^53, above which not all integers can be represented by a
+// float64.
+const maxFloatInt = 1 << 53
+
+var amountUnmarshalJSONTests = []struct {
+	Name   string
+	JSON   string
+	Amount Amount
+	Error  string
+}{{
+	Name:   "number",
+	JSON:   `123`,
+	Amount: 123,
+}, {
+	Name:   "number above 2^53",
+	JSON:   `9007199254740993`,
+	Amount: maxFloatInt + 1,
+}, {
+	Name:   "max uint64",
+	JSON:   `18446744073709551615`,
+	Amount: math.MaxUint64,
+}, {
+	Name:   "string",
+	JSON:   `"9007199254740993"`,
+	Amount: maxFloatInt + 1,
+}, {
+	Name:  "overflow",
+	JSON:  `18446744073709551616`,
+	Error: `invalid amount: "18446744073709551616"`,
+}, {
+	Name:  "negative",
+	JSON:  `-1`,
+	Error: `invalid amount: "-1"`,
+}, {
+	Name:  "fraction",
+	JSON:  `1.5`,
+	Error: `invalid amount: "1.5"`,
+}, {
+	Name:  "exponent",
+	JSON:  `1e3`,
+	Error: `invalid amount: "1e3"`,
+}, {
+	Name:  "empty string",
+	JSON:  `""`,
+	Error: `invalid amount: ""`,
+}, {
+	Name: "null",
+	JSON: `null`,
+}}
+
+func TestAmountUnmarshalJSON(t *testing.T) {
+	for _, test := range amountUnmarshalJSONTests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			var a Amount
+			err := json.Unmarshal([]byte(test.JSON), &a)
+			if len(test.Error) > 0 {
+				assert.EqualError(err, test.Error)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.Amount, a)
+
+			var as AmountString
+			assert.NoError(json.Unmarshal([]byte(test.JSON), &as))
+			assert.Equal(AmountString(test.Amount), as)
+		})
+	}
+
+	// A JSON null leaves the Amount unchanged.
+	a := Amount(5)
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &a))
+	assert.Equal(t, Amount(5), a)
+}
+
+func TestAmountMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+	type amounts struct {
+		A  Amount       `json:"a"`
+		AS AmountString `json:"as"`
+	}
+	v := amounts{A: maxFloatInt + 1, AS: math.MaxUint64}
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(`{"a":9007199254740993,"as":"18446744073709551615"}`,
+		string(data))
+
+	var got amounts
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(v, got)
+
+	// Amounts must also survive decoding into an interface{}, as long as
+	// the json.Decoder uses json.Number.
+	var generic map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	require.NoError(t, d.Decode(&generic))
+	a, err := ParseAmount(generic["a"].(json.Number))
+	require.NoError(t, err)
+	assert.Equal(v.A, a)
+	assert.Equal(v.A.Number(), generic["a"])
+}
+
+func TestGetBalanceAbove2p53(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	c := newMockClient(t, map[string]interface{}{
+		"factoid-balance": map[string]interface{}{
+			"balance": json.Number("9007199254740993")},
+		"entry-credit-balance": map[string]interface{}{
+			"balance": "9007199254740995"},
+		"entry-credit-rate": map[string]interface{}{
+			"rate": json.Number("9007199254740997")},
+	})
+	ctx := context.Background()
+
+	balance, err := FAAddress{}.GetBalance(ctx, c)
+	require.NoError(err)
+	assert.Equal(uint64(maxFloatInt+1), balance)
+
+	balance, err = ECAddress{}.GetBalance(ctx, c)
+	require.NoError(err)
+	assert.Equal(uint64(maxFloatInt+3), balance)
+
+	rate, err := c.GetECRate(ctx)
+	require.NoError(err)
+	assert.Equal(uint64(maxFloatInt+5), rate)
+}
@@ -0,0 +1,297 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package appidentity implements long lived application identities whose keys
+// can be rotated.
+//
+// An application identity chain is created by an Entry with the ExtIDs
+//
+//	["IdentityChain", names...]
+//
+// and the Content
+//
+//	{"version":1,"keys":["id1...", ...]}
+//
+// which lists the initial keys in order of decreasing priority.
+//
+// A key is replaced by a key replacement Entry with the ExtIDs
+//
+//	["ReplaceKey", <old id1 key>, <new id1 key>, <signature>, <signer RCD>]
+//
+// where the signature is the ed25519 signature of the hex encoded ChainID
+// concatenated with the old and new keys, as they appear in the ExtIDs. The
+// signer must be an active key of the same or higher priority as the old key.
+// The new key replaces the old key at the same priority. A key may never be
+// reused once it has been part of the identity, so replacement Entries cannot
+// be replayed.
+//
+// Anyone may write to the chain, so Resolve ignores all invalid Entries.
+package appidentity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+var (
+	// ChainTag is the first ExtID of the first Entry of an identity chain.
+	ChainTag = factom.Bytes("IdentityChain")
+
+	// ReplaceKeyTag is the first ExtID of a key replacement Entry.
+	ReplaceKeyTag = factom.Bytes("ReplaceKey")
+)
+
+// Version is the only supported version of the identity chain Content.
+const Version = 1
+
+// Identity is the state of an application identity.
+type Identity struct {
+	ChainID factom.Bytes32
+	Names   []factom.Bytes
+
+	// Keys are the active keys in order of decreasing priority.
+	Keys []factom.ID1Key
+
+	// used holds all keys that have ever been active.
+	used map[factom.ID1Key]struct{}
+}
+
+type identityContent struct {
+	Version int             `json:"version"`
+	Keys    []factom.ID1Key `json:"keys"`
+}
+
+// New returns a new Identity with the given keys and names, along with the
+// first Entry of its chain, which must be submitted to create the chain.
+func New(keys []factom.ID1Key, names ...factom.Bytes) (
+	Identity, factom.Entry, error) {
+	if len(keys) == 0 {
+		return Identity{}, factom.Entry{}, fmt.Errorf("no keys")
+	}
+	content, err := json.Marshal(identityContent{Version: Version,
+		Keys: keys})
+	if err != nil {
+		return Identity{}, factom.Entry{}, err
+	}
+	extIDs := append([]factom.Bytes{ChainTag}, names...)
+	chainID := factom.ComputeChainID(extIDs)
+	e := factom.Entry{ChainID: &chainID, ExtIDs: extIDs, Content: content}
+	i, err := parseFirst(e)
+	if err != nil {
+		return Identity{}, factom.Entry{}, err
+	}
+	return i, e, nil
+}
+
+// parseFirst parses the first Entry of an identity chain.
+func parseFirst(e factom.Entry) (Identity, error) {
+	if len(e.ExtIDs) == 0 || !bytes.Equal(e.ExtIDs[0], ChainTag) {
+		return Identity{}, fmt.Errorf("first entry: missing tag")
+	}
+	if e.ChainID == nil || *e.ChainID != factom.ComputeChainID(e.ExtIDs) {
+		return Identity{}, fmt.Errorf("first entry: invalid ChainID")
+	}
+	var content identityContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return Identity{}, fmt.Errorf("first entry: invalid content: %w", err)
+	}
+	if content.Version != Version {
+		return Identity{}, fmt.Errorf("first entry: unsupported version: %v",
+			content.Version)
+	}
+	if len(content.Keys) == 0 {
+		return Identity{}, fmt.Errorf("first entry: no keys")
+	}
+
+	i := Identity{
+		ChainID: *e.ChainID,
+		Names:   e.ExtIDs[1:],
+		Keys:    content.Keys,
+		used:    make(map[factom.ID1Key]struct{}, len(content.Keys)),
+	}
+	for _, key := range content.Keys {
+		if _, ok := i.used[key]; ok {
+			return Identity{}, fmt.Errorf("first entry: duplicate key: %v",
+				key)
+		}
+		i.used[key] = struct{}{}
+	}
+	return i, nil
+}
+
+// Priority returns the priority of key, where 0 is the highest priority, or
+// -1 if key is not active.
+func (i Identity) Priority(key factom.ID1Key) int {
+	for p, k := range i.Keys {
+		if k == key {
+			return p
+		}
+	}
+	return -1
+}
+
+// replaceKeyMsg returns the data signed by a key replacement.
+func (i Identity) replaceKeyMsg(oldKey, newKey []byte) []byte {
+	msg := []byte(i.ChainID.String())
+	msg = append(msg, oldKey...)
+	return append(msg, newKey...)
+}
+
+// ReplaceKey returns a key replacement Entry, signed by signer, that replaces
+// oldKey with newKey. The Entry is validated but not applied to i. Use Apply
+// after it has been successfully submitted.
+func (i Identity) ReplaceKey(signer factom.SK1Key,
+	oldKey, newKey factom.ID1Key) (factom.Entry, error) {
+	oldKeyStr := factom.Bytes(oldKey.String())
+	newKeyStr := factom.Bytes(newKey.String())
+	msg := i.replaceKeyMsg(oldKeyStr, newKeyStr)
+	chainID := i.ChainID
+	e := factom.Entry{
+		ChainID: &chainID,
+		ExtIDs: []factom.Bytes{ReplaceKeyTag, oldKeyStr, newKeyStr,
+			signer.Sign(msg), factom.Bytes(signer.RCD())},
+		Content: factom.Bytes{},
+	}
+	if _, _, err := i.parseReplaceKey(e); err != nil {
+		return factom.Entry{}, err
+	}
+	return e, nil
+}
+
+// parseReplaceKey returns the priority of the old key and the new key of the
+// key replacement Entry e, or an error if e is not a valid key replacement for
+// i.
+func (i Identity) parseReplaceKey(e factom.Entry) (int, factom.ID1Key, error) {
+	if e.ChainID == nil || *e.ChainID != i.ChainID {
+		return 0, factom.ID1Key{}, fmt.Errorf("invalid ChainID")
+	}
+	if len(e.ExtIDs) != 5 || !bytes.Equal(e.ExtIDs[0], ReplaceKeyTag) {
+		return 0, factom.ID1Key{}, fmt.Errorf("invalid ExtIDs")
+	}
+	oldKey, err := factom.NewID1Key(string(e.ExtIDs[1]))
+	if err != nil {
+		return 0, factom.ID1Key{}, fmt.Errorf("invalid old key: %w", err)
+	}
+	newKey, err := factom.NewID1Key(string(e.ExtIDs[2]))
+	if err != nil {
+		return 0, factom.ID1Key{}, fmt.Errorf("invalid new key: %w", err)
+	}
+
+	var rcd factom.RCD
+	if err := rcd.UnmarshalBinary(e.ExtIDs[4]); err != nil {
+		return 0, factom.ID1Key{}, fmt.Errorf("invalid signer RCD: %w", err)
+	}
+	signer := factom.ID1Key(rcd.Hash())
+
+	oldPriority := i.Priority(oldKey)
+	if oldPriority < 0 {
+		return 0, factom.ID1Key{}, fmt.Errorf("old key is not active")
+	}
+	signerPriority := i.Priority(signer)
+	if signerPriority < 0 {
+		return 0, factom.ID1Key{}, fmt.Errorf("signer is not active")
+	}
+	if signerPriority > oldPriority {
+		return 0, factom.ID1Key{}, fmt.Errorf(
+			"signer has lower priority than old key")
+	}
+	if _, ok := i.used[newKey]; ok {
+		return 0, factom.ID1Key{}, fmt.Errorf("new key has already been used")
+	}
+
+	msg := i.replaceKeyMsg(e.ExtIDs[1], e.ExtIDs[2])
+	if err := rcd.Validate(e.ExtIDs[3], msg); err != nil {
+		return 0, factom.ID1Key{}, err
+	}
+	return oldPriority, newKey, nil
+}
+
+// Apply validates the key replacement Entry e and applies it to i.
+func (i *Identity) Apply(e factom.Entry) error {
+	priority, newKey, err := i.parseReplaceKey(e)
+	if err != nil {
+		return err
+	}
+	i.Keys = append([]factom.ID1Key(nil), i.Keys...)
+	i.Keys[priority] = newKey
+	if i.used == nil {
+		i.used = make(map[factom.ID1Key]struct{})
+	}
+	i.used[newKey] = struct{}{}
+	return nil
+}
+
+// WriteReplaceKey creates a key replacement Entry using ReplaceKey, submits
+// it using ComposeCreate with es, and then applies it to i.
+func (i *Identity) WriteReplaceKey(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, signer factom.SK1Key,
+	oldKey, newKey factom.ID1Key) (factom.TxID, error) {
+	e, err := i.ReplaceKey(signer, oldKey, newKey)
+	if err != nil {
+		return factom.TxID{}, err
+	}
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return factom.TxID{}, err
+	}
+	return txID, i.Apply(e)
+}
+
+// Resolve computes the active keys of the identity from all of the entries in
+// its chain, in order from the first Entry.
+//
+// The first Entry must be a valid identity chain Entry. All subsequent Entries
+// that are not valid key replacements are ignored.
+func Resolve(entries []factom.Entry) (Identity, error) {
+	if len(entries) == 0 {
+		return Identity{}, fmt.Errorf("no entries")
+	}
+	i, err := parseFirst(entries[0])
+	if err != nil {
+		return Identity{}, err
+	}
+	for _, e := range entries[1:] {
+		_ = i.Apply(e) // Invalid entries are ignored.
+	}
+	return i, nil
+}
+
+// Get downloads all Entries of the identity chain with the given chainID and
+// resolves its active keys. See Resolve.
+func Get(ctx context.Context, c *factom.Client,
+	chainID factom.Bytes32) (Identity, error) {
+	typed, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return Identity{}, err
+	}
+	entries := make([]factom.Entry, len(typed))
+	for i := range typed {
+		entries[i] = typed[i].Entry
+	}
+	return Resolve(entries)
+}
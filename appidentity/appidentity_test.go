@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package appidentity_test
+
+import (
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/appidentity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateKeys(t *testing.T, n int) ([]factom.SK1Key, []factom.ID1Key) {
+	sks := make([]factom.SK1Key, n)
+	ids := make([]factom.ID1Key, n)
+	for i := range sks {
+		var err error
+		sks[i], err = factom.GenerateSK1Key()
+		require.NoError(t, err)
+		ids[i] = sks[i].ID1Key()
+	}
+	return sks, ids
+}
+
+func TestKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sks, ids := generateKeys(t, 5)
+	id, first, err := New(ids[:3], factom.Bytes("app"), factom.Bytes("1"))
+	require.NoError(err)
+	assert.Equal(ids[:3], id.Keys)
+	assert.Equal(factom.ComputeChainID(first.ExtIDs), id.ChainID)
+	assert.Equal(`{"version":1,"keys":["`+ids[0].String()+`","`+
+		ids[1].String()+`","`+ids[2].String()+`"]}`,
+		string(first.Content))
+
+	entries := []factom.Entry{first}
+
+	// Key 1 replaces itself with key 3.
+	e, err := id.ReplaceKey(sks[1], ids[1], ids[3])
+	require.NoError(err)
+	require.NoError(id.Apply(e))
+	assert.Equal([]factom.ID1Key{ids[0], ids[3], ids[2]}, id.Keys)
+	entries = append(entries, e)
+
+	// Replaying the same Entry fails since key 1 is no longer active.
+	assert.EqualError(id.Apply(e), "old key is not active")
+	entries = append(entries, e)
+
+	// Key 2 cannot replace the higher priority key 0.
+	_, err = id.ReplaceKey(sks[2], ids[0], ids[4])
+	assert.EqualError(err, "signer has lower priority than old key")
+
+	// A replaced key may not be reused.
+	_, err = id.ReplaceKey(sks[0], ids[2], ids[1])
+	assert.EqualError(err, "new key has already been used")
+
+	// Key 0 replaces key 2 with key 4.
+	e, err = id.ReplaceKey(sks[0], ids[2], ids[4])
+	require.NoError(err)
+	require.NoError(id.Apply(e))
+	entries = append(entries, e)
+	assert.Equal([]factom.ID1Key{ids[0], ids[3], ids[4]}, id.Keys)
+	assert.Equal(2, id.Priority(ids[4]))
+	assert.Equal(-1, id.Priority(ids[2]))
+
+	// A forged signature is ignored by Resolve.
+	forged, err := id.ReplaceKey(sks[0], ids[0], factom.ID1Key{1})
+	require.NoError(err)
+	forged.ExtIDs[3][0]++
+	assert.EqualError(id.Apply(forged), "invalid signature")
+	entries = append(entries, forged, factom.Entry{ChainID: &id.ChainID})
+
+	resolved, err := Resolve(entries)
+	require.NoError(err)
+	assert.Equal(id.ChainID, resolved.ChainID)
+	assert.Equal(id.Keys, resolved.Keys)
+	assert.Equal([]factom.Bytes{factom.Bytes("app"), factom.Bytes("1")},
+		resolved.Names)
+}
+
+func TestResolveInvalidFirstEntry(t *testing.T) {
+	_, ids := generateKeys(t, 2)
+	for _, test := range []struct {
+		Name   string
+		Modify func(*factom.Entry)
+		Error  string
+	}{{
+		Name:   "missing tag",
+		Modify: func(e *factom.Entry) { e.ExtIDs[0] = factom.Bytes("x") },
+		Error:  "first entry: missing tag",
+	}, {
+		Name:   "invalid ChainID",
+		Modify: func(e *factom.Entry) { e.ChainID = new(factom.Bytes32) },
+		Error:  "first entry: invalid ChainID",
+	}, {
+		Name: "unsupported version",
+		Modify: func(e *factom.Entry) {
+			e.Content = factom.Bytes(`{"version":2,"keys":[]}`)
+		},
+		Error: "first entry: unsupported version: 2",
+	}, {
+		Name: "no keys",
+		Modify: func(e *factom.Entry) {
+			e.Content = factom.Bytes(`{"version":1,"keys":[]}`)
+		},
+		Error: "first entry: no keys",
+	}, {
+		Name: "duplicate key",
+		Modify: func(e *factom.Entry) {
+			e.Content = factom.Bytes(`{"version":1,"keys":["` +
+				ids[1].String() + `","` + ids[1].String() + `"]}`)
+		},
+		Error: "first entry: duplicate key: " + ids[1].String(),
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			_, first, err := New(ids, factom.Bytes("app"))
+			require.NoError(t, err)
+			test.Modify(&first)
+			_, err = Resolve([]factom.Entry{first})
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+
+	_, err := Resolve(nil)
+	assert.EqualError(t, err, "no entries")
+}
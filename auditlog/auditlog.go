@@ -0,0 +1,230 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package auditlog implements hash-linked audit logs stored in Factom chains.
+//
+// The first Entry of an audit log chain has the ExtIDs
+//
+//	["auditlog", nameIDs...]
+//
+// and every subsequent Entry, a Record, has the ExtIDs
+//
+//	["auditlog", <Entry Hash of the previous Entry>]
+//
+// with the Record's payload as its Content. Since each Record commits to the
+// Entry before it, a reader can detect any missing, reordered, or foreign
+// Entries by verifying the hash chain with Verify.
+//
+// The hash links only establish the order and integrity of the Records. They
+// do not authenticate the writer. Anyone may write to a Factom chain, and so
+// any foreign Entry causes Verify to fail. Applications that require
+// authenticated Records should sign the payload.
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of all Entries in an audit log chain.
+var Tag = factom.Bytes("auditlog")
+
+// Log is a hash-linked audit log stored in a Factom chain.
+//
+// A Log is not safe for concurrent use, since Append updates the Head.
+type Log struct {
+	ChainID factom.Bytes32
+
+	// Head is the Entry Hash of the latest Entry in the Log, which the next
+	// Record links to.
+	Head factom.EntryHash
+
+	// Len is the number of Records in the Log, not including the first
+	// Entry.
+	Len uint64
+}
+
+// Record is an Entry in a Log after the first Entry.
+type Record struct {
+	factom.Entry
+
+	// Prev is the Entry Hash of the previous Entry in the Log.
+	Prev factom.EntryHash
+}
+
+// Payload returns the Content of r.
+func (r Record) Payload() factom.Bytes {
+	return r.Content
+}
+
+// NameIDs returns the ExtIDs of the first Entry of a Log with the given
+// nameIDs.
+func NameIDs(nameIDs ...factom.Bytes) []factom.Bytes {
+	return append([]factom.Bytes{Tag}, nameIDs...)
+}
+
+// New returns a new Log for the given nameIDs along with its first Entry,
+// which must be submitted, e.g. with factom.Entry.Create, to create the chain
+// before any Records.
+func New(nameIDs ...factom.Bytes) (Log, factom.Entry, error) {
+	extIDs := NameIDs(nameIDs...)
+	chainID := factom.ComputeChainID(extIDs)
+	e := factom.Entry{ChainID: &chainID, ExtIDs: extIDs,
+		Content: factom.Bytes{}}
+	hash, err := entryHash(e)
+	if err != nil {
+		return Log{}, factom.Entry{}, err
+	}
+	e.Hash = &hash
+	return Log{ChainID: chainID, Head: hash}, e, nil
+}
+
+// Append returns the next Record with the given payload and links l.Head to
+// it.
+//
+// The Record's Entry must be submitted, in order, after all previously
+// appended Records.
+func (l *Log) Append(payload []byte) (Record, error) {
+	chainID := l.ChainID
+	prev := l.Head
+	e := factom.Entry{
+		ChainID: &chainID,
+		ExtIDs:  []factom.Bytes{Tag, prev[:]},
+		Content: factom.Bytes(payload),
+	}
+	if e.Content == nil {
+		e.Content = factom.Bytes{}
+	}
+	hash, err := entryHash(e)
+	if err != nil {
+		return Record{}, err
+	}
+	e.Hash = &hash
+	l.Head = hash
+	l.Len++
+	return Record{Entry: e, Prev: prev}, nil
+}
+
+// Write appends a Record with the given payload to l, and submits it using
+// factom.Entry.Create with the given ec.
+//
+// If the submission fails, l is left unchanged.
+func (l *Log) Write(ctx context.Context, c *factom.Client,
+	ec factom.ECAddress, payload []byte) (Record, factom.TxID, error) {
+	next := *l
+	r, err := next.Append(payload)
+	if err != nil {
+		return Record{}, factom.TxID{}, err
+	}
+	txID, err := r.Create(ctx, c, ec)
+	if err != nil {
+		return Record{}, factom.TxID{}, err
+	}
+	*l = next
+	return r, txID, nil
+}
+
+// Verify checks that entries, in order from the first Entry of the chain,
+// form a valid Log, and returns the Log and its Records.
+//
+// An error is returned if the first Entry does not have the Tag or does not
+// match its ChainID, or if any Record does not link to the Entry before it.
+func Verify(entries []factom.Entry) (Log, []Record, error) {
+	if len(entries) == 0 {
+		return Log{}, nil, fmt.Errorf("no entries")
+	}
+
+	first := entries[0]
+	if len(first.ExtIDs) == 0 || !bytes.Equal(first.ExtIDs[0], Tag) {
+		return Log{}, nil, fmt.Errorf("first entry: missing tag")
+	}
+	if first.ChainID == nil ||
+		*first.ChainID != factom.ComputeChainID(first.ExtIDs) {
+		return Log{}, nil, fmt.Errorf("first entry: invalid ChainID")
+	}
+
+	l := Log{ChainID: *first.ChainID}
+	var err error
+	if l.Head, err = entryHash(first); err != nil {
+		return Log{}, nil, fmt.Errorf("first entry: %w", err)
+	}
+
+	records := make([]Record, 0, len(entries)-1)
+	for i, e := range entries[1:] {
+		i++
+		if e.ChainID == nil || *e.ChainID != l.ChainID {
+			return Log{}, nil, fmt.Errorf("entry %v: invalid ChainID", i)
+		}
+		if len(e.ExtIDs) != 2 || !bytes.Equal(e.ExtIDs[0], Tag) {
+			return Log{}, nil, fmt.Errorf("entry %v: invalid ExtIDs", i)
+		}
+		if !bytes.Equal(e.ExtIDs[1], l.Head[:]) {
+			return Log{}, nil, fmt.Errorf("entry %v: broken hash link", i)
+		}
+		hash, err := entryHash(e)
+		if err != nil {
+			return Log{}, nil, fmt.Errorf("entry %v: %w", i, err)
+		}
+		records = append(records, Record{Entry: e, Prev: l.Head})
+		l.Head = hash
+		l.Len++
+	}
+	return l, records, nil
+}
+
+// Read downloads all Entries of the Log with the given chainID and verifies
+// them. See Verify.
+func Read(ctx context.Context, c *factom.Client,
+	chainID factom.Bytes32) (Log, []Record, error) {
+	typed, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return Log{}, nil, err
+	}
+	entries := make([]factom.Entry, len(typed))
+	for i := range typed {
+		entries[i] = typed[i].Entry
+	}
+	return Verify(entries)
+}
+
+// entryHash computes the Entry Hash of e, and checks it against e.Hash, if
+// not nil.
+func entryHash(e factom.Entry) (factom.EntryHash, error) {
+	if e.ExtIDs == nil {
+		e.ExtIDs = []factom.Bytes{}
+	}
+	if e.Content == nil {
+		e.Content = factom.Bytes{}
+	}
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return factom.EntryHash{}, err
+	}
+	hash := factom.ComputeEntryHash(data)
+	if e.Hash != nil && *e.Hash != hash {
+		return factom.EntryHash{}, fmt.Errorf("invalid Entry Hash")
+	}
+	return hash, nil
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package auditlog_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/auditlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLog(t *testing.T, n int) (Log, []factom.Entry) {
+	l, first, err := New(factom.Bytes("test"), factom.Bytes("log"))
+	require.NoError(t, err)
+	entries := []factom.Entry{first}
+	for i := 0; i < n; i++ {
+		r, err := l.Append([]byte(fmt.Sprintf("payload %v", i)))
+		require.NoError(t, err)
+		entries = append(entries, r.Entry)
+	}
+	return l, entries
+}
+
+func TestLog(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	l, entries := newLog(t, 3)
+	assert.Equal(factom.ComputeChainID([]factom.Bytes{Tag,
+		factom.Bytes("test"), factom.Bytes("log")}), l.ChainID)
+	assert.Equal(uint64(3), l.Len)
+	assert.Equal(*entries[3].Hash, l.Head)
+
+	verified, records, err := Verify(entries)
+	require.NoError(err)
+	assert.Equal(l, verified)
+	require.Len(records, 3)
+	for i, r := range records {
+		assert.Equal(*entries[i].Hash, r.Prev)
+		assert.Equal(fmt.Sprintf("payload %v", i), string(r.Payload()))
+	}
+
+	// Verify recomputes the Entry Hashes.
+	for i := range entries {
+		entries[i].Hash = nil
+	}
+	verified, _, err = Verify(entries)
+	require.NoError(err)
+	assert.Equal(l, verified)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Modify func([]factom.Entry) []factom.Entry
+		Error  string
+	}{{
+		Name:   "empty",
+		Modify: func([]factom.Entry) []factom.Entry { return nil },
+		Error:  "no entries",
+	}, {
+		Name: "missing tag",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[0].ExtIDs = entries[0].ExtIDs[1:]
+			return entries
+		},
+		Error: "first entry: missing tag",
+	}, {
+		Name: "invalid first ChainID",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[0].ExtIDs = append(entries[0].ExtIDs,
+				factom.Bytes("x"))
+			return entries
+		},
+		Error: "first entry: invalid ChainID",
+	}, {
+		Name: "missing record",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			return append(entries[:1], entries[2:]...)
+		},
+		Error: "entry 1: broken hash link",
+	}, {
+		Name: "reordered",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[1], entries[2] = entries[2], entries[1]
+			return entries
+		},
+		Error: "entry 1: broken hash link",
+	}, {
+		Name: "foreign entry",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[2].ExtIDs = []factom.Bytes{factom.Bytes("spam")}
+			return entries
+		},
+		Error: "entry 2: invalid ExtIDs",
+	}, {
+		Name: "other chain",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[3].ChainID = new(factom.Bytes32)
+			return entries
+		},
+		Error: "entry 3: invalid ChainID",
+	}, {
+		Name: "modified payload",
+		Modify: func(entries []factom.Entry) []factom.Entry {
+			entries[2].Content = factom.Bytes("modified")
+			return entries
+		},
+		Error: "entry 2: invalid Entry Hash",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			_, entries := newLog(t, 3)
+			_, _, err := Verify(test.Modify(entries))
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
@@ -0,0 +1,379 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package authority tracks the Factom authority set, the federated and audit
+// servers and their keys, by replaying Admin Blocks.
+//
+// Admin Block Entries that carry a DBHeight take effect at that height. All
+// other changes take effect at the height following the ABlock that declares
+// them. Authority(height) returns the set that is responsible for the DBlock
+// at height.
+//
+// The authority set that factomd bootstraps before the genesis ABlock is not
+// recorded in any ABlock, so it must be supplied to NewTracker if it is
+// required.
+package authority
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/varintf"
+)
+
+// Status is the role of an identity in the authority set.
+type Status int
+
+// Statuses of an Authority.
+const (
+	// None identities are not in the authority set, but may have
+	// authority attributes from prior Admin Block Entries.
+	None Status = iota
+	Federated
+	Audit
+)
+
+// String returns the name of s.
+func (s Status) String() string {
+	switch s {
+	case None:
+		return "none"
+	case Federated:
+		return "federated"
+	case Audit:
+		return "audit"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// AnchorKey is a Bitcoin anchor signing key of an Authority.
+type AnchorKey struct {
+	Priority byte
+	Type     byte
+	Key      [20]byte
+}
+
+// Authority is a server identity and its attributes.
+type Authority struct {
+	ChainID factom.Bytes32
+	Status  Status
+
+	// SigningKey is the ed25519 public key used to sign DBlocks. It is
+	// nil if not yet declared.
+	SigningKey *factom.Bytes32
+
+	MatryoshkaHash  *factom.Bytes32
+	AnchorKeys      []AnchorKey
+	CoinbaseAddress *factom.FAAddress
+
+	// Efficiency is the share of the coinbase payout, in hundredths of a
+	// percent, that the Authority forgoes. It defaults to MaxEfficiency.
+	Efficiency uint16
+}
+
+// MaxEfficiency is the maximum Efficiency of an Authority, at which it is
+// paid nothing.
+const MaxEfficiency = 10000
+
+// Set is the state of all known identities, by ChainID.
+type Set map[factom.Bytes32]Authority
+
+func (s Set) copy() Set {
+	cp := make(Set, len(s))
+	for id, a := range s {
+		a.AnchorKeys = append([]AnchorKey(nil), a.AnchorKeys...)
+		cp[id] = a
+	}
+	return cp
+}
+
+// Federated returns the federated servers, sorted by ChainID.
+func (s Set) Federated() []Authority {
+	return s.withStatus(Federated)
+}
+
+// Audit returns the audit servers, sorted by ChainID.
+func (s Set) Audit() []Authority {
+	return s.withStatus(Audit)
+}
+
+// SigningKeys returns the DBlock signing keys of the federated servers, so
+// that s may be used with factom.DBlock.Verify. Federated servers without a
+// SigningKey have the zero key.
+func (s Set) SigningKeys() map[factom.Bytes32]factom.Bytes32 {
+	keys := make(map[factom.Bytes32]factom.Bytes32)
+	for id, a := range s {
+		if a.Status != Federated {
+			continue
+		}
+		var key factom.Bytes32
+		if a.SigningKey != nil {
+			key = *a.SigningKey
+		}
+		keys[id] = key
+	}
+	return keys
+}
+
+func (s Set) withStatus(status Status) []Authority {
+	var list []Authority
+	for _, a := range s {
+		if a.Status == status {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].ChainID[:], list[j].ChainID[:]) < 0
+	})
+	return list
+}
+
+// snapshot is the Set in effect from Height until the next snapshot.
+type snapshot struct {
+	Height uint32
+	Set    Set
+}
+
+// change is a pending update to a single Authority.
+type change struct {
+	Height  uint32
+	ChainID factom.Bytes32
+	Apply   func(*Authority)
+}
+
+// Tracker replays ABlocks to maintain the history of the authority set.
+//
+// A Tracker is not safe for concurrent use.
+type Tracker struct {
+	// Height is the height of the next ABlock to be applied.
+	Height uint32
+
+	current   Set
+	snapshots []snapshot
+	pending   []change
+}
+
+// NewTracker returns a Tracker that expects the genesis ABlock next. The
+// initial Set, which may be nil, is in effect at height 0.
+func NewTracker(initial Set) *Tracker {
+	t := Tracker{current: initial.copy()}
+	t.snapshots = []snapshot{{Set: t.current.copy()}}
+	return &t
+}
+
+// Apply updates the authority set with the Entries of ab, which must be the
+// ABlock at t.Height. If any Entry is invalid, an error is returned and t is
+// not modified.
+func (t *Tracker) Apply(ab factom.ABlock) error {
+	if ab.Height != t.Height {
+		return fmt.Errorf("unexpected ABlock height: %v, expected %v",
+			ab.Height, t.Height)
+	}
+	if t.current == nil {
+		*t = *NewTracker(nil)
+	}
+
+	next := ab.Height + 1
+	var changes []change
+	for i, e := range ab.Entries {
+		c, ok, err := parseChange(e, next)
+		if err != nil {
+			return fmt.Errorf("entry %v: %w", i, err)
+		}
+		if ok {
+			changes = append(changes, c)
+		}
+	}
+
+	pending := append(t.pending, changes...)
+	var remaining []change
+	set := t.current
+	var modified bool
+	for _, c := range pending {
+		if c.Height > next {
+			remaining = append(remaining, c)
+			continue
+		}
+		if !modified {
+			set = set.copy()
+			modified = true
+		}
+		a, ok := set[c.ChainID]
+		if !ok {
+			a.ChainID = c.ChainID
+			a.Efficiency = MaxEfficiency
+		}
+		c.Apply(&a)
+		set[c.ChainID] = a
+	}
+
+	t.pending = remaining
+	if modified {
+		t.current = set
+		t.snapshots = append(t.snapshots,
+			snapshot{Height: next, Set: set.copy()})
+	}
+	t.Height++
+	return nil
+}
+
+// Update uses c to load and Apply all ABlocks from t.Height up to and
+// including height. The ABlocks applied before any error remain applied.
+func (t *Tracker) Update(ctx context.Context, c *factom.Client, height uint32) error {
+	for t.Height <= height {
+		ab := factom.ABlock{Height: t.Height}
+		if err := ab.Get(ctx, c); err != nil {
+			return err
+		}
+		if err := t.Apply(ab); err != nil {
+			return err
+		}
+		if t.Height == 0 {
+			// Height overflowed.
+			break
+		}
+	}
+	return nil
+}
+
+// Authority returns the authority set in effect for the DBlock at height,
+// which must not be greater than t.Height. Only Federated and Audit
+// identities are included. The returned Set may be modified by the caller.
+func (t *Tracker) Authority(height uint32) (Set, error) {
+	if height > t.Height {
+		return nil, fmt.Errorf("height not yet applied: %v", height)
+	}
+	if len(t.snapshots) == 0 {
+		return Set{}, nil
+	}
+	i := sort.Search(len(t.snapshots), func(i int) bool {
+		return t.snapshots[i].Height > height
+	})
+	if i == 0 {
+		return Set{}, nil
+	}
+	set := make(Set)
+	for id, a := range t.snapshots[i-1].Set {
+		if a.Status != None {
+			a.AnchorKeys = append([]AnchorKey(nil), a.AnchorKeys...)
+			set[id] = a
+		}
+	}
+	return set, nil
+}
+
+// fixedSizes are the data lengths of the fixed size ABEntry types that
+// parseChange reads.
+var fixedSizes = map[factom.ABEntryType]int{
+	factom.ABEntryAddFederatedServer:                 32 + 4,
+	factom.ABEntryAddAuditServer:                     32 + 4,
+	factom.ABEntryRemoveFederatedServer:              32 + 4,
+	factom.ABEntryAddFederatedServerSigningKey:       32 + 1 + 32 + 4,
+	factom.ABEntryAddReplaceMatryoshkaHash:           32 + 32,
+	factom.ABEntryAddFederatedServerBitcoinAnchorKey: 32 + 1 + 1 + 20,
+}
+
+// parseChange returns the change declared by e, if any. Changes without a
+// DBHeight, or a DBHeight in the past, take effect at next.
+func parseChange(e factom.ABEntry, next uint32) (change, bool, error) {
+	var c change
+	data := e.Data
+	if size, ok := fixedSizes[e.Type]; ok && len(data) != size {
+		return change{}, false, fmt.Errorf("invalid entry length")
+	}
+	switch e.Type {
+	case factom.ABEntryAddFederatedServer,
+		factom.ABEntryAddAuditServer,
+		factom.ABEntryRemoveFederatedServer:
+		status := map[factom.ABEntryType]Status{
+			factom.ABEntryAddFederatedServer:    Federated,
+			factom.ABEntryAddAuditServer:        Audit,
+			factom.ABEntryRemoveFederatedServer: None,
+		}[e.Type]
+		c.Height = binary.BigEndian.Uint32(data[32:])
+		c.Apply = func(a *Authority) { a.Status = status }
+
+	case factom.ABEntryAddFederatedServerSigningKey:
+		var key factom.Bytes32
+		copy(key[:], data[33:])
+		c.Height = binary.BigEndian.Uint32(data[65:])
+		c.Apply = func(a *Authority) { a.SigningKey = &key }
+
+	case factom.ABEntryAddReplaceMatryoshkaHash:
+		var mhash factom.Bytes32
+		copy(mhash[:], data[32:])
+		c.Apply = func(a *Authority) { a.MatryoshkaHash = &mhash }
+
+	case factom.ABEntryAddFederatedServerBitcoinAnchorKey:
+		key := AnchorKey{Priority: data[32], Type: data[33]}
+		copy(key.Key[:], data[34:])
+		c.Apply = func(a *Authority) {
+			for i := range a.AnchorKeys {
+				if a.AnchorKeys[i].Priority == key.Priority {
+					a.AnchorKeys[i] = key
+					return
+				}
+			}
+			a.AnchorKeys = append(a.AnchorKeys, key)
+		}
+
+	case factom.ABEntryAddFactoidAddress, factom.ABEntryAddFactoidEfficiency:
+		size, read := varintf.Decode(data)
+		if read < 0 || size != uint64(len(data[read:])) {
+			return change{}, false, fmt.Errorf("invalid entry size")
+		}
+		data = data[read:]
+		if e.Type == factom.ABEntryAddFactoidAddress {
+			if len(data) != 64 {
+				return change{}, false,
+					fmt.Errorf("invalid factoid address entry")
+			}
+			var adr factom.FAAddress
+			copy(adr[:], data[32:])
+			c.Apply = func(a *Authority) { a.CoinbaseAddress = &adr }
+		} else {
+			if len(data) != 34 {
+				return change{}, false,
+					fmt.Errorf("invalid efficiency entry")
+			}
+			efficiency := binary.BigEndian.Uint16(data[32:])
+			if efficiency > MaxEfficiency {
+				efficiency = MaxEfficiency
+			}
+			c.Apply = func(a *Authority) { a.Efficiency = efficiency }
+		}
+
+	default:
+		return change{}, false, nil
+	}
+
+	copy(c.ChainID[:], data)
+	if c.Height < next {
+		c.Height = next
+	}
+	return c, true, nil
+}
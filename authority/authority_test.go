@@ -0,0 +1,158 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package authority_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/authority"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	idA = factom.Bytes32{0x88, 0x88, 0x88, 1}
+	idB = factom.Bytes32{0x88, 0x88, 0x88, 2}
+)
+
+func serverEntry(typ factom.ABEntryType, id factom.Bytes32, height uint32) factom.ABEntry {
+	data := make([]byte, 36)
+	copy(data, id[:])
+	binary.BigEndian.PutUint32(data[32:], height)
+	return factom.ABEntry{Type: typ, Data: data}
+}
+
+func signingKeyEntry(id, key factom.Bytes32, height uint32) factom.ABEntry {
+	data := make([]byte, 69)
+	copy(data, id[:])
+	copy(data[33:], key[:])
+	binary.BigEndian.PutUint32(data[65:], height)
+	return factom.ABEntry{
+		Type: factom.ABEntryAddFederatedServerSigningKey, Data: data}
+}
+
+func efficiencyEntry(id factom.Bytes32, efficiency uint16) factom.ABEntry {
+	data := append([]byte{34}, id[:]...)
+	data = append(data, byte(efficiency>>8), byte(efficiency))
+	return factom.ABEntry{
+		Type: factom.ABEntryAddFactoidEfficiency, Data: data}
+}
+
+func newTracker(t *testing.T) *Tracker {
+	key := factom.Bytes32{0xee}
+	tr := NewTracker(nil)
+	for _, entries := range [][]factom.ABEntry{
+		// 0
+		{{Type: factom.ABEntryDBSignature, Data: make([]byte, 128)},
+			serverEntry(factom.ABEntryAddFederatedServer, idA, 1),
+			serverEntry(factom.ABEntryAddAuditServer, idB, 1)},
+		// 1
+		{signingKeyEntry(idA, key, 3), efficiencyEntry(idA, 20000)},
+		// 2
+		{},
+		// 3
+		{serverEntry(factom.ABEntryAddFederatedServer, idB, 4),
+			serverEntry(factom.ABEntryRemoveFederatedServer, idA, 0)},
+	} {
+		require.NoError(t, tr.Apply(factom.ABlock{
+			Height: tr.Height, Entries: entries}))
+	}
+	return tr
+}
+
+func TestTracker(t *testing.T) {
+	tr := newTracker(t)
+	assert := assert.New(t)
+	assert.Equal(uint32(4), tr.Height)
+
+	set, err := tr.Authority(0)
+	require.NoError(t, err)
+	assert.Empty(set)
+
+	set, err = tr.Authority(1)
+	require.NoError(t, err)
+	assert.Equal([]Authority{{ChainID: idA, Status: Federated,
+		Efficiency: MaxEfficiency}}, set.Federated())
+	assert.Equal([]Authority{{ChainID: idB, Status: Audit,
+		Efficiency: MaxEfficiency}}, set.Audit())
+
+	// The efficiency takes effect at 2, the signing key at 3.
+	set, err = tr.Authority(2)
+	require.NoError(t, err)
+	assert.Nil(set[idA].SigningKey)
+	assert.Equal(uint16(MaxEfficiency), set[idA].Efficiency)
+
+	set, err = tr.Authority(3)
+	require.NoError(t, err)
+	assert.Equal(&factom.Bytes32{0xee}, set[idA].SigningKey)
+	assert.Equal(map[factom.Bytes32]factom.Bytes32{idA: {0xee}},
+		set.SigningKeys())
+
+	set, err = tr.Authority(4)
+	require.NoError(t, err)
+	assert.Len(set, 1)
+	assert.Equal([]Authority{{ChainID: idB, Status: Federated,
+		Efficiency: MaxEfficiency}}, set.Federated())
+
+	_, err = tr.Authority(5)
+	assert.EqualError(err, "height not yet applied: 5")
+}
+
+func TestTrackerInitial(t *testing.T) {
+	tr := NewTracker(Set{idA: {ChainID: idA, Status: Federated}})
+	set, err := tr.Authority(0)
+	require.NoError(t, err)
+	assert.Len(t, set.Federated(), 1)
+}
+
+func TestTrackerApplyInvalid(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		ABlock factom.ABlock
+		Error  string
+	}{{
+		Name:   "height",
+		ABlock: factom.ABlock{Height: 5},
+		Error:  "unexpected ABlock height: 5, expected 4",
+	}, {
+		Name: "entry length",
+		ABlock: factom.ABlock{Height: 4, Entries: []factom.ABEntry{
+			{Type: factom.ABEntryAddFederatedServer},
+		}},
+		Error: "entry 0: invalid entry length",
+	}, {
+		Name: "entry size",
+		ABlock: factom.ABlock{Height: 4, Entries: []factom.ABEntry{
+			{Type: factom.ABEntryAddFactoidAddress, Data: []byte{5}},
+		}},
+		Error: "entry 0: invalid entry size",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			tr := newTracker(t)
+			assert.EqualError(t, tr.Apply(test.ABlock), test.Error)
+			assert.Equal(t, uint32(4), tr.Height)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package blob stores payloads too large for an Entry in object storage, such
+// as S3 or GCS, and anchors them on the Factom blockchain with a Reference
+// Entry.
+//
+// A Reference Entry has the ExtIDs
+//
+//	["blob", <bucket>, <key>, <SHA-256 (32 bytes)>]
+//
+// and its Content is the JSON object
+//
+//	{"etag":<ETag of the object>,"size":<payload size in bytes>}
+//
+// The SHA-256 hash and size are what bind the payload to the Entry. The ETag
+// identifies the exact version of the object that was written, so that a
+// Store can refuse to return an object that has since been overwritten, but
+// since ETags are backend specific they are not used to verify the payload.
+//
+// Object storage is accessed through the Store interface, which is adapted
+// from the SDK of choice, so that any backend may be used. DirStore stores
+// objects in a local directory.
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of all Reference Entries.
+var Tag = factom.Bytes("blob")
+
+// Ref is the data stored in a Reference Entry.
+type Ref struct {
+	Bucket string
+	Key    string
+	ETag   string
+	SHA256 factom.Bytes32
+	Size   uint64
+}
+
+type refContent struct {
+	ETag string  `json:"etag"`
+	Size *uint64 `json:"size"`
+}
+
+// Entry returns the Reference Entry for r in the given chainID. If chainID is
+// nil, the Entry will create a new chain, whose ChainID is computed from the
+// ExtIDs.
+func (r Ref) Entry(chainID *factom.Bytes32) factom.Entry {
+	content, _ := json.Marshal(refContent{ETag: r.ETag, Size: &r.Size})
+	return factom.Entry{
+		ChainID: chainID,
+		ExtIDs: []factom.Bytes{Tag,
+			factom.Bytes(r.Bucket), factom.Bytes(r.Key), r.SHA256[:]},
+		Content: content,
+	}
+}
+
+// ParseRef parses the Ref from the Reference Entry e.
+func ParseRef(e factom.Entry) (Ref, error) {
+	if len(e.ExtIDs) != 4 || !bytes.Equal(e.ExtIDs[0], Tag) {
+		return Ref{}, fmt.Errorf("invalid ExtIDs")
+	}
+	var r Ref
+	if len(e.ExtIDs[1]) == 0 {
+		return Ref{}, fmt.Errorf("missing bucket")
+	}
+	r.Bucket = string(e.ExtIDs[1])
+	if len(e.ExtIDs[2]) == 0 {
+		return Ref{}, fmt.Errorf("missing key")
+	}
+	r.Key = string(e.ExtIDs[2])
+	if len(e.ExtIDs[3]) != len(r.SHA256) {
+		return Ref{}, fmt.Errorf("invalid SHA-256 length")
+	}
+	copy(r.SHA256[:], e.ExtIDs[3])
+
+	var content refContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return Ref{}, fmt.Errorf("invalid content: %w", err)
+	}
+	if content.Size == nil {
+		return Ref{}, fmt.Errorf("invalid content: missing size")
+	}
+	r.ETag = content.ETag
+	r.Size = *content.Size
+	return r, nil
+}
+
+// Store reads and writes the objects of a single bucket.
+//
+// For example, with the S3 upload manager of the AWS SDK, Put is
+//
+//	out, err := uploader.Upload(ctx, &s3.PutObjectInput{
+//		Bucket: &bucket, Key: &key, Body: r})
+//	if err != nil {
+//		return "", err
+//	}
+//	return *out.ETag, nil
+//
+// and Get calls GetObject with IfMatch set to etag, if not empty.
+type Store interface {
+	// Bucket returns the name of the bucket.
+	Bucket() string
+
+	// Put writes all data from r to the object with key and returns
+	// its ETag.
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+
+	// Get returns a ReadCloser of the object with key. If etag is not
+	// empty, and the ETag of the object differs, an error is returned.
+	Get(ctx context.Context, key, etag string) (io.ReadCloser, error)
+}
+
+// Put streams all data from r to the object with key in s and returns its
+// Ref.
+func Put(ctx context.Context, s Store, key string, r io.Reader) (Ref, error) {
+	h := sha256.New()
+	cr := &countReader{r: io.TeeReader(r, h)}
+	etag, err := s.Put(ctx, key, cr)
+	if err != nil {
+		return Ref{}, fmt.Errorf("put %v: %w", key, err)
+	}
+	ref := Ref{Bucket: s.Bucket(), Key: key, ETag: etag, Size: cr.n}
+	h.Sum(ref.SHA256[:0])
+	return ref, nil
+}
+
+type countReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += uint64(n)
+	return n, err
+}
+
+// Anchor streams all data from r to the object with key in s with Put, and
+// then submits its Reference Entry to chainID using ComposeCreate with es. If
+// chainID is nil, the Entry creates a new chain.
+func Anchor(ctx context.Context, c *factom.Client, es factom.EsAddress,
+	chainID *factom.Bytes32, s Store, key string,
+	r io.Reader) (Ref, factom.EntryHash, factom.TxID, error) {
+	ref, err := Put(ctx, s, key, r)
+	if err != nil {
+		return Ref{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	e := ref.Entry(chainID)
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return Ref{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	return ref, *e.Hash, txID, nil
+}
+
+// Open returns a ReadCloser of the object of ref from s, which must be for
+// ref.Bucket. The payload is verified as it is read. If it is larger than
+// ref.Size, or does not match ref.SHA256 and ref.Size when the end is reached,
+// Read returns an error instead of io.EOF, so a payload that is read to the
+// end is always verified.
+func Open(ctx context.Context, s Store, ref Ref) (io.ReadCloser, error) {
+	if s.Bucket() != ref.Bucket {
+		return nil, fmt.Errorf("bucket %q does not match %q",
+			s.Bucket(), ref.Bucket)
+	}
+	rc, err := s.Get(ctx, ref.Key, ref.ETag)
+	if err != nil {
+		return nil, fmt.Errorf("get %v: %w", ref.Key, err)
+	}
+	return &verifyReader{rc: rc, ref: ref, h: sha256.New()}, nil
+}
+
+// Get reads and verifies the payload of the Reference Entry e from s.
+func Get(ctx context.Context, s Store, e factom.Entry) ([]byte, error) {
+	ref, err := ParseRef(e)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := Open(ctx, s, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+type verifyReader struct {
+	rc  io.ReadCloser
+	ref Ref
+	h   hash.Hash
+	n   uint64
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, err := v.rc.Read(p)
+	v.h.Write(p[:n])
+	v.n += uint64(n)
+	if v.n > v.ref.Size {
+		return 0, fmt.Errorf("payload exceeds size %v", v.ref.Size)
+	}
+	if err == io.EOF {
+		if v.n != v.ref.Size {
+			return n, fmt.Errorf("payload size %v does not match size %v",
+				v.n, v.ref.Size)
+		}
+		var sum factom.Bytes32
+		if v.h.Sum(sum[:0]); sum != v.ref.SHA256 {
+			return n, fmt.Errorf("payload does not match SHA-256")
+		}
+	}
+	return n, err
+}
+
+func (v *verifyReader) Close() error {
+	return v.rc.Close()
+}
+
+// DirStore is a Store of the files in a local directory, whose base name is
+// the bucket name. Keys may contain slashes, which create subdirectories. The
+// ETag of a file is its quoted hex MD5 hash, as for single part S3 uploads.
+type DirStore string
+
+var _ Store = DirStore("")
+
+// Bucket returns the base name of the directory.
+func (d DirStore) Bucket() string {
+	return filepath.Base(string(d))
+}
+
+func (d DirStore) path(key string) (string, error) {
+	path := filepath.Join(string(d), filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(string(d))+
+		string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key")
+	}
+	return path, nil
+}
+
+// Put writes all data from r to a temporary file, which is renamed to key
+// once complete.
+func (d DirStore) Put(ctx context.Context, key string,
+	r io.Reader) (string, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	h := md5.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	return md5ETag(h), nil
+}
+
+// Get opens the file for key. If etag is not empty, the file is hashed first
+// to check its ETag.
+func (d DirStore) Get(ctx context.Context,
+	key, etag string) (io.ReadCloser, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if etag == "" {
+		return f, nil
+	}
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if md5ETag(h) != etag {
+		f.Close()
+		return nil, fmt.Errorf("precondition failed: ETag does not match")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func md5ETag(h hash.Hash) string {
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
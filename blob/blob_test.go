@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package blob_test
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const payload = "The quick brown fox jumps over the lazy dog"
+
+func TestAnchor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	s := DirStore(filepath.Join(dir, "bucket"))
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+
+	ref, _, _, err := Anchor(ctx, c, es, nil, s, "docs/fox.txt",
+		strings.NewReader(payload))
+	require.NoError(err)
+	assert.Equal(Ref{
+		Bucket: "bucket",
+		Key:    "docs/fox.txt",
+		ETag:   fmt.Sprintf(`"%x"`, md5.Sum([]byte(payload))),
+		SHA256: sha256.Sum256([]byte(payload)),
+		Size:   uint64(len(payload)),
+	}, ref)
+	requests := c.DryRun.Requests()
+	require.Len(requests, 2)
+	assert.Equal("commit-chain", requests[0].Method)
+
+	e := ref.Entry(nil)
+	parsed, err := ParseRef(e)
+	require.NoError(err)
+	assert.Equal(ref, parsed)
+
+	data, err := Get(ctx, s, e)
+	require.NoError(err)
+	assert.Equal(payload, string(data))
+
+	// Modified objects fail the ETag precondition, or the SHA-256 check
+	// when there is no ETag.
+	path := filepath.Join(string(s), "docs", "fox.txt")
+	require.NoError(ioutil.WriteFile(path, []byte(strings.ToUpper(payload)), 0644))
+	_, err = Get(ctx, s, e)
+	assert.EqualError(err, "get docs/fox.txt: "+
+		"precondition failed: ETag does not match")
+	noETag := ref
+	noETag.ETag = ""
+	_, err = Get(ctx, s, noETag.Entry(nil))
+	assert.EqualError(err, "payload does not match SHA-256")
+
+	_, err = Get(ctx, DirStore(dir), e)
+	assert.EqualError(err, fmt.Sprintf("bucket %q does not match %q",
+		filepath.Base(dir), "bucket"))
+	_, err = Put(ctx, s, "../escape", strings.NewReader(payload))
+	assert.EqualError(err, "put ../escape: invalid key")
+}
+
+func TestParseRefInvalid(t *testing.T) {
+	valid := Ref{Bucket: "b", Key: "k", Size: 1}.Entry(nil)
+	for _, test := range []struct {
+		Name   string
+		ExtIDs []factom.Bytes
+		Error  string
+	}{{
+		Name:   "tag",
+		ExtIDs: []factom.Bytes{{}, valid.ExtIDs[1], valid.ExtIDs[2], valid.ExtIDs[3]},
+		Error:  "invalid ExtIDs",
+	}, {
+		Name:   "bucket",
+		ExtIDs: []factom.Bytes{Tag, {}, valid.ExtIDs[2], valid.ExtIDs[3]},
+		Error:  "missing bucket",
+	}, {
+		Name:   "key",
+		ExtIDs: []factom.Bytes{Tag, valid.ExtIDs[1], {}, valid.ExtIDs[3]},
+		Error:  "missing key",
+	}, {
+		Name:   "SHA-256",
+		ExtIDs: []factom.Bytes{Tag, valid.ExtIDs[1], valid.ExtIDs[2], {}},
+		Error:  "invalid SHA-256 length",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := ParseRef(factom.Entry{ExtIDs: test.ExtIDs,
+				Content: valid.Content})
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+	_, err := ParseRef(factom.Entry{ExtIDs: valid.ExtIDs,
+		Content: factom.Bytes(`{"etag":""}`)})
+	assert.EqualError(t, err, "invalid content: missing size")
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrorNoEBlock is returned by Client.EBlockAtHeight if the Chain has no
+// EBlock in the DBlock at the requested height.
+var ErrorNoEBlock = fmt.Errorf("no EBlock for chain at height")
+
+// DBlockByHeight returns the DBlock at height. The EBlocks have their ChainID
+// and KeyMR, but not their Entries.
+func (c *Client) DBlockByHeight(ctx context.Context, height uint32) (DBlock, error) {
+	db := DBlock{Height: height}
+	if err := db.Get(ctx, c); err != nil {
+		return DBlock{}, err
+	}
+	return db, nil
+}
+
+// ABlockByHeight returns the ABlock at height.
+func (c *Client) ABlockByHeight(ctx context.Context, height uint32) (ABlock, error) {
+	ab := ABlock{Height: height}
+	if err := ab.Get(ctx, c); err != nil {
+		return ABlock{}, err
+	}
+	return ab, nil
+}
+
+// FBlockByHeight returns the FBlock at height, with all Transactions
+// populated.
+func (c *Client) FBlockByHeight(ctx context.Context, height uint32) (FBlock, error) {
+	fb := FBlock{Height: height}
+	if err := fb.Get(ctx, c); err != nil {
+		return FBlock{}, err
+	}
+	return fb, nil
+}
+
+// EBlockAtHeight returns the EBlock for chainID in the DBlock at height, with
+// its Timestamp and all Entry Timestamps established by the DBlock.
+//
+// ErrorNoEBlock is returned if the Chain has no EBlock at height, which is the
+// case for most heights of most Chains.
+func (c *Client) EBlockAtHeight(ctx context.Context,
+	chainID Bytes32, height uint32) (EBlock, error) {
+	db, err := c.DBlockByHeight(ctx, height)
+	if err != nil {
+		return EBlock{}, err
+	}
+	dbEB := db.EBlock(chainID)
+	if dbEB == nil {
+		return EBlock{}, ErrorNoEBlock
+	}
+	eb := EBlock{ChainID: &chainID, KeyMR: dbEB.KeyMR}
+	if err := eb.Get(ctx, c); err != nil {
+		return EBlock{}, err
+	}
+	if eb.Height != height {
+		return EBlock{}, fmt.Errorf("EBlock height %v, expected %v",
+			eb.Height, height)
+	}
+	eb.SetTimestamp(db.Timestamp)
+	return eb, nil
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByHeight(t *testing.T) {
+	ctx := context.Background()
+	chainID := ComputeChainID([]Bytes{Bytes("by height")})
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("first")}},
+		[]Entry{{Content: Bytes("second")}, {Content: Bytes("third")}})
+
+	t.Run("DBlockByHeight", func(t *testing.T) {
+		db, err := c.DBlockByHeight(ctx, 11)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(11), db.Height)
+		assert.Equal(t, mockDBlockTimestamp(11), db.Timestamp)
+		assert.NotNil(t, db.EBlock(chainID))
+
+		_, err = c.DBlockByHeight(ctx, 12)
+		assert.Error(t, err)
+	})
+
+	t.Run("EBlockAtHeight", func(t *testing.T) {
+		eb, err := c.EBlockAtHeight(ctx, chainID, 11)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(11), eb.Height)
+		assert.Equal(t, mockDBlockTimestamp(11), eb.Timestamp)
+		require.Len(t, eb.Entries, 2)
+		// All Entries are before the first minute marker.
+		for _, e := range eb.Entries {
+			assert.Equal(t, mockDBlockTimestamp(11).Add(time.Minute),
+				e.Timestamp)
+		}
+
+		_, err = c.EBlockAtHeight(ctx, Bytes32{1}, 11)
+		assert.Equal(t, ErrorNoEBlock, err)
+	})
+}
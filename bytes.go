@@ -23,8 +23,10 @@
 package factom
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
 )
 
 // Bytes32 implements encoding.TextMarshaler and encoding.TextUnmarshaler to
@@ -34,8 +36,18 @@ type Bytes32 [32]byte
 
 // Bytes implements encoding.TextMarshaler and encoding.TextUnmarshaler to
 // encode and decode hex strings, such as an Entry's ExtIDs or Content.
+//
+// Use UnmarshalTextStrict to accept only the canonical lowercase hex produced
+// by MarshalText. See Base64Bytes for the factomd and factom-walletd fields that
+// are base64 rather than hex.
 type Bytes []byte
 
+// Base64Bytes implements encoding.TextMarshaler and encoding.TextUnmarshaler
+// to encode and decode padded standard base64 strings. It is only for the few
+// factomd and factom-walletd API fields that use base64 rather than hex. Use
+// Bytes for everything else.
+type Base64Bytes []byte
+
 // NewBytes32 returns a Bytes32 populated with the data from s32, a hex encoded
 // string.
 func NewBytes32(s32 string) Bytes32 {
@@ -82,6 +94,83 @@ func (b *Bytes) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// UnmarshalText decodes a padded standard base64 string into b.
+func (b *Base64Bytes) UnmarshalText(text []byte) error {
+	data := make(Base64Bytes, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return err
+	}
+	*b = data[:n]
+	return nil
+}
+
+// MarshalText encodes b as a padded standard base64 string.
+func (b Base64Bytes) MarshalText() ([]byte, error) {
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(text, b)
+	return text, nil
+}
+
+// UnmarshalTextStrict decodes text into b only if it is canonical, that is
+// lowercase hex as produced by MarshalText.
+func (b *Bytes) UnmarshalTextStrict(text []byte) error {
+	for i, c := range text {
+		if ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') {
+			continue
+		}
+		return fmt.Errorf("non-canonical hex: invalid byte at %v: %q",
+			i, c)
+	}
+	data := make(Bytes, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(data, text); err != nil {
+		return err
+	}
+	*b = data
+	return nil
+}
+
+// WriteHex writes b to w as a hex string, without first encoding all of b in
+// memory. The number of bytes written to w is returned.
+func (b Bytes) WriteHex(w io.Writer) (int64, error) {
+	n, err := hex.NewEncoder(w).Write(b)
+	return int64(hex.EncodedLen(n)), err
+}
+
+// ReadHex decodes a hex string read from r until io.EOF into b, without first
+// reading all of the hex from r into memory. The number of bytes read from r is
+// returned.
+func (b *Bytes) ReadHex(r io.Reader) (int64, error) {
+	cr := countingReader{R: r}
+	var data []byte
+	buf := make([]byte, 32*1024)
+	dec := hex.NewDecoder(&cr)
+	for {
+		n, err := dec.Read(buf)
+		data = append(data, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return cr.N, err
+		}
+	}
+	*b = data
+	return cr.N, nil
+}
+
+// countingReader counts the bytes read from R.
+type countingReader struct {
+	R io.Reader
+	N int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.R.Read(p)
+	r.N += int64(n)
+	return n, err
+}
+
 // String encodes b as a hex string.
 func (b Bytes32) String() string {
 	text, _ := b.MarshalText()
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Bytes32Set is a set of Bytes32, such as ChainIDs, Entry Hashes, or RCD
+// hashes. Since Bytes32 is an array, it is used directly as the map key, so
+// Add, Has, and Remove do not allocate, unlike sets keyed by the string or hex
+// of the hash.
+//
+// A nil Bytes32Set is empty and may be read, but not added to.
+type Bytes32Set map[Bytes32]struct{}
+
+// NewBytes32Set returns a Bytes32Set containing hashes.
+func NewBytes32Set(hashes ...Bytes32) Bytes32Set {
+	s := make(Bytes32Set, len(hashes))
+	for _, h := range hashes {
+		s[h] = struct{}{}
+	}
+	return s
+}
+
+// Add adds h to s and returns true if h was not already in s.
+func (s Bytes32Set) Add(h Bytes32) bool {
+	if _, ok := s[h]; ok {
+		return false
+	}
+	s[h] = struct{}{}
+	return true
+}
+
+// Has returns true if h is in s.
+func (s Bytes32Set) Has(h Bytes32) bool {
+	_, ok := s[h]
+	return ok
+}
+
+// Remove removes h from s.
+func (s Bytes32Set) Remove(h Bytes32) {
+	delete(s, h)
+}
+
+// Sorted returns the elements of s in ascending order.
+func (s Bytes32Set) Sorted() []Bytes32 {
+	hashes := make([]Bytes32, 0, len(s))
+	for h := range s {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+	return hashes
+}
+
+// Bytes32Window remembers the most recent Size distinct Bytes32 added to it,
+// for deduplicating a stream of Entries or messages without unbounded memory.
+// Once full, each new Bytes32 evicts the oldest. After the window fills, Add
+// does not allocate.
+//
+// A Bytes32Window is not safe for concurrent use.
+type Bytes32Window struct {
+	// Size is the number of Bytes32 remembered. It must be set before the
+	// first Add. If zero, nothing is remembered.
+	Size int
+
+	set  Bytes32Set
+	ring []Bytes32
+	next int
+}
+
+// Add adds h to w and returns true if h was not already in w.
+func (w *Bytes32Window) Add(h Bytes32) bool {
+	if w.Size <= 0 {
+		return true
+	}
+	if w.set.Has(h) {
+		return false
+	}
+	if w.set == nil {
+		w.set = make(Bytes32Set, w.Size)
+		w.ring = make([]Bytes32, 0, w.Size)
+	}
+	if len(w.ring) < w.Size {
+		w.ring = append(w.ring, h)
+	} else {
+		w.set.Remove(w.ring[w.next])
+		w.ring[w.next] = h
+		w.next = (w.next + 1) % w.Size
+	}
+	w.set.Add(h)
+	return true
+}
+
+// Has returns true if h is in w.
+func (w *Bytes32Window) Has(h Bytes32) bool {
+	return w.set.Has(h)
+}
+
+// Len returns the number of Bytes32 in w.
+func (w *Bytes32Window) Len() int {
+	return len(w.set)
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes32Set(t *testing.T) {
+	assert := assert.New(t)
+	var empty Bytes32Set
+	assert.False(empty.Has(Bytes32{1}))
+
+	s := NewBytes32Set(Bytes32{2}, Bytes32{1})
+	assert.True(s.Has(Bytes32{1}))
+	assert.False(s.Add(Bytes32{1}))
+	assert.True(s.Add(Bytes32{3}))
+	assert.Equal([]Bytes32{{1}, {2}, {3}}, s.Sorted())
+	s.Remove(Bytes32{2})
+	assert.False(s.Has(Bytes32{2}))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Has(Bytes32{1})
+		s.Add(Bytes32{1})
+	})
+	assert.Equal(0.0, allocs)
+}
+
+func TestBytes32Window(t *testing.T) {
+	assert := assert.New(t)
+	w := Bytes32Window{Size: 2}
+	assert.True(w.Add(Bytes32{1}))
+	assert.True(w.Add(Bytes32{2}))
+	assert.False(w.Add(Bytes32{1}))
+	assert.True(w.Add(Bytes32{3}))
+	assert.Equal(2, w.Len())
+	assert.False(w.Has(Bytes32{1}))
+	assert.True(w.Has(Bytes32{2}))
+	assert.True(w.Add(Bytes32{1}))
+	assert.False(w.Has(Bytes32{2}))
+
+	var none Bytes32Window
+	assert.True(none.Add(Bytes32{1}))
+	assert.True(none.Add(Bytes32{1}))
+}
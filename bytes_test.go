@@ -82,6 +82,11 @@ var unmarshalJSONtests = []unmarshalJSONTest{{
 	Name: "invalid symbol",
 	Data: `"DA56930e8693fb7c0a13aac4d01cf26184d760f2fd92d2f0a62aa630b1zxcva7"`,
 	Err:  "encoding/hex: invalid byte: U+007A 'z'",
+}, {
+	Name: "Bytes/base64",
+	Data: `"aGVsbG8gd29ybGQ="`,
+	Err:  "encoding/hex: invalid byte: U+0047 'G'",
+	Un:   new(Bytes),
 }, {
 	Name: "invalid type",
 	Data: `{}`,
@@ -167,3 +172,84 @@ func TestBytes(t *testing.T) {
 		assert.False(t, Bytes32{0: 1}.IsZero())
 	})
 }
+
+func TestBase64Bytes(t *testing.T) {
+	assert := assert.New(t)
+	var b Base64Bytes
+	assert.NoError(json.Unmarshal([]byte(`"aGVsbG8gd29ybGQ="`), &b))
+	assert.Equal(Base64Bytes("hello world"), b)
+
+	data, err := json.Marshal(b)
+	assert.NoError(err)
+	assert.Equal(`"aGVsbG8gd29ybGQ="`, string(data))
+
+	// Hex is not accepted as base64 unless it happens to be valid base64.
+	assert.EqualError(json.Unmarshal([]byte(`"abc"`), &b),
+		"illegal base64 data at input byte 0")
+	// Unpadded base64 is rejected.
+	assert.Error(json.Unmarshal([]byte(`"aGVsbG8gd29ybGQ"`), &b))
+}
+
+func TestBytesUnmarshalTextStrict(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Text string
+		Exp  Bytes
+		Err  string
+	}{{
+		Name: "valid",
+		Text: "da56",
+		Exp:  Bytes{0xda, 0x56},
+	}, {
+		Name: "empty",
+		Text: "",
+		Exp:  Bytes{},
+	}, {
+		Name: "uppercase",
+		Text: "DA56",
+		Err:  `non-canonical hex: invalid byte at 0: 'D'`,
+	}, {
+		Name: "base64",
+		Text: "aGk=",
+		Err:  `non-canonical hex: invalid byte at 1: 'G'`,
+	}, {
+		Name: "odd length",
+		Text: "da5",
+		Err:  "encoding/hex: odd length hex string",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			var b Bytes
+			err := b.UnmarshalTextStrict([]byte(test.Text))
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Exp, b)
+		})
+	}
+}
+
+func TestBytesHexStreaming(t *testing.T) {
+	assert := assert.New(t)
+	data := make(Bytes, 100*1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	var buf strings.Builder
+	n, err := data.WriteHex(&buf)
+	assert.NoError(err)
+	assert.Equal(int64(len(data)*2), n)
+	assert.Equal(data.String(), buf.String())
+
+	var b Bytes
+	n, err = b.ReadHex(strings.NewReader(buf.String()))
+	assert.NoError(err)
+	assert.Equal(int64(len(data)*2), n)
+	assert.Equal(data, b)
+
+	_, err = b.ReadHex(strings.NewReader("abz"))
+	assert.EqualError(err, "encoding/hex: invalid byte: U+007A 'z'")
+	assert.Equal(data, b, "unchanged on error")
+}
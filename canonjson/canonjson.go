@@ -0,0 +1,213 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package canonjson encodes JSON deterministically, so that JSON Entry
+// Content that is signed can be re-encoded by a verifier and produce exactly
+// the signed bytes, regardless of map iteration order, whitespace, or how
+// numbers happen to be formatted.
+//
+// The canonical form has no insignificant whitespace, object keys sorted by
+// their UTF-8 bytes, strings without HTML escaping, and numbers formatted
+// like ECMAScript's Number.prototype.toString, as in RFC 8785. Unlike RFC
+// 8785, numbers written as integers are kept exactly, even if they cannot be
+// represented by a float64, since token amounts may use all 64 bits.
+package canonjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal returns the canonical JSON encoding of v, which is first encoded
+// with json.Marshal.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(data)
+}
+
+// Canonicalize returns the canonical form of the JSON data.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid JSON: trailing data")
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify returns an error if data is not exactly in canonical form. Verifiers
+// of signed JSON Content should reject Content that fails Verify, since a
+// signature over a non-canonical encoding cannot be reproduced from the
+// decoded value.
+func Verify(data []byte) error {
+	canonical, err := Canonicalize(data)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, canonical) {
+		return fmt.Errorf("JSON is not in canonical form")
+	}
+	return nil
+}
+
+func encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		n, err := formatNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(n)
+	case string:
+		encodeString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encode(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodeString(buf, key)
+			buf.WriteByte(':')
+			if err := encode(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value type: %T", v)
+	}
+	return nil
+}
+
+func encodeString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	// Encoding a string never fails, and Encode appends a newline.
+	enc.Encode(s)
+	buf.Truncate(buf.Len() - 1)
+}
+
+// formatNumber returns the canonical form of n.
+func formatNumber(n json.Number) (string, error) {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		// Integers are kept exactly, which only requires removing
+		// the sign from -0.
+		var i big.Int
+		if _, ok := i.SetString(s, 10); !ok {
+			return "", fmt.Errorf("invalid JSON number: %v", s)
+		}
+		return i.String(), nil
+	}
+
+	// ParseFloat returns an infinity along with ErrRange for numbers too
+	// large for a float64.
+	f, err := strconv.ParseFloat(s, 64)
+	if math.IsInf(f, 0) {
+		return "", fmt.Errorf("JSON number out of range: %v", s)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid JSON number: %v", s)
+	}
+	return formatFloat(f), nil
+}
+
+// formatFloat formats f like ECMAScript's Number.prototype.toString.
+func formatFloat(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	var sign string
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// The shortest decimal digits of f, and the exponent of the first.
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	i := strings.IndexByte(e, 'e')
+	digits := strings.Replace(e[:i], ".", "", 1)
+	x, _ := strconv.Atoi(e[i+1:])
+	// n is the position of the decimal point relative to the digits.
+	n, k := x+1, len(digits)
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+	expSign := "+"
+	if n-1 < 0 {
+		expSign = "-"
+	}
+	exp := strconv.Itoa(abs(n - 1))
+	if k == 1 {
+		return sign + digits + "e" + expSign + exp
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + expSign + exp
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
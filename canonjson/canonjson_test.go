@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package canonjson_test
+
+import (
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom/canonjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var canonicalizeTests = []struct {
+	Name string
+	JSON string
+	Exp  string
+}{{
+	Name: "whitespace",
+	JSON: " { \"a\" : [ 1 , true , null ] } \n",
+	Exp:  `{"a":[1,true,null]}`,
+}, {
+	Name: "sorted keys",
+	JSON: `{"b":{"d":1,"c":2},"a":0,"A":0,"é":0}`,
+	Exp:  `{"A":0,"a":0,"b":{"c":2,"d":1},"é":0}`,
+}, {
+	Name: "strings",
+	JSON: `"<A&\/>é\n"`,
+	Exp:  "\"<A&/>é\\n\"",
+}, {
+	Name: "large integers",
+	JSON: `[18446744073709551615, -0, 100]`,
+	Exp:  `[18446744073709551615,0,100]`,
+}, {
+	Name: "numbers",
+	JSON: `[1.0, 1e2, 1.5E-7, 0.000001, 123e18, 1e21, -2.50, 0.0, 4.5e300]`,
+	Exp:  `[1,100,1.5e-7,0.000001,123000000000000000000,1e+21,-2.5,0,4.5e+300]`,
+}, {
+	Name: "float precision",
+	JSON: `[0.1, 333333333.33333329, 1E-323]`,
+	Exp:  `[0.1,333333333.3333333,1e-323]`,
+}}
+
+func TestCanonicalize(t *testing.T) {
+	for _, test := range canonicalizeTests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := canonjson.Canonicalize([]byte(test.JSON))
+			require.NoError(t, err)
+			assert.Equal(t, test.Exp, string(data))
+			assert.NoError(t, canonjson.Verify(data))
+		})
+	}
+
+	_, err := canonjson.Canonicalize([]byte(`{} {}`))
+	assert.EqualError(t, err, "invalid JSON: trailing data")
+	_, err = canonjson.Canonicalize([]byte(`1e400`))
+	assert.EqualError(t, err, "JSON number out of range: 1e400")
+	_, err = canonjson.Canonicalize([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	v := struct {
+		Z string            `json:"z"`
+		M map[string]uint64 `json:"m"`
+	}{Z: "z", M: map[string]uint64{"y": 2, "x": 1}}
+	data, err := canonjson.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"m":{"x":1,"y":2},"z":"z"}`, string(data))
+}
+
+func TestVerify(t *testing.T) {
+	assert.NoError(t, canonjson.Verify([]byte(`{"a":1,"b":[]}`)))
+	assert.EqualError(t, canonjson.Verify([]byte(`{"b":[],"a":1}`)),
+		"JSON is not in canonical form")
+	assert.EqualError(t, canonjson.Verify([]byte(`{"a":1.0}`)),
+		"JSON is not in canonical form")
+	assert.Error(t, canonjson.Verify([]byte(`nul`)))
+}
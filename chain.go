@@ -0,0 +1,241 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Chain is a Factom Chain identified by its ChainID.
+//
+// If Codecs is not nil, GetAllEntries decodes the Content of each Entry using
+// the ContentCodec registered for ID.
+type Chain struct {
+	ID     Bytes32
+	Codecs *CodecRegistry
+
+	// Signers, if not nil, is used by GetFirstEntry to determine the
+	// Creator of the Chain. It must return the RCDs with valid signatures
+	// of the Entry, or nil if the Entry is not signed. See
+	// fat103.Signers.
+	Signers func(Entry) []RCD
+
+	// Cache, if not nil, is used by GetFirstEntry to avoid walking the
+	// Chain again for ChainMetadata that has already been queried.
+	Cache *ChainCache
+}
+
+// ChainMetadata describes the creation of a Chain.
+type ChainMetadata struct {
+	// FirstEntry is the Entry that created the Chain. Its ExtIDs are the
+	// NameIDs of the Chain.
+	FirstEntry Entry
+
+	// Height is the DBlock height of the first EBlock of the Chain.
+	Height uint32
+
+	// Created is the Timestamp of the FirstEntry.
+	Created time.Time
+
+	// Creator is the FAAddress of the first RCD that signed the
+	// FirstEntry, if Chain.Signers is not nil and it is signed.
+	Creator *FAAddress
+}
+
+// NameIDs returns the ExtIDs of the FirstEntry, which determine the ChainID.
+func (m ChainMetadata) NameIDs() []Bytes {
+	return m.FirstEntry.ExtIDs
+}
+
+// ChainCache retains the ChainMetadata returned by Chain.GetFirstEntry. Since
+// the first Entry of a Chain never changes, cached values never expire.
+//
+// The zero value is ready to use, and a ChainCache is safe for concurrent use,
+// so it may be shared by many Chains.
+type ChainCache struct {
+	mu       sync.RWMutex
+	metadata map[Bytes32]ChainMetadata
+}
+
+// Lookup returns the cached ChainMetadata for chainID, if any.
+func (cc *ChainCache) Lookup(chainID Bytes32) (ChainMetadata, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	m, ok := cc.metadata[chainID]
+	return m, ok
+}
+
+func (cc *ChainCache) store(chainID Bytes32, m ChainMetadata) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.metadata == nil {
+		cc.metadata = make(map[Bytes32]ChainMetadata)
+	}
+	cc.metadata[chainID] = m
+}
+
+// GetFirstEntry returns the ChainMetadata for the first Entry of ch.
+//
+// The Chain is walked back from the chain head to the first EBlock, reusing a
+// single EBlock as EBlock.GetFirst does, and then the DBlock at the height of
+// the first EBlock is queried to establish the Timestamp of the first Entry.
+//
+// If ch.Cache is not nil, it is checked first and updated after a successful
+// query.
+func (ch Chain) GetFirstEntry(
+	ctx context.Context, c *Client) (ChainMetadata, error) {
+
+	if ch.Cache != nil {
+		if m, ok := ch.Cache.Lookup(ch.ID); ok {
+			return m, nil
+		}
+	}
+
+	chainID := ch.ID
+	eb := EBlock{ChainID: &chainID}
+	if err := eb.GetFirst(ctx, c); err != nil {
+		return ChainMetadata{}, err
+	}
+	if len(eb.Entries) == 0 {
+		return ChainMetadata{}, fmt.Errorf("first EBlock has no Entries")
+	}
+
+	db := DBlock{Height: eb.Height}
+	if err := db.Get(ctx, c); err != nil {
+		return ChainMetadata{}, err
+	}
+	dbEB := db.EBlock(chainID)
+	if dbEB == nil || *dbEB.KeyMR != *eb.KeyMR {
+		return ChainMetadata{}, fmt.Errorf(
+			"first EBlock not found in DBlock %v", db.Height)
+	}
+	eb.SetTimestamp(db.Timestamp)
+
+	m := ChainMetadata{FirstEntry: eb.Entries[0], Height: eb.Height}
+	if err := m.FirstEntry.Get(ctx, c); err != nil {
+		return ChainMetadata{}, err
+	}
+	m.Created = m.FirstEntry.Timestamp
+
+	if ch.Signers != nil {
+		if rcds := ch.Signers(m.FirstEntry); len(rcds) > 0 {
+			creator := rcds[0].FAAddress()
+			m.Creator = &creator
+		}
+	}
+
+	if ch.Cache != nil {
+		ch.Cache.store(ch.ID, m)
+	}
+	return m, nil
+}
+
+// TypedEntry is an Entry along with its Content decoded by a ContentCodec.
+type TypedEntry struct {
+	Entry
+
+	// Value is a pointer to the decoded Content, or nil if no ContentCodec
+	// is registered for the Entry's ChainID.
+	Value interface{}
+}
+
+// GetAllEntries returns all Entries in the chain in order from the first
+// Entry to the latest.
+//
+// If ch.Codecs is not nil and has a codec registered for ch.ID, the Content of
+// every Entry is decoded into TypedEntry.Value, and any decoding error is
+// returned.
+func (ch Chain) GetAllEntries(ctx context.Context, c *Client) ([]TypedEntry, error) {
+	chainID := ch.ID
+	eblocks, err := EBlock{ChainID: &chainID}.GetPrevAll(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType *ContentType
+	if ch.Codecs != nil {
+		if t, ok := ch.Codecs.Lookup(ch.ID); ok {
+			contentType = &t
+		}
+	}
+
+	var entries []TypedEntry
+	for i := len(eblocks) - 1; i >= 0; i-- {
+		eb := &eblocks[i]
+		if err := eb.GetEntries(ctx, c); err != nil {
+			return nil, err
+		}
+		for _, e := range eb.Entries {
+			te := TypedEntry{Entry: e}
+			if contentType != nil {
+				te.Value = contentType.New()
+				if err := contentType.Codec.DecodeContent(
+					e.Content, te.Value); err != nil {
+					return nil, fmt.Errorf("entry %v: %w",
+						e.Hash, err)
+				}
+			}
+			entries = append(entries, te)
+		}
+	}
+	return entries, nil
+}
+
+// ScanBack calls fn with each Entry of ch in order from the latest Entry to
+// the first, until fn returns true or an error. EBlocks and Entries are only
+// downloaded as they are reached, so finding a recent Entry does not require
+// downloading the whole Chain.
+//
+// Like EBlock.Get, the Entry Timestamps are not established by the DBlock.
+//
+// Any error returned by fn is returned by ScanBack.
+func (ch Chain) ScanBack(ctx context.Context, c *Client,
+	fn func(e Entry) (stop bool, err error)) error {
+	chainID := ch.ID
+	eb := EBlock{ChainID: &chainID}
+	for {
+		if err := eb.Get(ctx, c); err != nil {
+			return err
+		}
+		for i := len(eb.Entries) - 1; i >= 0; i-- {
+			e := eb.Entries[i]
+			if err := e.Get(ctx, c); err != nil {
+				return err
+			}
+			stop, err := fn(e)
+			if err != nil {
+				return err
+			}
+			if stop {
+				return nil
+			}
+		}
+		if eb.IsFirst() {
+			return nil
+		}
+		eb = eb.Prev()
+	}
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainGetFirstEntry(t *testing.T) {
+	ctx := context.Background()
+	nameIDs := []Bytes{Bytes("first"), Bytes("entry")}
+	chainID := ComputeChainID(nameIDs)
+	first := Entry{ExtIDs: nameIDs, Content: Bytes("first")}
+	c := newMockChain(t, chainID,
+		[]Entry{first, {Content: Bytes("second")}},
+		[]Entry{{Content: Bytes("third")}},
+		[]Entry{{Content: Bytes("fourth")}})
+
+	var requests int
+	transport := c.Factomd.Client.Transport
+	c.Factomd.Client.Transport = RoundTripFunc(
+		func(req *http.Request) *http.Response {
+			requests++
+			res, _ := transport.RoundTrip(req)
+			return res
+		})
+
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	ch := Chain{ID: chainID, Cache: new(ChainCache),
+		Signers: func(e Entry) []RCD {
+			if string(e.Content) != "first" {
+				return nil
+			}
+			return []RCD{fs.RCD()}
+		}}
+
+	m, err := ch.GetFirstEntry(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, nameIDs, m.NameIDs())
+	assert.Equal(t, Bytes("first"), m.FirstEntry.Content)
+	assert.Equal(t, chainID, *m.FirstEntry.ChainID)
+	assert.Equal(t, uint32(10), m.Height)
+	assert.False(t, m.Created.Before(mockDBlockTimestamp(10)))
+	assert.True(t, m.Created.Before(mockDBlockTimestamp(11)))
+	require.NotNil(t, m.Creator)
+	assert.Equal(t, fs.FAAddress(), *m.Creator)
+
+	// chain-head, 3 EBlocks, the DBlock and the Entry.
+	assert.Equal(t, 6, requests)
+
+	cached, err := ch.GetFirstEntry(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, m, cached)
+	assert.Equal(t, 6, requests)
+
+	ch.Signers = nil
+	ch.Cache = nil
+	m, err = ch.GetFirstEntry(ctx, c)
+	require.NoError(t, err)
+	assert.Nil(t, m.Creator)
+
+	_, err = Chain{ID: Bytes32{1}}.GetFirstEntry(ctx, c)
+	assert.Error(t, err)
+}
+
+func TestChainScanBack(t *testing.T) {
+	ctx := context.Background()
+	chainID := ComputeChainID([]Bytes{Bytes("scan back")})
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("first")}},
+		[]Entry{{Content: Bytes("second")}, {Content: Bytes("third")}},
+		[]Entry{{Content: Bytes("fourth")}})
+	var requests int
+	transport := c.Factomd.Client.Transport
+	c.Factomd.Client.Transport = RoundTripFunc(
+		func(req *http.Request) *http.Response {
+			requests++
+			res, _ := transport.RoundTrip(req)
+			return res
+		})
+	ch := Chain{ID: chainID}
+
+	var contents []string
+	require.NoError(t, ch.ScanBack(ctx, c, func(e Entry) (bool, error) {
+		contents = append(contents, string(e.Content))
+		return false, nil
+	}))
+	assert.Equal(t, []string{"fourth", "third", "second", "first"},
+		contents)
+
+	requests, contents = 0, nil
+	require.NoError(t, ch.ScanBack(ctx, c, func(e Entry) (bool, error) {
+		contents = append(contents, string(e.Content))
+		return string(e.Content) == "third", nil
+	}))
+	assert.Equal(t, []string{"fourth", "third"}, contents)
+	// chain-head, and the latest two EBlocks and their Entries, but
+	// nothing from the first EBlock.
+	assert.Equal(t, 5, requests)
+
+	err := ch.ScanBack(ctx, c, func(e Entry) (bool, error) {
+		return false, fmt.Errorf("stop")
+	})
+	assert.EqualError(t, err, "stop")
+}
@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// StringNameIDs returns names as NameIDs.
+func StringNameIDs(names ...string) []Bytes {
+	nameIDs := make([]Bytes, len(names))
+	for i, name := range names {
+		nameIDs[i] = Bytes(name)
+	}
+	return nameIDs
+}
+
+// ComputeNamedChainID returns the ChainID for the NameIDs formed from names,
+// so that a chain may be found from human readable names alone, without a
+// registry.
+func ComputeNamedChainID(names ...string) Bytes32 {
+	return ComputeChainID(StringNameIDs(names...))
+}
+
+// MaxChainIDPrefixLen is the longest hex prefix that SearchChainID accepts.
+// Each additional hex digit multiplies the expected search time by 16.
+const MaxChainIDPrefixLen = 12
+
+// chainIDPrefix matches the leading hex digits of a ChainID.
+type chainIDPrefix struct {
+	full []byte
+	// half is the high nibble of the final odd hex digit, if odd.
+	half byte
+	odd  bool
+}
+
+func parseChainIDPrefix(prefix string) (chainIDPrefix, error) {
+	if len(prefix) == 0 || len(prefix) > MaxChainIDPrefixLen {
+		return chainIDPrefix{}, fmt.Errorf(
+			"invalid prefix length: %v", len(prefix))
+	}
+	prefix = strings.ToLower(prefix)
+	var p chainIDPrefix
+	if len(prefix)%2 == 1 {
+		p.odd = true
+		prefix += "0"
+	}
+	full, err := hex.DecodeString(prefix)
+	if err != nil {
+		return chainIDPrefix{}, fmt.Errorf("invalid prefix: %w", err)
+	}
+	if p.odd {
+		p.half = full[len(full)-1]
+		full = full[:len(full)-1]
+	}
+	p.full = full
+	return p, nil
+}
+
+func (p chainIDPrefix) match(chainID *Bytes32) bool {
+	for i, b := range p.full {
+		if chainID[i] != b {
+			return false
+		}
+	}
+	return !p.odd || chainID[len(p.full)]&0xf0 == p.half
+}
+
+// SearchChainID searches for a nonce which, when appended to nameIDs as the
+// final NameID, produces a ChainID that begins with the hex digits of prefix.
+// This is how Identity Chains obtain their "888888" prefix, and allows
+// protocols to allocate recognizable chain namespaces.
+//
+// The search runs on runtime.NumCPU() goroutines until a match is found or ctx
+// is done. Nonces are 8 byte big endian counters, but which matching nonce is
+// returned depends on scheduling.
+func SearchChainID(ctx context.Context, prefix string,
+	nameIDs ...Bytes) (Bytes, Bytes32, error) {
+	p, err := parseChainIDPrefix(prefix)
+	if err != nil {
+		return nil, Bytes32{}, err
+	}
+
+	// The hashes of the fixed NameIDs are computed once, and only the
+	// hash of the nonce is computed for each attempt.
+	sums := make([]byte, 0, (len(nameIDs)+1)*sha256.Size)
+	for _, id := range nameIDs {
+		sum := sha256Sum(id)
+		sums = append(sums, sum[:]...)
+	}
+
+	var mu sync.Mutex
+	var nonce Bytes
+	var chainID Bytes32
+	search, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	n := runtime.NumCPU()
+	for w := 0; w < n; w++ {
+		w := uint64(w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := make([]byte, len(sums), len(sums)+sha256.Size)
+			copy(data, sums)
+			var try [8]byte
+			for i := w; ; i += uint64(n) {
+				if i/uint64(n)%4096 == 0 && search.Err() != nil {
+					return
+				}
+				binary.BigEndian.PutUint64(try[:], i)
+				sum := sha256Sum(try[:])
+				id := Bytes32(sha256Sum(append(data, sum[:]...)))
+				if !p.match(&id) {
+					continue
+				}
+				mu.Lock()
+				if nonce == nil {
+					nonce = append(Bytes(nil), try[:]...)
+					chainID = id
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	if nonce == nil {
+		return nil, Bytes32{}, ctx.Err()
+	}
+	return nonce, chainID, nil
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeNamedChainID(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal([]Bytes{Bytes("a"), Bytes("b")}, StringNameIDs("a", "b"))
+	assert.Equal(ComputeChainID([]Bytes{Bytes("a"), Bytes("b")}),
+		ComputeNamedChainID("a", "b"))
+}
+
+func TestSearchChainID(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	nameIDs := StringNameIDs("vanity", "test")
+	for _, prefix := range []string{"8", "88", "aBc"} {
+		nonce, chainID, err := SearchChainID(ctx, prefix, nameIDs...)
+		require.NoError(err, prefix)
+		assert.Len(nonce, 8)
+		assert.True(strings.HasPrefix(chainID.String(),
+			strings.ToLower(prefix)), prefix)
+		assert.Equal(ComputeChainID(append(nameIDs, nonce)), chainID)
+	}
+
+	// A search that is canceled before a match is found returns ctx.Err().
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err := SearchChainID(ctx, "000000000000", nameIDs...)
+	assert.Equal(context.Canceled, err)
+
+	_, _, err = SearchChainID(ctx, "")
+	assert.EqualError(err, "invalid prefix length: 0")
+	_, _, err = SearchChainID(ctx, "0000000000000")
+	assert.EqualError(err, "invalid prefix length: 13")
+	_, _, err = SearchChainID(ctx, "xy")
+	assert.EqualError(err,
+		"invalid prefix: encoding/hex: invalid byte: U+0078 'x'")
+}
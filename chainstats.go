@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"time"
+)
+
+// ChainStats summarizes the Entries of a Chain.
+type ChainStats struct {
+	// EBlockCount and EntryCount are the number of EBlocks and Entries in
+	// the Chain.
+	EBlockCount uint64
+	EntryCount  uint64
+
+	// ContentSize is the total length of the Content of all Entries, and
+	// TotalSize is the total encoded length of all Entries.
+	ContentSize uint64
+	TotalSize   uint64
+
+	// ECSpent is the total Entry Credit cost of all Entries, including
+	// the NewChainCost paid by the first Entry.
+	ECSpent uint64
+
+	// FirstEntry and LastEntry are the Timestamps of the first and latest
+	// Entries, as established by their DBlocks.
+	FirstEntry time.Time
+	LastEntry  time.Time
+
+	// EntriesPerEBlock maps a number of Entries to the number of EBlocks
+	// with that many Entries.
+	EntriesPerEBlock map[int]uint64
+}
+
+// Stats walks all EBlocks and Entries of ch and returns their ChainStats.
+//
+// Every EBlock and Entry is loaded with EBlock.Get and Entry.Get, so if the
+// Client.Store is not nil, only data not already in the Store is requested
+// from factomd. Only the DBlock headers of the first and latest EBlocks are
+// queried to establish the Timestamps.
+func (ch Chain) Stats(ctx context.Context, c *Client) (ChainStats, error) {
+	chainID := ch.ID
+	eblocks, err := EBlock{ChainID: &chainID}.GetPrevAll(ctx, c)
+	if err != nil {
+		return ChainStats{}, err
+	}
+
+	stats := ChainStats{
+		EBlockCount:      uint64(len(eblocks)),
+		EntriesPerEBlock: make(map[int]uint64),
+	}
+	for i := len(eblocks) - 1; i >= 0; i-- {
+		eb := &eblocks[i]
+		if i == 0 || i == len(eblocks)-1 {
+			db := DBlockHeader{Height: eb.Height}
+			if err := db.Get(ctx, c); err != nil {
+				return ChainStats{}, err
+			}
+			eb.SetTimestamp(db.Timestamp)
+		}
+		if err := eb.GetEntries(ctx, c); err != nil {
+			return ChainStats{}, err
+		}
+
+		stats.EntriesPerEBlock[len(eb.Entries)]++
+		for j, e := range eb.Entries {
+			size := e.MarshalBinaryLen()
+			cost, err := EntryCost(size,
+				i == len(eblocks)-1 && j == 0)
+			if err != nil {
+				return ChainStats{}, err
+			}
+			stats.EntryCount++
+			stats.ContentSize += uint64(len(e.Content))
+			stats.TotalSize += uint64(size)
+			stats.ECSpent += uint64(cost)
+		}
+
+		if len(eb.Entries) == 0 {
+			continue
+		}
+		if i == len(eblocks)-1 {
+			stats.FirstEntry = eb.Entries[0].Timestamp
+		}
+		if i == 0 {
+			stats.LastEntry = eb.Entries[len(eb.Entries)-1].Timestamp
+		}
+		eb.Entries = nil
+	}
+	return stats, nil
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainStats(t *testing.T) {
+	chainID := Bytes32{1}
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("a")}},
+		[]Entry{{Content: make(Bytes, 1500)}, {Content: Bytes("bc"),
+			ExtIDs: []Bytes{Bytes("x")}}})
+	c.Store = &MemoryStore{}
+
+	stats, err := Chain{ID: chainID}.Stats(context.Background(), c)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), stats.EBlockCount)
+	assert.Equal(t, uint64(3), stats.EntryCount)
+	assert.Equal(t, uint64(1503), stats.ContentSize)
+	assert.Equal(t, uint64(3*EntryHeaderSize+1503+3), stats.TotalSize)
+	assert.Equal(t, uint64(NewChainCost+1+2+1), stats.ECSpent)
+	assert.Equal(t, map[int]uint64{1: 1, 2: 1}, stats.EntriesPerEBlock)
+	assert.Equal(t, mockDBlockTimestamp(10).Add(time.Minute),
+		stats.FirstEntry)
+	assert.Equal(t, mockDBlockTimestamp(11).Add(time.Minute),
+		stats.LastEntry)
+
+	_, err = Chain{ID: Bytes32{2}}.Stats(context.Background(), c)
+	assert.Error(t, err)
+}
@@ -0,0 +1,228 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package chaos injects faults into the requests of a factom.Client so that
+// applications can test their retry and fallback behavior.
+//
+// A Transport wraps an http.RoundTripper and, following a Plan, delays
+// requests, fails them with 5xx responses, truncates response bodies, or
+// replaces them with malformed JSON. The Plan's random decisions are drawn
+// from a source seeded by Plan.Seed, so a failing test can be reproduced
+// exactly, as long as requests are made in the same order.
+package chaos
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Fault is a kind of injected failure.
+type Fault int
+
+// Faults that may be injected.
+const (
+	// FaultNone passes the response through unmodified.
+	FaultNone Fault = iota
+
+	// FaultServerError returns a 503 Service Unavailable response
+	// without making the request.
+	FaultServerError
+
+	// FaultTruncate returns only the first half of the response body.
+	FaultTruncate
+
+	// FaultMalformed replaces the response body with malformed JSON.
+	FaultMalformed
+)
+
+// String returns the name of f.
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultServerError:
+		return "server error"
+	case FaultTruncate:
+		return "truncate"
+	case FaultMalformed:
+		return "malformed"
+	}
+	return "unknown"
+}
+
+// MalformedBody is the response body of a FaultMalformed.
+const MalformedBody = `{"jsonrpc": "2.0", "result": }`
+
+// Plan describes the faults injected by a Transport. The rates are
+// probabilities from 0 to 1, and their sum should not exceed 1.
+type Plan struct {
+	// Seed seeds the random decisions.
+	Seed int64
+
+	// Latency is added to every request, plus a random duration less
+	// than Jitter.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	ServerErrorRate float64
+	TruncateRate    float64
+	MalformedRate   float64
+}
+
+// Transport is an http.RoundTripper that injects faults according to a Plan.
+// It is safe for concurrent use.
+type Transport struct {
+	Plan Plan
+
+	// Transport makes the requests. If nil, http.DefaultTransport is
+	// used.
+	Transport http.RoundTripper
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	faults []Fault
+}
+
+// NewTransport returns a Transport that injects faults into the requests made
+// by rt according to plan.
+func NewTransport(plan Plan, rt http.RoundTripper) *Transport {
+	return &Transport{Plan: plan, Transport: rt}
+}
+
+// Use sets t as the Transport of the Factomd and Walletd clients of c. Any
+// Transport they already had is used by t to make requests, if t.Transport is
+// nil.
+func (t *Transport) Use(c *factom.Client) {
+	if t.Transport == nil {
+		t.Transport = c.Factomd.Client.Transport
+	}
+	c.Factomd.Client.Transport = t
+	c.Walletd.Client.Transport = t
+}
+
+// Faults returns the Fault injected into each request so far, in order.
+func (t *Transport) Faults() []Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Fault(nil), t.faults...)
+}
+
+// next decides the latency and Fault of the next request.
+func (t *Transport) next() (time.Duration, Fault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rand == nil {
+		t.rand = rand.New(rand.NewSource(t.Plan.Seed))
+	}
+	latency := t.Plan.Latency
+	if t.Plan.Jitter > 0 {
+		latency += time.Duration(t.rand.Int63n(int64(t.Plan.Jitter)))
+	}
+	fault := FaultNone
+	r := t.rand.Float64()
+	for _, f := range []struct {
+		Fault
+		Rate float64
+	}{
+		{FaultServerError, t.Plan.ServerErrorRate},
+		{FaultTruncate, t.Plan.TruncateRate},
+		{FaultMalformed, t.Plan.MalformedRate},
+	} {
+		if r < f.Rate {
+			fault = f.Fault
+			break
+		}
+		r -= f.Rate
+	}
+	t.faults = append(t.faults, fault)
+	return latency, fault
+}
+
+// RoundTrip makes req, injecting the next latency and Fault of the Plan.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency, fault := t.next()
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+	}
+
+	if fault == FaultServerError {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		body := []byte(http.StatusText(http.StatusServiceUnavailable))
+		return &http.Response{
+			Status:        "503 Service Unavailable",
+			StatusCode:    http.StatusServiceUnavailable,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	rt := t.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	res, err := rt.RoundTrip(req)
+	if err != nil || fault == FaultNone {
+		return res, err
+	}
+
+	var body []byte
+	switch fault {
+	case FaultTruncate:
+		data, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data[:len(data)/2]
+	case FaultMalformed:
+		res.Body.Close()
+		body = []byte(MalformedBody)
+		// The malformed body is never compressed.
+		res.Header.Del("Content-Encoding")
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Del("Content-Length")
+	return res, nil
+}
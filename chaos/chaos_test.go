@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package chaos_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/chaos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			var jReq struct{ ID json.RawMessage }
+			data, _ := ioutil.ReadAll(req.Body)
+			_ = json.Unmarshal(data, &jReq)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": jReq.ID,
+				"result": factom.Heights{DirectoryBlock: 10}})
+		}))
+}
+
+func TestTransport(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		Name  string
+		Plan  Plan
+		Fault Fault
+	}{{
+		Name:  "none",
+		Fault: FaultNone,
+	}, {
+		Name:  "server error",
+		Plan:  Plan{ServerErrorRate: 1},
+		Fault: FaultServerError,
+	}, {
+		Name:  "truncate",
+		Plan:  Plan{TruncateRate: 1},
+		Fault: FaultTruncate,
+	}, {
+		Name:  "malformed",
+		Plan:  Plan{MalformedRate: 1},
+		Fault: FaultMalformed,
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			c := factom.NewClient()
+			c.FactomdServer = srv.URL
+			tr := NewTransport(test.Plan, nil)
+			tr.Use(c)
+			var h factom.Heights
+			err := h.Get(ctx, c)
+			if test.Fault == FaultNone {
+				require.NoError(t, err)
+				assert.Equal(t, uint32(10), h.DirectoryBlock)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, []Fault{test.Fault}, tr.Faults())
+		})
+	}
+
+	t.Run("latency", func(t *testing.T) {
+		c := factom.NewClient()
+		c.FactomdServer = srv.URL
+		NewTransport(Plan{Latency: time.Second}, nil).Use(c)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		var h factom.Heights
+		assert.Error(t, h.Get(ctx, c))
+	})
+}
+
+func TestSeed(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+	plan := Plan{Seed: 42, ServerErrorRate: 0.3, TruncateRate: 0.2,
+		MalformedRate: 0.2}
+	var faults [][]Fault
+	for i := 0; i < 2; i++ {
+		c := factom.NewClient()
+		c.FactomdServer = srv.URL
+		tr := NewTransport(plan, nil)
+		tr.Use(c)
+		for j := 0; j < 20; j++ {
+			var h factom.Heights
+			_ = h.Get(context.Background(), c)
+		}
+		faults = append(faults, tr.Faults())
+	}
+	assert.Equal(t, faults[0], faults[1])
+	assert.Contains(t, faults[0], FaultNone)
+	assert.Contains(t, faults[0], FaultServerError)
+}
@@ -25,6 +25,7 @@ package factom
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/AdamSLevy/jsonrpc2/v14"
 )
@@ -39,6 +40,89 @@ type Client struct {
 	FactomdServer string
 	Walletd       jsonrpc2.Client
 	WalletdServer string
+
+	// DryRun, if not nil, intercepts all requests that would change the
+	// state of factomd or factom-walletd. See DryRun for details.
+	DryRun *DryRun
+
+	// Network, if not zero, is the network that factomd is expected to be
+	// on. See Network for details.
+	Network Network
+
+	// networkVerified is set to 1 by VerifyNetwork.
+	networkVerified uint32
+
+	// MaxResponseSize is the maximum number of bytes read from the body
+	// of any response. If zero, DefaultMaxResponseSize is used. If
+	// negative, response sizes are not limited.
+	MaxResponseSize int64
+
+	// DisableEntryValidation skips the Entry.Valid check that Entry.Create
+	// and Entry.ComposeCreate perform before submitting an Entry.
+	DisableEntryValidation bool
+
+	// SkipExistingEntries causes Entry.Create and Entry.ComposeCreate to
+	// first check whether the Entry already exists using EntryExists, and
+	// if so, return ErrorEntryExists instead of submitting it again. This
+	// makes replaying the same Entries idempotent without spending Entry
+	// Credits.
+	SkipExistingEntries bool
+
+	// Quorum, if not nil, sends factomd requests that do not change state
+	// to multiple nodes and requires them to agree. See QuorumClient for
+	// details.
+	Quorum *QuorumClient
+
+	// Clock, if not nil, is used instead of package time for commit
+	// timestamps and polling intervals, so that tests may use a
+	// FakeClock.
+	Clock Clock
+
+	// ClockSkew is added to the time of Clock for commit timestamps, to
+	// correct for a local clock that is not in sync with factomd. See
+	// SyncClockSkew.
+	ClockSkew time.Duration
+
+	// CommitWindow is how far a commit timestamp may be from the clock
+	// of factomd. If zero, DefaultCommitWindow is used. See
+	// CheckCommitTimestamp.
+	CommitWindow time.Duration
+
+	// Endpoints, if not nil, is used instead of FactomdServer to choose
+	// the factomd API for each request, failing over between the
+	// discovered URLs. See EndpointPool for details.
+	Endpoints *EndpointPool
+
+	// Limiter, if not nil, limits the number of concurrent requests made
+	// by the Client. See Limiter for details.
+	Limiter *Limiter
+
+	// TimestampCache, if not nil, caches the DBlock Timestamps looked up
+	// by HeightAtTime. See TimestampCache for details.
+	TimestampCache *TimestampCache
+
+	// ECRateCache, if not nil, caches the Entry Credit exchange rates
+	// looked up by ECRateAt. See ECRateCache for details.
+	ECRateCache *ECRateCache
+
+	// HealthPolicy, if not nil, configures the checks made by Health. See
+	// HealthPolicy for details.
+	HealthPolicy *HealthPolicy
+
+	// Store, if not nil, is read through by Entry.Get, EBlock.Get, and
+	// FBlock.Get. See Store for details.
+	Store Store
+
+	// DisableCompression stops the Client from requesting gzip compressed
+	// responses with the Accept-Encoding header. Responses that are gzip
+	// compressed are always decompressed.
+	DisableCompression bool
+
+	// CompressRequests gzip compresses the body of requests and sets the
+	// Content-Encoding header. factomd and factom-walletd do not accept
+	// compressed requests, so this is only useful behind a proxy that
+	// decompresses them.
+	CompressRequests bool
 }
 
 // Defaults for the factomd and factom-walletd endpoints.
@@ -62,20 +146,44 @@ func NewClient() *Client {
 func (c *Client) FactomdRequest(
 	ctx context.Context, method string, params, result interface{}) error {
 
+	if c.DryRun.interceptsFactomd(method) {
+		return c.DryRun.factomdRequest(ctx, c, method, params, result)
+	}
+
+	if _, ok := dryRunFactomdMethods[method]; c.Quorum != nil && !ok {
+		return c.Quorum.request(ctx, method, params, result)
+	}
+
+	return c.factomdRequest(ctx, method, params, result)
+}
+
+// factomdRequest makes a request to factomd's v2 API without any
+// interception or adaptation.
+func (c *Client) factomdRequest(
+	ctx context.Context, method string, params, result interface{}) error {
+
+	if c.Endpoints != nil {
+		return c.Endpoints.request(ctx, c, method, params, result)
+	}
+
 	url := c.FactomdServer
 	if c.Factomd.DebugRequest {
 		fmt.Println("factomd:", url)
 	}
-	return c.Factomd.Request(ctx, url, method, params, result)
+	return c.request(ctx, &c.Factomd, url, method, params, result)
 }
 
 // WalletdRequest makes a request to factom-walletd's v2 API.
 func (c *Client) WalletdRequest(
 	ctx context.Context, method string, params, result interface{}) error {
 
+	if c.DryRun.interceptsWalletd(method) {
+		return c.DryRun.walletdRequest(method, params)
+	}
+
 	url := c.WalletdServer
 	if c.Walletd.DebugRequest {
 		fmt.Println("factom-walletd:", url)
 	}
-	return c.Walletd.Request(ctx, url, method, params, result)
+	return c.request(ctx, &c.Walletd, url, method, params, result)
 }
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	clock := NewFakeClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	now := clock.After(0)
+	assert.Equal(t, start, <-now)
+
+	short := clock.After(time.Second)
+	long := clock.After(time.Minute)
+	assert.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-short)
+	select {
+	case <-long:
+		t.Fatal("fired early")
+	default:
+	}
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Hour), <-long)
+	assert.Equal(t, 0, clock.Waiters())
+}
+
+func TestWaitForConfirmationsClock(t *testing.T) {
+	c := newMockClient(t, map[string]interface{}{
+		"transaction": map[string]int64{
+			"includedindirectoryblockheight": -1,
+		},
+		"heights": Heights{DirectoryBlock: 100},
+	})
+	clock := NewFakeClock(time.Unix(1500000000, 0))
+	c.Clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.WaitForConfirmations(ctx, Bytes32{1}, 1) }()
+
+	// Each poll waits on the Clock instead of sleeping.
+	for i := 0; i < 3; i++ {
+		require.Eventually(t, func() bool { return clock.Waiters() == 1 },
+			time.Second, time.Millisecond)
+		clock.Advance(BlockPollInterval)
+	}
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 },
+		time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestClientComposeEntry(t *testing.T) {
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+	chainID := Bytes32{1}
+	e := Entry{ChainID: &chainID, Content: Bytes("hello")}
+
+	c := NewClient()
+	c.Clock = NewFakeClock(time.Unix(1500000000, 0))
+	c.ClockSkew = time.Hour
+
+	commit, _, _, err := c.ComposeEntry(&e, es)
+	require.NoError(t, err)
+	var cm Commit
+	require.NoError(t, cm.UnmarshalBinary(commit))
+	assert.Equal(t, int64(1500000000+3600), cm.Timestamp.Unix())
+}
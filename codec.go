@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ContentCodec encodes typed values into Entry Content and decodes Entry
+// Content back into typed values.
+//
+// Implementations may enforce any schema, such as a JSON schema, a protobuf
+// message, or a CBOR data definition, by returning an error from either
+// method.
+type ContentCodec interface {
+	// EncodeContent returns the Entry Content encoding v.
+	EncodeContent(v interface{}) ([]byte, error)
+
+	// DecodeContent decodes content into v, which is a pointer.
+	DecodeContent(content []byte, v interface{}) error
+}
+
+// JSONCodec is a ContentCodec that uses encoding/json.
+type JSONCodec struct {
+	// DisallowUnknownFields causes DecodeContent to return an error if the
+	// content contains object keys that do not match any field of v.
+	DisallowUnknownFields bool
+}
+
+// EncodeContent returns the JSON encoding of v.
+func (codec JSONCodec) EncodeContent(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// DecodeContent unmarshals the JSON content into v. The content must contain
+// exactly one JSON value.
+func (codec JSONCodec) DecodeContent(content []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(content))
+	if codec.DisallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if d.More() {
+		return fmt.Errorf("invalid content: trailing data")
+	}
+	return nil
+}
+
+// ContentType is the ContentCodec and Go type registered for a ChainID.
+type ContentType struct {
+	Codec ContentCodec
+	Type  reflect.Type
+}
+
+// New returns a pointer to a new zero value of t.Type.
+func (t ContentType) New() interface{} {
+	return reflect.New(t.Type).Interface()
+}
+
+// CodecRegistry maps ChainIDs to the ContentType of the Entries in the chain,
+// turning chains into typed append only logs.
+//
+// The zero value is ready to use. A CodecRegistry is safe for concurrent use.
+type CodecRegistry struct {
+	mu    sync.RWMutex
+	types map[Bytes32]ContentType
+}
+
+// Register codec for the Entries of chainID, which decode into values of the
+// same type as prototype. For example,
+//
+//	r.Register(chainID, JSONCodec{}, MyType{})
+//
+// An error is returned if a codec is already registered for chainID.
+func (r *CodecRegistry) Register(
+	chainID Bytes32, codec ContentCodec, prototype interface{}) error {
+	if codec == nil {
+		return fmt.Errorf("codec is nil")
+	}
+	if prototype == nil {
+		return fmt.Errorf("prototype is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[chainID]; ok {
+		return fmt.Errorf("chain already registered: %v", chainID)
+	}
+	if r.types == nil {
+		r.types = make(map[Bytes32]ContentType)
+	}
+	r.types[chainID] = ContentType{
+		Codec: codec,
+		Type:  reflect.TypeOf(prototype),
+	}
+	return nil
+}
+
+// Unregister removes any codec registered for chainID.
+func (r *CodecRegistry) Unregister(chainID Bytes32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.types, chainID)
+}
+
+// Lookup returns the ContentType registered for chainID, if any.
+func (r *CodecRegistry) Lookup(chainID Bytes32) (ContentType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[chainID]
+	return t, ok
+}
+
+func (r *CodecRegistry) lookup(chainID *Bytes32) (ContentType, error) {
+	if chainID == nil {
+		return ContentType{}, fmt.Errorf("ChainID is nil")
+	}
+	t, ok := r.Lookup(*chainID)
+	if !ok {
+		return ContentType{}, fmt.Errorf("no codec registered for chain: %v",
+			chainID)
+	}
+	return t, nil
+}
+
+// Decode returns a pointer to a new value decoded from the Content of e using
+// the codec registered for e.ChainID.
+func (r *CodecRegistry) Decode(e Entry) (interface{}, error) {
+	t, err := r.lookup(e.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	v := t.New()
+	if err := t.Codec.DecodeContent(e.Content, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SetContentFrom sets e.Content to the encoding of v using the codec
+// registered in r for e.ChainID.
+//
+// An error is returned if v is not of the registered type, or a pointer to it.
+func (e *Entry) SetContentFrom(r *CodecRegistry, v interface{}) error {
+	t, err := r.lookup(e.ChainID)
+	if err != nil {
+		return err
+	}
+	if typ := reflect.TypeOf(v); typ != t.Type &&
+		typ != reflect.PtrTo(t.Type) {
+		return fmt.Errorf("invalid type: %v, expected %v", typ, t.Type)
+	}
+	content, err := t.Codec.EncodeContent(v)
+	if err != nil {
+		return err
+	}
+	e.Content = content
+	e.ClearMarshalBinaryCache()
+	return nil
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCodecRegistry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var r CodecRegistry
+	chainID := NewBytes32(
+		"9dec48ff4e4bc0ba3d6b5d3fa0d2d0f2e4b6cd4d9c51bd0e8f8b5616b7f6a5e8")
+	require.NoError(r.Register(chainID, JSONCodec{
+		DisallowUnknownFields: true}, record{}))
+	assert.EqualError(r.Register(chainID, JSONCodec{}, record{}),
+		"chain already registered: "+chainID.String())
+	assert.EqualError(r.Register(Bytes32{}, nil, record{}),
+		"codec is nil")
+	assert.EqualError(r.Register(Bytes32{}, JSONCodec{}, nil),
+		"prototype is nil")
+
+	e := Entry{ChainID: &chainID}
+	require.NoError(e.SetContentFrom(&r, record{Name: "a", Count: 1}))
+	assert.Equal(`{"name":"a","count":1}`, string(e.Content))
+	require.NoError(e.SetContentFrom(&r, &record{Name: "b", Count: 2}))
+	assert.Equal(`{"name":"b","count":2}`, string(e.Content))
+	assert.EqualError(e.SetContentFrom(&r, "a"),
+		"invalid type: string, expected factom_test.record")
+
+	v, err := r.Decode(e)
+	require.NoError(err)
+	assert.Equal(&record{Name: "b", Count: 2}, v)
+
+	e.Content = Bytes(`{"name":"c","other":1}`)
+	_, err = r.Decode(e)
+	assert.EqualError(err, `json: unknown field "other"`)
+
+	e.Content = Bytes(`{"name":"c"} {}`)
+	_, err = r.Decode(e)
+	assert.EqualError(err, "invalid content: trailing data")
+
+	var unregistered Bytes32
+	e.ChainID = &unregistered
+	_, err = r.Decode(e)
+	assert.EqualError(err, "no codec registered for chain: "+
+		unregistered.String())
+	assert.Error(e.SetContentFrom(&r, record{}))
+
+	e.ChainID = nil
+	_, err = r.Decode(e)
+	assert.EqualError(err, "ChainID is nil")
+
+	r.Unregister(chainID)
+	_, ok := r.Lookup(chainID)
+	assert.False(ok)
+}
+
+func TestChainGetAllEntries(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	chainID := ComputeChainID([]Bytes{Bytes("typed log")})
+	var codecs CodecRegistry
+	require.NoError(codecs.Register(chainID, JSONCodec{}, record{}))
+
+	var entries [][]Entry
+	var count int
+	for _, n := range []int{2, 1, 3} {
+		var eb []Entry
+		for i := 0; i < n; i++ {
+			count++
+			e := Entry{ChainID: &chainID}
+			require.NoError(e.SetContentFrom(&codecs,
+				record{Name: "r", Count: count}))
+			eb = append(eb, e)
+		}
+		entries = append(entries, eb)
+	}
+	c := newMockChain(t, chainID, entries...)
+
+	typed, err := Chain{ID: chainID, Codecs: &codecs}.GetAllEntries(ctx, c)
+	require.NoError(err)
+	require.Len(typed, count)
+	for i, te := range typed {
+		assert.Equal(&record{Name: "r", Count: i + 1}, te.Value)
+		assert.Equal(chainID, *te.ChainID)
+	}
+
+	typed, err = Chain{ID: chainID}.GetAllEntries(ctx, c)
+	require.NoError(err)
+	require.Len(typed, count)
+	assert.Nil(typed[0].Value)
+	assert.Equal(`{"name":"r","count":1}`, string(typed[0].Content))
+
+	bad := ComputeChainID([]Bytes{Bytes("bad")})
+	require.NoError(codecs.Register(bad, JSONCodec{}, record{}))
+	c = newMockChain(t, bad, []Entry{{Content: Bytes("not json")}})
+	_, err = Chain{ID: bad, Codecs: &codecs}.GetAllEntries(ctx, c)
+	assert.Error(err)
+}
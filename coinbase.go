@@ -0,0 +1,314 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom/varintf"
+)
+
+// IsCoinbase returns true if tx has no FCTInputs, which is only valid for the
+// coinbase Transaction at the start of every FBlock.
+func (tx Transaction) IsCoinbase() bool {
+	return len(tx.FCTInputs) == 0
+}
+
+// Coinbase returns the coinbase Transaction of fb, if fb has one.
+func (fb FBlock) Coinbase() (Transaction, bool) {
+	if len(fb.Transactions) == 0 || !fb.Transactions[0].IsCoinbase() {
+		return Transaction{}, false
+	}
+	return fb.Transactions[0], true
+}
+
+// CoinbaseDescriptor declares the outputs of a future coinbase Transaction.
+type CoinbaseDescriptor struct {
+	Outputs []AddressAmount
+}
+
+// CoinbaseDescriptor parses e as a CoinbaseDescriptor.
+func (e ABEntry) CoinbaseDescriptor() (CoinbaseDescriptor, error) {
+	if e.Type != ABEntryCoinbaseDescriptor {
+		return CoinbaseDescriptor{}, fmt.Errorf("invalid entry type: %v",
+			e.Type)
+	}
+	body, err := abEntryBody(e.Data)
+	if err != nil {
+		return CoinbaseDescriptor{}, err
+	}
+	var cd CoinbaseDescriptor
+	for len(body) > 0 {
+		amount, read := varintf.Decode(body)
+		if read < 0 {
+			return CoinbaseDescriptor{},
+				fmt.Errorf("amount is not a valid varint")
+		}
+		body = body[read:]
+		if len(body) < 32 {
+			return CoinbaseDescriptor{},
+				fmt.Errorf("insufficient length")
+		}
+		cd.Outputs = append(cd.Outputs,
+			AddressAmount{Address: body[:32], Amount: amount})
+		body = body[32:]
+	}
+	return cd, nil
+}
+
+// ABEntry returns the Admin Block Entry for cd.
+func (cd CoinbaseDescriptor) ABEntry() ABEntry {
+	var body []byte
+	for _, output := range cd.Outputs {
+		body = append(body, varintf.Encode(output.Amount)...)
+		body = append(body, output.Address...)
+	}
+	return ABEntry{Type: ABEntryCoinbaseDescriptor,
+		Data: append(varintf.Encode(uint64(len(body))), body...)}
+}
+
+// CoinbaseDescriptorCancel cancels a single output of a CoinbaseDescriptor
+// before it is paid.
+type CoinbaseDescriptorCancel struct {
+	// DescriptorHeight is the height of the ABlock with the
+	// CoinbaseDescriptor.
+	DescriptorHeight uint32
+	// DescriptorIndex is the index of the cancelled output.
+	DescriptorIndex uint32
+}
+
+// CoinbaseDescriptorCancel parses e as a CoinbaseDescriptorCancel.
+func (e ABEntry) CoinbaseDescriptorCancel() (CoinbaseDescriptorCancel, error) {
+	if e.Type != ABEntryCoinbaseDescriptorCancel {
+		return CoinbaseDescriptorCancel{},
+			fmt.Errorf("invalid entry type: %v", e.Type)
+	}
+	body, err := abEntryBody(e.Data)
+	if err != nil {
+		return CoinbaseDescriptorCancel{}, err
+	}
+	height, read := varintf.Decode(body)
+	if read < 0 {
+		return CoinbaseDescriptorCancel{},
+			fmt.Errorf("descriptor height is not a valid varint")
+	}
+	index, read2 := varintf.Decode(body[read:])
+	if read2 < 0 {
+		return CoinbaseDescriptorCancel{},
+			fmt.Errorf("descriptor index is not a valid varint")
+	}
+	return CoinbaseDescriptorCancel{
+		DescriptorHeight: uint32(height),
+		DescriptorIndex:  uint32(index),
+	}, nil
+}
+
+// abEntryBody returns the body of the size prefixed Admin Block Entry data.
+func abEntryBody(data []byte) ([]byte, error) {
+	size, read := varintf.Decode(data)
+	if read < 0 {
+		return nil, fmt.Errorf("entry size is not a valid varint")
+	}
+	if size != uint64(len(data[read:])) {
+		return nil, fmt.Errorf("invalid entry size")
+	}
+	return data[read:], nil
+}
+
+// CoinbaseDescriptor returns the first CoinbaseDescriptor in ab, or nil if ab
+// has none.
+func (ab ABlock) CoinbaseDescriptor() (*CoinbaseDescriptor, error) {
+	for _, e := range ab.Entries {
+		if e.Type == ABEntryCoinbaseDescriptor {
+			cd, err := e.CoinbaseDescriptor()
+			if err != nil {
+				return nil, err
+			}
+			return &cd, nil
+		}
+	}
+	return nil, nil
+}
+
+// CoinbaseParams are the network parameters that schedule coinbase payouts.
+//
+// Every PayoutFrequency blocks, authority servers are paid by a
+// CoinbaseDescriptor in the ABlock at a height divisible by PayoutFrequency,
+// and grants are paid by a CoinbaseDescriptor in the following ABlock. The
+// outputs of a CoinbaseDescriptor are paid by the coinbase Transaction
+// Declaration blocks later.
+type CoinbaseParams struct {
+	// Activation is the height above which coinbase payouts occur.
+	Activation uint32
+
+	PayoutFrequency uint32
+	Declaration     uint32
+}
+
+// MainnetCoinbaseParams are the CoinbaseParams of the Factom mainnet.
+var MainnetCoinbaseParams = CoinbaseParams{
+	Activation:      140200,
+	PayoutFrequency: 25,
+	Declaration:     1000,
+}
+
+// LocalnetCoinbaseParams are the CoinbaseParams of a LOCAL factomd network.
+var LocalnetCoinbaseParams = CoinbaseParams{
+	Activation:      0,
+	PayoutFrequency: 5,
+	Declaration:     10,
+}
+
+// DescriptorHeight returns the height of the ABlock with the
+// CoinbaseDescriptor paid by the coinbase Transaction of the FBlock at
+// height. False is returned if no payouts are made at height.
+func (p CoinbaseParams) DescriptorHeight(height uint32) (uint32, bool) {
+	if p.PayoutFrequency == 0 ||
+		height <= p.Activation ||
+		height <= p.Declaration+p.PayoutFrequency {
+		return 0, false
+	}
+	if mod := height % p.PayoutFrequency; mod != 0 && mod != 1 {
+		return 0, false
+	}
+	return height - p.Declaration, true
+}
+
+// PayoutKind classifies the outputs of a coinbase Transaction.
+type PayoutKind int
+
+// Kinds of Payouts.
+const (
+	// PayoutGenesis outputs are the initial supply in the genesis FBlock.
+	PayoutGenesis PayoutKind = iota
+
+	// PayoutAuthority outputs pay the authority servers.
+	PayoutAuthority
+
+	// PayoutGrant outputs pay grants.
+	PayoutGrant
+)
+
+// String returns the name of k.
+func (k PayoutKind) String() string {
+	switch k {
+	case PayoutGenesis:
+		return "genesis"
+	case PayoutAuthority:
+		return "authority"
+	case PayoutGrant:
+		return "grant"
+	default:
+		return fmt.Sprintf("PayoutKind(%d)", int(k))
+	}
+}
+
+// Payout is a single protocol emission of new factoshis paid by a coinbase
+// Transaction.
+type Payout struct {
+	Kind    PayoutKind
+	Address FAAddress
+	Amount  uint64
+
+	// DescriptorHeight and DescriptorIndex locate the output in the
+	// CoinbaseDescriptor that declared it. They are zero for
+	// PayoutGenesis.
+	DescriptorHeight uint32
+	DescriptorIndex  int
+}
+
+// Payouts returns the classified outputs of the coinbase Transaction of fb.
+//
+// Outputs after the genesis FBlock must be declared by the CoinbaseDescriptor
+// in descriptor, which must be the ABlock at the height returned by
+// p.DescriptorHeight. The descriptor is ignored if the coinbase has no
+// outputs. Since individual outputs may be cancelled before they are paid,
+// the coinbase outputs must be an ordered subset of the descriptor outputs.
+func (p CoinbaseParams) Payouts(fb FBlock, descriptor *ABlock) ([]Payout, error) {
+	coinbase, ok := fb.Coinbase()
+	if !ok {
+		return nil, fmt.Errorf("missing coinbase")
+	}
+	if len(coinbase.FCTOutputs) == 0 {
+		return nil, nil
+	}
+
+	payouts := make([]Payout, len(coinbase.FCTOutputs))
+	if fb.Height == 0 {
+		for i, output := range coinbase.FCTOutputs {
+			payouts[i] = Payout{
+				Kind:    PayoutGenesis,
+				Address: output.FAAddress(),
+				Amount:  output.Amount,
+			}
+		}
+		return payouts, nil
+	}
+
+	height, ok := p.DescriptorHeight(fb.Height)
+	if !ok {
+		return nil, fmt.Errorf("unexpected coinbase outputs at height %v",
+			fb.Height)
+	}
+	if descriptor == nil {
+		return nil, fmt.Errorf("missing descriptor ABlock")
+	}
+	if descriptor.Height != height {
+		return nil, fmt.Errorf("descriptor ABlock height: %v, expected %v",
+			descriptor.Height, height)
+	}
+	cd, err := descriptor.CoinbaseDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	if cd == nil {
+		return nil, fmt.Errorf("missing coinbase descriptor")
+	}
+
+	kind := PayoutAuthority
+	if height%p.PayoutFrequency == 1 {
+		kind = PayoutGrant
+	}
+
+	var j int
+	for i, output := range coinbase.FCTOutputs {
+		for j < len(cd.Outputs) &&
+			(cd.Outputs[j].Amount != output.Amount ||
+				!bytes.Equal(cd.Outputs[j].Address, output.Address)) {
+			j++
+		}
+		if j == len(cd.Outputs) {
+			return nil, fmt.Errorf("coinbase output %v not declared", i)
+		}
+		payouts[i] = Payout{
+			Kind:             kind,
+			Address:          output.FAAddress(),
+			Amount:           output.Amount,
+			DescriptorHeight: height,
+			DescriptorIndex:  j,
+		}
+		j++
+	}
+	return payouts, nil
+}
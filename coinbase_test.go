@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinbaseDescriptor(t *testing.T) {
+	adr1, adr2 := Bytes32{1}, Bytes32{2}
+	outputs := []AddressAmount{
+		{Address: adr1[:], Amount: 640000000},
+		{Address: adr2[:], Amount: 1},
+	}
+	e := CoinbaseDescriptor{Outputs: outputs}.ABEntry()
+	cd, err := e.CoinbaseDescriptor()
+	require.NoError(t, err)
+	assert.Equal(t, outputs, cd.Outputs)
+
+	_, err = ABEntry{Type: ABEntryCoinbaseDescriptorCancel}.CoinbaseDescriptor()
+	assert.EqualError(t, err, "invalid entry type: 12")
+
+	e.Data = e.Data[:len(e.Data)-1]
+	_, err = e.CoinbaseDescriptor()
+	assert.EqualError(t, err, "invalid entry size")
+
+	cancel, err := ABEntry{Type: ABEntryCoinbaseDescriptorCancel,
+		Data: []byte{3, 0x87, 0x68, 2}}.CoinbaseDescriptorCancel()
+	require.NoError(t, err)
+	assert.Equal(t, CoinbaseDescriptorCancel{
+		DescriptorHeight: 1000, DescriptorIndex: 2}, cancel)
+}
+
+func TestCoinbaseParamsDescriptorHeight(t *testing.T) {
+	for _, test := range []struct {
+		Height           uint32
+		DescriptorHeight uint32
+		OK               bool
+	}{
+		{Height: 140200},
+		{Height: 150000, DescriptorHeight: 149000, OK: true},
+		{Height: 150001, DescriptorHeight: 149001, OK: true},
+		{Height: 150002},
+		{Height: 150024},
+	} {
+		height, ok := MainnetCoinbaseParams.DescriptorHeight(test.Height)
+		assert.Equal(t, test.OK, ok, test.Height)
+		assert.Equal(t, test.DescriptorHeight, height, test.Height)
+	}
+}
+
+func TestCoinbaseParamsPayouts(t *testing.T) {
+	authority := Bytes32{1}
+	grant := Bytes32{2}
+	cancelled := Bytes32{3}
+	descriptor := func(height uint32, outputs ...AddressAmount) *ABlock {
+		return &ABlock{Height: height, Entries: []ABEntry{
+			{Type: ABEntryDBSignature, Data: make([]byte, 128)},
+			CoinbaseDescriptor{Outputs: outputs}.ABEntry(),
+		}}
+	}
+	fblock := func(height uint32, outputs ...AddressAmount) FBlock {
+		return FBlock{Height: height, Transactions: []Transaction{
+			{FCTOutputs: outputs},
+			{FCTInputs: []AddressAmount{{Address: authority[:], Amount: 1}}},
+		}}
+	}
+
+	assert := assert.New(t)
+	payouts, err := MainnetCoinbaseParams.Payouts(
+		fblock(150000, AddressAmount{Address: authority[:], Amount: 10}),
+		descriptor(149000,
+			AddressAmount{Address: cancelled[:], Amount: 10},
+			AddressAmount{Address: authority[:], Amount: 10}))
+	require.NoError(t, err)
+	assert.Equal([]Payout{{
+		Kind:             PayoutAuthority,
+		Address:          FAAddress(authority),
+		Amount:           10,
+		DescriptorHeight: 149000,
+		DescriptorIndex:  1,
+	}}, payouts)
+
+	payouts, err = MainnetCoinbaseParams.Payouts(
+		fblock(150001, AddressAmount{Address: grant[:], Amount: 7}),
+		descriptor(149001, AddressAmount{Address: grant[:], Amount: 7}))
+	require.NoError(t, err)
+	require.Len(t, payouts, 1)
+	assert.Equal(PayoutGrant, payouts[0].Kind)
+	assert.Equal("grant", payouts[0].Kind.String())
+
+	payouts, err = MainnetCoinbaseParams.Payouts(
+		fblock(0, AddressAmount{Address: grant[:], Amount: 7}), nil)
+	require.NoError(t, err)
+	require.Len(t, payouts, 1)
+	assert.Equal(PayoutGenesis, payouts[0].Kind)
+
+	payouts, err = MainnetCoinbaseParams.Payouts(fblock(150002), nil)
+	assert.NoError(err)
+	assert.Nil(payouts)
+
+	for _, test := range []struct {
+		Name       string
+		FBlock     FBlock
+		Descriptor *ABlock
+		Error      string
+	}{{
+		Name:   "missing coinbase",
+		FBlock: FBlock{Height: 150000},
+		Error:  "missing coinbase",
+	}, {
+		Name: "unexpected height",
+		FBlock: fblock(150002,
+			AddressAmount{Address: grant[:], Amount: 7}),
+		Error: "unexpected coinbase outputs at height 150002",
+	}, {
+		Name: "missing descriptor ABlock",
+		FBlock: fblock(150000,
+			AddressAmount{Address: grant[:], Amount: 7}),
+		Error: "missing descriptor ABlock",
+	}, {
+		Name: "descriptor height",
+		FBlock: fblock(150000,
+			AddressAmount{Address: grant[:], Amount: 7}),
+		Descriptor: descriptor(149001),
+		Error:      "descriptor ABlock height: 149001, expected 149000",
+	}, {
+		Name: "missing coinbase descriptor",
+		FBlock: fblock(150000,
+			AddressAmount{Address: grant[:], Amount: 7}),
+		Descriptor: &ABlock{Height: 149000},
+		Error:      "missing coinbase descriptor",
+	}, {
+		Name: "not declared",
+		FBlock: fblock(150000,
+			AddressAmount{Address: grant[:], Amount: 7}),
+		Descriptor: descriptor(149000,
+			AddressAmount{Address: grant[:], Amount: 8}),
+		Error: "coinbase output 0 not declared",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := MainnetCoinbaseParams.Payouts(
+				test.FBlock, test.Descriptor)
+			require.EqualError(t, err, test.Error)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"time"
+)
+
+// Commit is a parsed Entry or Chain commit, as found in an ECBlock or as
+// produced by GenerateCommit.
+//
+// UnmarshalBinary does not reject commits with an invalid signature or EC
+// cost, so that malformed commits may be inspected. Instead, ValidSignature
+// and ValidCost report whether the commit would be accepted, and Valid
+// returns the reason a commit is invalid.
+type Commit struct {
+	// TxID is the Entry Transaction ID, the sha256 of the signed data.
+	TxID TxID
+
+	// Timestamp is the timestamp salt of the commit, with millisecond
+	// precision.
+	Timestamp time.Time
+
+	// ChainIDHash and Weld are only populated for Chain commits.
+	ChainIDHash *Bytes32
+	Weld        *Bytes32
+
+	EntryHash EntryHash
+	Cost      uint8
+
+	// ECAddress is the public key of the Entry Credit address that paid
+	// for the commit.
+	ECAddress ECAddress
+	Signature Bytes
+
+	// ValidSignature is true if Signature is a valid signature of the
+	// commit by ECAddress.
+	ValidSignature bool
+
+	// ValidCost is true if Cost is within the range allowed for the type
+	// of commit.
+	ValidCost bool
+}
+
+// NewChain returns true if cmt is a Chain commit.
+func (cmt Commit) NewChain() bool {
+	return cmt.ChainIDHash != nil
+}
+
+// Valid returns an error if cmt does not have a valid signature or EC cost.
+func (cmt Commit) Valid() error {
+	if !cmt.ValidCost {
+		return fmt.Errorf("invalid EC cost")
+	}
+	if !cmt.ValidSignature {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// UnmarshalBinary parses an Entry or Chain commit, which is determined by the
+// length of data, and verifies its signature and EC cost. See
+// GenerateCommit for the data format.
+//
+// An error is only returned if data is not the length of a commit or does not
+// have a valid version byte.
+func (cmt *Commit) UnmarshalBinary(data []byte) error {
+	signed, err := cmt.unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	cmt.ValidSignature = signed.Verify()
+	return nil
+}
+
+// unmarshalBinary is UnmarshalBinary without verifying the signature, which is
+// returned so that the caller may verify it and set cmt.ValidSignature.
+func (cmt *Commit) unmarshalBinary(data []byte) (Signed, error) {
+	var newChain bool
+	switch len(data) {
+	case EntryCommitSize:
+	case ChainCommitSize:
+		newChain = true
+	default:
+		return Signed{}, fmt.Errorf("invalid commit length")
+	}
+	if data[0] != 0x00 {
+		return Signed{}, fmt.Errorf("invalid version byte")
+	}
+
+	i := 1 // Skip version byte.
+	cmt.Timestamp = DecodeTimestamp(data[i:])
+	i += TimestampSize
+
+	cmt.ChainIDHash, cmt.Weld = nil, nil
+	if newChain {
+		cmt.ChainIDHash, cmt.Weld = new(Bytes32), new(Bytes32)
+		i += copy(cmt.ChainIDHash[:], data[i:])
+		i += copy(cmt.Weld[:], data[i:])
+	}
+	i += copy(cmt.EntryHash[:], data[i:])
+
+	cmt.Cost = data[i]
+	minCost, maxCost := uint8(1), uint8(EntryMaxDataSize/1024)
+	if newChain {
+		minCost += NewChainCost
+		maxCost += NewChainCost
+	}
+	cmt.ValidCost = minCost <= cmt.Cost && cmt.Cost <= maxCost
+	i++
+
+	signed := data[:i]
+	cmt.TxID = sha256Sum(signed)
+
+	i += copy(cmt.ECAddress[:], data[i:])
+	cmt.Signature = append(Bytes(nil), data[i:]...)
+	cmt.ValidSignature = false
+
+	return Signed{PublicKey: cmt.ECAddress.PublicKey(), Message: signed,
+		Signature: cmt.Signature}, nil
+}
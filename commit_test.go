@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommit(t *testing.T) {
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+
+	for _, newChain := range []bool{false, true} {
+		e := Entry{ExtIDs: []Bytes{Bytes("commit")}, Content: Bytes("test")}
+		name := "entry"
+		if newChain {
+			name = "chain"
+		} else {
+			chainID := ComputeChainID(e.ExtIDs)
+			e.ChainID = &chainID
+		}
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			data, _, txID, err := e.Compose(es)
+			require.NoError(t, err)
+
+			var cmt Commit
+			require.NoError(t, cmt.UnmarshalBinary(data))
+			assert.Equal(txID, cmt.TxID)
+			assert.Equal(*e.Hash, cmt.EntryHash)
+			assert.Equal(es.ECAddress(), cmt.ECAddress)
+			assert.Equal(newChain, cmt.NewChain())
+			assert.True(cmt.ValidSignature)
+			assert.True(cmt.ValidCost)
+			assert.NoError(cmt.Valid())
+			if newChain {
+				assert.Equal(Bytes32(sha256d(e.ChainID[:])),
+					*cmt.ChainIDHash)
+			}
+
+			data[len(data)-1] ^= 1
+			require.NoError(t, cmt.UnmarshalBinary(data))
+			assert.False(cmt.ValidSignature)
+			assert.EqualError(cmt.Valid(), "invalid signature")
+
+			data[len(data)-96-1] = 0
+			require.NoError(t, cmt.UnmarshalBinary(data))
+			assert.False(cmt.ValidCost)
+			assert.EqualError(cmt.Valid(), "invalid EC cost")
+
+			data[0] = 1
+			assert.EqualError(cmt.UnmarshalBinary(data),
+				"invalid version byte")
+			assert.EqualError(cmt.UnmarshalBinary(data[1:]),
+				"invalid commit length")
+		})
+	}
+}
+
+func sha256d(data []byte) [32]byte {
+	hash := sha256.Sum256(data)
+	return sha256.Sum256(hash[:])
+}
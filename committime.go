@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultCommitWindow is the Client.CommitWindow used if zero. factomd rejects
+// commits whose timestamp is more than this before or after its own clock.
+const DefaultCommitWindow = 12 * time.Hour
+
+// ErrorCommitTimestamp is returned by Client.CheckCommitTimestamp for a commit
+// timestamp that factomd would reject, or will soon reject.
+type ErrorCommitTimestamp struct {
+	Timestamp time.Time
+
+	// Now is the current time, corrected by Client.ClockSkew.
+	Now time.Time
+
+	// Expired is true if factomd would reject the commit now. Otherwise,
+	// the commit is only about to expire.
+	Expired bool
+}
+
+// Error implements error.
+func (err ErrorCommitTimestamp) Error() string {
+	if err.Expired {
+		return fmt.Sprintf("commit timestamp %v outside window at %v",
+			err.Timestamp, err.Now)
+	}
+	return fmt.Sprintf("commit timestamp %v about to expire at %v",
+		err.Timestamp, err.Now)
+}
+
+// commitTime returns the time to use for new commit timestamps, which is the
+// time of c.Clock corrected by c.ClockSkew.
+func (c *Client) commitTime() time.Time {
+	return c.clock().Now().Add(c.ClockSkew)
+}
+
+func (c *Client) commitWindow() time.Duration {
+	if c.CommitWindow == 0 {
+		return DefaultCommitWindow
+	}
+	return c.CommitWindow
+}
+
+// CheckCommitTimestamp returns an ErrorCommitTimestamp if a commit with the
+// timestamp ts is outside of c.CommitWindow, or will be within margin, so
+// that callers holding composed commits, such as in a queue, may be warned
+// and re-compose them before they are rejected.
+func (c *Client) CheckCommitTimestamp(ts time.Time, margin time.Duration) error {
+	now := c.commitTime()
+	window := c.commitWindow()
+	err := ErrorCommitTimestamp{Timestamp: ts, Now: now}
+	if ts.Before(now.Add(-window)) || ts.After(now.Add(window)) {
+		err.Expired = true
+		return err
+	}
+	if ts.Before(now.Add(margin - window)) {
+		return err
+	}
+	return nil
+}
+
+// SyncClockSkew queries the current time of factomd and sets c.ClockSkew to
+// its offset from the local Clock, so that commit timestamps are corrected
+// for any skew of the local clock. Half of the round trip time of the request
+// is assumed to have elapsed before factomd read its clock. The new
+// ClockSkew is returned.
+func (c *Client) SyncClockSkew(ctx context.Context) (time.Duration, error) {
+	start := c.clock().Now()
+	var cm CurrentMinute
+	if err := cm.Get(ctx, c); err != nil {
+		return 0, err
+	}
+	if cm.CurrentTime <= 0 {
+		return 0, fmt.Errorf("factomd did not report its current time")
+	}
+	end := c.clock().Now()
+	local := start.Add(end.Sub(start) / 2)
+	c.ClockSkew = time.Unix(0, cm.CurrentTime).Sub(local)
+	return c.ClockSkew, nil
+}
+
+// ComposeAt is like Compose but uses ts as the commit timestamp, instead of
+// the current time. The timestamp must be within the CommitWindow of the
+// factomd clock when the commit is submitted.
+func (e *Entry) ComposeAt(es EsAddress, ts time.Time) (
+	commit []byte, reveal []byte, txID TxID, err error) {
+	return e.compose(es, ts)
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncClockSkew(t *testing.T) {
+	local := time.Unix(1500000000, 0)
+	node := local.Add(90 * time.Second)
+	c := newMockClient(t, map[string]interface{}{
+		"current-minute": CurrentMinute{CurrentTime: node.UnixNano()},
+	})
+	c.Clock = NewFakeClock(local)
+
+	skew, err := c.SyncClockSkew(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, skew)
+	assert.Equal(t, skew, c.ClockSkew)
+
+	c = newMockClient(t, map[string]interface{}{
+		"current-minute": CurrentMinute{},
+	})
+	_, err = c.SyncClockSkew(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCheckCommitTimestamp(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	c := NewClient()
+	c.Clock = NewFakeClock(now)
+	c.ClockSkew = time.Hour
+	now = now.Add(c.ClockSkew)
+
+	assert.NoError(t, c.CheckCommitTimestamp(now, time.Hour))
+	assert.NoError(t, c.CheckCommitTimestamp(
+		now.Add(-10*time.Hour), time.Hour))
+
+	err := c.CheckCommitTimestamp(now.Add(-11*time.Hour-time.Minute), time.Hour)
+	var tsErr ErrorCommitTimestamp
+	require.True(t, errors.As(err, &tsErr))
+	assert.False(t, tsErr.Expired)
+	assert.Equal(t, now, tsErr.Now)
+
+	err = c.CheckCommitTimestamp(now.Add(-13*time.Hour), time.Hour)
+	require.True(t, errors.As(err, &tsErr))
+	assert.True(t, tsErr.Expired)
+
+	err = c.CheckCommitTimestamp(now.Add(13*time.Hour), 0)
+	require.True(t, errors.As(err, &tsErr))
+	assert.True(t, tsErr.Expired)
+
+	c.CommitWindow = 24 * time.Hour
+	assert.NoError(t, c.CheckCommitTimestamp(now.Add(-13*time.Hour), time.Hour))
+}
+
+func TestEntryComposeAt(t *testing.T) {
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+	chainID := Bytes32{1}
+	e := Entry{ChainID: &chainID, Content: Bytes("hello")}
+	ts := time.Unix(1500000000, 0)
+
+	commit, _, _, err := e.ComposeAt(es, ts)
+	require.NoError(t, err)
+	var cm Commit
+	require.NoError(t, cm.UnmarshalBinary(commit))
+	assert.Equal(t, ts.Unix(), cm.Timestamp.Unix())
+}
@@ -23,107 +23,158 @@
 package factom
 
 import (
-	"crypto/sha256"
-	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
 
-	merkle "github.com/AdamSLevy/go-merkle"
+	"github.com/Factom-Asset-Tokens/factom/merkle"
 )
 
 // ComputeDBlockHeaderHash returns sha256(data[:DBlockHeaderSize]).
 func ComputeDBlockHeaderHash(data []byte) Bytes32 {
-	return sha256.Sum256(data[:DBlockHeaderSize])
+	return sha256Sum(data[:DBlockHeaderSize])
 }
 
 // ComputeEBlockHeaderHash returns sha256(data[:EBlockHeaderSize]).
 func ComputeEBlockHeaderHash(data []byte) Bytes32 {
-	return sha256.Sum256(data[:EBlockHeaderSize])
+	return sha256Sum(data[:EBlockHeaderSize])
 }
 
-// ComputeFBlockKeyMR returns the Key Merkle root of the FBlock.
-func ComputeFBlockKeyMR(elements [][]byte) (Bytes32, error) {
-	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{DoubleOddNodes: true, DisableHashLeaves: true})
-	if err := tree.Generate(elements, sha256.New()); err != nil {
-		return Bytes32{}, err
+// computeMerkleRoot returns the merkle root of the tree created with elements
+// as leaves. If hashLeaves is false, each element must be 32 bytes.
+func computeMerkleRoot(elements [][]byte, hashLeaves bool) (Bytes32, error) {
+	if len(elements) == 0 {
+		return Bytes32{}, fmt.Errorf("empty tree")
+	}
+	b := merkle.Builder{Hasher: merkleHasher}
+	for _, element := range elements {
+		if hashLeaves {
+			b.Add(merkleHasher.HashLeaf(element))
+			continue
+		}
+		var leaf [32]byte
+		if len(element) != len(leaf) {
+			return Bytes32{}, fmt.Errorf("invalid leaf length: %v",
+				len(element))
+		}
+		copy(leaf[:], element)
+		b.Add(leaf)
 	}
-	root := tree.Root()
-	var keyMR Bytes32
-	copy(keyMR[:], root.Hash)
-	return keyMR, nil
+	return b.Root(), nil
+}
+
+// ComputeFBlockKeyMR returns the Key Merkle root of the FBlock.
+func ComputeFBlockKeyMR(elements [][]byte) (KeyMR, error) {
+	keyMR, err := computeMerkleRoot(elements, false)
+	return KeyMR(keyMR), err
 }
 
 // ComputeFBlockBodyMR returns the merkle root of the tree created with
 // elements as leaves, where the leaves are hashed.
 func ComputeFBlockBodyMR(elements [][]byte) (Bytes32, error) {
-	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{DoubleOddNodes: true})
-	if err := tree.Generate(elements, sha256.New()); err != nil {
-		return Bytes32{}, err
-	}
-	root := tree.Root()
-	var bodyMR Bytes32
-	copy(bodyMR[:], root.Hash)
-	return bodyMR, nil
+	return computeMerkleRoot(elements, true)
 }
 
 // ComputeDBlockBodyMR returns the merkle root of the tree created with
 // elements as leaves, where the leaves are hashed.
 func ComputeDBlockBodyMR(elements [][]byte) (Bytes32, error) {
-	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{DoubleOddNodes: true})
-	if err := tree.Generate(elements, sha256.New()); err != nil {
-		return Bytes32{}, err
-	}
-	root := tree.Root()
-	var bodyMR Bytes32
-	copy(bodyMR[:], root.Hash)
-	return bodyMR, nil
+	return computeMerkleRoot(elements, true)
 }
 
 // ComputeEBlockBodyMR returns the merkle root of the tree created with
 // elements as leaves, where the leaves are not hashed.
 func ComputeEBlockBodyMR(elements [][]byte) (Bytes32, error) {
-	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{
-		DoubleOddNodes:    true,
-		DisableHashLeaves: true})
-	if err := tree.Generate(elements, sha256.New()); err != nil {
-		return Bytes32{}, err
-	}
-	root := tree.Root()
-	var bodyMR Bytes32
-	copy(bodyMR[:], root.Hash)
-	return bodyMR, nil
+	return computeMerkleRoot(elements, false)
+}
+
+// EBlockKeyMRBuilder computes the BodyMR and KeyMR of an EBlock as its Entry
+// Hashes and minute markers are added, without holding them all in memory.
+type EBlockKeyMRBuilder struct {
+	// Header Fields
+	ChainID      Bytes32
+	PrevKeyMR    KeyMR
+	PrevFullHash Bytes32
+	Sequence     uint32
+	Height       uint32
+
+	body merkle.Builder
+}
+
+// tree returns the body Merkle tree, which uses merkleHasher.
+func (b *EBlockKeyMRBuilder) tree() *merkle.Builder {
+	b.body.Hasher = merkleHasher
+	return &b.body
+}
+
+// AddEntry adds the next Entry Hash of the EBlock.
+func (b *EBlockKeyMRBuilder) AddEntry(hash EntryHash) {
+	b.tree().Add(hash)
+}
+
+// AddMinuteMarker adds the marker that ends the Entries of minute, from 1 to
+// 10.
+func (b *EBlockKeyMRBuilder) AddMinuteMarker(minute int) {
+	var marker [32]byte
+	marker[len(marker)-1] = byte(minute)
+	b.tree().Add(marker)
+}
+
+// ObjectCount returns the number of Entry Hashes and minute markers added.
+func (b *EBlockKeyMRBuilder) ObjectCount() uint32 {
+	return uint32(b.body.Len())
+}
+
+// BodyMR returns the BodyMR of the objects added so far.
+func (b *EBlockKeyMRBuilder) BodyMR() Bytes32 {
+	return b.tree().Root()
+}
+
+// KeyMR returns the KeyMR of the EBlock with the header fields of b and the
+// objects added so far.
+func (b *EBlockKeyMRBuilder) KeyMR() KeyMR {
+	bodyMR := b.BodyMR()
+	header := make([]byte, EBlockHeaderSize)
+	i := copy(header, b.ChainID[:])
+	i += copy(header[i:], bodyMR[:])
+	i += copy(header[i:], b.PrevKeyMR[:])
+	i += copy(header[i:], b.PrevFullHash[:])
+	binary.BigEndian.PutUint32(header[i:], b.Sequence)
+	i += 4
+	binary.BigEndian.PutUint32(header[i:], b.Height)
+	i += 4
+	binary.BigEndian.PutUint32(header[i:], b.ObjectCount())
+	headerHash := ComputeEBlockHeaderHash(header)
+	return ComputeKeyMR(&headerHash, &bodyMR)
 }
 
 // ComputeFullHash returns sha256(data).
 func ComputeFullHash(data []byte) Bytes32 {
-	return sha256.Sum256(data)
+	return sha256Sum(data)
 }
 
 // ComputeKeyMR returns sha256(headerHash|bodyMR).
-func ComputeKeyMR(headerHash, bodyMR *Bytes32) Bytes32 {
+func ComputeKeyMR(headerHash, bodyMR *Bytes32) KeyMR {
 	data := make([]byte, len(headerHash)+len(bodyMR))
 	i := copy(data, headerHash[:])
 	copy(data[i:], bodyMR[:])
-	return sha256.Sum256(data)
+	return sha256Sum(data)
 }
 
 // ComputeChainID returns the chain ID for a set of NameIDs.
 func ComputeChainID(nameIDs []Bytes) Bytes32 {
-	hash := sha256.New()
+	sums := make([]byte, 0, len(nameIDs)*len(Bytes32{}))
 	for _, id := range nameIDs {
-		idSum := sha256.Sum256(id)
-		hash.Write(idSum[:])
+		idSum := sha256Sum(id)
+		sums = append(sums, idSum[:]...)
 	}
-	c := hash.Sum(nil)
-	var chainID Bytes32
-	copy(chainID[:], c)
-	return chainID
+	return sha256Sum(sums)
 }
 
 // ComputeEntryHash returns the Entry hash of data. Entry's are hashed via:
 // sha256(sha512(data) + data).
-func ComputeEntryHash(data []byte) Bytes32 {
-	sum := sha512.Sum512(data)
+func ComputeEntryHash(data []byte) EntryHash {
+	sum := sha512Sum(data)
 	saltedSum := make([]byte, len(sum)+len(data))
 	i := copy(saltedSum, sum[:])
 	copy(saltedSum[i:], data)
-	return sha256.Sum256(saltedSum)
+	return sha256Sum(saltedSum)
 }
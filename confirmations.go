@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import "context"
+
+// Confirmations returns the number of DBlocks that have been saved by factomd
+// since the Factoid Transaction, EC commit, or Entry with the given hash was
+// included, counting the including DBlock as the first confirmation. Zero is
+// returned if hash is pending or unknown.
+func (c *Client) Confirmations(ctx context.Context, hash Bytes32) (uint32, error) {
+	params := struct {
+		Hash Bytes32 `json:"hash"`
+	}{Hash: hash}
+	var result struct {
+		Height int64 `json:"includedindirectoryblockheight"`
+	}
+	if err := c.FactomdRequest(ctx, "transaction", params, &result); err != nil {
+		return 0, err
+	}
+	if result.Height < 0 {
+		return 0, nil
+	}
+
+	var heights Heights
+	if err := heights.Get(ctx, c); err != nil {
+		return 0, err
+	}
+	if uint64(result.Height) > uint64(heights.DirectoryBlock) {
+		return 0, nil
+	}
+	return heights.DirectoryBlock - uint32(result.Height) + 1, nil
+}
+
+// WaitForConfirmations blocks until hash has at least n Confirmations, or ctx
+// is done. Confirmations are polled every BlockPollInterval.
+func (c *Client) WaitForConfirmations(ctx context.Context,
+	hash Bytes32, n uint32) error {
+	for {
+		confirmations, err := c.Confirmations(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if confirmations >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock().After(BlockPollInterval):
+		}
+	}
+}
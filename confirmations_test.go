@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmations(t *testing.T) {
+	hash := Bytes32{1}
+	for _, test := range []struct {
+		Name          string
+		Height        int64
+		Confirmations uint32
+	}{{
+		Name:   "pending",
+		Height: -1,
+	}, {
+		Name:          "latest",
+		Height:        100,
+		Confirmations: 1,
+	}, {
+		Name:          "buried",
+		Height:        95,
+		Confirmations: 6,
+	}, {
+		Name:   "ahead of node",
+		Height: 101,
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			c := newMockClient(t, map[string]interface{}{
+				"transaction": map[string]int64{
+					"includedindirectoryblockheight": test.Height,
+				},
+				"heights": Heights{DirectoryBlock: 100},
+			})
+			confirmations, err := c.Confirmations(
+				context.Background(), hash)
+			require.NoError(t, err)
+			assert.Equal(test.Confirmations, confirmations)
+
+			err = c.WaitForConfirmations(
+				context.Background(), hash, test.Confirmations)
+			assert.NoError(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(),
+				10*time.Millisecond)
+			defer cancel()
+			err = c.WaitForConfirmations(
+				ctx, hash, test.Confirmations+1)
+			assert.Equal(context.DeadlineExceeded, err)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+// CursorStore durably records the positions of Cursors by name. A position is
+// one more than the last acknowledged height, so that zero means that nothing
+// has been acknowledged. MemoryStore implements CursorStore.
+type CursorStore interface {
+	// GetCursor returns the position stored for name, or 0 if none is
+	// stored.
+	GetCursor(name string) (uint32, error)
+
+	// PutCursor stores the position for name.
+	PutCursor(name string, pos uint32) error
+}
+
+// Cursor records the DBlock height up to which a consumer has processed
+// blocks, so that processing may resume after a restart with at-least-once
+// guarantees.
+//
+// A consumer calls Ack once it has durably processed a height, and Start to
+// find the height to resume from. Start replays the last Replay acknowledged
+// heights, for consumers whose processing of recent blocks may be lost, or
+// that must re-read recent blocks until they are final. Rewind explicitly
+// moves the Cursor back to reprocess blocks.
+//
+// A Cursor does not lock its Store, so each Name must only be used by one
+// consumer at a time.
+type Cursor struct {
+	Store CursorStore
+	Name  string
+
+	// Replay is the number of acknowledged heights that Start replays.
+	Replay uint32
+}
+
+// Acked returns the last acknowledged height. False is returned if no height
+// has been acknowledged.
+func (cur Cursor) Acked() (uint32, bool, error) {
+	pos, err := cur.Store.GetCursor(cur.Name)
+	if err != nil || pos == 0 {
+		return 0, false, err
+	}
+	return pos - 1, true, nil
+}
+
+// Ack marks all heights up to and including height as processed. Heights
+// below the last acknowledged height are ignored, so Ack never moves the
+// Cursor back. Use Rewind to move the Cursor back.
+func (cur Cursor) Ack(height uint32) error {
+	pos, err := cur.Store.GetCursor(cur.Name)
+	if err != nil {
+		return err
+	}
+	if height < pos {
+		return nil
+	}
+	return cur.Store.PutCursor(cur.Name, height+1)
+}
+
+// Rewind moves the Cursor back by n heights, so that they are processed
+// again. Rewinding past the first height leaves nothing acknowledged.
+func (cur Cursor) Rewind(n uint32) error {
+	pos, err := cur.Store.GetCursor(cur.Name)
+	if err != nil {
+		return err
+	}
+	if n > pos {
+		n = pos
+	}
+	return cur.Store.PutCursor(cur.Name, pos-n)
+}
+
+// Start returns the height to resume processing from, which is one more than
+// the last acknowledged height, less Replay. If no height has been
+// acknowledged, initial is returned.
+func (cur Cursor) Start(initial uint32) (uint32, error) {
+	pos, err := cur.Store.GetCursor(cur.Name)
+	if err != nil {
+		return 0, err
+	}
+	if pos == 0 {
+		return initial, nil
+	}
+	if cur.Replay > pos {
+		return 0, nil
+	}
+	return pos - cur.Replay, nil
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cur := Cursor{Store: new(MemoryStore), Name: "test", Replay: 3}
+	_, ok, err := cur.Acked()
+	require.NoError(err)
+	assert.False(ok)
+	start, err := cur.Start(100)
+	require.NoError(err)
+	assert.Equal(uint32(100), start)
+
+	require.NoError(cur.Ack(0))
+	acked, ok, err := cur.Acked()
+	require.NoError(err)
+	assert.True(ok)
+	assert.Equal(uint32(0), acked)
+	// The replay window cannot precede the first height.
+	start, err = cur.Start(100)
+	require.NoError(err)
+	assert.Equal(uint32(0), start)
+
+	require.NoError(cur.Ack(110))
+	// Ack never moves the Cursor back.
+	require.NoError(cur.Ack(105))
+	acked, _, err = cur.Acked()
+	require.NoError(err)
+	assert.Equal(uint32(110), acked)
+	start, err = cur.Start(100)
+	require.NoError(err)
+	assert.Equal(uint32(108), start)
+
+	require.NoError(cur.Rewind(5))
+	acked, _, err = cur.Acked()
+	require.NoError(err)
+	assert.Equal(uint32(105), acked)
+
+	// Cursors are independent by Name.
+	other := Cursor{Store: cur.Store, Name: "other"}
+	_, ok, err = other.Acked()
+	require.NoError(err)
+	assert.False(ok)
+
+	require.NoError(cur.Rewind(200))
+	_, ok, err = cur.Acked()
+	require.NoError(err)
+	assert.False(ok)
+}
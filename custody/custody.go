@@ -0,0 +1,394 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package custody records the chain of custody of an asset, such as a physical
+// shipment or a set of evidence, as custody transfers between identities that
+// are signed by both parties.
+//
+// An asset chain is created by an Entry with the ExtIDs
+//
+//	["AssetChain", <asset hash (32 bytes)>, names...]
+//
+// and the Content
+//
+//	{"version":1,"custodian":"<identity ChainID>"}
+//
+// where the asset hash identifies the asset, e.g. the hash of its serial
+// number or of its documents, and the custodian is the identity chain of the
+// initial custodian.
+//
+// Custody is transferred by an Entry with the ExtIDs
+//
+//	["CustodyTransfer", <sequence>, <from ChainID>, <to ChainID>,
+//		<asset hash>, <from RCD>, <from signature>, <to RCD>, <to signature>]
+//
+// and an optional free form note as the Content. The sequence is the decimal
+// number of the transfer, starting at 1, and the ChainIDs and asset hash are
+// 32 bytes. Both parties sign the hex encoded asset ChainID concatenated with
+// the sequence, ChainIDs, and asset hash, as they appear in the ExtIDs, and
+// the SHA-256 hash of the Content. The from identity must be the current
+// custodian, so a transfer cannot be replayed or made by any other party.
+//
+// Identities are not resolved by this package. A KeyChecker, such as one
+// returned by IdentityKeys, decides whether a key may sign for an identity.
+//
+// Anyone may write to the chain, so Resolve ignores all invalid Entries.
+package custody
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+)
+
+var (
+	// ChainTag is the first ExtID of the first Entry of an asset chain.
+	ChainTag = factom.Bytes("AssetChain")
+
+	// TransferTag is the first ExtID of a custody transfer Entry.
+	TransferTag = factom.Bytes("CustodyTransfer")
+)
+
+// Version is the only supported version of the asset chain Content.
+const Version = 1
+
+// KeyChecker returns true if key may sign for the identity with the ChainID
+// identity.
+type KeyChecker func(identity factom.Bytes32, key factom.ID1Key) bool
+
+// IdentityKeys returns a KeyChecker that accepts the active keys of ids.
+func IdentityKeys(ids ...appidentity.Identity) KeyChecker {
+	keys := make(map[factom.Bytes32]appidentity.Identity, len(ids))
+	for _, id := range ids {
+		keys[id.ChainID] = id
+	}
+	return func(identity factom.Bytes32, key factom.ID1Key) bool {
+		id, ok := keys[identity]
+		return ok && id.Priority(key) >= 0
+	}
+}
+
+// Asset is the custody history of an asset.
+type Asset struct {
+	ChainID   factom.Bytes32
+	AssetHash factom.Bytes32
+	Names     []factom.Bytes
+
+	// Custodian is the ChainID of the identity with custody of the asset.
+	Custodian factom.Bytes32
+
+	// Transfers are the valid custody transfers, in order.
+	Transfers []Transfer
+}
+
+// Transfer is a custody transfer.
+type Transfer struct {
+	Sequence uint64
+	From, To factom.Bytes32
+	Note     factom.Bytes
+
+	// FromKey and ToKey are the keys that signed for From and To.
+	FromKey, ToKey factom.ID1Key
+
+	// EntryHash and Timestamp are those of the transfer Entry, if known.
+	EntryHash *factom.EntryHash
+	Timestamp time.Time
+}
+
+type assetContent struct {
+	Version   int            `json:"version"`
+	Custodian factom.Bytes32 `json:"custodian"`
+}
+
+// New returns a new Asset in the custody of custodian, along with the first
+// Entry of its chain, which must be submitted to create the chain.
+func New(assetHash, custodian factom.Bytes32, names ...factom.Bytes) (
+	Asset, factom.Entry, error) {
+	content, err := json.Marshal(assetContent{Version: Version,
+		Custodian: custodian})
+	if err != nil {
+		return Asset{}, factom.Entry{}, err
+	}
+	extIDs := append([]factom.Bytes{ChainTag, assetHash[:]}, names...)
+	chainID := factom.ComputeChainID(extIDs)
+	e := factom.Entry{ChainID: &chainID, ExtIDs: extIDs, Content: content}
+	a, err := parseFirst(e)
+	if err != nil {
+		return Asset{}, factom.Entry{}, err
+	}
+	return a, e, nil
+}
+
+// parseFirst parses the first Entry of an asset chain.
+func parseFirst(e factom.Entry) (Asset, error) {
+	if len(e.ExtIDs) < 2 || !bytes.Equal(e.ExtIDs[0], ChainTag) {
+		return Asset{}, fmt.Errorf("first entry: missing tag")
+	}
+	if e.ChainID == nil || *e.ChainID != factom.ComputeChainID(e.ExtIDs) {
+		return Asset{}, fmt.Errorf("first entry: invalid ChainID")
+	}
+	var a Asset
+	if len(e.ExtIDs[1]) != len(a.AssetHash) {
+		return Asset{}, fmt.Errorf("first entry: invalid asset hash length")
+	}
+	var content assetContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return Asset{}, fmt.Errorf("first entry: invalid content: %w", err)
+	}
+	if content.Version != Version {
+		return Asset{}, fmt.Errorf("first entry: unsupported version: %v",
+			content.Version)
+	}
+	if content.Custodian.IsZero() {
+		return Asset{}, fmt.Errorf("first entry: missing custodian")
+	}
+	a.ChainID = *e.ChainID
+	copy(a.AssetHash[:], e.ExtIDs[1])
+	a.Names = e.ExtIDs[2:]
+	a.Custodian = content.Custodian
+	return a, nil
+}
+
+// Proposal is a custody transfer that is signed in turn by both parties,
+// before its Entry is submitted. A Proposal may be passed between the parties
+// as JSON.
+type Proposal struct {
+	ChainID   factom.Bytes32 `json:"chainid"`
+	AssetHash factom.Bytes32 `json:"assethash"`
+	Sequence  uint64         `json:"sequence"`
+	From      factom.Bytes32 `json:"from"`
+	To        factom.Bytes32 `json:"to"`
+	Note      factom.Bytes   `json:"note,omitempty"`
+
+	FromRCD       factom.Bytes `json:"fromrcd,omitempty"`
+	FromSignature factom.Bytes `json:"fromsignature,omitempty"`
+	ToRCD         factom.Bytes `json:"torcd,omitempty"`
+	ToSignature   factom.Bytes `json:"tosignature,omitempty"`
+}
+
+// Propose returns an unsigned Proposal to transfer custody of a from the
+// current Custodian to the identity to.
+func (a Asset) Propose(to factom.Bytes32, note []byte) Proposal {
+	return Proposal{
+		ChainID:   a.ChainID,
+		AssetHash: a.AssetHash,
+		Sequence:  uint64(len(a.Transfers)) + 1,
+		From:      a.Custodian,
+		To:        to,
+		Note:      note,
+	}
+}
+
+// msg returns the data signed by both parties to p.
+func (p Proposal) msg() []byte {
+	noteHash := sha256.Sum256(p.Note)
+	msg := []byte(p.ChainID.String())
+	msg = strconv.AppendUint(msg, p.Sequence, 10)
+	msg = append(msg, p.From[:]...)
+	msg = append(msg, p.To[:]...)
+	msg = append(msg, p.AssetHash[:]...)
+	return append(msg, noteHash[:]...)
+}
+
+// SignFrom signs p for the From identity.
+func (p *Proposal) SignFrom(signer factom.RCDSigner) {
+	p.FromRCD = factom.Bytes(signer.RCD())
+	p.FromSignature = signer.Sign(p.msg())
+}
+
+// SignTo signs p for the To identity.
+func (p *Proposal) SignTo(signer factom.RCDSigner) {
+	p.ToRCD = factom.Bytes(signer.RCD())
+	p.ToSignature = signer.Sign(p.msg())
+}
+
+// Entry returns the custody transfer Entry for p, which must be signed by both
+// parties.
+func (p Proposal) Entry() (factom.Entry, error) {
+	if len(p.FromSignature) == 0 {
+		return factom.Entry{}, fmt.Errorf("missing from signature")
+	}
+	if len(p.ToSignature) == 0 {
+		return factom.Entry{}, fmt.Errorf("missing to signature")
+	}
+	chainID := p.ChainID
+	note := p.Note
+	if note == nil {
+		note = factom.Bytes{}
+	}
+	return factom.Entry{
+		ChainID: &chainID,
+		ExtIDs: []factom.Bytes{TransferTag,
+			factom.Bytes(strconv.FormatUint(p.Sequence, 10)),
+			p.From[:], p.To[:], p.AssetHash[:],
+			p.FromRCD, p.FromSignature, p.ToRCD, p.ToSignature},
+		Content: note,
+	}, nil
+}
+
+// parseTransfer returns the Transfer of the custody transfer Entry e, or an
+// error if e is not a valid next transfer of a.
+func (a Asset) parseTransfer(e factom.Entry, keys KeyChecker) (Transfer, error) {
+	if e.ChainID == nil || *e.ChainID != a.ChainID {
+		return Transfer{}, fmt.Errorf("invalid ChainID")
+	}
+	if len(e.ExtIDs) != 9 || !bytes.Equal(e.ExtIDs[0], TransferTag) {
+		return Transfer{}, fmt.Errorf("invalid ExtIDs")
+	}
+	for _, extID := range e.ExtIDs[2:5] {
+		if len(extID) != len(factom.Bytes32{}) {
+			return Transfer{}, fmt.Errorf("invalid ExtIDs")
+		}
+	}
+	p := Proposal{ChainID: a.ChainID, Note: e.Content}
+	var err error
+	if p.Sequence, err = strconv.ParseUint(string(e.ExtIDs[1]),
+		10, 64); err != nil {
+		return Transfer{}, fmt.Errorf("invalid sequence: %w", err)
+	}
+	copy(p.From[:], e.ExtIDs[2])
+	copy(p.To[:], e.ExtIDs[3])
+	copy(p.AssetHash[:], e.ExtIDs[4])
+
+	if p.Sequence != uint64(len(a.Transfers))+1 ||
+		string(e.ExtIDs[1]) != strconv.FormatUint(p.Sequence, 10) {
+		return Transfer{}, fmt.Errorf("invalid sequence")
+	}
+	if p.AssetHash != a.AssetHash {
+		return Transfer{}, fmt.Errorf("invalid asset hash")
+	}
+	if p.From != a.Custodian {
+		return Transfer{}, fmt.Errorf("from is not the custodian")
+	}
+
+	msg := p.msg()
+	fromKey, err := validate(e.ExtIDs[5], e.ExtIDs[6], msg)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("from: %w", err)
+	}
+	if !keys(p.From, fromKey) {
+		return Transfer{}, fmt.Errorf("from: key is not active")
+	}
+	toKey, err := validate(e.ExtIDs[7], e.ExtIDs[8], msg)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("to: %w", err)
+	}
+	if !keys(p.To, toKey) {
+		return Transfer{}, fmt.Errorf("to: key is not active")
+	}
+
+	return Transfer{
+		Sequence:  p.Sequence,
+		From:      p.From,
+		To:        p.To,
+		Note:      e.Content,
+		FromKey:   fromKey,
+		ToKey:     toKey,
+		EntryHash: e.Hash,
+		Timestamp: e.Timestamp,
+	}, nil
+}
+
+// validate the signature of msg by the RCD data and return its ID1Key.
+func validate(data, sig, msg []byte) (factom.ID1Key, error) {
+	var rcd factom.RCD
+	if err := rcd.UnmarshalBinary(data); err != nil {
+		return factom.ID1Key{}, fmt.Errorf("invalid RCD: %w", err)
+	}
+	if err := rcd.Validate(sig, msg); err != nil {
+		return factom.ID1Key{}, err
+	}
+	return factom.ID1Key(rcd.Hash()), nil
+}
+
+// Apply validates the custody transfer Entry e with keys and applies it to a.
+func (a *Asset) Apply(e factom.Entry, keys KeyChecker) error {
+	t, err := a.parseTransfer(e, keys)
+	if err != nil {
+		return err
+	}
+	a.Transfers = append(a.Transfers[:len(a.Transfers):len(a.Transfers)], t)
+	a.Custodian = t.To
+	return nil
+}
+
+// WriteTransfer submits the Entry of the signed Proposal p using
+// ComposeCreate with es, and then applies it to a.
+func (a *Asset) WriteTransfer(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, p Proposal, keys KeyChecker) (factom.TxID, error) {
+	e, err := p.Entry()
+	if err != nil {
+		return factom.TxID{}, err
+	}
+	if _, err := a.parseTransfer(e, keys); err != nil {
+		return factom.TxID{}, err
+	}
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return factom.TxID{}, err
+	}
+	return txID, a.Apply(e, keys)
+}
+
+// Resolve reconstructs the custody history of the asset from all of the
+// entries in its chain, in order from the first Entry, using keys to validate
+// signatures.
+//
+// The first Entry must be a valid asset chain Entry. All subsequent Entries
+// that are not valid custody transfers are ignored.
+func Resolve(entries []factom.Entry, keys KeyChecker) (Asset, error) {
+	if len(entries) == 0 {
+		return Asset{}, fmt.Errorf("no entries")
+	}
+	a, err := parseFirst(entries[0])
+	if err != nil {
+		return Asset{}, err
+	}
+	for _, e := range entries[1:] {
+		_ = a.Apply(e, keys) // Invalid entries are ignored.
+	}
+	return a, nil
+}
+
+// Get downloads all Entries of the asset chain with the given chainID and
+// reconstructs its custody history. See Resolve.
+func Get(ctx context.Context, c *factom.Client,
+	chainID factom.Bytes32, keys KeyChecker) (Asset, error) {
+	typed, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return Asset{}, err
+	}
+	entries := make([]factom.Entry, len(typed))
+	for i := range typed {
+		entries[i] = typed[i].Entry
+	}
+	return Resolve(entries, keys)
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package custody_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+	. "github.com/Factom-Asset-Tokens/factom/custody"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdentity(t *testing.T, name string) (appidentity.Identity, factom.SK1Key) {
+	sk, err := factom.GenerateSK1Key()
+	require.NoError(t, err)
+	id, _, err := appidentity.New([]factom.ID1Key{sk.ID1Key()},
+		factom.Bytes(name))
+	require.NoError(t, err)
+	return id, sk
+}
+
+func TestCustody(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	factory, factorySK := newIdentity(t, "factory")
+	carrier, carrierSK := newIdentity(t, "carrier")
+	store, storeSK := newIdentity(t, "store")
+	_, otherSK := newIdentity(t, "other")
+	keys := IdentityKeys(factory, carrier, store)
+
+	assetHash := factom.Bytes32(sha256.Sum256([]byte("serial 1234")))
+	asset, first, err := New(assetHash, factory.ChainID,
+		factom.Bytes("pallet"))
+	require.NoError(err)
+	assert.Equal(factom.ComputeChainID(first.ExtIDs), asset.ChainID)
+	assert.Equal(factory.ChainID, asset.Custodian)
+	entries := []factom.Entry{first}
+
+	// The factory hands the asset to the carrier. The Proposal is passed
+	// between the parties as JSON.
+	p := asset.Propose(carrier.ChainID, []byte("dock 4"))
+	p.SignFrom(factorySK)
+	_, err = p.Entry()
+	assert.EqualError(err, "missing to signature")
+	data, err := json.Marshal(p)
+	require.NoError(err)
+	var received Proposal
+	require.NoError(json.Unmarshal(data, &received))
+	received.SignTo(carrierSK)
+	e, err := received.Entry()
+	require.NoError(err)
+	require.NoError(asset.Apply(e, keys))
+	assert.Equal(carrier.ChainID, asset.Custodian)
+	entries = append(entries, e)
+
+	// Replaying the transfer fails.
+	assert.EqualError(asset.Apply(e, keys), "invalid sequence")
+	entries = append(entries, e)
+
+	// Only the custodian may transfer custody.
+	p = asset.Propose(store.ChainID, nil)
+	p.From = factory.ChainID
+	p.SignFrom(factorySK)
+	p.SignTo(storeSK)
+	e, err = p.Entry()
+	require.NoError(err)
+	assert.EqualError(asset.Apply(e, keys), "from is not the custodian")
+	entries = append(entries, e)
+
+	// Both parties must sign with their own keys.
+	p = asset.Propose(store.ChainID, nil)
+	p.SignFrom(carrierSK)
+	p.SignTo(otherSK)
+	e, err = p.Entry()
+	require.NoError(err)
+	assert.EqualError(asset.Apply(e, keys), "to: key is not active")
+	entries = append(entries, e)
+
+	// The carrier hands the asset to the store.
+	p.SignTo(storeSK)
+	p.Note = factom.Bytes("modified")
+	e, err = p.Entry()
+	require.NoError(err)
+	assert.EqualError(asset.Apply(e, keys), "from: invalid signature")
+	p.Note = nil
+	e, err = p.Entry()
+	require.NoError(err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	_, err = asset.WriteTransfer(context.Background(), c, es, p, keys)
+	require.NoError(err)
+	assert.Equal(store.ChainID, asset.Custodian)
+	require.Len(asset.Transfers, 2)
+	assert.NotNil(asset.Transfers[1].EntryHash)
+	entries = append(entries, e)
+
+	resolved, err := Resolve(entries, keys)
+	require.NoError(err)
+	assert.Equal(asset.Custodian, resolved.Custodian)
+	require.Len(resolved.Transfers, 2)
+	assert.Equal(factom.Bytes("dock 4"), resolved.Transfers[0].Note)
+	assert.Equal(carrierSK.ID1Key(), resolved.Transfers[0].ToKey)
+	assert.Equal(uint64(2), resolved.Transfers[1].Sequence)
+	assert.Equal(carrier.ChainID, resolved.Transfers[1].From)
+}
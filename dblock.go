@@ -33,9 +33,9 @@ import (
 )
 
 var (
-	aBlockChainID  = Bytes32{31: 0x0a}
-	ecBlockChainID = Bytes32{31: 0x0c}
-	fBlockChainID  = Bytes32{31: 0x0f}
+	aBlockChainID  = AdminChain.ChainID()
+	ecBlockChainID = ECChain.ChainID()
+	fBlockChainID  = FactoidChain.ChainID()
 )
 
 // ABlockChainID returns the ChainID of the Admin Block Chain, 0x00..0a.
@@ -51,13 +51,13 @@ func FBlockChainID() Bytes32 { return fBlockChainID }
 // DBlock represents a Factom Directory Block.
 type DBlock struct {
 	// Computed
-	KeyMR    *Bytes32
+	KeyMR    *KeyMR
 	FullHash *Bytes32
 
 	// Unmarshaled
 	NetworkID    NetworkID
 	BodyMR       *Bytes32
-	PrevKeyMR    *Bytes32
+	PrevKeyMR    *KeyMR
 	PrevFullHash *Bytes32
 	Height       uint32
 	Timestamp    time.Time
@@ -103,44 +103,58 @@ func (db *DBlock) Get(ctx context.Context, c *Client) (err error) {
 		return nil
 	}
 
+	data, err := c.getDBlockRawData(ctx, db.Height, &db.KeyMR)
+	if err != nil {
+		return err
+	}
+	if err := db.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return c.Network.checkDBlock(db)
+}
+
+// getDBlockRawData returns the raw data of the DBlock with *keyMR, if not nil,
+// or otherwise of the DBlock at height, in which case *keyMR is populated.
+func (c *Client) getDBlockRawData(ctx context.Context,
+	height uint32, keyMR **KeyMR) (Bytes, error) {
 	// Normally query by height.
 	method := "dblock-by-height"
 	params := interface{}(struct {
 		Height uint32 `json:"height"`
-	}{db.Height})
+	}{height})
 	var res struct {
 		Data Bytes `json:"rawdata"`
 		// DBlock nesting required for factomd API.
 		DBlock struct {
 			// Use a double pointer so that the db.KeyMR pointer
 			// will be populated during unmarshalling.
-			KeyMR **Bytes32 `json:"keymr"`
+			KeyMR **KeyMR `json:"keymr"`
 		} `json:"dblock"`
 	}
-	res.DBlock.KeyMR = &db.KeyMR
+	res.DBlock.KeyMR = keyMR
 	result := interface{}(&res)
 
 	// If a KeyMR is specified, query for that DBlock specifically.
-	if db.KeyMR != nil {
+	if *keyMR != nil {
 		method = "raw-data"
 		params = struct {
-			Hash *Bytes32 `json:"hash"`
-		}{Hash: db.KeyMR}
+			Hash *KeyMR `json:"hash"`
+		}{Hash: *keyMR}
 
 		// Use a typecase to overwrite the JSON field names. Only Data
 		// will be populated.
 		result = (*struct {
 			Data   Bytes `json:"data"`
 			DBlock struct {
-				KeyMR **Bytes32 `json:"-"`
+				KeyMR **KeyMR `json:"-"`
 			} `json:"-"`
 		})(&res)
 	}
 
 	if err := c.FactomdRequest(ctx, method, params, result); err != nil {
-		return err
+		return nil, err
 	}
-	return db.UnmarshalBinary(res.Data)
+	return res.Data, nil
 }
 
 // DBlockHeaderSize is the exact length of a DBlock header.
@@ -220,7 +234,7 @@ func (db *DBlock) UnmarshalBinary(data []byte) error {
 	db.BodyMR = new(Bytes32)
 	i += copy(db.BodyMR[:], data[i:])
 
-	db.PrevKeyMR = new(Bytes32)
+	db.PrevKeyMR = new(KeyMR)
 	i += copy(db.PrevKeyMR[:], data[i:])
 
 	db.PrevFullHash = new(Bytes32)
@@ -238,9 +252,12 @@ func (db *DBlock) UnmarshalBinary(data []byte) error {
 	i += 4
 
 	// Ensure we have enough data left to read all of the EBlocks.
-	if eBlockCount*DBlockEBlockSize > len(data[i:]) {
+	if uint64(eBlockCount)*DBlockEBlockSize > uint64(len(data[i:])) {
 		return fmt.Errorf("insufficient length")
 	}
+	if eBlockCount*DBlockEBlockSize < DBlockMinBodySize {
+		return fmt.Errorf("insufficient EBlock count")
+	}
 
 	// elements are used to compute the BodyMR.
 	elements := make([][]byte, eBlockCount)
@@ -262,7 +279,8 @@ func (db *DBlock) UnmarshalBinary(data []byte) error {
 
 		// Populate ChainID, KeyMR, Timestamp, and Height.
 
-		var chainID, keyMR Bytes32
+		var chainID Bytes32
+		var keyMR KeyMR
 		i += copy(chainID[:], data[i:])
 		prevChainID = &chainID
 		i += copy(keyMR[:], data[i:])
@@ -277,6 +295,8 @@ func (db *DBlock) UnmarshalBinary(data []byte) error {
 		var offset int
 		if db.FBlock.KeyMR != nil {
 			offset++
+		} else if ebi == len(db.EBlocks) {
+			return fmt.Errorf("missing FBlock")
 		}
 
 		eb := &db.EBlocks[ebi-offset]
@@ -410,3 +430,19 @@ func (db DBlock) EBlock(chainID Bytes32) *EBlock {
 	}
 	return nil
 }
+
+// EBlocksFor returns the EBlocks in db for the given chainIDs, in the order of
+// chainIDs. ChainIDs without an EBlock in db are omitted, so the result is
+// empty if db does not reference any of the chainIDs.
+//
+// Only the returned EBlocks need be fetched with EBlock.Get, which lets a
+// caller watching a few Chains ignore all other EBlocks in db.
+func (db DBlock) EBlocksFor(chainIDs ...Bytes32) []EBlock {
+	var ebs []EBlock
+	for _, chainID := range chainIDs {
+		if eb := db.EBlock(chainID); eb != nil {
+			ebs = append(ebs, *eb)
+		}
+	}
+	return ebs
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// DBSignature is an Admin Block Entry in the ABlock at height N+1 that
+// signs the header of the DBlock at height N.
+type DBSignature struct {
+	// ChainID is the identity ChainID of the signing server.
+	ChainID   Bytes32
+	PublicKey Bytes32
+	Signature Bytes
+}
+
+// DBSignature parses e as a DBSignature.
+func (e ABEntry) DBSignature() (DBSignature, error) {
+	if e.Type != ABEntryDBSignature {
+		return DBSignature{}, fmt.Errorf("invalid entry type: %v", e.Type)
+	}
+	if len(e.Data) != abEntryFixedSizes[ABEntryDBSignature] {
+		return DBSignature{}, fmt.Errorf("invalid entry length")
+	}
+	var sig DBSignature
+	copy(sig.ChainID[:], e.Data)
+	copy(sig.PublicKey[:], e.Data[32:])
+	sig.Signature = e.Data[64:]
+	return sig, nil
+}
+
+// ABEntry returns the Admin Block Entry for sig.
+func (sig DBSignature) ABEntry() ABEntry {
+	data := make([]byte, abEntryFixedSizes[ABEntryDBSignature])
+	copy(data, sig.ChainID[:])
+	copy(data[32:], sig.PublicKey[:])
+	copy(data[64:], sig.Signature)
+	return ABEntry{Type: ABEntryDBSignature, Data: data}
+}
+
+// DBSignatures returns all DBSignatures in ab.
+func (ab ABlock) DBSignatures() ([]DBSignature, error) {
+	var sigs []DBSignature
+	for i, e := range ab.Entries {
+		if e.Type != ABEntryDBSignature {
+			continue
+		}
+		sig, err := e.DBSignature()
+		if err != nil {
+			return nil, fmt.Errorf("entry %v: %w", i, err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// AuthoritySet provides the federated servers that sign DBlocks.
+type AuthoritySet interface {
+	// SigningKeys returns the DBlock signing keys of all federated
+	// servers by their identity ChainIDs.
+	SigningKeys() map[Bytes32]Bytes32
+}
+
+// Verify returns an error unless the DBSignatures in next, the ABlock at
+// db.Height+1, prove that a majority of the federated servers in set signed
+// the header of db. The set must be the authority set responsible for the
+// DBlock at db.Height+1.
+//
+// A DBSignature must not be duplicated and must use the signing key of a
+// federated server in set. DBSignatures with invalid signatures are not
+// counted.
+func (db DBlock) Verify(next ABlock, set AuthoritySet) error {
+	if next.Height != db.Height+1 {
+		return fmt.Errorf("invalid ABlock height: %v, expected %v",
+			next.Height, db.Height+1)
+	}
+	data, err := db.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	header := data[:DBlockHeaderSize]
+
+	sigs, err := next.DBSignatures()
+	if err != nil {
+		return err
+	}
+
+	keys := set.SigningKeys()
+	if len(keys) == 0 {
+		return fmt.Errorf("no federated servers")
+	}
+
+	signed := make(map[Bytes32]struct{}, len(sigs))
+	var valid int
+	for _, sig := range sigs {
+		if _, ok := signed[sig.ChainID]; ok {
+			return fmt.Errorf("duplicate DBSignature: %v", sig.ChainID)
+		}
+		signed[sig.ChainID] = struct{}{}
+
+		key, ok := keys[sig.ChainID]
+		if !ok {
+			return fmt.Errorf("DBSignature from non-federated server: %v",
+				sig.ChainID)
+		}
+		if key != sig.PublicKey {
+			return fmt.Errorf("DBSignature with invalid key: %v",
+				sig.ChainID)
+		}
+		if ed25519.Verify(sig.PublicKey[:], header, sig.Signature) {
+			valid++
+		}
+	}
+
+	if valid <= len(keys)/2 {
+		return fmt.Errorf("insufficient valid DBSignatures: %v of %v",
+			valid, len(keys))
+	}
+	return nil
+}
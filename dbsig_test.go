@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"crypto/ed25519"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signingKeys map[Bytes32]Bytes32
+
+func (keys signingKeys) SigningKeys() map[Bytes32]Bytes32 { return keys }
+
+func testDBlockHeader(t *testing.T) (DBlock, []byte) {
+	var keyMR KeyMR
+	var hash Bytes32
+	adminChainID := ABlockChainID()
+	db := DBlock{
+		KeyMR:        &keyMR,
+		FullHash:     &hash,
+		BodyMR:       &hash,
+		PrevKeyMR:    &keyMR,
+		PrevFullHash: &hash,
+		NetworkID:    MainnetID(),
+		Height:       1000,
+		Timestamp:    time.Unix(24028950*60, 0),
+		FBlock:       FBlock{KeyMR: &keyMR},
+		EBlocks:      []EBlock{{ChainID: &adminChainID, KeyMR: &keyMR}},
+	}
+	data, err := db.MarshalBinary()
+	require.NoError(t, err)
+	return db, data[:DBlockHeaderSize]
+}
+
+func TestDBlockVerify(t *testing.T) {
+	db, header := testDBlockHeader(t)
+	rand := rand.New(rand.NewSource(1))
+
+	keys := make(signingKeys)
+	var sigs []DBSignature
+	for i := 0; i < 3; i++ {
+		pub, priv, err := ed25519.GenerateKey(rand)
+		require.NoError(t, err)
+		id := Bytes32{0x88, 0x88, 0x88, byte(i)}
+		var key Bytes32
+		copy(key[:], pub)
+		keys[id] = key
+		sigs = append(sigs, DBSignature{ChainID: id, PublicKey: key,
+			Signature: ed25519.Sign(priv, header)})
+	}
+	ablock := func(sigs ...DBSignature) ABlock {
+		ab := ABlock{Height: db.Height + 1}
+		for _, sig := range sigs {
+			ab.Entries = append(ab.Entries, sig.ABEntry())
+		}
+		return ab
+	}
+
+	assert.NoError(t, db.Verify(ablock(sigs...), keys))
+	assert.NoError(t, db.Verify(ablock(sigs[:2]...), keys))
+
+	invalid := sigs[2]
+	invalid.Signature = append(Bytes{}, invalid.Signature...)
+	invalid.Signature[0]++
+	wrongKey := sigs[1]
+	wrongKey.PublicKey = sigs[0].PublicKey
+	unknown := sigs[1]
+	unknown.ChainID = Bytes32{1}
+
+	for _, test := range []struct {
+		Name   string
+		ABlock ABlock
+		Keys   signingKeys
+		Error  string
+	}{{
+		Name:   "height",
+		ABlock: ABlock{Height: db.Height},
+		Keys:   keys,
+		Error:  "invalid ABlock height: 1000, expected 1001",
+	}, {
+		Name:   "no federated servers",
+		ABlock: ablock(sigs...),
+		Error:  "no federated servers",
+	}, {
+		Name:   "insufficient",
+		ABlock: ablock(sigs[0]),
+		Keys:   keys,
+		Error:  "insufficient valid DBSignatures: 1 of 3",
+	}, {
+		Name:   "invalid signature",
+		ABlock: ablock(sigs[0], invalid),
+		Keys:   keys,
+		Error:  "insufficient valid DBSignatures: 1 of 3",
+	}, {
+		Name:   "duplicate",
+		ABlock: ablock(sigs[0], sigs[1], sigs[0]),
+		Keys:   keys,
+		Error:  "duplicate DBSignature: " + sigs[0].ChainID.String(),
+	}, {
+		Name:   "wrong key",
+		ABlock: ablock(wrongKey),
+		Keys:   keys,
+		Error:  "DBSignature with invalid key: " + sigs[1].ChainID.String(),
+	}, {
+		Name:   "non-federated",
+		ABlock: ablock(unknown),
+		Keys:   keys,
+		Error: "DBSignature from non-federated server: " +
+			unknown.ChainID.String(),
+	}, {
+		Name: "entry length",
+		ABlock: ABlock{Height: db.Height + 1, Entries: []ABEntry{
+			{Type: ABEntryDBSignature}}},
+		Keys:  keys,
+		Error: "entry 0: invalid entry length",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.EqualError(t, db.Verify(test.ABlock, test.Keys),
+				test.Error)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+)
+
+// Resolver looks up DNS records. *net.Resolver implements Resolver.
+type Resolver interface {
+	LookupSRV(ctx context.Context,
+		service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+var _ Resolver = &net.Resolver{}
+
+// Discoverer returns the URLs of the factomd APIs that are currently
+// available, in order of preference.
+type Discoverer func(ctx context.Context) ([]string, error)
+
+// DiscoverSRV returns a Discoverer that looks up the SRV records of
+// _service._proto.name, such as _factomd._tcp.example.com, and returns an
+// Endpoint URL with scheme for each target, in the order returned by r. If r
+// is nil, net.DefaultResolver is used.
+func DiscoverSRV(r Resolver, scheme, service, proto, name string) Discoverer {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return func(ctx context.Context) ([]string, error) {
+		_, srvs, err := r.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+		urls := make([]string, len(srvs))
+		for i, srv := range srvs {
+			urls[i] = Endpoint{Scheme: scheme,
+				Host: strings.TrimSuffix(srv.Target, "."),
+				Port: int(srv.Port)}.FactomdURL()
+		}
+		return urls, nil
+	}
+}
+
+// DiscoverTXT returns a Discoverer that looks up the TXT records of name, each
+// of which holds one or more whitespace separated absolute URLs. If r is nil,
+// net.DefaultResolver is used.
+func DiscoverTXT(r Resolver, name string) Discoverer {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return func(ctx context.Context) ([]string, error) {
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		var urls []string
+		for _, txt := range txts {
+			for _, field := range strings.Fields(txt) {
+				u, err := url.Parse(field)
+				if err != nil || !u.IsAbs() || u.Host == "" {
+					return nil, fmt.Errorf(
+						"invalid URL in TXT record: %q", field)
+				}
+				urls = append(urls, u.String())
+			}
+		}
+		return urls, nil
+	}
+}
+
+// DefaultDiscoveryInterval is the EndpointPool.RefreshInterval used if zero.
+const DefaultDiscoveryInterval = 5 * time.Minute
+
+// EndpointPool is a pool of factomd API URLs found by a Discoverer.
+//
+// When Client.Endpoints is set, factomd requests are sent to the preferred URL
+// in the pool instead of Client.FactomdServer. If a request fails without a
+// JSON-RPC response, the next URL is tried, and it becomes the preferred URL
+// for subsequent requests. JSON-RPC errors are returned without failing over,
+// since they come from a reachable node.
+//
+// The URLs are discovered again every RefreshInterval, and immediately after
+// all of them fail, so that a fleet of nodes may be reconfigured without
+// redeploying its clients. If discovery fails, the previous URLs are kept.
+//
+// An EndpointPool is safe for concurrent use and may be shared by Clients.
+type EndpointPool struct {
+	Discover Discoverer
+
+	// RefreshInterval is how often Discover is called. If zero,
+	// DefaultDiscoveryInterval is used.
+	RefreshInterval time.Duration
+
+	mu        sync.Mutex
+	urls      []string
+	preferred int
+	refreshed time.Time
+}
+
+// URLs returns the URLs of the pool starting with the preferred URL. Discover
+// is called first if the URLs are due to be refreshed. An error is only
+// returned if no URLs are known.
+func (p *EndpointPool) URLs(ctx context.Context) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	interval := p.RefreshInterval
+	if interval == 0 {
+		interval = DefaultDiscoveryInterval
+	}
+	var err error
+	if p.refreshed.IsZero() || time.Since(p.refreshed) >= interval {
+		err = p.refresh(ctx)
+	}
+	if len(p.urls) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no factomd endpoints discovered")
+		}
+		return nil, err
+	}
+	urls := make([]string, 0, len(p.urls))
+	urls = append(urls, p.urls[p.preferred:]...)
+	return append(urls, p.urls[:p.preferred]...), nil
+}
+
+// Refresh calls Discover and replaces the URLs of the pool, unless Discover
+// fails or returns no URLs. The preferred URL is kept if it is still present.
+func (p *EndpointPool) Refresh(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refresh(ctx)
+}
+
+func (p *EndpointPool) refresh(ctx context.Context) error {
+	p.refreshed = time.Now()
+	urls, err := p.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("no factomd endpoints discovered")
+	}
+	var preferred string
+	if len(p.urls) > 0 {
+		preferred = p.urls[p.preferred]
+	}
+	p.urls, p.preferred = urls, 0
+	for i, u := range urls {
+		if u == preferred {
+			p.preferred = i
+			break
+		}
+	}
+	return nil
+}
+
+// fail records that a request to url failed, so the following URL becomes
+// preferred. If every URL has failed since the last success, the pool is
+// marked to be refreshed.
+func (p *EndpointPool) fail(url string, last bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.urls) == 0 || p.urls[p.preferred] != url {
+		return
+	}
+	p.preferred = (p.preferred + 1) % len(p.urls)
+	if last {
+		p.refreshed = time.Time{}
+	}
+}
+
+// request makes the request to the URLs of p in turn until one responds.
+func (p *EndpointPool) request(ctx context.Context, c *Client,
+	method string, params, result interface{}) error {
+	urls, err := p.URLs(ctx)
+	if err != nil {
+		return err
+	}
+	for i, url := range urls {
+		if c.Factomd.DebugRequest {
+			fmt.Println("factomd:", url)
+		}
+		err = c.request(ctx, &c.Factomd, url, method, params, result)
+		var jErr jsonrpc2.Error
+		if err == nil || errors.As(err, &jErr) ||
+			(ctx != nil && ctx.Err() != nil) {
+			return err
+		}
+		p.fail(url, i == len(urls)-1)
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResolver struct {
+	srvs []*net.SRV
+	txts []string
+	err  error
+}
+
+func (r *mockResolver) LookupSRV(ctx context.Context,
+	service, proto, name string) (string, []*net.SRV, error) {
+	return fmt.Sprintf("_%v._%v.%v", service, proto, name), r.srvs, r.err
+}
+
+func (r *mockResolver) LookupTXT(
+	ctx context.Context, name string) ([]string, error) {
+	return r.txts, r.err
+}
+
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+	r := &mockResolver{
+		srvs: []*net.SRV{
+			{Target: "a.example.com.", Port: 8088},
+			{Target: "b.example.com.", Port: 443},
+		},
+		txts: []string{"https://c.example.com/v2 http://d.example.com:8088/v2"},
+	}
+
+	urls, err := DiscoverSRV(r, "https", "factomd", "tcp", "example.com")(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://a.example.com:8088/v2",
+		"https://b.example.com:443/v2"}, urls)
+
+	urls, err = DiscoverTXT(r, "example.com")(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://c.example.com/v2",
+		"http://d.example.com:8088/v2"}, urls)
+
+	r.txts = []string{"c.example.com"}
+	_, err = DiscoverTXT(r, "example.com")(ctx)
+	assert.EqualError(t, err, `invalid URL in TXT record: "c.example.com"`)
+
+	r.err = fmt.Errorf("no such host")
+	_, err = DiscoverSRV(r, "", "factomd", "tcp", "example.com")(ctx)
+	assert.EqualError(t, err, "no such host")
+}
+
+func TestEndpointPool(t *testing.T) {
+	ctx := context.Background()
+	var discovered []string
+	var discoverErr error
+	var discoveries int
+	pool := &EndpointPool{Discover: func(context.Context) ([]string, error) {
+		discoveries++
+		return discovered, discoverErr
+	}}
+
+	down := map[string]bool{"a": true}
+	var requested []string
+	c := NewClient()
+	c.Endpoints = pool
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		requested = append(requested, req.URL.Host)
+		if down[req.URL.Host] {
+			return &http.Response{StatusCode: http.StatusBadGateway,
+				Body:   ioutil.NopCloser(bytes.NewBufferString("down")),
+				Header: make(http.Header)}
+		}
+		var jReq struct {
+			ID interface{} `json:"id"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		res := jsonrpc2.Response{ID: jReq.ID, Result: req.URL.Host}
+		respData, _ := json.Marshal(res)
+		return &http.Response{StatusCode: http.StatusOK,
+			Body:   ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header: make(http.Header)}
+	})
+
+	var host string
+	assert.EqualError(t, c.FactomdRequest(ctx, "heights", nil, &host),
+		"no factomd endpoints discovered")
+
+	discovered = []string{"http://a/v2", "http://b/v2"}
+	require.NoError(t, pool.Refresh(ctx))
+
+	// a is down, so the request fails over to b, which becomes preferred.
+	require.NoError(t, c.FactomdRequest(ctx, "heights", nil, &host))
+	assert.Equal(t, "b", host)
+	assert.Equal(t, []string{"a", "b"}, requested)
+
+	requested = nil
+	require.NoError(t, c.FactomdRequest(ctx, "heights", nil, &host))
+	assert.Equal(t, []string{"b"}, requested)
+
+	// When every URL fails, the pool is discovered again before the
+	// next request.
+	down["b"] = true
+	assert.Error(t, c.FactomdRequest(ctx, "heights", nil, &host))
+	n := discoveries
+	discovered = []string{"http://c/v2"}
+	require.NoError(t, c.FactomdRequest(ctx, "heights", nil, &host))
+	assert.Equal(t, "c", host)
+	assert.Equal(t, n+1, discoveries)
+
+	// The previous URLs are kept if discovery fails.
+	discoverErr = fmt.Errorf("no such host")
+	assert.EqualError(t, pool.Refresh(ctx), "no such host")
+	urls, err := pool.URLs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://c/v2"}, urls)
+}
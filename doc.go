@@ -49,7 +49,18 @@
 // encoding.TextUnmarshaler interfaces and are used by other types when JSON
 // marshaling and unmarshaling to and from hex strings is required.
 //
-// Bytes32 is an array used for ChainIDs, KeyMRs, and other 32 byte hashes.
+// Bytes32 is an array used for ChainIDs and other 32 byte hashes. The
+// EntryHash, KeyMR, and TxID types are distinct Bytes32 based types so that the
+// different kinds of hashes cannot be accidentally mixed up.
+//
+// The hash and address types implement fmt.Formatter so that they print
+// consistently: %s and %v print the String, %q quotes it, %x and %X print the
+// hex of the raw 32 bytes, and %#v prints the GoString. A precision truncates
+// the output, so "%.8s" prints the short form used in logs.
+//
+// The Int48BE and Timestamp functions, along with the varintf package, expose
+// the number encodings used by the binary data structures for use by code
+// that extends this package, such as new RCD types or block parsers.
 //
 // The Address interfaces and types allow for working with the four Factom
 // address types.
@@ -65,7 +76,12 @@
 // and reveal data, if the private entry credit key is available locally. See
 // Entry.Create and Entry.ComposeCreate.
 //
-// This package does not yet support Factoid transactions, nor does it support
-// the binary data structures for Admin Blocks, Entry Credit Blocks, or Factoid
-// Blocks.
+// The coinbase Transaction of an FBlock may be classified into genesis,
+// authority, and grant Payouts using the CoinbaseDescriptor of the ABlock that
+// declared it. See CoinbaseParams.Payouts. ABlock Entries other than
+// CoinbaseDescriptors are only available as raw data.
+//
+// Entry Credit Blocks may be loaded and parsed with ECBlock, which verifies
+// the signature and EC cost of each Entry and Chain Commit. See
+// ECBlock.InvalidCommits.
 package factom
@@ -0,0 +1,431 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// DryRun validates and records the state changing requests of a Client,
+// instead of sending them. Set Client.DryRun to a new(DryRun) to enable dry
+// run mode.
+//
+// Entry and Chain commits are checked for a valid encoding and signature, and
+// that the Entry Credit balance can pay for them. Reveals are checked for a
+// valid Entry encoding, and against any matching commit in the DryRun.
+// Factoid transactions are checked for a valid encoding and signatures, the
+// required fee, and that the FCTInputs have sufficient balances. Successful
+// factomd requests return a simulated result, and are reflected in the
+// balances used to validate subsequent requests.
+//
+// Requests to factom-walletd that modify its state are recorded and, where
+// possible, their params are validated, but no result is returned.
+//
+// Read only requests, including those made to check balances and the EC
+// rate, are always sent.
+type DryRun struct {
+	// SkipBalanceCheck disables the queries for the Factoid and Entry
+	// Credit balances, and the EC rate.
+	SkipBalanceCheck bool
+
+	mu       sync.Mutex
+	requests []DryRunRequest
+	commits  map[EntryHash]dryRunCommit
+	fctDelta map[FAAddress]int64
+	ecDelta  map[ECAddress]int64
+}
+
+// DryRunRequest is a request that was intercepted by a DryRun, and what it
+// would have sent.
+type DryRunRequest struct {
+	// Server is either "factomd" or "factom-walletd".
+	Server string
+	Method string
+	Params json.RawMessage
+
+	// Result is the simulated result returned for the request, if any.
+	Result json.RawMessage
+}
+
+type dryRunCommit struct {
+	Cost     uint8
+	NewChain bool
+
+	// ChainIDHash is only populated if NewChain is true.
+	ChainIDHash Bytes32
+	Weld        Bytes32
+}
+
+// Requests returns all requests intercepted by dr, in the order that they
+// were made.
+func (dr *DryRun) Requests() []DryRunRequest {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	return append([]DryRunRequest(nil), dr.requests...)
+}
+
+// Reset discards all recorded requests and balance changes.
+func (dr *DryRun) Reset() {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.requests = nil
+	dr.commits = nil
+	dr.fctDelta = nil
+	dr.ecDelta = nil
+}
+
+var dryRunFactomdMethods = map[string]struct{}{
+	"commit-chain":     {},
+	"commit-entry":     {},
+	"reveal-chain":     {},
+	"reveal-entry":     {},
+	"factoid-submit":   {},
+	"send-raw-message": {},
+}
+
+var dryRunWalletdMethods = map[string]struct{}{
+	"generate-ec-address":      {},
+	"generate-factoid-address": {},
+	"import-addresses":         {},
+	"import-koinify":           {},
+	"remove-address":           {},
+	"new-transaction":          {},
+	"delete-transaction":       {},
+	"add-input":                {},
+	"add-output":               {},
+	"add-ec-output":            {},
+	"add-fee":                  {},
+	"sub-fee":                  {},
+	"sign-transaction":         {},
+}
+
+// interceptsFactomd returns true if the factomd method changes state and must
+// be handled by dr.
+func (dr *DryRun) interceptsFactomd(method string) bool {
+	_, ok := dryRunFactomdMethods[method]
+	return dr != nil && ok
+}
+
+// interceptsWalletd returns true if the factom-walletd method changes state
+// and must be handled by dr.
+func (dr *DryRun) interceptsWalletd(method string) bool {
+	_, ok := dryRunWalletdMethods[method]
+	return dr != nil && ok
+}
+
+func (dr *DryRun) factomdRequest(ctx context.Context, c *Client,
+	method string, params, result interface{}) error {
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var res interface{}
+	switch method {
+	case "commit-chain", "commit-entry":
+		res, err = dr.commit(ctx, c, rawParams)
+	case "reveal-chain", "reveal-entry":
+		res, err = dr.reveal(rawParams)
+	case "factoid-submit":
+		res, err = dr.factoidSubmit(ctx, c, rawParams)
+	}
+	if err != nil {
+		return fmt.Errorf("dry run: %v: %w", method, err)
+	}
+
+	var rawResult json.RawMessage
+	if res != nil {
+		if rawResult, err = json.Marshal(res); err != nil {
+			return err
+		}
+		if result != nil {
+			if err := json.Unmarshal(rawResult, result); err != nil {
+				return err
+			}
+		}
+	}
+
+	dr.record("factomd", method, rawParams, rawResult)
+	return nil
+}
+
+func (dr *DryRun) walletdRequest(method string, params interface{}) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if method == "import-addresses" {
+		if err := dryRunImportAddresses(rawParams); err != nil {
+			return fmt.Errorf("dry run: %v: %w", method, err)
+		}
+	}
+	dr.record("factom-walletd", method, rawParams, nil)
+	return nil
+}
+
+// init allocates the maps of dr, if necessary. The caller must hold dr.mu.
+func (dr *DryRun) init() {
+	if dr.commits == nil {
+		dr.commits = make(map[EntryHash]dryRunCommit)
+		dr.fctDelta = make(map[FAAddress]int64)
+		dr.ecDelta = make(map[ECAddress]int64)
+	}
+}
+
+func (dr *DryRun) record(server, method string, params, result json.RawMessage) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	dr.requests = append(dr.requests, DryRunRequest{
+		Server: server,
+		Method: method,
+		Params: params,
+		Result: result,
+	})
+}
+
+func dryRunImportAddresses(params json.RawMessage) error {
+	var p struct{ Addresses []struct{ Secret string } }
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	for _, adr := range p.Addresses {
+		if len(adr.Secret) < 2 {
+			return fmt.Errorf("invalid address")
+		}
+		var err error
+		switch adr.Secret[:2] {
+		case fsPrefixStr:
+			_, err = NewFsAddress(adr.Secret)
+		case esPrefixStr:
+			_, err = NewEsAddress(adr.Secret)
+		default:
+			err = fmt.Errorf("invalid prefix")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dr *DryRun) commit(ctx context.Context, c *Client,
+	params json.RawMessage) (interface{}, error) {
+
+	var p struct {
+		Commit Bytes `json:"message"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+
+	var commit Commit
+	if err := commit.UnmarshalBinary(p.Commit); err != nil {
+		return nil, err
+	}
+	if err := commit.Valid(); err != nil {
+		return nil, err
+	}
+	cmt := dryRunCommit{Cost: commit.Cost, NewChain: commit.NewChain()}
+	if cmt.NewChain {
+		cmt.ChainIDHash = *commit.ChainIDHash
+		cmt.Weld = *commit.Weld
+	}
+	hash, ec := commit.EntryHash, commit.ECAddress
+
+	var balance uint64
+	if !dr.SkipBalanceCheck {
+		var err error
+		if balance, err = ec.GetBalance(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if !dr.SkipBalanceCheck &&
+		int64(balance)+dr.ecDelta[ec] < int64(cmt.Cost) {
+		return nil, fmt.Errorf("insufficient balance")
+	}
+	dr.init()
+	dr.ecDelta[ec] -= int64(cmt.Cost)
+	dr.commits[hash] = cmt
+
+	result := struct {
+		Message     string    `json:"message"`
+		TxID        TxID      `json:"txid"`
+		EntryHash   EntryHash `json:"entryhash"`
+		ChainIDHash *Bytes32  `json:"chainidhash,omitempty"`
+	}{
+		Message:   "Entry Commit Success",
+		TxID:      commit.TxID,
+		EntryHash: hash,
+	}
+	if cmt.NewChain {
+		result.Message = "Chain Commit Success"
+		result.ChainIDHash = &cmt.ChainIDHash
+	}
+	return result, nil
+}
+
+func (dr *DryRun) reveal(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Reveal Bytes `json:"entry"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+
+	var e Entry
+	if err := e.UnmarshalBinary(p.Reveal); err != nil {
+		return nil, err
+	}
+
+	dr.mu.Lock()
+	cmt, ok := dr.commits[*e.Hash]
+	dr.mu.Unlock()
+	if ok {
+		cost, err := EntryCost(len(p.Reveal), cmt.NewChain)
+		if err != nil {
+			return nil, err
+		}
+		if cmt.Cost < cost {
+			return nil, fmt.Errorf("insufficient EC cost paid by commit")
+		}
+		if cmt.NewChain {
+			if cmt.ChainIDHash != sha256d(e.ChainID[:]) {
+				return nil, fmt.Errorf("invalid ChainID hash")
+			}
+			weld := sha256d(append(e.Hash[:], e.ChainID[:]...))
+			if cmt.Weld != weld {
+				return nil, fmt.Errorf("invalid commit weld")
+			}
+		}
+	}
+
+	return struct {
+		Message   string     `json:"message"`
+		EntryHash *EntryHash `json:"entryhash"`
+		ChainID   *Bytes32   `json:"chainid"`
+	}{
+		Message:   "Entry Reveal Success",
+		EntryHash: e.Hash,
+		ChainID:   e.ChainID,
+	}, nil
+}
+
+func (dr *DryRun) factoidSubmit(ctx context.Context, c *Client,
+	params json.RawMessage) (interface{}, error) {
+
+	var p struct {
+		Transaction Bytes `json:"transaction"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+
+	var tx Transaction
+	if err := tx.UnmarshalBinary(p.Transaction); err != nil {
+		return nil, err
+	}
+	if tx.MarshalBinaryLen() != len(p.Transaction) {
+		return nil, fmt.Errorf("invalid length")
+	}
+	if len(tx.FCTInputs) == 0 {
+		return nil, fmt.Errorf("no inputs")
+	}
+
+	// All amounts must be representable as an int64, as they are in
+	// factomd.
+	inputs := make(map[FAAddress]int64, len(tx.FCTInputs))
+	for _, adrs := range [][]AddressAmount{
+		tx.FCTInputs, tx.FCTOutputs, tx.ECOutputs} {
+		for _, adr := range adrs {
+			if adr.Amount > math.MaxInt64 {
+				return nil, fmt.Errorf("amount out of range")
+			}
+		}
+	}
+	if tx.TotalIn > math.MaxInt64 {
+		return nil, fmt.Errorf("amount out of range")
+	}
+	for _, adr := range tx.FCTInputs {
+		inputs[adr.FAAddress()] += int64(adr.Amount)
+	}
+
+	var ecRate uint64
+	balances := make(map[FAAddress]uint64, len(inputs))
+	if !dr.SkipBalanceCheck {
+		var err error
+		if ecRate, err = c.GetECRate(ctx); err != nil {
+			return nil, err
+		}
+		fee, err := tx.RequiredFee(ecRate)
+		if err != nil {
+			return nil, err
+		}
+		if tx.TotalBurn < fee {
+			return nil, fmt.Errorf("insufficient fee: %v < %v",
+				tx.TotalBurn, fee)
+		}
+		for fa := range inputs {
+			if balances[fa], err = fa.GetBalance(ctx, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if !dr.SkipBalanceCheck {
+		for fa, amount := range inputs {
+			if int64(balances[fa])+dr.fctDelta[fa] < amount {
+				return nil, fmt.Errorf("insufficient balance: %v", fa)
+			}
+		}
+	}
+	dr.init()
+	for fa, amount := range inputs {
+		dr.fctDelta[fa] -= amount
+	}
+	for _, adr := range tx.FCTOutputs {
+		dr.fctDelta[adr.FAAddress()] += int64(adr.Amount)
+	}
+	if ecRate > 0 {
+		for _, adr := range tx.ECOutputs {
+			dr.ecDelta[adr.ECAddress()] += int64(adr.Amount / ecRate)
+		}
+	}
+
+	return struct {
+		Message string `json:"message"`
+		TxID    *TxID  `json:"txid"`
+	}{
+		Message: "Successfully submitted the transaction",
+		TxID:    tx.ID,
+	}, nil
+}
@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockClient returns a Client whose factomd and factom-walletd requests
+// are answered with the result in results for the request method. Requests
+// for any other method fail the test.
+func newMockClient(t *testing.T, results map[string]interface{}) *Client {
+	c := NewClient()
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		var jReq jsonrpc2.Request
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		result, ok := results[jReq.Method]
+		if !ok {
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: result,
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+	c.Factomd.Client = *httpClient
+	c.Walletd.Client = *httpClient
+	return c
+}
+
+func TestDryRun(t *testing.T) {
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	fa := fs.FAAddress()
+
+	const ecRate = 1000
+	results := map[string]interface{}{
+		"entry-credit-balance": map[string]int{"balance": 12},
+		"factoid-balance":      map[string]int{"balance": 1e6},
+		"entry-credit-rate":    map[string]int{"rate": ecRate},
+	}
+
+	t.Run("ComposeCreate", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		c := newMockClient(t, results)
+		c.DryRun = new(DryRun)
+
+		// New chain, costs 11 EC.
+		e := Entry{ExtIDs: []Bytes{Bytes("dry"), Bytes("run")},
+			Content: Bytes("test")}
+		txID, err := e.ComposeCreate(context.Background(), c, es)
+		require.NoError(err)
+		assert.False(txID.IsZero())
+
+		reqs := c.DryRun.Requests()
+		require.Len(reqs, 2)
+		assert.Equal("commit-chain", reqs[0].Method)
+		assert.Equal("reveal-entry", reqs[1].Method)
+		var res struct{ TxID TxID }
+		require.NoError(json.Unmarshal(reqs[0].Result, &res))
+		assert.Equal(txID, res.TxID)
+
+		// Second entry costs 1 EC, for a total of 12 EC.
+		e2 := Entry{ChainID: e.ChainID, Content: Bytes("test")}
+		_, err = e2.ComposeCreate(context.Background(), c, es)
+		require.NoError(err)
+
+		// Third entry exceeds the balance.
+		e3 := Entry{ChainID: e.ChainID, Content: Bytes("test3")}
+		_, err = e3.ComposeCreate(context.Background(), c, es)
+		assert.EqualError(err, "factom.Client.Commit(): "+
+			"dry run: commit-entry: insufficient balance")
+		assert.Len(c.DryRun.Requests(), 4)
+
+		c.DryRun.Reset()
+		assert.Empty(c.DryRun.Requests())
+	})
+
+	t.Run("Commit/invalid", func(t *testing.T) {
+		c := newMockClient(t, results)
+		c.DryRun = &DryRun{SkipBalanceCheck: true}
+		e := Entry{ChainID: new(Bytes32), Content: Bytes("test")}
+		commit, _, _, err := e.Compose(es)
+		require.NoError(t, err)
+		commit[len(commit)-1] ^= 0xff
+		assert.EqualError(t, c.Commit(context.Background(), commit),
+			"dry run: commit-entry: invalid signature")
+	})
+
+	t.Run("Reveal/insufficient cost", func(t *testing.T) {
+		c := newMockClient(t, results)
+		c.DryRun = &DryRun{SkipBalanceCheck: true}
+		e := Entry{ChainID: new(Bytes32), Content: make(Bytes, 2000)}
+		reveal, err := e.MarshalBinary()
+		require.NoError(t, err)
+		hash := ComputeEntryHash(reveal)
+		// Commit for a shorter entry that only pays 1 EC.
+		commit, _ := GenerateCommit(es, reveal[:100], &hash, false)
+		require.NoError(t, c.Commit(context.Background(), commit))
+		assert.EqualError(t, c.Reveal(context.Background(), reveal),
+			"dry run: reveal-entry: insufficient EC cost paid by commit")
+	})
+
+	t.Run("FactoidSubmit", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		c := newMockClient(t, results)
+		c.DryRun = new(DryRun)
+
+		tx := Transaction{
+			TimestampSalt: time.Now(),
+			FCTInputs: []AddressAmount{
+				{Address: fa[:], Amount: 100000}},
+			FCTOutputs: []AddressAmount{
+				{Address: fa[:], Amount: 100000 - 12*ecRate}},
+			Signatures: make([]RCDSignature, 1),
+		}
+		data, err := tx.Sign(fs)
+		require.NoError(err)
+		fee, err := tx.RequiredFee(ecRate)
+		require.NoError(err)
+		assert.Equal(uint64(12*ecRate), fee)
+		require.NoError(c.FactoidSubmit(context.Background(), data))
+
+		reqs := c.DryRun.Requests()
+		require.Len(reqs, 1)
+		var res struct{ TxID TxID }
+		require.NoError(json.Unmarshal(reqs[0].Result, &res))
+		assert.Equal(*tx.ID, res.TxID)
+
+		// Insufficient fee.
+		tx.FCTOutputs[0].Amount++
+		data, err = tx.Sign(fs)
+		require.NoError(err)
+		assert.EqualError(c.FactoidSubmit(context.Background(), data),
+			"dry run: factoid-submit: insufficient fee: 11999 < 12000")
+
+		// Insufficient balance after the first transaction.
+		tx.FCTInputs[0].Amount = 1e6 - 1000
+		tx.FCTOutputs[0].Amount = 1e6 - 1000 - 12*ecRate
+		data, err = tx.Sign(fs)
+		require.NoError(err)
+		assert.EqualError(c.FactoidSubmit(context.Background(), data),
+			"dry run: factoid-submit: insufficient balance: "+fa.String())
+	})
+
+	t.Run("Walletd", func(t *testing.T) {
+		assert := assert.New(t)
+		c := newMockClient(t, results)
+		c.DryRun = new(DryRun)
+		assert.NoError(fs.Save(context.Background(), c))
+		assert.NoError(fa.Remove(context.Background(), c))
+		assert.EqualError(c.SavePrivateAddresses(context.Background(),
+			fa.String()),
+			"dry run: import-addresses: invalid prefix")
+		reqs := c.DryRun.Requests()
+		if assert.Len(reqs, 2) {
+			assert.Equal("factom-walletd", reqs[0].Server)
+			assert.Equal("import-addresses", reqs[0].Method)
+			assert.Equal("remove-address", reqs[1].Method)
+		}
+	})
+}
@@ -38,14 +38,14 @@ import (
 type EBlock struct {
 	// DBlock.Get populates the ChainID, KeyMR, Height and Timestamp.
 	ChainID   *Bytes32
-	KeyMR     *Bytes32  // Computed
+	KeyMR     *KeyMR    // Computed
 	Timestamp time.Time // Established by DBlock
 	Height    uint32
 
 	FullHash *Bytes32 // Computed
 
 	// Unmarshaled
-	PrevKeyMR    *Bytes32
+	PrevKeyMR    *KeyMR
 	PrevFullHash *Bytes32
 	BodyMR       *Bytes32
 	Sequence     uint32
@@ -104,17 +104,14 @@ func (eb *EBlock) Get(ctx context.Context, c *Client) error {
 		}
 	}
 
-	params := struct {
-		KeyMR *Bytes32 `json:"hash"`
-	}{KeyMR: eb.KeyMR}
-	var result struct {
-		Data Bytes `json:"data"`
+	data, stored, err := c.getRawData(ctx, Bytes32(*eb.KeyMR))
+	if err != nil {
+		return err
 	}
-
-	if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+	if err := eb.UnmarshalBinary(data); err != nil || stored {
 		return err
 	}
-	return eb.UnmarshalBinary(result.Data)
+	return c.putRawData(Bytes32(*eb.KeyMR), data)
 }
 
 // GetChainHead populates eb.KeyMR with the chain head for eb.ChainID, the
@@ -141,7 +138,7 @@ func (eb *EBlock) GetChainHead(ctx context.Context, c *Client) (bool, error) {
 		ChainInProcessList bool   `json:"chaininprocesslist"`
 	}
 
-	var keyMR Bytes32
+	var keyMR KeyMR
 	if err := c.FactomdRequest(ctx, "chain-head", params, &result); err != nil {
 		return result.ChainInProcessList, err
 	}
@@ -158,13 +155,26 @@ func (eb *EBlock) GetChainHead(ctx context.Context, c *Client) (bool, error) {
 
 // GetEntries calls eb.Get and then calls Get on each Entry in eb.Entries.
 //
-// Entries are downloaded concurrently.
+// Entries are downloaded concurrently, by no more goroutines than
+// c.Limiter allows requests.
 func (eb *EBlock) GetEntries(ctx context.Context, c *Client) error {
+	return eb.getEntries(ctx, c, func(ctx context.Context, e *Entry) error {
+		return e.Get(ctx, c)
+	})
+}
+
+// getEntries calls eb.Get and then calls get on each Entry in eb.Entries
+// concurrently.
+func (eb *EBlock) getEntries(ctx context.Context, c *Client,
+	get func(context.Context, *Entry) error) error {
 	if err := eb.Get(ctx, c); err != nil {
 		return err
 	}
 
 	n := runtime.NumCPU()
+	if limit := c.Limiter.Cap(); limit > 0 && limit < n {
+		n = limit
+	}
 	if len(eb.Entries) < n {
 		n = len(eb.Entries)
 	}
@@ -175,7 +185,7 @@ func (eb *EBlock) GetEntries(ctx context.Context, c *Client) error {
 	for i := 0; i < n; i++ {
 		g.Go(func() error {
 			for e := range entries {
-				if err := e.Get(ctx, c); err != nil {
+				if err := get(ctx, e); err != nil {
 					return err
 				}
 			}
@@ -245,7 +255,7 @@ func (eb EBlock) GetPrevAll(ctx context.Context, c *Client) ([]EBlock, error) {
 // If the beginning of the chain is reached without finding keyMR, then
 // fmt.Errorf("end of chain") is returned.
 func (eb EBlock) GetPrevBackTo(
-	ctx context.Context, c *Client, keyMR *Bytes32) ([]EBlock, error) {
+	ctx context.Context, c *Client, keyMR *KeyMR) ([]EBlock, error) {
 
 	if err := eb.Get(ctx, c); err != nil {
 		return nil, err
@@ -393,7 +403,7 @@ func (eb *EBlock) UnmarshalBinary(data []byte) error {
 	eb.BodyMR = new(Bytes32)
 	i += copy(eb.BodyMR[:], data[i:])
 
-	eb.PrevKeyMR = new(Bytes32)
+	eb.PrevKeyMR = new(KeyMR)
 	i += copy(eb.PrevKeyMR[:], data[i:])
 
 	eb.PrevFullHash = new(Bytes32)
@@ -408,20 +418,26 @@ func (eb *EBlock) UnmarshalBinary(data []byte) error {
 	eb.ObjectCount = binary.BigEndian.Uint32(data[i : i+4])
 	i += 4
 
-	if len(data[i:]) != int(eb.ObjectCount*32) {
+	if uint64(len(data[i:])) != uint64(eb.ObjectCount)*32 {
 		return fmt.Errorf("invalid length")
 	}
 
 	// Parse objects and mark indexes of minute markers.
 	objects := make([][]byte, eb.ObjectCount)
-	minuteMarkerID := make([]int, 10)
-	var numMins int
+	minuteMarkerID := []int{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
+	var numMins, lastMin int
 	for oi := range objects {
 		objects[oi] = data[i : i+len(Bytes32{})]
 		i += len(Bytes32{})
 
 		if bytes.Compare(objects[oi], min10Marker[:]) <= 0 {
+			// Minute markers must be unique and ascending.
 			minute := int(objects[oi][len(Bytes32{})-1])
+			if minute <= lastMin {
+				return fmt.Errorf("invalid minute marker %v",
+					objects[oi])
+			}
+			lastMin = minute
 			minuteMarkerID[minute-1] = oi
 			numMins++
 		}
@@ -437,13 +453,13 @@ func (eb *EBlock) UnmarshalBinary(data []byte) error {
 	}
 
 	// Populate Entries from objects.
-	eb.Entries = make([]Entry, int(eb.ObjectCount)-numMins)
+	eb.Entries = getEntries(int(eb.ObjectCount) - numMins)
 
 	// ei indexes into eb.Entries. oi indexes into objects.
 	var ei, oi int
 
 	for min, markerID := range minuteMarkerID {
-		if markerID == 0 {
+		if markerID == -1 {
 			continue
 		}
 
@@ -455,7 +471,7 @@ func (eb *EBlock) UnmarshalBinary(data []byte) error {
 			e := &eb.Entries[ei]
 			ei++
 
-			e.Hash = new(Bytes32)
+			e.Hash = new(EntryHash)
 			copy(e.Hash[:], objects[oi])
 
 			e.ChainID = eb.ChainID
@@ -581,3 +597,66 @@ func (eb *EBlock) SetTimestamp(ts time.Time) {
 	}
 	eb.Timestamp = ts
 }
+
+// EBlockMinute is the Entries of an EBlock that were included in the same
+// minute of the DBlock.
+type EBlockMinute struct {
+	// Minute is the value of the minute marker that follows the
+	// Entries, from 1 to 10.
+	Minute int
+
+	// Entries are in the order that they appear in the EBlock.
+	Entries []Entry
+}
+
+// EntryMinute returns the value of the minute marker that follows e in its
+// EBlock, from 1 to 10, which is established by e.Timestamp relative to
+// eb.Timestamp.
+//
+// An error is returned if e.Timestamp is not within the minutes of eb.
+func (eb EBlock) EntryMinute(e Entry) (int, error) {
+	offset := e.Timestamp.Sub(eb.Timestamp)
+	if offset%MinuteDuration != 0 {
+		return 0, fmt.Errorf("invalid entry timestamp")
+	}
+	min := int(offset / MinuteDuration)
+	if min < 1 || min > 10 {
+		return 0, fmt.Errorf("invalid entry timestamp")
+	}
+	return min, nil
+}
+
+// Minutes returns eb.Entries grouped by their minute markers, in order. Only
+// minutes that contain Entries are included, and the Entries of each
+// EBlockMinute share the underlying array of eb.Entries.
+//
+// Entries in an earlier minute were acknowledged before any Entries in a later
+// minute, so protocols that rely on ordering within an EBlock may use the
+// minute as a coarse unit of time.
+func (eb EBlock) Minutes() ([]EBlockMinute, error) {
+	var minutes []EBlockMinute
+	start := 0
+	for i, e := range eb.Entries {
+		min, err := eb.EntryMinute(e)
+		if err != nil {
+			return nil, fmt.Errorf("entry %v: %w", i, err)
+		}
+		if len(minutes) > 0 {
+			last := &minutes[len(minutes)-1]
+			if min < last.Minute {
+				return nil, fmt.Errorf(
+					"entry %v: minute out of order", i)
+			}
+			if min == last.Minute {
+				continue
+			}
+			last.Entries = eb.Entries[start:i]
+		}
+		minutes = append(minutes, EBlockMinute{Minute: min})
+		start = i
+	}
+	if len(minutes) > 0 {
+		minutes[len(minutes)-1].Entries = eb.Entries[start:]
+	}
+	return minutes, nil
+}
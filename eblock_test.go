@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEBlockMinutes(t *testing.T) {
+	chainID := Bytes32{1}
+	// Entries by minute marker.
+	byMinute := map[int][]Bytes32{
+		1:  {{1}, {2}},
+		4:  {{3}},
+		10: {{4}, {5}, {6}},
+	}
+	var objects [][]byte
+	for min := 1; min <= 10; min++ {
+		hashes, ok := byMinute[min]
+		if !ok {
+			continue
+		}
+		for _, hash := range hashes {
+			hash := hash
+			objects = append(objects, hash[:])
+		}
+		objects = append(objects, (&Bytes32{31: byte(min)})[:])
+	}
+	bodyMR, err := ComputeEBlockBodyMR(objects)
+	require.NoError(t, err)
+	data := make([]byte, EBlockHeaderSize)
+	i := copy(data, chainID[:])
+	copy(data[i:], bodyMR[:])
+	binary.BigEndian.PutUint32(data[EBlockHeaderSize-4:],
+		uint32(len(objects)))
+	for _, obj := range objects {
+		data = append(data, obj...)
+	}
+
+	var eb EBlock
+	require.NoError(t, eb.UnmarshalBinary(data))
+	eb.SetTimestamp(time.Unix(1500000000, 0))
+
+	minutes, err := eb.Minutes()
+	require.NoError(t, err)
+	require.Len(t, minutes, 3)
+	for i, min := range []int{1, 4, 10} {
+		assert.Equal(t, min, minutes[i].Minute)
+		require.Len(t, minutes[i].Entries, len(byMinute[min]))
+		for j, e := range minutes[i].Entries {
+			assert.Equal(t, EntryHash(byMinute[min][j]), *e.Hash)
+			entryMin, err := eb.EntryMinute(e)
+			require.NoError(t, err)
+			assert.Equal(t, min, entryMin)
+		}
+	}
+
+	// The minutes are preserved by MarshalBinary.
+	eb.ClearMarshalBinaryCache()
+	marshaled, err := eb.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, marshaled)
+
+	minutes, err = EBlock{}.Minutes()
+	require.NoError(t, err)
+	assert.Empty(t, minutes)
+
+	eb.Entries[0].Timestamp = eb.Timestamp.Add(11 * MinuteDuration)
+	_, err = eb.Minutes()
+	assert.EqualError(t, err, "entry 0: invalid entry timestamp")
+
+	eb.Entries[0].Timestamp = eb.Timestamp.Add(5 * MinuteDuration)
+	_, err = eb.Minutes()
+	assert.EqualError(t, err, "entry 1: minute out of order")
+}
+
+func TestEBlockKeyMRBuilder(t *testing.T) {
+	b := EBlockKeyMRBuilder{
+		ChainID:      Bytes32{1},
+		PrevKeyMR:    KeyMR{2},
+		PrevFullHash: Bytes32{3},
+		Sequence:     4,
+		Height:       5,
+	}
+	var objects [][]byte
+	for i := 1; i <= 3; i++ {
+		hash := EntryHash{byte(i)}
+		b.AddEntry(hash)
+		objects = append(objects, hash[:])
+	}
+	b.AddMinuteMarker(3)
+	objects = append(objects, (&Bytes32{31: 3})[:])
+	assert.Equal(t, uint32(len(objects)), b.ObjectCount())
+
+	bodyMR, err := ComputeEBlockBodyMR(objects)
+	require.NoError(t, err)
+	assert.Equal(t, bodyMR, b.BodyMR())
+
+	data := make([]byte, EBlockHeaderSize)
+	i := copy(data, b.ChainID[:])
+	i += copy(data[i:], bodyMR[:])
+	i += copy(data[i:], b.PrevKeyMR[:])
+	i += copy(data[i:], b.PrevFullHash[:])
+	binary.BigEndian.PutUint32(data[i:], b.Sequence)
+	binary.BigEndian.PutUint32(data[i+4:], b.Height)
+	binary.BigEndian.PutUint32(data[i+8:], b.ObjectCount())
+	for _, obj := range objects {
+		data = append(data, obj...)
+	}
+	var eb EBlock
+	require.NoError(t, eb.UnmarshalBinary(data))
+	assert.Equal(t, *eb.KeyMR, b.KeyMR())
+}
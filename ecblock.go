@@ -0,0 +1,266 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom/varintf"
+)
+
+// ECBlock represents a Factom Entry Credit Block, which records the Entry and
+// Chain commits, and the Entry Credit purchases, of a DBlock.
+//
+// The signature and EC cost of every Commit are verified by UnmarshalBinary,
+// and reported by the Commit's ValidSignature and ValidCost, so that
+// malformed commits may be detected without running a full node.
+type ECBlock struct {
+	// HeaderHash, if not nil, is used by Get instead of Height.
+	HeaderHash *Bytes32
+
+	// Header Fields
+	BodyHash       Bytes32
+	PrevHeaderHash Bytes32
+	PrevFullHash   Bytes32
+	Height         uint32
+
+	// Expansion is the raw header expansion area.
+	Expansion Bytes
+
+	// Body Fields
+
+	// Commits are the Entry and Chain commits in the order that they
+	// appear in the ECBlock.
+	Commits []Commit
+
+	// Purchases are the Entry Credit purchases in the order that they
+	// appear in the ECBlock.
+	Purchases []ECPurchase
+}
+
+// ECPurchase is an Entry Credit purchase by an EC output of a Factoid
+// Transaction, which is recorded in an ECBlock.
+type ECPurchase struct {
+	ECAddress ECAddress
+	TxID      TxID
+
+	// Index is the index of the EC output within the Transaction.
+	Index uint64
+
+	// Amount is the number of Entry Credits purchased.
+	Amount uint64
+}
+
+// Types of the objects in the body of an ECBlock.
+const (
+	ecIDServerIndexNumber byte = iota
+	ecIDMinuteNumber
+	ecIDChainCommit
+	ecIDEntryCommit
+	ecIDBalanceIncrease
+)
+
+// ECBlockHeaderMinSize is the minimum length of an ECBlock header.
+const ECBlockHeaderMinSize = 32 + // EC Block ChainID
+	32 + // BodyHash
+	32 + // PrevHeaderHash
+	32 + // PrevFullHash
+	4 + // DB Height
+	1 + // Header Expansion Size (varint)
+	8 + // Object Count
+	8 // Body Size
+
+// Get queries factomd for the Entry Credit Block at ecb.HeaderHash, if not
+// nil, or otherwise at ecb.Height.
+func (ecb *ECBlock) Get(ctx context.Context, c *Client) error {
+	if ecb.HeaderHash != nil {
+		params := struct {
+			Hash *Bytes32 `json:"hash"`
+		}{Hash: ecb.HeaderHash}
+		var result struct {
+			Data Bytes `json:"data"`
+		}
+		if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+			return err
+		}
+		return ecb.UnmarshalBinary(result.Data)
+	}
+
+	params := struct {
+		Height uint32 `json:"height"`
+	}{ecb.Height}
+	var result struct {
+		RawData Bytes `json:"rawdata"`
+	}
+	if err := c.FactomdRequest(ctx, "ecblock-by-height", params, &result); err != nil {
+		return err
+	}
+	return ecb.UnmarshalBinary(result.RawData)
+}
+
+// UnmarshalBinary unmarshals raw ECBlock data and verifies the BodyHash. The
+// ECBlock.HeaderHash is computed. The data format is as follows.
+//
+// Header
+//
+//	[EC Block ChainID (Bytes32{31:0x0c})] +
+//	[BodyHash (Bytes32)] +
+//	[PrevHeaderHash (Bytes32)] +
+//	[PrevFullHash (Bytes32)] +
+//	[DB Height (4 bytes)] +
+//	[Header Expansion Size (varint)] +
+//	[Header Expansion Area (Header Expansion Size bytes)] +
+//	[Object Count (8 bytes)] +
+//	[Body Size (8 bytes)] +
+//
+// Body
+//
+//	[Object Type (1 byte)] + [Object (Bytes)] +
+//	... +
+//
+// The Object Types are Server Index Number (0x00, 1 byte), Minute Number
+// (0x01, 1 byte), Chain Commit (0x02), Entry Commit (0x03), and Balance
+// Increase (0x04), which is [EC Public Key (Bytes32)] + [TxID (Bytes32)] +
+// [Index (varint)] + [Amount (varint)].
+//
+// https://github.com/FactomProject/FactomDocs/blob/master/factomDataStructureDetails.md#entry-credit-block
+func (ecb *ECBlock) UnmarshalBinary(data []byte) error {
+	if len(data) < ECBlockHeaderMinSize {
+		return fmt.Errorf("insufficient length")
+	}
+	if bytes.Compare(data[:32], ecBlockChainID[:]) != 0 {
+		return fmt.Errorf("invalid entry credit chainid")
+	}
+
+	i := 32
+	i += copy(ecb.BodyHash[:], data[i:])
+	i += copy(ecb.PrevHeaderHash[:], data[i:])
+	i += copy(ecb.PrevFullHash[:], data[i:])
+	ecb.Height = binary.BigEndian.Uint32(data[i:])
+	i += 4
+
+	expansionSize, read := varintf.Decode(data[i:])
+	if read <= 0 {
+		return fmt.Errorf("expansion size is not a valid varint")
+	}
+	i += read
+	if uint64(len(data[i:])) < expansionSize+8+8 {
+		return fmt.Errorf("insufficient length")
+	}
+	ecb.Expansion = append(Bytes(nil), data[i:i+int(expansionSize)]...)
+	i += int(expansionSize)
+
+	objectCount := binary.BigEndian.Uint64(data[i:])
+	i += 8
+	bodySize := binary.BigEndian.Uint64(data[i:])
+	i += 8
+
+	headerHash := Bytes32(sha256Sum(data[:i]))
+	ecb.HeaderHash = &headerHash
+
+	if uint64(len(data[i:])) < bodySize {
+		return fmt.Errorf("insufficient length")
+	}
+	body := data[i : i+int(bodySize)]
+	if Bytes32(sha256Sum(body)) != ecb.BodyHash {
+		return fmt.Errorf("invalid BodyHash")
+	}
+
+	ecb.Commits, ecb.Purchases = nil, nil
+	var signed []Signed
+	for j := uint64(0); j < objectCount; j++ {
+		if len(body) == 0 {
+			return fmt.Errorf("insufficient length")
+		}
+		id := body[0]
+		body = body[1:]
+
+		var size int
+		switch id {
+		case ecIDServerIndexNumber, ecIDMinuteNumber:
+			size = 1
+		case ecIDChainCommit, ecIDEntryCommit:
+			size = EntryCommitSize
+			if id == ecIDChainCommit {
+				size = ChainCommitSize
+			}
+			if len(body) < size {
+				return fmt.Errorf("insufficient length")
+			}
+			var cmt Commit
+			s, err := cmt.unmarshalBinary(body[:size])
+			if err != nil {
+				return err
+			}
+			ecb.Commits = append(ecb.Commits, cmt)
+			signed = append(signed, s)
+		case ecIDBalanceIncrease:
+			var p ECPurchase
+			size = len(p.ECAddress) + len(p.TxID)
+			if len(body) < size {
+				return fmt.Errorf("insufficient length")
+			}
+			copy(p.ECAddress[:], body)
+			copy(p.TxID[:], body[len(p.ECAddress):])
+			if p.Index, read = varintf.Decode(body[size:]); read <= 0 {
+				return fmt.Errorf("index is not a valid varint")
+			}
+			size += read
+			if p.Amount, read = varintf.Decode(body[size:]); read <= 0 {
+				return fmt.Errorf("amount is not a valid varint")
+			}
+			size += read
+			ecb.Purchases = append(ecb.Purchases, p)
+		default:
+			return fmt.Errorf("invalid object type: %#x", id)
+		}
+		if len(body) < size {
+			return fmt.Errorf("insufficient length")
+		}
+		body = body[size:]
+	}
+	if len(body) > 0 {
+		return fmt.Errorf("invalid body size")
+	}
+
+	for j, valid := range VerifyAll(signed) {
+		ecb.Commits[j].ValidSignature = valid
+	}
+
+	return nil
+}
+
+// InvalidCommits returns the Commits in ecb that do not have a valid
+// signature or EC cost.
+func (ecb ECBlock) InvalidCommits() []Commit {
+	var invalid []Commit
+	for _, cmt := range ecb.Commits {
+		if cmt.Valid() != nil {
+			invalid = append(invalid, cmt)
+		}
+	}
+	return invalid
+}
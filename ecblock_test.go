@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/varintf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECBlock(t *testing.T) {
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+	chainID := ComputeChainID([]Bytes{Bytes("ecblock")})
+
+	var commits [][]byte
+	for _, e := range []Entry{
+		{ChainID: &chainID, Content: Bytes("valid")},
+		{ChainID: &chainID, Content: Bytes("invalid")},
+		{ExtIDs: []Bytes{Bytes("ecblock")}},
+	} {
+		commit, _, _, err := e.Compose(es)
+		require.NoError(t, err)
+		commits = append(commits, commit)
+	}
+	commits[1][len(commits[1])-1] ^= 1
+
+	body := []byte{0x00, 0x00} // Server Index Number
+	body = append(body, 0x03)
+	body = append(body, commits[0]...)
+	body = append(body, 0x03)
+	body = append(body, commits[1]...)
+	body = append(body, 0x01, 0x01) // Minute Number
+	body = append(body, 0x02)
+	body = append(body, commits[2]...)
+	body = append(body, 0x04)
+	ec := es.ECAddress()
+	txID := TxID{1}
+	body = append(body, ec[:]...)
+	body = append(body, txID[:]...)
+	body = append(body, varintf.Encode(2)...)
+	body = append(body, varintf.Encode(1000)...)
+	objectCount := 6
+
+	bodyHash := sha256.Sum256(body)
+	ecChainID := ECBlockChainID()
+	data := append([]byte{}, ecChainID[:]...)
+	data = append(data, bodyHash[:]...)
+	data = append(data, make([]byte, 64)...) // PrevHeaderHash, PrevFullHash
+	data = append(data, 0, 0, 0, 10)         // DB Height
+	data = append(data, 0x00)                // Header Expansion Size
+	data = append(data, make([]byte, 16)...)
+	binary.BigEndian.PutUint64(data[len(data)-16:], uint64(objectCount))
+	binary.BigEndian.PutUint64(data[len(data)-8:], uint64(len(body)))
+	headerHash := Bytes32(sha256.Sum256(data))
+	data = append(data, body...)
+
+	c := newMockClient(t, map[string]interface{}{
+		"ecblock-by-height": map[string]interface{}{
+			"rawdata": Bytes(data)}})
+	ecb := ECBlock{Height: 10}
+	require.NoError(t, ecb.Get(context.Background(), c))
+
+	assert := assert.New(t)
+	assert.Equal(uint32(10), ecb.Height)
+	assert.Equal(headerHash, *ecb.HeaderHash)
+	require.Len(t, ecb.Commits, 3)
+	assert.True(ecb.Commits[0].ValidSignature)
+	assert.False(ecb.Commits[1].ValidSignature)
+	assert.True(ecb.Commits[2].NewChain())
+	assert.Equal([]Commit{ecb.Commits[1]}, ecb.InvalidCommits())
+	assert.Equal([]ECPurchase{{ECAddress: ec, TxID: txID,
+		Index: 2, Amount: 1000}}, ecb.Purchases)
+
+	data[len(data)-1] ^= 1
+	assert.EqualError(ecb.UnmarshalBinary(data), "invalid BodyHash")
+	assert.EqualError(ecb.UnmarshalBinary(data[:100]),
+		"insufficient length")
+}
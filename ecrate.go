@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"sync"
+)
+
+// GetECRate queries factomd for the current Entry Credit exchange rate in
+// factoshis per Entry Credit.
+func (c *Client) GetECRate(ctx context.Context) (uint64, error) {
+	var result struct{ Rate Amount }
+	if err := c.FactomdRequest(ctx, "entry-credit-rate", nil, &result); err != nil {
+		return 0, err
+	}
+	return uint64(result.Rate), nil
+}
+
+// ECRateCache caches the Entry Credit exchange rates of FBlocks by height for
+// Client.ECRateAt. Since a saved FBlock never changes, entries are never
+// invalidated, and a single ECRateCache may be shared by many Clients on the
+// same network. It is safe for concurrent use.
+//
+// The zero value is ready to use. A nil *ECRateCache caches nothing.
+type ECRateCache struct {
+	mu    sync.RWMutex
+	rates map[uint32]uint64
+}
+
+// Len returns the number of cached rates.
+func (rc *ECRateCache) Len() int {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return len(rc.rates)
+}
+
+func (rc *ECRateCache) get(height uint32) (uint64, bool) {
+	if rc == nil {
+		return 0, false
+	}
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	rate, ok := rc.rates[height]
+	return rate, ok
+}
+
+func (rc *ECRateCache) add(height uint32, rate uint64) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.rates == nil {
+		rc.rates = make(map[uint32]uint64)
+	}
+	rc.rates[height] = rate
+}
+
+// ECRateAt returns the Entry Credit exchange rate in factoshis per Entry
+// Credit that applied to the transactions in the FBlock at height, as recorded
+// in its header. Unlike GetECRate, this is the correct rate for cost
+// accounting of past blocks. Use Client.ECRateCache to avoid fetching the
+// FBlock again.
+func (c *Client) ECRateAt(ctx context.Context, height uint32) (uint64, error) {
+	if rate, ok := c.ECRateCache.get(height); ok {
+		return rate, nil
+	}
+	fb, err := c.FBlockByHeight(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+	c.ECRateCache.add(height, fb.ECExchangeRate)
+	return fb.ECExchangeRate, nil
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECRateAt(t *testing.T) {
+	var requests int
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq jsonrpc2.Request
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		requests++
+
+		var params struct{ Height uint32 }
+		data, _ := json.Marshal(jReq.Params)
+		_ = json.Unmarshal(data, &params)
+		fb := mockFBlockData(t, params.Height)
+		binary.BigEndian.PutUint64(fb[128:], 1000*uint64(params.Height))
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: map[string]Bytes{"rawdata": fb},
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	assert := assert.New(t)
+	rate, err := c.ECRateAt(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(uint64(5000), rate)
+
+	c.ECRateCache = new(ECRateCache)
+	for i := 0; i < 2; i++ {
+		rate, err = c.ECRateAt(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(uint64(7000), rate)
+	}
+	assert.Equal(2, requests)
+	assert.Equal(1, c.ECRateCache.Len())
+}
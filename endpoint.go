@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Default ports and path of the factomd and factom-walletd APIs.
+const (
+	FactomdDefaultPort = 8088
+	WalletdDefaultPort = 8089
+	DefaultAPIPath     = "/v2"
+)
+
+// Endpoint describes the URL of a factomd or factom-walletd API, for hosted
+// providers and reverse proxies that remap the default ports or paths.
+//
+// Use Endpoint.FactomdURL and Endpoint.WalletdURL to set
+// Client.FactomdServer and Client.WalletdServer.
+type Endpoint struct {
+	// Scheme is the URL scheme. If empty, "http" is used.
+	Scheme string
+
+	// Host is the host name or IP address. If empty, "localhost" is used.
+	Host string
+
+	// Port is the TCP port. If zero, the default port of the API is used,
+	// unless Scheme is "https", in which case the port is omitted.
+	Port int
+
+	// Path is the path of the JSON-RPC API. If empty, DefaultAPIPath is
+	// used.
+	Path string
+}
+
+// FactomdURL returns the URL of e with FactomdDefaultPort as the default.
+func (e Endpoint) FactomdURL() string {
+	return e.url(FactomdDefaultPort)
+}
+
+// WalletdURL returns the URL of e with WalletdDefaultPort as the default.
+func (e Endpoint) WalletdURL() string {
+	return e.url(WalletdDefaultPort)
+}
+
+func (e Endpoint) url(defaultPort int) string {
+	u := url.URL{Scheme: e.Scheme, Host: e.Host, Path: e.Path}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	if u.Host == "" {
+		u.Host = "localhost"
+	}
+	port := e.Port
+	if port == 0 && u.Scheme != "https" {
+		port = defaultPort
+	}
+	if port != 0 {
+		u.Host = net.JoinHostPort(u.Host, strconv.Itoa(port))
+	}
+	if u.Path == "" {
+		u.Path = DefaultAPIPath
+	}
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path
+	}
+	return u.String()
+}
+
+// ExpandURL replaces each {name} in template with the query escaped value
+// of vars[name], for endpoints that embed an API key in their path or query,
+// such as "https://{network}.example.com/{apikey}/v2". The result must be a
+// valid absolute URL.
+func ExpandURL(template string, vars map[string]string) (string, error) {
+	var expanded strings.Builder
+	for s := template; len(s) > 0; {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			expanded.WriteString(s)
+			break
+		}
+		if s[i] == '}' {
+			return "", fmt.Errorf("unexpected '}' in url template")
+		}
+		expanded.WriteString(s[:i])
+		s = s[i+1:]
+		j := strings.IndexAny(s, "{}")
+		if j < 0 || s[j] != '}' {
+			return "", fmt.Errorf("unclosed '{' in url template")
+		}
+		name := s[:j]
+		value, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("undefined url template variable: %q",
+				name)
+		}
+		expanded.WriteString(url.QueryEscape(value))
+		s = s[j+1:]
+	}
+
+	u, err := url.Parse(expanded.String())
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("url template does not expand to an absolute url")
+	}
+	return u.String(), nil
+}
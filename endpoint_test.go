@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoint(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Endpoint Endpoint
+		Factomd  string
+		Walletd  string
+	}{{
+		Name:    "default",
+		Factomd: FactomdDefault,
+		Walletd: WalletdDefault,
+	}, {
+		Name:     "port",
+		Endpoint: Endpoint{Host: "10.0.0.1", Port: 9000},
+		Factomd:  "http://10.0.0.1:9000/v2",
+		Walletd:  "http://10.0.0.1:9000/v2",
+	}, {
+		Name:     "https",
+		Endpoint: Endpoint{Scheme: "https", Host: "example.com", Path: "factomd/v2"},
+		Factomd:  "https://example.com/factomd/v2",
+		Walletd:  "https://example.com/factomd/v2",
+	}, {
+		Name:     "ipv6",
+		Endpoint: Endpoint{Host: "::1"},
+		Factomd:  "http://[::1]:8088/v2",
+		Walletd:  "http://[::1]:8089/v2",
+	}} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Factomd, test.Endpoint.FactomdURL())
+			assert.Equal(t, test.Walletd, test.Endpoint.WalletdURL())
+		})
+	}
+}
+
+func TestExpandURL(t *testing.T) {
+	vars := map[string]string{"network": "mainnet", "apikey": "a/b&c"}
+	for _, test := range []struct {
+		Name     string
+		Template string
+		URL      string
+		Err      string
+	}{{
+		Name:     "valid",
+		Template: "https://{network}.example.com/{apikey}/v2?key={apikey}",
+		URL:      "https://mainnet.example.com/a%2Fb%26c/v2?key=a%2Fb%26c",
+	}, {
+		Name:     "no vars",
+		Template: FactomdDefault,
+		URL:      FactomdDefault,
+	}, {
+		Name:     "undefined",
+		Template: "https://example.com/{key}/v2",
+		Err:      `undefined url template variable: "key"`,
+	}, {
+		Name:     "unclosed",
+		Template: "https://example.com/{apikey/v2",
+		Err:      "unclosed '{' in url template",
+	}, {
+		Name:     "unexpected",
+		Template: "https://example.com/apikey}/v2",
+		Err:      "unexpected '}' in url template",
+	}, {
+		Name:     "relative",
+		Template: "/{apikey}/v2",
+		Err:      "url template does not expand to an absolute url",
+	}} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			u, err := ExpandURL(test.Template, vars)
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.URL, u)
+		})
+	}
+}
@@ -24,7 +24,6 @@ package factom
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -41,9 +40,9 @@ import (
 type Entry struct {
 	// An Entry in EBlock.Entries after a successful call to EBlock.Get has
 	// its ChainID, Hash, and Timestamp.
-	ChainID   *Bytes32  `json:"chainid,omitempty"`
-	Hash      *Bytes32  `json:"entryhash,omitempty"`
-	Timestamp time.Time `json:"-"` // Established by EBlock
+	ChainID   *Bytes32   `json:"chainid,omitempty"`
+	Hash      *EntryHash `json:"entryhash,omitempty"`
+	Timestamp time.Time  `json:"-"` // Established by EBlock
 
 	// Entry.Get populates the Content and ExtIDs.
 	ExtIDs  []Bytes `json:"extids"`
@@ -52,6 +51,9 @@ type Entry struct {
 	// marshalBinaryCache is the binary data of the Entry. It is cached by
 	// UnmarshalBinary so it can be re-used by MarshalBinary.
 	marshalBinaryCache []byte
+
+	// pooled is true if the marshalBinaryCache may be recycled.
+	pooled bool
 }
 
 // ClearMarshalBinaryCache discards the cached MarshalBinary data.
@@ -60,6 +62,7 @@ type Entry struct {
 // of the Entry.
 func (e *Entry) ClearMarshalBinaryCache() {
 	e.marshalBinaryCache = nil
+	e.pooled = false
 }
 
 // IsPopulated returns true if e has already been successfully populated by a
@@ -85,17 +88,21 @@ func (e *Entry) Get(ctx context.Context, c *Client) error {
 		return fmt.Errorf("Hash is nil")
 	}
 
-	params := struct {
-		Hash *Bytes32 `json:"hash"`
-	}{Hash: e.Hash}
-	var result struct {
-		Data Bytes `json:"data"`
+	data, pooled, stored, err := c.getEntryRawData(ctx, Bytes32(*e.Hash))
+	if err != nil {
+		return err
 	}
-
-	if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+	if err := e.UnmarshalBinary(data); err != nil {
+		if pooled {
+			putEntryData(data)
+		}
 		return err
 	}
-	return e.UnmarshalBinary(result.Data)
+	e.pooled = pooled
+	if pooled || stored {
+		return nil
+	}
+	return c.putRawData(Bytes32(*e.Hash), data)
 }
 
 type chainFirstEntryParams struct {
@@ -119,7 +126,7 @@ type composeResult struct {
 	Reveal composeJRPC `json:"reveal"`
 }
 type commitResult struct {
-	TxID *Bytes32
+	TxID *TxID
 }
 
 // Create queries factom-walletd to compose e as a new Entry, and then queries
@@ -130,7 +137,15 @@ type commitResult struct {
 //
 // If successful, the commit transaction ID is returned and e.Hash and
 // e.ChainID will be populated.
-func (e *Entry) Create(ctx context.Context, c *Client, ec ECAddress) (Bytes32, error) {
+//
+// Unless c.DisableEntryValidation is set, e.Valid is checked first. If
+// c.SkipExistingEntries is set and e already exists, ErrorEntryExists is
+// returned and nothing is submitted.
+func (e *Entry) Create(ctx context.Context, c *Client, ec ECAddress) (TxID, error) {
+	if err := e.checkCreate(ctx, c); err != nil {
+		return TxID{}, err
+	}
+
 	var params interface{}
 	var method string
 
@@ -147,21 +162,21 @@ func (e *Entry) Create(ctx context.Context, c *Client, ec ECAddress) (Bytes32, e
 	result := composeResult{}
 
 	if err := c.WalletdRequest(ctx, method, params, &result); err != nil {
-		return Bytes32{}, err
+		return TxID{}, err
 	}
 	if len(result.Commit.Method) == 0 {
-		return Bytes32{}, fmt.Errorf("Wallet request error: method: %#v", method)
+		return TxID{}, fmt.Errorf("Wallet request error: method: %#v", method)
 	}
 
 	var commit commitResult
 	if err := c.FactomdRequest(ctx,
 		result.Commit.Method, result.Commit.Params, &commit); err != nil {
-		return Bytes32{}, err
+		return TxID{}, err
 	}
 
 	if err := c.FactomdRequest(ctx,
 		result.Reveal.Method, result.Reveal.Params, e); err != nil {
-		return Bytes32{}, err
+		return TxID{}, err
 	}
 	return *commit.TxID, nil
 }
@@ -176,13 +191,21 @@ func (e *Entry) Create(ctx context.Context, c *Client, ec ECAddress) (Bytes32, e
 // If e.ChainID == nil, a new chain will be created, and e.ChainID will be
 // populated.
 //
+// Unless c.DisableEntryValidation is set, e.Valid is checked first. If
+// c.SkipExistingEntries is set and e already exists, ErrorEntryExists is
+// returned and nothing is submitted.
+//
 // If successful, the Transaction ID is returned.
 func (e *Entry) ComposeCreate(
-	ctx context.Context, c *Client, es EsAddress) (Bytes32, error) {
+	ctx context.Context, c *Client, es EsAddress) (TxID, error) {
+
+	if err := e.checkCreate(ctx, c); err != nil {
+		return TxID{}, err
+	}
 
-	commit, reveal, txID, err := e.Compose(es)
+	commit, reveal, txID, err := e.compose(es, c.commitTime())
 	if err != nil {
-		return Bytes32{}, fmt.Errorf("factom.Entry.Compose(): %w", err)
+		return TxID{}, fmt.Errorf("factom.Entry.Compose(): %w", err)
 	}
 
 	if err := c.Commit(ctx, commit); err != nil {
@@ -195,6 +218,22 @@ func (e *Entry) ComposeCreate(
 	return txID, nil
 }
 
+// checkCreate checks e.Valid, unless c.DisableEntryValidation is set, and
+// checks that e does not already exist, if c.SkipExistingEntries is set.
+func (e *Entry) checkCreate(ctx context.Context, c *Client) error {
+	if !c.DisableEntryValidation {
+		if err := e.Valid(); err != nil {
+			return fmt.Errorf("factom.Entry.Valid(): %w", err)
+		}
+	}
+	if c.SkipExistingEntries {
+		if err := e.checkExists(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Commit sends an entry or new chain commit to factomd.
 func (c *Client) Commit(ctx context.Context, commit []byte) error {
 	var method string
@@ -207,6 +246,10 @@ func (c *Client) Commit(ctx context.Context, commit []byte) error {
 		return fmt.Errorf("invalid commit length")
 	}
 
+	if err := c.checkNetwork(ctx); err != nil {
+		return err
+	}
+
 	params := struct {
 		Commit Bytes `json:"message"`
 	}{Commit: commit}
@@ -240,8 +283,25 @@ func (c *Client) Reveal(ctx context.Context, reveal []byte) error {
 // If the reveal is already available to the caller, use GenerateCommit to
 // create the commit without recreating the reveal, which is simply the raw
 // data of an Entry.
+//
+// The commit timestamp is the local time.Now. Use Client.ComposeEntry to use
+// the Clock and ClockSkew of a Client instead, or ComposeAt for any other
+// timestamp.
 func (e *Entry) Compose(es EsAddress) (
-	commit []byte, reveal []byte, txID Bytes32, err error) {
+	commit []byte, reveal []byte, txID TxID, err error) {
+	return e.compose(es, time.Now())
+}
+
+// ComposeEntry is like e.Compose but the commit timestamp is the time of
+// c.Clock corrected by c.ClockSkew, as used by Entry.ComposeCreate.
+func (c *Client) ComposeEntry(e *Entry, es EsAddress) (
+	commit []byte, reveal []byte, txID TxID, err error) {
+	return e.compose(es, c.commitTime())
+}
+
+// compose is like Compose but uses ts for the commit timestamp.
+func (e *Entry) compose(es EsAddress, ts time.Time) (
+	commit []byte, reveal []byte, txID TxID, err error) {
 
 	newChain := e.ChainID == nil
 
@@ -257,11 +317,11 @@ func (e *Entry) Compose(es EsAddress) (
 	}
 
 	if e.Hash == nil {
-		e.Hash = new(Bytes32)
+		e.Hash = new(EntryHash)
 		*e.Hash = ComputeEntryHash(reveal)
 	}
 
-	commit, txID = GenerateCommit(es, reveal, e.Hash, newChain)
+	commit, txID, _ = generateCommit(es, reveal, e.Hash, newChain, ts)
 	return
 }
 
@@ -312,8 +372,36 @@ const (
 //	[EC Cost (1 byte)] +
 //	[EC Public Key (32 Bytes)] +
 //	[Signature of data up to and including EC Cost (64 Bytes)]
-func GenerateCommit(es EsAddress, entrydata []byte, hash *Bytes32,
-	newChain bool) ([]byte, Bytes32) {
+func GenerateCommit(es EsAddress, entrydata []byte, hash *EntryHash,
+	newChain bool) ([]byte, TxID) {
+	commit, txID, _ := GenerateCommitWithSigner(es, entrydata, hash, newChain)
+	return commit, txID
+}
+
+// CommitSigner is the interface implemented by types that can sign Entry and
+// Chain commits, such as EsAddress.
+type CommitSigner interface {
+	// PublicKey returns the ed25519.PublicKey of the Entry Credit address
+	// that pays for the commit.
+	PublicKey() ed25519.PublicKey
+
+	// Sign the msg. A nil or otherwise invalid signature indicates that
+	// signing failed.
+	Sign(msg []byte) []byte
+}
+
+// GenerateCommitWithSigner is like GenerateCommit, but the commit is signed by
+// signer, which need not hold the private key locally. An error is returned if
+// the signer does not produce a public key and signature of the correct size.
+func GenerateCommitWithSigner(signer CommitSigner, entrydata []byte,
+	hash *EntryHash, newChain bool) ([]byte, TxID, error) {
+	return generateCommit(signer, entrydata, hash, newChain, time.Now())
+}
+
+// generateCommit is like GenerateCommitWithSigner but uses ts for the
+// timestamp salt.
+func generateCommit(signer CommitSigner, entrydata []byte,
+	hash *EntryHash, newChain bool, ts time.Time) ([]byte, TxID, error) {
 
 	commitSize := EntryCommitSize
 	if newChain {
@@ -324,10 +412,10 @@ func GenerateCommit(es EsAddress, entrydata []byte, hash *Bytes32,
 
 	i := 1 // Skip version byte
 
-	// ms is a timestamp salt in milliseconds.
-	ms := time.Now().Unix()*1e3 + rand.Int63n(1000)
-	putInt48BE(commit[i:], ms)
-	i += 6
+	// Salt the whole second of ts with a random number of milliseconds.
+	salt := time.Duration(rand.Int63n(1000)) * time.Millisecond
+	PutTimestamp(commit[i:], ts.Truncate(time.Second).Add(salt))
+	i += TimestampSize
 
 	if newChain {
 		chainID := entrydata[1 : 1+len(Bytes32{})]
@@ -347,17 +435,22 @@ func GenerateCommit(es EsAddress, entrydata []byte, hash *Bytes32,
 	commit[i] = byte(cost)
 	i++
 
-	txID := sha256.Sum256(commit[:i])
+	txID := sha256Sum(commit[:i])
 
 	// Public Key
 	signedDataSize := i
-	i += copy(commit[i:], es.PublicKey())
+	pubKey := signer.PublicKey()
+	i += copy(commit[i:], pubKey)
 
 	// Signature
-	sig := ed25519.Sign(es.PrivateKey(), commit[:signedDataSize])
+	sig := signer.Sign(commit[:signedDataSize])
+	if len(pubKey) != ed25519.PublicKeySize ||
+		len(sig) != ed25519.SignatureSize {
+		return nil, TxID{}, fmt.Errorf("signing failed")
+	}
 	copy(commit[i:], sig)
 
-	return commit, txID
+	return commit, txID, nil
 }
 
 // NewChainCost is the fixed added cost of creating a new chain.
@@ -512,6 +605,9 @@ func (e *Entry) UnmarshalBinary(data []byte) error {
 	e.ExtIDs = e.ExtIDs[0:0]
 
 	for i < EntryHeaderSize+extIDTotalSize {
+		if i+2 > EntryHeaderSize+extIDTotalSize {
+			return fmt.Errorf("error parsing ExtIDs")
+		}
 		extIDSize := int(binary.BigEndian.Uint16(data[i : i+2]))
 		if i+2+extIDSize > EntryHeaderSize+extIDTotalSize {
 			return fmt.Errorf("error parsing ExtIDs")
@@ -540,6 +636,7 @@ func (e *Entry) UnmarshalBinary(data []byte) error {
 
 	// Cache data for efficient marshaling.
 	e.marshalBinaryCache = data
+	e.pooled = false
 
 	return nil
 }
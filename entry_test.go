@@ -27,7 +27,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/AdamSLevy/jsonrpc2/v14"
 	. "github.com/Factom-Asset-Tokens/factom"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,8 +38,8 @@ var marshalBinaryTests = []struct {
 }{{
 	Name: "valid",
 	Entry: Entry{
-		Hash: func() *Bytes32 {
-			b := NewBytes32(
+		Hash: func() *EntryHash {
+			b := NewEntryHash(
 				"72177d733dcd0492066b79c5f3e417aef7f22909674f7dc351ca13b04742bb91")
 			return &b
 		}(),
@@ -116,6 +115,11 @@ var unmarshalBinaryTests = []struct {
 	Data: hexToBytes(
 		"009005bb7dd69fb9910ee0b0db7b8a01198f03623eab6dadf1eba01f9dbc20757700530009436861696e54797065001253494e474c455f50524f4f465f434841494e000448617368002b4a74446f413157476a784f63584a67496365574e6336396a5551524867506835414e337848646b6a7158303d48796742426b32317a79384c576e5a56785a48526c38706b502f366e34377546317664324a4378654238593d"),
 	Error: "error parsing ExtIDs",
+}, {
+	Name: "invalid (odd ext ID Total Size)",
+	Data: hexToBytes(
+		"009005bb7dd69fb9910ee0b0db7b8a01198f03623eab6dadf1eba01f9dbc207577000300000a"),
+	Error: "error parsing ExtIDs",
 }}
 
 func TestEntry(t *testing.T) {
@@ -137,81 +141,63 @@ func TestEntry(t *testing.T) {
 	ec, _ := NewECAddress(ecAddressStr)
 	chainID := ComputeChainID([]Bytes{Bytes(ec[:])})
 	t.Run("ComposeCreate", func(t *testing.T) {
-		c := NewClient()
-		es, err := ec.GetEsAddress(nil, c)
-		if _, ok := err.(jsonrpc2.Error); ok {
-			// Skip if the EC address is not in the wallet.
-			t.SkipNow()
-		}
+		c := IntegrationClient(t)
+		es := Integration.Es
 		assert := assert.New(t)
-		assert.NoError(err)
-		balance, err := ec.GetBalance(nil, c)
-		assert.NoError(err)
-		if balance == 0 {
-			// Skip if the EC address is not funded.
-			t.SkipNow()
-		}
+		require := require.New(t)
 
+		// Create a new chain, and then an Entry in it.
 		randData, err := GenerateEsAddress()
-		assert.NoError(err)
+		require.NoError(err)
 		e := Entry{Content: Bytes(randData[:]),
-			ExtIDs:  []Bytes{Bytes(ec[:])},
-			ChainID: &chainID}
+			ExtIDs: []Bytes{Bytes(randData[:])}}
 		tx, err := e.ComposeCreate(nil, c, es)
 		assert.NoError(err)
 		assert.NotNil(tx)
 		assert.NotNil(e.Hash)
+		require.NotNil(e.ChainID)
 		fmt.Println("Tx: ", tx)
 		fmt.Println("Entry Hash: ", e.Hash)
 		fmt.Println("Chain ID: ", e.ChainID)
 
 		e.Hash = nil
-		e.ChainID = nil
-		e.Content = Bytes(randData[:])
-		e.ExtIDs = []Bytes{Bytes(randData[:])}
+		e.ExtIDs = []Bytes{Bytes(randData[:]), Bytes("entry")}
 		tx, err = e.ComposeCreate(nil, c, es)
 		assert.NoError(err)
 		assert.NotNil(tx)
 		assert.NotNil(e.Hash)
-		assert.NotNil(e.ChainID)
 		fmt.Println("Tx: ", tx)
 		fmt.Println("Entry Hash: ", e.Hash)
 		fmt.Println("Chain ID: ", e.ChainID)
 	})
 	t.Run("Create", func(t *testing.T) {
-		c := NewClient()
+		c := IntegrationClient(t)
+		ec := Integration.Es.ECAddress()
 		//c.Factomd.DebugRequest = true
 		//c.Walletd.DebugRequest = true
-		balance, err := ec.GetBalance(nil, c)
 		assert := assert.New(t)
 		require := require.New(t)
-		require.NoError(err)
-		if balance == 0 {
-			// Skip if the EC address is not funded.
-			t.SkipNow()
-		}
 
+		// Create a new chain, and then an Entry in it.
 		randData, err := GenerateEsAddress()
-		assert.NoError(err)
+		require.NoError(err)
 		e := Entry{Content: Bytes(randData[:]),
-			ExtIDs:  []Bytes{Bytes(ec[:])},
-			ChainID: &chainID}
+			ExtIDs: []Bytes{Bytes(randData[:])}}
 		tx, err := e.Create(nil, c, ec)
 		assert.NoError(err)
 		assert.NotNil(tx)
 		assert.NotNil(e.Hash)
+		require.NotNil(e.ChainID)
 		fmt.Println("Tx: ", tx)
 		fmt.Println("Entry Hash: ", e.Hash)
 		fmt.Println("Chain ID: ", e.ChainID)
 
-		e.ChainID = nil
-		e.Content = Bytes(randData[:])
-		e.ExtIDs = []Bytes{Bytes(randData[:])}
+		e.Hash = nil
+		e.ExtIDs = []Bytes{Bytes(randData[:]), Bytes("entry")}
 		tx, err = e.Create(nil, c, ec)
 		assert.NoError(err)
 		assert.NotNil(tx)
 		assert.NotNil(e.Hash)
-		assert.NotNil(e.ChainID)
 		fmt.Println("Tx: ", tx)
 		fmt.Println("Entry Hash: ", e.Hash)
 		fmt.Println("Chain ID: ", e.ChainID)
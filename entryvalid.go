@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import "fmt"
+
+// EntryMaxExtIDs is the maximum number of ExtIDs of an Entry. Each ExtID
+// requires at least its two byte length prefix in the EntryMaxDataSize.
+const EntryMaxExtIDs = EntryMaxDataSize / 2
+
+// ErrorEntrySize is returned by Entry.Valid when the encoded ExtIDs and
+// Content of an Entry exceed EntryMaxDataSize.
+type ErrorEntrySize struct {
+	Size int
+}
+
+// Error implements error.
+func (err ErrorEntrySize) Error() string {
+	return fmt.Sprintf("entry data size %v exceeds %v",
+		err.Size, EntryMaxDataSize)
+}
+
+// ErrorEntryExtIDs is returned by Entry.Valid when an Entry has more than
+// EntryMaxExtIDs ExtIDs.
+type ErrorEntryExtIDs struct {
+	Count int
+}
+
+// Error implements error.
+func (err ErrorEntryExtIDs) Error() string {
+	return fmt.Sprintf("entry ExtID count %v exceeds %v",
+		err.Count, EntryMaxExtIDs)
+}
+
+// ErrorEntryChainID is returned by Entry.Valid when the ChainID of an Entry
+// does not match the ChainID in its cached binary data.
+type ErrorEntryChainID struct {
+	ChainID  Bytes32
+	Expected Bytes32
+}
+
+// Error implements error.
+func (err ErrorEntryChainID) Error() string {
+	return fmt.Sprintf("entry ChainID %v does not match %v",
+		err.ChainID, err.Expected)
+}
+
+// ErrorEntryHash is returned by Entry.Valid when the Hash of an Entry does not
+// match the hash of its data.
+type ErrorEntryHash struct {
+	Hash     EntryHash
+	Expected EntryHash
+}
+
+// Error implements error.
+func (err ErrorEntryHash) Error() string {
+	return fmt.Sprintf("entry hash %v does not match %v",
+		err.Hash, err.Expected)
+}
+
+// Valid returns an error if e does not satisfy the limits that factomd
+// enforces on Entries, or if its fields are inconsistent with each other.
+//
+// The encoded size of the ExtIDs and Content must not exceed
+// EntryMaxDataSize, and there may be at most EntryMaxExtIDs ExtIDs. If e was
+// populated by UnmarshalBinary, e.ChainID must match the ChainID of the data.
+// If both e.ChainID and e.Hash are populated, e.Hash must match the hash of
+// the data.
+//
+// Entry.Create and Entry.ComposeCreate call Valid before submitting e, unless
+// Client.DisableEntryValidation is set.
+func (e Entry) Valid() error {
+	if len(e.ExtIDs) > EntryMaxExtIDs {
+		return ErrorEntryExtIDs{Count: len(e.ExtIDs)}
+	}
+	if size := e.MarshalBinaryLen() - EntryHeaderSize; size > EntryMaxDataSize {
+		return ErrorEntrySize{Size: size}
+	}
+	if e.ChainID == nil {
+		return nil
+	}
+	if len(e.marshalBinaryCache) >= EntryHeaderSize {
+		var chainID Bytes32
+		copy(chainID[:], e.marshalBinaryCache[1:])
+		if chainID != *e.ChainID {
+			return ErrorEntryChainID{ChainID: *e.ChainID,
+				Expected: chainID}
+		}
+	}
+	if e.Hash != nil {
+		data, err := e.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if hash := ComputeEntryHash(data); hash != *e.Hash {
+			return ErrorEntryHash{Hash: *e.Hash, Expected: hash}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryValid(t *testing.T) {
+	chainID := Bytes32{1}
+	valid := Entry{ChainID: &chainID,
+		ExtIDs:  []Bytes{Bytes("ext")},
+		Content: Bytes("content")}
+	data, err := valid.MarshalBinary()
+	require.NoError(t, err)
+	hash := ComputeEntryHash(data)
+
+	var populated Entry
+	require.NoError(t, populated.UnmarshalBinary(data))
+	otherChainID := Bytes32{2}
+	populated.ChainID = &otherChainID
+
+	var tests = []struct {
+		Name  string
+		Entry Entry
+		Error string
+	}{{
+		Name:  "valid",
+		Entry: valid,
+	}, {
+		Name: "valid/hash",
+		Entry: Entry{ChainID: &chainID, Hash: &hash,
+			ExtIDs: valid.ExtIDs, Content: valid.Content},
+	}, {
+		Name:  "valid/new chain",
+		Entry: Entry{ExtIDs: valid.ExtIDs},
+	}, {
+		Name:  "valid/max size",
+		Entry: Entry{Content: make(Bytes, EntryMaxDataSize)},
+	}, {
+		Name: "valid/max size with ExtIDs",
+		Entry: Entry{ExtIDs: []Bytes{make(Bytes, 10)},
+			Content: make(Bytes, EntryMaxDataSize-12)},
+	}, {
+		Name:  "too large",
+		Entry: Entry{Content: make(Bytes, EntryMaxDataSize+1)},
+		Error: "entry data size 10241 exceeds 10240",
+	}, {
+		Name: "too large with ExtIDs",
+		Entry: Entry{ExtIDs: []Bytes{make(Bytes, 10)},
+			Content: make(Bytes, EntryMaxDataSize-11)},
+		Error: "entry data size 10241 exceeds 10240",
+	}, {
+		Name:  "too many ExtIDs",
+		Entry: Entry{ExtIDs: make([]Bytes, EntryMaxExtIDs+1)},
+		Error: "entry ExtID count 5121 exceeds 5120",
+	}, {
+		Name:  "ChainID mismatch",
+		Entry: populated,
+		Error: "entry ChainID " + otherChainID.String() +
+			" does not match " + chainID.String(),
+	}, {
+		Name: "hash mismatch",
+		Entry: Entry{ChainID: &otherChainID, Hash: &hash,
+			ExtIDs: valid.ExtIDs, Content: valid.Content},
+		Error: "entry hash " + hash.String() + " does not match " +
+			func() string {
+				e := Entry{ChainID: &otherChainID,
+					ExtIDs:  valid.ExtIDs,
+					Content: valid.Content}
+				data, _ := e.MarshalBinary()
+				return ComputeEntryHash(data).String()
+			}(),
+	}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Entry.Valid()
+			if test.Error == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+
+	t.Run("ComposeCreate", func(t *testing.T) {
+		es, err := GenerateEsAddress()
+		require.NoError(t, err)
+		c := newMockClient(t, nil)
+		c.DryRun = &DryRun{SkipBalanceCheck: true}
+
+		e := Entry{ChainID: &chainID,
+			Content: make(Bytes, EntryMaxDataSize+1)}
+		_, err = e.ComposeCreate(context.Background(), c, es)
+		var sizeErr ErrorEntrySize
+		require.True(t, errors.As(err, &sizeErr), "%v", err)
+		assert.Equal(t, EntryMaxDataSize+1, sizeErr.Size)
+
+		c.DisableEntryValidation = true
+		_, err = e.ComposeCreate(context.Background(), c, es)
+		assert.EqualError(t, err, "factom.Entry.Compose(): "+
+			"factom.Entry.MarshalBinary(): length exceeds 10275")
+		assert.Empty(t, c.DryRun.Requests())
+	})
+	t.Run("Create", func(t *testing.T) {
+		c := newMockClient(t, nil)
+		e := Entry{ChainID: &chainID,
+			Content: make(Bytes, EntryMaxDataSize+1)}
+		_, err := e.Create(context.Background(), c, ECAddress{})
+		var sizeErr ErrorEntrySize
+		require.True(t, errors.As(err, &sizeErr), "%v", err)
+		assert.Contains(t, err.Error(), "factom.Entry.Valid(): ")
+	})
+}
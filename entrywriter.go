@@ -0,0 +1,518 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+)
+
+// Priority is the priority class of an Entry queued in an EntryWriter.
+type Priority int
+
+// Priority classes, from lowest to highest.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	numPriorities
+)
+
+// DefaultBudgetPeriod is the EntryWriter.Period used if zero, which is the
+// duration of one DBlock.
+const DefaultBudgetPeriod = 10 * time.Minute
+
+// EntryWriter queues Entries and submits them with Entry.ComposeCreate, paid
+// for by EsAddress, in order of Priority and then in the order they were
+// queued.
+//
+// Spending is limited to Budget Entry Credits per Period. Entries that would
+// exceed the Budget remain queued until a later Flush in a new Period, except
+// for PriorityHigh Entries, which are always submitted and count against the
+// Budget. The Entry Credits spent are accounted per tag, for chargeback in
+// services that write on behalf of many tenants.
+//
+// If an Entry is committed but its reveal fails, such as during a node restart
+// or a long outage, only the reveal is retried by later calls to Flush. If the
+// commit ages out of the Client.CommitWindow before the Entry is revealed, the
+// commit is rebuilt with a fresh timestamp and resubmitted, and its Entry
+// Credits are accounted again, and also as double spent.
+//
+// If an Outbox is set, queued Entries and Transactions, and their commits, are
+// stored in it until they are published, so that they survive process
+// restarts. Commits are stored before they are submitted, so that after a
+// restart the same commit is resubmitted rather than paying for a new one.
+// Use LoadOutbox to queue them again after a restart.
+//
+// The exported fields must not be changed after the first call to Queue. An
+// EntryWriter is safe for concurrent use.
+type EntryWriter struct {
+	EsAddress EsAddress
+
+	// Budget is the maximum number of Entry Credits spent per Period. If
+	// zero, spending is not limited.
+	Budget uint64
+
+	// Period is the duration of each Budget. If zero, DefaultBudgetPeriod
+	// is used.
+	Period time.Duration
+
+	// Clock, if not nil, is used instead of package time to start each
+	// Period.
+	Clock Clock
+
+	// CommitMargin is how long before a commit leaves the
+	// Client.CommitWindow that it is treated as expired and resubmitted,
+	// rather than revealed.
+	CommitMargin time.Duration
+
+	// Outbox, if not nil, durably stores queued Entries and Transactions
+	// until they are published.
+	Outbox Outbox
+
+	mu          sync.Mutex
+	queues      [numPriorities][]queuedEntry
+	periodStart time.Time
+	periodSpent uint64
+	spent       map[string]uint64
+	doubleSpent map[string]uint64
+	seq         uint64
+}
+
+type queuedEntry struct {
+	Entry
+	tag      string
+	priority Priority
+	seq      uint64
+
+	// id is the Entry hash, or the TxID of tx. It is set once the Entry
+	// has been composed.
+	id Bytes32
+
+	// tx is the signed binary Transaction, if this is not an Entry.
+	tx []byte
+
+	// reveal is set once the Entry has been composed. commit is set once
+	// a commit has been composed, and accepted once factomd has accepted
+	// the commit, until the Entry is revealed.
+	reveal    []byte
+	commit    []byte
+	accepted  bool
+	newChain  bool
+	timestamp time.Time
+
+	// expired is set if a commit for the Entry was dropped because it
+	// aged out before the Entry was revealed.
+	expired bool
+
+	// recovered is set if the Entry was loaded from the Outbox, until it
+	// is checked whether it was already published.
+	recovered bool
+}
+
+// Queue adds e to the queue for priority, to be submitted by Flush. The
+// Entry Credits spent on e are accounted to tag.
+//
+// If w.Outbox is not nil, e is composed and stored in the Outbox before Queue
+// returns, and any error is returned.
+func (w *EntryWriter) Queue(e Entry, priority Priority, tag string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	q := queuedEntry{Entry: e, tag: tag, priority: priority.clamp()}
+	if w.Outbox != nil {
+		if err := q.prepare(); err != nil {
+			return err
+		}
+	}
+	return w.enqueue(q)
+}
+
+// QueueTransaction adds the signed binary Transaction tx to the queue for
+// priority, to be submitted with Client.FactoidSubmit by Flush. No Entry
+// Credits are accounted to tag, nor is tx limited by the Budget.
+//
+// If w.Outbox is not nil, tx is stored in the Outbox before QueueTransaction
+// returns, and any error is returned.
+func (w *EntryWriter) QueueTransaction(
+	tx []byte, priority Priority, tag string) error {
+	var t Transaction
+	if err := t.UnmarshalBinary(tx); err != nil {
+		return fmt.Errorf("factom.Transaction.UnmarshalBinary(): %w", err)
+	}
+	txID, err := t.TxID()
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enqueue(queuedEntry{tx: tx, id: Bytes32(txID),
+		tag: tag, priority: priority.clamp()})
+}
+
+// LoadOutbox queues the Entries and Transactions stored in w.Outbox, in the
+// order they were first queued, and returns the number loaded. It should be
+// called once after a restart, before Queue or Flush.
+//
+// Before Flush submits a loaded Entry or Transaction, it checks whether it
+// was already published before the restart, in which case it is dequeued
+// without being submitted again or accounted.
+func (w *EntryWriter) LoadOutbox() (int, error) {
+	if w.Outbox == nil {
+		return 0, nil
+	}
+	var loaded []queuedEntry
+	if err := w.Outbox.Records(func(id Bytes32, data []byte) error {
+		var e queuedEntry
+		if err := e.unmarshalOutboxRecord(id, data); err != nil {
+			return fmt.Errorf("outbox record %v: %w", id, err)
+		}
+		loaded = append(loaded, e)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].seq < loaded[j].seq
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, e := range loaded {
+		e.priority = e.priority.clamp()
+		w.queues[e.priority] = append(w.queues[e.priority], e)
+		if e.seq >= w.seq {
+			w.seq = e.seq + 1
+		}
+	}
+	return len(loaded), nil
+}
+
+// clamp returns p limited to the range of defined Priority classes.
+func (p Priority) clamp() Priority {
+	if p < PriorityLow {
+		return PriorityLow
+	}
+	if p > PriorityHigh {
+		return PriorityHigh
+	}
+	return p
+}
+
+// enqueue stores e in the Outbox and adds it to its queue.
+func (w *EntryWriter) enqueue(e queuedEntry) error {
+	e.seq = w.seq
+	w.seq++
+	if err := w.persist(&e); err != nil {
+		return err
+	}
+	w.queues[e.priority] = append(w.queues[e.priority], e)
+	return nil
+}
+
+// Len returns the number of Entries queued.
+func (w *EntryWriter) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var n int
+	for _, q := range w.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// Remaining returns the number of Entry Credits that may still be spent in
+// the current Period, or 0 if Budget is zero.
+func (w *EntryWriter) Remaining() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.updatePeriod()
+	return w.remaining()
+}
+
+// Spent returns the total Entry Credits spent per tag.
+func (w *EntryWriter) Spent() map[string]uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	spent := make(map[string]uint64, len(w.spent))
+	for tag, ec := range w.spent {
+		spent[tag] = ec
+	}
+	return spent
+}
+
+// DoubleSpent returns the Entry Credits per tag that were spent on commits
+// that expired before their Entry was revealed, and so were spent again to
+// resubmit the commit. These are included in Spent.
+func (w *EntryWriter) DoubleSpent() map[string]uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	doubleSpent := make(map[string]uint64, len(w.doubleSpent))
+	for tag, ec := range w.doubleSpent {
+		doubleSpent[tag] = ec
+	}
+	return doubleSpent
+}
+
+// Flush submits queued Entries until the queues are empty, or the next Entry
+// would exceed the Budget and is not PriorityHigh. The number of Entries
+// submitted is returned.
+//
+// Entries for which the commit was already accepted are revealed without
+// being committed again, unless the commit has expired. Entries that already
+// exist are dequeued without being accounted, if Client.SkipExistingEntries is
+// set. If any other error occurs, the Entry remains queued, and the error is
+// returned.
+//
+// The queue is locked during Flush, so concurrent calls to Flush submit
+// Entries one at a time.
+func (w *EntryWriter) Flush(ctx context.Context, c *Client) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.updatePeriod()
+
+	var n int
+	for p := PriorityHigh; p >= PriorityLow; p-- {
+		q := w.queues[p]
+		for len(q) > 0 {
+			done, err := w.submit(ctx, c, &q[0])
+			if done {
+				q = q[1:]
+				n++
+			}
+			if err != nil {
+				w.queues[p] = q
+				return n, err
+			}
+			if !done {
+				break
+			}
+		}
+		w.queues[p] = q
+	}
+	return n, nil
+}
+
+// submit publishes e and removes it from the Outbox. If e no longer needs to
+// be submitted, true is returned, even if removing it from the Outbox fails.
+// False and no error is returned if e would exceed the Budget.
+func (w *EntryWriter) submit(
+	ctx context.Context, c *Client, e *queuedEntry) (bool, error) {
+	if e.tx != nil {
+		return w.submitTransaction(ctx, c, e)
+	}
+
+	if e.recovered {
+		// The Entry may have been revealed before a restart.
+		exists, err := c.EntryExists(ctx, *e.Hash)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return true, w.unpersist(e)
+		}
+		e.recovered = false
+	}
+
+	if e.commit != nil &&
+		c.CheckCommitTimestamp(e.timestamp, w.CommitMargin) != nil {
+		e.expired = e.expired || e.accepted
+		e.commit = nil
+		e.accepted = false
+	}
+	if e.commit == nil {
+		cost, err := e.Cost()
+		if err != nil {
+			return false, err
+		}
+		if e.priority != PriorityHigh && w.Budget > 0 &&
+			uint64(cost) > w.remaining() {
+			return false, nil
+		}
+		if err := w.composeCommit(ctx, c, e); err != nil {
+			var exists ErrorEntryExists
+			if errors.As(err, &exists) {
+				return true, w.unpersist(e)
+			}
+			return false, err
+		}
+	}
+	if !e.accepted {
+		if err := w.submitCommit(ctx, c, e); err != nil {
+			return false, err
+		}
+	}
+	if err := c.Reveal(ctx, e.reveal); err != nil {
+		return false, fmt.Errorf("factom.Client.Reveal(): %w", err)
+	}
+	return true, w.unpersist(e)
+}
+
+// prepare composes the reveal of e, if not already composed, and sets its
+// ChainID, Hash, and id.
+func (e *queuedEntry) prepare() error {
+	if e.reveal != nil {
+		return nil
+	}
+	e.newChain = e.ChainID == nil
+	if e.newChain {
+		e.ChainID = new(Bytes32)
+		*e.ChainID = ComputeChainID(e.ExtIDs)
+	}
+	reveal, err := e.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("factom.Entry.MarshalBinary(): %w", err)
+	}
+	e.reveal = reveal
+	e.Hash = new(EntryHash)
+	*e.Hash = ComputeEntryHash(reveal)
+	e.id = Bytes32(*e.Hash)
+	return nil
+}
+
+// composeCommit composes a new commit for e with a fresh timestamp, and
+// stores it in the Outbox before it is submitted.
+func (w *EntryWriter) composeCommit(
+	ctx context.Context, c *Client, e *queuedEntry) error {
+	if err := e.checkCreate(ctx, c); err != nil {
+		return err
+	}
+	if err := e.prepare(); err != nil {
+		return err
+	}
+	ts := c.commitTime()
+	commit, _, err := generateCommit(w.EsAddress,
+		e.reveal, e.Hash, e.newChain, ts)
+	if err != nil {
+		return err
+	}
+	e.commit = commit
+	e.timestamp = ts
+	return w.persist(e)
+}
+
+// submitCommit submits the commit of e, and accounts for it once accepted. A
+// commit that factomd reports as repeated was already accepted, such as
+// before a restart.
+func (w *EntryWriter) submitCommit(
+	ctx context.Context, c *Client, e *queuedEntry) error {
+	if err := c.Commit(ctx, e.commit); err != nil {
+		var jErr jsonrpc2.Error
+		if !errors.As(err, &jErr) || jErr.Code != errorCodeRepeatedCommit {
+			return fmt.Errorf("factom.Client.Commit(): %w", err)
+		}
+	}
+	cost, err := e.Cost()
+	if err != nil {
+		return err
+	}
+	e.accepted = true
+	w.account(e, uint64(cost))
+	return w.persist(e)
+}
+
+// submitTransaction submits e.tx and removes it from the Outbox, unless it
+// was recovered from the Outbox and is already known to factomd.
+func (w *EntryWriter) submitTransaction(
+	ctx context.Context, c *Client, e *queuedEntry) (bool, error) {
+	if e.recovered {
+		exists, err := c.transactionExists(ctx, e.id)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return true, w.unpersist(e)
+		}
+		e.recovered = false
+	}
+	if err := c.FactoidSubmit(ctx, e.tx); err != nil {
+		return false, err
+	}
+	return true, w.unpersist(e)
+}
+
+// persist stores e in the Outbox, if not nil.
+func (w *EntryWriter) persist(e *queuedEntry) error {
+	if w.Outbox == nil {
+		return nil
+	}
+	data, err := e.marshalOutboxRecord()
+	if err != nil {
+		return err
+	}
+	return w.Outbox.Put(e.id, data)
+}
+
+// unpersist removes e from the Outbox, if not nil.
+func (w *EntryWriter) unpersist(e *queuedEntry) error {
+	if w.Outbox == nil {
+		return nil
+	}
+	return w.Outbox.Delete(e.id)
+}
+
+// account adds cost to the Entry Credits spent in the current Period and by
+// e.tag.
+func (w *EntryWriter) account(e *queuedEntry, cost uint64) {
+	w.periodSpent += cost
+	if w.spent == nil {
+		w.spent = make(map[string]uint64)
+	}
+	w.spent[e.tag] += cost
+	if e.expired {
+		if w.doubleSpent == nil {
+			w.doubleSpent = make(map[string]uint64)
+		}
+		w.doubleSpent[e.tag] += cost
+		e.expired = false
+	}
+}
+
+// updatePeriod starts a new Period if the current one has elapsed.
+func (w *EntryWriter) updatePeriod() {
+	period := w.Period
+	if period == 0 {
+		period = DefaultBudgetPeriod
+	}
+	clock := w.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	if now := clock.Now(); now.Sub(w.periodStart) >= period {
+		w.periodStart = now
+		w.periodSpent = 0
+	}
+}
+
+func (w *EntryWriter) remaining() uint64 {
+	if w.periodSpent >= w.Budget {
+		return 0
+	}
+	return w.Budget - w.periodSpent
+}
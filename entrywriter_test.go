@@ -0,0 +1,212 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryWriter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	clock := NewFakeClock(time.Unix(1500000000, 0))
+	var revealed []string
+	var committed []time.Time
+	c := NewClient()
+	c.Clock = clock
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+			Params struct {
+				Entry  Bytes `json:"entry"`
+				Commit Bytes `json:"message"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		switch jReq.Method {
+		case "commit-entry":
+			var commit Commit
+			require.NoError(commit.UnmarshalBinary(jReq.Params.Commit))
+			committed = append(committed, commit.Timestamp)
+		case "reveal-entry":
+			var e Entry
+			require.NoError(e.UnmarshalBinary(jReq.Params.Entry))
+			revealed = append(revealed, string(e.Content))
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: struct{}{},
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	es, err := GenerateEsAddress()
+	require.NoError(err)
+	w := EntryWriter{
+		EsAddress: es,
+		Budget:    2,
+		Period:    100 * time.Millisecond,
+		Clock:     clock,
+	}
+	chainID := Bytes32{1}
+	entry := func(content string) Entry {
+		return Entry{ChainID: &chainID, Content: Bytes(content)}
+	}
+	w.Queue(entry("low 1"), PriorityLow, "a")
+	w.Queue(entry("normal 1"), PriorityNormal, "b")
+	w.Queue(entry("high 1"), PriorityHigh, "a")
+	w.Queue(entry("high 2"), PriorityHigh, "a")
+	w.Queue(entry("low 2"), PriorityLow, "b")
+	assert.Equal(5, w.Len())
+
+	// High priority Entries exceed the Budget, and the rest wait.
+	n, err := w.Flush(context.Background(), c)
+	require.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal(3, w.Len())
+	assert.Equal(uint64(0), w.Remaining())
+	assert.Equal([]string{"high 1", "high 2"}, revealed)
+
+	n, err = w.Flush(context.Background(), c)
+	require.NoError(err)
+	assert.Equal(0, n)
+
+	// The next Period frees up the Budget.
+	clock.Advance(w.Period)
+	n, err = w.Flush(context.Background(), c)
+	require.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal(1, w.Len())
+	assert.Equal([]string{"high 1", "high 2", "normal 1", "low 1"},
+		revealed)
+	assert.Equal(map[string]uint64{"a": 3, "b": 1}, w.Spent())
+
+	// Commit timestamps come from the Client's Clock, plus a random salt
+	// of less than a second.
+	require.Len(committed, 4)
+	for _, ts := range committed {
+		offset := ts.Sub(time.Unix(1500000000, 0))
+		assert.True(offset >= 0 && offset < w.Period+time.Second, ts)
+	}
+}
+
+func TestEntryWriterResubmit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start := time.Unix(1500000000, 0)
+	clock := NewFakeClock(start)
+	var committed []time.Time
+	var revealed int
+	failReveal := true
+	c := NewClient()
+	c.Clock = clock
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+			Params struct {
+				Commit Bytes `json:"message"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		res := jsonrpc2.Response{Result: struct{}{}, ID: jReq.ID}
+		switch jReq.Method {
+		case "commit-entry":
+			var commit Commit
+			require.NoError(commit.UnmarshalBinary(jReq.Params.Commit))
+			committed = append(committed, commit.Timestamp)
+		case "reveal-entry":
+			if failReveal {
+				res.Result = nil
+				res.Error = jsonrpc2.Error{Code: -32603,
+					Message: "Internal error"}
+				break
+			}
+			revealed++
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(res)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	es, err := GenerateEsAddress()
+	require.NoError(err)
+	w := EntryWriter{EsAddress: es, Clock: clock, CommitMargin: time.Hour}
+	chainID := Bytes32{1}
+	w.Queue(Entry{ChainID: &chainID, Content: Bytes("hello")},
+		PriorityNormal, "a")
+
+	// The commit is accepted, but the reveal fails.
+	_, err = w.Flush(context.Background(), c)
+	require.Error(err)
+	assert.Equal(1, w.Len())
+	require.Len(committed, 1)
+	assert.Equal(map[string]uint64{"a": 1}, w.Spent())
+
+	// Before the commit expires, only the reveal is retried.
+	clock.Advance(time.Hour)
+	_, err = w.Flush(context.Background(), c)
+	require.Error(err)
+	assert.Len(committed, 1)
+
+	// Once the commit is about to expire, it is resubmitted with a fresh
+	// timestamp and its Entry Credits are double spent.
+	clock.Advance(10*time.Hour + time.Minute)
+	failReveal = false
+	n, err := w.Flush(context.Background(), c)
+	require.NoError(err)
+	assert.Equal(1, n)
+	assert.Equal(0, w.Len())
+	assert.Equal(1, revealed)
+	require.Len(committed, 2)
+	assert.True(committed[1].Sub(start) >= 11*time.Hour)
+	assert.Equal(map[string]uint64{"a": 2}, w.Spent())
+	assert.Equal(map[string]uint64{"a": 1}, w.DoubleSpent())
+}
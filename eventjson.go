@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType returns the type of ev used by MarshalEventJSON: "block",
+// "entry", "balance", or "ack".
+func EventType(ev Event) string {
+	switch ev.eventKind() {
+	case eventBlock:
+		return "block"
+	case eventEntry:
+		return "entry"
+	case eventBalance:
+		return "balance"
+	case eventAck:
+		return "ack"
+	}
+	return "unknown"
+}
+
+// MarshalEventJSON returns a self describing JSON encoding of ev, for
+// delivery to services that do not use this package. Hashes and binary data
+// are hex encoded, and the "type" field is the EventType.
+//
+// A BlockEvent is encoded as
+//
+//	{"type":"block","height":<int>,"keymr":<hex>,"timestamp":<RFC 3339>,
+//	 "eblocks":[{"chainid":<hex>,"keymr":<hex>}, ...]}
+//
+// an EntryEvent as
+//
+//	{"type":"entry","height":<int>,"chainid":<hex>,"entryhash":<hex>,
+//	 "timestamp":<RFC 3339>,"extids":[<hex>, ...],"content":<hex>}
+//
+// a BalanceEvent as
+//
+//	{"type":"balance","height":<int>,"address":<FA address>,
+//	 "kind":"credit"|"debit","txid":<hex>,"amount":<factoshis>}
+//
+// and an AckEvent as
+//
+//	{"type":"ack","entryhash":<hex>,"status":<string>,"prev":<string>}
+func MarshalEventJSON(ev Event) ([]byte, error) {
+	switch ev := ev.(type) {
+	case BlockEvent:
+		type eblockJSON struct {
+			ChainID *Bytes32 `json:"chainid"`
+			KeyMR   *Bytes32 `json:"keymr"`
+		}
+		eblocks := make([]eblockJSON, len(ev.DBlock.EBlocks))
+		for i, eb := range ev.DBlock.EBlocks {
+			eblocks[i] = eblockJSON{ChainID: eb.ChainID,
+				KeyMR: (*Bytes32)(eb.KeyMR)}
+		}
+		return json.Marshal(struct {
+			Type      string       `json:"type"`
+			Height    uint32       `json:"height"`
+			KeyMR     *Bytes32     `json:"keymr"`
+			Timestamp time.Time    `json:"timestamp"`
+			EBlocks   []eblockJSON `json:"eblocks"`
+		}{"block", ev.DBlock.Height, (*Bytes32)(ev.DBlock.KeyMR),
+			ev.DBlock.Timestamp, eblocks})
+	case EntryEvent:
+		extIDs := ev.Entry.ExtIDs
+		if extIDs == nil {
+			extIDs = []Bytes{}
+		}
+		return json.Marshal(struct {
+			Type      string    `json:"type"`
+			Height    uint32    `json:"height"`
+			ChainID   *Bytes32  `json:"chainid"`
+			Hash      *Bytes32  `json:"entryhash"`
+			Timestamp time.Time `json:"timestamp"`
+			ExtIDs    []Bytes   `json:"extids"`
+			Content   Bytes     `json:"content"`
+		}{"entry", ev.Height, ev.Entry.ChainID, (*Bytes32)(ev.Entry.Hash),
+			ev.Entry.Timestamp, extIDs, ev.Entry.Content})
+	case BalanceEvent:
+		return json.Marshal(struct {
+			Type    string    `json:"type"`
+			Height  uint32    `json:"height"`
+			Address FAAddress `json:"address"`
+			Kind    string    `json:"kind"`
+			TxID    *Bytes32  `json:"txid"`
+			Amount  uint64    `json:"amount"`
+		}{"balance", ev.Height, ev.Address, ev.Kind.String(),
+			(*Bytes32)(ev.Transaction.ID), ev.Amount})
+	case AckEvent:
+		return json.Marshal(struct {
+			Type   string  `json:"type"`
+			Hash   Bytes32 `json:"entryhash"`
+			Status string  `json:"status"`
+			Prev   string  `json:"prev"`
+		}{"ack", Bytes32(ev.Hash), ev.Status, ev.Prev})
+	}
+	return nil, fmt.Errorf("unsupported event type: %T", ev)
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalEventJSON(t *testing.T) {
+	chainID := Bytes32{1}
+	keyMR := KeyMR{2}
+	hash := EntryHash{3}
+	txID := TxID{4}
+	ts := time.Unix(1500000000, 0).UTC()
+	for _, test := range []struct {
+		Event
+		JSON string
+	}{{
+		Event: BlockEvent{DBlock: DBlock{Height: 5, KeyMR: &keyMR,
+			Timestamp: ts, EBlocks: []EBlock{
+				{ChainID: &chainID, KeyMR: &keyMR}}}},
+		JSON: `{"type":"block","height":5,"keymr":"` + Bytes32(keyMR).String() +
+			`","timestamp":"2017-07-14T02:40:00Z","eblocks":[{"chainid":"` +
+			chainID.String() + `","keymr":"` + Bytes32(keyMR).String() + `"}]}`,
+	}, {
+		Event: EntryEvent{Height: 5, Entry: Entry{ChainID: &chainID,
+			Hash: &hash, Timestamp: ts, Content: Bytes("hi")}},
+		JSON: `{"type":"entry","height":5,"chainid":"` + chainID.String() +
+			`","entryhash":"` + Bytes32(hash).String() +
+			`","timestamp":"2017-07-14T02:40:00Z","extids":[],"content":"6869"}`,
+	}, {
+		Event: BalanceEvent{Address: FAAddress{}, AddressActivity: AddressActivity{
+			Kind: ActivityDebit, Height: 5, Amount: 10,
+			Transaction: Transaction{ID: &txID}}},
+		JSON: `{"type":"balance","height":5,"address":"` + FAAddress{}.String() +
+			`","kind":"debit","txid":"` + Bytes32(txID).String() +
+			`","amount":10}`,
+	}, {
+		Event: AckEvent{Hash: hash, Status: "DBlockConfirmed",
+			Prev: "TransactionACK"},
+		JSON: `{"type":"ack","entryhash":"` + Bytes32(hash).String() +
+			`","status":"DBlockConfirmed","prev":"TransactionACK"}`,
+	}} {
+		data, err := MarshalEventJSON(test.Event)
+		require.NoError(t, err)
+		assert.JSONEq(t, test.JSON, string(data))
+	}
+}
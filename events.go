@@ -0,0 +1,317 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"sync"
+)
+
+// Event is published on an EventBus. It is one of BlockEvent, EntryEvent,
+// BalanceEvent, or AckEvent.
+type Event interface {
+	eventKind() eventKind
+}
+
+type eventKind int
+
+const (
+	eventBlock eventKind = iota
+	eventEntry
+	eventBalance
+	eventAck
+
+	// eventAny subscribes to every kind of Event.
+	eventAny eventKind = -1
+)
+
+// BlockEvent is published for each new DBlock. The EBlocks of the DBlock have
+// their ChainID and KeyMR, but not their Entries.
+type BlockEvent struct {
+	DBlock DBlock
+}
+
+// EntryEvent is published for each new Entry in a watched Chain, with its
+// Timestamp established by its DBlock.
+type EntryEvent struct {
+	Entry Entry
+
+	// Height of the DBlock that includes the Entry.
+	Height uint32
+}
+
+// BalanceEvent is published for each credit to or debit from a watched
+// FAAddress.
+type BalanceEvent struct {
+	Address FAAddress
+	AddressActivity
+}
+
+// AckEvent is published when the acknowledgement status reported by factomd
+// for a watched Entry changes, for example from "TransactionACK" to
+// "DBlockConfirmed".
+type AckEvent struct {
+	Hash EntryHash
+
+	// Status is the new status and Prev is the previous status, which is
+	// empty for the first AckEvent of an Entry.
+	Status string
+	Prev   string
+}
+
+func (BlockEvent) eventKind() eventKind   { return eventBlock }
+func (EntryEvent) eventKind() eventKind   { return eventEntry }
+func (BalanceEvent) eventKind() eventKind { return eventBalance }
+func (AckEvent) eventKind() eventKind     { return eventAck }
+
+// EventBus delivers published Events to the handlers subscribed to their
+// type, so that producers, such as an EventSource, and consumers need not be
+// plumbed together with channels for each feature.
+//
+// Handlers are called synchronously by Publish, in the order that they were
+// subscribed. The zero value is ready to use, and an EventBus is safe for
+// concurrent use.
+type EventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []eventSub
+}
+
+type eventSub struct {
+	id   int
+	kind eventKind
+	fn   func(Event) error
+}
+
+// Publish calls each handler subscribed to the type of ev and returns the
+// first error returned by a handler, after which no further handlers are
+// called.
+func (b *EventBus) Publish(ev Event) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	kind := ev.eventKind()
+	for _, sub := range subs {
+		if sub.kind != kind && sub.kind != eventAny {
+			continue
+		}
+		if err := sub.fn(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnBlock subscribes fn to BlockEvents. Call the returned func to
+// unsubscribe.
+func (b *EventBus) OnBlock(fn func(BlockEvent) error) (unsubscribe func()) {
+	return b.subscribe(eventBlock,
+		func(ev Event) error { return fn(ev.(BlockEvent)) })
+}
+
+// OnEntry subscribes fn to EntryEvents. Call the returned func to
+// unsubscribe.
+func (b *EventBus) OnEntry(fn func(EntryEvent) error) (unsubscribe func()) {
+	return b.subscribe(eventEntry,
+		func(ev Event) error { return fn(ev.(EntryEvent)) })
+}
+
+// OnBalance subscribes fn to BalanceEvents. Call the returned func to
+// unsubscribe.
+func (b *EventBus) OnBalance(fn func(BalanceEvent) error) (unsubscribe func()) {
+	return b.subscribe(eventBalance,
+		func(ev Event) error { return fn(ev.(BalanceEvent)) })
+}
+
+// OnAck subscribes fn to AckEvents. Call the returned func to unsubscribe.
+func (b *EventBus) OnAck(fn func(AckEvent) error) (unsubscribe func()) {
+	return b.subscribe(eventAck,
+		func(ev Event) error { return fn(ev.(AckEvent)) })
+}
+
+// OnEvent subscribes fn to all Events, such as to forward them to another
+// system. Call the returned func to unsubscribe.
+func (b *EventBus) OnEvent(fn func(Event) error) (unsubscribe func()) {
+	return b.subscribe(eventAny, fn)
+}
+
+func (b *EventBus) subscribe(kind eventKind, fn func(Event) error) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	// Copy on write so that Publish may iterate without holding the lock.
+	subs := make([]eventSub, len(b.subs), len(b.subs)+1)
+	copy(subs, b.subs)
+	b.subs = append(subs, eventSub{id: id, kind: kind, fn: fn})
+	return func() { b.unsubscribe(id) }
+}
+
+func (b *EventBus) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := make([]eventSub, 0, len(b.subs))
+	for _, sub := range b.subs {
+		if sub.id != id {
+			subs = append(subs, sub)
+		}
+	}
+	b.subs = subs
+}
+
+// EventSource polls factomd and publishes Events to Bus.
+//
+// A BlockEvent is published for every DBlock, followed by an EntryEvent for
+// each Entry in Chains and a BalanceEvent for each credit to or debit from
+// Addresses. AckEvents are published for Entries whenever their status
+// changes, until they are confirmed in a DBlock.
+type EventSource struct {
+	Bus *EventBus
+
+	Chains    []Bytes32
+	Addresses []FAAddress
+
+	// Cursor, if not nil, is acknowledged with each height once all of its
+	// Events have been handled. Use Cursor.Start to find the height to pass
+	// to Run.
+	Cursor *Cursor
+
+	mu   sync.Mutex
+	acks map[EntryHash]string
+}
+
+// WatchAck adds hash to the Entries whose acknowledgement status is polled.
+func (s *EventSource) WatchAck(hash EntryHash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.acks == nil {
+		s.acks = make(map[EntryHash]string)
+	}
+	if _, ok := s.acks[hash]; !ok {
+		s.acks[hash] = ""
+	}
+}
+
+// Run publishes the Events of every DBlock from height onward, and the
+// AckEvents of the watched Entries, polling every BlockPollInterval.
+//
+// Run blocks until ctx is done or a handler returns an error, and returns
+// that error. Like Client.WatchAddress, a caller may resume after a restart
+// by passing one more than the last Height it handled.
+func (s *EventSource) Run(ctx context.Context, c *Client, height uint32) error {
+	for {
+		var heights Heights
+		if err := heights.Get(ctx, c); err != nil {
+			return err
+		}
+		for ; height <= heights.DirectoryBlock; height++ {
+			if err := s.publishBlock(ctx, c, height); err != nil {
+				return err
+			}
+			if s.Cursor != nil {
+				if err := s.Cursor.Ack(height); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.publishAcks(ctx, c); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock().After(BlockPollInterval):
+		}
+	}
+}
+
+func (s *EventSource) publishBlock(
+	ctx context.Context, c *Client, height uint32) error {
+	db, err := c.DBlockByHeight(ctx, height)
+	if err != nil {
+		return err
+	}
+	if err := s.Bus.Publish(BlockEvent{DBlock: db}); err != nil {
+		return err
+	}
+
+	for _, eb := range db.EBlocksFor(s.Chains...) {
+		if err := eb.GetEntries(ctx, c); err != nil {
+			return err
+		}
+		for _, e := range eb.Entries {
+			if err := s.Bus.Publish(
+				EntryEvent{Entry: e, Height: height}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(s.Addresses) == 0 {
+		return nil
+	}
+	fb, err := c.FBlockByHeight(ctx, height)
+	if err != nil {
+		return err
+	}
+	for _, adr := range s.Addresses {
+		for _, activity := range fb.AddressActivity(adr) {
+			if err := s.Bus.Publish(BalanceEvent{Address: adr,
+				AddressActivity: activity}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *EventSource) publishAcks(ctx context.Context, c *Client) error {
+	s.mu.Lock()
+	acks := make(map[EntryHash]string, len(s.acks))
+	for hash, status := range s.acks {
+		acks[hash] = status
+	}
+	s.mu.Unlock()
+
+	for hash, prev := range acks {
+		status, err := c.entryAckStatus(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if status == prev {
+			continue
+		}
+		s.mu.Lock()
+		if status == ackStatusDBlockConfirmed {
+			delete(s.acks, hash)
+		} else {
+			s.acks[hash] = status
+		}
+		s.mu.Unlock()
+		if err := s.Bus.Publish(AckEvent{
+			Hash: hash, Status: status, Prev: prev}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	var bus EventBus
+	var calls []string
+	unsubscribe := bus.OnBlock(func(ev BlockEvent) error {
+		calls = append(calls, fmt.Sprintf("block 1: %v", ev.DBlock.Height))
+		return nil
+	})
+	bus.OnBlock(func(ev BlockEvent) error {
+		calls = append(calls, fmt.Sprintf("block 2: %v", ev.DBlock.Height))
+		return nil
+	})
+	errStop := fmt.Errorf("stop")
+	bus.OnAck(func(ev AckEvent) error {
+		calls = append(calls, "ack: "+ev.Status)
+		return errStop
+	})
+	bus.OnAck(func(ev AckEvent) error {
+		calls = append(calls, "not called")
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(BlockEvent{DBlock: DBlock{Height: 5}}))
+	require.NoError(t, bus.Publish(EntryEvent{}))
+	assert.Equal(t, errStop, bus.Publish(AckEvent{Status: "1Minute"}))
+	unsubscribe()
+	require.NoError(t, bus.Publish(BlockEvent{DBlock: DBlock{Height: 6}}))
+
+	assert.Equal(t, []string{
+		"block 1: 5", "block 2: 5", "ack: 1Minute", "block 2: 6"}, calls)
+}
+
+func TestEventBusOnEvent(t *testing.T) {
+	var bus EventBus
+	var types []string
+	unsubscribe := bus.OnEvent(func(ev Event) error {
+		types = append(types, EventType(ev))
+		return nil
+	})
+	require.NoError(t, bus.Publish(BlockEvent{}))
+	require.NoError(t, bus.Publish(EntryEvent{}))
+	require.NoError(t, bus.Publish(BalanceEvent{}))
+	require.NoError(t, bus.Publish(AckEvent{}))
+	unsubscribe()
+	require.NoError(t, bus.Publish(AckEvent{}))
+	assert.Equal(t, []string{"block", "entry", "balance", "ack"}, types)
+}
+
+func TestEventSource(t *testing.T) {
+	chainID := Bytes32{1}
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("a")}},
+		[]Entry{{Content: Bytes("b")}, {Content: Bytes("c")}})
+
+	// Serve "heights" and "ack", which the mock chain does not.
+	mockChain := c.Factomd.Transport
+	c.Factomd.Transport = RoundTripFunc(func(req *http.Request) *http.Response {
+		reqData, _ := ioutil.ReadAll(req.Body)
+		var jReq jsonrpc2.Request
+		_ = json.Unmarshal(reqData, &jReq)
+		var result interface{}
+		switch jReq.Method {
+		case "heights":
+			result = Heights{DirectoryBlock: 11}
+		case "ack":
+			result = map[string]interface{}{"entrydata": map[string]string{
+				"status": "TransactionACK"}}
+		default:
+			req.Body = ioutil.NopCloser(bytes.NewBuffer(reqData))
+			res, _ := mockChain.RoundTrip(req)
+			return res
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: result, ID: jReq.ID})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cur := Cursor{Store: new(MemoryStore), Name: "events"}
+	s := EventSource{Bus: &EventBus{}, Chains: []Bytes32{chainID},
+		Cursor: &cur}
+	s.WatchAck(EntryHash{2})
+
+	var events []string
+	s.Bus.OnBlock(func(ev BlockEvent) error {
+		events = append(events, fmt.Sprintf("block %v", ev.DBlock.Height))
+		return nil
+	})
+	s.Bus.OnEntry(func(ev EntryEvent) error {
+		assert.Equal(t, chainID, *ev.Entry.ChainID)
+		assert.Equal(t, mockDBlockTimestamp(ev.Height).Add(MinuteDuration),
+			ev.Entry.Timestamp)
+		events = append(events, fmt.Sprintf("entry %v: %v",
+			ev.Height, string(ev.Entry.Content)))
+		return nil
+	})
+	errStop := fmt.Errorf("stop")
+	s.Bus.OnAck(func(ev AckEvent) error {
+		assert.Equal(t, EntryHash{2}, ev.Hash)
+		assert.Equal(t, "", ev.Prev)
+		events = append(events, "ack "+ev.Status)
+		return errStop
+	})
+
+	assert.Equal(t, errStop, s.Run(context.Background(), c, 10))
+	assert.Equal(t, []string{
+		"block 10", "entry 10: a",
+		"block 11", "entry 11: b", "entry 11: c",
+		"ack TransactionACK"}, events)
+
+	acked, ok, err := cur.Acked()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(11), acked)
+}
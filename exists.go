@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+)
+
+// errorCodeMissingChainHead is the jsonrpc2.ErrorCode factomd returns from
+// "chain-head" for a Chain that does not exist.
+const errorCodeMissingChainHead jsonrpc2.ErrorCode = -32009
+
+// errorCodeRepeatedCommit is the jsonrpc2.ErrorCode factomd returns from
+// "commit-entry" and "commit-chain" for a commit it has already accepted.
+const errorCodeRepeatedCommit jsonrpc2.ErrorCode = -32011
+
+// Acknowledgement statuses returned by factomd's "ack" API that indicate that
+// an Entry has been revealed.
+const (
+	ackStatusACK             = "TransactionACK"
+	ackStatus1Minute         = "1Minute"
+	ackStatusDBlockConfirmed = "DBlockConfirmed"
+)
+
+// EntryExists returns true if the Entry with the given hash has been revealed
+// to factomd, whether it is in a DBlock or only acknowledged in the process
+// list.
+//
+// This uses factomd's "ack" API, which only returns the status of the Entry,
+// so unlike Entry.Get the Content is never transferred. Entries that have
+// only been committed, or whose reveal is held but not yet acknowledged, do
+// not exist.
+func (c *Client) EntryExists(ctx context.Context, hash EntryHash) (bool, error) {
+	status, err := c.entryAckStatus(ctx, hash)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case ackStatusACK, ackStatus1Minute, ackStatusDBlockConfirmed:
+		return true, nil
+	}
+	return false, nil
+}
+
+// entryAckStatus returns the status of the Entry with the given hash reported
+// by factomd's "ack" API.
+func (c *Client) entryAckStatus(
+	ctx context.Context, hash EntryHash) (string, error) {
+	// factomd ignores the ChainID for Entry hashes, but requires it to be
+	// present and not one of the special block ChainIDs.
+	params := struct {
+		Hash    EntryHash `json:"hash"`
+		ChainID Bytes32   `json:"chainid"`
+	}{Hash: hash}
+	var result struct {
+		EntryData struct {
+			Status string `json:"status"`
+		} `json:"entrydata"`
+	}
+	if err := c.FactomdRequest(ctx, "ack", params, &result); err != nil {
+		return "", err
+	}
+	return result.EntryData.Status, nil
+}
+
+// ChainExists returns true if the Chain with the given chainID exists, or if
+// its creation is pending in the process list.
+//
+// This uses factomd's "chain-head" API, which only returns the KeyMR of the
+// latest EBlock of the Chain.
+func (c *Client) ChainExists(ctx context.Context, chainID Bytes32) (bool, error) {
+	eb := EBlock{ChainID: &chainID}
+	pending, err := eb.GetChainHead(ctx, c)
+	if err != nil {
+		var jErr jsonrpc2.Error
+		if errors.As(err, &jErr) && jErr.Code == errorCodeMissingChainHead {
+			return false, nil
+		}
+		return false, err
+	}
+	return pending || eb.KeyMR != nil, nil
+}
+
+// transactionExists returns true if factomd's "transaction" API knows of the
+// Factoid Transaction with the given hash, whether pending or in a DBlock.
+func (c *Client) transactionExists(
+	ctx context.Context, hash Bytes32) (bool, error) {
+	params := struct {
+		Hash Bytes32 `json:"hash"`
+	}{Hash: hash}
+	err := c.FactomdRequest(ctx, "transaction", params, nil)
+	if err != nil {
+		var jErr jsonrpc2.Error
+		if errors.As(err, &jErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ErrorEntryExists is returned by Entry.Create and Entry.ComposeCreate when
+// Client.SkipExistingEntries is set and the Entry already exists, in which
+// case nothing is submitted and no Entry Credits are spent.
+type ErrorEntryExists struct {
+	Hash EntryHash
+
+	// Receipt locates the existing Entry in its EBlock and DBlock. It is
+	// nil if the Entry is not yet in a DBlock, or if the Receipt could
+	// not be queried.
+	Receipt *Receipt
+}
+
+// Error implements error.
+func (err ErrorEntryExists) Error() string {
+	return fmt.Sprintf("entry already exists: %v", err.Hash)
+}
+
+// checkExists returns ErrorEntryExists if the Entry that would be created
+// from e already exists. If e.ChainID is nil, the ChainID is computed from
+// e.ExtIDs, as it would be for a new Chain, but e is not modified.
+func (e Entry) checkExists(ctx context.Context, c *Client) error {
+	if e.ChainID == nil {
+		chainID := ComputeChainID(e.ExtIDs)
+		e.ChainID = &chainID
+	}
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	hash := ComputeEntryHash(data)
+	exists, err := c.EntryExists(ctx, hash)
+	if err != nil || !exists {
+		return err
+	}
+	existsErr := ErrorEntryExists{Hash: hash}
+	r := Receipt{EntryHash: hash}
+	if err := r.Get(ctx, c); err == nil {
+		existsErr.Receipt = &r
+	}
+	return existsErr
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryExists(t *testing.T) {
+	for _, test := range []struct {
+		Status string
+		Exists bool
+	}{
+		{Status: "DBlockConfirmed", Exists: true},
+		{Status: "TransactionACK", Exists: true},
+		{Status: "1Minute", Exists: true},
+		{Status: "NotConfirmed"},
+		{Status: "Unknown"},
+	} {
+		test := test
+		t.Run(test.Status, func(t *testing.T) {
+			c := newMockClient(t, map[string]interface{}{
+				"ack": map[string]interface{}{
+					"entrydata": map[string]interface{}{
+						"status": test.Status}}})
+			exists, err := c.EntryExists(context.Background(),
+				EntryHash{1})
+			require.NoError(t, err)
+			assert.Equal(t, test.Exists, exists)
+		})
+	}
+}
+
+func TestChainExists(t *testing.T) {
+	ctx := context.Background()
+	chainID := ComputeChainID([]Bytes{Bytes("exists")})
+	c := newMockChain(t, chainID, []Entry{{Content: Bytes("test")}})
+
+	exists, err := c.ChainExists(ctx, chainID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = c.ChainExists(ctx, Bytes32{1})
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	c = newMockClient(t, map[string]interface{}{
+		"chain-head": map[string]interface{}{
+			"chainhead": "", "chaininprocesslist": true}})
+	exists, err = c.ChainExists(ctx, chainID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestSkipExistingEntries(t *testing.T) {
+	ctx := context.Background()
+	es, err := GenerateEsAddress()
+	require.NoError(t, err)
+	e := Entry{ExtIDs: []Bytes{Bytes("dedup")}, Content: Bytes("test")}
+	chainID := ComputeChainID(e.ExtIDs)
+	data, err := Entry{ChainID: &chainID, ExtIDs: e.ExtIDs,
+		Content: e.Content}.MarshalBinary()
+	require.NoError(t, err)
+	hash := ComputeEntryHash(data)
+
+	receipt := newReceipt(t)
+	for _, test := range []struct {
+		Name    string
+		Status  string
+		Receipt interface{}
+		Exists  bool
+		Located bool
+	}{{
+		Name:    "confirmed",
+		Status:  "DBlockConfirmed",
+		Receipt: map[string]interface{}{"receipt": receipt},
+		Exists:  true,
+		Located: true,
+	}, {
+		Name:    "acknowledged",
+		Status:  "TransactionACK",
+		Receipt: map[string]interface{}{},
+		Exists:  true,
+	}, {
+		Name:   "unknown",
+		Status: "Unknown",
+	}} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			c := newMockClient(t, map[string]interface{}{
+				"ack": map[string]interface{}{
+					"entrydata": map[string]interface{}{
+						"status": test.Status}},
+				"receipt": test.Receipt,
+			})
+			c.DryRun = &DryRun{SkipBalanceCheck: true}
+			c.SkipExistingEntries = true
+
+			e := e
+			_, err := e.ComposeCreate(ctx, c, es)
+			if !test.Exists {
+				require.NoError(t, err)
+				assert.Len(t, c.DryRun.Requests(), 2)
+				assert.Equal(t, hash, *e.Hash)
+				return
+			}
+			var existsErr ErrorEntryExists
+			require.True(t, errors.As(err, &existsErr), "%v", err)
+			assert.Equal(t, hash, existsErr.Hash)
+			assert.Empty(t, c.DryRun.Requests())
+			assert.Nil(t, e.ChainID)
+			if !test.Located {
+				assert.Nil(t, existsErr.Receipt)
+				return
+			}
+			require.NotNil(t, existsErr.Receipt)
+			assert.Equal(t, receipt.DBlockHeight,
+				existsErr.Receipt.DBlockHeight)
+		})
+	}
+}
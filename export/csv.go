@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVTimeFormat is the layout used for Timestamp columns in CSV output. It is
+// RFC 3339 with millisecond precision, always in UTC.
+const CSVTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// CSVWriter writes rows as RFC 4180 CSV with a header row of the Column
+// names.
+type CSVWriter struct {
+	schema Schema
+	w      *csv.Writer
+	record []string
+	header bool
+}
+
+// NewCSVWriter returns a CSVWriter that writes rows conforming to schema to w.
+// The header row is written with the first row, or on Close if no rows are
+// written.
+func NewCSVWriter(w io.Writer, schema Schema) *CSVWriter {
+	return &CSVWriter{
+		schema: schema,
+		w:      csv.NewWriter(w),
+		record: make([]string, len(schema)),
+	}
+}
+
+func (cw *CSVWriter) writeHeader() error {
+	if cw.header {
+		return nil
+	}
+	cw.header = true
+	for i, col := range cw.schema {
+		cw.record[i] = col.Name
+	}
+	return cw.w.Write(cw.record)
+}
+
+// WriteRow writes row as a CSV record. The row is validated against the
+// Schema.
+func (cw *CSVWriter) WriteRow(row []interface{}) error {
+	if err := cw.schema.Validate(row); err != nil {
+		return err
+	}
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	for i, v := range row {
+		switch v := v.(type) {
+		case string:
+			cw.record[i] = v
+		case int64:
+			cw.record[i] = strconv.FormatInt(v, 10)
+		case uint64:
+			cw.record[i] = strconv.FormatUint(v, 10)
+		case time.Time:
+			cw.record[i] = v.UTC().Format(CSVTimeFormat)
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+// Close writes the header if no rows have been written and flushes all
+// buffered data to the underlying io.Writer.
+func (cw *CSVWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
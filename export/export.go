@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// FBlock writes a TransactionSchema row to txs and the TransferSchema rows to
+// transfers for each Transaction in fb. Either txs or transfers may be nil.
+func FBlock(txs, transfers RowWriter, fb factom.FBlock) error {
+	for _, tx := range fb.Transactions {
+		if txs != nil {
+			if err := Transaction(txs, fb.Height, tx); err != nil {
+				return err
+			}
+		}
+		if transfers != nil {
+			if err := Transfers(transfers, fb.Height, tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Transaction writes tx, which was included in the FBlock at height, to w as
+// a TransactionSchema row.
+func Transaction(w RowWriter, height uint32, tx factom.Transaction) error {
+	if tx.ID == nil {
+		return fmt.Errorf("Transaction.ID is nil")
+	}
+	return w.WriteRow([]interface{}{
+		tx.ID.String(),
+		int64(height),
+		tx.Timestamp,
+		tx.TimestampSalt,
+		int64(len(tx.FCTInputs)),
+		int64(len(tx.FCTOutputs)),
+		int64(len(tx.ECOutputs)),
+		tx.TotalIn,
+		tx.TotalFCTOut,
+		tx.TotalECOut,
+		tx.TotalBurn,
+	})
+}
+
+// Transfers writes a TransferSchema row to w for each input and output of tx,
+// which was included in the FBlock at height.
+func Transfers(w RowWriter, height uint32, tx factom.Transaction) error {
+	if tx.ID == nil {
+		return fmt.Errorf("Transaction.ID is nil")
+	}
+	txID := tx.ID.String()
+	for _, dir := range []struct {
+		Direction string
+		Amounts   []factom.AddressAmount
+	}{
+		{DirectionFCTInput, tx.FCTInputs},
+		{DirectionFCTOutput, tx.FCTOutputs},
+		{DirectionECOutput, tx.ECOutputs},
+	} {
+		for i, adr := range dir.Amounts {
+			var adrStr string
+			if dir.Direction == DirectionECOutput {
+				adrStr = adr.ECAddress().String()
+			} else {
+				adrStr = adr.FAAddress().String()
+			}
+			if err := w.WriteRow([]interface{}{
+				txID,
+				int64(height),
+				tx.Timestamp,
+				int64(i),
+				dir.Direction,
+				adrStr,
+				adr.Amount,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EBlock writes an EntrySchema row to w for each Entry in eb. The Entries
+// must already be populated, for example by EBlock.GetEntries.
+func EBlock(w RowWriter, eb factom.EBlock) error {
+	for _, e := range eb.Entries {
+		if err := Entry(w, eb.Height, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entry writes e, which was included in the EBlock at height, to w as an
+// EntrySchema row. The Entry must be populated.
+func Entry(w RowWriter, height uint32, e factom.Entry) error {
+	if !e.IsPopulated() {
+		return fmt.Errorf("Entry is not populated")
+	}
+	if e.Hash == nil {
+		return fmt.Errorf("Entry.Hash is nil")
+	}
+	extIDs, err := json.Marshal(e.ExtIDs)
+	if err != nil {
+		return err
+	}
+	return w.WriteRow([]interface{}{
+		e.Hash.String(),
+		e.ChainID.String(),
+		int64(height),
+		e.Timestamp,
+		int64(len(e.ExtIDs)),
+		string(extIDs),
+		int64(len(e.Content)),
+		e.Content.String(),
+	})
+}
@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package export_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTxID    = factom.NewTxID("a9e3f9a8c35d9a61a2a0fa7c1ac6f5d8174c499fe0708c01d3c13cd1828fe6b9")
+	testChainID = factom.NewBytes32("9005bb7dd69fb9910ee0b0db7b8a01198f03623eab6dadf1eba01f9dbc207577")
+	testTime    = time.Date(2019, 11, 20, 12, 30, 0, 0, time.UTC)
+)
+
+func testTransaction() factom.Transaction {
+	fa := factom.FAAddress{1, 2, 3}
+	ec := factom.ECAddress{4, 5, 6}
+	return factom.Transaction{
+		ID:            &testTxID,
+		Timestamp:     testTime,
+		TimestampSalt: testTime.Add(-time.Second),
+		TotalIn:       1000,
+		TotalFCTOut:   600,
+		TotalECOut:    300,
+		TotalBurn:     100,
+		FCTInputs:     []factom.AddressAmount{{Address: fa[:], Amount: 1000}},
+		FCTOutputs:    []factom.AddressAmount{{Address: fa[:], Amount: 600}},
+		ECOutputs:     []factom.AddressAmount{{Address: ec[:], Amount: 300}},
+	}
+}
+
+func testEntry() factom.Entry {
+	e := factom.Entry{
+		ChainID:   &testChainID,
+		Timestamp: testTime,
+		ExtIDs:    []factom.Bytes{factom.Bytes("a"), factom.Bytes("b")},
+		Content:   factom.Bytes("hello"),
+	}
+	data, _ := e.MarshalBinary()
+	hash := factom.ComputeEntryHash(data)
+	e.Hash = &hash
+	return e
+}
+
+func TestCSV(t *testing.T) {
+	t.Run("Transaction", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		var buf bytes.Buffer
+		w := NewCSVWriter(&buf, TransactionSchema)
+		require.NoError(Transaction(w, 100, testTransaction()))
+		require.NoError(w.Close())
+		assert.Equal("tx_id,height,timestamp,timestamp_salt,"+
+			"fct_input_count,fct_output_count,ec_output_count,"+
+			"total_in,total_fct_out,total_ec_out,total_burn\n"+
+			testTxID.String()+",100,2019-11-20T12:30:00.000Z,"+
+			"2019-11-20T12:29:59.000Z,1,1,1,1000,600,300,100\n",
+			buf.String())
+	})
+	t.Run("Transfers", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		var buf bytes.Buffer
+		w := NewCSVWriter(&buf, TransferSchema)
+		tx := testTransaction()
+		require.NoError(Transfers(w, 100, tx))
+		require.NoError(w.Close())
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(lines, 4)
+		assert.Equal("tx_id,height,timestamp,index,direction,address,amount",
+			lines[0])
+		assert.Contains(lines[1], ",fct_input,"+
+			tx.FCTInputs[0].FAAddress().String()+",1000")
+		assert.Contains(lines[2], ",fct_output,"+
+			tx.FCTOutputs[0].FAAddress().String()+",600")
+		assert.Contains(lines[3], ",ec_output,"+
+			tx.ECOutputs[0].ECAddress().String()+",300")
+	})
+	t.Run("Entry", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		var buf bytes.Buffer
+		w := NewCSVWriter(&buf, EntrySchema)
+		e := testEntry()
+		require.NoError(Entry(w, 5, e))
+		require.NoError(w.Close())
+		assert.Equal("entry_hash,chain_id,height,timestamp,ext_id_count,"+
+			"ext_ids,content_size,content\n"+
+			e.Hash.String()+","+testChainID.String()+
+			`,5,2019-11-20T12:30:00.000Z,2,"[""61"",""62""]",5,68656c6c6f`+"\n",
+			buf.String())
+	})
+	t.Run("Entry/not populated", func(t *testing.T) {
+		w := NewCSVWriter(&bytes.Buffer{}, EntrySchema)
+		assert.EqualError(t, Entry(w, 5, factom.Entry{}),
+			"Entry is not populated")
+	})
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewCSVWriter(&buf, TransferSchema)
+		require.NoError(t, w.Close())
+		assert.Equal(t,
+			"tx_id,height,timestamp,index,direction,address,amount\n",
+			buf.String())
+	})
+}
+
+var validateTests = []struct {
+	Name  string
+	Row   []interface{}
+	Error string
+}{{
+	Name: "valid",
+	Row:  []interface{}{"a", int64(1), uint64(2), time.Time{}},
+}, {
+	Name:  "invalid (length)",
+	Row:   []interface{}{"a"},
+	Error: "invalid row length: 1, expected 4",
+}, {
+	Name:  "invalid (type)",
+	Row:   []interface{}{"a", 1, uint64(2), time.Time{}},
+	Error: `column "b": invalid int64 value: int`,
+}}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := Schema{
+		{Name: "a", Type: String},
+		{Name: "b", Type: Int64},
+		{Name: "c", Type: Uint64},
+		{Name: "d", Type: Timestamp},
+	}
+	for _, test := range validateTests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := schema.Validate(test.Row)
+			if len(test.Error) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.Error)
+			}
+		})
+	}
+}
+
+func TestParquet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, EntrySchema)
+	w.RowGroupSize = 2
+	e := testEntry()
+	for i := 0; i < 5; i++ {
+		require.NoError(Entry(w, uint32(i), e))
+	}
+	require.NoError(w.Close())
+
+	data := buf.Bytes()
+	require.True(len(data) > 12)
+	assert.Equal("PAR1", string(data[:4]))
+	assert.Equal("PAR1", string(data[len(data)-4:]))
+	footerLen := binary.LittleEndian.Uint32(data[len(data)-8:])
+	assert.True(int(footerLen) < len(data)-12)
+	assert.Contains(string(data[len(data)-8-int(footerLen):]), "entry_hash")
+
+	// Rows that do not conform to the schema are rejected.
+	assert.Error(w.WriteRow([]interface{}{"a"}))
+}
+
+func TestParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, TransactionSchema)
+	require.NoError(t, w.Close())
+	data := buf.Bytes()
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+}
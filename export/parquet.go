@@ -0,0 +1,402 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Notes: This file contains a minimal Parquet encoder sufficient for the flat
+// schemas used by this package, as specified by
+// https://github.com/apache/parquet-format
+//
+// All columns are REQUIRED, PLAIN encoded, and UNCOMPRESSED. Each row group
+// has exactly one data page per column chunk. Since no column is optional or
+// repeated, no definition or repetition levels are written.
+
+// DefaultRowGroupSize is the number of rows buffered in memory before a row
+// group is written, if ParquetWriter.RowGroupSize is zero.
+const DefaultRowGroupSize = 10000
+
+var parquetMagic = []byte("PAR1")
+
+// Parquet physical types, converted types, and other enum values.
+const (
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+	parquetConvertedUint64          = 14
+
+	parquetRepetitionRequired = 0
+	parquetEncodingPlain      = 0
+	parquetEncodingRLE        = 3
+	parquetCodecUncompressed  = 0
+	parquetPageTypeData       = 0
+)
+
+// ParquetWriter writes rows as a Parquet file. Rows are buffered in memory
+// and written out as a row group every RowGroupSize rows. The file footer is
+// written by Close.
+type ParquetWriter struct {
+	// RowGroupSize is the number of rows per row group. If zero,
+	// DefaultRowGroupSize is used. It may be changed before the first
+	// call to WriteRow.
+	RowGroupSize int
+
+	schema    Schema
+	w         io.Writer
+	offset    int64
+	started   bool
+	columns   [][]byte
+	rows      int
+	numRows   int64
+	rowGroups []parquetRowGroup
+}
+
+type parquetRowGroup struct {
+	numRows       int64
+	totalByteSize int64
+	columns       []parquetColumnChunk
+}
+
+type parquetColumnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// NewParquetWriter returns a ParquetWriter that writes rows conforming to
+// schema to w.
+func NewParquetWriter(w io.Writer, schema Schema) *ParquetWriter {
+	return &ParquetWriter{
+		schema:  schema,
+		w:       w,
+		columns: make([][]byte, len(schema)),
+	}
+}
+
+func (pw *ParquetWriter) write(data []byte) error {
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	return err
+}
+
+func (pw *ParquetWriter) start() error {
+	if pw.started {
+		return nil
+	}
+	pw.started = true
+	return pw.write(parquetMagic)
+}
+
+func (pw *ParquetWriter) rowGroupSize() int {
+	if pw.RowGroupSize > 0 {
+		return pw.RowGroupSize
+	}
+	return DefaultRowGroupSize
+}
+
+// WriteRow buffers row and writes out a row group if RowGroupSize rows are
+// buffered. The row is validated against the Schema.
+func (pw *ParquetWriter) WriteRow(row []interface{}) error {
+	if err := pw.schema.Validate(row); err != nil {
+		return err
+	}
+	if err := pw.start(); err != nil {
+		return err
+	}
+	for i, v := range row {
+		col := pw.columns[i]
+		switch v := v.(type) {
+		case string:
+			col = appendUint32LE(col, uint32(len(v)))
+			col = append(col, v...)
+		case int64:
+			col = appendUint64LE(col, uint64(v))
+		case uint64:
+			col = appendUint64LE(col, v)
+		case time.Time:
+			ms := v.Unix()*1e3 + int64(v.Nanosecond())/1e6
+			col = appendUint64LE(col, uint64(ms))
+		}
+		pw.columns[i] = col
+	}
+	pw.rows++
+	if pw.rows >= pw.rowGroupSize() {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+func (pw *ParquetWriter) flushRowGroup() error {
+	if pw.rows == 0 {
+		return nil
+	}
+	rg := parquetRowGroup{
+		numRows: int64(pw.rows),
+		columns: make([]parquetColumnChunk, len(pw.schema)),
+	}
+	for i, data := range pw.columns {
+		if len(data) > 1<<31-1 {
+			return fmt.Errorf("column %q: page too large",
+				pw.schema[i].Name)
+		}
+		var t thriftWriter
+		t.structBegin()
+		t.i32(1, parquetPageTypeData)
+		t.i32(2, int32(len(data)))
+		t.i32(3, int32(len(data)))
+		t.fieldStruct(5)
+		t.i32(1, int32(pw.rows))
+		t.i32(2, parquetEncodingPlain)
+		t.i32(3, parquetEncodingRLE)
+		t.i32(4, parquetEncodingRLE)
+		t.structEnd()
+		t.structEnd()
+
+		size := int64(len(t.buf) + len(data))
+		rg.columns[i] = parquetColumnChunk{
+			offset:           pw.offset,
+			numValues:        int64(pw.rows),
+			uncompressedSize: size,
+			compressedSize:   size,
+		}
+		rg.totalByteSize += size
+
+		if err := pw.write(t.buf); err != nil {
+			return err
+		}
+		if err := pw.write(data); err != nil {
+			return err
+		}
+		pw.columns[i] = data[:0]
+	}
+	pw.rowGroups = append(pw.rowGroups, rg)
+	pw.numRows += rg.numRows
+	pw.rows = 0
+	return nil
+}
+
+// Close writes any buffered rows as a final row group and then writes the
+// file footer. It does not close the underlying io.Writer.
+func (pw *ParquetWriter) Close() error {
+	if err := pw.start(); err != nil {
+		return err
+	}
+	if err := pw.flushRowGroup(); err != nil {
+		return err
+	}
+	footer := pw.marshalFileMetaData()
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+	if err := pw.write(appendUint32LE(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return pw.write(parquetMagic)
+}
+
+func (pw *ParquetWriter) marshalFileMetaData() []byte {
+	var t thriftWriter
+	t.structBegin()
+	t.i32(1, 1) // version
+
+	// Schema, the root element followed by each column.
+	t.fieldList(2, thriftStruct, len(pw.schema)+1)
+	t.structBegin()
+	t.string(4, "schema")
+	t.i32(5, int32(len(pw.schema)))
+	t.structEnd()
+	for _, col := range pw.schema {
+		physical, converted := parquetTypes(col.Type)
+		t.structBegin()
+		t.i32(1, physical)
+		t.i32(3, parquetRepetitionRequired)
+		t.string(4, col.Name)
+		if converted >= 0 {
+			t.i32(6, converted)
+		}
+		t.structEnd()
+	}
+
+	t.i64(3, pw.numRows)
+
+	t.fieldList(4, thriftStruct, len(pw.rowGroups))
+	for _, rg := range pw.rowGroups {
+		t.structBegin()
+		t.fieldList(1, thriftStruct, len(rg.columns))
+		for i, cc := range rg.columns {
+			physical, _ := parquetTypes(pw.schema[i].Type)
+			t.structBegin()
+			t.i64(2, cc.offset)
+			t.fieldStruct(3)
+			t.i32(1, physical)
+			t.fieldList(2, thriftI32, 2)
+			t.listI32(parquetEncodingPlain)
+			t.listI32(parquetEncodingRLE)
+			t.fieldList(3, thriftBinary, 1)
+			t.listString(pw.schema[i].Name)
+			t.i32(4, parquetCodecUncompressed)
+			t.i64(5, cc.numValues)
+			t.i64(6, cc.uncompressedSize)
+			t.i64(7, cc.compressedSize)
+			t.i64(9, cc.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64(2, rg.totalByteSize)
+		t.i64(3, rg.numRows)
+		t.structEnd()
+	}
+
+	t.string(6, "github.com/Factom-Asset-Tokens/factom/export")
+	t.structEnd()
+	return t.buf
+}
+
+// parquetTypes returns the physical and converted types for t. The converted
+// type is -1 if there is none.
+func parquetTypes(t ColumnType) (physical, converted int32) {
+	switch t {
+	case String:
+		return parquetTypeByteArray, parquetConvertedUTF8
+	case Uint64:
+		return parquetTypeInt64, parquetConvertedUint64
+	case Timestamp:
+		return parquetTypeInt64, parquetConvertedTimestampMillis
+	default:
+		return parquetTypeInt64, -1
+	}
+}
+
+func appendUint32LE(data []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], x)
+	return append(data, buf[:]...)
+}
+
+func appendUint64LE(data []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(data, buf[:]...)
+}
+
+// Thrift compact protocol type IDs.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter implements the subset of the Thrift compact protocol required
+// to encode Parquet metadata.
+//
+// https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+type thriftWriter struct {
+	buf []byte
+
+	// lastID is a stack of the last field ID written for each nested
+	// struct, used for field ID delta encoding.
+	lastID []int16
+}
+
+func (t *thriftWriter) structBegin() {
+	t.lastID = append(t.lastID, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf = append(t.buf, 0) // STOP
+	t.lastID = t.lastID[:len(t.lastID)-1]
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &t.lastID[len(t.lastID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) varint(x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	t.buf = append(t.buf, buf[:n]...)
+}
+
+func zigzag(x int64) uint64 {
+	return uint64((x << 1) ^ (x >> 63))
+}
+
+func (t *thriftWriter) i32(id int16, x int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag(int64(x)))
+}
+
+func (t *thriftWriter) i64(id int16, x int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag(x))
+}
+
+func (t *thriftWriter) string(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.listString(s)
+}
+
+// fieldStruct writes the header of a struct field and begins the struct,
+// which must be ended with structEnd.
+func (t *thriftWriter) fieldStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.structBegin()
+}
+
+// fieldList writes the header of a list field with size elements of type
+// elemType, which must then be written individually.
+func (t *thriftWriter) fieldList(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|elemType)
+	} else {
+		t.buf = append(t.buf, 0xf0|elemType)
+		t.varint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) listI32(x int32) {
+	t.varint(zigzag(int64(x)))
+}
+
+func (t *thriftWriter) listString(s string) {
+	t.varint(uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
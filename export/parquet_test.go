@@ -0,0 +1,293 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package export_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rowRecorder is a RowWriter that records the rows written to it.
+type rowRecorder [][]interface{}
+
+func (r *rowRecorder) WriteRow(row []interface{}) error {
+	*r = append(*r, row)
+	return nil
+}
+
+func (r *rowRecorder) Close() error { return nil }
+
+// thriftStruct is a decoded Thrift struct, by field ID.
+type thriftStruct map[int16]interface{}
+
+// thriftReader decodes the Thrift compact protocol generically, without
+// knowledge of the Parquet metadata structures, so that the output of
+// ParquetWriter is not verified by the same code that encoded it.
+type thriftReader struct {
+	data []byte
+	err  error
+}
+
+func (r *thriftReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+	r.data = nil
+}
+
+func (r *thriftReader) byte() byte {
+	if len(r.data) == 0 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("invalid varint")
+		return 0
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *thriftReader) varint() int64 {
+	x := r.uvarint()
+	return int64(x>>1) ^ -int64(x&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, 5, 6:
+		return r.varint()
+	case 8:
+		size := r.uvarint()
+		if uint64(len(r.data)) < size {
+			r.fail("binary too long: %v", size)
+			return nil
+		}
+		b := r.data[:size]
+		r.data = r.data[size:]
+		return string(b)
+	case 9:
+		header := r.byte()
+		size := uint64(header >> 4)
+		if size == 15 {
+			size = r.uvarint()
+		}
+		list := make([]interface{}, 0, size)
+		for i := uint64(0); i < size && r.err == nil; i++ {
+			list = append(list, r.value(header&0x0f))
+		}
+		return list
+	case 12:
+		return r.structure()
+	}
+	r.fail("unsupported type: %v", typ)
+	return nil
+}
+
+func (r *thriftReader) structure() thriftStruct {
+	s := make(thriftStruct)
+	var id int16
+	for r.err == nil {
+		header := r.byte()
+		if header == 0 {
+			break
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		s[id] = r.value(header & 0x0f)
+	}
+	return s
+}
+
+func decodeThrift(data []byte) (thriftStruct, int, error) {
+	r := thriftReader{data: data}
+	s := r.structure()
+	return s, len(data) - len(r.data), r.err
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Schema Schema
+		Write  func(RowWriter) error
+	}{{
+		Name:   "Transaction",
+		Schema: TransactionSchema,
+		Write: func(w RowWriter) error {
+			tx := testTransaction()
+			for i := 0; i < 3; i++ {
+				tx.TimestampSalt = tx.TimestampSalt.Add(
+					1234 * time.Millisecond)
+				if err := Transaction(w, uint32(i), tx); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, {
+		Name:   "Transfers",
+		Schema: TransferSchema,
+		Write: func(w RowWriter) error {
+			return Transfers(w, 7, testTransaction())
+		},
+	}, {
+		Name:   "Entry",
+		Schema: EntrySchema,
+		Write: func(w RowWriter) error {
+			e := testEntry()
+			for i := 0; i < 5; i++ {
+				if err := Entry(w, uint32(i), e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			var rows rowRecorder
+			require.NoError(t, test.Write(&rows))
+
+			var buf bytes.Buffer
+			w := NewParquetWriter(&buf, test.Schema)
+			w.RowGroupSize = 2
+			require.NoError(t, test.Write(w))
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, [][]interface{}(rows),
+				readParquet(t, buf.Bytes(), test.Schema))
+		})
+	}
+}
+
+// readParquet decodes all rows of the Parquet file data, after verifying its
+// metadata against schema.
+func readParquet(t *testing.T, data []byte, schema Schema) [][]interface{} {
+	require := require.New(t)
+	require.True(len(data) > 12)
+	require.Equal("PAR1", string(data[:4]))
+	require.Equal("PAR1", string(data[len(data)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	require.True(footerLen < len(data)-12)
+	footer := data[len(data)-8-footerLen : len(data)-8]
+
+	meta, n, err := decodeThrift(footer)
+	require.NoError(err)
+	require.Equal(len(footer), n)
+
+	// The schema is the root element followed by each column.
+	elements := meta[2].([]interface{})
+	require.Len(elements, len(schema)+1)
+	require.Equal(int64(len(schema)), elements[0].(thriftStruct)[5])
+	for i, col := range schema {
+		el := elements[i+1].(thriftStruct)
+		assert.Equal(t, col.Name, el[4])
+		assert.Equal(t, int64(0), el[3], "repetition type")
+		physical, converted := int64(2), interface{}(nil)
+		switch col.Type {
+		case String:
+			physical, converted = 6, int64(0)
+		case Uint64:
+			converted = int64(14)
+		case Timestamp:
+			converted = int64(9)
+		}
+		assert.Equal(t, physical, el[1], col.Name)
+		assert.Equal(t, converted, el[6], col.Name)
+	}
+
+	var rows [][]interface{}
+	for _, rg := range meta[4].([]interface{}) {
+		rg := rg.(thriftStruct)
+		numRows := int(rg[3].(int64))
+		chunks := rg[1].([]interface{})
+		require.Len(chunks, len(schema))
+		groupRows := make([][]interface{}, numRows)
+		for i := range groupRows {
+			groupRows[i] = make([]interface{}, len(schema))
+		}
+		for c, chunk := range chunks {
+			cmeta := chunk.(thriftStruct)[3].(thriftStruct)
+			require.Equal([]interface{}{schema[c].Name}, cmeta[3])
+			require.Equal(int64(0), cmeta[4], "codec")
+			require.Equal(int64(numRows), cmeta[5])
+
+			offset := int(cmeta[9].(int64))
+			header, n, err := decodeThrift(data[offset:])
+			require.NoError(err)
+			require.Equal(int64(0), header[1], "page type")
+			size := int(header[3].(int64))
+			require.Equal(cmeta[7], int64(n+size))
+			dph := header[5].(thriftStruct)
+			require.Equal(int64(numRows), dph[1])
+			require.Equal(int64(0), dph[2], "encoding")
+
+			page := data[offset+n : offset+n+size]
+			for r := range groupRows {
+				var v interface{}
+				switch schema[c].Type {
+				case String:
+					l := int(binary.LittleEndian.Uint32(page))
+					v = string(page[4 : 4+l])
+					page = page[4+l:]
+				default:
+					x := binary.LittleEndian.Uint64(page)
+					page = page[8:]
+					switch schema[c].Type {
+					case Int64:
+						v = int64(x)
+					case Uint64:
+						v = x
+					case Timestamp:
+						v = time.Unix(0, int64(x)*1e6).UTC()
+					}
+				}
+				groupRows[r][c] = v
+			}
+			require.Empty(page)
+		}
+		rows = append(rows, groupRows...)
+	}
+	require.Equal(meta[3], int64(len(rows)))
+	return rows
+}
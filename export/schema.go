@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package export streams Factoid Transactions and Entries into tabular file
+// formats, CSV and Parquet, with fixed schemas so that the data can be loaded
+// into analysis tools without any additional glue code.
+//
+// Rows are written through the RowWriter interface, which is implemented by
+// CSVWriter and ParquetWriter. The FBlock, Transaction, Transfers, EBlock, and
+// Entry functions convert factom data types into rows of the corresponding
+// Schema and write them to a RowWriter.
+package export
+
+import (
+	"fmt"
+	"time"
+)
+
+// ColumnType is the logical type of the values in a Column.
+type ColumnType int
+
+const (
+	// String columns hold UTF8 strings, such as hex encoded hashes or
+	// human readable addresses.
+	String ColumnType = iota
+
+	// Int64 columns hold signed 64 bit integers.
+	Int64
+
+	// Uint64 columns hold unsigned 64 bit integers, such as amounts in
+	// factoshis.
+	Uint64
+
+	// Timestamp columns hold a time.Time with millisecond precision.
+	Timestamp
+)
+
+// String returns the name of t.
+func (t ColumnType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int64:
+		return "int64"
+	case Uint64:
+		return "uint64"
+	case Timestamp:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("ColumnType(%d)", int(t))
+	}
+}
+
+// Column is a named and typed column of a Schema.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Schema is the ordered list of Columns for the rows of a table.
+type Schema []Column
+
+// Validate returns an error if row does not conform to s.
+//
+// The Go type of each value must correspond to its ColumnType: string for
+// String, int64 for Int64, uint64 for Uint64, and time.Time for Timestamp.
+func (s Schema) Validate(row []interface{}) error {
+	if len(row) != len(s) {
+		return fmt.Errorf("invalid row length: %v, expected %v",
+			len(row), len(s))
+	}
+	for i, col := range s {
+		var ok bool
+		switch col.Type {
+		case String:
+			_, ok = row[i].(string)
+		case Int64:
+			_, ok = row[i].(int64)
+		case Uint64:
+			_, ok = row[i].(uint64)
+		case Timestamp:
+			_, ok = row[i].(time.Time)
+		}
+		if !ok {
+			return fmt.Errorf("column %q: invalid %v value: %T",
+				col.Name, col.Type, row[i])
+		}
+	}
+	return nil
+}
+
+// TransactionSchema has one row per Factoid Transaction.
+var TransactionSchema = Schema{
+	{Name: "tx_id", Type: String},
+	{Name: "height", Type: Int64},
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "timestamp_salt", Type: Timestamp},
+	{Name: "fct_input_count", Type: Int64},
+	{Name: "fct_output_count", Type: Int64},
+	{Name: "ec_output_count", Type: Int64},
+	{Name: "total_in", Type: Uint64},
+	{Name: "total_fct_out", Type: Uint64},
+	{Name: "total_ec_out", Type: Uint64},
+	{Name: "total_burn", Type: Uint64},
+}
+
+// Directions of the rows in the TransferSchema.
+const (
+	DirectionFCTInput  = "fct_input"
+	DirectionFCTOutput = "fct_output"
+	DirectionECOutput  = "ec_output"
+)
+
+// TransferSchema has one row per input or output of a Factoid Transaction.
+// The direction is one of DirectionFCTInput, DirectionFCTOutput, or
+// DirectionECOutput, and the address is the human readable FA or EC address.
+var TransferSchema = Schema{
+	{Name: "tx_id", Type: String},
+	{Name: "height", Type: Int64},
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "index", Type: Int64},
+	{Name: "direction", Type: String},
+	{Name: "address", Type: String},
+	{Name: "amount", Type: Uint64},
+}
+
+// EntrySchema has one row per Entry. The ext_ids are encoded as a JSON array
+// of hex strings, and the content is hex encoded.
+var EntrySchema = Schema{
+	{Name: "entry_hash", Type: String},
+	{Name: "chain_id", Type: String},
+	{Name: "height", Type: Int64},
+	{Name: "timestamp", Type: Timestamp},
+	{Name: "ext_id_count", Type: Int64},
+	{Name: "ext_ids", Type: String},
+	{Name: "content_size", Type: Int64},
+	{Name: "content", Type: String},
+}
+
+// RowWriter is the interface implemented by the tabular file encoders.
+type RowWriter interface {
+	// WriteRow writes a single row, which must conform to the Schema
+	// of the RowWriter.
+	WriteRow(row []interface{}) error
+
+	// Close flushes any buffered rows and finalizes the encoding. It does
+	// not close the underlying io.Writer.
+	Close() error
+}
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/canonjson"
 	"github.com/Factom-Asset-Tokens/factom/jsonlen"
 )
 
@@ -70,6 +71,19 @@ func Sign(e factom.Entry, signingSet ...factom.RCDSigner) factom.Entry {
 	}
 	return e
 }
+// SignJSON sets the Content of the factom.Entry to the canonical JSON encoding
+// of v and then signs it like Sign. Verifiers can use canonjson.Verify to
+// ensure that the signed Content re-encodes to exactly the same bytes.
+func SignJSON(e factom.Entry, v interface{},
+	signingSet ...factom.RCDSigner) (factom.Entry, error) {
+	content, err := canonjson.Marshal(v)
+	if err != nil {
+		return factom.Entry{}, err
+	}
+	e.Content = content
+	return Sign(e, signingSet...), nil
+}
+
 func newTimestampSalt() []byte {
 	timestamp := time.Now().Add(time.Duration(-rng.Int63n(int64(1 * time.Hour))))
 	return []byte(strconv.FormatInt(timestamp.Unix(), 10))
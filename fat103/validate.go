@@ -96,3 +96,24 @@ func Validate(e factom.Entry, expected map[factom.Bytes32]struct{},
 
 	return nil
 }
+
+// Signers returns the RCDs of all FAT-103 signatures of the factom.Entry, or
+// nil if the ExtIDs are not a valid set of RCD/signature pairs.
+//
+// This is suitable for use as factom.Chain.Signers.
+func Signers(e factom.Entry) []factom.RCD {
+	if len(e.ExtIDs) < 3 || len(e.ExtIDs)%2 != 1 {
+		return nil
+	}
+	rcds := make([]factom.RCD, 0, len(e.ExtIDs)/2)
+	expected := make(map[factom.Bytes32]struct{}, len(e.ExtIDs)/2)
+	for i := 1; i < len(e.ExtIDs); i += 2 {
+		rcd := factom.RCD(e.ExtIDs[i])
+		rcds = append(rcds, rcd)
+		expected[rcd.Hash()] = struct{}{}
+	}
+	if err := Validate(e, expected); err != nil {
+		return nil
+	}
+	return rcds
+}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/Factom-Asset-Tokens/factom"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidate(t *testing.T) {
@@ -40,6 +41,19 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestSigners(t *testing.T) {
+	e, adrs := validEntry(3)
+	rcds := Signers(e)
+	require.Len(t, rcds, len(adrs))
+	for i, adr := range adrs {
+		assert.Equal(t, adr.RCD(), rcds[i])
+	}
+
+	e.ExtIDs[2][0]++
+	assert.Nil(t, Signers(e))
+	assert.Nil(t, Signers(factom.Entry{}))
+}
+
 func testValidate(t *testing.T, test validateTest) {
 	assert := assert.New(t)
 	err := Validate(test.Entry, rcdHashes(test.Expected))
@@ -244,3 +258,16 @@ func sha256d(data []byte) [sha256.Size]byte {
 	hash := sha256.Sum256(data)
 	return sha256.Sum256(hash[:])
 }
+
+func TestSignJSON(t *testing.T) {
+	adrs := genAddresses(1)
+	e := factom.Entry{ChainID: &factom.Bytes32{1}}
+	e, err := SignJSON(e, map[string]interface{}{"b": 1.50, "a": "<x>"},
+		adrs...)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"<x>","b":1.5}`, string(e.Content))
+	assert.NoError(t, Validate(e, rcdHashes(adrs)))
+
+	_, err = SignJSON(e, make(chan int), adrs...)
+	assert.Error(t, err)
+}
@@ -55,7 +55,7 @@ func Generate(ctx context.Context, es factom.EsAddress,
 	dataSize uint64, dataHash *factom.Bytes32,
 	appMetadata json.RawMessage, appNamespace ...factom.Bytes) (
 	chainID factom.Bytes32,
-	txIDs, entryHashes []factom.Bytes32,
+	txIDs []factom.TxID, entryHashes []factom.EntryHash,
 	commits, reveals []factom.Bytes,
 	totalCost uint,
 	err error) {
@@ -95,8 +95,8 @@ func Generate(ctx context.Context, es factom.EsAddress,
 
 	// We return the commit and reveal data so that users of the library
 	// don't need to regenerate them.
-	txIDs = make([]factom.Bytes32, totalECount)
-	entryHashes = make([]factom.Bytes32, totalECount)
+	txIDs = make([]factom.TxID, totalECount)
+	entryHashes = make([]factom.EntryHash, totalECount)
 	commits = make([]factom.Bytes, totalECount)
 	reveals = make([]factom.Bytes, totalECount)
 
@@ -139,7 +139,7 @@ func Generate(ctx context.Context, es factom.EsAddress,
 	// the last DBI Entry.
 	dbiI := len(dbi) - (nDBHash * 32)
 
-	var dbiStart factom.Bytes32
+	var dbiStart factom.EntryHash
 	for i := dbiECount; i > 0; i-- {
 		e := factom.Entry{ChainID: &chainID}
 
@@ -167,14 +167,14 @@ func TestGenerate(t *testing.T) {
 		start := time.Now()
 		require.NoError(Submit(policy,
 			func() error { return c.Commit(nil, commit) },
-			&txIDs[i], nil))
+			(*factom.Bytes32)(&txIDs[i]), nil))
 		fmt.Printf("%v\n", time.Since(start))
 
 		fmt.Printf("%v %v revealing ... ", i+1, eHashes[i].String()[:6])
 		start = time.Now()
 		require.NoError(Submit(policy,
 			func() error { return c.Reveal(nil, reveals[i]) },
-			&eHashes[i], &chainID))
+			(*factom.Bytes32)(&eHashes[i]), &chainID))
 		fmt.Printf("%v\n", time.Since(start))
 	}
 	fmt.Println("All entries submitted.", time.Since(start))
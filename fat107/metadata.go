@@ -58,7 +58,7 @@ type Metadata struct {
 	*Compression `json:"compression,omitempty"`
 
 	// The Entry Hash of the first DBI Entry that describing the Data.
-	DBIStart *factom.Bytes32 `json:"dbi-start"`
+	DBIStart *factom.EntryHash `json:"dbi-start"`
 
 	// Optional additional JSON containing application defined Metadata.
 	AppMetadata json.RawMessage `json:"metadata,omitempty"`
@@ -302,7 +302,7 @@ func (m Metadata) Download(ctx context.Context, c *factom.Client, data io.Writer
 		}
 
 		// Parse out the next Data Block Entry Hash.
-		dbE := factom.Entry{Hash: new(factom.Bytes32)}
+		dbE := factom.Entry{Hash: new(factom.EntryHash)}
 		dbiBuf.Read(dbE.Hash[:])
 
 		// Set the Content of each Data Block so the Content will get
@@ -25,7 +25,6 @@ package factom
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 	"time"
@@ -36,13 +35,13 @@ import (
 // FBlock represents a Factoid Block.
 type FBlock struct {
 	// Computed Fields
-	KeyMR       *Bytes32
-	LedgerKeyMR *Bytes32
+	KeyMR       *KeyMR
+	LedgerKeyMR *KeyMR
 
 	// Header Fields
 	BodyMR          *Bytes32
-	PrevKeyMR       *Bytes32
-	PrevLedgerKeyMR *Bytes32
+	PrevKeyMR       *KeyMR
+	PrevLedgerKeyMR *KeyMR
 
 	ECExchangeRate uint64
 	Height         uint32
@@ -100,16 +99,14 @@ func (fb *FBlock) Get(ctx context.Context, c *Client) (err error) {
 	}
 
 	if fb.KeyMR != nil {
-		params := struct {
-			Hash *Bytes32 `json:"hash"`
-		}{Hash: fb.KeyMR}
-		var result struct {
-			Data Bytes `json:"data"`
+		data, stored, err := c.getRawData(ctx, Bytes32(*fb.KeyMR))
+		if err != nil {
+			return err
 		}
-		if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+		if err := fb.UnmarshalBinary(data); err != nil || stored {
 			return err
 		}
-		return fb.UnmarshalBinary(result.Data)
+		return c.putRawData(Bytes32(*fb.KeyMR), data)
 	}
 
 	params := struct {
@@ -123,7 +120,10 @@ func (fb *FBlock) Get(ctx context.Context, c *Client) (err error) {
 		return err
 	}
 
-	return fb.UnmarshalBinary(result.RawData)
+	if err := fb.UnmarshalBinary(result.RawData); err != nil {
+		return err
+	}
+	return c.putRawData(Bytes32(*fb.KeyMR), result.RawData)
 }
 
 const (
@@ -180,10 +180,10 @@ func (fb *FBlock) UnmarshalBinary(data []byte) (err error) {
 	fb.BodyMR = new(Bytes32)
 	i += copy(fb.BodyMR[:], data[i:])
 
-	fb.PrevKeyMR = new(Bytes32)
+	fb.PrevKeyMR = new(KeyMR)
 	i += copy(fb.PrevKeyMR[:], data[i:])
 
-	fb.PrevLedgerKeyMR = new(Bytes32)
+	fb.PrevLedgerKeyMR = new(KeyMR)
 	i += copy(fb.PrevLedgerKeyMR[:], data[i:])
 
 	fb.ECExchangeRate = binary.BigEndian.Uint64(data[i : i+8])
@@ -201,7 +201,7 @@ func (fb *FBlock) UnmarshalBinary(data []byte) (err error) {
 	// sanity check, if the expansion size is greater than all the data we
 	// have, less 8 bytes for the tx count and body size, then the
 	// expansion size was bogus.
-	if expansionSize > uint64(len(data[i:])-8) {
+	if len(data[i:]) < 8 || expansionSize > uint64(len(data[i:])-8) {
 		return fmt.Errorf("expansion size is larger than remaining data")
 	}
 	// This should be a safe cast to int, as the size is never > max int
@@ -216,23 +216,25 @@ func (fb *FBlock) UnmarshalBinary(data []byte) (err error) {
 	i += 4
 
 	// If the declared txCount would require
-	if int(txCount*TransactionMinTotalSize) > len(data) {
+	if uint64(txCount)*TransactionMinTotalSize > uint64(len(data)) {
 		return fmt.Errorf("unreasonable Transaction count")
 
 	}
 
 	// Header is all data we've read so far
-	headerHash := sha256.Sum256(data[:i])
+	headerHash := sha256Sum(data[:i])
 	bodyMRElements := make([][]byte, int(txCount)+len(fb.endOfPeriod))
 	bodyLedgerMRElements := make([][]byte, int(txCount)+len(fb.endOfPeriod))
 
 	fb.Transactions = make([]Transaction, txCount)
+	var signed []Signed
+	var signedTx []int // The index of the Transaction of each Signed.
 	var period int
 	for c := range fb.Transactions {
 		// Before each fct tx, we need to see if there is a marker byte that
 		// indicates a minute marker
-		for data[i] == FBlockMinuteMarker {
-			if period > len(fb.endOfPeriod) {
+		for i < len(data) && data[i] == FBlockMinuteMarker {
+			if period >= len(fb.endOfPeriod) {
 				return fmt.Errorf("too many minute markers")
 			}
 			fb.endOfPeriod[period] = c
@@ -243,9 +245,14 @@ func (fb *FBlock) UnmarshalBinary(data []byte) (err error) {
 		}
 
 		tx := &fb.Transactions[c]
-		if err := tx.UnmarshalBinary(data[i:]); err != nil {
+		txSigned, err := tx.unmarshalBinary(data[i:])
+		if err != nil {
 			return err
 		}
+		signed = append(signed, txSigned...)
+		for range txSigned {
+			signedTx = append(signedTx, c)
+		}
 		read := tx.MarshalBinaryLen()
 
 		tx.Timestamp = fb.Timestamp.Add(time.Duration(period) * MinuteDuration)
@@ -277,6 +284,13 @@ func (fb *FBlock) UnmarshalBinary(data []byte) (err error) {
 		i++
 	}
 
+	for j, valid := range VerifyAll(signed) {
+		if !valid {
+			return fmt.Errorf("Transaction %v: invalid signature",
+				signedTx[j])
+		}
+	}
+
 	// Merkle Root Calculations
 	bodyMR, err := ComputeFBlockBodyMR(bodyMRElements)
 	if err != nil {
@@ -378,5 +392,5 @@ func (fb FBlock) ComputeFullHash() (Bytes32, error) {
 	if err != nil {
 		return Bytes32{}, err
 	}
-	return sha256.Sum256(data), nil
+	return sha256Sum(data), nil
 }
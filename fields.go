@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import "context"
+
+// EntryFields selects the fields of an Entry that are kept by Entry.GetFields
+// and EBlock.GetEntriesFields. The Hash, ChainID, and Timestamp are always
+// kept.
+//
+// factomd has no RPC that returns only part of an Entry, so selecting any
+// field requires the whole Entry to be downloaded, but the bytes of the fields
+// that were not selected are released immediately. Selecting no fields avoids
+// downloading the Entries at all, since their Hashes are listed in the EBlock.
+type EntryFields uint8
+
+// EntryFields that may be combined.
+const (
+	EntryExtIDs EntryFields = 1 << iota
+	EntryContent
+
+	// EntryHashOnly selects no fields.
+	EntryHashOnly EntryFields = 0
+
+	// EntryAllFields selects all fields, which is what Entry.Get and
+	// EBlock.GetEntries populate.
+	EntryAllFields = EntryExtIDs | EntryContent
+)
+
+// GetFields is like Get, but only keeps the selected fields. If fields is
+// EntryHashOnly, nothing is downloaded.
+//
+// Since e is only partially populated, e.IsPopulated returns false, and
+// e.MarshalBinary and e.ComputeHash will not be correct unless all fields
+// are selected.
+func (e *Entry) GetFields(ctx context.Context, c *Client, fields EntryFields) error {
+	if fields == EntryHashOnly {
+		return nil
+	}
+	if err := e.Get(ctx, c); err != nil {
+		return err
+	}
+	if fields == EntryAllFields {
+		return nil
+	}
+	if fields&EntryExtIDs == 0 {
+		e.ExtIDs = nil
+	}
+	if fields&EntryContent == 0 {
+		e.Content = nil
+	}
+	e.ClearMarshalBinaryCache()
+	return nil
+}
+
+// GetEntriesFields is like GetEntries, but only keeps the selected fields of
+// each Entry. If fields is EntryHashOnly, it is equivalent to eb.Get, and the
+// Entries only have their Hash, ChainID, and Timestamp.
+func (eb *EBlock) GetEntriesFields(ctx context.Context, c *Client,
+	fields EntryFields) error {
+	if fields == EntryHashOnly {
+		return eb.Get(ctx, c)
+	}
+	return eb.getEntries(ctx, c, func(ctx context.Context, e *Entry) error {
+		return e.GetFields(ctx, c, fields)
+	})
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEntriesFields(t *testing.T) {
+	chainID := Bytes32{1}
+	c := newMockChain(t, chainID, []Entry{
+		{ExtIDs: []Bytes{Bytes("a")}, Content: Bytes("content a")},
+		{ExtIDs: []Bytes{Bytes("b")}, Content: Bytes("content b")},
+	})
+
+	for _, test := range []struct {
+		Name    string
+		Fields  EntryFields
+		ExtIDs  bool
+		Content bool
+	}{{
+		Name:   "hash only",
+		Fields: EntryHashOnly,
+	}, {
+		Name:   "ExtIDs",
+		Fields: EntryExtIDs,
+		ExtIDs: true,
+	}, {
+		Name:    "Content",
+		Fields:  EntryContent,
+		Content: true,
+	}, {
+		Name:    "all",
+		Fields:  EntryAllFields,
+		ExtIDs:  true,
+		Content: true,
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			eb := EBlock{ChainID: &chainID}
+			require.NoError(t, eb.GetEntriesFields(
+				context.Background(), c, test.Fields))
+			require.Len(t, eb.Entries, 2)
+			for _, e := range eb.Entries {
+				assert.NotNil(e.Hash)
+				assert.Equal(chainID, *e.ChainID)
+				assert.False(e.Timestamp.IsZero())
+				assert.Equal(test.ExtIDs, e.ExtIDs != nil)
+				assert.Equal(test.Content, e.Content != nil)
+			}
+			if test.Fields == EntryAllFields {
+				assert.Equal(Bytes("content b"), eb.Entries[1].Content)
+			}
+		})
+	}
+}
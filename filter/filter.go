@@ -0,0 +1,144 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package filter builds predicates on Entries, so that the same filtering
+// logic may be used anywhere Entries are scanned, such as with
+// factom.Chain.ScanBack, or on Entries that are already downloaded.
+//
+// Filters are composed with All, Any, and Not:
+//
+//	f := filter.All(
+//		filter.ExtID(0, []byte("memo")),
+//		filter.Time(start, end),
+//		filter.Not(filter.Size(0, 100)))
+//
+// Each constructor copies its arguments and does any preparation up front, so
+// evaluating a Filter does not allocate.
+package filter
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Filter reports whether an Entry matches.
+type Filter func(e factom.Entry) bool
+
+// Match returns the Entries in es that match f, in order.
+func (f Filter) Match(es []factom.Entry) []factom.Entry {
+	var matched []factom.Entry
+	for _, e := range es {
+		if f(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Scan returns a callback for factom.Chain.ScanBack that calls fn with only
+// the Entries that match f.
+func (f Filter) Scan(fn func(factom.Entry) (bool, error)) func(
+	factom.Entry) (bool, error) {
+	return func(e factom.Entry) (bool, error) {
+		if !f(e) {
+			return false, nil
+		}
+		return fn(e)
+	}
+}
+
+// All matches Entries that match all filters. All with no filters matches
+// all Entries.
+func All(filters ...Filter) Filter {
+	filters = append([]Filter(nil), filters...)
+	return func(e factom.Entry) bool {
+		for _, f := range filters {
+			if !f(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any matches Entries that match at least one of filters. Any with no filters
+// matches no Entries.
+func Any(filters ...Filter) Filter {
+	filters = append([]Filter(nil), filters...)
+	return func(e factom.Entry) bool {
+		for _, f := range filters {
+			if f(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches Entries that do not match f.
+func Not(f Filter) Filter {
+	return func(e factom.Entry) bool { return !f(e) }
+}
+
+// ExtID matches Entries whose ExtID at index i equals value.
+func ExtID(i int, value []byte) Filter {
+	value = append([]byte{}, value...)
+	return func(e factom.Entry) bool {
+		return i < len(e.ExtIDs) && bytes.Equal(e.ExtIDs[i], value)
+	}
+}
+
+// ExtIDPrefix matches Entries whose ExtID at index i begins with prefix.
+func ExtIDPrefix(i int, prefix []byte) Filter {
+	prefix = append([]byte{}, prefix...)
+	return func(e factom.Entry) bool {
+		return i < len(e.ExtIDs) && bytes.HasPrefix(e.ExtIDs[i], prefix)
+	}
+}
+
+// Contains matches Entries whose Content contains sub.
+func Contains(sub []byte) Filter {
+	sub = append([]byte{}, sub...)
+	return func(e factom.Entry) bool {
+		return bytes.Contains(e.Content, sub)
+	}
+}
+
+// Size matches Entries whose Entry.MarshalBinaryLen is within [min, max]. If
+// max is less than or equal to zero, there is no maximum.
+func Size(min, max int) Filter {
+	return func(e factom.Entry) bool {
+		size := e.MarshalBinaryLen()
+		return size >= min && (max <= 0 || size <= max)
+	}
+}
+
+// Time matches Entries whose Timestamp is within [start, end). A zero start
+// or end leaves that side of the range open.
+func Time(start, end time.Time) Filter {
+	return func(e factom.Entry) bool {
+		return (start.IsZero() || !e.Timestamp.Before(start)) &&
+			(end.IsZero() || e.Timestamp.Before(end))
+	}
+}
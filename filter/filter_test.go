@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	e := factom.Entry{
+		ExtIDs:    []factom.Bytes{factom.Bytes("memo"), factom.Bytes("abc")},
+		Content:   factom.Bytes("hello, world"),
+		Timestamp: ts,
+	}
+	size := e.MarshalBinaryLen()
+
+	for _, test := range []struct {
+		Name   string
+		Filter Filter
+		Match  bool
+	}{
+		{"ExtID", ExtID(0, []byte("memo")), true},
+		{"ExtID/mismatch", ExtID(1, []byte("memo")), false},
+		{"ExtID/out of range", ExtID(2, []byte("memo")), false},
+		{"ExtIDPrefix", ExtIDPrefix(1, []byte("ab")), true},
+		{"ExtIDPrefix/mismatch", ExtIDPrefix(1, []byte("bc")), false},
+		{"Contains", Contains([]byte("world")), true},
+		{"Contains/mismatch", Contains([]byte("moon")), false},
+		{"Size", Size(size, size), true},
+		{"Size/no max", Size(size, 0), true},
+		{"Size/too small", Size(size+1, 0), false},
+		{"Size/too large", Size(0, size-1), false},
+		{"Time", Time(ts, ts.Add(time.Second)), true},
+		{"Time/open", Time(time.Time{}, time.Time{}), true},
+		{"Time/end exclusive", Time(time.Time{}, ts), false},
+		{"Time/before", Time(ts.Add(time.Second), time.Time{}), false},
+		{"All", All(ExtID(0, []byte("memo")),
+			Contains([]byte("hello"))), true},
+		{"All/mismatch", All(ExtID(0, []byte("memo")),
+			Contains([]byte("moon"))), false},
+		{"All/empty", All(), true},
+		{"Any", Any(Contains([]byte("moon")),
+			Contains([]byte("hello"))), true},
+		{"Any/empty", Any(), false},
+		{"Not", Not(Contains([]byte("moon"))), true},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Match, test.Filter(e))
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	es := []factom.Entry{
+		{Content: factom.Bytes("a")},
+		{Content: factom.Bytes("b")},
+		{Content: factom.Bytes("ab")},
+	}
+	f := Contains([]byte("a"))
+	assert.Equal(t, []factom.Entry{es[0], es[2]}, f.Match(es))
+
+	var scanned []factom.Entry
+	fn := f.Scan(func(e factom.Entry) (bool, error) {
+		scanned = append(scanned, e)
+		return len(scanned) == 2, nil
+	})
+	for _, e := range es {
+		stop, err := fn(e)
+		assert.NoError(t, err)
+		if stop {
+			break
+		}
+	}
+	assert.Equal(t, []factom.Entry{es[0], es[2]}, scanned)
+}
@@ -0,0 +1,157 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// formatValue writes str or raw to s according to verb. All unsupported
+// verbs are reported the same way fmt reports them for other types.
+func formatValue(s fmt.State, verb rune, str string, raw []byte, goStr string) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			fmt.Fprint(s, goStr)
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		fmt.Fprintf(s, directive(s, verb), str)
+	case 'x', 'X':
+		fmt.Fprintf(s, directive(s, verb), raw)
+	default:
+		fmt.Fprintf(s, "%%!%c(%v)", verb, str)
+	}
+}
+
+// directive reconstructs the formatting directive, including flags, width
+// and precision, that s was created from, with verb 'v' replaced by 's'.
+func directive(s fmt.State, verb rune) string {
+	d := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			d = append(d, byte(flag))
+		}
+	}
+	if width, ok := s.Width(); ok {
+		d = strconv.AppendInt(d, int64(width), 10)
+	}
+	if prec, ok := s.Precision(); ok {
+		d = append(d, '.')
+		d = strconv.AppendInt(d, int64(prec), 10)
+	}
+	if verb == 'v' {
+		verb = 's'
+	}
+	return string(append(d, byte(verb)))
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports.
+func (b Bytes32) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, b.String(), b[:], b.GoString())
+}
+
+// GoString returns a Go expression that evaluates to b.
+func (b Bytes32) GoString() string {
+	return fmt.Sprintf("factom.NewBytes32(%q)", b.String())
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports.
+func (h EntryHash) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, h.String(), h[:], h.GoString())
+}
+
+// GoString returns a Go expression that evaluates to h.
+func (h EntryHash) GoString() string {
+	return fmt.Sprintf("factom.NewEntryHash(%q)", h.String())
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports.
+func (h KeyMR) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, h.String(), h[:], h.GoString())
+}
+
+// GoString returns a Go expression that evaluates to h.
+func (h KeyMR) GoString() string {
+	return fmt.Sprintf("factom.NewKeyMR(%q)", h.String())
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports.
+func (h TxID) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, h.String(), h[:], h.GoString())
+}
+
+// GoString returns a Go expression that evaluates to h.
+func (h TxID) GoString() string {
+	return fmt.Sprintf("factom.NewTxID(%q)", h.String())
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports. The %x and %X verbs print the RCD Hash.
+func (adr FAAddress) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, adr.String(), adr[:], adr.GoString())
+}
+
+// GoString returns a Go expression that evaluates to adr.
+func (adr FAAddress) GoString() string {
+	return fmt.Sprintf("factom.FAAddress(%#v)", Bytes32(adr))
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports. The %x and %X verbs print the private key seed.
+func (adr FsAddress) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, adr.String(), adr[:], adr.GoString())
+}
+
+// GoString returns a Go expression that evaluates to adr.
+func (adr FsAddress) GoString() string {
+	return fmt.Sprintf("factom.FsAddress(%#v)", Bytes32(adr))
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports. The %x and %X verbs print the public key.
+func (adr ECAddress) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, adr.String(), adr[:], adr.GoString())
+}
+
+// GoString returns a Go expression that evaluates to adr.
+func (adr ECAddress) GoString() string {
+	return fmt.Sprintf("factom.ECAddress(%#v)", Bytes32(adr))
+}
+
+// Format implements fmt.Formatter. See the package documentation for the
+// verbs it supports. The %x and %X verbs print the private key seed.
+func (adr EsAddress) Format(s fmt.State, verb rune) {
+	formatValue(s, verb, adr.String(), adr[:], adr.GoString())
+}
+
+// GoString returns a Go expression that evaluates to adr.
+func (adr EsAddress) GoString() string {
+	return fmt.Sprintf("factom.EsAddress(%#v)", Bytes32(adr))
+}
@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	const hexStr = "72177d733dcd0492066b79c5f3e417aef7f22909674f7dc351ca13b04742bb91"
+	const faStr = "FA2PdKfzGP5XwoSbeW1k9QunCHwC8DY6d8xgEdfm57qfR31nTueb"
+	fa, err := NewFAAddress(faStr)
+	require.NoError(t, err)
+	faHex := fmt.Sprintf("%x", fa[:])
+
+	for _, test := range []struct {
+		Name   string
+		Format string
+		Value  interface{}
+		Exp    string
+	}{{
+		Name:   "Bytes32/s",
+		Format: "%s",
+		Value:  NewBytes32(hexStr),
+		Exp:    hexStr,
+	}, {
+		Name:   "EntryHash/v",
+		Format: "%v",
+		Value:  NewEntryHash(hexStr),
+		Exp:    hexStr,
+	}, {
+		Name:   "KeyMR/short",
+		Format: "%.8s",
+		Value:  NewKeyMR(hexStr),
+		Exp:    hexStr[:8],
+	}, {
+		Name:   "TxID/X",
+		Format: "%X",
+		Value:  NewTxID(hexStr),
+		Exp:    strings.ToUpper(hexStr),
+	}, {
+		Name:   "TxID/q",
+		Format: "%q",
+		Value:  NewTxID(hexStr),
+		Exp:    `"` + hexStr + `"`,
+	}, {
+		Name:   "TxID/width",
+		Format: "%-6.4v|",
+		Value:  NewTxID(hexStr),
+		Exp:    hexStr[:4] + "  |",
+	}, {
+		Name:   "KeyMR/GoString",
+		Format: "%#v",
+		Value:  NewKeyMR(hexStr),
+		Exp:    `factom.NewKeyMR("` + hexStr + `")`,
+	}, {
+		Name:   "KeyMR/bad verb",
+		Format: "%d",
+		Value:  NewKeyMR(hexStr),
+		Exp:    "%!d(" + hexStr + ")",
+	}, {
+		Name:   "FAAddress/s",
+		Format: "%s",
+		Value:  fa,
+		Exp:    faStr,
+	}, {
+		Name:   "FAAddress/short",
+		Format: "%.8v",
+		Value:  fa,
+		Exp:    faStr[:8],
+	}, {
+		Name:   "FAAddress/x",
+		Format: "%x",
+		Value:  fa,
+		Exp:    faHex,
+	}, {
+		Name:   "FAAddress/GoString",
+		Format: "%#v",
+		Value:  fa,
+		Exp: `factom.FAAddress(factom.NewBytes32("` +
+			faHex + `"))`,
+	}, {
+		Name:   "ECAddress/s",
+		Format: "%s",
+		Value:  ECAddress(fa),
+		Exp:    ECAddress(fa).String(),
+	}} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Exp, fmt.Sprintf(test.Format, test.Value))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom/frost/internal/edwards25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdwards25519(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The base point must match the standard encoding.
+	one := Identifier(1).scalar()
+	enc := encodePoint(baseMult(one))
+	assert.Equal("5866666666666666666666666666666666666666666666666666666666666666",
+		hex.EncodeToString(enc[:]))
+
+	// Scalar multiplication matches crypto/ed25519.
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	h := sha512.Sum512(priv.Seed())
+	s, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	require.NoError(err)
+	pub := encodePoint(baseMult(s))
+	assert.Equal([]byte(priv.Public().(ed25519.PublicKey)), pub[:])
+
+	// Decoding round trips, but the identity is rejected.
+	p, err := decodePoint(pub[:])
+	require.NoError(err)
+	assert.Equal(pub, encodePoint(p))
+	id := encodePoint(edwards25519.NewIdentityPoint())
+	_, err = decodePoint(id[:])
+	assert.EqualError(err, "invalid point: identity")
+
+	// L is not a canonical scalar encoding.
+	l, _ := hex.DecodeString(
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")
+	_, err = decodeScalar(l)
+	assert.EqualError(err, "invalid scalar encoding")
+}
+
+func TestInvert(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		x, err := randomScalar(rand.Reader)
+		require.NoError(t, err)
+		x.Multiply(x, invert(x))
+		assert.Equal(t, 1, x.Equal(Identifier(1).scalar()))
+	}
+}
+
+func TestLagrange(t *testing.T) {
+	// Over ids {1, 2, 3}, the coefficients at 0 are 3, -3 and 1.
+	pkg := signingPackage{ids: []Identifier{1, 2, 3}}
+	three := Identifier(3).scalar()
+	assert.Equal(t, 1, pkg.lagrange(1).Equal(three))
+	assert.Equal(t, 1, pkg.lagrange(2).Equal(
+		edwards25519.NewScalar().Negate(three)))
+	assert.Equal(t, 1, pkg.lagrange(3).Equal(Identifier(1).scalar()))
+}
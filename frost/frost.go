@@ -0,0 +1,470 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package frost implements FROST(Ed25519, SHA-512) threshold signing, as
+// specified by RFC 9591, for Factom Fs and Es keys.
+//
+// A FROST signature is a standard ed25519 signature, so a threshold of
+// signers can produce signatures for Factoid Transactions and Entry commits
+// without any single machine ever holding the complete private key.
+//
+// SplitKey uses a trusted dealer to split an existing private key, such as
+// FsAddress.PrivateKey() or EsAddress.PrivateKey(), into KeyShares that are
+// distributed to the signers. The dealer must then destroy the original key.
+//
+// Signing has two rounds. First, each signer calls KeyShare.Commit and
+// publishes its Commitment, while keeping its Nonce secret. Then each signer
+// calls KeyShare.Sign with the message and the Commitments of all
+// participating signers, and publishes its SignatureShare. Finally a
+// coordinator calls Aggregate to verify the SignatureShares and produce the
+// ed25519 signature. The Signer type performs the coordinator's role for a
+// set of Participants, and implements factom.RCDSigner and
+// factom.CommitSigner.
+//
+// All group and scalar arithmetic on secret values is constant time.
+package frost
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Factom-Asset-Tokens/factom/frost/internal/edwards25519"
+)
+
+const contextString = "FROST-ED25519-SHA512-v1"
+
+// hashToScalar returns SHA-512(prefix|msgs...) mod L, where the digest is
+// interpreted as a little endian integer.
+func hashToScalar(prefix string, msgs ...[]byte) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write([]byte(prefix))
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	s, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func hash(tag string, msgs ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(contextString + tag))
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+// Identifier identifies a signer. Identifiers start at 1.
+type Identifier uint16
+
+func (id Identifier) scalar() *edwards25519.Scalar {
+	enc := id.encode()
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(enc[:])
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (id Identifier) encode() [32]byte {
+	var enc [32]byte
+	binary.LittleEndian.PutUint16(enc[:], uint16(id))
+	return enc
+}
+
+func encodeScalar(s *edwards25519.Scalar) [32]byte {
+	var enc [32]byte
+	copy(enc[:], s.Bytes())
+	return enc
+}
+
+func decodeScalar(enc []byte) (*edwards25519.Scalar, error) {
+	return edwards25519.NewScalar().SetCanonicalBytes(enc)
+}
+
+func encodePoint(p *edwards25519.Point) [32]byte {
+	var enc [32]byte
+	copy(enc[:], p.Bytes())
+	return enc
+}
+
+// decodePoint decodes enc and rejects the identity element, as required by
+// RFC 9591 for all public elements.
+func decodePoint(enc []byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(enc)
+	if err != nil {
+		return nil, err
+	}
+	if p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, fmt.Errorf("invalid point: identity")
+	}
+	return p, nil
+}
+
+func baseMult(s *edwards25519.Scalar) *edwards25519.Point {
+	return new(edwards25519.Point).ScalarBaseMult(s)
+}
+
+// lMinus2 is the big endian encoding of L-2, the exponent of a scalar inverse.
+var lMinus2 = [32]byte{
+	0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x14, 0xde, 0xf9, 0xde, 0xa2, 0xf7, 0x9c, 0xd6,
+	0x58, 0x12, 0x63, 0x1a, 0x5c, 0xf5, 0xd3, 0xeb,
+}
+
+// invert returns 1/x by computing x^(L-2). The exponent is public, so the
+// sequence of operations does not depend on x.
+func invert(x *edwards25519.Scalar) *edwards25519.Scalar {
+	r := Identifier(1).scalar()
+	for _, b := range lMinus2 {
+		for i := 7; i >= 0; i-- {
+			r.Multiply(r, r)
+			if b>>uint(i)&1 == 1 {
+				r.Multiply(r, x)
+			}
+		}
+	}
+	return r
+}
+
+// KeyShare is a signer's share of a threshold key. The Secret must be kept
+// private.
+type KeyShare struct {
+	Identifier Identifier
+
+	// Secret is the little endian encoding of the secret share scalar.
+	Secret [32]byte
+
+	PublicKeys
+}
+
+// PublicKeys are the public keys of a threshold key, which are used to verify
+// SignatureShares.
+type PublicKeys struct {
+	// GroupKey is the ed25519 public key that verifies signatures.
+	GroupKey ed25519.PublicKey
+
+	// Shares are the public keys of each signer's share.
+	Shares map[Identifier][32]byte
+
+	// Threshold is the minimum number of signers required to sign.
+	Threshold int
+}
+
+// SplitKey splits key into n KeyShares, any threshold of which can produce a
+// signature, using the rand source for the secret sharing polynomial.
+func SplitKey(key ed25519.PrivateKey, threshold, n int,
+	rand io.Reader) ([]KeyShare, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length")
+	}
+	if threshold < 2 || threshold > n {
+		return nil, fmt.Errorf("invalid threshold: %v of %v", threshold, n)
+	}
+	if n >= 1<<16 {
+		return nil, fmt.Errorf("too many shares: %v", n)
+	}
+
+	// Derive the ed25519 secret scalar from the seed as in RFC 8032.
+	h := sha512.Sum512(key.Seed())
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	groupKey := encodePoint(baseMult(secret))
+	if !bytes.Equal(groupKey[:], key.Public().(ed25519.PublicKey)) {
+		return nil, fmt.Errorf("invalid private key")
+	}
+
+	coefficients := []*edwards25519.Scalar{secret}
+	for i := 1; i < threshold; i++ {
+		c, err := randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		coefficients = append(coefficients, c)
+	}
+
+	pub := PublicKeys{
+		GroupKey:  ed25519.PublicKey(groupKey[:]),
+		Shares:    make(map[Identifier][32]byte, n),
+		Threshold: threshold,
+	}
+	shares := make([]KeyShare, n)
+	for i := range shares {
+		id := Identifier(i + 1)
+		// Evaluate the polynomial at id using Horner's method.
+		y := edwards25519.NewScalar()
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			y.MultiplyAdd(y, id.scalar(), coefficients[j])
+		}
+		shares[i] = KeyShare{Identifier: id, Secret: encodeScalar(y)}
+		pub.Shares[id] = encodePoint(baseMult(y))
+	}
+	for i := range shares {
+		shares[i].PublicKeys = pub
+	}
+	return shares, nil
+}
+
+func randomScalar(rand io.Reader) (*edwards25519.Scalar, error) {
+	var buf [64]byte
+	if _, err := io.ReadFull(rand, buf[:]); err != nil {
+		return nil, err
+	}
+	return edwards25519.NewScalar().SetUniformBytes(buf[:])
+}
+
+// Nonce is a signer's secret nonce pair for a single signature. A Nonce must
+// never be used more than once.
+type Nonce struct {
+	Hiding, Binding [32]byte
+
+	Commitment Commitment
+}
+
+// Commitment is the public commitment to a Nonce.
+type Commitment struct {
+	Identifier      Identifier
+	Hiding, Binding [32]byte
+}
+
+// Commit generates a new Nonce and its Commitment for the first round of
+// signing, using the rand source.
+func (ks KeyShare) Commit(rand io.Reader) (Nonce, error) {
+	nonce := Nonce{Commitment: Commitment{Identifier: ks.Identifier}}
+	for _, n := range []struct{ secret, commitment *[32]byte }{
+		{&nonce.Hiding, &nonce.Commitment.Hiding},
+		{&nonce.Binding, &nonce.Commitment.Binding},
+	} {
+		var random [32]byte
+		if _, err := io.ReadFull(rand, random[:]); err != nil {
+			return Nonce{}, err
+		}
+		k := hashToScalar(contextString+"nonce", random[:], ks.Secret[:])
+		*n.secret = encodeScalar(k)
+		*n.commitment = encodePoint(baseMult(k))
+	}
+	return nonce, nil
+}
+
+// SignatureShare is a signer's share of a signature.
+type SignatureShare struct {
+	Identifier Identifier
+	Share      [32]byte
+}
+
+// signingPackage holds the values shared by all signers for a given message and
+// set of commitments.
+type signingPackage struct {
+	bindingFactors map[Identifier]*edwards25519.Scalar
+	groupCommit    *edwards25519.Point
+	challenge      *edwards25519.Scalar
+	ids            []Identifier
+}
+
+func newSigningPackage(pub PublicKeys, msg []byte,
+	commitments []Commitment) (signingPackage, error) {
+	if len(commitments) < pub.Threshold {
+		return signingPackage{}, fmt.Errorf("insufficient commitments: %v",
+			len(commitments))
+	}
+	sorted := sort.SliceIsSorted(commitments, func(i, j int) bool {
+		return commitments[i].Identifier < commitments[j].Identifier
+	})
+	if !sorted {
+		return signingPackage{}, fmt.Errorf("commitments not sorted")
+	}
+
+	var encoded []byte
+	for i, c := range commitments {
+		if i > 0 && c.Identifier == commitments[i-1].Identifier {
+			return signingPackage{}, fmt.Errorf(
+				"duplicate commitment: %v", c.Identifier)
+		}
+		if _, ok := pub.Shares[c.Identifier]; !ok {
+			return signingPackage{}, fmt.Errorf(
+				"unknown identifier: %v", c.Identifier)
+		}
+		id := c.Identifier.encode()
+		encoded = append(encoded, id[:]...)
+		encoded = append(encoded, c.Hiding[:]...)
+		encoded = append(encoded, c.Binding[:]...)
+	}
+
+	prefix := append([]byte(nil), pub.GroupKey...)
+	prefix = append(prefix, hash("msg", msg)...)
+	prefix = append(prefix, hash("com", encoded)...)
+
+	pkg := signingPackage{
+		bindingFactors: make(map[Identifier]*edwards25519.Scalar,
+			len(commitments)),
+		groupCommit: edwards25519.NewIdentityPoint(),
+	}
+	for _, c := range commitments {
+		id := c.Identifier.encode()
+		rho := hashToScalar(contextString+"rho", prefix, id[:])
+		pkg.bindingFactors[c.Identifier] = rho
+		pkg.ids = append(pkg.ids, c.Identifier)
+
+		hiding, err := decodePoint(c.Hiding[:])
+		if err != nil {
+			return signingPackage{}, fmt.Errorf("commitment %v: %w",
+				c.Identifier, err)
+		}
+		binding, err := decodePoint(c.Binding[:])
+		if err != nil {
+			return signingPackage{}, fmt.Errorf("commitment %v: %w",
+				c.Identifier, err)
+		}
+		pkg.groupCommit.Add(pkg.groupCommit, hiding)
+		pkg.groupCommit.Add(pkg.groupCommit,
+			binding.ScalarMult(rho, binding))
+	}
+
+	r := encodePoint(pkg.groupCommit)
+	pkg.challenge = hashToScalar("", r[:], pub.GroupKey, msg)
+	return pkg, nil
+}
+
+// lagrange returns the Lagrange coefficient for id at 0 over pkg.ids.
+func (pkg signingPackage) lagrange(id Identifier) *edwards25519.Scalar {
+	x := id.scalar()
+	num, den := Identifier(1).scalar(), Identifier(1).scalar()
+	for _, j := range pkg.ids {
+		if j == id {
+			continue
+		}
+		xj := j.scalar()
+		num.Multiply(num, xj)
+		den.Multiply(den, edwards25519.NewScalar().Subtract(xj, x))
+	}
+	return num.Multiply(num, invert(den))
+}
+
+// Sign returns the SignatureShare of msg for the second round of signing. The
+// commitments must include nonce.Commitment, and be sorted by Identifier.
+//
+// The nonce must be discarded after calling Sign.
+func (ks KeyShare) Sign(msg []byte, nonce Nonce,
+	commitments []Commitment) (SignatureShare, error) {
+	var found bool
+	for _, c := range commitments {
+		if c == nonce.Commitment && c.Identifier == ks.Identifier {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return SignatureShare{}, fmt.Errorf("missing own commitment")
+	}
+
+	pkg, err := newSigningPackage(ks.PublicKeys, msg, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	secret, err := decodeScalar(ks.Secret[:])
+	if err != nil {
+		return SignatureShare{}, fmt.Errorf("invalid secret: %w", err)
+	}
+	hiding, err := decodeScalar(nonce.Hiding[:])
+	if err != nil {
+		return SignatureShare{}, fmt.Errorf("invalid nonce: %w", err)
+	}
+	binding, err := decodeScalar(nonce.Binding[:])
+	if err != nil {
+		return SignatureShare{}, fmt.Errorf("invalid nonce: %w", err)
+	}
+
+	// z = d + e*rho + lambda*c*s
+	z := edwards25519.NewScalar().MultiplyAdd(binding,
+		pkg.bindingFactors[ks.Identifier], hiding)
+	lc := edwards25519.NewScalar().Multiply(pkg.lagrange(ks.Identifier),
+		pkg.challenge)
+	z.MultiplyAdd(lc, secret, z)
+	return SignatureShare{Identifier: ks.Identifier,
+		Share: encodeScalar(z)}, nil
+}
+
+// Aggregate verifies the signature shares and returns the ed25519 signature
+// of msg. The shares must correspond to the commitments, which must be sorted
+// by Identifier.
+func Aggregate(pub PublicKeys, msg []byte, commitments []Commitment,
+	shares []SignatureShare) ([]byte, error) {
+	if len(shares) != len(commitments) {
+		return nil, fmt.Errorf("shares and commitments mismatch")
+	}
+	pkg, err := newSigningPackage(pub, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	z := edwards25519.NewScalar()
+	for i, share := range shares {
+		c := commitments[i]
+		if share.Identifier != c.Identifier {
+			return nil, fmt.Errorf("shares and commitments mismatch")
+		}
+		zi, err := decodeScalar(share.Share[:])
+		if err != nil {
+			return nil, fmt.Errorf("share %v: %w", share.Identifier, err)
+		}
+
+		// Verify zi*B == D + rho*E + lambda*c*PK.
+		pubShare := pub.Shares[c.Identifier]
+		pk, err := decodePoint(pubShare[:])
+		if err != nil {
+			return nil, fmt.Errorf("public share %v: %w",
+				share.Identifier, err)
+		}
+		hiding, _ := decodePoint(c.Hiding[:])
+		binding, _ := decodePoint(c.Binding[:])
+		lc := edwards25519.NewScalar().Multiply(pkg.lagrange(c.Identifier),
+			pkg.challenge)
+		expected := hiding.Add(hiding,
+			binding.ScalarMult(pkg.bindingFactors[c.Identifier], binding))
+		expected.Add(expected, pk.ScalarMult(lc, pk))
+		if baseMult(zi).Equal(expected) != 1 {
+			return nil, fmt.Errorf("share %v: invalid signature share",
+				share.Identifier)
+		}
+		z.Add(z, zi)
+	}
+
+	r := encodePoint(pkg.groupCommit)
+	zEnc := encodeScalar(z)
+	sig := append(r[:], zEnc[:]...)
+	if !ed25519.Verify(pub.GroupKey, msg, sig) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return sig, nil
+}
@@ -0,0 +1,209 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package frost_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/frost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func splitFs(t *testing.T, threshold, n int) (factom.FsAddress, []KeyShare) {
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	shares, err := SplitKey(fs.PrivateKey(), threshold, n, rand.Reader)
+	require.NoError(t, err)
+	return fs, shares
+}
+
+func TestThresholdSign(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	fs, shares := splitFs(t, 2, 3)
+	require.Len(shares, 3)
+	assert.Equal(fs.PublicKey(), shares[0].GroupKey)
+
+	msg := []byte("message")
+	for _, subset := range [][]int{{0, 1}, {0, 2}, {1, 2}, {0, 1, 2}} {
+		var nonces []Nonce
+		var commitments []Commitment
+		for _, i := range subset {
+			nonce, err := shares[i].Commit(rand.Reader)
+			require.NoError(err)
+			nonces = append(nonces, nonce)
+			commitments = append(commitments, nonce.Commitment)
+		}
+		var sigShares []SignatureShare
+		for j, i := range subset {
+			share, err := shares[i].Sign(msg, nonces[j], commitments)
+			require.NoError(err)
+			sigShares = append(sigShares, share)
+		}
+		sig, err := Aggregate(shares[0].PublicKeys, msg, commitments,
+			sigShares)
+		require.NoError(err)
+		assert.True(ed25519.Verify(fs.PublicKey(), msg, sig))
+
+		// A corrupted share is detected.
+		sigShares[0].Share[0]++
+		_, err = Aggregate(shares[0].PublicKeys, msg, commitments,
+			sigShares)
+		assert.Error(err)
+	}
+}
+
+func TestSplitKeyInvalid(t *testing.T) {
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	_, err = SplitKey(fs.PrivateKey(), 1, 3, rand.Reader)
+	assert.EqualError(t, err, "invalid threshold: 1 of 3")
+	_, err = SplitKey(fs.PrivateKey(), 4, 3, rand.Reader)
+	assert.EqualError(t, err, "invalid threshold: 4 of 3")
+	_, err = SplitKey(fs.PrivateKey()[:32], 2, 3, rand.Reader)
+	assert.EqualError(t, err, "invalid private key length")
+}
+
+func TestSignInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	_, shares := splitFs(t, 2, 3)
+	msg := []byte("message")
+
+	n0, err := shares[0].Commit(rand.Reader)
+	require.NoError(err)
+	n1, err := shares[1].Commit(rand.Reader)
+	require.NoError(err)
+
+	_, err = shares[0].Sign(msg, n0, []Commitment{n0.Commitment})
+	assert.EqualError(err, "insufficient commitments: 1")
+
+	_, err = shares[0].Sign(msg, n0, []Commitment{n1.Commitment})
+	assert.EqualError(err, "missing own commitment")
+
+	_, err = shares[0].Sign(msg, n0,
+		[]Commitment{n1.Commitment, n0.Commitment})
+	assert.EqualError(err, "commitments not sorted")
+
+	_, err = shares[0].Sign(msg, n0,
+		[]Commitment{n0.Commitment, n0.Commitment})
+	assert.EqualError(err, "duplicate commitment: 1")
+}
+
+func newSigner(t *testing.T, threshold, n int) (factom.FsAddress, Signer,
+	[]*LocalParticipant) {
+	fs, shares := splitFs(t, threshold, n)
+	signer := Signer{PublicKeys: shares[0].PublicKeys}
+	var local []*LocalParticipant
+	for _, ks := range shares {
+		p := &LocalParticipant{KeyShare: ks}
+		local = append(local, p)
+	}
+	return fs, signer, local
+}
+
+func TestSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	fs, signer, local := newSigner(t, 2, 3)
+	assert.Equal(fs.FAAddress(), signer.FAAddress())
+	assert.Equal(fs.RCD(), signer.RCD())
+
+	_, err := signer.TrySign([]byte("msg"))
+	assert.EqualError(err, "insufficient participants: 0 of 2")
+	assert.Nil(signer.Sign([]byte("msg")))
+
+	signer.Participants = []Participant{local[2], local[0]}
+	sig, err := signer.TrySign([]byte("msg"))
+	require.NoError(err)
+	assert.True(ed25519.Verify(fs.PublicKey(), []byte("msg"), sig))
+
+	// A Nonce may only be used once.
+	c, err := local[0].Commit()
+	require.NoError(err)
+	c1, err := local[1].Commit()
+	require.NoError(err)
+	_, err = local[0].Sign([]byte("a"), []Commitment{c, c1})
+	require.NoError(err)
+	_, err = local[0].Sign([]byte("b"), []Commitment{c, c1})
+	assert.EqualError(err, "unknown commitment")
+}
+
+func TestSignerTransaction(t *testing.T) {
+	require := require.New(t)
+	_, signer, local := newSigner(t, 2, 3)
+	signer.Participants = []Participant{local[0], local[1]}
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	input, output := signer.FAAddress(), fs.FAAddress()
+	tx := factom.Transaction{
+		TimestampSalt: time.Now(),
+		FCTInputs: []factom.AddressAmount{{
+			Address: input[:],
+			Amount:  1e8}},
+		FCTOutputs: []factom.AddressAmount{{
+			Address: output[:],
+			Amount:  1e8 - 1e6}},
+		Signatures: make([]factom.RCDSignature, 1),
+	}
+	_, err = tx.Sign(signer)
+	require.NoError(err)
+	data, err := tx.MarshalBinary()
+	require.NoError(err)
+	var parsed factom.Transaction
+	require.NoError(parsed.UnmarshalBinary(data))
+}
+
+func TestSignerCommit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	_, signer, local := newSigner(t, 2, 2)
+	signer.Participants = []Participant{local[0], local[1]}
+
+	chainID := factom.NewBytes32(
+		"0000000000000000000000000000000000000000000000000000000000000001")
+	e := factom.Entry{ChainID: &chainID, ExtIDs: []factom.Bytes{},
+		Content: factom.Bytes("content")}
+	data, err := e.MarshalBinary()
+	require.NoError(err)
+	hash := factom.ComputeEntryHash(data)
+	commit, _, err := factom.GenerateCommitWithSigner(signer, data, &hash,
+		false)
+	require.NoError(err)
+
+	signed := commit[:len(commit)-96]
+	pubKey := commit[len(commit)-96 : len(commit)-64]
+	sig := commit[len(commit)-64:]
+	assert.Equal([]byte(signer.ECAddress().PublicKey()), pubKey)
+	assert.True(ed25519.Verify(pubKey, signed, sig))
+
+	signer.Participants = nil
+	_, _, err = factom.GenerateCommitWithSigner(signer, data, &hash, false)
+	assert.EqualError(err, "signing failed")
+}
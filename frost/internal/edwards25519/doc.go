@@ -0,0 +1,17 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package edwards25519 implements group logic for the twisted Edwards curve
+//
+//	-x^2 + y^2 = 1 + -(121665/121666)*x^2*y^2
+//
+// This is better known as the Edwards curve equivalent to Curve25519, and is
+// the curve used by the Ed25519 signature scheme.
+//
+// This is a copy of crypto/internal/fips140/edwards25519 from the Go standard
+// library, which is also the basis of filippo.io/edwards25519, restricted to
+// the portable field arithmetic. All operations on secret values are constant
+// time. It is internal to package frost, and adapted only as needed to build
+// with the Go version declared in go.mod.
+package edwards25519
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Participant is a signer taking part in threshold signing, which may be
+// local or remote.
+type Participant interface {
+	// Identifier returns the Identifier of the Participant's KeyShare.
+	Identifier() Identifier
+
+	// Commit returns a Commitment to a new Nonce, which the Participant
+	// keeps secret until Sign is called.
+	Commit() (Commitment, error)
+
+	// Sign returns the Participant's SignatureShare of msg using the
+	// Nonce committed to in commitments, which it must then discard.
+	Sign(msg []byte, commitments []Commitment) (SignatureShare, error)
+}
+
+// LocalParticipant is a Participant that holds its KeyShare in memory.
+type LocalParticipant struct {
+	KeyShare KeyShare
+
+	// Rand is the source of randomness for Nonces. If nil, crypto/rand
+	// is used.
+	Rand io.Reader
+
+	mu     sync.Mutex
+	nonces map[Commitment]Nonce
+}
+
+var _ Participant = &LocalParticipant{}
+
+// Identifier returns p.KeyShare.Identifier.
+func (p *LocalParticipant) Identifier() Identifier {
+	return p.KeyShare.Identifier
+}
+
+// Commit generates and stores a new Nonce and returns its Commitment.
+func (p *LocalParticipant) Commit() (Commitment, error) {
+	r := p.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	nonce, err := p.KeyShare.Commit(r)
+	if err != nil {
+		return Commitment{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.nonces == nil {
+		p.nonces = make(map[Commitment]Nonce)
+	}
+	p.nonces[nonce.Commitment] = nonce
+	return nonce.Commitment, nil
+}
+
+// Sign signs msg with the stored Nonce for p's Commitment in commitments. The
+// Nonce is deleted, even if signing fails, so that it is never reused.
+func (p *LocalParticipant) Sign(msg []byte,
+	commitments []Commitment) (SignatureShare, error) {
+	p.mu.Lock()
+	var nonce Nonce
+	var found bool
+	for _, c := range commitments {
+		if nonce, found = p.nonces[c]; found {
+			delete(p.nonces, c)
+			break
+		}
+	}
+	p.mu.Unlock()
+	if !found {
+		return SignatureShare{}, fmt.Errorf("unknown commitment")
+	}
+	return p.KeyShare.Sign(msg, nonce, commitments)
+}
+
+// Signer coordinates threshold signing between Participants. It implements
+// factom.RCDSigner, for Factoid Transactions, and factom.CommitSigner, for
+// Entry commits.
+type Signer struct {
+	PublicKeys
+
+	// Participants sign in each round. At least PublicKeys.Threshold are
+	// required.
+	Participants []Participant
+}
+
+var (
+	_ factom.RCDSigner    = Signer{}
+	_ factom.CommitSigner = Signer{}
+)
+
+// PublicKey returns s.GroupKey.
+func (s Signer) PublicKey() ed25519.PublicKey {
+	return s.GroupKey
+}
+
+// RCD returns the RCD for s.GroupKey.
+func (s Signer) RCD() factom.RCD {
+	return append(factom.RCD{byte(factom.RCDType01)}, s.GroupKey...)
+}
+
+// FAAddress returns the FAAddress for s.GroupKey.
+func (s Signer) FAAddress() factom.FAAddress {
+	return s.RCD().FAAddress()
+}
+
+// ECAddress returns the ECAddress for s.GroupKey.
+func (s Signer) ECAddress() factom.ECAddress {
+	var ec factom.ECAddress
+	copy(ec[:], s.GroupKey)
+	return ec
+}
+
+// Sign msg using TrySign. If signing fails, nil is returned.
+func (s Signer) Sign(msg []byte) []byte {
+	sig, _ := s.TrySign(msg)
+	return sig
+}
+
+// TrySign runs both rounds of threshold signing with s.Participants and
+// returns the aggregated ed25519 signature of msg.
+func (s Signer) TrySign(msg []byte) ([]byte, error) {
+	if len(s.Participants) < s.Threshold {
+		return nil, fmt.Errorf("insufficient participants: %v of %v",
+			len(s.Participants), s.Threshold)
+	}
+	participants := append([]Participant(nil), s.Participants...)
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].Identifier() < participants[j].Identifier()
+	})
+
+	commitments := make([]Commitment, len(participants))
+	for i, p := range participants {
+		c, err := p.Commit()
+		if err != nil {
+			return nil, fmt.Errorf("participant %v: %w", p.Identifier(), err)
+		}
+		if c.Identifier != p.Identifier() {
+			return nil, fmt.Errorf("participant %v: invalid commitment",
+				p.Identifier())
+		}
+		commitments[i] = c
+	}
+
+	shares := make([]SignatureShare, len(participants))
+	for i, p := range participants {
+		share, err := p.Sign(msg, commitments)
+		if err != nil {
+			return nil, fmt.Errorf("participant %v: %w", p.Identifier(), err)
+		}
+		shares[i] = share
+	}
+
+	return Aggregate(s.PublicKeys, msg, commitments, shares)
+}
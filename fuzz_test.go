@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+//go:build go1.18
+// +build go1.18
+
+package factom_test
+
+import (
+	"encoding"
+	"reflect"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+)
+
+// fuzzBinary fuzzes the UnmarshalBinary method of the value returned by
+// newV, which must never panic. Any successfully unmarshaled value must also
+// marshal without error.
+func fuzzBinary(f *testing.F, newV func() encoding.BinaryUnmarshaler,
+	seeds ...[]byte) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := newV()
+		if err := v.UnmarshalBinary(data); err != nil {
+			return
+		}
+		m, ok := v.(encoding.BinaryMarshaler)
+		if !ok {
+			return
+		}
+		if _, err := m.MarshalBinary(); err != nil {
+			t.Fatalf("MarshalBinary after UnmarshalBinary: %v", err)
+		}
+	})
+}
+
+// fuzzText fuzzes the UnmarshalText method of the value returned by newV,
+// which must never panic. Any successfully unmarshaled value must round trip
+// through MarshalText.
+func fuzzText(f *testing.F, newV func() encoding.TextUnmarshaler,
+	seeds ...string) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		v := newV()
+		if err := v.UnmarshalText([]byte(text)); err != nil {
+			return
+		}
+		out, err := v.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText after UnmarshalText: %v", err)
+		}
+		v2 := newV()
+		if err := v2.UnmarshalText(out); err != nil {
+			t.Fatalf("UnmarshalText after MarshalText: %v", err)
+		}
+		if !reflect.DeepEqual(v, v2) {
+			t.Fatalf("round trip: %q != %q", out, text)
+		}
+	})
+}
+
+func FuzzEntryUnmarshalBinary(f *testing.F) {
+	var seeds [][]byte
+	for _, test := range unmarshalBinaryTests {
+		seeds = append(seeds, test.Data)
+	}
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(Entry) },
+		seeds...)
+}
+
+func FuzzEBlockUnmarshalBinary(f *testing.F) {
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(EBlock) })
+}
+
+func FuzzDBlockUnmarshalBinary(f *testing.F) {
+	var seeds [][]byte
+	for _, test := range DBlockTests {
+		seeds = append(seeds, test.Data)
+	}
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(DBlock) },
+		seeds...)
+}
+
+func FuzzFBlockUnmarshalBinary(f *testing.F) {
+	var seeds [][]byte
+	for _, test := range fblockUnmarshalBinaryTests {
+		seeds = append(seeds, test.Data)
+	}
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(FBlock) },
+		seeds...)
+}
+
+func FuzzTransactionUnmarshalBinary(f *testing.F) {
+	var seeds [][]byte
+	for _, test := range txUnmarshalBinaryTests {
+		seeds = append(seeds, test.Data)
+	}
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler {
+		return new(Transaction)
+	}, seeds...)
+}
+
+func FuzzRCDUnmarshalBinary(f *testing.F) {
+	var seeds [][]byte
+	for _, test := range rcdUnmarshalBinaryTests {
+		seeds = append(seeds, test.Data)
+	}
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(RCD) },
+		seeds...)
+}
+
+func FuzzIdentityUnmarshalBinary(f *testing.F) {
+	fuzzBinary(f, func() encoding.BinaryUnmarshaler { return new(Identity) })
+}
+
+func FuzzFAAddressUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(FAAddress) },
+		"FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q")
+}
+
+func FuzzFsAddressUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(FsAddress) },
+		"Fs3E9gV6DXsYzf7Fqx1fVBQPQXV695eP3k5XbmHEZVRLkMdD9qCK")
+}
+
+func FuzzECAddressUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(ECAddress) })
+}
+
+func FuzzEsAddressUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(EsAddress) })
+}
+
+func FuzzID1KeyUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(ID1Key) })
+}
+
+func FuzzSK1KeyUnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(SK1Key) })
+}
+
+func FuzzBytes32UnmarshalText(f *testing.F) {
+	fuzzText(f, func() encoding.TextUnmarshaler { return new(Bytes32) })
+}
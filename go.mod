@@ -3,7 +3,6 @@ module github.com/Factom-Asset-Tokens/factom
 go 1.13
 
 require (
-	github.com/AdamSLevy/go-merkle v0.0.0-20190611101253-ca33344a884d
 	github.com/AdamSLevy/jsonrpc2/v14 v14.0.0
 	github.com/AdamSLevy/retry v0.0.0-20191017184328-cce921f261f4
 	github.com/Factom-Asset-Tokens/base58 v0.0.0-20191118025050-4fa02e92ec20
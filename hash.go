@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+// EntryHash, KeyMR, and TxID are distinct 32 byte hash types so that values
+// of one kind cannot be used in place of another without an explicit
+// conversion. They are encoded as hex strings, exactly like Bytes32.
+
+// EntryHash is the hash of an Entry.
+type EntryHash Bytes32
+
+// KeyMR is the Key Merkle Root of a DBlock, EBlock, or FBlock.
+type KeyMR Bytes32
+
+// TxID is the ID of a Factoid Transaction or an Entry commit.
+type TxID Bytes32
+
+// NewEntryHash returns a EntryHash populated with the data from s32, a hex encoded
+// string.
+func NewEntryHash(s32 string) EntryHash {
+	return EntryHash(NewBytes32(s32))
+}
+
+// Set decodes a hex string with exactly 32 bytes of data into h.
+func (h *EntryHash) Set(hexStr string) error {
+	return (*Bytes32)(h).Set(hexStr)
+}
+
+// UnmarshalText decodes a hex string with exactly 32 bytes of data into h.
+func (h *EntryHash) UnmarshalText(text []byte) error {
+	return (*Bytes32)(h).UnmarshalText(text)
+}
+
+// MarshalText encodes h as a hex string. It never returns an error.
+func (h EntryHash) MarshalText() ([]byte, error) {
+	return Bytes32(h).MarshalText()
+}
+
+// String encodes h as a hex string.
+func (h EntryHash) String() string {
+	return Bytes32(h).String()
+}
+
+// Type returns "EntryHash". Satisfies pflag.Value interface.
+func (h EntryHash) Type() string {
+	return "EntryHash"
+}
+
+// IsZero returns true if h is equal to its zero value.
+func (h EntryHash) IsZero() bool {
+	return h == EntryHash{}
+}
+
+// Bytes32 returns h as a Bytes32.
+func (h EntryHash) Bytes32() Bytes32 {
+	return Bytes32(h)
+}
+
+// NewKeyMR returns a KeyMR populated with the data from s32, a hex encoded
+// string.
+func NewKeyMR(s32 string) KeyMR {
+	return KeyMR(NewBytes32(s32))
+}
+
+// Set decodes a hex string with exactly 32 bytes of data into h.
+func (h *KeyMR) Set(hexStr string) error {
+	return (*Bytes32)(h).Set(hexStr)
+}
+
+// UnmarshalText decodes a hex string with exactly 32 bytes of data into h.
+func (h *KeyMR) UnmarshalText(text []byte) error {
+	return (*Bytes32)(h).UnmarshalText(text)
+}
+
+// MarshalText encodes h as a hex string. It never returns an error.
+func (h KeyMR) MarshalText() ([]byte, error) {
+	return Bytes32(h).MarshalText()
+}
+
+// String encodes h as a hex string.
+func (h KeyMR) String() string {
+	return Bytes32(h).String()
+}
+
+// Type returns "KeyMR". Satisfies pflag.Value interface.
+func (h KeyMR) Type() string {
+	return "KeyMR"
+}
+
+// IsZero returns true if h is equal to its zero value.
+func (h KeyMR) IsZero() bool {
+	return h == KeyMR{}
+}
+
+// Bytes32 returns h as a Bytes32.
+func (h KeyMR) Bytes32() Bytes32 {
+	return Bytes32(h)
+}
+
+// NewTxID returns a TxID populated with the data from s32, a hex encoded
+// string.
+func NewTxID(s32 string) TxID {
+	return TxID(NewBytes32(s32))
+}
+
+// Set decodes a hex string with exactly 32 bytes of data into h.
+func (h *TxID) Set(hexStr string) error {
+	return (*Bytes32)(h).Set(hexStr)
+}
+
+// UnmarshalText decodes a hex string with exactly 32 bytes of data into h.
+func (h *TxID) UnmarshalText(text []byte) error {
+	return (*Bytes32)(h).UnmarshalText(text)
+}
+
+// MarshalText encodes h as a hex string. It never returns an error.
+func (h TxID) MarshalText() ([]byte, error) {
+	return Bytes32(h).MarshalText()
+}
+
+// String encodes h as a hex string.
+func (h TxID) String() string {
+	return Bytes32(h).String()
+}
+
+// Type returns "TxID". Satisfies pflag.Value interface.
+func (h TxID) Type() string {
+	return "TxID"
+}
+
+// IsZero returns true if h is equal to its zero value.
+func (h TxID) IsZero() bool {
+	return h == TxID{}
+}
+
+// Bytes32 returns h as a Bytes32.
+func (h TxID) Bytes32() Bytes32 {
+	return Bytes32(h)
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashTypes(t *testing.T) {
+	const hexStr = "72177d733dcd0492066b79c5f3e417aef7f22909674f7dc351ca13b04742bb91"
+	b32 := NewBytes32(hexStr)
+	for _, test := range []struct {
+		Name string
+		Hash interface {
+			String() string
+			Type() string
+			IsZero() bool
+			Bytes32() Bytes32
+		}
+		New func() interface{}
+	}{{
+		Name: "EntryHash",
+		Hash: NewEntryHash(hexStr),
+		New:  func() interface{} { return new(EntryHash) },
+	}, {
+		Name: "KeyMR",
+		Hash: NewKeyMR(hexStr),
+		New:  func() interface{} { return new(KeyMR) },
+	}, {
+		Name: "TxID",
+		Hash: NewTxID(hexStr),
+		New:  func() interface{} { return new(TxID) },
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			assert.Equal(hexStr, test.Hash.String())
+			assert.Equal(test.Name, test.Hash.Type())
+			assert.False(test.Hash.IsZero())
+			assert.Equal(b32, test.Hash.Bytes32())
+
+			data, err := json.Marshal(test.Hash)
+			require.NoError(err)
+			assert.Equal(`"`+hexStr+`"`, string(data))
+
+			h := test.New()
+			require.NoError(json.Unmarshal(data, h))
+			assert.Equal(test.Hash.Bytes32(), h.(interface {
+				Bytes32() Bytes32
+			}).Bytes32())
+
+			assert.EqualError(json.Unmarshal([]byte(`"00"`), h),
+				"invalid length")
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+
+	"github.com/Factom-Asset-Tokens/factom/merkle"
+)
+
+// hasher computes the hash functions used by the Factom protocol.
+//
+// All hashing of protocol data in this package goes through hashes, including
+// the Merkle trees computed by the merkle package, via merkleHasher, so that
+// tests can substitute an instrumented or alternate implementation, and so
+// that a future change to the protocol's hash functions only requires a new
+// hasher, rather than changes to every marshaler.
+type hasher interface {
+	SHA256(data []byte) [sha256.Size]byte
+	SHA512(data []byte) [sha512.Size]byte
+}
+
+// stdHasher implements hasher using crypto/sha256 and crypto/sha512.
+type stdHasher struct{}
+
+func (stdHasher) SHA256(data []byte) [sha256.Size]byte { return sha256.Sum256(data) }
+func (stdHasher) SHA512(data []byte) [sha512.Size]byte { return sha512.Sum512(data) }
+
+// hashes is the hasher used by this package. It must only be replaced by
+// tests, and never while any other goroutine may be hashing.
+var hashes hasher = stdHasher{}
+
+// sha256Sum returns sha256(data).
+func sha256Sum(data []byte) [sha256.Size]byte {
+	return hashes.SHA256(data)
+}
+
+// merkleHasher computes Merkle trees with sha256Sum.
+var merkleHasher = merkle.Hasher(sha256Sum)
+
+// sha512Sum returns sha512(data).
+func sha512Sum(data []byte) [sha512.Size]byte {
+	return hashes.SHA512(data)
+}
+
+// sha256d returns sha256(sha256(data)).
+func sha256d(data []byte) [sha256.Size]byte {
+	hash := sha256Sum(data)
+	return sha256Sum(hash[:])
+}
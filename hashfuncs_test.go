@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingHasher counts the calls to each hash function of the hasher it
+// wraps.
+type countingHasher struct {
+	hasher
+	sha256, sha512 int
+}
+
+func (h *countingHasher) SHA256(data []byte) [sha256.Size]byte {
+	h.sha256++
+	return h.hasher.SHA256(data)
+}
+
+func (h *countingHasher) SHA512(data []byte) [sha512.Size]byte {
+	h.sha512++
+	return h.hasher.SHA512(data)
+}
+
+// constHasher returns fixed hashes, for test vectors that are independent of
+// the hash functions.
+type constHasher struct{}
+
+func (constHasher) SHA256([]byte) [sha256.Size]byte { return [sha256.Size]byte{1} }
+func (constHasher) SHA512([]byte) [sha512.Size]byte { return [sha512.Size]byte{2} }
+
+// setHasher replaces hashes with h and returns a func that restores it.
+func setHasher(h hasher) (restore func()) {
+	prev := hashes
+	hashes = h
+	return func() { hashes = prev }
+}
+
+func TestHasher(t *testing.T) {
+	nameIDs := []Bytes{Bytes("a"), Bytes("b")}
+	data := []byte("entry")
+	rcd := RCD{byte(RCDType01)}
+	chainID := ComputeChainID(nameIDs)
+	entryHash := ComputeEntryHash(data)
+	rcdHash := rcd.Hash()
+	leaves := [][]byte{make([]byte, 32), make([]byte, 32), make([]byte, 32)}
+
+	t.Run("counting", func(t *testing.T) {
+		h := &countingHasher{hasher: stdHasher{}}
+		defer setHasher(h)()
+		assert.Equal(t, chainID, ComputeChainID(nameIDs))
+		assert.Equal(t, 3, h.sha256)
+		assert.Equal(t, entryHash, ComputeEntryHash(data))
+		assert.Equal(t, 4, h.sha256)
+		assert.Equal(t, 1, h.sha512)
+		assert.Equal(t, rcdHash, rcd.Hash())
+		assert.Equal(t, 6, h.sha256)
+		// Two nodes and the root of a tree of 3 leaves.
+		_, err := ComputeEBlockBodyMR(leaves)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, h.sha256)
+	})
+
+	t.Run("const", func(t *testing.T) {
+		defer setHasher(constHasher{})()
+		assert.Equal(t, Bytes32{1}, ComputeChainID(nameIDs))
+		assert.Equal(t, EntryHash{1}, ComputeEntryHash(data))
+		assert.Equal(t, Bytes32{1}, rcd.Hash())
+
+		bodyMR, err := ComputeDBlockBodyMR(leaves[:2])
+		assert.NoError(t, err)
+		assert.Equal(t, Bytes32{1}, bodyMR)
+		var b EBlockKeyMRBuilder
+		b.AddEntry(EntryHash{3})
+		b.AddMinuteMarker(1)
+		assert.Equal(t, Bytes32{1}, b.BodyMR())
+
+		_, id, err := SearchChainID(context.Background(), "01", nameIDs...)
+		assert.NoError(t, err)
+		assert.Equal(t, Bytes32{1}, id)
+	})
+
+	assert.Equal(t, chainID, ComputeChainID(nameIDs))
+}
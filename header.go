@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// DBlockHeader is the header of a DBlock, without the list of EBlocks. It is
+// much cheaper to decode than a DBlock for tools that only track Heights and
+// links between blocks.
+type DBlockHeader struct {
+	// Computed
+	KeyMR *KeyMR
+
+	// Unmarshaled
+	NetworkID    NetworkID
+	BodyMR       Bytes32
+	PrevKeyMR    KeyMR
+	PrevFullHash Bytes32
+	Timestamp    time.Time
+	Height       uint32
+	EBlockCount  uint32
+}
+
+// Get queries factomd for the Directory Block at h.KeyMR, if not nil, or
+// otherwise at h.Height, and unmarshals only its header.
+func (h *DBlockHeader) Get(ctx context.Context, c *Client) error {
+	data, err := c.getDBlockRawData(ctx, h.Height, &h.KeyMR)
+	if err != nil {
+		return err
+	}
+	if err := h.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return c.Network.checkDBlock(&DBlock{
+		KeyMR: h.KeyMR, NetworkID: h.NetworkID, Height: h.Height})
+}
+
+// UnmarshalBinary unmarshals the header of raw DBlock data and populates
+// h.KeyMR, if nil. If h.KeyMR is populated, it is verified. Since the KeyMR
+// commits to the BodyMR, the body of the DBlock is ignored and may be
+// omitted.
+//
+// See DBlock.UnmarshalBinary for the header format.
+func (h *DBlockHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < DBlockHeaderSize {
+		return fmt.Errorf("invalid length")
+	}
+	if data[0] != 0x00 {
+		return fmt.Errorf("invalid version byte")
+	}
+
+	i := 1
+	i += copy(h.NetworkID[:], data[i:])
+	i += copy(h.BodyMR[:], data[i:])
+	i += copy(h.PrevKeyMR[:], data[i:])
+	i += copy(h.PrevFullHash[:], data[i:])
+
+	h.Timestamp = time.Unix(int64(binary.BigEndian.Uint32(data[i:i+4]))*60, 0)
+	i += 4
+
+	h.Height = binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+
+	h.EBlockCount = binary.BigEndian.Uint32(data[i : i+4])
+	if uint64(h.EBlockCount)*DBlockEBlockSize < DBlockMinBodySize {
+		return fmt.Errorf("insufficient EBlock count")
+	}
+
+	headerHash := ComputeDBlockHeaderHash(data)
+	return verifyHeaderKeyMR(&h.KeyMR, &headerHash, &h.BodyMR)
+}
+
+// EBlockHeader is the header of an EBlock, without the list of Entry Hashes.
+type EBlockHeader struct {
+	ChainID *Bytes32
+	KeyMR   *KeyMR // Computed
+
+	// Unmarshaled
+	BodyMR       Bytes32
+	PrevKeyMR    KeyMR
+	PrevFullHash Bytes32
+	Sequence     uint32
+	Height       uint32
+	ObjectCount  uint32
+}
+
+// Get queries factomd for the Entry Block at h.KeyMR, if not nil, and
+// unmarshals only its header. If h.KeyMR is nil, the chain head of h.ChainID
+// is used, like EBlock.Get.
+func (h *EBlockHeader) Get(ctx context.Context, c *Client) error {
+	if h.KeyMR == nil {
+		eb := EBlock{ChainID: h.ChainID}
+		if _, err := eb.GetChainHead(ctx, c); err != nil {
+			return err
+		}
+		if eb.KeyMR == nil {
+			return fmt.Errorf("missing chain head")
+		}
+		h.KeyMR = eb.KeyMR
+	}
+	// Only the header is verified, so the data is not added to the
+	// c.Store.
+	data, _, err := c.getRawData(ctx, Bytes32(*h.KeyMR))
+	if err != nil {
+		return err
+	}
+	return h.UnmarshalBinary(data)
+}
+
+// UnmarshalBinary unmarshals the header of raw EBlock data and populates
+// h.ChainID and h.KeyMR, if nil. If they are populated, they are verified.
+// Since the KeyMR commits to the BodyMR, the body of the EBlock is ignored and
+// may be omitted.
+//
+// See EBlock.UnmarshalBinary for the header format.
+func (h *EBlockHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < EBlockHeaderSize {
+		return fmt.Errorf("invalid length")
+	}
+
+	var chainID Bytes32
+	i := copy(chainID[:], data)
+	if h.ChainID != nil {
+		if *h.ChainID != chainID {
+			return fmt.Errorf("invalid ChainID")
+		}
+	} else {
+		h.ChainID = &chainID
+	}
+
+	i += copy(h.BodyMR[:], data[i:])
+	i += copy(h.PrevKeyMR[:], data[i:])
+	i += copy(h.PrevFullHash[:], data[i:])
+
+	h.Sequence = binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+
+	h.Height = binary.BigEndian.Uint32(data[i : i+4])
+	i += 4
+
+	h.ObjectCount = binary.BigEndian.Uint32(data[i : i+4])
+	if h.ObjectCount < 2 {
+		return fmt.Errorf("invalid object count")
+	}
+
+	headerHash := ComputeEBlockHeaderHash(data)
+	return verifyHeaderKeyMR(&h.KeyMR, &headerHash, &h.BodyMR)
+}
+
+// IsFirst returns true if h is the first EBlock in its Chain.
+func (h EBlockHeader) IsFirst() bool {
+	return h.PrevKeyMR.IsZero()
+}
+
+// Prev returns an EBlockHeader with its KeyMR initialized to h.PrevKeyMR and
+// ChainID initialized to h.ChainID.
+//
+// If h is the first EBlock in its Chain, then the returned EBlockHeader has a
+// nil KeyMR.
+func (h EBlockHeader) Prev() EBlockHeader {
+	if h.IsFirst() {
+		return EBlockHeader{ChainID: h.ChainID}
+	}
+	prevKeyMR := h.PrevKeyMR
+	return EBlockHeader{ChainID: h.ChainID, KeyMR: &prevKeyMR}
+}
+
+// verifyHeaderKeyMR computes the KeyMR from headerHash and bodyMR and
+// populates *keyMR, if nil, or otherwise verifies it.
+func verifyHeaderKeyMR(keyMR **KeyMR, headerHash, bodyMR *Bytes32) error {
+	computed := ComputeKeyMR(headerHash, bodyMR)
+	if *keyMR != nil {
+		if **keyMR != computed {
+			return fmt.Errorf("invalid KeyMR")
+		}
+		return nil
+	}
+	*keyMR = &computed
+	return nil
+}
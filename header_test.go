@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEBlockHeader(t *testing.T) {
+	chainID := Bytes32{1}
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("a")}},
+		[]Entry{{Content: Bytes("b")}, {Content: Bytes("c")}})
+	ctx := context.Background()
+
+	eb := EBlock{ChainID: &chainID}
+	require.NoError(t, eb.Get(ctx, c))
+
+	h := EBlockHeader{ChainID: &chainID}
+	require.NoError(t, h.Get(ctx, c))
+	assert.Equal(t, *eb.KeyMR, *h.KeyMR)
+	assert.Equal(t, *eb.BodyMR, h.BodyMR)
+	assert.Equal(t, *eb.PrevKeyMR, h.PrevKeyMR)
+	assert.Equal(t, eb.Sequence, h.Sequence)
+	assert.Equal(t, eb.Height, h.Height)
+	assert.Equal(t, eb.ObjectCount, h.ObjectCount)
+	assert.False(t, h.IsFirst())
+
+	prev := h.Prev()
+	require.NoError(t, prev.Get(ctx, c))
+	assert.True(t, prev.IsFirst())
+	assert.Equal(t, uint32(0), prev.Sequence)
+	assert.Nil(t, prev.Prev().KeyMR)
+
+	// The body is not needed to verify the KeyMR.
+	data, err := eb.MarshalBinary()
+	require.NoError(t, err)
+	h = EBlockHeader{KeyMR: eb.KeyMR}
+	require.NoError(t, h.UnmarshalBinary(data[:EBlockHeaderSize]))
+	assert.Equal(t, chainID, *h.ChainID)
+
+	h = EBlockHeader{KeyMR: &KeyMR{1}}
+	assert.EqualError(t, h.UnmarshalBinary(data), "invalid KeyMR")
+	h = EBlockHeader{ChainID: &Bytes32{2}}
+	assert.EqualError(t, h.UnmarshalBinary(data), "invalid ChainID")
+	assert.EqualError(t, h.UnmarshalBinary(data[:EBlockHeaderSize-1]),
+		"invalid length")
+}
+
+func TestDBlockHeader(t *testing.T) {
+	chainID := Bytes32{1}
+	c := newMockChain(t, chainID, []Entry{{Content: Bytes("a")}})
+	ctx := context.Background()
+
+	db := DBlock{Height: 10}
+	require.NoError(t, db.Get(ctx, c))
+
+	h := DBlockHeader{Height: 10}
+	require.NoError(t, h.Get(ctx, c))
+	assert.Equal(t, *db.KeyMR, *h.KeyMR)
+	assert.Equal(t, *db.BodyMR, h.BodyMR)
+	assert.Equal(t, *db.PrevKeyMR, h.PrevKeyMR)
+	assert.Equal(t, db.Timestamp, h.Timestamp)
+	assert.Equal(t, db.Height, h.Height)
+	assert.Equal(t, uint32(len(db.EBlocks)+1), h.EBlockCount)
+
+	data, err := db.MarshalBinary()
+	require.NoError(t, err)
+	h = DBlockHeader{KeyMR: db.KeyMR}
+	require.NoError(t, h.UnmarshalBinary(data[:DBlockHeaderSize]))
+
+	h = DBlockHeader{KeyMR: &KeyMR{1}}
+	assert.EqualError(t, h.UnmarshalBinary(data), "invalid KeyMR")
+
+	h = DBlockHeader{Height: 11}
+	assert.Error(t, h.Get(ctx, c))
+}
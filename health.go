@@ -0,0 +1,217 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CurrentMinute is the progress of the block being built by the leaders, as
+// reported by factomd.
+type CurrentMinute struct {
+	LeaderHeight         uint32 `json:"leaderheight"`
+	DirectoryBlockHeight uint32 `json:"directoryblockheight"`
+	Minute               int    `json:"minute"`
+
+	// The start times are in nanoseconds since the Unix epoch.
+	CurrentBlockStartTime  int64 `json:"currentblockstarttime"`
+	CurrentMinuteStartTime int64 `json:"currentminutestarttime"`
+	CurrentTime            int64 `json:"currenttime"`
+
+	DirectoryBlockInSeconds int  `json:"directoryblockinseconds"`
+	StallDetected           bool `json:"stalldetected"`
+}
+
+// Get uses c to call the "current-minute" RPC method and populates cm with the
+// result.
+func (cm *CurrentMinute) Get(ctx context.Context, c *Client) error {
+	return c.FactomdRequest(ctx, "current-minute", nil, cm)
+}
+
+// HealthPolicy configures the checks made by Client.Health.
+//
+// A nil *HealthPolicy uses the defaults of all fields.
+type HealthPolicy struct {
+	// Reference, if not nil, is another node whose DBlock height the
+	// node is compared against.
+	Reference *Client
+
+	// MaxLag is the number of DBlocks the node may be behind the
+	// Reference, or the wall clock estimate, and the number of Entry
+	// heights it may be behind its own DBlock height. If zero, 1 is used.
+	MaxLag uint32
+
+	// MinVersion, if not nil, is the lowest acceptable factomd version.
+	MinVersion *Version
+
+	// MaxMinuteAge is how long the current minute may have lasted before
+	// the leaders are considered stalled. If zero, 2*MinuteDuration is
+	// used.
+	MaxMinuteAge time.Duration
+}
+
+func (p *HealthPolicy) maxLag() uint32 {
+	if p == nil || p.MaxLag == 0 {
+		return 1
+	}
+	return p.MaxLag
+}
+
+func (p *HealthPolicy) maxMinuteAge() time.Duration {
+	if p == nil || p.MaxMinuteAge == 0 {
+		return 2 * MinuteDuration
+	}
+	return p.MaxMinuteAge
+}
+
+// HealthReport is the result of Client.Health. It is a JSON object suitable
+// for a health check endpoint.
+type HealthReport struct {
+	// Time is when the check started.
+	Time time.Time `json:"time"`
+
+	// Latency is the round trip time of the "properties" request.
+	Latency time.Duration `json:"latency"`
+
+	Version string        `json:"version,omitempty"`
+	Heights Heights       `json:"heights"`
+	Minute  CurrentMinute `json:"minute"`
+
+	// ReferenceHeight is the DBlock height of the HealthPolicy.Reference,
+	// if any.
+	ReferenceHeight *uint32 `json:"referenceheight,omitempty"`
+
+	// Lag is the number of DBlocks the node is behind the greater of the
+	// ReferenceHeight and the height estimated from the wall clock and
+	// the Timestamp of its latest DBlock.
+	Lag uint32 `json:"lag"`
+
+	// Problems describe each failed check. The node is healthy if there
+	// are none.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Healthy returns true if r has no Problems.
+func (r HealthReport) Healthy() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *HealthReport) problem(format string, args ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// Health checks that factomd is reachable, its version, that its DBlock and
+// Entry heights are not lagging behind c.HealthPolicy.Reference or the wall
+// clock, and that the leaders are making progress through the minutes of the
+// current block. Failed checks are reported as Problems, not errors, so that
+// the report is always complete. If factomd is not reachable, no further
+// checks are made.
+func (c *Client) Health(ctx context.Context) HealthReport {
+	p := c.HealthPolicy
+	r := HealthReport{Time: c.clock().Now()}
+
+	var props Properties
+	if err := props.Get(ctx, c); err != nil {
+		r.problem("unreachable: %v", err)
+		return r
+	}
+	r.Latency = c.clock().Now().Sub(r.Time)
+	r.Version = props.FactomdVersion
+	if p != nil && p.MinVersion != nil {
+		v, err := props.Version()
+		if err != nil {
+			r.problem("%v", err)
+		} else if v.Less(*p.MinVersion) {
+			r.problem("version %v is less than %v", v, *p.MinVersion)
+		}
+	}
+
+	maxLag := p.maxLag()
+	if err := r.Heights.Get(ctx, c); err != nil {
+		r.problem("heights: %v", err)
+	} else {
+		h := r.Heights
+		if h.Entry+maxLag < h.DirectoryBlock {
+			r.problem("entry height %v is behind DBlock height %v",
+				h.Entry, h.DirectoryBlock)
+		}
+
+		ts, err := c.dblockTimestamp(ctx, h.DirectoryBlock)
+		if err != nil {
+			r.problem("latest DBlock: %v", err)
+		} else if age := r.Time.Sub(ts) - DBlockDuration; age > 0 {
+			r.Lag = uint32(age / DBlockDuration)
+		}
+
+		if p != nil && p.Reference != nil {
+			var ref Heights
+			if err := ref.Get(ctx, p.Reference); err != nil {
+				r.problem("reference heights: %v", err)
+			} else {
+				r.ReferenceHeight = &ref.DirectoryBlock
+				if ref.DirectoryBlock > h.DirectoryBlock &&
+					ref.DirectoryBlock-h.DirectoryBlock > r.Lag {
+					r.Lag = ref.DirectoryBlock - h.DirectoryBlock
+				}
+			}
+		}
+		if r.Lag > maxLag {
+			r.problem("DBlock height %v is %v blocks behind",
+				h.DirectoryBlock, r.Lag)
+		}
+	}
+
+	if err := r.Minute.Get(ctx, c); err != nil {
+		r.problem("current minute: %v", err)
+	} else {
+		if r.Minute.StallDetected {
+			r.problem("stall detected")
+		}
+		start := time.Unix(0, r.Minute.CurrentMinuteStartTime)
+		if age := r.Time.Sub(start); r.Minute.CurrentMinuteStartTime > 0 &&
+			age > p.maxMinuteAge() {
+			r.problem("minute %v started %v ago",
+				r.Minute.Minute, age.Round(time.Second))
+		}
+	}
+
+	return r
+}
+
+// HealthHandler returns an http.Handler that serves the JSON HealthReport of
+// c.Health with the status 200 OK if it is Healthy, or 503 Service
+// Unavailable otherwise, for use by load balancers.
+func (c *Client) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r := c.Health(req.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !r.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(r)
+	})
+}
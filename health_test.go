@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNode struct {
+	Version   string
+	Heights   Heights
+	Minute    CurrentMinute
+	DBlockAge time.Duration
+}
+
+func (n *mockNode) client(t *testing.T) *Client {
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq jsonrpc2.Request
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		var result interface{}
+		switch jReq.Method {
+		case "properties":
+			result = Properties{FactomdVersion: n.Version}
+		case "heights":
+			result = n.Heights
+		case "current-minute":
+			result = n.Minute
+		case "dblock-by-height":
+			chainID := Bytes32{1}
+			eb := EBlock{ChainID: &chainID, KeyMR: &KeyMR{},
+				Height: n.Heights.DirectoryBlock}
+			result = map[string]Bytes{"rawdata": newMockDBlockAt(t, eb,
+				time.Now().Add(-n.DBlockAge))}
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: result,
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+	return c
+}
+
+func TestHealth(t *testing.T) {
+	healthy := func() *mockNode {
+		return &mockNode{
+			Version: "6.7.0",
+			Heights: Heights{DirectoryBlock: 100, Leader: 101,
+				EntryBlock: 100, Entry: 100},
+			Minute: CurrentMinute{LeaderHeight: 101, Minute: 3,
+				CurrentMinuteStartTime: time.Now().UnixNano()},
+			DBlockAge: 15 * time.Minute,
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("healthy", func(t *testing.T) {
+		r := healthy().client(t).Health(ctx)
+		assert.True(t, r.Healthy(), r.Problems)
+		assert.Equal(t, "6.7.0", r.Version)
+		assert.Equal(t, uint32(0), r.Lag)
+	})
+
+	for _, test := range []struct {
+		Name    string
+		Modify  func(n *mockNode, p *HealthPolicy)
+		Problem string
+	}{{
+		Name: "old version",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			p.MinVersion = &Version{Major: 6, Minor: 8}
+		},
+		Problem: "version 6.7.0 is less than 6.8.0",
+	}, {
+		Name: "entries syncing",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			n.Heights.Entry = 90
+		},
+		Problem: "entry height 90 is behind DBlock height 100",
+	}, {
+		Name: "wall clock lag",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			n.DBlockAge = 35 * time.Minute
+		},
+		Problem: "DBlock height 100 is 2 blocks behind",
+	}, {
+		Name: "reference lag",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			ref := healthy()
+			ref.Heights.DirectoryBlock = 105
+			p.Reference = ref.client(t)
+		},
+		Problem: "DBlock height 100 is 5 blocks behind",
+	}, {
+		Name: "stall",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			n.Minute.StallDetected = true
+		},
+		Problem: "stall detected",
+	}, {
+		Name: "minute not progressing",
+		Modify: func(n *mockNode, p *HealthPolicy) {
+			n.Minute.CurrentMinuteStartTime = time.Now().
+				Add(-5 * time.Minute).UnixNano()
+		},
+		Problem: "minute 3 started 5m0s ago",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			n := healthy()
+			var p HealthPolicy
+			test.Modify(n, &p)
+			c := n.client(t)
+			c.HealthPolicy = &p
+			r := c.Health(ctx)
+			assert.Equal(t, []string{test.Problem}, r.Problems)
+		})
+	}
+
+	t.Run("handler", func(t *testing.T) {
+		n := healthy()
+		n.Minute.StallDetected = true
+		rec := httptest.NewRecorder()
+		n.client(t).HealthHandler().ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		var r HealthReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+		assert.Equal(t, []string{"stall detected"}, r.Problems)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		c := NewClient()
+		c.FactomdServer = "http://localhost:1"
+		r := c.Health(ctx)
+		require.Len(t, r.Problems, 1)
+		assert.Contains(t, r.Problems[0], "unreachable")
+	})
+}
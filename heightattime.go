@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TimestampCache caches the Timestamps of DBlocks by height for
+// Client.HeightAtTime. Since a saved DBlock never changes, entries are never
+// invalidated, and a single TimestampCache may be shared by many Clients on
+// the same network. It is safe for concurrent use.
+//
+// The zero value is ready to use. A nil *TimestampCache caches nothing.
+type TimestampCache struct {
+	mu         sync.RWMutex
+	timestamps map[uint32]time.Time
+}
+
+// Len returns the number of cached Timestamps.
+func (tc *TimestampCache) Len() int {
+	if tc == nil {
+		return 0
+	}
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return len(tc.timestamps)
+}
+
+func (tc *TimestampCache) get(height uint32) (time.Time, bool) {
+	if tc == nil {
+		return time.Time{}, false
+	}
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	ts, ok := tc.timestamps[height]
+	return ts, ok
+}
+
+func (tc *TimestampCache) add(height uint32, ts time.Time) {
+	if tc == nil {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.timestamps == nil {
+		tc.timestamps = make(map[uint32]time.Time)
+	}
+	tc.timestamps[height] = ts
+}
+
+// dblockTimestamp returns the Timestamp of the DBlock at height, using the
+// c.TimestampCache if possible.
+func (c *Client) dblockTimestamp(ctx context.Context,
+	height uint32) (time.Time, error) {
+	if ts, ok := c.TimestampCache.get(height); ok {
+		return ts, nil
+	}
+	db, err := c.DBlockByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c.TimestampCache.add(height, db.Timestamp)
+	return db.Timestamp, nil
+}
+
+// HeightAtTime returns the height of the DBlock that was active at t, which is
+// the latest DBlock with a Timestamp not after t. The DBlocks are binary
+// searched by Timestamp, so only about log2 of the current DBlock height are
+// fetched. Use Client.TimestampCache to avoid fetching them again.
+//
+// An error is returned if t is before the Timestamp of the first DBlock. If t
+// is after the Timestamp of the latest DBlock, its height is returned.
+func (c *Client) HeightAtTime(ctx context.Context, t time.Time) (uint32, error) {
+	var heights Heights
+	if err := heights.Get(ctx, c); err != nil {
+		return 0, err
+	}
+
+	ts, err := c.dblockTimestamp(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	if t.Before(ts) {
+		return 0, fmt.Errorf("time %v is before the first DBlock", t)
+	}
+
+	// The DBlock at lo is never after t.
+	lo, hi := uint32(0), heights.DirectoryBlock
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		ts, err := c.dblockTimestamp(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+		if ts.After(t) {
+			hi = mid - 1
+		} else {
+			lo = mid
+		}
+	}
+	return lo, nil
+}
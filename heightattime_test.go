@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightAtTime(t *testing.T) {
+	const latest = 100
+	chainID, keyMR := Bytes32{1}, KeyMR{2}
+	dblocks := make(map[uint32]Bytes, latest+1)
+	for h := uint32(0); h <= latest; h++ {
+		dblocks[h] = newMockDBlock(t, EBlock{
+			ChainID: &chainID, KeyMR: &keyMR, Height: h})
+	}
+
+	var requests int
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+			Params struct {
+				Height uint32 `json:"height"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		var res jsonrpc2.Response
+		res.ID = jReq.ID
+		switch jReq.Method {
+		case "heights":
+			res.Result = Heights{DirectoryBlock: latest}
+		case "dblock-by-height":
+			requests++
+			res.Result = map[string]interface{}{
+				"rawdata": dblocks[jReq.Params.Height]}
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(res)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	for _, test := range []struct {
+		Name   string
+		Time   time.Time
+		Height uint32
+		Error  string
+	}{{
+		Name:   "first",
+		Time:   mockDBlockTimestamp(0),
+		Height: 0,
+	}, {
+		Name:   "exact",
+		Time:   mockDBlockTimestamp(37),
+		Height: 37,
+	}, {
+		Name:   "between",
+		Time:   mockDBlockTimestamp(62).Add(5 * time.Minute),
+		Height: 62,
+	}, {
+		Name:   "just before",
+		Time:   mockDBlockTimestamp(63).Add(-time.Second),
+		Height: 62,
+	}, {
+		Name:   "latest",
+		Time:   mockDBlockTimestamp(latest).Add(time.Hour),
+		Height: latest,
+	}, {
+		Name: "before first",
+		Time: mockDBlockTimestamp(0).Add(-time.Second),
+		Error: "time " + mockDBlockTimestamp(0).Add(-time.Second).String() +
+			" is before the first DBlock",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			height, err := c.HeightAtTime(context.Background(), test.Time)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Height, height)
+		})
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		assert := assert.New(t)
+		c.TimestampCache = new(TimestampCache)
+		ts := mockDBlockTimestamp(37)
+
+		requests = 0
+		height, err := c.HeightAtTime(context.Background(), ts)
+		require.NoError(t, err)
+		assert.Equal(uint32(37), height)
+		assert.LessOrEqual(requests, 8)
+		assert.Equal(requests, c.TimestampCache.Len())
+
+		requests = 0
+		height, err = c.HeightAtTime(context.Background(), ts)
+		require.NoError(t, err)
+		assert.Equal(uint32(37), height)
+		assert.Equal(0, requests)
+	})
+}
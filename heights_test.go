@@ -31,7 +31,7 @@ import (
 func TestHeights(t *testing.T) {
 	var h Heights
 	assert := assert.New(t)
-	c := NewClient()
+	c := IntegrationClient(t)
 	err := h.Get(nil, c)
 	assert.NoError(err)
 	zero := uint32(0)
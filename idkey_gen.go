@@ -27,6 +27,7 @@ package factom
 import (
 	"crypto/ed25519"
 	"crypto/sha256"
+	"database/sql/driver"
 )
 
 // Defines IDKeys ID1Key - ID4Key and corresponding SKKeys SK1Key - SK4Key.
@@ -142,6 +143,23 @@ func (key SK1Key) MarshalText() ([]byte, error) {
 	return key.payload().MarshalTextWithPrefix(key.PrefixBytes())
 }
 
+// Value implements driver.Valuer by returning key.String().
+func (key ID1Key) Value() (driver.Value, error) {
+	return key.payload().ValueWithPrefix(key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *ID1Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *SK1Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
 // NewID1Key attempts to parse keyStr into a new ID1Key.
 func NewID1Key(keyStr string) (key ID1Key, err error) {
 	err = key.Set(keyStr)
@@ -292,6 +310,23 @@ func (key SK2Key) MarshalText() ([]byte, error) {
 	return key.payload().MarshalTextWithPrefix(key.PrefixBytes())
 }
 
+// Value implements driver.Valuer by returning key.String().
+func (key ID2Key) Value() (driver.Value, error) {
+	return key.payload().ValueWithPrefix(key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *ID2Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *SK2Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
 // NewID2Key attempts to parse keyStr into a new ID2Key.
 func NewID2Key(keyStr string) (key ID2Key, err error) {
 	err = key.Set(keyStr)
@@ -442,6 +477,23 @@ func (key SK3Key) MarshalText() ([]byte, error) {
 	return key.payload().MarshalTextWithPrefix(key.PrefixBytes())
 }
 
+// Value implements driver.Valuer by returning key.String().
+func (key ID3Key) Value() (driver.Value, error) {
+	return key.payload().ValueWithPrefix(key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *ID3Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *SK3Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
 // NewID3Key attempts to parse keyStr into a new ID3Key.
 func NewID3Key(keyStr string) (key ID3Key, err error) {
 	err = key.Set(keyStr)
@@ -592,6 +644,23 @@ func (key SK4Key) MarshalText() ([]byte, error) {
 	return key.payload().MarshalTextWithPrefix(key.PrefixBytes())
 }
 
+// Value implements driver.Valuer by returning key.String().
+func (key ID4Key) Value() (driver.Value, error) {
+	return key.payload().ValueWithPrefix(key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *ID4Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
+// Scan implements sql.Scanner for the human readable string of key.
+func (key *SK4Key) Scan(v interface{}) error {
+	return key.payload().ScanWithPrefix(v,
+		key.PrefixString(), key.PrefixBytes())
+}
+
 // NewID4Key attempts to parse keyStr into a new ID4Key.
 func NewID4Key(keyStr string) (key ID4Key, err error) {
 	err = key.Set(keyStr)
@@ -22,10 +22,19 @@
 
 package factom
 
-// Entry commits and Transcations use 6 byte (48 bit) big endian numbers.
+import "time"
 
-func getInt48BE(data []byte) int64 {
-	const size = 6
+// Int48BESize is the size of the 6 byte (48 bit) big endian numbers used by
+// Entry commits and Transactions.
+const Int48BESize = 6
+
+// TimestampSize is the size of an encoded millisecond timestamp.
+const TimestampSize = Int48BESize
+
+// GetInt48BE returns the 6 byte big endian number at the start of data, which
+// must have a length of at least Int48BESize.
+func GetInt48BE(data []byte) int64 {
+	const size = Int48BESize
 	var x int64
 	for i := 0; i < size; i++ {
 		x |= int64(data[i]) << (8 * (size - 1 - i))
@@ -33,9 +42,40 @@ func getInt48BE(data []byte) int64 {
 	return x
 }
 
-func putInt48BE(data []byte, x int64) {
-	const size = 6
+// PutInt48BE puts the 6 byte big endian encoding of x at the start of data,
+// which must have a length of at least Int48BESize. The most significant 16
+// bits of x are ignored.
+func PutInt48BE(data []byte, x int64) {
+	const size = Int48BESize
 	for i := 0; i < size; i++ {
 		data[i] = byte(x >> (8 * (size - 1 - i)))
 	}
 }
+
+// DecodeTimestamp returns the time encoded as a 6 byte big endian number of
+// milliseconds since the Unix epoch at the start of data, which must have a
+// length of at least TimestampSize.
+//
+// This is the encoding used by Entry commit and Transaction timestamp salts.
+func DecodeTimestamp(data []byte) time.Time {
+	return time.Unix(0, GetInt48BE(data)*1e6)
+}
+
+// PutTimestamp puts the encoding of t, truncated to millisecond precision, at
+// the start of data, which must have a length of at least TimestampSize.
+//
+// This is the encoding of Entry commit and Transaction timestamp salts, so the
+// sub-second precision of t is part of any TxID or signed commit data.
+func PutTimestamp(data []byte, t time.Time) {
+	PutInt48BE(data, t.UnixNano()/1e6)
+}
+
+// EncodeTimestamp returns the encoding of t in a new []byte of length
+// TimestampSize.
+//
+// Use PutTimestamp to control the allocation of the slice.
+func EncodeTimestamp(t time.Time) []byte {
+	data := make([]byte, TimestampSize)
+	PutTimestamp(data, t)
+	return data
+}
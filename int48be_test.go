@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInt48BE(t *testing.T) {
+	for _, test := range []struct {
+		X    int64
+		Data []byte
+	}{{
+		X:    0,
+		Data: []byte{0, 0, 0, 0, 0, 0},
+	}, {
+		X:    1,
+		Data: []byte{0, 0, 0, 0, 0, 1},
+	}, {
+		X:    0x0102030405ff,
+		Data: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0xff},
+	}, {
+		X:    1<<48 - 1,
+		Data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}} {
+		data := make([]byte, Int48BESize)
+		PutInt48BE(data, test.X)
+		assert.Equal(t, test.Data, data)
+		assert.Equal(t, test.X, GetInt48BE(test.Data))
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	assert := assert.New(t)
+	ts := time.Unix(1574976420, 123456789)
+	data := EncodeTimestamp(ts)
+	assert.Len(data, TimestampSize)
+	assert.Equal(int64(1574976420123), GetInt48BE(data))
+	assert.Equal(ts.Truncate(time.Millisecond).UnixNano(),
+		DecodeTimestamp(data).UnixNano())
+
+	buf := make([]byte, TimestampSize+1)
+	PutTimestamp(buf[1:], ts)
+	assert.Equal(data, buf[1:])
+}
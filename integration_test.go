@@ -0,0 +1,44 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import "testing"
+
+// Integration holds the LOCAL factomd and factom-walletd started by TestMain
+// with the testenv package. It is zero if Docker is not available.
+var Integration struct {
+	// Client is connected to factomd and factom-walletd.
+	Client *Client
+
+	// Es is saved in factom-walletd and funded with Entry Credits.
+	Es EsAddress
+}
+
+// IntegrationClient returns Integration.Client, or skips t if the integration
+// test environment is not running.
+func IntegrationClient(t *testing.T) *Client {
+	if Integration.Client == nil {
+		t.Skip("requires Docker for the testenv integration environment")
+	}
+	return Integration.Client
+}
@@ -0,0 +1,312 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package ipfs stores payloads too large for an Entry in IPFS, and anchors
+// them on the Factom blockchain with a Reference Entry.
+//
+// A Reference Entry has the ExtIDs
+//
+//	["ipfs", <CID (string)>, <SHA-256 (32 bytes)>]
+//
+// and its Content is the JSON object
+//
+//	{"size":<payload size in bytes>}
+//
+// The SHA-256 hash and size are recorded in addition to the CID so that
+// readers can verify the payload with only standard hash functions, and so
+// that the payload is bound to the Entry even if the CID is later
+// reinterpreted, e.g. under a different chunking. Open verifies both as the
+// payload is read.
+//
+// The IPFS node is accessed through the Node interface, which HTTPNode
+// implements with the HTTP RPC API of Kubo, formerly go-ipfs.
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of all Reference Entries.
+var Tag = factom.Bytes("ipfs")
+
+// Ref is the data stored in a Reference Entry.
+type Ref struct {
+	CID    string
+	SHA256 factom.Bytes32
+	Size   uint64
+}
+
+type refContent struct {
+	Size *uint64 `json:"size"`
+}
+
+// Entry returns the Reference Entry for r in the given chainID. If chainID is
+// nil, the Entry will create a new chain, whose ChainID is computed from the
+// ExtIDs.
+func (r Ref) Entry(chainID *factom.Bytes32) factom.Entry {
+	content, _ := json.Marshal(refContent{Size: &r.Size})
+	return factom.Entry{
+		ChainID: chainID,
+		ExtIDs:  []factom.Bytes{Tag, factom.Bytes(r.CID), r.SHA256[:]},
+		Content: content,
+	}
+}
+
+// ParseRef parses the Ref from the Reference Entry e.
+func ParseRef(e factom.Entry) (Ref, error) {
+	if len(e.ExtIDs) != 3 || !bytes.Equal(e.ExtIDs[0], Tag) {
+		return Ref{}, fmt.Errorf("invalid ExtIDs")
+	}
+	var r Ref
+	if len(e.ExtIDs[1]) == 0 {
+		return Ref{}, fmt.Errorf("missing CID")
+	}
+	r.CID = string(e.ExtIDs[1])
+	if len(e.ExtIDs[2]) != len(r.SHA256) {
+		return Ref{}, fmt.Errorf("invalid SHA-256 length")
+	}
+	copy(r.SHA256[:], e.ExtIDs[2])
+
+	var content refContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return Ref{}, fmt.Errorf("invalid content: %w", err)
+	}
+	if content.Size == nil {
+		return Ref{}, fmt.Errorf("invalid content: missing size")
+	}
+	r.Size = *content.Size
+	return r, nil
+}
+
+// Node adds and retrieves content on an IPFS node.
+type Node interface {
+	// Add adds and pins all data from r and returns its CID.
+	Add(ctx context.Context, r io.Reader) (string, error)
+
+	// Cat returns a ReadCloser of the content with the given cid.
+	Cat(ctx context.Context, cid string) (io.ReadCloser, error)
+}
+
+// Pin streams all data from r to node and returns its Ref.
+func Pin(ctx context.Context, node Node, r io.Reader) (Ref, error) {
+	h := sha256.New()
+	cr := &countReader{r: io.TeeReader(r, h)}
+	cid, err := node.Add(ctx, cr)
+	if err != nil {
+		return Ref{}, fmt.Errorf("ipfs add: %w", err)
+	}
+	var ref Ref
+	ref.CID = cid
+	h.Sum(ref.SHA256[:0])
+	ref.Size = cr.n
+	return ref, nil
+}
+
+type countReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += uint64(n)
+	return n, err
+}
+
+// Store streams all data from r to node with Pin, and then submits its
+// Reference Entry to chainID using ComposeCreate with es. If chainID is nil,
+// the Entry creates a new chain.
+func Store(ctx context.Context, c *factom.Client, es factom.EsAddress,
+	chainID *factom.Bytes32, node Node,
+	r io.Reader) (Ref, factom.EntryHash, factom.TxID, error) {
+	ref, err := Pin(ctx, node, r)
+	if err != nil {
+		return Ref{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	e := ref.Entry(chainID)
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return Ref{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	return ref, *e.Hash, txID, nil
+}
+
+// Open returns a ReadCloser of the payload of ref from node. The payload is
+// verified as it is read. If it is larger than ref.Size, or does not match
+// ref.SHA256 and ref.Size when the end is reached, Read returns an error
+// instead of io.EOF, so a payload that is read to the end is always verified.
+func Open(ctx context.Context, node Node, ref Ref) (io.ReadCloser, error) {
+	rc, err := node.Cat(ctx, ref.CID)
+	if err != nil {
+		return nil, fmt.Errorf("ipfs cat: %w", err)
+	}
+	return &verifyReader{rc: rc, ref: ref, h: sha256.New()}, nil
+}
+
+// Get reads and verifies the payload of the Reference Entry e from node.
+func Get(ctx context.Context, node Node, e factom.Entry) ([]byte, error) {
+	ref, err := ParseRef(e)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := Open(ctx, node, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+type verifyReader struct {
+	rc  io.ReadCloser
+	ref Ref
+	h   hash.Hash
+	n   uint64
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, err := v.rc.Read(p)
+	v.h.Write(p[:n])
+	v.n += uint64(n)
+	if v.n > v.ref.Size {
+		return 0, fmt.Errorf("payload exceeds size %v", v.ref.Size)
+	}
+	if err == io.EOF {
+		if v.n != v.ref.Size {
+			return n, fmt.Errorf("payload size %v does not match size %v",
+				v.n, v.ref.Size)
+		}
+		var sum factom.Bytes32
+		if v.h.Sum(sum[:0]); sum != v.ref.SHA256 {
+			return n, fmt.Errorf("payload does not match SHA-256")
+		}
+	}
+	return n, err
+}
+
+func (v *verifyReader) Close() error {
+	return v.rc.Close()
+}
+
+// HTTPNodeDefault is the default URL of the Kubo HTTP RPC API.
+const HTTPNodeDefault = "http://localhost:5001"
+
+// HTTPNode is a Node that uses the Kubo HTTP RPC API. The zero value uses
+// HTTPNodeDefault and http.DefaultClient.
+type HTTPNode struct {
+	// URL is the base URL of the API, without the /api/v0 path.
+	URL string
+
+	// Client, if not nil, is used for requests.
+	Client *http.Client
+}
+
+func (n HTTPNode) post(ctx context.Context, method string, query url.Values,
+	contentType string, body io.Reader) (*http.Response, error) {
+	base := n.URL
+	if base == "" {
+		base = HTTPNodeDefault
+	}
+	req, err := http.NewRequest(http.MethodPost,
+		base+"/api/v0/"+method+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		var apiErr struct{ Message string }
+		json.NewDecoder(io.LimitReader(res.Body, 4096)).Decode(&apiErr)
+		return nil, fmt.Errorf("%v: %v %v", method, res.Status,
+			apiErr.Message)
+	}
+	return res, nil
+}
+
+// Add adds and pins all data from r using the /api/v0/add method, with CID
+// version 1. The data is streamed, not buffered.
+func (n HTTPNode) Add(ctx context.Context, r io.Reader) (string, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", "payload")
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	res, err := n.post(ctx, "add", url.Values{
+		"pin":         {"true"},
+		"cid-version": {"1"},
+	}, mw.FormDataContentType(), pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return "", err
+	}
+	defer res.Body.Close()
+	var added struct{ Hash string }
+	if err := json.NewDecoder(res.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("add: %w", err)
+	}
+	if added.Hash == "" {
+		return "", fmt.Errorf("add: missing CID")
+	}
+	return added.Hash, nil
+}
+
+// Cat returns the content with the given cid using the /api/v0/cat method.
+func (n HTTPNode) Cat(ctx context.Context, cid string) (io.ReadCloser, error) {
+	res, err := n.post(ctx, "cat", url.Values{"arg": {cid}}, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
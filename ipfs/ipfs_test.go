@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package ipfs_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/ipfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const payload = "The quick brown fox jumps over the lazy dog"
+
+// newNode returns an HTTPNode for a fake Kubo API that stores content by the
+// hex sha256 hash in place of a real CID.
+func newNode(t *testing.T) (HTTPNode, map[string]string, func()) {
+	blocks := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v0/add":
+				assert.Equal(t, "true", r.URL.Query().Get("pin"))
+				f, _, err := r.FormFile("file")
+				require.NoError(t, err)
+				data, err := ioutil.ReadAll(f)
+				require.NoError(t, err)
+				cid := fmt.Sprintf("%x", sha256.Sum256(data))
+				blocks[cid] = string(data)
+				json.NewEncoder(w).Encode(map[string]string{
+					"Name": "payload", "Hash": cid})
+			case "/api/v0/cat":
+				data, ok := blocks[r.URL.Query().Get("arg")]
+				if !ok {
+					w.WriteHeader(http.StatusInternalServerError)
+					json.NewEncoder(w).Encode(map[string]string{
+						"Message": "block not found"})
+					return
+				}
+				w.Write([]byte(data))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	return HTTPNode{URL: srv.URL}, blocks, srv.Close
+}
+
+func TestStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	node, blocks, closeNode := newNode(t)
+	defer closeNode()
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+
+	ref, _, _, err := Store(ctx, c, es, nil, node,
+		strings.NewReader(payload))
+	require.NoError(err)
+	assert.Equal(factom.Bytes32(sha256.Sum256([]byte(payload))), ref.SHA256)
+	assert.Equal(uint64(len(payload)), ref.Size)
+	assert.Equal(payload, blocks[ref.CID])
+	requests := c.DryRun.Requests()
+	require.Len(requests, 2)
+	assert.Equal("commit-chain", requests[0].Method)
+
+	e := ref.Entry(nil)
+	assert.Equal(`{"size":43}`, string(e.Content))
+	parsed, err := ParseRef(e)
+	require.NoError(err)
+	assert.Equal(ref, parsed)
+
+	data, err := Get(ctx, node, e)
+	require.NoError(err)
+	assert.Equal(payload, string(data))
+
+	// Content that does not match the Ref is rejected.
+	blocks[ref.CID] = strings.ToUpper(payload)
+	_, err = Get(ctx, node, e)
+	assert.EqualError(err, "payload does not match SHA-256")
+	blocks[ref.CID] = payload + "!"
+	_, err = Get(ctx, node, e)
+	assert.EqualError(err, "payload exceeds size 43")
+	blocks[ref.CID] = payload[1:]
+	_, err = Get(ctx, node, e)
+	assert.EqualError(err, "payload size 42 does not match size 43")
+
+	delete(blocks, ref.CID)
+	_, err = Get(ctx, node, e)
+	assert.EqualError(err,
+		"ipfs cat: cat: 500 Internal Server Error block not found")
+}
+
+func TestParseRefInvalid(t *testing.T) {
+	valid := Ref{CID: "bafy", Size: 1}.Entry(nil)
+	for _, test := range []struct {
+		Name  string
+		Entry factom.Entry
+		Error string
+	}{{
+		Name:  "tag",
+		Entry: factom.Entry{ExtIDs: []factom.Bytes{{}, valid.ExtIDs[1], valid.ExtIDs[2]}},
+		Error: "invalid ExtIDs",
+	}, {
+		Name:  "CID",
+		Entry: factom.Entry{ExtIDs: []factom.Bytes{Tag, {}, valid.ExtIDs[2]}},
+		Error: "missing CID",
+	}, {
+		Name:  "SHA-256",
+		Entry: factom.Entry{ExtIDs: []factom.Bytes{Tag, valid.ExtIDs[1], {}}},
+		Error: "invalid SHA-256 length",
+	}, {
+		Name:  "size",
+		Entry: factom.Entry{ExtIDs: valid.ExtIDs, Content: factom.Bytes(`{}`)},
+		Error: "invalid content: missing size",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := ParseRef(test.Entry)
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
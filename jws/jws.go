@@ -0,0 +1,312 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package jws signs and verifies compact JSON Web Signatures (RFC 7515), such
+// as JSON Web Tokens (RFC 7519), with the EdDSA algorithm (RFC 8037) using
+// Factom keys, so that Factom identities may be used in standard web
+// authentication flows.
+//
+// The "kid" header of a token is the FA address or the id1, id2, id3, or id4
+// identity key of the signer. Since these are hashes of the RCD of the ed25519
+// public key, the public key is included in the "jwk" header, and Verify
+// checks that it corresponds to the kid before checking the signature.
+//
+// Verify only establishes which key signed a token. VerifyIdentity
+// additionally resolves the active keys of an application identity chain from
+// the blockchain, and requires the kid to be one of them.
+package jws
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+)
+
+// Algorithm is the "alg" header of all tokens.
+const Algorithm = "EdDSA"
+
+// Key is a private key that may sign tokens: a factom.FsAddress, or a
+// factom.SK1Key, SK2Key, SK3Key, or SK4Key.
+type Key interface {
+	PublicKey() ed25519.PublicKey
+	Sign(msg []byte) []byte
+}
+
+// KeyID returns the "kid" of tokens signed by key, which is the FA address of
+// an FsAddress, or the identity key of an SK key.
+func KeyID(key Key) (string, error) {
+	switch key := key.(type) {
+	case factom.FsAddress:
+		return key.FAAddress().String(), nil
+	case factom.SK1Key:
+		return key.ID1Key().String(), nil
+	case factom.SK2Key:
+		return key.ID2Key().String(), nil
+	case factom.SK3Key:
+		return key.ID3Key().String(), nil
+	case factom.SK4Key:
+		return key.ID4Key().String(), nil
+	}
+	return "", fmt.Errorf("unsupported key type: %T", key)
+}
+
+// Header is the JOSE header of a token.
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid"`
+	JWK       JWK    `json:"jwk"`
+}
+
+// JWK is an ed25519 public JSON Web Key (RFC 8037).
+type JWK struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+}
+
+// PublicKey decodes the public key of jwk.
+func (jwk JWK) PublicKey() (ed25519.PublicKey, error) {
+	if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" {
+		return nil, fmt.Errorf("unsupported jwk: %v %v",
+			jwk.KeyType, jwk.Curve)
+	}
+	pub, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("jwk x: %w", err)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("jwk x: invalid public key length")
+	}
+	return pub, nil
+}
+
+// Sign returns a compact JWT with the JSON encoding of claims as the payload,
+// signed by key.
+func Sign(key Key, claims interface{}) (string, error) {
+	kid, err := KeyID(key)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(Header{
+		Algorithm: Algorithm,
+		Type:      "JWT",
+		KeyID:     kid,
+		JWK: JWK{KeyType: "OKP", Curve: "Ed25519",
+			X: base64.RawURLEncoding.EncodeToString(key.PublicKey())},
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := encode(header) + "." + encode(payload)
+	sig := key.Sign([]byte(signingInput))
+	return signingInput + "." + encode(sig), nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Verify checks the signature of token against the public key in its "jwk"
+// header, and that the public key corresponds to its "kid", and then
+// unmarshals the payload into claims, unless claims is nil.
+//
+// Verify does not check the validity period of the claims. See Claims.Valid.
+func Verify(token string, claims interface{}) (Header, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Header{}, fmt.Errorf("invalid compact serialization")
+	}
+	var data [3][]byte
+	for i, part := range parts {
+		var err error
+		if data[i], err = base64.RawURLEncoding.DecodeString(part); err != nil {
+			return Header{}, fmt.Errorf("invalid base64url: %w", err)
+		}
+	}
+
+	var header Header
+	if err := json.Unmarshal(data[0], &header); err != nil {
+		return Header{}, fmt.Errorf("header: %w", err)
+	}
+	if header.Algorithm != Algorithm {
+		return Header{}, fmt.Errorf("unsupported alg: %q", header.Algorithm)
+	}
+	pub, err := header.JWK.PublicKey()
+	if err != nil {
+		return Header{}, err
+	}
+	hash, err := parseKeyID(header.KeyID)
+	if err != nil {
+		return Header{}, err
+	}
+	rcd := append(factom.RCD{byte(factom.RCDType01)}, pub...)
+	if rcd.Hash() != hash {
+		return Header{}, fmt.Errorf("kid does not match jwk")
+	}
+
+	signingInput := token[:len(parts[0])+1+len(parts[1])]
+	if !ed25519.Verify(pub, []byte(signingInput), data[2]) {
+		return Header{}, fmt.Errorf("invalid signature")
+	}
+
+	if claims != nil {
+		if err := json.Unmarshal(data[1], claims); err != nil {
+			return Header{}, fmt.Errorf("payload: %w", err)
+		}
+	}
+	return header, nil
+}
+
+// parseKeyID returns the RCD hash of the FA address or identity key kid.
+func parseKeyID(kid string) (factom.Bytes32, error) {
+	var hash [32]byte
+	var err error
+	switch {
+	case strings.HasPrefix(kid, "FA"):
+		var adr factom.FAAddress
+		err = adr.Set(kid)
+		hash = adr
+	case strings.HasPrefix(kid, "id1"):
+		var key factom.ID1Key
+		err = key.Set(kid)
+		hash = key
+	case strings.HasPrefix(kid, "id2"):
+		var key factom.ID2Key
+		err = key.Set(kid)
+		hash = key
+	case strings.HasPrefix(kid, "id3"):
+		var key factom.ID3Key
+		err = key.Set(kid)
+		hash = key
+	case strings.HasPrefix(kid, "id4"):
+		var key factom.ID4Key
+		err = key.Set(kid)
+		hash = key
+	default:
+		return factom.Bytes32{}, fmt.Errorf("unsupported kid: %q", kid)
+	}
+	if err != nil {
+		return factom.Bytes32{}, fmt.Errorf("kid: %w", err)
+	}
+	return hash, nil
+}
+
+// VerifyIdentity is like Verify, but also requires the "kid" of token to be an
+// active id1 key of the application identity chain with the given chainID,
+// which is resolved with appidentity.Get.
+func VerifyIdentity(ctx context.Context, c *factom.Client,
+	chainID factom.Bytes32, token string, claims interface{}) (Header, error) {
+	i, err := appidentity.Get(ctx, c, chainID)
+	if err != nil {
+		return Header{}, err
+	}
+	return VerifyIdentityKeys(i, token, claims)
+}
+
+// VerifyIdentityKeys is like Verify, but also requires the "kid" of token to
+// be one of the active i.Keys.
+func VerifyIdentityKeys(i appidentity.Identity,
+	token string, claims interface{}) (Header, error) {
+	header, err := Verify(token, claims)
+	if err != nil {
+		return Header{}, err
+	}
+	for _, key := range i.Keys {
+		if key.String() == header.KeyID {
+			return header, nil
+		}
+	}
+	return Header{}, fmt.Errorf("kid is not an active key of identity %v",
+		i.ChainID)
+}
+
+// Claims are the registered claims of a JWT (RFC 7519), which may be embedded
+// in application claims.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Valid returns an error if now is not before c.ExpiresAt or is before
+// c.NotBefore, when set.
+func (c Claims) Valid(now time.Time) error {
+	if c.ExpiresAt != 0 && now.Unix() >= c.ExpiresAt {
+		return fmt.Errorf("token expired")
+	}
+	if c.NotBefore != 0 && now.Unix() < c.NotBefore {
+		return fmt.Errorf("token not yet valid")
+	}
+	return nil
+}
+
+// Audience is the "aud" claim, which is either a single string or an array of
+// strings.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string.
+func (aud Audience) MarshalJSON() ([]byte, error) {
+	if len(aud) == 1 {
+		return json.Marshal(aud[0])
+	}
+	return json.Marshal([]string(aud))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (aud *Audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*aud = Audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(aud))
+}
+
+// Contains returns true if aud contains s.
+func (aud Audience) Contains(s string) bool {
+	for _, a := range aud {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
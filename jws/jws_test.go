@@ -0,0 +1,156 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package jws_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+	. "github.com/Factom-Asset-Tokens/factom/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	Claims
+	Role string `json:"role"`
+}
+
+func TestSignVerify(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	sk1, err := factom.GenerateSK1Key()
+	require.NoError(err)
+
+	claims := testClaims{
+		Claims: Claims{Subject: "alice", Audience: Audience{"api"},
+			ExpiresAt: 1500000600},
+		Role: "admin",
+	}
+	for _, key := range []Key{fs, sk1} {
+		token, err := Sign(key, claims)
+		require.NoError(err)
+
+		var got testClaims
+		header, err := Verify(token, &got)
+		require.NoError(err)
+		assert.Equal(claims, got)
+		assert.Equal(Algorithm, header.Algorithm)
+		kid, err := KeyID(key)
+		require.NoError(err)
+		assert.Equal(kid, header.KeyID)
+
+		// Tampered payloads are rejected.
+		parts := strings.Split(token, ".")
+		other, err := Sign(key, testClaims{Role: "user"})
+		require.NoError(err)
+		_, err = Verify(parts[0]+"."+strings.Split(other, ".")[1]+
+			"."+parts[2], nil)
+		assert.EqualError(err, "invalid signature")
+	}
+	assert.Equal(fs.FAAddress().String(), mustKeyID(t, fs))
+	assert.Equal(sk1.ID1Key().String(), mustKeyID(t, sk1))
+
+	// A jwk that does not match the kid is rejected, even if it signed
+	// the token.
+	token, err := Sign(fs, claims)
+	require.NoError(err)
+	parts := strings.Split(token, ".")
+	var header Header
+	require.NoError(json.Unmarshal(decode(t, parts[0]), &header))
+	header.KeyID = mustKeyID(t, sk1)
+	data, err := json.Marshal(header)
+	require.NoError(err)
+	_, err = Verify(encode(data)+"."+parts[1]+"."+parts[2], nil)
+	assert.EqualError(err, "kid does not match jwk")
+
+	_, err = Verify("a.b", nil)
+	assert.Error(err)
+}
+
+func TestVerifyIdentityKeys(t *testing.T) {
+	require := require.New(t)
+
+	sk1, err := factom.GenerateSK1Key()
+	require.NoError(err)
+	sk2, err := factom.GenerateSK1Key()
+	require.NoError(err)
+	i, _, err := appidentity.New([]factom.ID1Key{sk1.ID1Key()},
+		factom.Bytes("app"))
+	require.NoError(err)
+
+	token, err := Sign(sk1, Claims{Issuer: i.ChainID.String()})
+	require.NoError(err)
+	var claims Claims
+	_, err = VerifyIdentityKeys(i, token, &claims)
+	require.NoError(err)
+	assert.Equal(t, i.ChainID.String(), claims.Issuer)
+
+	token, err = Sign(sk2, Claims{})
+	require.NoError(err)
+	_, err = VerifyIdentityKeys(i, token, nil)
+	assert.Error(t, err)
+}
+
+func TestClaims(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1500000000, 0)
+	assert.NoError(Claims{}.Valid(now))
+	assert.NoError(Claims{ExpiresAt: now.Unix() + 1,
+		NotBefore: now.Unix()}.Valid(now))
+	assert.Error(Claims{ExpiresAt: now.Unix()}.Valid(now))
+	assert.Error(Claims{NotBefore: now.Unix() + 1}.Valid(now))
+
+	var aud Audience
+	assert.NoError(json.Unmarshal([]byte(`"api"`), &aud))
+	assert.Equal(Audience{"api"}, aud)
+	assert.NoError(json.Unmarshal([]byte(`["api","web"]`), &aud))
+	assert.True(aud.Contains("web"))
+	data, err := json.Marshal(Audience{"api"})
+	assert.NoError(err)
+	assert.Equal(`"api"`, string(data))
+}
+
+func mustKeyID(t *testing.T, key Key) string {
+	kid, err := KeyID(key)
+	require.NoError(t, err)
+	return kid
+}
+
+func decode(t *testing.T, s string) []byte {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return data
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
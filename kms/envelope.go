@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// KeyWrapper encrypts and decrypts small secrets with a key encryption key
+// held by a KMS, e.g. with the AWS or Google Cloud KMS Encrypt and Decrypt
+// APIs.
+type KeyWrapper interface {
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, error)
+	Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// KeyWrapperFuncs adapts a pair of functions to a KeyWrapper.
+type KeyWrapperFuncs struct {
+	WrapFunc   func(ctx context.Context, plaintext []byte) ([]byte, error)
+	UnwrapFunc func(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// Wrap calls f.WrapFunc(ctx, plaintext).
+func (f KeyWrapperFuncs) Wrap(ctx context.Context,
+	plaintext []byte) ([]byte, error) {
+	return f.WrapFunc(ctx, plaintext)
+}
+
+// Unwrap calls f.UnwrapFunc(ctx, ciphertext).
+func (f KeyWrapperFuncs) Unwrap(ctx context.Context,
+	ciphertext []byte) ([]byte, error) {
+	return f.UnwrapFunc(ctx, ciphertext)
+}
+
+// EnvelopedKey is an ed25519 seed encrypted by a KeyWrapper. It contains no
+// secrets in the clear and may be stored alongside application configuration.
+type EnvelopedKey struct {
+	PublicKey  factom.Bytes `json:"publickey"`
+	Ciphertext factom.Bytes `json:"ciphertext"`
+}
+
+// GenerateEnvelopedKey generates a new ed25519 key and encrypts it with w.
+func GenerateEnvelopedKey(ctx context.Context,
+	w KeyWrapper) (EnvelopedKey, error) {
+	fs, err := factom.GenerateFsAddress()
+	if err != nil {
+		return EnvelopedKey{}, err
+	}
+	defer zero(fs[:])
+	return NewEnvelopedKey(ctx, w, fs.PrivateKey())
+}
+
+// NewEnvelopedKey encrypts the seed of key with w. The PrivateKey of an
+// existing factom.FsAddress or factom.EsAddress may be imported this way.
+func NewEnvelopedKey(ctx context.Context, w KeyWrapper,
+	key ed25519.PrivateKey) (EnvelopedKey, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return EnvelopedKey{}, fmt.Errorf("invalid private key size: %v",
+			len(key))
+	}
+	ciphertext, err := w.Wrap(ctx, key.Seed())
+	if err != nil {
+		return EnvelopedKey{}, fmt.Errorf("wrap: %w", err)
+	}
+	return EnvelopedKey{
+		PublicKey:  factom.Bytes(key.Public().(ed25519.PublicKey)),
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Backend returns a Backend that decrypts k with w for each signature.
+func (k EnvelopedKey) Backend(w KeyWrapper) Backend {
+	return envelopeBackend{key: k, wrapper: w}
+}
+
+type envelopeBackend struct {
+	key     EnvelopedKey
+	wrapper KeyWrapper
+}
+
+func (b envelopeBackend) PublicKey(context.Context) (ed25519.PublicKey, error) {
+	if len(b.key.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: %v",
+			len(b.key.PublicKey))
+	}
+	return ed25519.PublicKey(b.key.PublicKey), nil
+}
+
+func (b envelopeBackend) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	seed, err := b.wrapper.Unwrap(ctx, b.key.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap: %w", err)
+	}
+	defer zero(seed)
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("unwrap: invalid seed size: %v", len(seed))
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	defer zero(priv)
+	if !bytes.Equal(priv[ed25519.SeedSize:], b.key.PublicKey) {
+		return nil, fmt.Errorf("unwrap: public key mismatch")
+	}
+	return ed25519.Sign(priv, msg), nil
+}
+
+func zero(data []byte) {
+	for i := range data {
+		data[i] = 0
+	}
+}
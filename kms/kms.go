@@ -0,0 +1,218 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package kms signs with ed25519 keys held by a cloud key management service,
+// such as AWS KMS or Google Cloud KMS, for custody requirements that rule out
+// holding private keys on application hosts.
+//
+// The cloud SDKs are not dependencies of this package. Instead a Signer uses a
+// Backend, which is adapted from the SDK of choice in a few lines. A Signer
+// implements factom.RCDSigner, for Factoid Transactions, and
+// factom.CommitSigner, for Entry commits, and verifies every signature it
+// returns.
+//
+// Where the KMS supports ed25519, the private key never leaves it. With AWS
+// KMS, create the key with KeySpec ECC_NIST_EDWARDS25519 and KeyUsage
+// SIGN_VERIFY, and adapt the client with:
+//
+//	backend := kms.BackendFuncs{
+//		PublicKeyFunc: func(ctx context.Context) (ed25519.PublicKey, error) {
+//			out, err := client.GetPublicKey(ctx,
+//				&awskms.GetPublicKeyInput{KeyId: &keyID})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return kms.ParsePublicKey(out.PublicKey)
+//		},
+//		SignFunc: func(ctx context.Context, msg []byte) ([]byte, error) {
+//			out, err := client.Sign(ctx, &awskms.SignInput{
+//				KeyId:            &keyID,
+//				Message:          msg,
+//				MessageType:      types.MessageTypeRaw,
+//				SigningAlgorithm: types.SigningAlgorithmSpecEd25519Sha512,
+//			})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return out.Signature, nil
+//		},
+//	}
+//	signer, err := kms.NewSigner(ctx, backend)
+//	signer.MaxMessageSize = kms.AWSMaxMessageSize
+//
+// Factom verifies pure ed25519 signatures, so the pre-hashed
+// ED25519_PH_SHA_512 algorithm must not be used. The IAM policy of the signing
+// role needs only kms:GetPublicKey and kms:Sign on the key.
+//
+// With Google Cloud KMS, create the key with purpose ASYMMETRIC_SIGN and
+// algorithm EC_SIGN_ED25519. Call GetPublicKey and pass the PEM to
+// ParsePublicKey, and call AsymmetricSign with the message in the Data field,
+// rather than the Digest. The signing service account needs only
+// roles/cloudkms.signerVerifier on the key.
+//
+// Where ed25519 is not supported, an EnvelopedKey holds an ed25519 seed
+// encrypted by a key encryption key in the KMS, using a KeyWrapper adapted from
+// the KMS Encrypt and Decrypt calls. The seed is decrypted for each signature
+// and then zeroed, so every use of the key is authorized and logged by the KMS.
+// The signing role needs only kms:Decrypt, or
+// roles/cloudkms.cryptoKeyDecrypter, and kms:Encrypt is only needed to create
+// keys.
+package kms
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Maximum message sizes of the KMS sign APIs. Factoid Transactions larger than
+// AWSMaxMessageSize cannot be signed by AWS KMS.
+const (
+	AWSMaxMessageSize = 4096
+	GCPMaxMessageSize = 64 << 10
+)
+
+// Backend signs with an ed25519 key held by a KMS.
+type Backend interface {
+	// PublicKey returns the ed25519.PublicKey of the key.
+	PublicKey(ctx context.Context) (ed25519.PublicKey, error)
+
+	// Sign returns the pure ed25519 signature of msg.
+	Sign(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// BackendFuncs adapts a pair of functions to a Backend.
+type BackendFuncs struct {
+	PublicKeyFunc func(ctx context.Context) (ed25519.PublicKey, error)
+	SignFunc      func(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// PublicKey calls f.PublicKeyFunc(ctx).
+func (f BackendFuncs) PublicKey(ctx context.Context) (ed25519.PublicKey, error) {
+	return f.PublicKeyFunc(ctx)
+}
+
+// Sign calls f.SignFunc(ctx, msg).
+func (f BackendFuncs) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	return f.SignFunc(ctx, msg)
+}
+
+// ParsePublicKey parses an ed25519 public key in the DER or PEM encoded
+// SubjectPublicKeyInfo form returned by the AWS and Google Cloud KMS
+// GetPublicKey APIs.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected PEM block type: %v",
+				block.Type)
+		}
+		data = block.Bytes
+	}
+	key, err := x509.ParsePKIXPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type: %T", key)
+	}
+	return pub, nil
+}
+
+// Signer signs with a key held by a KMS. It implements factom.RCDSigner, for
+// Factoid Transactions, and factom.CommitSigner, for Entry commits.
+type Signer struct {
+	Backend Backend
+
+	// Key is the ed25519.PublicKey of the Backend. Every signature
+	// returned by the Backend is verified against it.
+	Key ed25519.PublicKey
+
+	// MaxMessageSize, if not zero, is the largest message that the
+	// Backend can sign. Larger messages fail without calling the Backend.
+	MaxMessageSize int
+}
+
+var (
+	_ factom.RCDSigner    = Signer{}
+	_ factom.CommitSigner = Signer{}
+)
+
+// NewSigner returns a Signer for b, after looking up its public key.
+func NewSigner(ctx context.Context, b Backend) (Signer, error) {
+	key, err := b.PublicKey(ctx)
+	if err != nil {
+		return Signer{}, err
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return Signer{}, fmt.Errorf("invalid public key size: %v", len(key))
+	}
+	return Signer{Backend: b, Key: key}, nil
+}
+
+// PublicKey returns s.Key.
+func (s Signer) PublicKey() ed25519.PublicKey {
+	return s.Key
+}
+
+// RCD returns the RCD for s.Key.
+func (s Signer) RCD() factom.RCD {
+	return append(factom.RCD{byte(factom.RCDType01)}, s.Key...)
+}
+
+// FAAddress returns the FAAddress for s.Key.
+func (s Signer) FAAddress() factom.FAAddress {
+	return s.RCD().FAAddress()
+}
+
+// ECAddress returns the ECAddress for s.Key.
+func (s Signer) ECAddress() factom.ECAddress {
+	var ec factom.ECAddress
+	copy(ec[:], s.Key)
+	return ec
+}
+
+// Sign msg using TrySign with context.Background(). If signing fails, nil is
+// returned.
+func (s Signer) Sign(msg []byte) []byte {
+	sig, _ := s.TrySign(context.Background(), msg)
+	return sig
+}
+
+// TrySign signs msg with s.Backend and verifies the signature against s.Key.
+func (s Signer) TrySign(ctx context.Context, msg []byte) ([]byte, error) {
+	if s.MaxMessageSize > 0 && len(msg) > s.MaxMessageSize {
+		return nil, fmt.Errorf("message exceeds %v bytes", s.MaxMessageSize)
+	}
+	sig, err := s.Backend.Sign(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(s.Key, msg, sig) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return sig, nil
+}
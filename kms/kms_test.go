@@ -0,0 +1,180 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package kms_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(err)
+	var calls int
+	backend := BackendFuncs{
+		PublicKeyFunc: func(context.Context) (ed25519.PublicKey, error) {
+			return ParsePublicKey(der)
+		},
+		SignFunc: func(_ context.Context, msg []byte) ([]byte, error) {
+			calls++
+			return ed25519.Sign(priv, msg), nil
+		},
+	}
+	s, err := NewSigner(ctx, backend)
+	require.NoError(err)
+	assert.Equal(pub, s.PublicKey())
+	rcd := append(factom.RCD{byte(factom.RCDType01)}, pub...)
+	assert.Equal(rcd, s.RCD())
+
+	msg := []byte("ledger")
+	assert.True(ed25519.Verify(pub, msg, s.Sign(msg)))
+
+	s.MaxMessageSize = AWSMaxMessageSize
+	_, err = s.TrySign(ctx, make([]byte, AWSMaxMessageSize+1))
+	assert.EqualError(err, "message exceeds 4096 bytes")
+	assert.Equal(1, calls)
+
+	s.Key, _, err = ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	_, err = s.TrySign(ctx, msg)
+	assert.EqualError(err, "invalid signature")
+
+	backend.PublicKeyFunc = func(context.Context) (ed25519.PublicKey, error) {
+		return nil, nil
+	}
+	_, err = NewSigner(ctx, backend)
+	assert.EqualError(err, "invalid public key size: 0")
+}
+
+func TestParsePublicKey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(err)
+
+	key, err := ParsePublicKey(der)
+	require.NoError(err)
+	assert.Equal(pub, key)
+
+	key, err = ParsePublicKey(pem.EncodeToMemory(
+		&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(err)
+	assert.Equal(pub, key)
+
+	_, err = ParsePublicKey(pem.EncodeToMemory(
+		&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.EqualError(err, "unexpected PEM block type: PRIVATE KEY")
+
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	der, err = x509.MarshalPKIXPublicKey(&ec.PublicKey)
+	require.NoError(err)
+	_, err = ParsePublicKey(der)
+	assert.EqualError(err, "unsupported public key type: *ecdsa.PublicKey")
+}
+
+// xorWrapper stands in for a KMS key encryption key.
+func xorWrapper(kek byte, unwraps *int) KeyWrapper {
+	xor := func(data []byte) []byte {
+		out := make([]byte, len(data))
+		for i := range data {
+			out[i] = data[i] ^ kek
+		}
+		return out
+	}
+	return KeyWrapperFuncs{
+		WrapFunc: func(_ context.Context, plaintext []byte) ([]byte, error) {
+			return xor(plaintext), nil
+		},
+		UnwrapFunc: func(_ context.Context, ciphertext []byte) ([]byte, error) {
+			*unwraps++
+			if kek == 0 {
+				return nil, fmt.Errorf("access denied")
+			}
+			return xor(ciphertext), nil
+		},
+	}
+}
+
+func TestEnvelopedKey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	var unwraps int
+	w := xorWrapper(0x5a, &unwraps)
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	k, err := NewEnvelopedKey(ctx, w, fs.PrivateKey())
+	require.NoError(err)
+	assert.Equal(factom.Bytes(fs.PublicKey()), k.PublicKey)
+	assert.False(bytes.Contains(k.Ciphertext, fs[:]))
+
+	s, err := NewSigner(ctx, k.Backend(w))
+	require.NoError(err)
+	assert.Equal(fs.FAAddress(), s.FAAddress())
+	msg := []byte("commit")
+	assert.Equal(fs.Sign(msg), s.Sign(msg))
+	assert.Equal(fs.Sign(msg), s.Sign(msg))
+	assert.Equal(2, unwraps)
+
+	_, err = s.TrySign(ctx, msg)
+	require.NoError(err)
+	s, _ = NewSigner(ctx, k.Backend(xorWrapper(0x01, &unwraps)))
+	_, err = s.TrySign(ctx, msg)
+	assert.EqualError(err, "unwrap: public key mismatch")
+	s, _ = NewSigner(ctx, k.Backend(xorWrapper(0, &unwraps)))
+	_, err = s.TrySign(ctx, msg)
+	assert.EqualError(err, "unwrap: access denied")
+
+	k, err = GenerateEnvelopedKey(ctx, w)
+	require.NoError(err)
+	s, err = NewSigner(ctx, k.Backend(w))
+	require.NoError(err)
+	assert.True(ed25519.Verify(s.Key, msg, s.Sign(msg)))
+
+	_, err = NewEnvelopedKey(ctx, w, nil)
+	assert.EqualError(err, "invalid private key size: 0")
+}
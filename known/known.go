@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package known provides typed values for well-known Factom ChainIDs,
+// addresses, and genesis blocks, so that they need not be hardcoded as strings.
+//
+// Each value is returned by a function, so that it cannot be reassigned by
+// any importing package. The tests verify each value against its hex, and
+// each ChainID against the NameIDs of the first Entry of its chain.
+package known
+
+import "github.com/Factom-Asset-Tokens/factom"
+
+// IdentityRegistrationChainID returns the chain in which server Identities are
+// registered. Its NameIDs are
+// ["Factom Identity Registration Chain", "44079090249"].
+func IdentityRegistrationChainID() factom.Bytes32 {
+	return factom.NewBytes32(
+		"888888001750ede0eff4b05f0c3f557890b256450cabbb84cada937f9c258327")
+}
+
+// BitcoinAnchorChainID returns the chain of the Entries recording the Bitcoin
+// transactions that anchor each DBlock KeyMR. Its NameIDs are
+// ["FactomAnchorChain"].
+func BitcoinAnchorChainID() factom.Bytes32 {
+	return factom.NewBytes32(
+		"df3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604")
+}
+
+// EthereumAnchorChainID returns the chain of the Entries recording the
+// Ethereum transactions that anchor windows of DBlock KeyMRs. Its NameIDs are
+// ["FactomEthereumAnchorChain"].
+func EthereumAnchorChainID() factom.Bytes32 {
+	return factom.NewBytes32(
+		"6e4540d08d5ac6a1a394e982fb6a2ab8b516ee751c37420055141b94fe070bfe")
+}
+
+// GrantsChainID returns the chain in which grant payouts are recorded. Grants
+// have no Entry chain of their own. They are declared by the
+// factom.CoinbaseDescriptor in the ABlock, so this is ABlockChainID. See
+// factom.CoinbaseParams.Payouts.
+func GrantsChainID() factom.Bytes32 {
+	return ABlockChainID()
+}
+
+// ABlockChainID returns the ChainID of the Admin Block chain, which is found
+// in every DBlock.
+func ABlockChainID() factom.Bytes32 { return factom.ABlockChainID() }
+
+// ECBlockChainID returns the ChainID of the Entry Credit Block chain, which is
+// found in every DBlock.
+func ECBlockChainID() factom.Bytes32 { return factom.ECBlockChainID() }
+
+// FBlockChainID returns the ChainID of the Factoid Block chain, which is found
+// in every DBlock.
+func FBlockChainID() factom.Bytes32 { return factom.FBlockChainID() }
+
+// MainnetGenesisKeyMR returns the KeyMR of the mainnet DBlock at height 0.
+//
+// The community testnet has been restarted from new genesis blocks, and LOCAL
+// networks each have their own, so only the mainnet genesis is known.
+func MainnetGenesisKeyMR() factom.KeyMR { return factom.MainnetGenesisKeyMR() }
+
+// FCTBurnAddress returns an FAAddress with an RCD hash for which no RCD is
+// known. Factoshis sent to it can never be spent.
+func FCTBurnAddress() factom.FAAddress {
+	return mustFAAddress(
+		"FA1zT4aFpEvcnPqPCigB3fvGu4Q4mTXY22iiuV69DqE1pNhdF2MC")
+}
+
+// PegNetBurnAddress returns the ECAddress to which FCT is converted to obtain
+// PEG on PegNet. Its private key is unknown, so the Entry Credits purchased
+// can never be spent.
+func PegNetBurnAddress() factom.ECAddress {
+	return mustECAddress(
+		"EC2BURNFCT2PEGNETooo1oooo1oooo1oooo1oooo1oooo19wthin")
+}
+
+func mustFAAddress(adrStr string) factom.FAAddress {
+	adr, err := factom.NewFAAddress(adrStr)
+	if err != nil {
+		panic(err)
+	}
+	return adr
+}
+
+func mustECAddress(adrStr string) factom.ECAddress {
+	adr, err := factom.NewECAddress(adrStr)
+	if err != nil {
+		panic(err)
+	}
+	return adr
+}
@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package known_test
+
+import (
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/known"
+	"github.com/stretchr/testify/assert"
+)
+
+func nameIDs(names ...string) []factom.Bytes {
+	ids := make([]factom.Bytes, len(names))
+	for i, name := range names {
+		ids[i] = factom.Bytes(name)
+	}
+	return ids
+}
+
+func TestChainIDs(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		ChainID factom.Bytes32
+		Hex     string
+		NameIDs []factom.Bytes
+	}{{
+		Name:    "identity registration",
+		ChainID: IdentityRegistrationChainID(),
+		Hex:     "888888001750ede0eff4b05f0c3f557890b256450cabbb84cada937f9c258327",
+		NameIDs: nameIDs("Factom Identity Registration Chain",
+			"44079090249"),
+	}, {
+		Name:    "bitcoin anchor",
+		ChainID: BitcoinAnchorChainID(),
+		Hex:     "df3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604",
+		NameIDs: nameIDs("FactomAnchorChain"),
+	}, {
+		Name:    "ethereum anchor",
+		ChainID: EthereumAnchorChainID(),
+		Hex:     "6e4540d08d5ac6a1a394e982fb6a2ab8b516ee751c37420055141b94fe070bfe",
+		NameIDs: nameIDs("FactomEthereumAnchorChain"),
+	}, {
+		Name:    "admin block",
+		ChainID: ABlockChainID(),
+		Hex:     "000000000000000000000000000000000000000000000000000000000000000a",
+	}, {
+		Name:    "grants",
+		ChainID: GrantsChainID(),
+		Hex:     "000000000000000000000000000000000000000000000000000000000000000a",
+	}, {
+		Name:    "entry credit block",
+		ChainID: ECBlockChainID(),
+		Hex:     "000000000000000000000000000000000000000000000000000000000000000c",
+	}, {
+		Name:    "factoid block",
+		ChainID: FBlockChainID(),
+		Hex:     "000000000000000000000000000000000000000000000000000000000000000f",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Hex, test.ChainID.String())
+			if test.NameIDs != nil {
+				assert.Equal(t, factom.ComputeChainID(test.NameIDs),
+					test.ChainID)
+			}
+		})
+	}
+}
+
+func TestGenesis(t *testing.T) {
+	assert.Equal(t,
+		"17ef7a21d1a616d65e6b73f3c6a7ad5c49340a6c2592872020ec60767ff00d7d",
+		MainnetGenesisKeyMR().String())
+	assert.Equal(t, MainnetGenesisKeyMR(), *factom.Mainnet().GenesisKeyMR)
+}
+
+func TestAddresses(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("FA1zT4aFpEvcnPqPCigB3fvGu4Q4mTXY22iiuV69DqE1pNhdF2MC",
+		FCTBurnAddress().String())
+	assert.Equal(
+		"031cce24bcc43b596af105167de2c03603c20ada3314a7cfb47befcad4883e6f",
+		factom.Bytes32(FCTBurnAddress().RCDHash()).String())
+
+	assert.Equal("EC2BURNFCT2PEGNETooo1oooo1oooo1oooo1oooo1oooo19wthin",
+		PegNetBurnAddress().String())
+	assert.Equal(
+		"37399721298d77984585040ea61055377039a4c3f3e2cd48c46ff643d50fd64f",
+		factom.Bytes32(PegNetBurnAddress()).String())
+}
@@ -0,0 +1,355 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package ledger computes the Factoid and Entry Credit balances of all
+// addresses by replaying FBlocks in order.
+//
+// A Ledger is built up one FBlock at a time with Apply, or synced against
+// factomd with Update. Its state may be saved with Save and restored with
+// Load so that a local copy can be incrementally updated as new blocks are
+// created. The state supports rich lists, supply audits, and, when
+// KeepHistory is set, historical balance queries.
+//
+// FBlocks only record the purchase of Entry Credits. The Entry Credits spent
+// on commits are recorded in ECBlocks, which this package does not replay, so
+// EC balances are the total amount of Entry Credits ever purchased by each
+// ECAddress.
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Ledger is the balance state after applying all FBlocks below Height.
+//
+// A Ledger is not safe for concurrent use.
+type Ledger struct {
+	// Height is the height of the next FBlock to be applied.
+	Height uint32 `json:"height"`
+
+	// KeyMR is the KeyMR of the last applied FBlock, if known. It is used
+	// to verify the PrevKeyMR of the next FBlock.
+	KeyMR *factom.KeyMR `json:"keymr,omitempty"`
+
+	// FCT and EC are the non-zero balances of all addresses.
+	FCT map[factom.FAAddress]uint64 `json:"fct"`
+	EC  map[factom.ECAddress]uint64 `json:"ec"`
+
+	// Minted is the total factoshis created by coinbase transactions.
+	// Fees is the total factoshis burned by transaction fees. Converted
+	// is the total factoshis burned to purchase Entry Credits.
+	Minted    uint64 `json:"minted"`
+	Fees      uint64 `json:"fees"`
+	Converted uint64 `json:"converted"`
+
+	// KeepHistory enables recording every balance change so that
+	// FCTBalanceAt and ECBalanceAt may be used. It must be set before the
+	// first FBlock is applied for the history to be complete.
+	KeepHistory bool                          `json:"keephistory"`
+	FCTHistory  map[factom.FAAddress][]Change `json:"fcthistory,omitempty"`
+	ECHistory   map[factom.ECAddress][]Change `json:"echistory,omitempty"`
+}
+
+// Change is the Balance of an address after the FBlock at Height.
+type Change struct {
+	Height  uint32 `json:"height"`
+	Balance uint64 `json:"balance"`
+}
+
+// FCTBalance is the Amount held by a FAAddress.
+type FCTBalance struct {
+	Address factom.FAAddress
+	Amount  uint64
+}
+
+// ECBalance is the Amount held by an ECAddress.
+type ECBalance struct {
+	Address factom.ECAddress
+	Amount  uint64
+}
+
+// New returns an empty Ledger, which expects the genesis FBlock next.
+func New() *Ledger {
+	var l Ledger
+	l.init()
+	return &l
+}
+
+func (l *Ledger) init() {
+	if l.FCT == nil {
+		l.FCT = make(map[factom.FAAddress]uint64)
+	}
+	if l.EC == nil {
+		l.EC = make(map[factom.ECAddress]uint64)
+	}
+	if l.KeepHistory {
+		if l.FCTHistory == nil {
+			l.FCTHistory = make(map[factom.FAAddress][]Change)
+		}
+		if l.ECHistory == nil {
+			l.ECHistory = make(map[factom.ECAddress][]Change)
+		}
+	}
+}
+
+// Apply updates the balances with all Transactions in fb, which must be the
+// FBlock at l.Height. If fb.PrevKeyMR and l.KeyMR are both known, they must
+// match.
+//
+// Every FCTInput must be covered by the balance of its address, and the
+// inputs of each Transaction must cover its outputs. Transactions without
+// inputs are coinbase transactions, which mint new factoshis and may not
+// purchase Entry Credits. If any check fails, an error is returned and l is
+// not modified.
+func (l *Ledger) Apply(fb factom.FBlock) error {
+	if fb.Height != l.Height {
+		return fmt.Errorf("unexpected FBlock height: %v, expected %v",
+			fb.Height, l.Height)
+	}
+	if l.KeyMR != nil && fb.PrevKeyMR != nil && *l.KeyMR != *fb.PrevKeyMR {
+		return fmt.Errorf("FBlock PrevKeyMR does not match KeyMR")
+	}
+	l.init()
+
+	// Stage all balance changes so that l is only modified if the whole
+	// FBlock is valid.
+	fct := make(map[factom.FAAddress]uint64)
+	ec := make(map[factom.ECAddress]uint64)
+	var minted, fees, converted uint64
+	for i, tx := range fb.Transactions {
+		var totalIn, totalFCTOut, totalECOut uint64
+		for _, input := range tx.FCTInputs {
+			adr := input.FAAddress()
+			bal, ok := fct[adr]
+			if !ok {
+				bal = l.FCT[adr]
+			}
+			if bal < input.Amount {
+				return fmt.Errorf("tx %v: %v: insufficient balance",
+					i, adr)
+			}
+			fct[adr] = bal - input.Amount
+			totalIn += input.Amount
+		}
+		for _, output := range tx.FCTOutputs {
+			adr := output.FAAddress()
+			bal, ok := fct[adr]
+			if !ok {
+				bal = l.FCT[adr]
+			}
+			fct[adr] = bal + output.Amount
+			totalFCTOut += output.Amount
+		}
+		for _, output := range tx.ECOutputs {
+			if fb.ECExchangeRate == 0 {
+				return fmt.Errorf("tx %v: invalid EC exchange rate", i)
+			}
+			adr := output.ECAddress()
+			bal, ok := ec[adr]
+			if !ok {
+				bal = l.EC[adr]
+			}
+			ec[adr] = bal + output.Amount/fb.ECExchangeRate
+			totalECOut += output.Amount
+		}
+
+		if len(tx.FCTInputs) == 0 {
+			if totalECOut > 0 {
+				return fmt.Errorf("tx %v: coinbase with ECOutputs", i)
+			}
+			minted += totalFCTOut
+			continue
+		}
+		totalOut := totalFCTOut + totalECOut
+		if totalIn < totalOut {
+			return fmt.Errorf("tx %v: outputs exceed inputs", i)
+		}
+		fees += totalIn - totalOut
+		converted += totalECOut
+	}
+
+	for adr, bal := range fct {
+		if bal == 0 {
+			delete(l.FCT, adr)
+		} else {
+			l.FCT[adr] = bal
+		}
+		if l.KeepHistory {
+			l.FCTHistory[adr] = append(l.FCTHistory[adr],
+				Change{Height: fb.Height, Balance: bal})
+		}
+	}
+	for adr, bal := range ec {
+		if bal == 0 {
+			delete(l.EC, adr)
+		} else {
+			l.EC[adr] = bal
+		}
+		if l.KeepHistory {
+			l.ECHistory[adr] = append(l.ECHistory[adr],
+				Change{Height: fb.Height, Balance: bal})
+		}
+	}
+	l.Minted += minted
+	l.Fees += fees
+	l.Converted += converted
+	l.KeyMR = fb.KeyMR
+	l.Height++
+	return nil
+}
+
+// Update uses c to load and Apply all FBlocks from l.Height up to and
+// including height. The FBlocks applied before any error remain applied.
+func (l *Ledger) Update(ctx context.Context, c *factom.Client, height uint32) error {
+	for l.Height <= height {
+		fb := factom.FBlock{Height: l.Height}
+		if err := fb.Get(ctx, c); err != nil {
+			return err
+		}
+		if err := l.Apply(fb); err != nil {
+			return err
+		}
+		if l.Height == 0 {
+			// Height overflowed.
+			break
+		}
+	}
+	return nil
+}
+
+// Supply returns the total factoshis in circulation, which is all minted
+// factoshis less those burned by fees and Entry Credit purchases.
+func (l *Ledger) Supply() uint64 {
+	return l.Minted - l.Fees - l.Converted
+}
+
+// Audit returns an error if the sum of all FCT balances does not equal
+// l.Supply().
+func (l *Ledger) Audit() error {
+	var sum uint64
+	for _, bal := range l.FCT {
+		sum += bal
+	}
+	if supply := l.Supply(); sum != supply {
+		return fmt.Errorf("balance sum %v does not match supply %v",
+			sum, supply)
+	}
+	return nil
+}
+
+// RichList returns the n largest FCT balances in descending order. Equal
+// balances are ordered by address. If n <= 0, all balances are returned.
+func (l *Ledger) RichList(n int) []FCTBalance {
+	list := make([]FCTBalance, 0, len(l.FCT))
+	for adr, amount := range l.FCT {
+		list = append(list, FCTBalance{Address: adr, Amount: amount})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Amount != list[j].Amount {
+			return list[i].Amount > list[j].Amount
+		}
+		return bytes.Compare(list[i].Address[:], list[j].Address[:]) < 0
+	})
+	if n > 0 && n < len(list) {
+		list = list[:n]
+	}
+	return list
+}
+
+// ECRichList returns the n largest EC balances in descending order. Equal
+// balances are ordered by address. If n <= 0, all balances are returned.
+func (l *Ledger) ECRichList(n int) []ECBalance {
+	list := make([]ECBalance, 0, len(l.EC))
+	for adr, amount := range l.EC {
+		list = append(list, ECBalance{Address: adr, Amount: amount})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Amount != list[j].Amount {
+			return list[i].Amount > list[j].Amount
+		}
+		return bytes.Compare(list[i].Address[:], list[j].Address[:]) < 0
+	})
+	if n > 0 && n < len(list) {
+		list = list[:n]
+	}
+	return list
+}
+
+// FCTBalanceAt returns the FCT balance of adr after the FBlock at height was
+// applied. KeepHistory must be set.
+func (l *Ledger) FCTBalanceAt(adr factom.FAAddress, height uint32) (uint64, error) {
+	if err := l.checkHistory(height); err != nil {
+		return 0, err
+	}
+	return balanceAt(l.FCTHistory[adr], height), nil
+}
+
+// ECBalanceAt returns the EC balance of adr after the FBlock at height was
+// applied. KeepHistory must be set.
+func (l *Ledger) ECBalanceAt(adr factom.ECAddress, height uint32) (uint64, error) {
+	if err := l.checkHistory(height); err != nil {
+		return 0, err
+	}
+	return balanceAt(l.ECHistory[adr], height), nil
+}
+
+func (l *Ledger) checkHistory(height uint32) error {
+	if !l.KeepHistory {
+		return fmt.Errorf("history not kept")
+	}
+	if height >= l.Height {
+		return fmt.Errorf("height not yet applied: %v", height)
+	}
+	return nil
+}
+
+func balanceAt(changes []Change, height uint32) uint64 {
+	i := sort.Search(len(changes), func(i int) bool {
+		return changes[i].Height > height
+	})
+	if i == 0 {
+		return 0
+	}
+	return changes[i-1].Balance
+}
+
+// Save writes the JSON encoded state of l to w.
+func (l *Ledger) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(l)
+}
+
+// Load reads a Ledger previously written by Save from r.
+func Load(r io.Reader) (*Ledger, error) {
+	var l Ledger
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+	l.init()
+	return &l, nil
+}
@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package ledger_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/ledger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	faA = factom.FAAddress{1}
+	faB = factom.FAAddress{2}
+	faC = factom.FAAddress{3}
+	ecA = factom.ECAddress{4}
+)
+
+func amount(adr [32]byte, amount uint64) factom.AddressAmount {
+	return factom.AddressAmount{Address: adr[:], Amount: amount}
+}
+
+func coinbase(outputs ...factom.AddressAmount) factom.Transaction {
+	return factom.Transaction{FCTOutputs: outputs}
+}
+
+func testFBlocks() []factom.FBlock {
+	keyMR0 := factom.KeyMR{0xa0}
+	keyMR1 := factom.KeyMR{0xa1}
+	return []factom.FBlock{{
+		Height:       0,
+		KeyMR:        &keyMR0,
+		Transactions: []factom.Transaction{coinbase(amount(faA, 1000))},
+	}, {
+		Height:         1,
+		KeyMR:          &keyMR1,
+		PrevKeyMR:      &keyMR0,
+		ECExchangeRate: 10,
+		Transactions: []factom.Transaction{
+			coinbase(amount(faC, 50)),
+			{
+				FCTInputs:  []factom.AddressAmount{amount(faA, 600)},
+				FCTOutputs: []factom.AddressAmount{amount(faB, 400)},
+				ECOutputs:  []factom.AddressAmount{amount(ecA, 150)},
+			},
+		},
+	}, {
+		Height:    2,
+		PrevKeyMR: &keyMR1,
+		Transactions: []factom.Transaction{
+			coinbase(),
+			{
+				FCTInputs: []factom.AddressAmount{
+					amount(faB, 400), amount(faA, 400)},
+				FCTOutputs: []factom.AddressAmount{amount(faC, 790)},
+			},
+		},
+	}}
+}
+
+func newLedger(t *testing.T, keepHistory bool) *Ledger {
+	l := New()
+	l.KeepHistory = keepHistory
+	for _, fb := range testFBlocks() {
+		require.NoError(t, l.Apply(fb))
+	}
+	return l
+}
+
+func TestApply(t *testing.T) {
+	l := newLedger(t, false)
+	assert := assert.New(t)
+	assert.Equal(uint32(3), l.Height)
+	assert.Nil(l.KeyMR)
+	assert.Equal(map[factom.FAAddress]uint64{faC: 840}, l.FCT)
+	assert.Equal(map[factom.ECAddress]uint64{ecA: 15}, l.EC)
+	assert.Equal(uint64(1050), l.Minted)
+	assert.Equal(uint64(60), l.Fees)
+	assert.Equal(uint64(150), l.Converted)
+	assert.Equal(uint64(840), l.Supply())
+	assert.NoError(l.Audit())
+}
+
+func TestApplyInvalid(t *testing.T) {
+	fbs := testFBlocks()
+	for _, test := range []struct {
+		Name  string
+		Edit  func(*factom.FBlock)
+		Error string
+	}{{
+		Name:  "height",
+		Edit:  func(fb *factom.FBlock) { fb.Height = 5 },
+		Error: "unexpected FBlock height: 5, expected 1",
+	}, {
+		Name: "PrevKeyMR",
+		Edit: func(fb *factom.FBlock) {
+			fb.PrevKeyMR = &factom.KeyMR{}
+		},
+		Error: "FBlock PrevKeyMR does not match KeyMR",
+	}, {
+		Name: "insufficient balance",
+		Edit: func(fb *factom.FBlock) {
+			fb.Transactions[1].FCTInputs[0].Amount = 1001
+		},
+		Error: "tx 1: " + faA.String() + ": insufficient balance",
+	}, {
+		Name: "outputs exceed inputs",
+		Edit: func(fb *factom.FBlock) {
+			fb.Transactions[1].FCTOutputs[0].Amount = 451
+		},
+		Error: "tx 1: outputs exceed inputs",
+	}, {
+		Name: "coinbase ECOutputs",
+		Edit: func(fb *factom.FBlock) {
+			fb.Transactions[0].ECOutputs = fb.Transactions[1].ECOutputs
+		},
+		Error: "tx 0: coinbase with ECOutputs",
+	}, {
+		Name:  "EC exchange rate",
+		Edit:  func(fb *factom.FBlock) { fb.ECExchangeRate = 0 },
+		Error: "tx 1: invalid EC exchange rate",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			l := New()
+			require.NoError(t, l.Apply(fbs[0]))
+			saved := *l
+
+			fb := testFBlocks()[1]
+			test.Edit(&fb)
+			assert.EqualError(t, l.Apply(fb), test.Error)
+			assert.Equal(t, saved, *l)
+			assert.Equal(t, map[factom.FAAddress]uint64{faA: 1000}, l.FCT)
+		})
+	}
+}
+
+func TestRichList(t *testing.T) {
+	l := New()
+	require.NoError(t, l.Apply(factom.FBlock{
+		Transactions: []factom.Transaction{coinbase(
+			amount(faB, 10), amount(faC, 30), amount(faA, 10))},
+	}))
+	assert.Equal(t, []FCTBalance{
+		{Address: faC, Amount: 30},
+		{Address: faA, Amount: 10},
+		{Address: faB, Amount: 10},
+	}, l.RichList(0))
+	assert.Equal(t, []FCTBalance{{Address: faC, Amount: 30}}, l.RichList(1))
+	assert.Len(t, l.RichList(10), 3)
+
+	l = newLedger(t, false)
+	assert.Equal(t, []ECBalance{{Address: ecA, Amount: 15}}, l.ECRichList(0))
+}
+
+func TestBalanceAt(t *testing.T) {
+	l := newLedger(t, true)
+	assert := assert.New(t)
+	for _, test := range []struct {
+		Address factom.FAAddress
+		Height  uint32
+		Balance uint64
+	}{
+		{faA, 0, 1000},
+		{faA, 1, 400},
+		{faA, 2, 0},
+		{faB, 0, 0},
+		{faB, 1, 400},
+		{faB, 2, 0},
+		{faC, 0, 0},
+		{faC, 1, 50},
+		{faC, 2, 840},
+	} {
+		bal, err := l.FCTBalanceAt(test.Address, test.Height)
+		assert.NoError(err)
+		assert.Equal(test.Balance, bal, "%v at %v",
+			test.Address, test.Height)
+	}
+
+	bal, err := l.ECBalanceAt(ecA, 2)
+	assert.NoError(err)
+	assert.Equal(uint64(15), bal)
+
+	_, err = l.FCTBalanceAt(faA, 3)
+	assert.EqualError(err, "height not yet applied: 3")
+
+	_, err = newLedger(t, false).FCTBalanceAt(faA, 0)
+	assert.EqualError(err, "history not kept")
+}
+
+func TestSaveLoad(t *testing.T) {
+	l := newLedger(t, true)
+	var buf bytes.Buffer
+	require.NoError(t, l.Save(&buf))
+
+	loaded, err := Load(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, l, loaded)
+
+	// Incremental updates continue from the saved state.
+	require.NoError(t, loaded.Apply(factom.FBlock{
+		Height:       3,
+		Transactions: []factom.Transaction{coinbase(amount(faB, 5))},
+	}))
+	assert.NoError(t, loaded.Audit())
+	bal, err := loaded.FCTBalanceAt(faB, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), bal)
+
+	_, err = Load(bytes.NewBufferString("{"))
+	assert.Error(t, err)
+}
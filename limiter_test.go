@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(2)
+	assert.Equal(t, 2, limiter.Cap())
+
+	var active, max int32
+	newClient := func() *Client {
+		c := newMockClient(t, map[string]interface{}{
+			"entry-credit-rate": map[string]int{"rate": 1000}})
+		transport := c.Factomd.Client.Transport
+		c.Factomd.Client.Transport = RoundTripFunc(
+			func(req *http.Request) *http.Response {
+				n := atomic.AddInt32(&active, 1)
+				defer atomic.AddInt32(&active, -1)
+				for m := atomic.LoadInt32(&max); n > m; m = atomic.LoadInt32(&max) {
+					if atomic.CompareAndSwapInt32(&max, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				res, _ := transport.RoundTrip(req)
+				return res
+			})
+		c.Limiter = limiter
+		return c
+	}
+
+	// The Limiter is shared by multiple Clients.
+	clients := []*Client{newClient(), newClient()}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			_, err := c.GetECRate(ctx)
+			assert.NoError(t, err)
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), max)
+
+	require.NoError(t, limiter.Acquire(ctx))
+	require.NoError(t, limiter.Acquire(ctx))
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err := clients[0].GetECRate(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	limiter.Release()
+	limiter.Release()
+
+	var nilLimiter *Limiter
+	assert.NoError(t, nilLimiter.Acquire(ctx))
+	nilLimiter.Release()
+	assert.Equal(t, 0, nilLimiter.Cap())
+}
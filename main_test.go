@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/testenv"
+)
+
+// TestMain starts factomd and factom-walletd on the LOCAL network with the
+// testenv package, if Docker is available, so that the integration tests do
+// not depend on external nodes. Otherwise those tests are skipped.
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	cfg := testenv.Config{Walletd: true}
+	if !testenv.Available(cfg) {
+		return m.Run()
+	}
+	ctx := context.Background()
+	env, err := testenv.Start(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "testenv:", err)
+		return 1
+	}
+	defer env.Close()
+
+	es, err := GenerateEsAddress()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "testenv:", err)
+		return 1
+	}
+	if err := es.Save(ctx, env.Client); err != nil {
+		fmt.Fprintln(os.Stderr, "testenv: save Es:", err)
+		return 1
+	}
+	if _, err := env.FundEC(ctx, es.ECAddress(), 1000); err != nil {
+		fmt.Fprintln(os.Stderr, "testenv: fund EC:", err)
+		return 1
+	}
+
+	Integration.Client = env.Client
+	Integration.Es = es
+	return m.Run()
+}
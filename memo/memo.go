@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package memo implements a convention for attaching memos to Factoid
+// Transactions, which have no native memo field, using Entries.
+//
+// A Memo Entry references the TxID of a Factoid Transaction and has the memo
+// text as its Content. Its ExtIDs are
+//
+//	["memo", <TxID (32 bytes)>]
+//
+// or, if it is signed,
+//
+//	["memo", <TxID (32 bytes)>, <RCD>, <signature>]
+//
+// where the signature is of the SHA-512 hash of the ChainID, TxID, and Content
+// of the Entry. Anyone may write a Memo Entry for any Transaction, so a memo
+// should only be trusted if it is signed by an input of the Transaction. See
+// Memo.SignedByInput.
+//
+// Memo Entries may be written to any chain. ChainID returns the ChainID of a
+// memo chain for a given namespace, which allows wallets to agree on where to
+// find memos without any further coordination.
+package memo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of all Memo Entries, and of the NameIDs of memo
+// chains.
+var Tag = factom.Bytes("memo")
+
+// NameIDs returns the NameIDs of the memo chain for namespace.
+func NameIDs(namespace string) []factom.Bytes {
+	return []factom.Bytes{Tag, factom.Bytes(namespace)}
+}
+
+// ChainID returns the ChainID of the memo chain for namespace.
+func ChainID(namespace string) factom.Bytes32 {
+	return factom.ComputeChainID(NameIDs(namespace))
+}
+
+// NewChainEntry returns the first Entry of the memo chain for namespace, which
+// creates the chain when it is submitted.
+func NewChainEntry(namespace string) factom.Entry {
+	return factom.Entry{ExtIDs: NameIDs(namespace), Content: factom.Bytes{}}
+}
+
+// Memo is a memo for a Factoid Transaction.
+type Memo struct {
+	TxID factom.TxID
+	Text string
+
+	// RCD is the RCD that signed the Memo Entry, or nil if it is
+	// unsigned.
+	RCD factom.RCD
+}
+
+// Entry returns the Memo Entry for m in chainID. If signer is not nil, the
+// Entry is signed, and m.RCD is ignored.
+func (m Memo) Entry(chainID factom.Bytes32,
+	signer factom.RCDSigner) factom.Entry {
+	e := factom.Entry{
+		ChainID: &chainID,
+		ExtIDs:  []factom.Bytes{Tag, m.TxID[:]},
+		Content: factom.Bytes(m.Text),
+	}
+	if signer != nil {
+		msg := signedMsg(chainID, m.TxID, e.Content)
+		e.ExtIDs = append(e.ExtIDs,
+			factom.Bytes(signer.RCD()), signer.Sign(msg[:]))
+	}
+	return e
+}
+
+func signedMsg(chainID factom.Bytes32, txID factom.TxID,
+	content []byte) [sha512.Size]byte {
+	msg := make([]byte, 0, len(chainID)+len(txID)+len(content))
+	msg = append(msg, chainID[:]...)
+	msg = append(msg, txID[:]...)
+	msg = append(msg, content...)
+	return sha512.Sum512(msg)
+}
+
+// Parse parses the Memo from the Memo Entry e and validates its signature, if
+// it is signed.
+func Parse(e factom.Entry) (Memo, error) {
+	if (len(e.ExtIDs) != 2 && len(e.ExtIDs) != 4) ||
+		!bytes.Equal(e.ExtIDs[0], Tag) {
+		return Memo{}, fmt.Errorf("invalid ExtIDs")
+	}
+	var m Memo
+	if len(e.ExtIDs[1]) != len(m.TxID) {
+		return Memo{}, fmt.Errorf("invalid TxID length")
+	}
+	copy(m.TxID[:], e.ExtIDs[1])
+	m.Text = string(e.Content)
+
+	if len(e.ExtIDs) == 4 {
+		if e.ChainID == nil {
+			return Memo{}, fmt.Errorf("missing ChainID")
+		}
+		rcd := factom.RCD(e.ExtIDs[2])
+		msg := signedMsg(*e.ChainID, m.TxID, e.Content)
+		if err := rcd.Validate(e.ExtIDs[3], msg[:]); err != nil {
+			return Memo{}, fmt.Errorf("invalid signature: %w", err)
+		}
+		m.RCD = rcd
+	}
+	return m, nil
+}
+
+// Signer returns the FAAddress of m.RCD, or nil if m is unsigned.
+func (m Memo) Signer() *factom.FAAddress {
+	if m.RCD == nil {
+		return nil
+	}
+	adr := m.RCD.FAAddress()
+	return &adr
+}
+
+// SignedByInput returns true if m is for tx and is signed by one of the
+// FCTInputs of tx.
+func (m Memo) SignedByInput(tx factom.Transaction) bool {
+	if m.RCD == nil || tx.ID == nil || *tx.ID != m.TxID {
+		return false
+	}
+	signer := m.RCD.Hash()
+	for _, input := range tx.FCTInputs {
+		if input.AddressBytes32() == signer {
+			return true
+		}
+	}
+	return false
+}
+
+// Attach writes a Memo Entry for txID with the given text to chainID using
+// ComposeCreate with es. If signer is not nil, the Memo Entry is signed.
+func Attach(ctx context.Context, c *factom.Client, es factom.EsAddress,
+	chainID factom.Bytes32, txID factom.TxID, text string,
+	signer factom.RCDSigner) (factom.EntryHash, factom.TxID, error) {
+	e := Memo{TxID: txID, Text: text}.Entry(chainID, signer)
+	commitTxID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return factom.EntryHash{}, factom.TxID{}, err
+	}
+	return *e.Hash, commitTxID, nil
+}
+
+// Resolve returns all valid Memos for txID in the chain with chainID, in order.
+// Entries in the chain that are not valid Memo Entries are ignored.
+func Resolve(ctx context.Context, c *factom.Client, chainID factom.Bytes32,
+	txID factom.TxID) ([]Memo, error) {
+	entries, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var memos []Memo
+	for _, e := range entries {
+		if len(e.ExtIDs) < 2 || !bytes.Equal(e.ExtIDs[1], txID[:]) {
+			continue
+		}
+		m, err := Parse(e.Entry)
+		if err != nil {
+			continue
+		}
+		memos = append(memos, m)
+	}
+	return memos, nil
+}
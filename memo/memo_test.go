@@ -0,0 +1,202 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package memo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/memo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemo(t *testing.T) {
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	chainID := ChainID("test")
+	txID := factom.TxID{1, 2, 3}
+	m := Memo{TxID: txID, Text: "rent for March"}
+
+	e := m.Entry(chainID, nil)
+	parsed, err := Parse(e)
+	require.NoError(t, err)
+	assert.Equal(t, m, parsed)
+	assert.Nil(t, parsed.Signer())
+
+	e = m.Entry(chainID, fs)
+	parsed, err = Parse(e)
+	require.NoError(t, err)
+	assert.Equal(t, fs.RCD(), parsed.RCD)
+	assert.Equal(t, fs.FAAddress(), *parsed.Signer())
+
+	fa := fs.FAAddress()
+	tx := factom.Transaction{ID: &txID, FCTInputs: []factom.AddressAmount{{
+		Address: fa[:], Amount: 5}}}
+	assert.True(t, parsed.SignedByInput(tx))
+	other := factom.TxID{4}
+	assert.False(t, parsed.SignedByInput(factom.Transaction{
+		ID: &other, FCTInputs: tx.FCTInputs}))
+	assert.False(t, parsed.SignedByInput(factom.Transaction{ID: &txID}))
+	unsigned, err := Parse(m.Entry(chainID, nil))
+	require.NoError(t, err)
+	assert.False(t, unsigned.SignedByInput(tx))
+
+	// A signature does not verify in a different chain.
+	otherChainID := ChainID("other")
+	e.ChainID = &otherChainID
+	_, err = Parse(e)
+	assert.EqualError(t, err, "invalid signature: invalid signature")
+
+	for _, e := range []factom.Entry{
+		{ExtIDs: []factom.Bytes{Tag}},
+		{ExtIDs: []factom.Bytes{factom.Bytes("other"), txID[:]}},
+		{ExtIDs: []factom.Bytes{Tag, txID[:1]}},
+	} {
+		_, err := Parse(e)
+		assert.Error(t, err)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	es, err := factom.GenerateEsAddress()
+	require.NoError(t, err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+
+	chainID := ChainID("test")
+	txID := factom.TxID{1}
+	entryHash, _, err := Attach(context.Background(), c, es,
+		chainID, txID, "memo", nil)
+	require.NoError(t, err)
+
+	requests := c.DryRun.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "commit-entry", requests[0].Method)
+
+	e := Memo{TxID: txID, Text: "memo"}.Entry(chainID, nil)
+	data, err := e.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, factom.ComputeEntryHash(data), entryHash)
+}
+
+func TestResolve(t *testing.T) {
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	const namespace = "test"
+	chainID := ChainID(namespace)
+	txID := factom.TxID{1}
+	entries := []factom.Entry{
+		NewChainEntry(namespace),
+		Memo{TxID: txID, Text: "first"}.Entry(chainID, nil),
+		Memo{TxID: factom.TxID{2}, Text: "other"}.Entry(chainID, nil),
+		{ChainID: &chainID, ExtIDs: []factom.Bytes{Tag, txID[:],
+			factom.Bytes(fs.RCD()), make(factom.Bytes, 64)}},
+		Memo{TxID: txID, Text: "second"}.Entry(chainID, fs),
+	}
+	c := newMockChain(t, chainID, entries)
+
+	memos, err := Resolve(context.Background(), c, chainID, txID)
+	require.NoError(t, err)
+	require.Len(t, memos, 2)
+	assert.Equal(t, "first", memos[0].Text)
+	assert.Nil(t, memos[0].RCD)
+	assert.Equal(t, "second", memos[1].Text)
+	assert.Equal(t, fs.FAAddress(), *memos[1].Signer())
+}
+
+// newMockChain returns a Client that serves a chain with a single EBlock
+// containing entries.
+func newMockChain(t *testing.T, chainID factom.Bytes32,
+	entries []factom.Entry) *factom.Client {
+	rawData := make(map[string]factom.Bytes)
+	var objects [][]byte
+	for _, e := range entries {
+		e.ChainID = &chainID
+		data, err := e.MarshalBinary()
+		require.NoError(t, err)
+		hash := factom.ComputeEntryHash(data)
+		rawData[hash.String()] = data
+		objects = append(objects, hash[:])
+	}
+	objects = append(objects, (&factom.Bytes32{31: 1})[:])
+	bodyMR, err := factom.ComputeEBlockBodyMR(objects)
+	require.NoError(t, err)
+	data := make([]byte, factom.EBlockHeaderSize)
+	i := copy(data, chainID[:])
+	copy(data[i:], bodyMR[:])
+	binary.BigEndian.PutUint32(data[factom.EBlockHeaderSize-4:],
+		uint32(len(objects)))
+	for _, obj := range objects {
+		data = append(data, obj...)
+	}
+	var eb factom.EBlock
+	require.NoError(t, eb.UnmarshalBinary(data))
+	rawData[eb.KeyMR.String()] = data
+
+	c := factom.NewClient()
+	c.Factomd.Client.Transport = roundTripFunc(
+		func(req *http.Request) *http.Response {
+			var jReq struct {
+				Method string      `json:"method"`
+				ID     interface{} `json:"id"`
+				Params struct {
+					Hash string `json:"hash"`
+				} `json:"params"`
+			}
+			reqData, _ := ioutil.ReadAll(req.Body)
+			_ = json.Unmarshal(reqData, &jReq)
+			res := jsonrpc2.Response{ID: jReq.ID}
+			switch jReq.Method {
+			case "chain-head":
+				res.Result = map[string]interface{}{
+					"chainhead": eb.KeyMR.String()}
+			case "raw-data":
+				res.Result = map[string]interface{}{
+					"data": rawData[jReq.Params.Hash]}
+			default:
+				t.Errorf("unexpected request: %v", jReq.Method)
+			}
+			respData, _ := json.Marshal(res)
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewBuffer(respData)),
+				Header: make(http.Header),
+			}
+		})
+	return c
+}
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
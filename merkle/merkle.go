@@ -0,0 +1,247 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package merkle implements the binary Merkle trees used by Factom for the
+// body Merkle roots of DBlocks, EBlocks, and FBlocks.
+//
+// Factom's trees differ from a textbook Merkle tree in one respect: when a
+// level has an odd number of nodes, the last node is paired with itself,
+// i.e. duplicated, rather than being promoted to the next level unchanged. A
+// tree with a single leaf has that leaf as its root, and an empty tree has the
+// zero hash as its root. All nodes are sha256(left|right).
+//
+// Leaves are always 32 byte hashes. Use HashLeaf for trees, such as the DBlock
+// body, whose leaves are the hash of each element.
+//
+// The package level functions use crypto/sha256. The methods of Hasher, and
+// Builder.Hasher, allow another implementation of the hash function to be
+// used instead.
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Hasher is the hash function used for the nodes of a tree, which must be a
+// drop in replacement for sha256. The nil Hasher uses crypto/sha256.
+type Hasher func(data []byte) [32]byte
+
+func (h Hasher) sum(data []byte) [32]byte {
+	if h == nil {
+		return sha256.Sum256(data)
+	}
+	return h(data)
+}
+
+// HashLeaf returns sha256(data), for use as a leaf of a tree whose elements
+// are hashed.
+func HashLeaf(data []byte) [32]byte {
+	return Hasher(nil).HashLeaf(data)
+}
+
+// HashLeaf returns h(data), for use as a leaf of a tree whose elements are
+// hashed.
+func (h Hasher) HashLeaf(data []byte) [32]byte {
+	return h.sum(data)
+}
+
+// HashNodes returns sha256(left|right).
+func HashNodes(left, right *[32]byte) [32]byte {
+	return Hasher(nil).HashNodes(left, right)
+}
+
+// HashNodes returns h(left|right).
+func (h Hasher) HashNodes(left, right *[32]byte) [32]byte {
+	var data [64]byte
+	i := copy(data[:], left[:])
+	copy(data[i:], right[:])
+	return h.sum(data[:])
+}
+
+// nextLevel returns the parents of level, duplicating the last node if the
+// level has an odd length.
+func (h Hasher) nextLevel(level [][32]byte) [][32]byte {
+	next := make([][32]byte, (len(level)+1)/2)
+	for i := range next {
+		left := &level[2*i]
+		right := left
+		if 2*i+1 < len(level) {
+			right = &level[2*i+1]
+		}
+		next[i] = h.HashNodes(left, right)
+	}
+	return next
+}
+
+// BuildRoot returns the Merkle root of leaves.
+func BuildRoot(leaves [][32]byte) [32]byte {
+	return Hasher(nil).BuildRoot(leaves)
+}
+
+// BuildRoot returns the Merkle root of leaves using h.
+func (h Hasher) BuildRoot(leaves [][32]byte) [32]byte {
+	if len(leaves) == 0 {
+		return [32]byte{}
+	}
+	level := leaves
+	for len(level) > 1 {
+		level = h.nextLevel(level)
+	}
+	return level[0]
+}
+
+// Builder computes a Merkle root incrementally as leaves are added, holding
+// only one hash per level of the tree, so that the leaves need not all be held
+// in memory. Its Root is always equal to the BuildRoot of the leaves added so
+// far.
+//
+// The zero value is an empty tree.
+type Builder struct {
+	// Hasher is the hash function for the nodes of the tree. If nil,
+	// crypto/sha256 is used. It must not be changed once leaves are added.
+	Hasher Hasher
+
+	// stack holds the roots of the complete subtrees of the leaves added
+	// so far, in strictly decreasing order of height.
+	stack []subtree
+	n     int
+}
+
+type subtree struct {
+	hash   [32]byte
+	height int
+}
+
+// Add adds leaf to the tree.
+func (b *Builder) Add(leaf [32]byte) {
+	node := subtree{hash: leaf}
+	for len(b.stack) > 0 && b.stack[len(b.stack)-1].height == node.height {
+		left := &b.stack[len(b.stack)-1]
+		node.hash = b.Hasher.HashNodes(&left.hash, &node.hash)
+		node.height++
+		b.stack = b.stack[:len(b.stack)-1]
+	}
+	b.stack = append(b.stack, node)
+	b.n++
+}
+
+// Len returns the number of leaves added.
+func (b *Builder) Len() int {
+	return b.n
+}
+
+// Root returns the Merkle root of the leaves added so far. More leaves may be
+// added afterwards.
+func (b *Builder) Root() [32]byte {
+	if len(b.stack) == 0 {
+		return [32]byte{}
+	}
+	// Combine the subtrees from the smallest, pairing the running hash
+	// with itself at each level where it is the odd last node.
+	node := b.stack[len(b.stack)-1]
+	for i := len(b.stack) - 2; i >= 0; i-- {
+		left := &b.stack[i]
+		for node.height < left.height {
+			node.hash = b.Hasher.HashNodes(&node.hash, &node.hash)
+			node.height++
+		}
+		node.hash = b.Hasher.HashNodes(&left.hash, &node.hash)
+		node.height++
+	}
+	return node.hash
+}
+
+// Node is a single step of a Merkle branch. Hash is the sibling of the running
+// hash, and Left is true if the sibling is on the left.
+type Node struct {
+	Hash [32]byte
+	Left bool
+}
+
+// BuildBranch returns the Merkle branch from leaves[index] up to the root of
+// leaves. The branch for a tree with a single leaf is empty.
+func BuildBranch(leaves [][32]byte, index int) ([]Node, error) {
+	return Hasher(nil).BuildBranch(leaves, index)
+}
+
+// BuildBranch returns the Merkle branch from leaves[index] up to the root of
+// leaves using h.
+func (h Hasher) BuildBranch(leaves [][32]byte, index int) ([]Node, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("invalid index: %v, leaves: %v",
+			index, len(leaves))
+	}
+	var branch []Node
+	level := leaves
+	for len(level) > 1 {
+		var node Node
+		if index%2 == 0 {
+			sibling := index + 1
+			if sibling == len(level) {
+				// Odd nodes are paired with themselves.
+				sibling = index
+			}
+			node.Hash = level[sibling]
+		} else {
+			node.Hash = level[index-1]
+			node.Left = true
+		}
+		branch = append(branch, node)
+		level = h.nextLevel(level)
+		index /= 2
+	}
+	return branch, nil
+}
+
+// ComputeBranchRoot returns the root obtained by hashing leaf up through
+// branch.
+func ComputeBranchRoot(leaf [32]byte, branch []Node) [32]byte {
+	return Hasher(nil).ComputeBranchRoot(leaf, branch)
+}
+
+// ComputeBranchRoot returns the root obtained by hashing leaf up through
+// branch using h.
+func (h Hasher) ComputeBranchRoot(leaf [32]byte, branch []Node) [32]byte {
+	hash := leaf
+	for i := range branch {
+		node := &branch[i]
+		if node.Left {
+			hash = h.HashNodes(&node.Hash, &hash)
+		} else {
+			hash = h.HashNodes(&hash, &node.Hash)
+		}
+	}
+	return hash
+}
+
+// VerifyBranch returns true if branch proves that leaf is included in the tree
+// with the given root.
+func VerifyBranch(leaf [32]byte, branch []Node, root [32]byte) bool {
+	return Hasher(nil).VerifyBranch(leaf, branch, root)
+}
+
+// VerifyBranch returns true if branch proves, using h, that leaf is included
+// in the tree with the given root.
+func (h Hasher) VerifyBranch(leaf [32]byte, branch []Node, root [32]byte) bool {
+	return h.ComputeBranchRoot(leaf, branch) == root
+}
@@ -0,0 +1,144 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom/merkle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func leaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		leaves[i] = sha256.Sum256([]byte{byte(i)})
+	}
+	return leaves
+}
+
+func TestBuildRoot(t *testing.T) {
+	l := leaves(3)
+	h01 := HashNodes(&l[0], &l[1])
+	h22 := HashNodes(&l[2], &l[2])
+	for _, test := range []struct {
+		Name   string
+		Leaves [][32]byte
+		Root   [32]byte
+	}{{
+		Name: "empty",
+	}, {
+		Name:   "single",
+		Leaves: l[:1],
+		Root:   l[0],
+	}, {
+		Name:   "even",
+		Leaves: l[:2],
+		Root:   h01,
+	}, {
+		Name:   "odd",
+		Leaves: l,
+		Root:   HashNodes(&h01, &h22),
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Root, BuildRoot(test.Leaves))
+		})
+	}
+}
+
+func TestHashNodes(t *testing.T) {
+	l := leaves(2)
+	data := append(l[0][:], l[1][:]...)
+	assert.Equal(t, sha256.Sum256(data), HashNodes(&l[0], &l[1]))
+}
+
+func TestBranch(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		l := leaves(n)
+		root := BuildRoot(l)
+		for i := range l {
+			t.Run(fmt.Sprintf("%v/%v", i, n), func(t *testing.T) {
+				assert := assert.New(t)
+				branch, err := BuildBranch(l, i)
+				require.NoError(t, err)
+				assert.True(VerifyBranch(l[i], branch, root))
+
+				other := l[(i+1)%n]
+				if n > 1 {
+					assert.False(VerifyBranch(other, branch, root))
+				}
+				for j := range branch {
+					branch[j].Hash[0]++
+					assert.False(VerifyBranch(l[i], branch, root))
+					branch[j].Hash[0]--
+				}
+			})
+		}
+	}
+}
+
+func TestBuildBranchInvalidIndex(t *testing.T) {
+	for _, index := range []int{-1, 3} {
+		_, err := BuildBranch(leaves(3), index)
+		assert.EqualError(t, err,
+			fmt.Sprintf("invalid index: %v, leaves: 3", index))
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	l := leaves(70)
+	var b Builder
+	assert.Equal(t, [32]byte{}, b.Root())
+	for i := range l {
+		b.Add(l[i])
+		require.Equal(t, i+1, b.Len())
+		require.Equal(t, BuildRoot(l[:i+1]), b.Root(), "leaves: %v", i+1)
+	}
+}
+
+func TestHasher(t *testing.T) {
+	assert := assert.New(t)
+	var calls int
+	h := Hasher(func(data []byte) [32]byte {
+		calls++
+		return sha256.Sum256(append([]byte{0}, data...))
+	})
+	l := leaves(5)
+	root := h.BuildRoot(l)
+	assert.NotEqual(BuildRoot(l), root)
+	assert.Equal(6, calls)
+
+	b := Builder{Hasher: h}
+	for i := range l {
+		b.Add(l[i])
+	}
+	assert.Equal(root, b.Root())
+
+	branch, err := h.BuildBranch(l, 4)
+	require.NoError(t, err)
+	assert.True(h.VerifyBranch(l[4], branch, root))
+	assert.False(VerifyBranch(l[4], branch, root))
+}
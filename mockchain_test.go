@@ -0,0 +1,187 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockChain returns a Client that serves a chain with the given chainID
+// whose EBlocks contain the given Entries, in order from the first EBlock.
+// Each EBlock has a single minute marker, and is in a DBlock at height
+// 10+seq, which is also served, with the Timestamp mockDBlockTimestamp.
+func newMockChain(t *testing.T, chainID Bytes32, eblocks ...[]Entry) *Client {
+	require := require.New(t)
+	rawData := make(map[string]Bytes)
+	dblocks := make(map[uint32]Bytes)
+	var prevKeyMR KeyMR
+	for seq, entries := range eblocks {
+		var objects [][]byte
+		for _, e := range entries {
+			e.ChainID = &chainID
+			if e.ExtIDs == nil {
+				e.ExtIDs = []Bytes{}
+			}
+			if e.Content == nil {
+				e.Content = Bytes{}
+			}
+			data, err := e.MarshalBinary()
+			require.NoError(err)
+			hash := ComputeEntryHash(data)
+			rawData[hash.String()] = data
+			objects = append(objects, hash[:])
+		}
+		var minute Bytes32
+		minute[len(minute)-1] = 1
+		objects = append(objects, minute[:])
+
+		bodyMR, err := ComputeEBlockBodyMR(objects)
+		require.NoError(err)
+		data := make([]byte, EBlockHeaderSize, EBlockHeaderSize+
+			len(objects)*len(Bytes32{}))
+		i := copy(data, chainID[:])
+		i += copy(data[i:], bodyMR[:])
+		i += copy(data[i:], prevKeyMR[:])
+		i += len(Bytes32{}) // PrevFullHash
+		binary.BigEndian.PutUint32(data[i:], uint32(seq))
+		i += 4
+		binary.BigEndian.PutUint32(data[i:], uint32(10+seq))
+		i += 4
+		binary.BigEndian.PutUint32(data[i:], uint32(len(objects)))
+		for _, obj := range objects {
+			data = append(data, obj...)
+		}
+
+		var eb EBlock
+		require.NoError(eb.UnmarshalBinary(data))
+		rawData[eb.KeyMR.String()] = data
+		prevKeyMR = *eb.KeyMR
+
+		dblocks[eb.Height] = newMockDBlock(t, eb)
+	}
+
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+			Params struct {
+				Hash    string `json:"hash"`
+				ChainID string `json:"chainid"`
+				Height  uint32 `json:"height"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		var res jsonrpc2.Response
+		res.ID = jReq.ID
+		switch jReq.Method {
+		case "chain-head":
+			if jReq.Params.ChainID != chainID.String() ||
+				len(eblocks) == 0 {
+				res.Error = jsonrpc2.Error{Code: -32009,
+					Message: "Missing Chain Head"}
+				break
+			}
+			res.Result = map[string]interface{}{
+				"chainhead": prevKeyMR.String()}
+		case "raw-data":
+			data, ok := rawData[jReq.Params.Hash]
+			if !ok {
+				res.Error = jsonrpc2.Error{Code: -32008,
+					Message: "Object not found"}
+				break
+			}
+			res.Result = map[string]interface{}{"data": data}
+		case "dblock-by-height":
+			data, ok := dblocks[jReq.Params.Height]
+			if !ok {
+				res.Error = jsonrpc2.Error{Code: -32008,
+					Message: "Block not found"}
+				break
+			}
+			res.Result = map[string]interface{}{"rawdata": data}
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(res)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+	return c
+}
+
+// mockDBlockTimestamp returns the Timestamp of the DBlock at height served by
+// newMockChain.
+func mockDBlockTimestamp(height uint32) time.Time {
+	return time.Unix(1500000000+int64(height)*600, 0)
+}
+
+// newMockDBlock returns the raw data of a DBlock containing only the Admin,
+// EC, and FCT Blocks, with zero KeyMRs, and eb.
+func newMockDBlock(t *testing.T, eb EBlock) Bytes {
+	return newMockDBlockAt(t, eb, mockDBlockTimestamp(eb.Height))
+}
+
+// newMockDBlockAt is like newMockDBlock but with the Timestamp ts.
+func newMockDBlockAt(t *testing.T, eb EBlock, ts time.Time) Bytes {
+	var elements [][]byte
+	for _, id := range []Bytes32{
+		ABlockChainID(), ECBlockChainID(), FBlockChainID()} {
+		elements = append(elements, append(id[:], make([]byte, 32)...))
+	}
+	elements = append(elements, append(eb.ChainID[:], eb.KeyMR[:]...))
+	bodyMR, err := ComputeDBlockBodyMR(elements)
+	require.NoError(t, err)
+
+	networkID := MainnetID()
+	data := make([]byte, DBlockHeaderSize)
+	i := 1 // Version byte
+	i += copy(data[i:], networkID[:])
+	i += copy(data[i:], bodyMR[:])
+	i += 2 * len(Bytes32{}) // PrevKeyMR, PrevFullHash
+	binary.BigEndian.PutUint32(data[i:],
+		uint32(ts.Unix()/60))
+	i += 4
+	binary.BigEndian.PutUint32(data[i:], eb.Height)
+	i += 4
+	binary.BigEndian.PutUint32(data[i:], uint32(len(elements)))
+	for _, element := range elements {
+		data = append(data, element...)
+	}
+	return data
+}
@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package namespace isolates the data of the tenants of multi-tenant
+// applications in separate Factom chains, whose ChainIDs are derived
+// deterministically from logical names.
+//
+// The chain for a tenant and topic within a Namespace is created by a first
+// Entry with the ExtIDs
+//
+//	["namespace", <namespace>, <tenant>, <topic>]
+//
+// so anyone who knows the names can compute the ChainID, and the data of
+// different tenants, topics, or applications never share a chain. The chain
+// is created on the first Write, and all subsequent Entries are written to it
+// with the ExtIDs and Content given by the application.
+package namespace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of the first Entry of all namespace chains.
+var Tag = factom.Bytes("namespace")
+
+// Namespace maps tenant and topic names within the namespace Name to ChainIDs.
+//
+// A Namespace remembers which chains it has seen to exist, so that Write only
+// checks for the chain once. It is safe for concurrent use.
+type Namespace struct {
+	Name string
+
+	mu     sync.Mutex
+	exists factom.Bytes32Set
+}
+
+// NameIDs returns the ExtIDs of the first Entry of the chain for tenant and
+// topic.
+func (ns *Namespace) NameIDs(tenant, topic string) []factom.Bytes {
+	return []factom.Bytes{Tag, factom.Bytes(ns.Name),
+		factom.Bytes(tenant), factom.Bytes(topic)}
+}
+
+// ChainID returns the ChainID of the chain for tenant and topic.
+func (ns *Namespace) ChainID(tenant, topic string) factom.Bytes32 {
+	return factom.ComputeChainID(ns.NameIDs(tenant, topic))
+}
+
+// Write submits an Entry with the given extIDs and content to the chain for
+// tenant and topic using factom.Entry.ComposeCreate with es. If the chain does
+// not yet exist, it is first created with its first Entry, which costs an
+// additional factom.NewChainCost Entry Credits.
+//
+// The submitted Entry and its Transaction ID are returned.
+func (ns *Namespace) Write(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, tenant, topic string,
+	extIDs []factom.Bytes, content []byte) (factom.Entry, factom.TxID, error) {
+	if len(tenant) == 0 {
+		return factom.Entry{}, factom.TxID{}, fmt.Errorf("empty tenant")
+	}
+	chainID := ns.ChainID(tenant, topic)
+	if err := ns.create(ctx, c, es, chainID, tenant, topic); err != nil {
+		return factom.Entry{}, factom.TxID{}, err
+	}
+
+	e := factom.Entry{ChainID: &chainID, ExtIDs: extIDs,
+		Content: factom.Bytes(content)}
+	if e.ExtIDs == nil {
+		e.ExtIDs = []factom.Bytes{}
+	}
+	if e.Content == nil {
+		e.Content = factom.Bytes{}
+	}
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return factom.Entry{}, factom.TxID{}, err
+	}
+	return e, txID, nil
+}
+
+// create creates the chain for tenant and topic, unless it is known to exist.
+func (ns *Namespace) create(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, chainID factom.Bytes32, tenant, topic string) error {
+	ns.mu.Lock()
+	ok := ns.exists.Has(chainID)
+	ns.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	exists, err := c.ChainExists(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		first := factom.Entry{ExtIDs: ns.NameIDs(tenant, topic),
+			Content: factom.Bytes{}}
+		if _, err := first.ComposeCreate(ctx, c, es); err != nil {
+			return fmt.Errorf("create chain: %w", err)
+		}
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.exists == nil {
+		ns.exists = make(factom.Bytes32Set)
+	}
+	ns.exists.Add(chainID)
+	return nil
+}
+
+// Read returns all Entries written to the chain for tenant and topic, in
+// order, not including its first Entry. If the chain does not exist, no
+// Entries and no error are returned.
+//
+// An error is returned if the first Entry of the chain does not have the
+// NameIDs for tenant and topic.
+func (ns *Namespace) Read(ctx context.Context, c *factom.Client,
+	tenant, topic string) ([]factom.Entry, error) {
+	chainID := ns.ChainID(tenant, topic)
+	exists, err := c.ChainExists(ctx, chainID)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	typed, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(typed) == 0 {
+		return nil, nil
+	}
+
+	nameIDs := ns.NameIDs(tenant, topic)
+	first := typed[0].ExtIDs
+	if len(first) != len(nameIDs) {
+		return nil, fmt.Errorf("first entry: invalid ExtIDs")
+	}
+	for i := range nameIDs {
+		if !bytes.Equal(first[i], nameIDs[i]) {
+			return nil, fmt.Errorf("first entry: invalid ExtIDs")
+		}
+	}
+
+	entries := make([]factom.Entry, len(typed)-1)
+	for i := range entries {
+		entries[i] = typed[i+1].Entry
+	}
+	return entries, nil
+}
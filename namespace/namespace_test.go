@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package namespace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/namespace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+
+	ns := Namespace{Name: "app"}
+	chainID := ns.ChainID("acme", "invoices")
+	assert.Equal(factom.ComputeChainID([]factom.Bytes{
+		Tag, factom.Bytes("app"), factom.Bytes("acme"),
+		factom.Bytes("invoices")}), chainID)
+	assert.NotEqual(chainID, ns.ChainID("acme", "orders"))
+	assert.NotEqual(chainID, ns.ChainID("other", "invoices"))
+	assert.NotEqual(chainID,
+		(&Namespace{Name: "other"}).ChainID("acme", "invoices"))
+
+	c, methods := newMockFactomd(t)
+
+	entries, err := ns.Read(ctx, c, "acme", "invoices")
+	require.NoError(err)
+	assert.Empty(entries)
+
+	_, _, err = ns.Write(ctx, c, es, "", "invoices", nil, nil)
+	assert.EqualError(err, "empty tenant")
+
+	*methods = nil
+	e, _, err := ns.Write(ctx, c, es, "acme", "invoices",
+		[]factom.Bytes{factom.Bytes("1")}, []byte("first"))
+	require.NoError(err)
+	assert.Equal(chainID, *e.ChainID)
+	assert.Equal([]string{"chain-head", "commit-chain", "reveal-entry",
+		"commit-entry", "reveal-entry"}, *methods)
+
+	// The chain is known to exist.
+	*methods = nil
+	_, _, err = ns.Write(ctx, c, es, "acme", "invoices",
+		nil, []byte("second"))
+	require.NoError(err)
+	assert.Equal([]string{"commit-entry", "reveal-entry"}, *methods)
+
+	// A new Namespace checks once.
+	*methods = nil
+	_, _, err = (&Namespace{Name: "app"}).Write(ctx, c, es, "acme",
+		"invoices", nil, []byte("third"))
+	require.NoError(err)
+	assert.Equal([]string{"chain-head", "commit-entry", "reveal-entry"},
+		*methods)
+
+	entries, err = ns.Read(ctx, c, "acme", "invoices")
+	require.NoError(err)
+	require.Len(entries, 3)
+	assert.Equal(factom.Bytes("first"), entries[0].Content)
+	assert.Equal([]factom.Bytes{factom.Bytes("1")}, entries[0].ExtIDs)
+	assert.Equal(factom.Bytes("third"), entries[2].Content)
+
+	entries, err = ns.Read(ctx, c, "acme", "orders")
+	require.NoError(err)
+	assert.Empty(entries)
+}
+
+// newMockFactomd returns a Client for a mock factomd that adds all revealed
+// Entries to a single EBlock of their chain, and the list of methods it has
+// been called with.
+func newMockFactomd(t *testing.T) (*factom.Client, *[]string) {
+	chains := make(map[factom.Bytes32][]factom.Bytes)
+	rawData := make(map[string]factom.Bytes)
+	var methods []string
+
+	eblock := func(chainID factom.Bytes32) factom.KeyMR {
+		objects := make([][]byte, 0, len(chains[chainID])+1)
+		for _, hash := range chains[chainID] {
+			objects = append(objects, hash)
+		}
+		objects = append(objects, (&factom.Bytes32{31: 1})[:])
+		bodyMR, err := factom.ComputeEBlockBodyMR(objects)
+		require.NoError(t, err)
+		data := make([]byte, factom.EBlockHeaderSize)
+		i := copy(data, chainID[:])
+		copy(data[i:], bodyMR[:])
+		binary.BigEndian.PutUint32(data[factom.EBlockHeaderSize-4:],
+			uint32(len(objects)))
+		for _, obj := range objects {
+			data = append(data, obj...)
+		}
+		var eb factom.EBlock
+		require.NoError(t, eb.UnmarshalBinary(data))
+		rawData[eb.KeyMR.String()] = data
+		return *eb.KeyMR
+	}
+
+	c := factom.NewClient()
+	c.Factomd.Client.Transport = roundTripFunc(
+		func(req *http.Request) *http.Response {
+			var jReq struct {
+				Method string      `json:"method"`
+				ID     interface{} `json:"id"`
+				Params struct {
+					Hash    string       `json:"hash"`
+					ChainID string       `json:"chainid"`
+					Entry   factom.Bytes `json:"entry"`
+				} `json:"params"`
+			}
+			reqData, _ := ioutil.ReadAll(req.Body)
+			_ = json.Unmarshal(reqData, &jReq)
+			methods = append(methods, jReq.Method)
+			res := jsonrpc2.Response{ID: jReq.ID, Result: struct{}{}}
+			switch jReq.Method {
+			case "chain-head":
+				var chainID factom.Bytes32
+				require.NoError(t, chainID.Set(jReq.Params.ChainID))
+				if len(chains[chainID]) == 0 {
+					res.Result = nil
+					res.Error = jsonrpc2.Error{Code: -32009,
+						Message: "Missing Chain Head"}
+					break
+				}
+				res.Result = map[string]interface{}{
+					"chainhead": eblock(chainID).String()}
+			case "raw-data":
+				res.Result = map[string]interface{}{
+					"data": rawData[jReq.Params.Hash]}
+			case "commit-chain", "commit-entry":
+			case "reveal-entry":
+				var e factom.Entry
+				require.NoError(t, e.UnmarshalBinary(jReq.Params.Entry))
+				rawData[e.Hash.String()] = jReq.Params.Entry
+				chains[*e.ChainID] = append(chains[*e.ChainID],
+					e.Hash[:])
+			default:
+				t.Errorf("unexpected request: %v", jReq.Method)
+			}
+			respData, _ := json.Marshal(res)
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewBuffer(respData)),
+				Header: make(http.Header),
+			}
+		})
+	return c, &methods
+}
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
@@ -0,0 +1,201 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Network describes a Factom network: its NetworkID, the default factomd and
+// factom-walletd endpoints to use with it, and optionally the KeyMR of its
+// genesis DBlock.
+//
+// Entry commits and Factoid Transactions do not commit to the NetworkID, so
+// the same signed data is valid on any network that shares the same
+// addresses. A Client with a Network set verifies the NetworkID of every
+// DBlock it loads, and calls VerifyNetwork before it first submits a commit or
+// Transaction, so that signed data is never sent to factomd on the wrong
+// network.
+type Network struct {
+	// ID is the NetworkID found in all DBlock headers of the network.
+	ID NetworkID
+
+	// FactomdServer and WalletdServer are the default endpoints used by
+	// Network.NewClient.
+	FactomdServer string
+	WalletdServer string
+
+	// GenesisKeyMR, if not nil, is the KeyMR of the DBlock at height 0.
+	GenesisKeyMR *KeyMR
+}
+
+// MainnetGenesisKeyMR returns the KeyMR of the mainnet DBlock at height 0.
+func MainnetGenesisKeyMR() KeyMR {
+	return NewKeyMR(
+		"17ef7a21d1a616d65e6b73f3c6a7ad5c49340a6c2592872020ec60767ff00d7d")
+}
+
+// Mainnet returns the Network for the Factom mainnet, using the Factom Open
+// Node courtesy endpoint for factomd.
+func Mainnet() Network {
+	genesis := MainnetGenesisKeyMR()
+	return Network{
+		ID:            MainnetID(),
+		FactomdServer: "https://api.factomd.net/v2",
+		WalletdServer: WalletdDefault,
+		GenesisKeyMR:  &genesis,
+	}
+}
+
+// Testnet returns the Network for the Factom community testnet, using the
+// Factom Open Node courtesy endpoint for factomd.
+//
+// The community testnet has been restarted from new genesis blocks, so its
+// GenesisKeyMR is not set and only its NetworkID is verified.
+func Testnet() Network {
+	return Network{
+		ID:            TestnetID(),
+		FactomdServer: "https://dev.factomd.net/v2",
+		WalletdServer: WalletdDefault,
+	}
+}
+
+// Localnet returns the Network for a LOCAL factomd network, using the default
+// localhost endpoints.
+func Localnet() Network {
+	return Network{
+		ID:            LocalnetID(),
+		FactomdServer: FactomdDefault,
+		WalletdServer: WalletdDefault,
+	}
+}
+
+// CustomNetwork returns a Network with the given id and the default localhost
+// endpoints.
+func CustomNetwork(id NetworkID) Network {
+	return Network{
+		ID:            id,
+		FactomdServer: FactomdDefault,
+		WalletdServer: WalletdDefault,
+	}
+}
+
+// ParseNetwork returns the Network for name, which may be any value accepted
+// by NetworkID.Set. A custom NetworkID returns a CustomNetwork.
+func ParseNetwork(name string) (Network, error) {
+	var id NetworkID
+	if err := id.Set(name); err != nil {
+		return Network{}, err
+	}
+	switch id {
+	case mainnetID:
+		return Mainnet(), nil
+	case testnetID:
+		return Testnet(), nil
+	case localnetID:
+		return Localnet(), nil
+	}
+	return CustomNetwork(id), nil
+}
+
+// String returns n.ID.String().
+func (n Network) String() string {
+	return n.ID.String()
+}
+
+// IsZero returns true if n has no NetworkID, in which case no network checks
+// are performed.
+func (n Network) IsZero() bool {
+	return n.ID == NetworkID{}
+}
+
+// NewClient returns a new Client using the default endpoints of n, which
+// verifies the NetworkID of all DBlocks it loads.
+func (n Network) NewClient() *Client {
+	c := NewClient()
+	c.Network = n
+	if n.FactomdServer != "" {
+		c.FactomdServer = n.FactomdServer
+	}
+	if n.WalletdServer != "" {
+		c.WalletdServer = n.WalletdServer
+	}
+	return c
+}
+
+// checkDBlock returns an error if n is not zero and db is not from the
+// network n.
+func (n Network) checkDBlock(db *DBlock) error {
+	if n.IsZero() {
+		return nil
+	}
+	if db.NetworkID != n.ID {
+		return fmt.Errorf("invalid NetworkID: %v, expected %v",
+			db.NetworkID, n.ID)
+	}
+	if db.Height == 0 && n.GenesisKeyMR != nil && db.KeyMR != nil &&
+		*db.KeyMR != *n.GenesisKeyMR {
+		return fmt.Errorf("invalid genesis KeyMR: %v, expected %v",
+			db.KeyMR, n.GenesisKeyMR)
+	}
+	return nil
+}
+
+// VerifyNetwork loads the genesis DBlock from factomd and returns an error if
+// its NetworkID, or its KeyMR if c.Network.GenesisKeyMR is set, does not match
+// c.Network.
+func (c *Client) VerifyNetwork(ctx context.Context) error {
+	if c.Network.IsZero() {
+		return fmt.Errorf("no Network set")
+	}
+	var db DBlock
+	if err := db.Get(ctx, c); err != nil {
+		return err
+	}
+	atomic.StoreUint32(&c.networkVerified, 1)
+	return nil
+}
+
+// checkNetwork calls VerifyNetwork if c.Network is set and has not yet been
+// verified by c. It is called before any commit or Transaction is submitted.
+func (c *Client) checkNetwork(ctx context.Context) error {
+	if c.Network.IsZero() || atomic.LoadUint32(&c.networkVerified) == 1 {
+		return nil
+	}
+	return c.VerifyNetwork(ctx)
+}
+
+// Set sets n to the Network for name. See ParseNetwork.
+//
+// Network conforms to the flag.Value interface.
+func (n *Network) Set(name string) error {
+	network, err := ParseNetwork(strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+	*n = network
+	return nil
+}
@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetwork(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Exp  Network
+		Err  string
+	}{
+		{Name: "main", Exp: Mainnet()},
+		{Name: "testnet", Exp: Testnet()},
+		{Name: "LOCAL", Exp: Localnet()},
+		{Name: "0xfa92e5a3",
+			Exp: CustomNetwork(NetworkID{0xfa, 0x92, 0xe5, 0xa3})},
+		{Name: "0x00", Err: "invalid length"},
+		{Name: "", Err: "invalid length"},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := ParseNetwork(test.Name)
+			if test.Err != "" {
+				assert.EqualError(err, test.Err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.Exp, n)
+		})
+	}
+}
+
+func TestNetworkNewClient(t *testing.T) {
+	assert := assert.New(t)
+	c := Mainnet().NewClient()
+	assert.Equal(Mainnet(), c.Network)
+	assert.Equal("https://api.factomd.net/v2", c.FactomdServer)
+	assert.Equal(WalletdDefault, c.WalletdServer)
+
+	c = CustomNetwork(NetworkID{1, 2, 3, 4}).NewClient()
+	assert.Equal(FactomdDefault, c.FactomdServer)
+	assert.Equal("custom: 0x01020304", c.Network.String())
+}
+
+func TestNetworkDBlockGet(t *testing.T) {
+	test := DBlockTests[0]
+	results := map[string]interface{}{
+		"dblock-by-height": map[string]interface{}{
+			"rawdata": Bytes(test.Data),
+			"dblock": map[string]interface{}{
+				"keymr": test.Exp.KeyMR},
+		},
+	}
+	for _, network := range []Network{{}, Mainnet(), Testnet()} {
+		t.Run(network.String(), func(t *testing.T) {
+			c := newMockClient(t, results)
+			c.Network = network
+			db := DBlock{Height: test.Exp.Height}
+			err := db.Get(context.Background(), c)
+			if network.ID == TestnetID() {
+				assert.EqualError(t, err, "invalid NetworkID: "+
+					"mainnet, expected testnet")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, MainnetID(), db.NetworkID)
+		})
+	}
+
+	c := newMockClient(t, results)
+	assert.EqualError(t, c.VerifyNetwork(context.Background()),
+		"no Network set")
+}
+
+func TestNetworkGenesis(t *testing.T) {
+	assert.Equal(t, NewKeyMR(
+		"17ef7a21d1a616d65e6b73f3c6a7ad5c49340a6c2592872020ec60767ff00d7d"),
+		*Mainnet().GenesisKeyMR)
+	assert.Nil(t, Testnet().GenesisKeyMR)
+
+	// A mainnet DBlock moved to height 0, which has a different KeyMR than
+	// the real genesis DBlock.
+	data := append(Bytes{}, DBlockTests[0].Data...)
+	binary.BigEndian.PutUint32(data[DBlockHeaderSize-8:], 0)
+	var genesis DBlock
+	require.NoError(t, genesis.UnmarshalBinary(data))
+	require.Equal(t, uint32(0), genesis.Height)
+
+	results := map[string]interface{}{
+		"dblock-by-height": map[string]interface{}{"rawdata": data},
+		"commit-entry":     map[string]interface{}{},
+	}
+	commit := make([]byte, EntryCommitSize)
+
+	c := newMockClient(t, results)
+	c.Network = Mainnet()
+	assert.EqualError(t, c.VerifyNetwork(context.Background()),
+		"invalid genesis KeyMR: "+genesis.KeyMR.String()+
+			", expected "+Mainnet().GenesisKeyMR.String())
+	assert.Error(t, c.Commit(context.Background(), commit))
+
+	c = newMockClient(t, results)
+	c.Network = Mainnet()
+	c.Network.GenesisKeyMR = genesis.KeyMR
+	assert.NoError(t, c.VerifyNetwork(context.Background()))
+	assert.NoError(t, c.Commit(context.Background(), commit))
+
+	c = newMockClient(t, results)
+	c.Network = Testnet()
+	assert.EqualError(t, c.FactoidSubmit(context.Background(), nil),
+		"invalid NetworkID: mainnet, expected testnet")
+}
@@ -74,7 +74,7 @@ func (n *NetworkID) Set(netIDStr string) error {
 	case "local", "localnet":
 		*n = localnetID
 	default:
-		if netIDStr[:2] == "0x" {
+		if strings.HasPrefix(netIDStr, "0x") {
 			// omit leading 0x
 			netIDStr = netIDStr[2:]
 		}
@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package nonce implements replay protection for signed Entry protocols.
+//
+// A protocol embeds a Nonce in an ExtID of each signed Entry, and includes it
+// in the signed data. A Nonce is the time that it was created followed by
+// random bytes, so it is unique and also bounds when the Entry may appear on
+// chain.
+//
+// A Verifier accepts each Nonce at most once, and only if the Entry's
+// Timestamp is within the Verifier's Window of the Nonce's time. Since
+// Nonces outside of the Window are always rejected, a Verifier only needs to
+// remember the Nonces from the most recent Window, which bounds its memory
+// use.
+//
+// Verifiers use the Entry Timestamps, not the local clock, so replaying the
+// history of a chain always produces the same result.
+package nonce
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Size is the length of an encoded Nonce: an 8 byte big endian Unix
+// timestamp in nanoseconds followed by 16 random bytes.
+const Size = 8 + 16
+
+// Nonce is a unique, timestamped value that may be used only once.
+type Nonce [Size]byte
+
+// New returns a new Nonce for the current time.
+func New() (Nonce, error) {
+	return NewAt(time.Now())
+}
+
+// NewAt returns a new Nonce for t.
+func NewAt(t time.Time) (Nonce, error) {
+	var n Nonce
+	binary.BigEndian.PutUint64(n[:8], uint64(t.UnixNano()))
+	if _, err := rand.Read(n[8:]); err != nil {
+		return Nonce{}, err
+	}
+	return n, nil
+}
+
+// Parse returns the Nonce encoded in extID.
+func Parse(extID []byte) (Nonce, error) {
+	var n Nonce
+	if len(extID) != len(n) {
+		return Nonce{}, fmt.Errorf("invalid nonce length")
+	}
+	copy(n[:], extID)
+	return n, nil
+}
+
+// Time returns the time that n was created.
+func (n Nonce) Time() time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(n[:8])))
+}
+
+// ExtID returns n as an ExtID.
+func (n Nonce) ExtID() factom.Bytes {
+	return append(factom.Bytes(nil), n[:]...)
+}
+
+// DefaultWindow is the Window used by a Verifier if its Window is zero.
+const DefaultWindow = 12 * time.Hour
+
+var (
+	// ErrorReplay is returned by Verifier.Check for a Nonce that has
+	// already been accepted.
+	ErrorReplay = fmt.Errorf("nonce replayed")
+
+	// ErrorExpired is returned by Verifier.Check for a Nonce whose time
+	// is not within the Window of the Entry Timestamp.
+	ErrorExpired = fmt.Errorf("nonce expired")
+)
+
+// Verifier rejects replayed and expired Nonces. The zero value is ready to
+// use, and a Verifier is safe for concurrent use.
+//
+// A Verifier should be used for the Entries of a single protocol, in the
+// order that they appear on chain.
+type Verifier struct {
+	// Window is the maximum difference between the time of a Nonce and
+	// the Timestamp of its Entry. If zero, DefaultWindow is used.
+	Window time.Duration
+
+	mu     sync.Mutex
+	seen   map[Nonce]struct{}
+	latest time.Time
+}
+
+func (v *Verifier) window() time.Duration {
+	if v.Window == 0 {
+		return DefaultWindow
+	}
+	return v.Window
+}
+
+// Check accepts n if it has not already been accepted and its time is within
+// v.Window of ts, which should be the Timestamp of the Entry that contains n.
+// Otherwise ErrorReplay or ErrorExpired is returned.
+func (v *Verifier) Check(n Nonce, ts time.Time) error {
+	window := v.window()
+	diff := ts.Sub(n.Time())
+	if diff < -window || diff > window {
+		return ErrorExpired
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.seen == nil {
+		v.seen = make(map[Nonce]struct{})
+	}
+	// Nonces from before the window of the latest Timestamp can no longer
+	// be accepted, so they are forgotten.
+	if ts.After(v.latest) {
+		v.latest = ts
+		v.prune(ts.Add(-window))
+	}
+	if n.Time().Before(v.latest.Add(-window)) {
+		return ErrorExpired
+	}
+	if _, ok := v.seen[n]; ok {
+		return ErrorReplay
+	}
+	v.seen[n] = struct{}{}
+	return nil
+}
+
+// CheckEntry parses the Nonce from e.ExtIDs[i] and then calls Check with
+// e.Timestamp.
+func (v *Verifier) CheckEntry(e factom.Entry, i int) error {
+	if i < 0 || i >= len(e.ExtIDs) {
+		return fmt.Errorf("missing nonce ExtID")
+	}
+	n, err := Parse(e.ExtIDs[i])
+	if err != nil {
+		return err
+	}
+	return v.Check(n, e.Timestamp)
+}
+
+// Len returns the number of Nonces that v currently remembers.
+func (v *Verifier) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.seen)
+}
+
+// prune forgets all Nonces from before cutoff. The caller must hold v.mu.
+func (v *Verifier) prune(cutoff time.Time) {
+	for n := range v.seen {
+		if n.Time().Before(cutoff) {
+			delete(v.seen, n)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package nonce_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/nonce"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonce(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Unix(1500000000, 123)
+	n1, err := NewAt(now)
+	require.NoError(err)
+	n2, err := NewAt(now)
+	require.NoError(err)
+	assert.NotEqual(n1, n2)
+	assert.True(now.Equal(n1.Time()))
+
+	n, err := Parse(n1.ExtID())
+	require.NoError(err)
+	assert.Equal(n1, n)
+
+	_, err = Parse(n1[1:])
+	assert.EqualError(err, "invalid nonce length")
+}
+
+func TestVerifier(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	newNonce := func(ts time.Time) Nonce {
+		n, err := NewAt(ts)
+		require.NoError(t, err)
+		return n
+	}
+
+	n1 := newNonce(start)
+	n2 := newNonce(start.Add(time.Minute))
+	v := Verifier{Window: time.Hour}
+	// The tests run in order against the same Verifier.
+	for _, test := range []struct {
+		Name  string
+		Nonce Nonce
+		TS    time.Time
+		Err   error
+		Len   int
+	}{{
+		Name:  "valid",
+		Nonce: n1,
+		TS:    start.Add(time.Minute),
+		Len:   1,
+	}, {
+		Name:  "replay",
+		Nonce: n1,
+		TS:    start.Add(2 * time.Minute),
+		Err:   ErrorReplay,
+		Len:   1,
+	}, {
+		Name:  "valid/second",
+		Nonce: n2,
+		TS:    start.Add(2 * time.Minute),
+		Len:   2,
+	}, {
+		Name:  "expired/future",
+		Nonce: newNonce(start.Add(2 * time.Hour)),
+		TS:    start.Add(2 * time.Minute),
+		Err:   ErrorExpired,
+		Len:   2,
+	}, {
+		Name:  "expired/past",
+		Nonce: newNonce(start),
+		TS:    start.Add(2 * time.Hour),
+		Err:   ErrorExpired,
+		Len:   2,
+	}, {
+		Name:  "prune",
+		Nonce: newNonce(start.Add(70 * time.Minute)),
+		TS:    start.Add(70 * time.Minute),
+		Len:   1,
+	}, {
+		Name:  "replay/pruned",
+		Nonce: n2,
+		TS:    start.Add(61 * time.Minute),
+		Err:   ErrorExpired,
+		Len:   1,
+	}} {
+		err := v.Check(test.Nonce, test.TS)
+		if test.Err != nil {
+			assert.Equal(t, test.Err, err, test.Name)
+		} else {
+			assert.NoError(t, err, test.Name)
+		}
+		assert.Equal(t, test.Len, v.Len(), test.Name)
+	}
+}
+
+func TestVerifierCheckEntry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Unix(1500000000, 0)
+	n, err := NewAt(now)
+	require.NoError(err)
+	e := factom.Entry{
+		ExtIDs:    []factom.Bytes{factom.Bytes("sig"), n.ExtID()},
+		Timestamp: now,
+	}
+
+	var v Verifier
+	assert.EqualError(v.CheckEntry(e, 2), "missing nonce ExtID")
+	assert.EqualError(v.CheckEntry(e, 0), "invalid nonce length")
+	assert.NoError(v.CheckEntry(e, 1))
+	assert.Equal(ErrorReplay, v.CheckEntry(e, 1))
+}
@@ -0,0 +1,285 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package notary implements document notarization, or hash anchoring, on the
+// Factom blockchain.
+//
+// A document is notarized by writing a Proof Entry, which contains the SHA-256
+// and SHA-512 hashes and the size of the document, but not the document itself.
+// The Proof Entry has the ExtIDs
+//
+//	["notarization", <SHA-256 (32 bytes)>, <SHA-512 (64 bytes)>]
+//
+// or, if it records the name of a file, such as a CI artifact or backup,
+//
+//	["notarization", <SHA-256 (32 bytes)>, <SHA-512 (64 bytes)>, <filename>]
+//
+// and its Content is the JSON object
+//
+//	{"size":<document size in bytes>}
+//
+// Once the Proof Entry is included in a DBlock, VerifyNotarization proves
+// that the document existed at the time of the DBlock by checking the
+// document against the Proof Entry and checking the Entry's Merkle Receipt
+// against the DBlock.
+package notary
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Tag is the first ExtID of all Proof Entries.
+var Tag = factom.Bytes("notarization")
+
+// Proof is the data stored in a Proof Entry.
+type Proof struct {
+	SHA256 factom.Bytes32
+	SHA512 [sha512.Size]byte
+	Size   uint64
+
+	// Filename is optional and is not covered by the hashes.
+	Filename string
+}
+
+// NewProof streams all data from r and returns its Proof. The data is never
+// held in memory in full, so r may be arbitrarily large.
+func NewProof(r io.Reader) (Proof, error) {
+	h256 := sha256.New()
+	h512 := sha512.New()
+	size, err := io.CopyBuffer(io.MultiWriter(h256, h512), r,
+		make([]byte, 1<<20))
+	if err != nil {
+		return Proof{}, err
+	}
+	var p Proof
+	h256.Sum(p.SHA256[:0])
+	h512.Sum(p.SHA512[:0])
+	p.Size = uint64(size)
+	return p, nil
+}
+
+type proofContent struct {
+	Size *uint64 `json:"size"`
+}
+
+// Entry returns the Proof Entry for p in the given chainID. If chainID is nil,
+// the Entry will create a new chain, whose ChainID is computed from the ExtIDs.
+func (p Proof) Entry(chainID *factom.Bytes32) factom.Entry {
+	content, _ := json.Marshal(proofContent{Size: &p.Size})
+	extIDs := []factom.Bytes{Tag, p.SHA256[:], p.SHA512[:]}
+	if p.Filename != "" {
+		extIDs = append(extIDs, factom.Bytes(p.Filename))
+	}
+	return factom.Entry{
+		ChainID: chainID,
+		ExtIDs:  extIDs,
+		Content: content,
+	}
+}
+
+// ParseProof parses the Proof from the Proof Entry e.
+func ParseProof(e factom.Entry) (Proof, error) {
+	if len(e.ExtIDs) < 3 || len(e.ExtIDs) > 4 ||
+		!bytes.Equal(e.ExtIDs[0], Tag) {
+		return Proof{}, fmt.Errorf("invalid ExtIDs")
+	}
+	var p Proof
+	if len(e.ExtIDs[1]) != len(p.SHA256) {
+		return Proof{}, fmt.Errorf("invalid SHA-256 length")
+	}
+	if len(e.ExtIDs[2]) != len(p.SHA512) {
+		return Proof{}, fmt.Errorf("invalid SHA-512 length")
+	}
+	copy(p.SHA256[:], e.ExtIDs[1])
+	copy(p.SHA512[:], e.ExtIDs[2])
+	if len(e.ExtIDs) == 4 {
+		if len(e.ExtIDs[3]) == 0 {
+			return Proof{}, fmt.Errorf("empty filename")
+		}
+		p.Filename = string(e.ExtIDs[3])
+	}
+
+	var content proofContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return Proof{}, fmt.Errorf("invalid content: %w", err)
+	}
+	if content.Size == nil {
+		return Proof{}, fmt.Errorf("invalid content: missing size")
+	}
+	p.Size = *content.Size
+	return p, nil
+}
+
+// Notarize streams all data from r and submits its Proof Entry in a new chain
+// using ComposeCreate with es.
+//
+// Since the ChainID is derived from the Proof, the same document can only be
+// notarized once with Notarize. Use NotarizeInChain to write the Proof Entry
+// to an existing chain.
+//
+// The returned Entry Hash is required by VerifyNotarization, which succeeds
+// only after the Entry is included in a DBlock.
+func Notarize(ctx context.Context, c *factom.Client, es factom.EsAddress,
+	r io.Reader) (factom.EntryHash, factom.TxID, error) {
+	return NotarizeInChain(ctx, c, es, nil, r)
+}
+
+// NotarizeInChain is like Notarize, but writes the Proof Entry to the existing
+// chainID, unless it is nil.
+func NotarizeInChain(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, chainID *factom.Bytes32,
+	r io.Reader) (factom.EntryHash, factom.TxID, error) {
+	p, err := NewProof(r)
+	if err != nil {
+		return factom.EntryHash{}, factom.TxID{}, err
+	}
+	e := p.Entry(chainID)
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return factom.EntryHash{}, factom.TxID{}, err
+	}
+	return *e.Hash, txID, nil
+}
+
+// CommitFileHash streams all data from r, which may be many gigabytes, and
+// submits its Proof Entry in a new chain using ComposeCreate with es. If r has
+// a Name method, as *os.File does, the base name is recorded as the Filename.
+//
+// Use CommitFileHashInChain to write the Proof Entry to an existing chain,
+// such as one chain per CI pipeline, or to set the Filename explicitly.
+func CommitFileHash(ctx context.Context, c *factom.Client, es factom.EsAddress,
+	r io.Reader) (Proof, factom.EntryHash, factom.TxID, error) {
+	var filename string
+	if f, ok := r.(interface{ Name() string }); ok {
+		filename = filepath.Base(f.Name())
+	}
+	return CommitFileHashInChain(ctx, c, es, nil, filename, r)
+}
+
+// CommitFileHashInChain is like CommitFileHash, but writes the Proof Entry to
+// the existing chainID, unless it is nil, and records filename, unless it is
+// empty.
+func CommitFileHashInChain(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, chainID *factom.Bytes32, filename string,
+	r io.Reader) (Proof, factom.EntryHash, factom.TxID, error) {
+	p, err := NewProof(r)
+	if err != nil {
+		return Proof{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	p.Filename = filename
+	e := p.Entry(chainID)
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return Proof{}, factom.EntryHash{}, factom.TxID{}, err
+	}
+	return p, *e.Hash, txID, nil
+}
+
+// Notarization is a verified notarization of a document.
+type Notarization struct {
+	Proof
+
+	// Entry is the Proof Entry.
+	Entry factom.Entry
+
+	// Receipt proves that the Entry is in the DBlock at
+	// Receipt.DBlockHeight.
+	Receipt factom.Receipt
+
+	// Timestamp is the time of the DBlock that includes the Entry.
+	Timestamp time.Time
+}
+
+// Verify returns the Proof of the document read from r if it matches the
+// Proof Entry e.
+func Verify(r io.Reader, e factom.Entry) (Proof, error) {
+	expected, err := ParseProof(e)
+	if err != nil {
+		return Proof{}, err
+	}
+	p, err := NewProof(r)
+	if err != nil {
+		return Proof{}, err
+	}
+	p.Filename = expected.Filename
+	if p != expected {
+		return Proof{}, fmt.Errorf("document does not match proof")
+	}
+	return p, nil
+}
+
+// VerifyNotarization verifies that the document read from r was notarized by
+// the Proof Entry with the given entryHash.
+//
+// The Proof Entry is downloaded and checked against the document. Then the
+// Entry's Receipt is downloaded and validated, and the DBlock at the
+// Receipt's height is downloaded to ensure that its KeyMR matches the Receipt
+// and that it includes the Receipt's EBlock for the Entry's chain.
+func VerifyNotarization(ctx context.Context, c *factom.Client,
+	r io.Reader, entryHash factom.EntryHash) (Notarization, error) {
+	e := factom.Entry{Hash: &entryHash}
+	if err := e.Get(ctx, c); err != nil {
+		return Notarization{}, err
+	}
+	p, err := Verify(r, e)
+	if err != nil {
+		return Notarization{}, err
+	}
+
+	receipt := factom.Receipt{EntryHash: entryHash}
+	if err := receipt.Get(ctx, c); err != nil {
+		return Notarization{}, err
+	}
+
+	db := factom.DBlock{Height: receipt.DBlockHeight}
+	if err := db.Get(ctx, c); err != nil {
+		return Notarization{}, err
+	}
+	if *db.KeyMR != receipt.DBlockKeyMR {
+		return Notarization{}, fmt.Errorf("invalid receipt: DBlock KeyMR")
+	}
+	var found bool
+	for _, eb := range db.EBlocks {
+		if *eb.ChainID == *e.ChainID && *eb.KeyMR == receipt.EBlockKeyMR {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return Notarization{}, fmt.Errorf("invalid receipt: EBlock not in DBlock")
+	}
+
+	return Notarization{Proof: p, Entry: e, Receipt: receipt,
+		Timestamp: db.Timestamp}, nil
+}
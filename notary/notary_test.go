@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package notary_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/notary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = "The quick brown fox jumps over the lazy dog"
+
+func TestProof(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p, err := NewProof(strings.NewReader(document))
+	require.NoError(err)
+	assert.Equal(factom.Bytes32(sha256.Sum256([]byte(document))), p.SHA256)
+	assert.Equal(sha512.Sum512([]byte(document)), p.SHA512)
+	assert.Equal(uint64(len(document)), p.Size)
+
+	e := p.Entry(nil)
+	assert.Nil(e.ChainID)
+	assert.Equal(`{"size":43}`, string(e.Content))
+	parsed, err := ParseProof(e)
+	require.NoError(err)
+	assert.Equal(p, parsed)
+
+	verified, err := Verify(strings.NewReader(document), e)
+	require.NoError(err)
+	assert.Equal(p, verified)
+
+	_, err = Verify(strings.NewReader(document+"."), e)
+	assert.EqualError(err, "document does not match proof")
+}
+
+func TestParseProofInvalid(t *testing.T) {
+	p, err := NewProof(strings.NewReader(document))
+	require.NoError(t, err)
+	for _, test := range []struct {
+		Name   string
+		Modify func(*factom.Entry)
+		Error  string
+	}{{
+		Name:   "missing tag",
+		Modify: func(e *factom.Entry) { e.ExtIDs[0] = factom.Bytes("x") },
+		Error:  "invalid ExtIDs",
+	}, {
+		Name:   "invalid SHA-256",
+		Modify: func(e *factom.Entry) { e.ExtIDs[1] = e.ExtIDs[1][1:] },
+		Error:  "invalid SHA-256 length",
+	}, {
+		Name:   "invalid SHA-512",
+		Modify: func(e *factom.Entry) { e.ExtIDs[2] = e.ExtIDs[2][1:] },
+		Error:  "invalid SHA-512 length",
+	}, {
+		Name:   "missing size",
+		Modify: func(e *factom.Entry) { e.Content = factom.Bytes(`{}`) },
+		Error:  "invalid content: missing size",
+	}, {
+		Name: "unknown field",
+		Modify: func(e *factom.Entry) {
+			e.Content = factom.Bytes(`{"size":1,"x":1}`)
+		},
+		Error: `invalid content: json: unknown field "x"`,
+	}, {
+		Name: "empty filename",
+		Modify: func(e *factom.Entry) {
+			e.ExtIDs = append(e.ExtIDs, factom.Bytes{})
+		},
+		Error: "empty filename",
+	}, {
+		Name: "extra ExtID",
+		Modify: func(e *factom.Entry) {
+			e.ExtIDs = append(e.ExtIDs, factom.Bytes("a"),
+				factom.Bytes("b"))
+		},
+		Error: "invalid ExtIDs",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			e := p.Entry(nil)
+			test.Modify(&e)
+			_, err := ParseProof(e)
+			assert.EqualError(t, err, test.Error)
+		})
+	}
+}
+
+func TestNotarize(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+
+	entryHash, _, err := Notarize(context.Background(), c, es,
+		strings.NewReader(document))
+	require.NoError(err)
+
+	requests := c.DryRun.Requests()
+	require.Len(requests, 2)
+	assert.Equal("commit-chain", requests[0].Method)
+	assert.Equal("reveal-entry", requests[1].Method)
+
+	p, err := NewProof(strings.NewReader(document))
+	require.NoError(err)
+	e := p.Entry(nil)
+	chainID := factom.ComputeChainID(e.ExtIDs)
+	e.ChainID = &chainID
+	data, err := e.MarshalBinary()
+	require.NoError(err)
+	assert.Equal(factom.ComputeEntryHash(data), entryHash)
+}
+
+func TestCommitFileHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	f, err := ioutil.TempFile("", "artifact-*.tar")
+	require.NoError(err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	_, err = f.WriteString(document)
+	require.NoError(err)
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(err)
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	c := factom.NewClient()
+	c.DryRun = &factom.DryRun{SkipBalanceCheck: true}
+
+	p, _, _, err := CommitFileHash(context.Background(), c, es, f)
+	require.NoError(err)
+	assert.Equal(filepath.Base(f.Name()), p.Filename)
+	assert.Equal(uint64(len(document)), p.Size)
+
+	e := p.Entry(nil)
+	require.Len(e.ExtIDs, 4)
+	parsed, err := ParseProof(e)
+	require.NoError(err)
+	assert.Equal(p, parsed)
+	verified, err := Verify(strings.NewReader(document), e)
+	require.NoError(err)
+	assert.Equal(p, verified)
+
+	chainID := factom.NewBytes32(
+		"df3ade9eec4b08d5379cc64270c30ea7315d8a8a1a69efe2b98a60ecdd69e604")
+	p, _, _, err = CommitFileHashInChain(context.Background(), c, es,
+		&chainID, "", strings.NewReader(document))
+	require.NoError(err)
+	assert.Empty(p.Filename)
+	assert.Len(p.Entry(&chainID).ExtIDs, 3)
+	requests := c.DryRun.Requests()
+	require.Len(requests, 4)
+	assert.Equal("commit-entry", requests[2].Method)
+}
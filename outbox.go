@@ -0,0 +1,255 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outbox is durable storage for the pending writes of an EntryWriter. Each
+// queued Entry or Transaction is stored until it has been published, along
+// with its commit once composed, so that writes survive process restarts.
+// See EntryWriter.LoadOutbox.
+//
+// Implementations may use any key value store, such as BoltDB or SQLite.
+// FileOutbox stores records as files in a directory.
+type Outbox interface {
+	// Put durably stores record under id, replacing any record already
+	// stored under id.
+	Put(id Bytes32, record []byte) error
+
+	// Delete removes the record stored under id, if any.
+	Delete(id Bytes32) error
+
+	// Records calls fn with every record stored, in any order. If fn
+	// returns an error, Records stops and returns it.
+	Records(fn func(id Bytes32, record []byte) error) error
+}
+
+// outboxRecord is the JSON encoding of a queuedEntry in an Outbox.
+type outboxRecord struct {
+	Seq         uint64    `json:"seq"`
+	Priority    Priority  `json:"priority"`
+	Tag         string    `json:"tag,omitempty"`
+	Entry       Bytes     `json:"entry,omitempty"`
+	NewChain    bool      `json:"newchain,omitempty"`
+	Commit      Bytes     `json:"commit,omitempty"`
+	Accepted    bool      `json:"accepted,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	Expired     bool      `json:"expired,omitempty"`
+	Transaction Bytes     `json:"transaction,omitempty"`
+}
+
+// MemoryOutbox is an Outbox held in memory, which is useful for tests. It is
+// safe for concurrent use. The zero value is ready to use.
+type MemoryOutbox struct {
+	mu      sync.Mutex
+	records map[Bytes32][]byte
+}
+
+var _ Outbox = &MemoryOutbox{}
+
+// Put stores a copy of record under id.
+func (o *MemoryOutbox) Put(id Bytes32, record []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.records == nil {
+		o.records = make(map[Bytes32][]byte)
+	}
+	o.records[id] = append([]byte{}, record...)
+	return nil
+}
+
+// Delete removes the record stored under id, if any.
+func (o *MemoryOutbox) Delete(id Bytes32) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.records, id)
+	return nil
+}
+
+// Records calls fn with a copy of every record stored.
+func (o *MemoryOutbox) Records(fn func(id Bytes32, record []byte) error) error {
+	o.mu.Lock()
+	records := make(map[Bytes32][]byte, len(o.records))
+	for id, record := range o.records {
+		records[id] = append([]byte{}, record...)
+	}
+	o.mu.Unlock()
+	for id, record := range records {
+		if err := fn(id, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of records stored.
+func (o *MemoryOutbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.records)
+}
+
+// FileOutbox is an Outbox that stores each record in its own file in Dir,
+// named by the hex encoded id. Records are written to a temporary file, synced
+// and then renamed, so a record is never partially written.
+//
+// Dir must already exist, and must only be used by one EntryWriter at a time.
+type FileOutbox struct {
+	Dir string
+}
+
+var _ Outbox = FileOutbox{}
+
+const fileOutboxTmpExt = ".tmp"
+
+// Put durably stores record in the file for id.
+func (o FileOutbox) Put(id Bytes32, record []byte) error {
+	path := filepath.Join(o.Dir, id.String())
+	tmp := path + fileOutboxTmpExt
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(record); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return o.syncDir()
+}
+
+// Delete removes the file for id, if it exists.
+func (o FileOutbox) Delete(id Bytes32) error {
+	err := os.Remove(filepath.Join(o.Dir, id.String()))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return o.syncDir()
+}
+
+// Records calls fn with the contents of every record file in Dir. Temporary
+// files left by an interrupted Put are ignored. Any other file or directory in
+// Dir that is not named by a hex encoded id returns an error, since it may be
+// a record that would otherwise never be resubmitted.
+func (o FileOutbox) Records(fn func(id Bytes32, record []byte) error) error {
+	infos, err := ioutil.ReadDir(o.Dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && strings.HasSuffix(name, fileOutboxTmpExt) {
+			continue
+		}
+		var id Bytes32
+		if info.IsDir() || id.Set(name) != nil || id.String() != name {
+			return fmt.Errorf("invalid outbox record file: %q",
+				filepath.Join(o.Dir, name))
+		}
+		record, err := ioutil.ReadFile(filepath.Join(o.Dir, name))
+		if err != nil {
+			return err
+		}
+		if err := fn(id, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDir syncs Dir so that renames and removals are durable.
+func (o FileOutbox) syncDir() error {
+	dir, err := os.Open(o.Dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil && !os.IsPermission(err) {
+		return err
+	}
+	return nil
+}
+
+// marshalOutboxRecord returns the outboxRecord encoding of e.
+func (e *queuedEntry) marshalOutboxRecord() ([]byte, error) {
+	return json.Marshal(outboxRecord{
+		Seq:         e.seq,
+		Priority:    e.priority,
+		Tag:         e.tag,
+		Entry:       e.reveal,
+		NewChain:    e.newChain,
+		Commit:      e.commit,
+		Accepted:    e.accepted,
+		Timestamp:   e.timestamp,
+		Expired:     e.expired,
+		Transaction: e.tx,
+	})
+}
+
+// unmarshalOutboxRecord populates e from its outboxRecord encoding.
+func (e *queuedEntry) unmarshalOutboxRecord(id Bytes32, data []byte) error {
+	var r outboxRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	*e = queuedEntry{
+		id:        id,
+		seq:       r.Seq,
+		priority:  r.Priority,
+		tag:       r.Tag,
+		newChain:  r.NewChain,
+		commit:    r.Commit,
+		accepted:  r.Accepted,
+		timestamp: r.Timestamp,
+		expired:   r.Expired,
+		tx:        r.Transaction,
+		recovered: true,
+	}
+	if e.tx != nil {
+		return nil
+	}
+	if err := e.UnmarshalBinary(r.Entry); err != nil {
+		return err
+	}
+	e.reveal = r.Entry
+	e.Hash = new(EntryHash)
+	*e.Hash = ComputeEntryHash(e.reveal)
+	return nil
+}
@@ -0,0 +1,199 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOutbox(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	o := FileOutbox{Dir: dir}
+
+	records := func() map[Bytes32]string {
+		records := make(map[Bytes32]string)
+		require.NoError(o.Records(func(id Bytes32, record []byte) error {
+			records[id] = string(record)
+			return nil
+		}))
+		return records
+	}
+
+	require.NoError(o.Put(Bytes32{1}, []byte("one")))
+	require.NoError(o.Put(Bytes32{2}, []byte("two")))
+	require.NoError(o.Put(Bytes32{1}, []byte("uno")))
+	// Temporary files from an interrupted Put are ignored.
+	require.NoError(ioutil.WriteFile(filepath.Join(dir,
+		Bytes32{3}.String()+".tmp"), []byte("three"), 0600))
+	assert.Equal(map[Bytes32]string{{1}: "uno", {2}: "two"}, records())
+
+	require.NoError(o.Delete(Bytes32{2}))
+	require.NoError(o.Delete(Bytes32{2}))
+	assert.Equal(map[Bytes32]string{{1}: "uno"}, records())
+
+	// Files that are not named by an id are reported, not skipped.
+	for _, name := range []string{"notes.txt",
+		strings.ToUpper(Bytes32{0xab}.String())} {
+		path := filepath.Join(dir, name)
+		require.NoError(ioutil.WriteFile(path, nil, 0600))
+		err := o.Records(func(Bytes32, []byte) error { return nil })
+		assert.EqualError(err,
+			fmt.Sprintf("invalid outbox record file: %q", path))
+		require.NoError(os.Remove(path))
+	}
+}
+
+func TestEntryWriterOutbox(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	chainID := Bytes32{1}
+	a := Entry{ChainID: &chainID, Content: Bytes("a")}
+	b := Entry{ChainID: &chainID, Content: Bytes("b")}
+	data, err := b.MarshalBinary()
+	require.NoError(err)
+	bHash := ComputeEntryHash(data)
+
+	fs, err := GenerateFsAddress()
+	require.NoError(err)
+	fa := fs.FAAddress()
+	tx := Transaction{
+		TimestampSalt: time.Now(),
+		FCTInputs:     []AddressAmount{{Address: fa[:], Amount: 100000}},
+		FCTOutputs:    []AddressAmount{{Address: fa[:], Amount: 90000}},
+		Signatures:    make([]RCDSignature, 1),
+	}
+	txData, err := tx.Sign(fs)
+	require.NoError(err)
+
+	var commits []Bytes
+	var revealed []string
+	var submitted int
+	failCommit := true
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+			Params struct {
+				Hash   EntryHash `json:"hash"`
+				Entry  Bytes     `json:"entry"`
+				Commit Bytes     `json:"message"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		res := jsonrpc2.Response{Result: struct{}{}, ID: jReq.ID}
+		switch jReq.Method {
+		case "commit-entry":
+			commits = append(commits, jReq.Params.Commit)
+			if failCommit {
+				res.Result = nil
+				res.Error = jsonrpc2.Error{Code: -32603,
+					Message: "Internal error"}
+			} else if len(commits) > 1 {
+				res.Result = nil
+				res.Error = jsonrpc2.Error{Code: -32011,
+					Message: "Repeated Commit"}
+			}
+		case "reveal-entry":
+			var e Entry
+			require.NoError(e.UnmarshalBinary(jReq.Params.Entry))
+			revealed = append(revealed, string(e.Content))
+		case "ack":
+			status := "Unknown"
+			if jReq.Params.Hash == bHash {
+				status = "DBlockConfirmed"
+			}
+			res.Result = map[string]interface{}{
+				"entrydata": map[string]string{"status": status}}
+		case "transaction":
+			res.Result = nil
+			res.Error = jsonrpc2.Error{Code: -32008,
+				Message: "Object not found"}
+		case "factoid-submit":
+			submitted++
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(res)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	es, err := GenerateEsAddress()
+	require.NoError(err)
+	outbox := new(MemoryOutbox)
+	w := EntryWriter{EsAddress: es, Outbox: outbox}
+	require.NoError(w.Queue(a, PriorityNormal, "a"))
+	require.NoError(w.Queue(b, PriorityNormal, "b"))
+	require.NoError(w.QueueTransaction(txData, PriorityNormal, "tx"))
+	assert.Equal(3, outbox.Len())
+
+	// The commit may or may not have been accepted before the writer is
+	// lost.
+	_, err = w.Flush(context.Background(), c)
+	require.Error(err)
+	require.Len(commits, 1)
+
+	// After a restart, the same commit is resubmitted, b has already been
+	// published, and the Transaction is not yet known to factomd.
+	failCommit = false
+	w = EntryWriter{EsAddress: es, Outbox: outbox}
+	n, err := w.LoadOutbox()
+	require.NoError(err)
+	assert.Equal(3, n)
+	n, err = w.Flush(context.Background(), c)
+	require.NoError(err)
+	assert.Equal(3, n)
+	assert.Equal(0, outbox.Len())
+
+	require.Len(commits, 2)
+	assert.Equal(commits[0], commits[1])
+	assert.Equal([]string{"a"}, revealed)
+	assert.Equal(1, submitted)
+	assert.Equal(map[string]uint64{"a": 1}, w.Spent())
+}
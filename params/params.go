@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package params is a registry of the protocol constants and activation
+// heights of the known Factom networks, so that parsers and validators can
+// apply the rules that were in effect at any height of a chain's history.
+//
+// The values are those of factomd v6.7.0. Networks other than mainnet,
+// testnet, and localnet use the defaults factomd uses for custom networks,
+// which never activate any of the Activations.
+package params
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Names of the Activations.
+const (
+	// ECBalanceEnforced rejects Entry and Chain commits that exceed the EC
+	// balance of the paying address. Before it activated on mainnet, EC
+	// balances could become negative.
+	ECBalanceEnforced = "ECBalanceEnforced"
+
+	// TestnetCoinbasePeriod increases the CoinbaseDeclaration to
+	// TestnetCoinbaseDeclaration.
+	TestnetCoinbasePeriod = "TestNetCoinBasePeriod"
+
+	// AuthorityMaxDelta ensures that fewer than half of the federated
+	// servers are replaced in a single election.
+	AuthorityMaxDelta = "AuthorityMaxDelta"
+)
+
+// TestnetCoinbaseDeclaration is the CoinbaseDeclaration once
+// TestnetCoinbasePeriod is active.
+const TestnetCoinbaseDeclaration = 140
+
+// Never is the Height of an Activation that never activates.
+const Never = math.MaxUint32
+
+// Activation is a protocol change that takes effect at Height.
+type Activation struct {
+	Name   string
+	Height uint32
+}
+
+// Params are the protocol constants and Activations of a network.
+type Params struct {
+	ID factom.NetworkID
+
+	// CoinbaseActivation is the height above which coinbase Transactions
+	// may have outputs.
+	CoinbaseActivation uint32
+
+	// CoinbasePayoutFrequency is the number of DBlocks between coinbase
+	// payouts.
+	CoinbasePayoutFrequency uint32
+
+	// CoinbaseDeclaration is the number of DBlocks between the ABlock
+	// with a CoinbaseDescriptor and the FBlock with the corresponding
+	// coinbase Transaction, unless TestnetCoinbasePeriod is active.
+	CoinbaseDeclaration uint32
+
+	// CoinbasePayoutAmount is the maximum number of factoshis paid to
+	// each server per payout.
+	CoinbasePayoutAmount uint64
+
+	// Activations are the Activations of the network, sorted by Height.
+	Activations []Activation
+
+	// GrantHeights are the heights of the ABlocks with a
+	// CoinbaseDescriptor that includes hard coded grants, sorted
+	// ascending.
+	GrantHeights []uint32
+}
+
+// Mainnet returns the Params of the Factom mainnet.
+func Mainnet() Params {
+	return Params{
+		ID:                      factom.MainnetID(),
+		CoinbaseActivation:      140200,
+		CoinbasePayoutFrequency: 25,
+		CoinbaseDeclaration:     1000,
+		CoinbasePayoutAmount:    6.4e8,
+		Activations: []Activation{
+			{Name: ECBalanceEnforced, Height: 97887},
+			{Name: AuthorityMaxDelta, Height: 222874},
+			{Name: TestnetCoinbasePeriod, Height: Never},
+		},
+		GrantHeights: []uint32{152751, 155501, 158001, 168576, 169901,
+			181001, 194126, 207326, 221126, 233201, 246851},
+	}
+}
+
+// Testnet returns the Params of the Factom community testnet, which factomd
+// runs as the custom network "fct_community_test".
+func Testnet() Params {
+	p := Custom(factom.TestnetID())
+	p.Activations = []Activation{
+		{Name: ECBalanceEnforced, Height: 0},
+		{Name: TestnetCoinbasePeriod, Height: 45335},
+		{Name: AuthorityMaxDelta, Height: 109387},
+	}
+	return p
+}
+
+// Localnet returns the Params of a LOCAL factomd network.
+func Localnet() Params {
+	p := Custom(factom.LocalnetID())
+	p.Activations = []Activation{
+		{Name: ECBalanceEnforced, Height: 0},
+		{Name: TestnetCoinbasePeriod, Height: 25},
+		{Name: AuthorityMaxDelta, Height: 25},
+	}
+	p.GrantHeights = []uint32{11, 41, 51}
+	return p
+}
+
+// Custom returns the Params that factomd uses for a custom network with the
+// given id.
+func Custom(id factom.NetworkID) Params {
+	return Params{
+		ID:                      id,
+		CoinbaseActivation:      0,
+		CoinbasePayoutFrequency: 5,
+		CoinbaseDeclaration:     10,
+		CoinbasePayoutAmount:    6.4e8,
+		Activations: []Activation{
+			{Name: ECBalanceEnforced, Height: 0},
+			{Name: TestnetCoinbasePeriod, Height: Never},
+			{Name: AuthorityMaxDelta, Height: Never},
+		},
+	}
+}
+
+// For returns the Params for the network with the given id.
+func For(id factom.NetworkID) Params {
+	switch id {
+	case factom.MainnetID():
+		return Mainnet()
+	case factom.TestnetID():
+		return Testnet()
+	case factom.LocalnetID():
+		return Localnet()
+	}
+	return Custom(id)
+}
+
+// IsActive returns true if the Activation with the given name is active at
+// height. Unknown Activations are never active.
+func (p Params) IsActive(name string, height uint32) bool {
+	for _, a := range p.Activations {
+		if a.Name == name {
+			return a.Height != Never && height >= a.Height
+		}
+	}
+	return false
+}
+
+// CoinbaseDeclarationAt returns the CoinbaseDeclaration in effect at height.
+func (p Params) CoinbaseDeclarationAt(height uint32) uint32 {
+	if p.IsActive(TestnetCoinbasePeriod, height) {
+		return TestnetCoinbaseDeclaration
+	}
+	return p.CoinbaseDeclaration
+}
+
+// CoinbaseDescriptorHeight returns the height of the ABlock with the
+// CoinbaseDescriptor that the coinbase Transaction at height pays out. False
+// is returned if the coinbase Transaction at height has no outputs.
+func (p Params) CoinbaseDescriptorHeight(height uint32) (uint32, bool) {
+	declaration := p.CoinbaseDeclarationAt(height)
+	mod := height % p.CoinbasePayoutFrequency
+	if height <= p.CoinbaseActivation || height == 0 ||
+		(mod != 0 && mod != 1) ||
+		height <= declaration+p.CoinbasePayoutFrequency {
+		return 0, false
+	}
+	return height - declaration, true
+}
+
+// IsGrantHeight returns true if the ABlock at height has a CoinbaseDescriptor
+// with hard coded grants.
+func (p Params) IsGrantHeight(height uint32) bool {
+	i := sort.Search(len(p.GrantHeights), func(i int) bool {
+		return p.GrantHeights[i] >= height
+	})
+	return i < len(p.GrantHeights) && p.GrantHeights[i] == height
+}
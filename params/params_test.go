@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package params_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor(t *testing.T) {
+	for _, p := range []Params{Mainnet(), Testnet(), Localnet(),
+		Custom(factom.NetworkID{1, 2, 3, 4})} {
+		p := p
+		t.Run(p.ID.String(), func(t *testing.T) {
+			assert.Equal(t, p, For(p.ID))
+			assert.True(t, sort.SliceIsSorted(p.Activations,
+				func(i, j int) bool {
+					return p.Activations[i].Height <
+						p.Activations[j].Height
+				}))
+			assert.True(t, sort.SliceIsSorted(p.GrantHeights,
+				func(i, j int) bool {
+					return p.GrantHeights[i] < p.GrantHeights[j]
+				}))
+			for _, height := range p.GrantHeights {
+				assert.Equal(t, uint32(1),
+					height%p.CoinbasePayoutFrequency)
+			}
+		})
+	}
+}
+
+func TestIsActive(t *testing.T) {
+	main := Mainnet()
+	assert.False(t, main.IsActive(ECBalanceEnforced, 97886))
+	assert.True(t, main.IsActive(ECBalanceEnforced, 97887))
+	assert.False(t, main.IsActive(TestnetCoinbasePeriod, Never))
+	assert.False(t, main.IsActive("unknown", 0))
+
+	custom := Custom(factom.NetworkID{1, 2, 3, 4})
+	assert.True(t, custom.IsActive(ECBalanceEnforced, 0))
+	assert.False(t, custom.IsActive(AuthorityMaxDelta, 1e6))
+}
+
+func TestCoinbaseDescriptorHeight(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Params Params
+		Height uint32
+		Desc   uint32
+		Payout bool
+	}{
+		{"mainnet/before activation", Mainnet(), 140200, 0, false},
+		{"mainnet/payout", Mainnet(), 140225, 139225, true},
+		{"mainnet/payout+1", Mainnet(), 140226, 139226, true},
+		{"mainnet/no payout", Mainnet(), 140227, 0, false},
+		{"testnet/before period", Testnet(), 45330, 45320, true},
+		{"testnet/after period", Testnet(), 45340, 45200, true},
+		{"testnet/no declaration", Testnet(), 15, 0, false},
+	} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			desc, payout := test.Params.CoinbaseDescriptorHeight(
+				test.Height)
+			assert.Equal(t, test.Payout, payout)
+			assert.Equal(t, test.Desc, desc)
+		})
+	}
+}
+
+func TestIsGrantHeight(t *testing.T) {
+	main := Mainnet()
+	assert.True(t, main.IsGrantHeight(152751))
+	assert.True(t, main.IsGrantHeight(246851))
+	assert.False(t, main.IsGrantHeight(152752))
+	assert.False(t, main.IsGrantHeight(300000))
+	assert.False(t, Testnet().IsGrantHeight(11))
+}
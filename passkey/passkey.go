@@ -0,0 +1,200 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package passkey encrypts a single factom.FsAddress or factom.EsAddress under
+// a passphrase, in a compact base58check string that is suitable for printing
+// or sending as an individual backup.
+//
+// The format closely follows BIP38 for non-EC-multiplied keys. An scrypt key
+// is derived from the passphrase, salted with a hash of the public address,
+// and the private key is XORed with its first half and then encrypted with
+// AES-256 under its second half. The public address hash allows Decrypt to
+// detect an incorrect passphrase.
+//
+// Encrypted Fs keys start with "Fp" and encrypted Es keys start with "Ep". The
+// binary layout, before base58check encoding, is
+//
+//	prefix (2) | scrypt log2(N) (1) | address hash (4) | encrypted key (32)
+//
+// The passphrase is used as is. Callers that accept non-ASCII passphrases
+// should normalize them first, e.g. to Unicode NFC, so that the same
+// passphrase typed on a different system decrypts the key.
+package passkey
+
+import (
+	"crypto/aes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/base58"
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+var (
+	fpPrefix = [...]byte{0xc9, 0x2c}
+	epPrefix = [...]byte{0xbb, 0x98}
+)
+
+const (
+	// EncodedLen is the length of all encrypted keys.
+	EncodedLen = 59
+
+	// scrypt parameters. Only log2(N) is encoded, so that the cost may be
+	// increased in the future. The r and p parameters are those of BIP38.
+	//
+	// The encoded cost is not trusted beyond maxLogN, which is the cost
+	// that EncryptFs and EncryptEs use. scrypt requires 128*r*N bytes of
+	// memory, so this limits decryption to 16 MiB.
+	scryptR   = 8
+	scryptP   = 8
+	maxLogN   = 14
+	bodyLen   = 1 + 4 + 32
+	hashLen   = 4
+	secretLen = 32
+)
+
+// logN is log2 of the scrypt N parameter used by EncryptFs and EncryptEs. It
+// is a variable so that it may be lowered by tests.
+var logN uint8 = maxLogN
+
+// ErrorPassphrase is returned by DecryptFs and DecryptEs when the passphrase
+// does not decrypt the key.
+type ErrorPassphrase struct{}
+
+// Error implements error.
+func (ErrorPassphrase) Error() string {
+	return "invalid passphrase"
+}
+
+// EncryptFs returns fs encrypted under passphrase.
+func EncryptFs(fs factom.FsAddress, passphrase string) string {
+	return encrypt(fs, fs.FAAddress().String(), fpPrefix, passphrase)
+}
+
+// EncryptEs returns es encrypted under passphrase.
+func EncryptEs(es factom.EsAddress, passphrase string) string {
+	return encrypt(es, es.ECAddress().String(), epPrefix, passphrase)
+}
+
+// DecryptFs decrypts an encrypted key returned by EncryptFs. If passphrase is
+// incorrect, ErrorPassphrase is returned.
+func DecryptFs(encrypted, passphrase string) (factom.FsAddress, error) {
+	secret, hash, err := decrypt(encrypted, fpPrefix, passphrase)
+	if err != nil {
+		return factom.FsAddress{}, err
+	}
+	fs := factom.FsAddress(secret)
+	if addressHash(fs.FAAddress().String()) != hash {
+		return factom.FsAddress{}, ErrorPassphrase{}
+	}
+	return fs, nil
+}
+
+// DecryptEs decrypts an encrypted key returned by EncryptEs. If passphrase is
+// incorrect, ErrorPassphrase is returned.
+func DecryptEs(encrypted, passphrase string) (factom.EsAddress, error) {
+	secret, hash, err := decrypt(encrypted, epPrefix, passphrase)
+	if err != nil {
+		return factom.EsAddress{}, err
+	}
+	es := factom.EsAddress(secret)
+	if addressHash(es.ECAddress().String()) != hash {
+		return factom.EsAddress{}, ErrorPassphrase{}
+	}
+	return es, nil
+}
+
+func encrypt(secret [secretLen]byte, adrStr string, prefix [2]byte,
+	passphrase string) string {
+	hash := addressHash(adrStr)
+
+	body := make([]byte, 0, bodyLen)
+	body = append(body, logN)
+	body = append(body, hash[:]...)
+
+	half1, half2 := deriveKey(passphrase, hash, logN)
+	for i := range secret {
+		secret[i] ^= half1[i]
+	}
+	block, _ := aes.NewCipher(half2)
+	enc := make([]byte, secretLen)
+	for i := 0; i < secretLen; i += aes.BlockSize {
+		block.Encrypt(enc[i:], secret[i:])
+	}
+	body = append(body, enc...)
+
+	return base58.CheckEncode(body, prefix[:]...)
+}
+
+func decrypt(encrypted string, prefix [2]byte,
+	passphrase string) (secret [secretLen]byte, hash [hashLen]byte, err error) {
+	if len(encrypted) != EncodedLen {
+		err = fmt.Errorf("invalid length")
+		return
+	}
+	body, version, err := base58.CheckDecode(encrypted, len(prefix))
+	if err != nil {
+		return
+	}
+	if len(body) != bodyLen {
+		err = fmt.Errorf("invalid length")
+		return
+	}
+	if string(version) != string(prefix[:]) {
+		err = fmt.Errorf("invalid prefix")
+		return
+	}
+	n := body[0]
+	if n == 0 || n > maxLogN {
+		err = fmt.Errorf("unsupported scrypt cost: %v", n)
+		return
+	}
+	copy(hash[:], body[1:])
+
+	half1, half2 := deriveKey(passphrase, hash, n)
+	block, _ := aes.NewCipher(half2)
+	enc := body[1+hashLen:]
+	for i := 0; i < secretLen; i += aes.BlockSize {
+		block.Decrypt(secret[i:], enc[i:])
+	}
+	for i := range secret {
+		secret[i] ^= half1[i]
+	}
+	return
+}
+
+// deriveKey returns the two halves of the scrypt key for passphrase and
+// hash.
+func deriveKey(passphrase string, hash [hashLen]byte,
+	logN uint8) (half1, half2 []byte) {
+	key := scrypt([]byte(passphrase), hash[:], 1<<logN,
+		scryptR, scryptP, 2*secretLen)
+	return key[:secretLen], key[secretLen:]
+}
+
+// addressHash returns the first 4 bytes of the double sha256 hash of adrStr.
+func addressHash(adrStr string) (hash [hashLen]byte) {
+	h := sha256.Sum256([]byte(adrStr))
+	h = sha256.Sum256(h[:])
+	copy(hash[:], h[:])
+	return
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package passkey
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/base58"
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrypt(t *testing.T) {
+	// Test vectors from RFC 7914.
+	for _, test := range []struct {
+		Password, Salt string
+		N, R, P        int
+		Exp            string
+	}{{
+		N: 16, R: 1, P: 1,
+		Exp: "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442" +
+			"fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906",
+	}, {
+		Password: "password", Salt: "NaCl",
+		N: 1024, R: 8, P: 16,
+		Exp: "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b373162" +
+			"2eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640",
+	}} {
+		key := scrypt([]byte(test.Password), []byte(test.Salt),
+			test.N, test.R, test.P, 64)
+		assert.Equal(t, test.Exp, hex.EncodeToString(key))
+	}
+}
+
+func TestPasskey(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	defer func(n uint8) { logN = n }(logN)
+	logN = 4
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+
+	encFs := EncryptFs(fs, "correct horse")
+	assert.Len(encFs, EncodedLen)
+	assert.True(strings.HasPrefix(encFs, "Fp"), encFs)
+	encEs := EncryptEs(es, "correct horse")
+	assert.Len(encEs, EncodedLen)
+	assert.True(strings.HasPrefix(encEs, "Ep"), encEs)
+
+	decFs, err := DecryptFs(encFs, "correct horse")
+	require.NoError(err)
+	assert.Equal(fs, decFs)
+	decEs, err := DecryptEs(encEs, "correct horse")
+	require.NoError(err)
+	assert.Equal(es, decEs)
+
+	_, err = DecryptFs(encFs, "wrong horse")
+	assert.Equal(ErrorPassphrase{}, err)
+	_, err = DecryptEs(encEs, "wrong horse")
+	assert.Equal(ErrorPassphrase{}, err)
+
+	_, err = DecryptFs(encEs, "correct horse")
+	assert.EqualError(err, "invalid prefix")
+	_, err = DecryptFs(encFs[:EncodedLen-1], "correct horse")
+	assert.EqualError(err, "invalid length")
+	corrupt := []byte(encFs)
+	corrupt[10] = corrupt[11]
+	if corrupt[10] == encFs[10] {
+		corrupt[10] = 'z'
+	}
+	_, err = DecryptFs(string(corrupt), "correct horse")
+	assert.Error(err)
+
+	body := make([]byte, bodyLen)
+	body[0] = maxLogN + 1
+	_, err = DecryptFs(base58.CheckEncode(body, fpPrefix[:]...), "")
+	assert.EqualError(err, "unsupported scrypt cost: 15")
+}
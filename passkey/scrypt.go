@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package passkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// scrypt derives a keyLen byte key from password and salt as specified by RFC
+// 7914. N must be a power of two greater than 1.
+func scrypt(password, salt []byte, N, r, p, keyLen int) []byte {
+	blockLen := 128 * r
+	b := pbkdf2SHA256(password, salt, 1, p*blockLen)
+
+	x := make([]uint32, 32*r)
+	y := make([]uint32, 32*r)
+	v := make([]uint32, 32*r*N)
+	for i := 0; i < p; i++ {
+		smix(b[i*blockLen:(i+1)*blockLen], r, N, x, y, v)
+	}
+
+	return pbkdf2SHA256(password, b, 1, keyLen)
+}
+
+// smix is the scryptROMix function of RFC 7914, operating on the 128*r bytes
+// of b in place.
+func smix(b []byte, r, N int, x, y, v []uint32) {
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	for i := 0; i < N; i++ {
+		copy(v[i*len(x):], x)
+		blockMix(x, y, r)
+	}
+	for i := 0; i < N; i++ {
+		j := int(x[(2*r-1)*16] & uint32(N-1))
+		for k, w := range v[j*len(x) : (j+1)*len(x)] {
+			x[k] ^= w
+		}
+		blockMix(x, y, r)
+	}
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[i*4:], w)
+	}
+}
+
+// blockMix is the scryptBlockMix function of RFC 7914, operating on the 2*r
+// 64 byte blocks of b in place, using y as scratch space.
+func blockMix(b, y []uint32, r int) {
+	var t [16]uint32
+	copy(t[:], b[(2*r-1)*16:])
+	for i := 0; i < 2*r; i++ {
+		for k := range t {
+			t[k] ^= b[i*16+k]
+		}
+		salsa208(&t)
+		// Even blocks go in the first half and odd blocks in the
+		// second half.
+		copy(y[(i/2+(i%2)*r)*16:], t[:])
+	}
+	copy(b, y)
+}
+
+// salsa208 applies the Salsa20/8 core to b.
+func salsa208(b *[16]uint32) {
+	x := *b
+	for i := 0; i < 8; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range b {
+		b[i] += x[i]
+	}
+}
+
+// pbkdf2SHA256 derives a keyLen byte key from password and salt as specified
+// by RFC 8018 using HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen+sha256.Size)
+	var u, t [sha256.Size]byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		var blockIdx [4]byte
+		binary.BigEndian.PutUint32(blockIdx[:], block)
+		prf.Write(blockIdx[:])
+		prf.Sum(u[:0])
+		t = u
+		for n := 1; n < iter; n++ {
+			prf.Reset()
+			prf.Write(u[:])
+			prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t[:]...)
+	}
+	return key[:keyLen]
+}
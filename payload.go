@@ -23,7 +23,9 @@
 package factom
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"database/sql/driver"
 	"fmt"
 
 	"github.com/Factom-Asset-Tokens/base58"
@@ -35,7 +37,7 @@ type payload [sha256.Size]byte
 // StringWithPrefix encodes payload as a base58check string with the given
 // prefix.
 func (p payload) StringWithPrefix(prefix []byte) string {
-	return base58.CheckEncode(p[:], prefix...)
+	return EncodePrefixed(prefix, p[:])
 }
 
 // MarshalTextWithPrefix encodes payload as a base58check string with the given
@@ -47,16 +49,30 @@ func (p payload) MarshalTextWithPrefix(prefix []byte) ([]byte, error) {
 // SetWithPrefix attempts to parse adrStr into adr enforcing that adrStr starts
 // with prefix, if not empty.
 func (p *payload) SetWithPrefix(str, prefix string) error {
+	return p.SetWithPrefixBytes(str, prefix, nil)
+}
+
+// SetWithPrefixBytes is like SetWithPrefix but also enforces that the decoded
+// prefix bytes equal prefixBytes, if not nil.
+func (p *payload) SetWithPrefixBytes(str, prefix string, prefixBytes []byte) error {
 	if len(str) != 50+len(prefix) {
 		return fmt.Errorf("invalid length")
 	}
 	if len(prefix) > 0 && str[:len(prefix)] != prefix {
 		return fmt.Errorf("invalid prefix")
 	}
-	b, _, err := base58.CheckDecode(str, len(prefix))
+	b, version, err := base58.CheckDecode(str, len(prefix))
 	if err != nil {
 		return err
 	}
+	// A string of the correct length may still decode to more or fewer
+	// bytes, which must not be truncated or padded into p.
+	if len(b) != len(p) {
+		return fmt.Errorf("invalid payload length")
+	}
+	if prefixBytes != nil && !bytes.Equal(version, prefixBytes) {
+		return fmt.Errorf("invalid prefix")
+	}
 	copy(p[:], b)
 	return nil
 }
@@ -66,3 +82,27 @@ func (p *payload) SetWithPrefix(str, prefix string) error {
 func (p *payload) UnmarshalTextWithPrefix(text []byte, prefix string) error {
 	return p.SetWithPrefix(string(text), prefix)
 }
+
+// ValueWithPrefix returns the human readable string with the given
+// prefixBytes. This implements driver.Valuer for the public Address and IDKey
+// types. The private types do not implement driver.Valuer, so that secret
+// keys are never written to a database in the clear by accident.
+func (p payload) ValueWithPrefix(prefixBytes []byte) (driver.Value, error) {
+	return p.StringWithPrefix(prefixBytes), nil
+}
+
+// ScanWithPrefix scans the human readable string v, as returned by
+// ValueWithPrefix, into p. Drivers may return text columns as either string
+// or []byte, so both are accepted, and both are fully validated by
+// SetWithPrefixBytes.
+func (p *payload) ScanWithPrefix(v interface{},
+	prefix string, prefixBytes []byte) error {
+	switch v := v.(type) {
+	case []byte:
+		return p.SetWithPrefixBytes(string(v), prefix, prefixBytes)
+	case string:
+		return p.SetWithPrefixBytes(v, prefix, prefixBytes)
+	default:
+		return fmt.Errorf("invalid type: %T", v)
+	}
+}
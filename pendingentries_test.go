@@ -41,7 +41,7 @@ var searchID = NewBytes32(
 // verified.
 func TestPendingEntries(t *testing.T) {
 	var pe PendingEntries
-	c := NewClient()
+	c := IntegrationClient(t)
 	//c.Factomd.DebugRequest = true
 	assert := assert.New(t)
 	require := require.New(t)
@@ -0,0 +1,274 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package pkcs11 signs with ed25519 keys held by a PKCS#11 hardware security
+// module, so that keys never need to be exported from the HSM.
+//
+// A Signer locates a key by its CKA_LABEL and signs with the CKM_EDDSA
+// mechanism through a pool of sessions. It implements factom.RCDSigner, for
+// Factoid Transactions, and factom.CommitSigner, for Entry commits.
+//
+// No PKCS#11 binding is a dependency of this package. Instead a Token opens
+// Sessions, which are adapted from the binding of choice, such as
+// github.com/miekg/pkcs11. An adapter's FindKey searches for the object with
+// CKA_CLASS CKO_PRIVATE_KEY, CKA_KEY_TYPE CKK_EC_EDWARDS, and the CKA_LABEL,
+// and reads the public key from the CKA_EC_POINT of the matching
+// CKO_PUBLIC_KEY object, which ParseECPoint decodes. Its Sign calls C_SignInit
+// with CKM_EDDSA and no parameters, for pure ed25519, and then C_Sign.
+package pkcs11
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// ObjectHandle is a PKCS#11 object handle.
+type ObjectHandle uint
+
+// Token opens Sessions on an HSM token. Sessions must already be logged in as
+// the user that may use the key.
+type Token interface {
+	OpenSession() (Session, error)
+}
+
+// TokenFunc adapts a function to a Token.
+type TokenFunc func() (Session, error)
+
+// OpenSession calls f().
+func (f TokenFunc) OpenSession() (Session, error) {
+	return f()
+}
+
+// Session is a PKCS#11 session. A Session is used by only one goroutine at a
+// time.
+type Session interface {
+	// FindKey returns the handle of the ed25519 private key with the
+	// CKA_LABEL label, and its public key.
+	FindKey(label string) (ObjectHandle, ed25519.PublicKey, error)
+
+	// Sign returns the CKM_EDDSA signature of msg by the key.
+	Sign(key ObjectHandle, msg []byte) ([]byte, error)
+
+	// Close closes the session.
+	Close() error
+}
+
+// ParseECPoint decodes the CKA_EC_POINT attribute of an ed25519 public key.
+// Both the DER encoded OCTET STRING required by PKCS#11 and the raw 32 bytes
+// returned by some HSMs are accepted.
+func ParseECPoint(data []byte) (ed25519.PublicKey, error) {
+	if len(data) != ed25519.PublicKeySize {
+		var point []byte
+		rest, err := asn1.Unmarshal(data, &point)
+		if err != nil {
+			return nil, fmt.Errorf("CKA_EC_POINT: %w", err)
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("CKA_EC_POINT: trailing data")
+		}
+		data = point
+	}
+	if len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("CKA_EC_POINT: invalid size: %v", len(data))
+	}
+	return ed25519.PublicKey(append([]byte(nil), data...)), nil
+}
+
+// session is a pooled Session and the handle of the key within it.
+type session struct {
+	Session
+	key ObjectHandle
+}
+
+// Signer signs with a key held by an HSM. It implements factom.RCDSigner, for
+// Factoid Transactions, and factom.CommitSigner, for Entry commits.
+//
+// Sessions are opened as needed, up to the pool size, and reused. A Session
+// that fails to sign is closed rather than reused, in case it has become
+// invalid, e.g. after the HSM was restarted.
+type Signer struct {
+	token Token
+	label string
+	key   ed25519.PublicKey
+
+	sem  chan struct{}
+	idle chan session
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var (
+	_ factom.RCDSigner    = &Signer{}
+	_ factom.CommitSigner = &Signer{}
+)
+
+// NewSigner returns a Signer for the key with label on token, using at most
+// size concurrent Sessions. If size is less than 1, it is treated as 1. A
+// Session is opened to find the key, and kept in the pool.
+func NewSigner(token Token, label string, size int) (*Signer, error) {
+	if size < 1 {
+		size = 1
+	}
+	s := &Signer{
+		token: token,
+		label: label,
+		sem:   make(chan struct{}, size),
+		idle:  make(chan session, size),
+	}
+	sess, key, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	s.key = key
+	s.idle <- sess
+	return s, nil
+}
+
+func (s *Signer) open() (session, ed25519.PublicKey, error) {
+	sess, err := s.token.OpenSession()
+	if err != nil {
+		return session{}, nil, fmt.Errorf("open session: %w", err)
+	}
+	handle, key, err := sess.FindKey(s.label)
+	if err != nil {
+		sess.Close()
+		return session{}, nil, fmt.Errorf("find key %q: %w", s.label, err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		sess.Close()
+		return session{}, nil, fmt.Errorf("find key %q: "+
+			"invalid public key size: %v", s.label, len(key))
+	}
+	return session{Session: sess, key: handle}, key, nil
+}
+
+// Label returns the CKA_LABEL of the key.
+func (s *Signer) Label() string {
+	return s.label
+}
+
+// PublicKey returns the ed25519.PublicKey of the key.
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key
+}
+
+// RCD returns the RCD for the key.
+func (s *Signer) RCD() factom.RCD {
+	return append(factom.RCD{byte(factom.RCDType01)}, s.key...)
+}
+
+// FAAddress returns the FAAddress for the key.
+func (s *Signer) FAAddress() factom.FAAddress {
+	return s.RCD().FAAddress()
+}
+
+// ECAddress returns the ECAddress for the key.
+func (s *Signer) ECAddress() factom.ECAddress {
+	var ec factom.ECAddress
+	copy(ec[:], s.key)
+	return ec
+}
+
+// Sign msg using TrySign with context.Background(). If signing fails, nil is
+// returned.
+func (s *Signer) Sign(msg []byte) []byte {
+	sig, _ := s.TrySign(context.Background(), msg)
+	return sig
+}
+
+// TrySign signs msg with a pooled Session, blocking until one is available or
+// ctx is done, and verifies the signature against the public key.
+func (s *Signer) TrySign(ctx context.Context, msg []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.sem }()
+
+	var sess session
+	select {
+	case sess = <-s.idle:
+	default:
+		var key ed25519.PublicKey
+		var err error
+		if sess, key, err = s.open(); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(key, s.key) {
+			sess.Close()
+			return nil, fmt.Errorf("find key %q: public key changed",
+				s.label)
+		}
+	}
+
+	sig, err := sess.Sign(sess.key, msg)
+	if err == nil && !ed25519.Verify(s.key, msg, sig) {
+		err = fmt.Errorf("invalid signature")
+	}
+	if err != nil {
+		sess.Close()
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	s.release(sess)
+	return sig, nil
+}
+
+// release returns sess to the pool, unless s is closed.
+func (s *Signer) release(sess session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		sess.Close()
+		return
+	}
+	s.idle <- sess
+}
+
+// Close closes all idle Sessions. Sessions in use are closed once they are
+// released. Signing after Close opens new Sessions, which are not pooled.
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var err error
+	for {
+		select {
+		case sess := <-s.idle:
+			if cErr := sess.Close(); err == nil {
+				err = cErr
+			}
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package pkcs11_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hsm is an in memory Token.
+type hsm struct {
+	mu     sync.Mutex
+	keys   map[string]ed25519.PrivateKey
+	open   int
+	opened int
+	fail   bool
+}
+
+type hsmSession struct{ *hsm }
+
+func (h *hsm) OpenSession() (Session, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.open++
+	h.opened++
+	return hsmSession{h}, nil
+}
+
+func (s hsmSession) FindKey(label string) (ObjectHandle, ed25519.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[label]
+	if !ok {
+		return 0, nil, fmt.Errorf("not found")
+	}
+	point, _ := asn1.Marshal([]byte(key.Public().(ed25519.PublicKey)))
+	pub, err := ParseECPoint(point)
+	return ObjectHandle(len(label)), pub, err
+}
+
+func (s hsmSession) Sign(_ ObjectHandle, msg []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return nil, fmt.Errorf("CKR_SESSION_HANDLE_INVALID")
+	}
+	for _, key := range s.keys {
+		return ed25519.Sign(key, msg), nil
+	}
+	return nil, nil
+}
+
+func (s hsmSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open--
+	return nil
+}
+
+func TestSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	h := &hsm{keys: map[string]ed25519.PrivateKey{"fct": fs.PrivateKey()}}
+
+	_, err = NewSigner(h, "missing", 2)
+	assert.EqualError(err, `find key "missing": not found`)
+	assert.Equal(0, h.open)
+
+	h.opened = 0
+	s, err := NewSigner(h, "fct", 2)
+	require.NoError(err)
+	assert.Equal("fct", s.Label())
+	assert.Equal(fs.RCD(), s.RCD())
+	assert.Equal(fs.FAAddress(), s.FAAddress())
+
+	msg := []byte("ledger")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(fs.Sign(msg), s.Sign(msg))
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(h.opened, 2)
+	require.NoError(s.Close())
+	assert.Equal(0, h.open)
+
+	// Failed sessions are closed and not reused.
+	s, err = NewSigner(h, "fct", 1)
+	require.NoError(err)
+	h.fail = true
+	_, err = s.TrySign(context.Background(), msg)
+	assert.EqualError(err, "sign: CKR_SESSION_HANDLE_INVALID")
+	assert.Equal(0, h.open)
+	h.fail = false
+	h.opened = 0
+	assert.Equal(fs.Sign(msg), s.Sign(msg))
+	assert.Equal(1, h.opened)
+	require.NoError(s.Close())
+	assert.Equal(0, h.open)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s, err = NewSigner(h, "fct", 0)
+	require.NoError(err)
+	_, err = s.TrySign(ctx, msg)
+	assert.Equal(context.Canceled, err)
+}
+
+func TestParseECPoint(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	pub := fs.PublicKey()
+
+	key, err := ParseECPoint(pub)
+	require.NoError(err)
+	assert.Equal(pub, key)
+
+	point, err := asn1.Marshal([]byte(pub))
+	require.NoError(err)
+	key, err = ParseECPoint(point)
+	require.NoError(err)
+	assert.Equal(pub, key)
+
+	_, err = ParseECPoint(append(point, 0))
+	assert.EqualError(err, "CKA_EC_POINT: trailing data")
+	point, err = asn1.Marshal(pub[:31])
+	require.NoError(err)
+	_, err = ParseECPoint(point)
+	assert.EqualError(err, "CKA_EC_POINT: invalid size: 31")
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// Scanning a chain allocates an Entry for every Entry Hash in every EBlock, and
+// a buffer for the data of every Entry, all of which are usually discarded
+// soon after. High throughput scanners may call EBlock.Recycle or
+// Entry.Recycle once they are done with them, so that EBlock.UnmarshalBinary
+// and Entry.Get reuse the memory instead of allocating it again.
+var (
+	// entriesPool holds *[]Entry for EBlock.Entries.
+	entriesPool sync.Pool
+
+	// entryDataPool holds *[]byte for the binary data of Entries.
+	entryDataPool sync.Pool
+)
+
+// getEntries returns a slice of n zero Entries, reusing a recycled slice if
+// one with sufficient capacity is available. The ExtIDs of reused Entries are
+// empty, but not nil, so that their capacity may be reused.
+func getEntries(n int) []Entry {
+	if p, ok := entriesPool.Get().(*[]Entry); ok && cap(*p) >= n {
+		return (*p)[:n]
+	}
+	return make([]Entry, n)
+}
+
+func putEntries(entries []Entry) {
+	for i := range entries {
+		entries[i].Recycle()
+	}
+	entries = entries[:cap(entries)]
+	entriesPool.Put(&entries)
+}
+
+// getEntryData returns a buffer of length n, reusing a recycled buffer if one
+// with sufficient capacity is available.
+func getEntryData(n int) []byte {
+	if p, ok := entryDataPool.Get().(*[]byte); ok && cap(*p) >= n {
+		return (*p)[:n]
+	}
+	return make([]byte, n)
+}
+
+func putEntryData(data []byte) {
+	entryDataPool.Put(&data)
+}
+
+// Recycle returns the memory used by e to be reused by subsequent calls to
+// Entry.Get and EBlock.UnmarshalBinary, and resets e to zero. Only the data
+// of an Entry populated by Entry.Get without a Client.Store is reused.
+//
+// Recycle is optional. After calling it, e, and any ExtIDs or Content that
+// were obtained from it, must no longer be used.
+func (e *Entry) Recycle() {
+	if e.pooled {
+		putEntryData(e.marshalBinaryCache)
+	}
+	*e = Entry{ExtIDs: e.ExtIDs[:0]}
+}
+
+// Recycle calls Recycle on all of eb.Entries, and returns the memory used by
+// eb.Entries to be reused by subsequent calls to EBlock.UnmarshalBinary. The
+// eb.Entries are set to nil.
+//
+// Recycle is optional. After calling it, eb.Entries, and any Entry, ExtIDs,
+// or Content that were obtained from them, must no longer be used.
+func (eb *EBlock) Recycle() {
+	if eb.Entries != nil {
+		putEntries(eb.Entries)
+		eb.Entries = nil
+	}
+}
+
+// pooledData is hex encoded JSON data that is decoded into a buffer from the
+// entryDataPool.
+type pooledData []byte
+
+func (d *pooledData) UnmarshalJSON(data []byte) error {
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		hexData := data[1 : len(data)-1]
+		buf := getEntryData(hex.DecodedLen(len(hexData)))
+		if _, err := hex.Decode(buf, hexData); err == nil {
+			*d = buf
+			return nil
+		}
+		putEntryData(buf)
+	}
+	// Fall back to the more lenient Bytes.
+	return json.Unmarshal(data, (*Bytes)(d))
+}
+
+// getEntryRawData is like getRawData for the data of an Entry, but decodes it
+// into a buffer from the entryDataPool, unless c.Store is not nil. It returns
+// true if the data may be recycled, and true if the data came from the
+// c.Store.
+func (c *Client) getEntryRawData(ctx context.Context,
+	hash Bytes32) (_ []byte, pooled, stored bool, _ error) {
+	if c.Store != nil {
+		data, stored, err := c.getRawData(ctx, hash)
+		return data, false, stored, err
+	}
+	params := struct {
+		Hash Bytes32 `json:"hash"`
+	}{Hash: hash}
+	var result struct {
+		Data pooledData `json:"data"`
+	}
+	if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+		return nil, false, false, err
+	}
+	return result.Data, true, false, nil
+}
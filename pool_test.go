@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecycle(t *testing.T) {
+	chainID := Bytes32{1}
+	var eblocks [][]Entry
+	for i := 0; i < 3; i++ {
+		var entries []Entry
+		for j := 0; j < 5; j++ {
+			entries = append(entries, Entry{
+				ExtIDs:  []Bytes{Bytes(fmt.Sprint(i)), Bytes(fmt.Sprint(j))},
+				Content: Bytes(fmt.Sprintf("content %v %v", i, j)),
+			})
+		}
+		eblocks = append(eblocks, entries)
+	}
+	c := newMockChain(t, chainID, eblocks...)
+	ctx := context.Background()
+
+	var eb EBlock
+	eb.ChainID = &chainID
+	require.NoError(t, eb.Get(ctx, c))
+	ebs, err := eb.GetPrevAll(ctx, c)
+	require.NoError(t, err)
+
+	for i := len(ebs) - 1; i >= 0; i-- {
+		eb := EBlock{ChainID: &chainID, KeyMR: ebs[i].KeyMR}
+		require.NoError(t, eb.GetEntries(ctx, c))
+		seq := len(ebs) - 1 - i
+		require.Len(t, eb.Entries, 5)
+		for j, e := range eb.Entries {
+			assert.Equal(t, eblocks[seq][j].ExtIDs, e.ExtIDs)
+			assert.Equal(t, eblocks[seq][j].Content, e.Content)
+			data, err := e.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, *e.Hash, ComputeEntryHash(data))
+		}
+		eb.Recycle()
+		assert.Nil(t, eb.Entries)
+	}
+
+	e := Entry{Hash: ebs[0].Entries[0].Hash}
+	require.NoError(t, e.Get(ctx, c))
+	e.Recycle()
+	assert.Nil(t, e.Hash)
+	assert.Empty(t, e.ExtIDs)
+	assert.Nil(t, e.Content)
+}
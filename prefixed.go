@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/base58"
+)
+
+// ErrorInvalidPrefix is returned by DecodePrefixed when the decoded prefix
+// bytes do not match the expected prefix.
+var ErrorInvalidPrefix = fmt.Errorf("invalid prefix")
+
+// EncodePrefixed encodes data as a base58check string with the given prefix
+// bytes. This is the encoding used by all Address and IDKey types, and may be
+// used to implement other prefixed identifiers.
+//
+// The checksum is the first four bytes of the double sha256 hash of the prefix
+// and data.
+func EncodePrefixed(prefix, data []byte) string {
+	return base58.CheckEncode(data, prefix...)
+}
+
+// DecodePrefixed decodes a base58check string that was encoded by
+// EncodePrefixed with the given prefix bytes, and returns the data without the
+// prefix.
+//
+// This returns base58.ErrInvalidFormat if str is not valid base58 or is too
+// short, base58.ErrChecksum if the checksum does not match, and
+// ErrorInvalidPrefix if str does not start with prefix. The length of the
+// returned data is not checked.
+func DecodePrefixed(str string, prefix []byte) ([]byte, error) {
+	data, version, err := base58.CheckDecode(str, len(prefix))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(version, prefix) {
+		return nil, ErrorInvalidPrefix
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/base58"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixed(t *testing.T) {
+	var fa FAAddress
+	require.NoError(t, fa.Set(FAAddressStr))
+	assert.Equal(t, FAAddressStr, EncodePrefixed(fa.PrefixBytes(), fa[:]))
+
+	var tests = []struct {
+		Name   string
+		Str    string
+		Prefix []byte
+		Data   []byte
+		Error  error
+	}{{
+		Name:   "address",
+		Str:    FAAddressStr,
+		Prefix: fa.PrefixBytes(),
+		Data:   fa[:],
+	}, {
+		Name:   "custom",
+		Str:    EncodePrefixed([]byte{0x01, 0x02, 0x03, 0x04}, []byte("data")),
+		Prefix: []byte{0x01, 0x02, 0x03, 0x04},
+		Data:   []byte("data"),
+	}, {
+		Name: "no prefix",
+		Str:  EncodePrefixed(nil, []byte("data")),
+		Data: []byte("data"),
+	}, {
+		Name:   "invalid prefix",
+		Str:    FAAddressStr,
+		Prefix: ECAddress{}.PrefixBytes(),
+		Error:  ErrorInvalidPrefix,
+	}, {
+		Name:   "invalid checksum",
+		Str:    FAAddressStr[:len(FAAddressStr)-1] + "c",
+		Prefix: fa.PrefixBytes(),
+		Error:  base58.ErrChecksum,
+	}, {
+		Name:   "invalid base58",
+		Str:    "0OIl",
+		Prefix: fa.PrefixBytes(),
+		Error:  base58.ErrInvalidFormat,
+	}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := DecodePrefixed(test.Str, test.Prefix)
+			if test.Error != nil {
+				assert.Equal(t, test.Error, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Data, data)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Prepared holds the fully signed binary messages for an Entry or a Factoid
+// Transaction, so that they may be prepared in one process, such as a secure
+// signing context without network access, and submitted by another with
+// Client.Submit. Prepared may be transferred as JSON.
+//
+// Use PrepareEntry or PrepareTransaction to create a Prepared.
+type Prepared struct {
+	// Commit and Reveal are the commit and Entry data of a prepared Entry
+	// or new Chain.
+	Commit Bytes `json:"commit,omitempty"`
+	Reveal Bytes `json:"reveal,omitempty"`
+
+	// Transaction is the signed binary data of a prepared Factoid
+	// Transaction.
+	Transaction Bytes `json:"transaction,omitempty"`
+
+	// TxID is the ID of the commit or Transaction.
+	TxID TxID `json:"txid"`
+
+	// EntryHash and ChainID identify a prepared Entry.
+	EntryHash *EntryHash `json:"entryhash,omitempty"`
+	ChainID   *Bytes32   `json:"chainid,omitempty"`
+
+	// Timestamp is the timestamp of the commit or Transaction. Expires is
+	// when Timestamp leaves the DefaultCommitWindow, after which factomd
+	// rejects the messages and they must be prepared again.
+	Timestamp time.Time `json:"timestamp"`
+	Expires   time.Time `json:"expires"`
+}
+
+// PrepareEntry composes e and signs its commit with signer, using ts as the
+// commit timestamp. No network requests are made.
+//
+// The e.Hash will be populated if not nil. If e.ChainID == nil, a new chain
+// will be created, and e.ChainID will be populated.
+func PrepareEntry(e *Entry, signer CommitSigner, ts time.Time) (Prepared, error) {
+	if err := e.Valid(); err != nil {
+		return Prepared{}, fmt.Errorf("factom.Entry.Valid(): %w", err)
+	}
+	newChain := e.ChainID == nil
+	if newChain {
+		e.ChainID = new(Bytes32)
+		*e.ChainID = ComputeChainID(e.ExtIDs)
+	}
+	reveal, err := e.MarshalBinary()
+	if err != nil {
+		return Prepared{}, fmt.Errorf("factom.Entry.MarshalBinary(): %w", err)
+	}
+	if e.Hash == nil {
+		e.Hash = new(EntryHash)
+		*e.Hash = ComputeEntryHash(reveal)
+	}
+	commit, txID, err := generateCommit(signer, reveal, e.Hash, newChain, ts)
+	if err != nil {
+		return Prepared{}, err
+	}
+	// The commit timestamp has millisecond resolution.
+	var cmt Commit
+	if err := cmt.UnmarshalBinary(commit); err != nil {
+		return Prepared{}, err
+	}
+	return Prepared{
+		Commit:    commit,
+		Reveal:    reveal,
+		TxID:      txID,
+		EntryHash: e.Hash,
+		ChainID:   e.ChainID,
+		Timestamp: cmt.Timestamp,
+		Expires:   cmt.Timestamp.Add(DefaultCommitWindow),
+	}, nil
+}
+
+// PrepareTransaction signs tx with signingSet, as with Transaction.Sign. The
+// tx.TimestampSalt is used as the Timestamp. No network requests are made.
+func PrepareTransaction(
+	tx *Transaction, signingSet ...RCDSigner) (Prepared, error) {
+	data, err := tx.Sign(signingSet...)
+	if err != nil {
+		return Prepared{}, err
+	}
+	txID, err := tx.TxID()
+	if err != nil {
+		return Prepared{}, err
+	}
+	return Prepared{
+		Transaction: data,
+		TxID:        txID,
+		Timestamp:   tx.TimestampSalt,
+		Expires:     tx.TimestampSalt.Add(DefaultCommitWindow),
+	}, nil
+}
+
+// Submit submits the messages of p to factomd, with Client.Commit and
+// Client.Reveal, or Client.FactoidSubmit.
+//
+// If p.Timestamp is outside of c.CommitWindow, an ErrorCommitTimestamp is
+// returned and nothing is submitted.
+func (c *Client) Submit(ctx context.Context, p Prepared) error {
+	if err := c.CheckCommitTimestamp(p.Timestamp, 0); err != nil {
+		return err
+	}
+	if len(p.Transaction) > 0 {
+		if err := c.FactoidSubmit(ctx, p.Transaction); err != nil {
+			return fmt.Errorf("factom.Client.FactoidSubmit(): %w", err)
+		}
+		return nil
+	}
+	if len(p.Commit) == 0 || len(p.Reveal) == 0 {
+		return fmt.Errorf("nothing prepared")
+	}
+	if err := c.Commit(ctx, p.Commit); err != nil {
+		return fmt.Errorf("factom.Client.Commit(): %w", err)
+	}
+	if err := c.Reveal(ctx, p.Reveal); err != nil {
+		return fmt.Errorf("factom.Client.Reveal(): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareEntry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	es, err := GenerateEsAddress()
+	require.NoError(err)
+	ts := time.Unix(1500000000, 0)
+	e := Entry{ExtIDs: []Bytes{Bytes("chain")}, Content: Bytes("hello")}
+	p, err := PrepareEntry(&e, es, ts)
+	require.NoError(err)
+	require.NotNil(e.ChainID)
+	assert.Equal(ComputeChainID(e.ExtIDs), *e.ChainID)
+	assert.Equal(e.Hash, p.EntryHash)
+	assert.Len(p.Commit, ChainCommitSize)
+	assert.Equal(ts.Unix(), p.Timestamp.Unix())
+	assert.Equal(p.Timestamp.Add(DefaultCommitWindow), p.Expires)
+
+	// Prepared messages may be transferred as JSON.
+	data, err := json.Marshal(p)
+	require.NoError(err)
+	var q Prepared
+	require.NoError(json.Unmarshal(data, &q))
+	assert.Equal(p.Commit, q.Commit)
+	assert.Equal(p.Reveal, q.Reveal)
+	assert.True(p.Expires.Equal(q.Expires))
+
+	c := newMockClient(t, map[string]interface{}{
+		"commit-chain": struct{}{},
+		"reveal-entry": struct{}{},
+	})
+	clock := NewFakeClock(ts.Add(time.Hour))
+	c.Clock = clock
+	require.NoError(c.Submit(context.Background(), q))
+
+	// Expired messages are not submitted.
+	clock.Set(p.Expires.Add(time.Second))
+	err = c.Submit(context.Background(), q)
+	var tsErr ErrorCommitTimestamp
+	require.True(errors.As(err, &tsErr), err)
+	assert.True(tsErr.Expired)
+}
+
+func TestPrepareTransaction(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := GenerateFsAddress()
+	require.NoError(err)
+	fa := fs.FAAddress()
+	ts := time.Unix(1500000000, 0)
+	tx := Transaction{
+		TimestampSalt: ts,
+		FCTInputs:     []AddressAmount{{Address: fa[:], Amount: 100000}},
+		FCTOutputs:    []AddressAmount{{Address: fa[:], Amount: 90000}},
+		Signatures:    make([]RCDSignature, 1),
+	}
+	p, err := PrepareTransaction(&tx, fs)
+	require.NoError(err)
+	txID, err := tx.TxID()
+	require.NoError(err)
+	assert.Equal(txID, p.TxID)
+	assert.Equal(ts, p.Timestamp)
+	assert.NotEmpty(p.Transaction)
+
+	c := newMockClient(t, map[string]interface{}{
+		"factoid-submit": struct{}{},
+	})
+	c.Clock = NewFakeClock(ts)
+	require.NoError(c.Submit(context.Background(), p))
+
+	assert.Error(c.Submit(context.Background(), Prepared{Timestamp: ts}))
+}
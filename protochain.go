@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import "fmt"
+
+// ProtocolChain identifies one of the three Chains maintained by the protocol
+// itself, whose blocks are referenced by every DBlock alongside the EBlocks.
+//
+// The ChainID of each ProtocolChain is all zeros except for its last byte,
+// which is the value of the ProtocolChain.
+type ProtocolChain byte
+
+// The ProtocolChains.
+const (
+	// AdminChain is the Admin Block Chain, 0x00..0a.
+	AdminChain ProtocolChain = 0x0a
+
+	// ECChain is the Entry Credit Block Chain, 0x00..0c.
+	ECChain ProtocolChain = 0x0c
+
+	// FactoidChain is the Factoid Block Chain, 0x00..0f.
+	FactoidChain ProtocolChain = 0x0f
+)
+
+// ProtocolChainOf returns the ProtocolChain with chainID, if there is one.
+func ProtocolChainOf(chainID Bytes32) (ProtocolChain, bool) {
+	p := ProtocolChain(chainID[len(chainID)-1])
+	if !p.IsValid() || chainID != p.ChainID() {
+		return 0, false
+	}
+	return p, true
+}
+
+// IsValid returns true if p is AdminChain, ECChain, or FactoidChain.
+func (p ProtocolChain) IsValid() bool {
+	switch p {
+	case AdminChain, ECChain, FactoidChain:
+		return true
+	}
+	return false
+}
+
+// ChainID returns the ChainID of p.
+func (p ProtocolChain) ChainID() Bytes32 {
+	return Bytes32{31: byte(p)}
+}
+
+// String returns the name of p.
+func (p ProtocolChain) String() string {
+	switch p {
+	case AdminChain:
+		return "Admin Chain"
+	case ECChain:
+		return "Entry Credit Chain"
+	case FactoidChain:
+		return "Factoid Chain"
+	}
+	return fmt.Sprintf("ProtocolChain(%#02x)", byte(p))
+}
+
+// ABlock returns the ABlock referenced by db with its LookupHash and Height
+// initialized, ready to be populated by ABlock.Get. The LookupHash is nil if
+// db does not reference an ABlock.
+func (db DBlock) ABlock() ABlock {
+	ab := ABlock{Height: db.Height}
+	if eb := db.EBlock(AdminChain.ChainID()); eb != nil {
+		lookupHash := Bytes32(*eb.KeyMR)
+		ab.LookupHash = &lookupHash
+	}
+	return ab
+}
+
+// ECBlock returns the ECBlock referenced by db with its HeaderHash and Height
+// initialized, ready to be populated by ECBlock.Get. The HeaderHash is nil if
+// db does not reference an ECBlock.
+func (db DBlock) ECBlock() ECBlock {
+	ecb := ECBlock{Height: db.Height}
+	if eb := db.EBlock(ECChain.ChainID()); eb != nil {
+		headerHash := Bytes32(*eb.KeyMR)
+		ecb.HeaderHash = &headerHash
+	}
+	return ecb
+}
+
+// EntryEBlocks returns the EBlocks in db excluding the references to the ABlock
+// and ECBlock, which DBlock.UnmarshalBinary leaves in db.EBlocks. The FBlock is
+// always in db.FBlock instead.
+func (db DBlock) EntryEBlocks() []EBlock {
+	ebs := make([]EBlock, 0, len(db.EBlocks))
+	for _, eb := range db.EBlocks {
+		if _, ok := ProtocolChainOf(*eb.ChainID); ok {
+			continue
+		}
+		ebs = append(ebs, eb)
+	}
+	return ebs
+}
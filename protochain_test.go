@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolChain(t *testing.T) {
+	for _, p := range []ProtocolChain{AdminChain, ECChain, FactoidChain} {
+		assert.True(t, p.IsValid())
+		found, ok := ProtocolChainOf(p.ChainID())
+		assert.True(t, ok)
+		assert.Equal(t, p, found)
+	}
+	assert.Equal(t, ABlockChainID(), AdminChain.ChainID())
+	assert.Equal(t, ECBlockChainID(), ECChain.ChainID())
+	assert.Equal(t, FBlockChainID(), FactoidChain.ChainID())
+	assert.Equal(t, "Entry Credit Chain", ECChain.String())
+	assert.Equal(t, "ProtocolChain(0x0b)", ProtocolChain(0x0b).String())
+
+	for _, chainID := range []Bytes32{{31: 0x0b}, {0: 1, 31: 0x0a}, {}} {
+		_, ok := ProtocolChainOf(chainID)
+		assert.False(t, ok, chainID)
+	}
+
+	adminChainID := AdminChain.ChainID()
+	ecChainID := ECChain.ChainID()
+	db := DBlock{Height: 5, EBlocks: []EBlock{
+		{ChainID: &adminChainID, KeyMR: &KeyMR{1}},
+		{ChainID: &ecChainID, KeyMR: &KeyMR{2}},
+		{ChainID: &Bytes32{3}, KeyMR: &KeyMR{3}},
+	}}
+
+	ab := db.ABlock()
+	assert.Equal(t, uint32(5), ab.Height)
+	assert.Equal(t, Bytes32{1}, *ab.LookupHash)
+
+	ecb := db.ECBlock()
+	assert.Equal(t, uint32(5), ecb.Height)
+	assert.Equal(t, Bytes32{2}, *ecb.HeaderHash)
+
+	ebs := db.EntryEBlocks()
+	require.Len(t, ebs, 1)
+	assert.Equal(t, Bytes32{3}, *ebs[0].ChainID)
+
+	assert.Nil(t, DBlock{}.ABlock().LookupHash)
+	assert.Nil(t, DBlock{}.ECBlock().HeaderHash)
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+package factom
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NewProxyTransport returns a copy of http.DefaultTransport that connects
+// through the proxy at proxyURL, regardless of the HTTP_PROXY, HTTPS_PROXY,
+// and NO_PROXY environment variables.
+//
+// The scheme of proxyURL must be "http", "https", or "socks5", and it may
+// include a username and password. For example, use "socks5://127.0.0.1:9050"
+// to route requests through a local Tor client. Host names are resolved by a
+// SOCKS5 proxy, not locally.
+//
+// If proxyURL is empty, the returned Transport does not use any proxy, even
+// if the environment variables are set.
+func NewProxyTransport(proxyURL string) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	if proxyURL == "" {
+		return t, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing proxy host")
+	}
+	t.Proxy = http.ProxyURL(u)
+	return t, nil
+}
+
+// SetProxy routes the factomd requests of c through the proxy at proxyURL by
+// replacing the Transport of c.Factomd. See NewProxyTransport for the
+// supported proxies.
+//
+// Since factom-walletd normally runs locally, c.Walletd is not changed. Use
+// NewProxyTransport directly to route it through a proxy as well.
+func (c *Client) SetProxy(proxyURL string) error {
+	t, err := NewProxyTransport(proxyURL)
+	if err != nil {
+		return err
+	}
+	c.Factomd.Transport = t
+	return nil
+}
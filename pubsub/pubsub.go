@@ -0,0 +1,379 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package pubsub turns Factom chains into durable message topics.
+//
+// A Topic is a chain whose first Entry has the ExtIDs
+//
+//	["pubsub-topic", names...]
+//
+// Publishers write each message as an envelope Entry with the ExtIDs
+//
+//	["pubsub", <message ID (16 bytes)>, <encoding>]
+//
+// or, if the message is signed,
+//
+//	["pubsub", <message ID (16 bytes)>, <encoding>, <RCD>, <signature>]
+//
+// where the encoding is "plain" or "aes-256-gcm", and the Content is the
+// payload. The signature is of the hex encoded Topic ChainID concatenated with
+// the message ID, the encoding, and the Content. Encrypted payloads are the
+// 12 byte GCM nonce followed by the ciphertext, sealed with the Topic Key and
+// the Topic ChainID followed by the message ID as additional data. They are
+// encrypted before they are signed.
+//
+// A publisher that retries a message reuses its message ID, so a Subscriber
+// delivers each message ID only once, even if it appears in several Entries.
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+var (
+	// ChainTag is the first ExtID of the first Entry of a Topic chain.
+	ChainTag = factom.Bytes("pubsub-topic")
+
+	// Tag is the first ExtID of all envelope Entries.
+	Tag = factom.Bytes("pubsub")
+)
+
+// Encodings of envelope Content.
+const (
+	EncodingPlain     = "plain"
+	EncodingAES256GCM = "aes-256-gcm"
+)
+
+// MessageID uniquely identifies a message.
+type MessageID [16]byte
+
+// NewMessageID returns a random MessageID.
+func NewMessageID() (MessageID, error) {
+	var id MessageID
+	_, err := rand.Read(id[:])
+	return id, err
+}
+
+// Topic is a chain of messages.
+type Topic struct {
+	Names []factom.Bytes
+
+	// Key, if not nil, encrypts published messages and decrypts received
+	// messages.
+	Key *[32]byte
+
+	// Publishers, if not nil, are the RCD hashes of the only keys whose
+	// signed messages are delivered. Unsigned messages are never
+	// delivered. If nil, signatures are still verified when present.
+	Publishers factom.Bytes32Set
+}
+
+// NameIDs returns the ExtIDs of the first Entry of the Topic chain.
+func (t Topic) NameIDs() []factom.Bytes {
+	return append([]factom.Bytes{ChainTag}, t.Names...)
+}
+
+// ChainID returns the ChainID of the Topic chain.
+func (t Topic) ChainID() factom.Bytes32 {
+	return factom.ComputeChainID(t.NameIDs())
+}
+
+// Create creates the Topic chain, unless it exists, using ComposeCreate with
+// es.
+func (t Topic) Create(ctx context.Context, c *factom.Client,
+	es factom.EsAddress) error {
+	exists, err := c.ChainExists(ctx, t.ChainID())
+	if err != nil || exists {
+		return err
+	}
+	first := factom.Entry{ExtIDs: t.NameIDs(), Content: factom.Bytes{}}
+	if _, err := first.ComposeCreate(ctx, c, es); err != nil {
+		return fmt.Errorf("create chain: %w", err)
+	}
+	return nil
+}
+
+func (t Topic) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(t.Key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (t Topic) additionalData(id MessageID) []byte {
+	chainID := t.ChainID()
+	return append(chainID[:], id[:]...)
+}
+
+// signedMsg returns the data signed for an envelope.
+func (t Topic) signedMsg(id, encoding, content []byte) []byte {
+	msg := []byte(t.ChainID().String())
+	msg = append(msg, id...)
+	msg = append(msg, encoding...)
+	return append(msg, content...)
+}
+
+// Envelope returns the envelope Entry for payload with id. The payload is
+// encrypted if t.Key is not nil, and signed by signer, unless it is nil.
+func (t Topic) Envelope(id MessageID, payload []byte,
+	signer factom.RCDSigner) (factom.Entry, error) {
+	encoding := EncodingPlain
+	content := factom.Bytes(payload)
+	if t.Key != nil {
+		aead, err := t.aead()
+		if err != nil {
+			return factom.Entry{}, err
+		}
+		nonce := make([]byte, aead.NonceSize(),
+			aead.NonceSize()+len(payload)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return factom.Entry{}, err
+		}
+		content = aead.Seal(nonce, nonce, payload, t.additionalData(id))
+		encoding = EncodingAES256GCM
+	}
+	if content == nil {
+		content = factom.Bytes{}
+	}
+	extIDs := []factom.Bytes{Tag, id[:], factom.Bytes(encoding)}
+	if signer != nil {
+		extIDs = append(extIDs, factom.Bytes(signer.RCD()),
+			signer.Sign(t.signedMsg(id[:], extIDs[2], content)))
+	}
+	chainID := t.ChainID()
+	return factom.Entry{ChainID: &chainID, ExtIDs: extIDs, Content: content},
+		nil
+}
+
+// Publish submits the envelope Entry for payload, with a new MessageID, using
+// ComposeCreate with es. The Topic chain must exist. The MessageID is returned
+// so that the message may be retried with Envelope without duplication.
+func (t Topic) Publish(ctx context.Context, c *factom.Client,
+	es factom.EsAddress, payload []byte,
+	signer factom.RCDSigner) (MessageID, factom.TxID, error) {
+	id, err := NewMessageID()
+	if err != nil {
+		return MessageID{}, factom.TxID{}, err
+	}
+	e, err := t.Envelope(id, payload, signer)
+	if err != nil {
+		return MessageID{}, factom.TxID{}, err
+	}
+	txID, err := e.ComposeCreate(ctx, c, es)
+	if err != nil {
+		return MessageID{}, factom.TxID{}, err
+	}
+	return id, txID, nil
+}
+
+// Message is a received message.
+type Message struct {
+	ID      MessageID
+	Payload []byte
+
+	// Publisher is the RCD hash of the signer, or nil if the message is
+	// unsigned.
+	Publisher *factom.Bytes32
+
+	// Entry is the envelope Entry.
+	Entry factom.Entry
+}
+
+// Open verifies and decrypts the envelope Entry e.
+func (t Topic) Open(e factom.Entry) (Message, error) {
+	if e.ChainID == nil || *e.ChainID != t.ChainID() {
+		return Message{}, fmt.Errorf("invalid ChainID")
+	}
+	if (len(e.ExtIDs) != 3 && len(e.ExtIDs) != 5) ||
+		!bytes.Equal(e.ExtIDs[0], Tag) {
+		return Message{}, fmt.Errorf("invalid ExtIDs")
+	}
+	var m Message
+	if len(e.ExtIDs[1]) != len(m.ID) {
+		return Message{}, fmt.Errorf("invalid message ID length")
+	}
+	copy(m.ID[:], e.ExtIDs[1])
+	m.Entry = e
+
+	if len(e.ExtIDs) == 5 {
+		var rcd factom.RCD
+		if err := rcd.UnmarshalBinary(e.ExtIDs[3]); err != nil {
+			return Message{}, fmt.Errorf("invalid RCD: %w", err)
+		}
+		msg := t.signedMsg(e.ExtIDs[1], e.ExtIDs[2], e.Content)
+		if err := rcd.Validate(e.ExtIDs[4], msg); err != nil {
+			return Message{}, err
+		}
+		publisher := rcd.Hash()
+		m.Publisher = &publisher
+	}
+	if t.Publishers != nil {
+		if m.Publisher == nil {
+			return Message{}, fmt.Errorf("unsigned message")
+		}
+		if !t.Publishers.Has(*m.Publisher) {
+			return Message{}, fmt.Errorf("unknown publisher")
+		}
+	}
+
+	switch encoding := string(e.ExtIDs[2]); encoding {
+	case EncodingPlain:
+		m.Payload = e.Content
+	case EncodingAES256GCM:
+		if t.Key == nil {
+			return Message{}, fmt.Errorf("missing key")
+		}
+		aead, err := t.aead()
+		if err != nil {
+			return Message{}, err
+		}
+		if len(e.Content) < aead.NonceSize() {
+			return Message{}, fmt.Errorf("invalid ciphertext")
+		}
+		nonce := e.Content[:aead.NonceSize()]
+		ciphertext := e.Content[aead.NonceSize():]
+		if m.Payload, err = aead.Open(nil, nonce, ciphertext,
+			t.additionalData(m.ID)); err != nil {
+			return Message{}, err
+		}
+	default:
+		return Message{}, fmt.Errorf("unknown encoding: %q", encoding)
+	}
+	return m, nil
+}
+
+// DefaultDedupSize is the default for Subscriber.DedupSize.
+const DefaultDedupSize = 10000
+
+// Subscriber receives the messages of a Topic.
+type Subscriber struct {
+	Topic Topic
+
+	// DedupSize is the number of recent MessageIDs remembered to
+	// deduplicate messages. If zero, DefaultDedupSize is used.
+	DedupSize int
+
+	// OnInvalid, if not nil, is called with each Entry that is not a
+	// valid message, such as one with an invalid signature or that cannot
+	// be decrypted. Such Entries are otherwise ignored.
+	OnInvalid func(e factom.Entry, err error)
+
+	seen  map[MessageID]struct{}
+	order []MessageID
+}
+
+// Receive opens e and returns the Message, or false if e is not a valid
+// message or its MessageID has already been received.
+func (s *Subscriber) Receive(e factom.Entry) (Message, bool) {
+	if len(e.ExtIDs) > 0 && bytes.Equal(e.ExtIDs[0], ChainTag) {
+		return Message{}, false
+	}
+	m, err := s.Topic.Open(e)
+	if err != nil {
+		if s.OnInvalid != nil {
+			s.OnInvalid(e, err)
+		}
+		return Message{}, false
+	}
+	if _, ok := s.seen[m.ID]; ok {
+		return Message{}, false
+	}
+	size := s.DedupSize
+	if size <= 0 {
+		size = DefaultDedupSize
+	}
+	if s.seen == nil {
+		s.seen = make(map[MessageID]struct{})
+	}
+	if len(s.order) >= size {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.seen[m.ID] = struct{}{}
+	s.order = append(s.order, m.ID)
+	return m, true
+}
+
+// Subscribe calls handler with each message in the Topic chain, in order,
+// after the EBlock with the KeyMR after, or from the start of the chain if
+// after is nil. New EBlocks are polled for every factom.BlockPollInterval.
+//
+// Subscribe blocks until ctx is done or handler returns an error, and returns
+// that error. Each handled EBlock's KeyMR is passed to handler along with its
+// messages, and may be persisted and passed as after to resume after a
+// restart. Messages are delivered at least once: the messages of an EBlock
+// that was partially handled are delivered again on resumption.
+func (s *Subscriber) Subscribe(ctx context.Context, c *factom.Client,
+	after *factom.KeyMR,
+	handler func(m Message, keyMR factom.KeyMR) error) error {
+	chainID := s.Topic.ChainID()
+	for {
+		head := factom.EBlock{ChainID: &chainID}
+		if _, err := head.GetChainHead(ctx, c); err != nil {
+			return err
+		}
+		if head.KeyMR != nil && (after == nil || *head.KeyMR != *after) {
+			var eblocks []factom.EBlock
+			var err error
+			if after == nil {
+				eblocks, err = head.GetPrevAll(ctx, c)
+			} else {
+				eblocks, err = head.GetPrevBackTo(ctx, c, after)
+			}
+			if err != nil {
+				return err
+			}
+			for i := len(eblocks) - 1; i >= 0; i-- {
+				eb := &eblocks[i]
+				if err := eb.GetEntries(ctx, c); err != nil {
+					return err
+				}
+				for _, e := range eb.Entries {
+					m, ok := s.Receive(e)
+					if !ok {
+						continue
+					}
+					if err := handler(m, *eb.KeyMR); err != nil {
+						return err
+					}
+				}
+				keyMR := *eb.KeyMR
+				after = &keyMR
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(factom.BlockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubSub(t *testing.T) {
+	publisher, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	key := [32]byte{1, 2, 3}
+	topic := Topic{
+		Names:      []factom.Bytes{factom.Bytes("test")},
+		Key:        &key,
+		Publishers: factom.NewBytes32Set(factom.Bytes32(publisher.FAAddress())),
+	}
+
+	id, err := NewMessageID()
+	require.NoError(t, err)
+	payload := []byte("hello")
+	e, err := topic.Envelope(id, payload, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, topic.ChainID(), *e.ChainID)
+	assert.NotContains(t, string(e.Content), "hello")
+
+	var invalid []error
+	s := Subscriber{Topic: topic, DedupSize: 1,
+		OnInvalid: func(_ factom.Entry, err error) {
+			invalid = append(invalid, err)
+		}}
+	m, ok := s.Receive(e)
+	require.True(t, ok)
+	assert.Equal(t, id, m.ID)
+	assert.Equal(t, payload, m.Payload)
+	assert.Equal(t, factom.Bytes32(publisher.FAAddress()), *m.Publisher)
+
+	// Retries of the same message are not delivered again.
+	retry, err := topic.Envelope(id, payload, publisher)
+	require.NoError(t, err)
+	_, ok = s.Receive(retry)
+	assert.False(t, ok)
+
+	// Unsigned, unknown and tampered messages are not delivered.
+	id2, _ := NewMessageID()
+	unsigned, err := topic.Envelope(id2, payload, nil)
+	require.NoError(t, err)
+	_, ok = s.Receive(unsigned)
+	assert.False(t, ok)
+
+	other, err := factom.GenerateFsAddress()
+	require.NoError(t, err)
+	unknown, err := topic.Envelope(id2, payload, other)
+	require.NoError(t, err)
+	_, ok = s.Receive(unknown)
+	assert.False(t, ok)
+
+	tampered, err := topic.Envelope(id2, payload, publisher)
+	require.NoError(t, err)
+	tampered.Content[len(tampered.Content)-1] ^= 1
+	_, ok = s.Receive(tampered)
+	assert.False(t, ok)
+	assert.Len(t, invalid, 3)
+
+	// Only DedupSize MessageIDs are remembered.
+	e2, err := topic.Envelope(id2, payload, publisher)
+	require.NoError(t, err)
+	_, ok = s.Receive(e2)
+	assert.True(t, ok)
+	_, ok = s.Receive(retry)
+	assert.True(t, ok)
+
+	// A subscriber without the Key cannot decrypt.
+	plain := Subscriber{Topic: Topic{Names: topic.Names}}
+	_, ok = plain.Receive(e)
+	assert.False(t, ok)
+
+	// Messages for other topics are rejected.
+	_, err = Topic{Names: []factom.Bytes{factom.Bytes("other")}}.Open(e)
+	assert.Error(t, err)
+}
+
+func TestPlain(t *testing.T) {
+	topic := Topic{Names: []factom.Bytes{factom.Bytes("plain")}}
+	id, err := NewMessageID()
+	require.NoError(t, err)
+	e, err := topic.Envelope(id, []byte("hello"), nil)
+	require.NoError(t, err)
+	assert.Len(t, e.ExtIDs, 3)
+	assert.Equal(t, factom.Bytes("hello"), e.Content)
+
+	m, err := topic.Open(e)
+	require.NoError(t, err)
+	assert.Nil(t, m.Publisher)
+	assert.Equal(t, []byte("hello"), m.Payload)
+
+	e.ExtIDs[2] = factom.Bytes("unknown")
+	_, err = topic.Open(e)
+	assert.Error(t, err)
+
+	var s Subscriber
+	s.Topic = topic
+	first := factom.Entry{ChainID: e.ChainID, ExtIDs: topic.NameIDs()}
+	_, ok := s.Receive(first)
+	assert.False(t, ok)
+}
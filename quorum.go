@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+)
+
+// QuorumClient cross-checks factomd read requests against multiple nodes, for
+// users who must not trust the operator of any single factomd API.
+//
+// When Client.Quorum is set, every factomd request that does not change state
+// is sent to all Clients concurrently, and a result is only returned if at
+// least Threshold of them return identical results. Results are compared
+// after normalizing their JSON, so field order and whitespace do not matter,
+// but any difference in a hash, height, or other value does. Identical
+// JSON-RPC errors also count as agreement, so that, for example, a missing
+// Chain is reported as missing.
+//
+// Requests that change state are sent only to the Client with the Quorum set.
+//
+// The Clients must not themselves have a Quorum set.
+type QuorumClient struct {
+	// Clients are the nodes to query.
+	Clients []*Client
+
+	// Threshold is the number of Clients that must agree. If zero, a
+	// majority of the Clients is required.
+	Threshold int
+
+	// Divergence, if not nil, is called with the Clients that did not
+	// agree whenever a quorum is reached without all Clients agreeing.
+	Divergence func(method string, divergent []*Client)
+}
+
+// ErrorQuorum is returned when fewer than the threshold of Clients agree on
+// the result of a request.
+type ErrorQuorum struct {
+	Method    string
+	Agree     int
+	Threshold int
+
+	// Errors are the errors from the Clients that failed to respond, by
+	// index in QuorumClient.Clients.
+	Errors map[int]error
+}
+
+// Error implements error.
+func (err ErrorQuorum) Error() string {
+	return fmt.Sprintf("%v: quorum not reached: %v agree, expected %v",
+		err.Method, err.Agree, err.Threshold)
+}
+
+func (q *QuorumClient) threshold() int {
+	if q.Threshold == 0 {
+		return len(q.Clients)/2 + 1
+	}
+	return q.Threshold
+}
+
+// quorumResponse is the normalized response of a single Client.
+type quorumResponse struct {
+	key    []byte
+	result json.RawMessage
+	err    error
+}
+
+// request makes the request to all q.Clients and unmarshals the agreed upon
+// result into result.
+func (q *QuorumClient) request(ctx context.Context,
+	method string, params, result interface{}) error {
+
+	responses := make([]quorumResponse, len(q.Clients))
+	var wg sync.WaitGroup
+	for i, c := range q.Clients {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			responses[i] = quorumRequest(ctx, c, method, params)
+		}(i, c)
+	}
+	wg.Wait()
+
+	// Find the largest group of identical responses.
+	var best, agree int
+	for i, res := range responses {
+		if res.key == nil {
+			continue
+		}
+		var n int
+		for _, other := range responses {
+			if bytes.Equal(res.key, other.key) {
+				n++
+			}
+		}
+		if n > agree {
+			best, agree = i, n
+		}
+	}
+
+	threshold := q.threshold()
+	if agree < threshold {
+		errs := make(map[int]error)
+		for i, res := range responses {
+			if res.key == nil {
+				errs[i] = res.err
+			}
+		}
+		return ErrorQuorum{Method: method,
+			Agree: agree, Threshold: threshold, Errors: errs}
+	}
+
+	if q.Divergence != nil && agree < len(q.Clients) {
+		var divergent []*Client
+		for i, res := range responses {
+			if !bytes.Equal(res.key, responses[best].key) {
+				divergent = append(divergent, q.Clients[i])
+			}
+		}
+		q.Divergence(method, divergent)
+	}
+
+	if err := responses[best].err; err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(responses[best].result, result)
+}
+
+// quorumRequest makes the request using c and returns the response with its
+// comparison key. The key is nil if c failed to respond.
+func quorumRequest(ctx context.Context, c *Client,
+	method string, params interface{}) quorumResponse {
+
+	var res quorumResponse
+	if err := c.FactomdRequest(ctx, method, params, &res.result); err != nil {
+		res.err = err
+		var jErr jsonrpc2.Error
+		if errors.As(err, &jErr) {
+			res.key, _ = json.Marshal(jErr)
+			res.key = append([]byte("error:"), res.key...)
+		}
+		return res
+	}
+
+	// Decoding and re-encoding sorts object keys and removes whitespace.
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(res.result))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		res.err = err
+		return res
+	}
+	key, err := json.Marshal(v)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	res.key = append([]byte("result:"), key...)
+	return res
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuorumClient(t *testing.T) {
+	ctx := context.Background()
+	rate := func(raw string) *Client {
+		return newMockClient(t, map[string]interface{}{
+			"entry-credit-rate": json.RawMessage(raw)})
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Clients   []*Client
+		Threshold int
+		Rate      uint64
+		Divergent int
+		Err       string
+	}{{
+		Name: "agree",
+		Clients: []*Client{rate(`{"rate":1000}`),
+			rate(`{ "rate": 1000 }`), rate(`{"rate":1000}`)},
+		Rate: 1000,
+	}, {
+		Name: "majority",
+		Clients: []*Client{rate(`{"rate":1000}`),
+			rate(`{"rate":2000}`), rate(`{"rate":1000}`)},
+		Rate:      1000,
+		Divergent: 1,
+	}, {
+		Name: "threshold",
+		Clients: []*Client{rate(`{"rate":1000}`),
+			rate(`{"rate":2000}`), rate(`{"rate":1000}`)},
+		Threshold: 3,
+		Err:       "entry-credit-rate: quorum not reached: 2 agree, expected 3",
+	}, {
+		Name: "no majority",
+		Clients: []*Client{rate(`{"rate":1000}`),
+			rate(`{"rate":2000}`), rate(`{"rate":3000}`)},
+		Err: "entry-credit-rate: quorum not reached: 1 agree, expected 2",
+	}} {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			var divergent []*Client
+			c := NewClient()
+			c.Quorum = &QuorumClient{
+				Clients:   test.Clients,
+				Threshold: test.Threshold,
+				Divergence: func(method string, clients []*Client) {
+					assert.Equal(t, "entry-credit-rate", method)
+					divergent = clients
+				},
+			}
+			rate, err := c.GetECRate(ctx)
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				var qErr ErrorQuorum
+				assert.True(t, errors.As(err, &qErr))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Rate, rate)
+			assert.Len(t, divergent, test.Divergent)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		chainID := ComputeChainID([]Bytes{Bytes("quorum")})
+		e := []Entry{{Content: Bytes("test")}}
+		c := NewClient()
+		c.Quorum = &QuorumClient{Clients: []*Client{
+			newMockChain(t, chainID, e),
+			newMockChain(t, chainID, e),
+		}}
+		exists, err := c.ChainExists(ctx, chainID)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		// Identical "missing chain" errors are agreement.
+		exists, err = c.ChainExists(ctx, Bytes32{1})
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		c := newMockClient(t, map[string]interface{}{
+			"commit-entry": map[string]interface{}{}})
+		// The Quorum Clients would fail the test if they received a
+		// request.
+		c.Quorum = &QuorumClient{Clients: []*Client{
+			newMockClient(t, nil)}}
+		assert.NoError(t, c.Commit(ctx, make([]byte, 136)))
+	})
+}
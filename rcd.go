@@ -24,7 +24,6 @@ package factom
 
 import (
 	"crypto/ed25519"
-	"crypto/sha256"
 	"fmt"
 )
 
@@ -95,12 +94,6 @@ func (rcd RCD) FAAddress() FAAddress {
 	return FAAddress(rcd.Hash())
 }
 
-// sha256(sha256(data))
-func sha256d(data []byte) [sha256.Size]byte {
-	hash := sha256.Sum256(data)
-	return sha256.Sum256(hash[:])
-}
-
 // Validate verifies the RCD against the given sig and msg. Only RCDTypes in
 // the whitelist are permitted. If no whitelist is provided all supported
 // RCDTypes are allowed.
@@ -130,6 +123,18 @@ func (rcd RCD) Validate(sig, msg []byte, whitelist ...RCDType) error {
 // ValidateType01 validates the RCD against sig and msg and ensures that the
 // RCD is RCDType01.
 func (rcd RCD) ValidateType01(sig, msg []byte) error {
+	if err := rcd.validType01(sig); err != nil {
+		return err
+	}
+	if !ed25519.Verify(rcd.publicKeyType01(), msg, sig) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// validType01 ensures that rcd is RCDType01 and that sig is the size of its
+// signatures, without verifying sig.
+func (rcd RCD) validType01(sig []byte) error {
 	if len(rcd) != RCDType01Size {
 		return fmt.Errorf("invalid RCD size")
 	}
@@ -139,15 +144,14 @@ func (rcd RCD) ValidateType01(sig, msg []byte) error {
 	if len(sig) != RCDType01SigSize {
 		return fmt.Errorf("invalid signature size")
 	}
-
-	pubKey := []byte(rcd[1:RCDType01Size])
-	if !ed25519.Verify(pubKey, msg, sig) {
-		return fmt.Errorf("invalid signature")
-	}
-
 	return nil
 }
 
+// publicKeyType01 returns the public key of an RCDType01.
+func (rcd RCD) publicKeyType01() ed25519.PublicKey {
+	return ed25519.PublicKey(rcd[1:RCDType01Size])
+}
+
 // UnmarshalBinary parses the first RCD out of data. Use len(rcd) to determine
 // how many bytes of data were read.
 func (rcd *RCD) UnmarshalBinary(data []byte) error {
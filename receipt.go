@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom/merkle"
+)
+
+// Receipt is a Merkle proof that an Entry is included in an EBlock, and that
+// the EBlock is included in a DBlock.
+//
+// The MerkleBranch leads from the EntryHash up through the EBlock body to the
+// EBlockKeyMR, and from there up through the DBlock body to the DBlockKeyMR.
+type Receipt struct {
+	EntryHash EntryHash
+
+	// Timestamp is the time of the end of the minute in which the Entry
+	// was included, with a precision of one second.
+	Timestamp time.Time
+
+	MerkleBranch []merkle.Node
+
+	EBlockKeyMR  KeyMR
+	DBlockKeyMR  KeyMR
+	DBlockHeight uint32
+}
+
+// Get queries factomd for the Receipt for r.EntryHash and validates it.
+//
+// The returned Receipt is only as trustworthy as the DBlockKeyMR. Compare it
+// to the KeyMR of the DBlock at r.DBlockHeight from a trusted source.
+func (r *Receipt) Get(ctx context.Context, c *Client) error {
+	params := struct {
+		Hash *EntryHash `json:"hash"`
+	}{Hash: &r.EntryHash}
+	result := struct {
+		Receipt *Receipt `json:"receipt"`
+	}{Receipt: r}
+	if err := c.FactomdRequest(ctx, "receipt", params, &result); err != nil {
+		return err
+	}
+	return r.Validate()
+}
+
+// Validate returns an error if the r.MerkleBranch does not lead from
+// r.EntryHash to r.EBlockKeyMR and then to r.DBlockKeyMR.
+func (r Receipt) Validate() error {
+	hash := [32]byte(r.EntryHash)
+	var eblockFound bool
+	for i := range r.MerkleBranch {
+		hash = merkleHasher.ComputeBranchRoot(hash,
+			r.MerkleBranch[i:i+1])
+		if hash == r.EBlockKeyMR {
+			eblockFound = true
+		}
+	}
+	if !eblockFound {
+		return fmt.Errorf("EBlock KeyMR not found in Merkle branch")
+	}
+	if hash != r.DBlockKeyMR {
+		return fmt.Errorf("Merkle branch does not lead to DBlock KeyMR")
+	}
+	return nil
+}
+
+type receiptNode struct {
+	Left  *Bytes32 `json:"left,omitempty"`
+	Right *Bytes32 `json:"right,omitempty"`
+	Top   *Bytes32 `json:"top,omitempty"`
+}
+
+type receiptJSON struct {
+	Entry struct {
+		EntryHash *EntryHash `json:"entryhash"`
+		Timestamp int64      `json:"timestamp,omitempty"`
+	} `json:"entry"`
+	MerkleBranch []receiptNode `json:"merklebranch"`
+	EBlockKeyMR  *KeyMR        `json:"entryblockkeymr"`
+	DBlockKeyMR  *KeyMR        `json:"directoryblockkeymr"`
+	DBlockHeight uint32        `json:"directoryblockheight,omitempty"`
+}
+
+// UnmarshalJSON unmarshals a full or minimal factomd receipt. The nodes of a
+// full receipt, which include both sides and the top of each node, are
+// checked for consistency.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var rJSON receiptJSON
+	if err := json.Unmarshal(data, &rJSON); err != nil {
+		return err
+	}
+	if rJSON.Entry.EntryHash == nil {
+		return fmt.Errorf("missing entryhash")
+	}
+	if rJSON.EBlockKeyMR == nil {
+		return fmt.Errorf("missing entryblockkeymr")
+	}
+	if rJSON.DBlockKeyMR == nil {
+		return fmt.Errorf("missing directoryblockkeymr")
+	}
+
+	branch := make([]merkle.Node, len(rJSON.MerkleBranch))
+	hash := [32]byte(*rJSON.Entry.EntryHash)
+	for i, n := range rJSON.MerkleBranch {
+		node := &branch[i]
+		switch {
+		case n.Left == nil && n.Right == nil:
+			return fmt.Errorf("merklebranch[%v]: missing left and right", i)
+		case n.Left == nil:
+			node.Hash = *n.Right
+		case n.Right == nil:
+			node.Hash = *n.Left
+			node.Left = true
+		case *n.Left == hash:
+			node.Hash = *n.Right
+		case *n.Right == hash:
+			node.Hash = *n.Left
+			node.Left = true
+		default:
+			return fmt.Errorf("merklebranch[%v]: missing hash", i)
+		}
+		hash = merkleHasher.ComputeBranchRoot(hash, branch[i:i+1])
+		if n.Top != nil && *n.Top != hash {
+			return fmt.Errorf("merklebranch[%v]: invalid top", i)
+		}
+	}
+
+	r.EntryHash = *rJSON.Entry.EntryHash
+	r.Timestamp = time.Time{}
+	if rJSON.Entry.Timestamp > 0 {
+		r.Timestamp = time.Unix(rJSON.Entry.Timestamp, 0)
+	}
+	r.MerkleBranch = branch
+	r.EBlockKeyMR = *rJSON.EBlockKeyMR
+	r.DBlockKeyMR = *rJSON.DBlockKeyMR
+	r.DBlockHeight = rJSON.DBlockHeight
+	return nil
+}
+
+// MarshalJSON marshals r as a minimal factomd receipt.
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	var rJSON receiptJSON
+	rJSON.Entry.EntryHash = &r.EntryHash
+	if !r.Timestamp.IsZero() {
+		rJSON.Entry.Timestamp = r.Timestamp.Unix()
+	}
+	rJSON.MerkleBranch = make([]receiptNode, len(r.MerkleBranch))
+	for i := range r.MerkleBranch {
+		node := &r.MerkleBranch[i]
+		hash := (*Bytes32)(&node.Hash)
+		if node.Left {
+			rJSON.MerkleBranch[i].Left = hash
+		} else {
+			rJSON.MerkleBranch[i].Right = hash
+		}
+	}
+	rJSON.EBlockKeyMR = &r.EBlockKeyMR
+	rJSON.DBlockKeyMR = &r.DBlockKeyMR
+	rJSON.DBlockHeight = r.DBlockHeight
+	return json.Marshal(rJSON)
+}
@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/merkle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReceipt returns a valid Receipt for an Entry as the second of three
+// EBlock objects, in an EBlock as the second of three DBlock EBlocks.
+func newReceipt(t *testing.T) Receipt {
+	h := func(s string) [32]byte { return sha256.Sum256([]byte(s)) }
+	minute := [32]byte{31: 1}
+	entryHash := h("entry")
+	ebObjects := [][32]byte{h("other entry"), entryHash, minute}
+	ebHeaderHash := h("eblock header")
+	ebBodyMR := merkle.BuildRoot(ebObjects)
+	ebKeyMR := merkle.HashNodes(&ebHeaderHash, &ebBodyMR)
+
+	dbLeaves := [][32]byte{h("a"), h("a keymr"),
+		h("chain"), ebKeyMR,
+		h("c"), h("c keymr")}
+	dbHeaderHash := h("dblock header")
+	dbBodyMR := merkle.BuildRoot(dbLeaves)
+	dbKeyMR := merkle.HashNodes(&dbHeaderHash, &dbBodyMR)
+
+	ebBranch, err := merkle.BuildBranch(ebObjects, 1)
+	require.NoError(t, err)
+	dbBranch, err := merkle.BuildBranch(dbLeaves, 3)
+	require.NoError(t, err)
+
+	branch := append(ebBranch, merkle.Node{Hash: ebHeaderHash, Left: true})
+	branch = append(branch, dbBranch...)
+	branch = append(branch, merkle.Node{Hash: dbHeaderHash, Left: true})
+	return Receipt{
+		EntryHash:    EntryHash(entryHash),
+		Timestamp:    time.Unix(1574976420, 0),
+		MerkleBranch: branch,
+		EBlockKeyMR:  KeyMR(ebKeyMR),
+		DBlockKeyMR:  KeyMR(dbKeyMR),
+		DBlockHeight: 1000,
+	}
+}
+
+func TestReceiptValidate(t *testing.T) {
+	assert := assert.New(t)
+	r := newReceipt(t)
+	assert.NoError(r.Validate())
+
+	invalid := r
+	invalid.EBlockKeyMR[0]++
+	assert.EqualError(invalid.Validate(),
+		"EBlock KeyMR not found in Merkle branch")
+
+	invalid = r
+	invalid.DBlockKeyMR[0]++
+	assert.EqualError(invalid.Validate(),
+		"Merkle branch does not lead to DBlock KeyMR")
+
+	invalid = r
+	invalid.EntryHash[0]++
+	assert.Error(invalid.Validate())
+}
+
+func TestReceiptJSON(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	r := newReceipt(t)
+
+	data, err := json.Marshal(r)
+	require.NoError(err)
+	var minimal Receipt
+	require.NoError(json.Unmarshal(data, &minimal))
+	assert.Equal(r.EntryHash, minimal.EntryHash)
+	assert.Equal(r.MerkleBranch, minimal.MerkleBranch)
+	assert.Equal(r.DBlockKeyMR, minimal.DBlockKeyMR)
+	assert.Equal(r.Timestamp.Unix(), minimal.Timestamp.Unix())
+
+	// Expand the minimal receipt into a full receipt, as returned by
+	// factomd, with both sides and the top of every node.
+	var full map[string]interface{}
+	require.NoError(json.Unmarshal(data, &full))
+	hash := [32]byte(r.EntryHash)
+	nodes := full["merklebranch"].([]interface{})
+	for i, node := range r.MerkleBranch {
+		n := nodes[i].(map[string]interface{})
+		self := Bytes32(hash).String()
+		if node.Left {
+			n["right"] = self
+		} else {
+			n["left"] = self
+		}
+		hash = merkle.ComputeBranchRoot(hash, []merkle.Node{node})
+		n["top"] = Bytes32(hash).String()
+	}
+	data, err = json.Marshal(full)
+	require.NoError(err)
+	var fromFull Receipt
+	require.NoError(json.Unmarshal(data, &fromFull))
+	assert.Equal(minimal.MerkleBranch, fromFull.MerkleBranch)
+
+	nodes[0].(map[string]interface{})["top"] = Bytes32{}.String()
+	data, err = json.Marshal(full)
+	require.NoError(err)
+	assert.EqualError(json.Unmarshal(data, &fromFull),
+		"merklebranch[0]: invalid top")
+
+	assert.EqualError(json.Unmarshal([]byte(`{}`), &fromFull),
+		"missing entryhash")
+}
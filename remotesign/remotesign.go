@@ -0,0 +1,341 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package remotesign isolates signing in a separate, hardened process or host.
+//
+// A Server holds private keys and serves a small JSON-RPC 2.0 protocol over
+// HTTP. A Client makes requests to a Server, and a Signer uses a Client to
+// implement factom.RCDSigner, for Factoid Transactions, and
+// factom.CommitSigner, for Entry commits, so that remote keys may be used
+// anywhere local keys are.
+//
+// The protocol has two methods. The "public-key" method returns the ed25519
+// public key for a key ID. The "sign" method returns the ed25519 signature of
+// a payload by the key with a key ID. By convention, the key ID is the public
+// FA or EC address of the key.
+//
+// Ed25519 signs the full message, not a hash of it, so the payload is sent
+// along with its sha256 hash. The Server rejects requests where the two do not
+// match, and passes the hash to Server.Authorize, so that signing policy and
+// audit logs may refer to the payload by hash. Factom signing payloads are
+// small, so sending them in full is not costly.
+package remotesign
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Method names of the protocol.
+const (
+	MethodPublicKey = "public-key"
+	MethodSign      = "sign"
+)
+
+// Error codes returned by a Server in addition to those defined by JSON-RPC
+// 2.0.
+const (
+	// ErrorCodeUnknownKey means that the Server does not hold a key with
+	// the requested key ID.
+	ErrorCodeUnknownKey jsonrpc2.ErrorCode = 1000 + iota
+
+	// ErrorCodeUnauthorized means that Server.Authorize refused the
+	// request.
+	ErrorCodeUnauthorized
+
+	// ErrorCodeSign means that the key failed to produce a signature.
+	ErrorCodeSign
+)
+
+// MaxPayloadSize is the largest payload that a Server signs. It is the
+// largest Factoid Transaction size.
+const MaxPayloadSize = 10240
+
+// maxRequestSize bounds the size of request bodies read by a Server. The
+// payload is hex encoded in the request.
+const maxRequestSize = 2*MaxPayloadSize + 4096
+
+// PublicKeyParams are the params of the "public-key" method.
+type PublicKeyParams struct {
+	KeyID string `json:"keyid"`
+}
+
+// PublicKeyResult is the result of the "public-key" method.
+type PublicKeyResult struct {
+	PublicKey factom.Bytes `json:"publickey"`
+}
+
+// SignParams are the params of the "sign" method.
+type SignParams struct {
+	KeyID string `json:"keyid"`
+
+	// Hash is the sha256 hash of Payload.
+	Hash factom.Bytes32 `json:"hash"`
+
+	// Payload is the message to sign.
+	Payload factom.Bytes `json:"payload"`
+}
+
+// NewSignParams returns SignParams for signing payload with the key with
+// keyID.
+func NewSignParams(keyID string, payload []byte) SignParams {
+	return SignParams{
+		KeyID:   keyID,
+		Hash:    sha256.Sum256(payload),
+		Payload: payload,
+	}
+}
+
+// SignResult is the result of the "sign" method.
+type SignResult struct {
+	Signature factom.Bytes `json:"signature"`
+}
+
+// Key is a private key held by a Server. It is implemented by
+// factom.FsAddress and factom.EsAddress, as well as by other signers, such as
+// frost.Signer or a hardware module.
+type Key interface {
+	// PublicKey returns the ed25519.PublicKey of the key.
+	PublicKey() ed25519.PublicKey
+
+	// Sign the msg. A nil or otherwise invalid signature indicates that
+	// signing failed.
+	Sign(msg []byte) []byte
+}
+
+// Server serves the signing protocol for the keys it holds.
+type Server struct {
+	// Keys maps key IDs to Keys. It is not safe to modify Keys while the
+	// Server is handling requests.
+	Keys map[string]Key
+
+	// Authorize, if not nil, is called before each signature is made. If
+	// it returns an error, the request is refused with
+	// ErrorCodeUnauthorized and the error message.
+	Authorize func(ctx context.Context, params SignParams) error
+
+	// Log is used by the JSON-RPC 2.0 handler. If nil, the default
+	// Logger from the log package is used.
+	Log jsonrpc2.Logger
+}
+
+// NewServer returns a Server holding keys, with the public FA address of each
+// factom.FsAddress, and the public EC address of each factom.EsAddress, as its
+// key ID. Other Keys must be added to Server.Keys directly.
+func NewServer(keys ...Key) (*Server, error) {
+	s := &Server{Keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		var keyID string
+		switch key := key.(type) {
+		case factom.FsAddress:
+			keyID = key.FAAddress().String()
+		case factom.EsAddress:
+			keyID = key.ECAddress().String()
+		default:
+			return nil, fmt.Errorf("unsupported key type: %T", key)
+		}
+		if _, ok := s.Keys[keyID]; ok {
+			return nil, fmt.Errorf("duplicate key: %v", keyID)
+		}
+		s.Keys[keyID] = key
+	}
+	return s, nil
+}
+
+// Handler returns an http.Handler that serves the protocol.
+func (s *Server) Handler() http.Handler {
+	handler := jsonrpc2.HTTPRequestHandler(jsonrpc2.MethodMap{
+		MethodPublicKey: s.publicKey,
+		MethodSign:      s.sign,
+	}, s.Log)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		handler(w, r)
+	})
+}
+
+func (s *Server) key(keyID string) (Key, error) {
+	key, ok := s.Keys[keyID]
+	if !ok {
+		return nil, jsonrpc2.NewError(ErrorCodeUnknownKey,
+			"Unknown key", keyID)
+	}
+	return key, nil
+}
+
+func (s *Server) publicKey(ctx context.Context, data json.RawMessage) interface{} {
+	var params PublicKeyParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return jsonrpc2.ErrorInvalidParams(err.Error())
+	}
+	key, err := s.key(params.KeyID)
+	if err != nil {
+		return err
+	}
+	return PublicKeyResult{PublicKey: factom.Bytes(key.PublicKey())}
+}
+
+func (s *Server) sign(ctx context.Context, data json.RawMessage) interface{} {
+	var params SignParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return jsonrpc2.ErrorInvalidParams(err.Error())
+	}
+	if len(params.Payload) == 0 {
+		return jsonrpc2.ErrorInvalidParams("missing payload")
+	}
+	if len(params.Payload) > MaxPayloadSize {
+		return jsonrpc2.ErrorInvalidParams(fmt.Sprintf(
+			"payload exceeds %v bytes", MaxPayloadSize))
+	}
+	if sha256.Sum256(params.Payload) != params.Hash {
+		return jsonrpc2.ErrorInvalidParams("hash does not match payload")
+	}
+	key, err := s.key(params.KeyID)
+	if err != nil {
+		return err
+	}
+	if s.Authorize != nil {
+		if err := s.Authorize(ctx, params); err != nil {
+			return jsonrpc2.NewError(ErrorCodeUnauthorized,
+				"Unauthorized", err.Error())
+		}
+	}
+	sig := key.Sign(params.Payload)
+	if !ed25519.Verify(key.PublicKey(), params.Payload, sig) {
+		return jsonrpc2.NewError(ErrorCodeSign, "Signing failed", nil)
+	}
+	return SignResult{Signature: sig}
+}
+
+// Client makes requests to a Server at URL using the HTTP and authentication
+// settings of the embedded jsonrpc2.Client.
+type Client struct {
+	URL string
+	jsonrpc2.Client
+}
+
+// NewClient returns a Client for the Server at url.
+func NewClient(url string) *Client {
+	return &Client{URL: url}
+}
+
+// PublicKey returns the ed25519.PublicKey of the key with keyID.
+func (c *Client) PublicKey(ctx context.Context,
+	keyID string) (ed25519.PublicKey, error) {
+	var res PublicKeyResult
+	if err := c.Request(ctx, c.URL, MethodPublicKey,
+		PublicKeyParams{KeyID: keyID}, &res); err != nil {
+		return nil, err
+	}
+	if len(res.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: %v",
+			len(res.PublicKey))
+	}
+	return ed25519.PublicKey(res.PublicKey), nil
+}
+
+// Sign returns the signature of msg by the key with keyID. The signature is
+// not verified. Use a Signer to verify it against the public key.
+func (c *Client) Sign(ctx context.Context,
+	keyID string, msg []byte) ([]byte, error) {
+	var res SignResult
+	if err := c.Request(ctx, c.URL, MethodSign,
+		NewSignParams(keyID, msg), &res); err != nil {
+		return nil, err
+	}
+	return res.Signature, nil
+}
+
+// Signer returns a Signer for the key with keyID, after looking up its public
+// key.
+func (c *Client) Signer(ctx context.Context, keyID string) (Signer, error) {
+	key, err := c.PublicKey(ctx, keyID)
+	if err != nil {
+		return Signer{}, err
+	}
+	return Signer{Client: c, KeyID: keyID, Key: key}, nil
+}
+
+// Signer signs with a remote key. It implements factom.RCDSigner, for Factoid
+// Transactions, and factom.CommitSigner, for Entry commits.
+type Signer struct {
+	Client *Client
+	KeyID  string
+
+	// Key is the ed25519.PublicKey of the remote key. Every signature
+	// returned by the Server is verified against it.
+	Key ed25519.PublicKey
+}
+
+var (
+	_ factom.RCDSigner    = Signer{}
+	_ factom.CommitSigner = Signer{}
+)
+
+// PublicKey returns s.Key.
+func (s Signer) PublicKey() ed25519.PublicKey {
+	return s.Key
+}
+
+// RCD returns the RCD for s.Key.
+func (s Signer) RCD() factom.RCD {
+	return append(factom.RCD{byte(factom.RCDType01)}, s.Key...)
+}
+
+// FAAddress returns the FAAddress for s.Key.
+func (s Signer) FAAddress() factom.FAAddress {
+	return s.RCD().FAAddress()
+}
+
+// ECAddress returns the ECAddress for s.Key.
+func (s Signer) ECAddress() factom.ECAddress {
+	var ec factom.ECAddress
+	copy(ec[:], s.Key)
+	return ec
+}
+
+// Sign msg using TrySign with context.Background(). If signing fails, nil is
+// returned. Use the Timeout of s.Client to bound the request.
+func (s Signer) Sign(msg []byte) []byte {
+	sig, _ := s.TrySign(context.Background(), msg)
+	return sig
+}
+
+// TrySign requests the signature of msg from the Server and verifies it
+// against s.Key.
+func (s Signer) TrySign(ctx context.Context, msg []byte) ([]byte, error) {
+	sig, err := s.Client.Sign(ctx, s.KeyID, msg)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(s.Key, msg, sig) {
+		return nil, fmt.Errorf("%v: invalid signature", s.KeyID)
+	}
+	return sig, nil
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package remotesign_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/remotesign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type badKey struct{ factom.FsAddress }
+
+func (k badKey) Sign(msg []byte) []byte {
+	return k.FsAddress.Sign(append(msg, 0))
+}
+
+func TestRemoteSign(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	srv, err := NewServer(fs, es)
+	require.NoError(err)
+	var authorized []factom.Bytes32
+	srv.Authorize = func(_ context.Context, params SignParams) error {
+		if string(params.Payload) == "refused" {
+			return fmt.Errorf("policy")
+		}
+		authorized = append(authorized, params.Hash)
+		return nil
+	}
+	bad, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	srv.Keys["bad"] = badKey{bad}
+
+	hs := httptest.NewServer(srv.Handler())
+	defer hs.Close()
+	c := NewClient(hs.URL)
+
+	signer, err := c.Signer(ctx, fs.FAAddress().String())
+	require.NoError(err)
+	assert.Equal(fs.RCD(), signer.RCD())
+	assert.Equal(fs.FAAddress(), signer.FAAddress())
+	msg := []byte("ledger")
+	sig := signer.Sign(msg)
+	assert.True(ed25519.Verify(fs.PublicKey(), msg, sig))
+	assert.Equal([]factom.Bytes32{NewSignParams("", msg).Hash}, authorized)
+
+	ecSigner, err := c.Signer(ctx, es.ECAddress().String())
+	require.NoError(err)
+	assert.Equal(es.ECAddress(), ecSigner.ECAddress())
+	sig, err = ecSigner.TrySign(ctx, msg)
+	require.NoError(err)
+	assert.True(ed25519.Verify(es.PublicKey(), msg, sig))
+
+	var jErr jsonrpc2.Error
+	_, err = c.PublicKey(ctx, "unknown")
+	require.True(errors.As(err, &jErr))
+	assert.Equal(ErrorCodeUnknownKey, jErr.Code)
+
+	_, err = signer.TrySign(ctx, []byte("refused"))
+	require.True(errors.As(err, &jErr))
+	assert.Equal(ErrorCodeUnauthorized, jErr.Code)
+	assert.Nil(signer.Sign([]byte("refused")))
+
+	params := NewSignParams(signer.KeyID, msg)
+	params.Payload = []byte("other")
+	err = c.Request(ctx, c.URL, MethodSign, params, &SignResult{})
+	require.True(errors.As(err, &jErr))
+	assert.Equal(jsonrpc2.ErrorCodeInvalidParams, jErr.Code)
+
+	_, err = c.Sign(ctx, signer.KeyID, make([]byte, MaxPayloadSize+1))
+	require.True(errors.As(err, &jErr))
+	assert.Equal(jsonrpc2.ErrorCodeInvalidParams, jErr.Code)
+
+	// The Server refuses to return invalid signatures.
+	_, err = c.Sign(ctx, "bad", msg)
+	require.True(errors.As(err, &jErr))
+	assert.Equal(ErrorCodeSign, jErr.Code)
+
+	// The Signer verifies signatures against its Key.
+	signer.Key = bad.PublicKey()
+	_, err = signer.TrySign(ctx, msg)
+	assert.EqualError(err, signer.KeyID+": invalid signature")
+
+	_, err = NewServer(fs, fs)
+	assert.EqualError(err, "duplicate key: "+fs.FAAddress().String())
+	_, err = NewServer(badKey{fs})
+	assert.EqualError(err, "unsupported key type: remotesign_test.badKey")
+}
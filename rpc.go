@@ -0,0 +1,277 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+)
+
+// requestID is the last JSON-RPC request ID that was issued. It is shared by
+// all Clients so that IDs are unique within the process.
+var requestID uint64
+
+// NextRequestID returns a new JSON-RPC request ID. IDs are monotonically
+// increasing and unique for the life of the process, so a Response can always
+// be correlated with the Request that produced it, even when requests are
+// batched, retried, or multiplexed over a single connection.
+func NextRequestID() uint64 {
+	return atomic.AddUint64(&requestID, 1)
+}
+
+// ErrorResponseID is returned when the "id" of a JSON-RPC Response does not
+// match the "id" of the Request that was sent.
+type ErrorResponseID struct {
+	Method string
+
+	// Expected is the ID of the Request.
+	Expected uint64
+
+	// Received is the raw JSON "id" of the Response.
+	Received json.RawMessage
+}
+
+// Error implements error.
+func (err ErrorResponseID) Error() string {
+	if len(err.Received) == 0 {
+		return fmt.Sprintf("%v: response id missing, expected %v",
+			err.Method, err.Expected)
+	}
+	return fmt.Sprintf("%v: response id mismatch: %s, expected %v",
+		err.Method, err.Received, err.Expected)
+}
+
+// DefaultMaxResponseSize is the default for Client.MaxResponseSize. It is
+// well above the size of the largest blocks factomd returns, but bounds the
+// memory that a misbehaving node can cause a Client to use.
+const DefaultMaxResponseSize = 32 << 20
+
+// ErrorResponseTooLarge is returned when the body of a response exceeds the
+// Client's MaxResponseSize.
+type ErrorResponseTooLarge struct {
+	Method string
+	Limit  int64
+}
+
+// Error implements error.
+func (err ErrorResponseTooLarge) Error() string {
+	return fmt.Sprintf("%v: response exceeds %v bytes", err.Method, err.Limit)
+}
+
+func (c *Client) maxResponseSize() int64 {
+	if c.MaxResponseSize == 0 {
+		return DefaultMaxResponseSize
+	}
+	return c.MaxResponseSize
+}
+
+// limitedReader is like io.LimitedReader, but returns ErrorResponseTooLarge
+// instead of io.EOF once more than N bytes would be read, so that a truncated
+// body cannot be mistaken for a complete one.
+type limitedReader struct {
+	R      io.Reader
+	N      int64
+	Method string
+	Limit  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.N < 0 {
+		return 0, ErrorResponseTooLarge{Method: l.Method, Limit: l.Limit}
+	}
+	if int64(len(p)) > l.N+1 {
+		p = p[:l.N+1]
+	}
+	n, err := l.R.Read(p)
+	l.N -= int64(n)
+	if l.N < 0 {
+		return 0, ErrorResponseTooLarge{Method: l.Method, Limit: l.Limit}
+	}
+	return n, err
+}
+
+// prefixWriter retains up to the first len(buf) bytes written to it.
+type prefixWriter struct {
+	buf []byte
+	n   int
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.n += copy(w.buf[w.n:], p)
+	return len(p), nil
+}
+
+// errorBodySize is the number of leading bytes of a response body that are
+// retained for jsonrpc2.ErrorUnexpectedHTTPResponse.
+const errorBodySize = 1024
+
+// request makes a JSON-RPC 2.0 request to url using the HTTP and BasicAuth
+// settings of jc. Unlike jsonrpc2.Client.Request, the ID is taken from
+// NextRequestID and the ID of the Response is verified against it.
+//
+// The response body is decoded as it is read, rather than buffered in full
+// first, and is limited to c.MaxResponseSize bytes.
+func (c *Client) request(ctx context.Context, jc *jsonrpc2.Client,
+	url, method string, params, result interface{}) error {
+	reqID := NextRequestID()
+
+	req := jsonrpc2.Request{ID: reqID, Method: method, Params: params}
+	if jc.DebugRequest {
+		if jc.Log == nil {
+			jc.Log = log.New(os.Stderr, "", 0)
+		}
+		jc.Log.Println(req)
+	}
+	reqData, err := req.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if c.CompressRequests {
+		if reqData, err = gzipData(reqData); err != nil {
+			return err
+		}
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url,
+		bytes.NewBuffer(reqData))
+	if err != nil {
+		return err
+	}
+	if ctx != nil {
+		httpReq = httpReq.WithContext(ctx)
+	}
+	httpReq.Header.Add("Content-Type", "application/json")
+	for k, v := range jc.Header {
+		httpReq.Header[http.CanonicalHeaderKey(k)] = v
+	}
+	if jc.BasicAuth {
+		httpReq.SetBasicAuth(jc.User, jc.Password)
+	}
+	if c.CompressRequests {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+	if !c.DisableCompression {
+		// Setting Accept-Encoding explicitly disables the transparent
+		// decompression of http.Transport, which is not used by all
+		// RoundTrippers, so responses are decompressed below.
+		httpReq.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	if err := c.Limiter.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.Limiter.Release()
+
+	httpRes, err := jc.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	var body io.Reader = httpRes.Body
+	if strings.EqualFold(httpRes.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("%v: invalid gzip response: %w",
+				method, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+	// The limit applies to the decompressed body.
+	if limit := c.maxResponseSize(); limit > 0 {
+		if httpRes.ContentLength > limit {
+			return ErrorResponseTooLarge{Method: method, Limit: limit}
+		}
+		body = &limitedReader{R: body, N: limit,
+			Method: method, Limit: limit}
+	}
+	prefix := prefixWriter{buf: make([]byte, errorBodySize)}
+	body = io.TeeReader(body, &prefix)
+	if jc.DebugRequest {
+		body = io.TeeReader(body, debugWriter{jc.Log})
+	}
+
+	var resID json.RawMessage
+	res := jsonrpc2.Response{Result: result, ID: &resID}
+	if err := json.NewDecoder(body).Decode(&res); err != nil {
+		var tooLarge ErrorResponseTooLarge
+		if errors.As(err, &tooLarge) {
+			return tooLarge
+		}
+		return jsonrpc2.ErrorUnexpectedHTTPResponse{UnmarshlingErr: err,
+			Body: prefix.buf[:prefix.n], Response: httpRes}
+	}
+
+	// A null ID is only valid on an error that prevented the server from
+	// reading the ID of the Request, in which case the error is more
+	// useful than the mismatch.
+	if res.HasError() && (resID == nil || string(resID) == "null") {
+		return res.Error
+	}
+	if string(resID) != strconv.FormatUint(reqID, 10) {
+		return ErrorResponseID{
+			Method: method, Expected: reqID, Received: resID}
+	}
+
+	if res.HasError() {
+		return res.Error
+	}
+
+	return nil
+}
+
+// debugWriter logs each chunk of a response body as it is read.
+type debugWriter struct{ jsonrpc2.Logger }
+
+func (w debugWriter) Write(p []byte) (int, error) {
+	w.Println("<--", string(p))
+	return len(p), nil
+}
+
+// gzipData returns data gzip compressed.
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
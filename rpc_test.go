@@ -0,0 +1,307 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRequestID(t *testing.T) {
+	const n = 1000
+	ids := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids <- NextRequestID()
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint64]struct{}, n)
+	for id := range ids {
+		assert.NotContains(t, seen, id)
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, n)
+}
+
+func TestRequestID(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Resp  func(id json.RawMessage) string
+		Check func(*testing.T, error)
+	}{{
+		Name: "valid",
+		Resp: func(id json.RawMessage) string {
+			return `{"jsonrpc":"2.0","result":"ok","id":` + string(id) + `}`
+		},
+	}, {
+		Name: "mismatch",
+		Resp: func(id json.RawMessage) string {
+			return `{"jsonrpc":"2.0","result":"ok","id":"other"}`
+		},
+		Check: func(t *testing.T, err error) {
+			idErr, ok := err.(ErrorResponseID)
+			require.True(t, ok, "%T", err)
+			assert.Equal(t, "test", idErr.Method)
+			assert.Equal(t, `"other"`, string(idErr.Received))
+		},
+	}, {
+		Name: "missing",
+		Resp: func(id json.RawMessage) string {
+			return `{"jsonrpc":"2.0","result":"ok"}`
+		},
+		Check: func(t *testing.T, err error) {
+			idErr, ok := err.(ErrorResponseID)
+			require.True(t, ok, "%T", err)
+			assert.Empty(t, idErr.Received)
+		},
+	}, {
+		Name: "null id with error",
+		Resp: func(id json.RawMessage) string {
+			return `{"jsonrpc":"2.0","id":null,` +
+				`"error":{"code":-32700,"message":"Parse error"}}`
+		},
+		Check: func(t *testing.T, err error) {
+			_, ok := err.(jsonrpc2.Error)
+			assert.True(t, ok, "%T", err)
+		},
+	}, {
+		Name: "error",
+		Resp: func(id json.RawMessage) string {
+			return `{"jsonrpc":"2.0","id":` + string(id) + `,` +
+				`"error":{"code":-32602,"message":"Invalid params"}}`
+		},
+		Check: func(t *testing.T, err error) {
+			_, ok := err.(jsonrpc2.Error)
+			assert.True(t, ok, "%T", err)
+		},
+	}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			var reqIDs []json.RawMessage
+			c := NewClient()
+			c.Factomd.Client = *NewTestClient(
+				func(req *http.Request) *http.Response {
+					var jReq struct {
+						ID json.RawMessage `json:"id"`
+					}
+					data, _ := ioutil.ReadAll(req.Body)
+					_ = json.Unmarshal(data, &jReq)
+					reqIDs = append(reqIDs, jReq.ID)
+					return &http.Response{
+						StatusCode: 200,
+						Body: ioutil.NopCloser(bytes.NewBufferString(
+							test.Resp(jReq.ID))),
+						Header: make(http.Header),
+					}
+				})
+
+			var result string
+			for i := 0; i < 2; i++ {
+				err := c.FactomdRequest(context.Background(),
+					"test", nil, &result)
+				if test.Check == nil {
+					require.NoError(t, err)
+					assert.Equal(t, "ok", result)
+					continue
+				}
+				require.Error(t, err)
+				test.Check(t, err)
+			}
+			require.Len(t, reqIDs, 2)
+			assert.NotEqual(t, reqIDs[0], reqIDs[1])
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	result := strings.Repeat("a", 1000)
+	var tests = []struct {
+		Name          string
+		Max           int64
+		ContentLength int64
+		TooLarge      bool
+	}{{
+		Name: "default",
+	}, {
+		Name: "unlimited",
+		Max:  -1,
+	}, {
+		Name: "within limit",
+		Max:  2000,
+	}, {
+		Name:     "too large",
+		Max:      500,
+		TooLarge: true,
+	}, {
+		Name:          "content length",
+		Max:           500,
+		ContentLength: 1500,
+		TooLarge:      true,
+	}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			c := NewClient()
+			c.MaxResponseSize = test.Max
+			c.Factomd.Client = *NewTestClient(
+				func(req *http.Request) *http.Response {
+					var jReq jsonrpc2.Request
+					reqData, _ := ioutil.ReadAll(req.Body)
+					_ = json.Unmarshal(reqData, &jReq)
+					data, _ := json.Marshal(jsonrpc2.Response{
+						Result: result, ID: jReq.ID})
+					return &http.Response{
+						StatusCode:    200,
+						ContentLength: test.ContentLength,
+						Body: ioutil.NopCloser(
+							bytes.NewBuffer(data)),
+						Header: make(http.Header),
+					}
+				})
+			var res string
+			err := c.FactomdRequest(context.Background(),
+				"test", nil, &res)
+			if test.TooLarge {
+				assert.EqualError(t, err, fmt.Sprintf(
+					"test: response exceeds %v bytes", test.Max))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, result, res)
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	result := strings.Repeat("a", 1000)
+	var tests = []struct {
+		Name               string
+		DisableCompression bool
+		CompressRequests   bool
+		Max                int64
+		TooLarge           bool
+	}{{
+		Name: "default",
+	}, {
+		Name:               "disabled",
+		DisableCompression: true,
+	}, {
+		Name:             "compress requests",
+		CompressRequests: true,
+	}, {
+		Name:     "decompressed size limited",
+		Max:      500,
+		TooLarge: true,
+	}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			c := NewClient()
+			c.DisableCompression = test.DisableCompression
+			c.CompressRequests = test.CompressRequests
+			c.MaxResponseSize = test.Max
+			c.Factomd.Client = *NewTestClient(
+				func(req *http.Request) *http.Response {
+					var body io.Reader = req.Body
+					if test.CompressRequests {
+						assert.Equal("gzip", req.Header.Get(
+							"Content-Encoding"))
+						gz, err := gzip.NewReader(body)
+						require.NoError(t, err)
+						body = gz
+					} else {
+						assert.Empty(req.Header.Get(
+							"Content-Encoding"))
+					}
+					var jReq jsonrpc2.Request
+					reqData, _ := ioutil.ReadAll(body)
+					require.NoError(t, json.Unmarshal(reqData, &jReq))
+					assert.Equal("test", jReq.Method)
+
+					data, _ := json.Marshal(jsonrpc2.Response{
+						Result: result, ID: jReq.ID})
+					header := make(http.Header)
+					if test.DisableCompression {
+						assert.Empty(req.Header.Get(
+							"Accept-Encoding"))
+					} else {
+						assert.Equal("gzip", req.Header.Get(
+							"Accept-Encoding"))
+						data, _ = gzipBytes(data)
+						assert.Less(len(data), len(result))
+						header.Set("Content-Encoding", "gzip")
+					}
+					return &http.Response{
+						StatusCode: 200,
+						Body: ioutil.NopCloser(
+							bytes.NewBuffer(data)),
+						Header: header,
+					}
+				})
+			var res string
+			err := c.FactomdRequest(context.Background(),
+				"test", nil, &res)
+			if test.TooLarge {
+				assert.EqualError(err, fmt.Sprintf(
+					"test: response exceeds %v bytes", test.Max))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(result, res)
+		})
+	}
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
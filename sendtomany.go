@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"fmt"
+	"time"
+)
+
+// SendToMany is a batch of payments from a single FAAddress to many
+// recipients in one Transaction, such as for payroll or airdrops.
+type SendToMany struct {
+	// FCTOutputs and ECOutputs are the requested amounts in factoshis
+	// for each recipient.
+	FCTOutputs []AddressAmount
+	ECOutputs  []AddressAmount
+
+	// DeductFee splits the fee evenly across the FCTOutputs, rather than
+	// adding it to the input. Any remainder of the split is deducted from
+	// the first FCTOutputs.
+	DeductFee bool
+}
+
+// Payment reports the effective amount paid to a single recipient of a
+// SendToMany Transaction.
+type Payment struct {
+	// Address is the human readable FA or EC address of the recipient.
+	Address string
+
+	// Requested is the requested amount in factoshis.
+	Requested uint64
+
+	// Amount is the output amount in factoshis, after any deducted fee.
+	Amount uint64
+
+	// Credits is the number of Entry Credits purchased by an EC output.
+	// Any remainder of Amount that does not purchase a whole Entry Credit
+	// is lost. It is zero for FCT outputs.
+	Credits uint64
+}
+
+// Build returns a Transaction paying all outputs of s from adr at the given
+// ecRate, in factoshis per Entry Credit, and the effective Payment to each
+// recipient, FCTOutputs first. The returned Transaction is ready to be signed
+// with Transaction.Sign using the RCDSigner for adr.
+//
+// Every FCT output must be non-zero, and every EC output must purchase at
+// least one Entry Credit. If s.DeductFee is set, every FCT output must cover
+// its share of the fee.
+func (s SendToMany) Build(adr FAAddress,
+	ecRate uint64) (Transaction, []Payment, error) {
+	numOutputs := len(s.FCTOutputs) + len(s.ECOutputs)
+	if numOutputs == 0 {
+		return Transaction{}, nil, fmt.Errorf("no outputs")
+	}
+	if len(s.FCTOutputs) > 256 || len(s.ECOutputs) > 256 {
+		return Transaction{}, nil, fmt.Errorf("too many inputs or outputs")
+	}
+	if ecRate == 0 {
+		return Transaction{}, nil, fmt.Errorf("invalid EC rate: 0")
+	}
+	if s.DeductFee && len(s.FCTOutputs) == 0 {
+		return Transaction{}, nil,
+			fmt.Errorf("cannot deduct fee without FCT outputs")
+	}
+
+	tx := Transaction{
+		TimestampSalt: time.Now(),
+		FCTOutputs:    append([]AddressAmount{}, s.FCTOutputs...),
+		ECOutputs:     append([]AddressAmount{}, s.ECOutputs...),
+	}
+
+	var total uint64
+	for i, output := range tx.FCTOutputs {
+		if output.Amount == 0 {
+			return Transaction{}, nil,
+				fmt.Errorf("FCTOutputs[%v]: zero amount", i)
+		}
+		if total+output.Amount < total {
+			return Transaction{}, nil,
+				fmt.Errorf("total output overflows")
+		}
+		total += output.Amount
+	}
+	for i, output := range tx.ECOutputs {
+		if output.Amount < ecRate {
+			return Transaction{}, nil, fmt.Errorf(
+				"ECOutputs[%v]: amount %v purchases no Entry Credits",
+				i, output.Amount)
+		}
+		if total+output.Amount < total {
+			return Transaction{}, nil,
+				fmt.Errorf("total output overflows")
+		}
+		total += output.Amount
+	}
+
+	tx.FCTInputs = []AddressAmount{{Amount: total, Address: adr[:]}}
+	fee, err := tx.RequiredFee(ecRate)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+
+	if s.DeductFee {
+		// The fee was computed with the full output amounts, which
+		// only shrink, so it is never less than what is required.
+		n := uint64(len(tx.FCTOutputs))
+		share, rem := fee/n, fee%n
+		for i := range tx.FCTOutputs {
+			output := &tx.FCTOutputs[i]
+			deduct := share
+			if uint64(i) < rem {
+				deduct++
+			}
+			if output.Amount <= deduct {
+				return Transaction{}, nil, fmt.Errorf(
+					"FCTOutputs[%v]: amount %v does not cover fee share %v",
+					i, output.Amount, deduct)
+			}
+			output.Amount -= deduct
+		}
+	} else {
+		// The input grows by the fee, which may grow the fee.
+		for {
+			if total+fee < total {
+				return Transaction{}, nil,
+					fmt.Errorf("total input overflows")
+			}
+			tx.FCTInputs[0].Amount = total + fee
+			required, err := tx.RequiredFee(ecRate)
+			if err != nil {
+				return Transaction{}, nil, err
+			}
+			if required <= fee {
+				break
+			}
+			fee = required
+		}
+	}
+
+	tx.Signatures = make([]RCDSignature, len(tx.FCTInputs))
+
+	payments := make([]Payment, 0, numOutputs)
+	for i, output := range tx.FCTOutputs {
+		payments = append(payments, Payment{
+			Address:   output.FAAddress().String(),
+			Requested: s.FCTOutputs[i].Amount,
+			Amount:    output.Amount,
+		})
+	}
+	for _, output := range tx.ECOutputs {
+		payments = append(payments, Payment{
+			Address:   output.ECAddress().String(),
+			Requested: output.Amount,
+			Amount:    output.Amount,
+			Credits:   output.Amount / ecRate,
+		})
+	}
+
+	return tx, payments, nil
+}
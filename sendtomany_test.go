@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendToManyBuild(t *testing.T) {
+	const ecRate = 1000
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	from := fs.FAAddress()
+	fa1, fa2 := FAAddress{1}, FAAddress{2}
+	ec := ECAddress{3}
+
+	for _, test := range []struct {
+		Name string
+		SendToMany
+		Amounts []uint64
+		Credits []uint64
+		Error   string
+	}{{
+		Name: "valid",
+		SendToMany: SendToMany{
+			FCTOutputs: []AddressAmount{
+				{Amount: 1e8, Address: fa1[:]},
+				{Amount: 2e8, Address: fa2[:]},
+			},
+			ECOutputs: []AddressAmount{
+				{Amount: 10*ecRate + 1, Address: ec[:]},
+			},
+		},
+		Amounts: []uint64{1e8, 2e8, 10*ecRate + 1},
+		Credits: []uint64{0, 0, 10},
+	}, {
+		Name: "valid, deduct fee",
+		SendToMany: SendToMany{
+			FCTOutputs: []AddressAmount{
+				{Amount: 1e8, Address: fa1[:]},
+				{Amount: 2e8, Address: fa2[:]},
+			},
+			DeductFee: true,
+		},
+		// 1 EC for size, 20 EC for outputs, 1 EC for the signature.
+		Amounts: []uint64{1e8 - 11000, 2e8 - 11000},
+		Credits: []uint64{0, 0},
+	}, {
+		Name:  "no outputs",
+		Error: "no outputs",
+	}, {
+		Name: "zero amount",
+		SendToMany: SendToMany{FCTOutputs: []AddressAmount{
+			{Amount: 1e8, Address: fa1[:]},
+			{Amount: 0, Address: fa2[:]},
+		}},
+		Error: "FCTOutputs[1]: zero amount",
+	}, {
+		Name: "EC output too small",
+		SendToMany: SendToMany{ECOutputs: []AddressAmount{
+			{Amount: ecRate - 1, Address: ec[:]},
+		}},
+		Error: "ECOutputs[0]: amount 999 purchases no Entry Credits",
+	}, {
+		Name: "deduct fee without FCT outputs",
+		SendToMany: SendToMany{
+			ECOutputs: []AddressAmount{
+				{Amount: ecRate, Address: ec[:]},
+			},
+			DeductFee: true,
+		},
+		Error: "cannot deduct fee without FCT outputs",
+	}, {
+		Name: "output does not cover fee share",
+		SendToMany: SendToMany{
+			FCTOutputs: []AddressAmount{
+				{Amount: 1e8, Address: fa1[:]},
+				{Amount: 100, Address: fa2[:]},
+			},
+			DeductFee: true,
+		},
+		Error: "FCTOutputs[1]: amount 100 does not cover fee share 11000",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			tx, payments, err := test.Build(from, ecRate)
+			if test.Error != "" {
+				assert.EqualError(err, test.Error)
+				return
+			}
+			require.NoError(err)
+
+			require.Len(payments, len(test.Amounts))
+			for i, payment := range payments {
+				assert.Equal(test.Amounts[i], payment.Amount)
+				assert.Equal(test.Credits[i], payment.Credits)
+			}
+			assert.Equal(fa1.String(), payments[0].Address)
+			assert.Equal(uint64(1e8), payments[0].Requested)
+
+			_, err = tx.Sign(fs)
+			require.NoError(err)
+			data, err := tx.MarshalBinary()
+			require.NoError(err)
+			var signed Transaction
+			require.NoError(signed.UnmarshalBinary(data))
+
+			fee, err := signed.RequiredFee(ecRate)
+			require.NoError(err)
+			assert.Equal(fee, signed.TotalBurn)
+		})
+	}
+}
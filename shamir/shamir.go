@@ -0,0 +1,305 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package shamir splits a Factom private key into Shamir secret shares, so
+// that custody of a backup can be distributed without any single share
+// revealing anything about the key.
+//
+// SplitKey splits an FsAddress into n Shares, any k of which recover it with
+// CombineKey. SplitEsKey and CombineEsKey do the same for an EsAddress. Each
+// byte of the 32 byte key is shared independently over GF(256).
+//
+// Each Share is encoded as a base58check string, beginning with "Fx" for Fs
+// keys and "Ex" for Es keys, so that typos are detected as each Share is
+// entered. Every Share also records k and a hash of the public address of the
+// key. This detects mixing Shares of different keys, and the recovered key is
+// checked against the hash, so a corrupt Share is never silently accepted.
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/Factom-Asset-Tokens/base58"
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Kind is the kind of key that a Share is part of.
+type Kind byte
+
+// Kinds of keys.
+const (
+	KindFs Kind = iota
+	KindEs
+)
+
+var prefixes = [...][2]byte{
+	KindFs: {0x2e, 0x00},
+	KindEs: {0x2a, 0xed},
+}
+
+const (
+	// EncodedLen is the length of all encoded Shares.
+	EncodedLen = 60
+
+	hashLen   = 4
+	secretLen = 32
+	bodyLen   = 1 + 1 + hashLen + secretLen
+)
+
+// Share is a single Shamir share of a private key.
+type Share struct {
+	Kind Kind
+
+	// Index is the x coordinate of the Share, from 1 to 255.
+	Index byte
+
+	// Threshold is the number of Shares required to recover the key.
+	Threshold byte
+
+	// AddressHash is the first 4 bytes of the double sha256 hash of the
+	// public address of the key.
+	AddressHash [hashLen]byte
+
+	Data [secretLen]byte
+}
+
+// SplitKey splits fs into n Shares, any k of which recover fs with CombineKey.
+func SplitKey(fs factom.FsAddress, n, k int) ([]Share, error) {
+	return split(KindFs, fs, fs.FAAddress().String(), n, k)
+}
+
+// SplitEsKey splits es into n Shares, any k of which recover es with
+// CombineEsKey.
+func SplitEsKey(es factom.EsAddress, n, k int) ([]Share, error) {
+	return split(KindEs, es, es.ECAddress().String(), n, k)
+}
+
+// CombineKey recovers an FsAddress from at least Threshold of its Shares.
+func CombineKey(shares []Share) (factom.FsAddress, error) {
+	secret, err := combine(KindFs, shares)
+	if err != nil {
+		return factom.FsAddress{}, err
+	}
+	fs := factom.FsAddress(secret)
+	if addressHash(fs.FAAddress().String()) != shares[0].AddressHash {
+		return factom.FsAddress{}, fmt.Errorf("recovered key does not " +
+			"match address hash: corrupt share")
+	}
+	return fs, nil
+}
+
+// CombineEsKey recovers an EsAddress from at least Threshold of its Shares.
+func CombineEsKey(shares []Share) (factom.EsAddress, error) {
+	secret, err := combine(KindEs, shares)
+	if err != nil {
+		return factom.EsAddress{}, err
+	}
+	es := factom.EsAddress(secret)
+	if addressHash(es.ECAddress().String()) != shares[0].AddressHash {
+		return factom.EsAddress{}, fmt.Errorf("recovered key does not " +
+			"match address hash: corrupt share")
+	}
+	return es, nil
+}
+
+func split(kind Kind, secret [secretLen]byte, adrStr string,
+	n, k int) ([]Share, error) {
+	if k < 2 || k > n {
+		return nil, fmt.Errorf("invalid threshold: %v of %v", k, n)
+	}
+	if n > 255 {
+		return nil, fmt.Errorf("too many shares: %v", n)
+	}
+
+	// coefficients[i] holds the random coefficients of degree 1 through
+	// k-1 of the polynomial for byte i of the secret.
+	coefficients := make([]byte, secretLen*(k-1))
+	if _, err := rand.Read(coefficients); err != nil {
+		return nil, err
+	}
+
+	hash := addressHash(adrStr)
+	shares := make([]Share, n)
+	for s := range shares {
+		share := &shares[s]
+		share.Kind = kind
+		share.Index = byte(s + 1)
+		share.Threshold = byte(k)
+		share.AddressHash = hash
+		for i := range secret {
+			// Evaluate the polynomial at Index using Horner's
+			// method.
+			var y byte
+			for j := k - 2; j >= 0; j-- {
+				y = gfMul(y, share.Index) ^ coefficients[i*(k-1)+j]
+			}
+			share.Data[i] = gfMul(y, share.Index) ^ secret[i]
+		}
+	}
+	return shares, nil
+}
+
+func combine(kind Kind, shares []Share) (secret [secretLen]byte, err error) {
+	if len(shares) == 0 {
+		err = fmt.Errorf("no shares")
+		return
+	}
+	first := shares[0]
+	seen := make(map[byte]struct{}, len(shares))
+	for _, share := range shares {
+		switch {
+		case share.Kind != kind:
+			err = fmt.Errorf("share %v: wrong kind of key", share.Index)
+		case share.Index == 0:
+			err = fmt.Errorf("share %v: invalid index", share.Index)
+		case share.Threshold != first.Threshold ||
+			share.AddressHash != first.AddressHash:
+			err = fmt.Errorf("share %v: belongs to a different key",
+				share.Index)
+		}
+		if err != nil {
+			return
+		}
+		if _, ok := seen[share.Index]; ok {
+			err = fmt.Errorf("share %v: duplicate", share.Index)
+			return
+		}
+		seen[share.Index] = struct{}{}
+	}
+	if len(shares) < int(first.Threshold) {
+		err = fmt.Errorf("insufficient shares: %v of %v",
+			len(shares), first.Threshold)
+		return
+	}
+	shares = shares[:first.Threshold]
+
+	// Lagrange interpolation at x = 0. In GF(256) subtraction is XOR.
+	for i, share := range shares {
+		var num, den byte = 1, 1
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			num = gfMul(num, other.Index)
+			den = gfMul(den, share.Index^other.Index)
+		}
+		basis := gfMul(num, gfInv(den))
+		for b := range secret {
+			secret[b] ^= gfMul(share.Data[b], basis)
+		}
+	}
+	return
+}
+
+// String encodes s as a base58check string.
+func (s Share) String() string {
+	text, _ := s.MarshalText()
+	return string(text)
+}
+
+// MarshalText encodes s as a base58check string.
+func (s Share) MarshalText() ([]byte, error) {
+	if int(s.Kind) >= len(prefixes) {
+		return nil, fmt.Errorf("invalid kind: %v", s.Kind)
+	}
+	body := make([]byte, 0, bodyLen)
+	body = append(body, s.Index, s.Threshold)
+	body = append(body, s.AddressHash[:]...)
+	body = append(body, s.Data[:]...)
+	prefix := prefixes[s.Kind]
+	return []byte(base58.CheckEncode(body, prefix[:]...)), nil
+}
+
+// Set decodes a base58check encoded Share into s.
+func (s *Share) Set(shareStr string) error {
+	if len(shareStr) != EncodedLen {
+		return fmt.Errorf("invalid length")
+	}
+	body, version, err := base58.CheckDecode(shareStr, 2)
+	if err != nil {
+		return err
+	}
+	if len(body) != bodyLen {
+		return fmt.Errorf("invalid length")
+	}
+	kind := -1
+	for k, prefix := range prefixes {
+		if string(version) == string(prefix[:]) {
+			kind = k
+		}
+	}
+	if kind < 0 {
+		return fmt.Errorf("invalid prefix")
+	}
+	if body[0] == 0 || body[1] < 2 {
+		return fmt.Errorf("invalid index or threshold")
+	}
+	s.Kind = Kind(kind)
+	s.Index, s.Threshold = body[0], body[1]
+	copy(s.AddressHash[:], body[2:])
+	copy(s.Data[:], body[2+hashLen:])
+	return nil
+}
+
+// UnmarshalText decodes a base58check encoded Share into s.
+func (s *Share) UnmarshalText(text []byte) error {
+	return s.Set(string(text))
+}
+
+// addressHash returns the first 4 bytes of the double sha256 hash of adrStr.
+func addressHash(adrStr string) (hash [hashLen]byte) {
+	h := sha256.Sum256([]byte(adrStr))
+	h = sha256.Sum256(h[:])
+	copy(hash[:], h[:])
+	return
+}
+
+// GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1, using log and exp
+// tables over the generator 3.
+var gfExp, gfLog = func() (exp [510]byte, log [256]byte) {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = x, x
+		log[x] = byte(i)
+		// Multiply x by 3.
+		hi := x & 0x80
+		x2 := x << 1
+		if hi != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+	return
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package shamir_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/shamir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShamir(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	shares, err := SplitKey(fs, 5, 3)
+	require.NoError(err)
+	require.Len(shares, 5)
+
+	// Every combination of 3 shares recovers the key.
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			for k := j + 1; k < 5; k++ {
+				key, err := CombineKey([]Share{
+					shares[k], shares[i], shares[j]})
+				require.NoError(err)
+				assert.Equal(fs, key)
+			}
+		}
+	}
+	key, err := CombineKey(shares)
+	require.NoError(err)
+	assert.Equal(fs, key)
+
+	// Shares survive encoding.
+	decoded := make([]Share, 3)
+	for i := range decoded {
+		str := shares[i].String()
+		assert.Len(str, EncodedLen)
+		assert.True(strings.HasPrefix(str, "Fx"), str)
+		require.NoError(decoded[i].Set(str))
+		assert.Equal(shares[i], decoded[i])
+	}
+	key, err = CombineKey(decoded)
+	require.NoError(err)
+	assert.Equal(fs, key)
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	esShares, err := SplitEsKey(es, 2, 2)
+	require.NoError(err)
+	assert.True(strings.HasPrefix(esShares[0].String(), "Ex"))
+	esKey, err := CombineEsKey(esShares)
+	require.NoError(err)
+	assert.Equal(es, esKey)
+
+	other, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	otherShares, err := SplitKey(other, 3, 3)
+	require.NoError(err)
+
+	corrupt := shares[1]
+	corrupt.Data[0]++
+
+	for _, test := range []struct {
+		Name   string
+		Shares []Share
+		Error  string
+	}{{
+		Name:  "none",
+		Error: "no shares",
+	}, {
+		Name:   "insufficient",
+		Shares: shares[:2],
+		Error:  "insufficient shares: 2 of 3",
+	}, {
+		Name:   "duplicate",
+		Shares: []Share{shares[0], shares[1], shares[1]},
+		Error:  "share 2: duplicate",
+	}, {
+		Name:   "different key",
+		Shares: []Share{shares[0], shares[1], otherShares[2]},
+		Error:  "share 3: belongs to a different key",
+	}, {
+		Name:   "wrong kind",
+		Shares: []Share{shares[0], esShares[1]},
+		Error:  "share 2: wrong kind of key",
+	}, {
+		Name:   "corrupt",
+		Shares: []Share{shares[0], corrupt, shares[2]},
+		Error:  "recovered key does not match address hash: corrupt share",
+	}} {
+		_, err := CombineKey(test.Shares)
+		assert.EqualError(err, test.Error, test.Name)
+	}
+
+	for _, test := range []struct{ N, K int }{{3, 1}, {2, 3}, {256, 2}} {
+		_, err := SplitKey(fs, test.N, test.K)
+		assert.Error(err)
+	}
+
+	var share Share
+	str := shares[0].String()
+	assert.EqualError(share.Set(str[1:]), "invalid length")
+	typo := []byte(str)
+	typo[20] = 'z'
+	if string(typo) == str {
+		typo[20] = 'y'
+	}
+	assert.Error(share.Set(string(typo)))
+}
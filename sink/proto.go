@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package sink
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// MarshalEventProto encodes ev as the protobuf message Event of the following
+// proto3 schema. Timestamps are unix seconds.
+//
+//	message Event {
+//	  string type = 1;
+//	  oneof event {
+//	    Block block = 2;
+//	    Entry entry = 3;
+//	    Balance balance = 4;
+//	    Ack ack = 5;
+//	  }
+//	}
+//	message Block {
+//	  uint32 height = 1;
+//	  bytes keymr = 2;
+//	  int64 timestamp = 3;
+//	  repeated EBlock eblocks = 4;
+//	}
+//	message EBlock {
+//	  bytes chainid = 1;
+//	  bytes keymr = 2;
+//	}
+//	message Entry {
+//	  uint32 height = 1;
+//	  bytes chainid = 2;
+//	  bytes entryhash = 3;
+//	  int64 timestamp = 4;
+//	  repeated bytes extids = 5;
+//	  bytes content = 6;
+//	}
+//	message Balance {
+//	  uint32 height = 1;
+//	  string address = 2;
+//	  string kind = 3;
+//	  bytes txid = 4;
+//	  uint64 amount = 5;
+//	}
+//	message Ack {
+//	  bytes entryhash = 1;
+//	  string status = 2;
+//	  string prev = 3;
+//	}
+func MarshalEventProto(ev factom.Event) ([]byte, error) {
+	var msg protoBuf
+	var field uint64
+	switch ev := ev.(type) {
+	case factom.BlockEvent:
+		field = 2
+		msg.uint(1, uint64(ev.DBlock.Height))
+		msg.bytes32(2, (*factom.Bytes32)(ev.DBlock.KeyMR))
+		msg.int(3, unix(ev.DBlock.Timestamp))
+		for _, eb := range ev.DBlock.EBlocks {
+			var eblock protoBuf
+			eblock.bytes32(1, eb.ChainID)
+			eblock.bytes32(2, (*factom.Bytes32)(eb.KeyMR))
+			msg.message(4, eblock)
+		}
+	case factom.EntryEvent:
+		field = 3
+		e := ev.Entry
+		msg.uint(1, uint64(ev.Height))
+		msg.bytes32(2, e.ChainID)
+		msg.bytes32(3, (*factom.Bytes32)(e.Hash))
+		msg.int(4, unix(e.Timestamp))
+		for _, extID := range e.ExtIDs {
+			// Repeated fields are encoded even if empty.
+			msg.tag(5, wireBytes)
+			msg.varint(uint64(len(extID)))
+			msg = append(msg, extID...)
+		}
+		msg.bytes(6, e.Content)
+	case factom.BalanceEvent:
+		field = 4
+		msg.uint(1, uint64(ev.Height))
+		msg.bytes(2, []byte(ev.Address.String()))
+		msg.bytes(3, []byte(ev.Kind.String()))
+		msg.bytes32(4, (*factom.Bytes32)(ev.Transaction.ID))
+		msg.uint(5, ev.Amount)
+	case factom.AckEvent:
+		field = 5
+		hash := factom.Bytes32(ev.Hash)
+		msg.bytes32(1, &hash)
+		msg.bytes(2, []byte(ev.Status))
+		msg.bytes(3, []byte(ev.Prev))
+	default:
+		return nil, fmt.Errorf("unsupported event type: %T", ev)
+	}
+	var event protoBuf
+	event.bytes(1, []byte(factom.EventType(ev)))
+	event.message(field, msg)
+	return event, nil
+}
+
+// unix returns the unix seconds of t, or 0 if t is the zero time.
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// Protobuf wire types.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// protoBuf appends proto3 fields, omitting fields with zero values.
+type protoBuf []byte
+
+func (b *protoBuf) varint(x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	*b = append(*b, buf[:n]...)
+}
+
+func (b *protoBuf) tag(field uint64, wireType uint64) {
+	b.varint(field<<3 | wireType)
+}
+
+func (b *protoBuf) uint(field uint64, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.tag(field, wireVarint)
+	b.varint(x)
+}
+
+// int appends an int64 field, which is encoded as its two's complement.
+func (b *protoBuf) int(field uint64, x int64) {
+	b.uint(field, uint64(x))
+}
+
+func (b *protoBuf) bytes(field uint64, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	b.tag(field, wireBytes)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *protoBuf) bytes32(field uint64, data *factom.Bytes32) {
+	if data == nil {
+		return
+	}
+	b.bytes(field, data[:])
+}
+
+// message appends msg as an embedded message. Unlike other fields, embedded
+// messages are present even if empty, as for a oneof.
+func (b *protoBuf) message(field uint64, msg protoBuf) {
+	b.tag(field, wireBytes)
+	b.varint(uint64(len(msg)))
+	*b = append(*b, msg...)
+}
@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package sink publishes the Events of a factom.EventBus to streaming systems,
+// such as Kafka and NATS, so that streaming architectures may be built on top
+// of Factom data.
+//
+// This package does not depend on any Kafka or NATS client. A NATSPublisher
+// wraps any NATSConn, such as a *nats.Conn from github.com/nats-io/nats.go,
+// and a KafkaPublisher calls a func that produces a KafkaMessage with any
+// Kafka client. Other systems may implement Publisher directly.
+//
+// Events are published to the topic "<Prefix>.<type>", where the type is the
+// factom.EventType, with a key that identifies what the Event is about: the
+// ChainID of an EntryEvent, the address of a BalanceEvent, the Entry hash of
+// an AckEvent, or the decimal height of a BlockEvent. Events with the same key
+// should be kept in order, such as by partitioning on the key.
+//
+// Events are encoded as JSON, with factom.MarshalEventJSON, or as protobuf,
+// with MarshalEventProto.
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// DefaultPrefix is the Sink.Prefix used if empty.
+const DefaultPrefix = "factom"
+
+// Encoding is the serialization of published Events.
+type Encoding int
+
+// Supported Encodings.
+const (
+	JSON Encoding = iota
+	Protobuf
+)
+
+// ContentType returns the MIME type of enc.
+func (enc Encoding) ContentType() string {
+	if enc == Protobuf {
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+// Marshal encodes ev with enc.
+func (enc Encoding) Marshal(ev factom.Event) ([]byte, error) {
+	switch enc {
+	case JSON:
+		return factom.MarshalEventJSON(ev)
+	case Protobuf:
+		return MarshalEventProto(ev)
+	}
+	return nil, fmt.Errorf("unsupported encoding: %v", int(enc))
+}
+
+// Message is a single published Event.
+type Message struct {
+	Topic       string
+	Key         string
+	Value       []byte
+	ContentType string
+}
+
+// Publisher publishes Messages to a streaming system.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Sink encodes Events and publishes them with Publisher.
+type Sink struct {
+	Publisher Publisher
+
+	// Prefix of all topics. If empty, DefaultPrefix is used.
+	Prefix string
+
+	Encoding Encoding
+}
+
+// Subscribe publishes all Events published on bus. Errors from Publish are
+// returned to the publisher of the Event, such as factom.EventSource.Run.
+// Call the returned func to unsubscribe.
+func (s *Sink) Subscribe(bus *factom.EventBus) (unsubscribe func()) {
+	return bus.OnEvent(func(ev factom.Event) error {
+		return s.Publish(context.Background(), ev)
+	})
+}
+
+// Publish encodes ev and publishes it.
+func (s *Sink) Publish(ctx context.Context, ev factom.Event) error {
+	value, err := s.Encoding.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return s.Publisher.Publish(ctx, Message{
+		Topic:       prefix + "." + factom.EventType(ev),
+		Key:         Key(ev),
+		Value:       value,
+		ContentType: s.Encoding.ContentType(),
+	})
+}
+
+// Key returns the key of ev. See the package documentation.
+func Key(ev factom.Event) string {
+	switch ev := ev.(type) {
+	case factom.BlockEvent:
+		return strconv.FormatUint(uint64(ev.DBlock.Height), 10)
+	case factom.EntryEvent:
+		if ev.Entry.ChainID == nil {
+			return ""
+		}
+		return ev.Entry.ChainID.String()
+	case factom.BalanceEvent:
+		return ev.Address.String()
+	case factom.AckEvent:
+		return factom.Bytes32(ev.Hash).String()
+	}
+	return ""
+}
+
+// NATSConn is the method of a *nats.Conn used by NATSPublisher.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher publishes each Message to the subject "<topic>.<key>", or
+// "<topic>" if the key is empty, so that subscribers may use wildcards such as
+// "factom.entry.>" or "factom.entry.<ChainID>".
+type NATSPublisher struct {
+	Conn NATSConn
+}
+
+var _ Publisher = NATSPublisher{}
+
+// Publish publishes msg with p.Conn.
+func (p NATSPublisher) Publish(_ context.Context, msg Message) error {
+	subject := msg.Topic
+	if msg.Key != "" {
+		subject += "." + msg.Key
+	}
+	return p.Conn.Publish(subject, msg.Value)
+}
+
+// KafkaPublisher publishes each Message with Produce, which should produce a
+// Kafka record with the Message Topic, Key, and Value, and a "content-type"
+// header with the ContentType, using any Kafka client, and return once the
+// record is acknowledged.
+type KafkaPublisher struct {
+	Produce func(ctx context.Context, msg Message) error
+}
+
+var _ Publisher = KafkaPublisher{}
+
+// Publish publishes msg with p.Produce.
+func (p KafkaPublisher) Publish(ctx context.Context, msg Message) error {
+	return p.Produce(ctx, msg)
+}
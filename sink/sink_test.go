@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package sink_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/sink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type natsConn map[string][]byte
+
+func (c natsConn) Publish(subject string, data []byte) error {
+	c[subject] = data
+	return nil
+}
+
+func TestNATS(t *testing.T) {
+	conn := make(natsConn)
+	s := Sink{Publisher: NATSPublisher{Conn: conn}}
+	var bus factom.EventBus
+	unsubscribe := s.Subscribe(&bus)
+	defer unsubscribe()
+
+	chainID := factom.Bytes32{1}
+	entry := factom.EntryEvent{Height: 10,
+		Entry: factom.Entry{ChainID: &chainID, Content: factom.Bytes("hi")}}
+	block := factom.BlockEvent{DBlock: factom.DBlock{Height: 10}}
+	require.NoError(t, bus.Publish(block))
+	require.NoError(t, bus.Publish(entry))
+
+	blockJSON, err := factom.MarshalEventJSON(block)
+	require.NoError(t, err)
+	entryJSON, err := factom.MarshalEventJSON(entry)
+	require.NoError(t, err)
+	assert.Equal(t, natsConn{
+		"factom.block.10":                  blockJSON,
+		"factom.entry." + chainID.String(): entryJSON,
+	}, conn)
+}
+
+func TestKafka(t *testing.T) {
+	var msgs []Message
+	errProduce := fmt.Errorf("produce")
+	s := Sink{
+		Publisher: KafkaPublisher{
+			Produce: func(ctx context.Context, msg Message) error {
+				msgs = append(msgs, msg)
+				return errProduce
+			}},
+		Prefix:   "chain",
+		Encoding: Protobuf,
+	}
+	var bus factom.EventBus
+	s.Subscribe(&bus)
+
+	ack := factom.AckEvent{Hash: factom.EntryHash{1}, Status: "1Minute"}
+	assert.Equal(t, errProduce, bus.Publish(ack))
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "chain.ack", msgs[0].Topic)
+	assert.Equal(t, factom.Bytes32{1}.String(), msgs[0].Key)
+	assert.Equal(t, "application/x-protobuf", msgs[0].ContentType)
+
+	value, err := MarshalEventProto(ack)
+	require.NoError(t, err)
+	assert.Equal(t, value, msgs[0].Value)
+}
+
+func TestMarshalEventProto(t *testing.T) {
+	hash := factom.EntryHash{1}
+	data, err := MarshalEventProto(factom.AckEvent{Hash: hash, Status: "1Minute"})
+	require.NoError(t, err)
+	ack := append([]byte{0x0a, 32}, hash[:]...)
+	ack = append(ack, 0x12, 7)
+	ack = append(ack, "1Minute"...)
+	expected := []byte{0x0a, 3, 'a', 'c', 'k', 0x2a, byte(len(ack))}
+	assert.Equal(t, append(expected, ack...), data)
+
+	chainID := factom.Bytes32{2}
+	ts := time.Unix(1500000000, 0)
+	data, err = MarshalEventProto(factom.EntryEvent{Height: 300,
+		Entry: factom.Entry{ChainID: &chainID, Timestamp: ts,
+			ExtIDs: []factom.Bytes{{}, factom.Bytes("a")}}})
+	require.NoError(t, err)
+	entry := []byte{0x08, 0xac, 0x02, 0x12, 32}
+	entry = append(entry, chainID[:]...)
+	entry = append(entry, 0x20, 0x80, 0xde, 0xa0, 0xcb, 0x05)
+	entry = append(entry, 0x2a, 0, 0x2a, 1, 'a')
+	expected = []byte{0x0a, 5, 'e', 'n', 't', 'r', 'y', 0x1a, byte(len(entry))}
+	assert.Equal(t, append(expected, entry...), data)
+
+	data, err = MarshalEventProto(factom.BlockEvent{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0a, 5, 'b', 'l', 'o', 'c', 'k', 0x12, 0}, data)
+}
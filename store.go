@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"sync"
+)
+
+// Store is a local store of the binary data of immutable factom objects, such
+// as Entries, EBlocks, and FBlocks, keyed by their hash or KeyMR.
+//
+// If the Client.Store is not nil, Entry.Get, EBlock.Get, and FBlock.Get read
+// through it, so any data already stored is never requested from factomd
+// again, and any data requested from factomd is stored once it has been
+// decoded and verified against its hash or KeyMR. This includes every
+// EBlock and Entry loaded by Chain.ScanBack and EBlock.GetAllEntries, and
+// every FBlock walked by Client.WatchAddress, so following the chain
+// populates the Store.
+//
+// Mutable lookups, such as chain heads or blocks by height, are never served
+// from a Store.
+type Store interface {
+	// Get returns the data stored for hash, or nil if none is stored.
+	Get(hash Bytes32) ([]byte, error)
+
+	// Put stores data for hash.
+	Put(hash Bytes32, data []byte) error
+}
+
+// MemoryStore is a Store and CursorStore held in memory. It is safe for
+// concurrent use. The zero value is ready to use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	data    map[Bytes32][]byte
+	cursors map[string]uint32
+}
+
+var _ Store = &MemoryStore{}
+var _ CursorStore = &MemoryStore{}
+
+// Get returns a copy of the data stored for hash, or nil if none is stored.
+func (s *MemoryStore) Get(hash Bytes32) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.data[hash]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte{}, data...), nil
+}
+
+// Put stores a copy of data for hash.
+func (s *MemoryStore) Put(hash Bytes32, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		s.data = make(map[Bytes32][]byte)
+	}
+	s.data[hash] = append([]byte{}, data...)
+	return nil
+}
+
+// GetCursor returns the position of the Cursor called name, or 0 if none is
+// stored.
+func (s *MemoryStore) GetCursor(name string) (uint32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursors[name], nil
+}
+
+// PutCursor stores the position of the Cursor called name.
+func (s *MemoryStore) PutCursor(name string, pos uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursors == nil {
+		s.cursors = make(map[string]uint32)
+	}
+	s.cursors[name] = pos
+	return nil
+}
+
+// Len returns the number of objects stored.
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+// getRawData returns the binary data of the object with the given hash from
+// the c.Store, if it is stored, or otherwise from factomd's "raw-data" API.
+// The returned boolean indicates whether the data came from the c.Store.
+//
+// Data from factomd is not added to the c.Store. The caller must call
+// putRawData only once the data has been decoded and verified against hash.
+func (c *Client) getRawData(ctx context.Context,
+	hash Bytes32) ([]byte, bool, error) {
+	if c.Store != nil {
+		data, err := c.Store.Get(hash)
+		if err != nil {
+			return nil, false, err
+		}
+		if data != nil {
+			return data, true, nil
+		}
+	}
+
+	params := struct {
+		Hash Bytes32 `json:"hash"`
+	}{Hash: hash}
+	var result struct {
+		Data Bytes `json:"data"`
+	}
+	if err := c.FactomdRequest(ctx, "raw-data", params, &result); err != nil {
+		return nil, false, err
+	}
+	return result.Data, false, nil
+}
+
+// putRawData adds data to the c.Store, if not nil.
+func (c *Client) putRawData(hash Bytes32, data []byte) error {
+	if c.Store == nil {
+		return nil
+	}
+	return c.Store.Put(hash, data)
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	assert := assert.New(t)
+	var s MemoryStore
+	data, err := s.Get(Bytes32{1})
+	assert.NoError(err)
+	assert.Nil(data)
+
+	orig := []byte("data")
+	require.NoError(t, s.Put(Bytes32{1}, orig))
+	orig[0] = 'x'
+	data, err = s.Get(Bytes32{1})
+	assert.NoError(err)
+	assert.Equal([]byte("data"), data)
+	data[0] = 'x'
+	data, _ = s.Get(Bytes32{1})
+	assert.Equal([]byte("data"), data)
+	assert.Equal(1, s.Len())
+}
+
+func TestStoreReadThrough(t *testing.T) {
+	ctx := context.Background()
+	chainID := ComputeChainID([]Bytes{Bytes("store")})
+	c := newMockChain(t, chainID,
+		[]Entry{{Content: Bytes("first")}},
+		[]Entry{{Content: Bytes("second")}, {Content: Bytes("third")}})
+	var requests int
+	transport := c.Factomd.Client.Transport
+	c.Factomd.Client.Transport = RoundTripFunc(
+		func(req *http.Request) *http.Response {
+			requests++
+			res, _ := transport.RoundTrip(req)
+			return res
+		})
+	store := new(MemoryStore)
+	c.Store = store
+	ch := Chain{ID: chainID}
+
+	var contents []string
+	require.NoError(t, ch.ScanBack(ctx, c, func(e Entry) (bool, error) {
+		contents = append(contents, string(e.Content))
+		return false, nil
+	}))
+	assert.Equal(t, []string{"third", "second", "first"}, contents)
+	// chain-head, and 2 EBlocks and 3 Entries.
+	assert.Equal(t, 6, requests)
+	assert.Equal(t, 5, store.Len())
+
+	// Only the chain-head is requested, everything else is stored.
+	requests = 0
+	entries, err := ch.GetAllEntries(ctx, c)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Bytes("first"), entries[0].Content)
+	assert.Equal(t, Bytes("third"), entries[2].Content)
+	assert.Equal(t, 1, requests)
+}
+
+func TestStoreCorruptData(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	chainID := ComputeChainID([]Bytes{Bytes("store")})
+	valid := Entry{ChainID: &chainID, ExtIDs: []Bytes{},
+		Content: Bytes("valid")}
+	data, err := valid.MarshalBinary()
+	require.NoError(err)
+	hash := ComputeEntryHash(data)
+	corrupt := append(Bytes{}, data...)
+	corrupt[len(corrupt)-1]++
+
+	result := map[string]interface{}{"data": corrupt}
+	c := newMockClient(t, map[string]interface{}{"raw-data": result})
+	store := new(MemoryStore)
+	c.Store = store
+
+	e := Entry{Hash: &hash}
+	assert.EqualError(e.Get(ctx, c), "invalid hash")
+	keyMR := KeyMR(hash)
+	eb := EBlock{KeyMR: &keyMR}
+	assert.Error(eb.Get(ctx, c))
+	fb := FBlock{KeyMR: &keyMR}
+	assert.Error(fb.Get(ctx, c))
+	assert.Equal(0, store.Len())
+
+	// Once factomd returns valid data, it is stored.
+	result["data"] = Bytes(data)
+	e = Entry{Hash: &hash}
+	require.NoError(e.Get(ctx, c))
+	assert.Equal(Bytes("valid"), e.Content)
+	assert.Equal(1, store.Len())
+	stored, err := store.Get(Bytes32(hash))
+	require.NoError(err)
+	assert.Equal(data, stored)
+}
@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package supply computes Factoid supply and Entry Credit statistics by
+// replaying FBlocks and ECBlocks in order.
+//
+// Unlike package ledger, which tracks the balance of every address, a Tracker
+// only keeps running totals, so it is cheap to keep up to date and, with
+// KeepHistory, to answer the statistics at any past height.
+package supply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/known"
+)
+
+// BurnAddress is the default burn address, known.FCTBurnAddress(). Factoshis
+// sent to it are counted as Burned.
+var BurnAddress = known.FCTBurnAddress()
+
+// Stats are the running totals after all blocks below Height.
+type Stats struct {
+	// Height is the height of the next blocks to be applied.
+	Height uint32 `json:"height"`
+
+	// Minted is the total factoshis created by coinbase transactions.
+	// Fees is the total factoshis burned by transaction fees. Converted
+	// is the total factoshis burned to purchase Entry Credits. Burned is
+	// the total factoshis sent to a burn address.
+	Minted    uint64 `json:"minted"`
+	Fees      uint64 `json:"fees"`
+	Converted uint64 `json:"converted"`
+	Burned    uint64 `json:"burned"`
+
+	// ECCreated is the total Entry Credits purchased. ECConsumed is the
+	// total Entry Credits spent on valid commits.
+	ECCreated  uint64 `json:"eccreated"`
+	ECConsumed uint64 `json:"ecconsumed"`
+}
+
+// Circulating returns the factoshis that may still be spent, which is all
+// minted factoshis less those burned by fees, Entry Credit purchases and
+// burn addresses.
+func (s Stats) Circulating() uint64 {
+	return s.Minted - s.Fees - s.Converted - s.Burned
+}
+
+// ECOutstanding returns the Entry Credits that have been purchased but not
+// yet consumed.
+func (s Stats) ECOutstanding() uint64 {
+	return s.ECCreated - s.ECConsumed
+}
+
+// Tracker maintains Stats by applying the FBlock and ECBlock of each height.
+//
+// A Tracker is not safe for concurrent use.
+type Tracker struct {
+	Stats
+
+	// BurnAddresses are the FAAddresses whose outputs are counted as
+	// Burned. If nil, BurnAddress is used.
+	BurnAddresses map[factom.FAAddress]struct{} `json:"burnaddresses,omitempty"`
+
+	// KeepHistory enables recording the Stats after every height so that
+	// At may be used. It must be set before the first blocks are applied
+	// for the history to be complete.
+	KeepHistory bool    `json:"keephistory"`
+	History     []Stats `json:"history,omitempty"`
+}
+
+func (t *Tracker) isBurn(adr factom.FAAddress) bool {
+	if t.BurnAddresses == nil {
+		return adr == BurnAddress
+	}
+	_, ok := t.BurnAddresses[adr]
+	return ok
+}
+
+// Apply updates the Stats with fb and ecb, which must both be at t.Height. If
+// any Transaction is invalid, an error is returned and t is not modified.
+func (t *Tracker) Apply(fb factom.FBlock, ecb factom.ECBlock) error {
+	if fb.Height != t.Height || ecb.Height != t.Height {
+		return fmt.Errorf("unexpected block heights: %v, %v, expected %v",
+			fb.Height, ecb.Height, t.Height)
+	}
+
+	s := t.Stats
+	for i, tx := range fb.Transactions {
+		var totalIn, totalFCTOut, totalECOut uint64
+		for _, input := range tx.FCTInputs {
+			totalIn += input.Amount
+		}
+		for _, output := range tx.FCTOutputs {
+			totalFCTOut += output.Amount
+			if t.isBurn(output.FAAddress()) {
+				s.Burned += output.Amount
+			}
+		}
+		for _, output := range tx.ECOutputs {
+			if fb.ECExchangeRate == 0 {
+				return fmt.Errorf("tx %v: invalid EC exchange rate", i)
+			}
+			totalECOut += output.Amount
+			s.ECCreated += output.Amount / fb.ECExchangeRate
+		}
+
+		if len(tx.FCTInputs) == 0 {
+			if totalECOut > 0 {
+				return fmt.Errorf("tx %v: coinbase with ECOutputs", i)
+			}
+			s.Minted += totalFCTOut
+			continue
+		}
+		totalOut := totalFCTOut + totalECOut
+		if totalIn < totalOut {
+			return fmt.Errorf("tx %v: outputs exceed inputs", i)
+		}
+		s.Fees += totalIn - totalOut
+		s.Converted += totalECOut
+	}
+	for _, cmt := range ecb.Commits {
+		if cmt.Valid() == nil {
+			s.ECConsumed += uint64(cmt.Cost)
+		}
+	}
+	s.Height++
+
+	t.Stats = s
+	if t.KeepHistory {
+		t.History = append(t.History, s)
+	}
+	return nil
+}
+
+// Update uses c to load and Apply the FBlocks and ECBlocks from t.Height up to
+// and including height. The blocks applied before any error remain applied.
+func (t *Tracker) Update(ctx context.Context, c *factom.Client, height uint32) error {
+	for t.Height <= height {
+		fb := factom.FBlock{Height: t.Height}
+		if err := fb.Get(ctx, c); err != nil {
+			return err
+		}
+		ecb := factom.ECBlock{Height: t.Height}
+		if err := ecb.Get(ctx, c); err != nil {
+			return err
+		}
+		if err := t.Apply(fb, ecb); err != nil {
+			return err
+		}
+		if t.Height == 0 {
+			// Height overflowed.
+			break
+		}
+	}
+	return nil
+}
+
+// At returns the Stats after the blocks at height were applied. KeepHistory
+// must be set.
+func (t *Tracker) At(height uint32) (Stats, error) {
+	if !t.KeepHistory {
+		return Stats{}, fmt.Errorf("history not kept")
+	}
+	if height >= t.Height {
+		return Stats{}, fmt.Errorf("height not yet applied: %v", height)
+	}
+	start := t.Height - uint32(len(t.History))
+	if height < start {
+		return Stats{}, fmt.Errorf("height before history: %v", height)
+	}
+	return t.History[height-start], nil
+}
+
+// Save writes the JSON encoded state of t to w.
+func (t *Tracker) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+// Load reads a Tracker previously written by Save from r.
+func Load(r io.Reader) (*Tracker, error) {
+	var t Tracker
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
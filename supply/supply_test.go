@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package supply_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/supply"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func amount(adr [32]byte, amount uint64) factom.AddressAmount {
+	return factom.AddressAmount{Address: adr[:], Amount: amount}
+}
+
+func TestTracker(t *testing.T) {
+	faA := factom.FAAddress{1}
+	ecA := factom.ECAddress{2}
+	fblocks := []factom.FBlock{{
+		Height: 0,
+		Transactions: []factom.Transaction{
+			{FCTOutputs: []factom.AddressAmount{amount(faA, 1000)}},
+		},
+	}, {
+		Height:         1,
+		ECExchangeRate: 10,
+		Transactions: []factom.Transaction{{
+			FCTInputs: []factom.AddressAmount{amount(faA, 600)},
+			FCTOutputs: []factom.AddressAmount{
+				amount(BurnAddress, 100)},
+			ECOutputs: []factom.AddressAmount{amount(ecA, 450)},
+		}},
+	}}
+	ecblocks := []factom.ECBlock{{Height: 0}, {
+		Height: 1,
+		Commits: []factom.Commit{
+			{Cost: 11, ValidCost: true, ValidSignature: true},
+			{Cost: 1, ValidCost: true},
+		},
+	}}
+
+	tr := Tracker{KeepHistory: true}
+	for i := range fblocks {
+		require.NoError(t, tr.Apply(fblocks[i], ecblocks[i]))
+	}
+	assert := assert.New(t)
+	assert.Equal(Stats{Height: 2, Minted: 1000, Fees: 50, Converted: 450,
+		Burned: 100, ECCreated: 45, ECConsumed: 11}, tr.Stats)
+	assert.Equal(uint64(400), tr.Circulating())
+	assert.Equal(uint64(34), tr.ECOutstanding())
+
+	s, err := tr.At(0)
+	require.NoError(t, err)
+	assert.Equal(uint64(1000), s.Circulating())
+	_, err = tr.At(2)
+	assert.EqualError(err, "height not yet applied: 2")
+
+	assert.EqualError(tr.Apply(fblocks[0], ecblocks[0]),
+		"unexpected block heights: 0, 0, expected 2")
+
+	var buf bytes.Buffer
+	require.NoError(t, tr.Save(&buf))
+	loaded, err := Load(&buf)
+	require.NoError(t, err)
+	assert.Equal(&tr, loaded)
+}
+
+func TestBurnAddress(t *testing.T) {
+	assert.Equal(t, "FA1zT4aFpEvcnPqPCigB3fvGu4Q4mTXY22iiuV69DqE1pNhdF2MC",
+		BurnAddress.String())
+}
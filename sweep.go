@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NewConsolidation returns a Transaction that drains the balances of all
+// inputs into a single FCTOutput to adr, less the fee required at ecRate, in
+// factoshis per Entry Credit. Inputs with a zero Amount are omitted. The
+// returned Transaction is ready to be signed with Transaction.Sign using an
+// RCDSigner for each of its FCTInputs, in order.
+//
+// The fee is computed as if the entire balance were output, so it is never
+// less than what factomd requires.
+func NewConsolidation(inputs []AddressAmount, adr FAAddress,
+	ecRate uint64) (Transaction, error) {
+	tx := Transaction{TimestampSalt: time.Now()}
+
+	seen := make(map[FAAddress]struct{}, len(inputs))
+	var total uint64
+	for _, input := range inputs {
+		if input.Amount == 0 {
+			continue
+		}
+		fa := input.FAAddress()
+		if _, ok := seen[fa]; ok {
+			return Transaction{}, fmt.Errorf("duplicate input: %v", fa)
+		}
+		seen[fa] = struct{}{}
+		if total+input.Amount < total {
+			return Transaction{}, fmt.Errorf("total input overflows")
+		}
+		total += input.Amount
+		tx.FCTInputs = append(tx.FCTInputs, input)
+	}
+	if len(tx.FCTInputs) == 0 {
+		return Transaction{}, fmt.Errorf("no funded inputs")
+	}
+	if len(tx.FCTInputs) > 256 {
+		return Transaction{}, fmt.Errorf("too many inputs or outputs")
+	}
+
+	tx.FCTOutputs = []AddressAmount{{Amount: total, Address: adr[:]}}
+	fee, err := tx.RequiredFee(ecRate)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if fee >= total {
+		return Transaction{}, fmt.Errorf(
+			"insufficient balance: %v, fee: %v", total, fee)
+	}
+	tx.FCTOutputs[0].Amount = total - fee
+
+	tx.Signatures = make([]RCDSignature, len(tx.FCTInputs))
+
+	return tx, nil
+}
+
+// SweepFCT transfers the entire Factoid balance of fromKeys to adr in a single
+// Transaction, less the fee at the current Entry Credit rate. Addresses with
+// no balance are skipped.
+//
+// The Transaction is signed with fromKeys, but is not submitted. The signed
+// Transaction and its binary data are returned, and the data may be submitted
+// with Client.FactoidSubmit.
+func SweepFCT(ctx context.Context, c *Client,
+	fromKeys []FsAddress, adr FAAddress) (Transaction, []byte, error) {
+	inputs := make([]AddressAmount, len(fromKeys))
+	signers := make(map[FAAddress]RCDSigner, len(fromKeys))
+	for i, fs := range fromKeys {
+		balance, err := fs.GetBalance(ctx, c)
+		if err != nil {
+			return Transaction{}, nil, err
+		}
+		fa := fs.FAAddress()
+		inputs[i] = AddressAmount{Amount: balance, Address: fa[:]}
+		signers[fa] = fs
+	}
+
+	ecRate, err := c.GetECRate(ctx)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+
+	tx, err := NewConsolidation(inputs, adr, ecRate)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+
+	signingSet := make([]RCDSigner, len(tx.FCTInputs))
+	for i, input := range tx.FCTInputs {
+		signingSet[i] = signers[input.FAAddress()]
+	}
+	data, err := tx.Sign(signingSet...)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	return tx, data, nil
+}
@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConsolidation(t *testing.T) {
+	const ecRate = 1000
+	var fas [3]FAAddress
+	for i := range fas {
+		fs, err := GenerateFsAddress()
+		require.NoError(t, err)
+		fas[i] = fs.FAAddress()
+	}
+	to := fas[2]
+
+	for _, test := range []struct {
+		Name   string
+		Inputs []AddressAmount
+		Count  int
+		Total  uint64
+		Error  string
+	}{{
+		Name: "valid",
+		Inputs: []AddressAmount{
+			{Amount: 5e8, Address: fas[0][:]},
+			{Amount: 0, Address: fas[2][:]},
+			{Amount: 3e8, Address: fas[1][:]},
+		},
+		Count: 2,
+		Total: 8e8,
+	}, {
+		Name:   "no funded inputs",
+		Inputs: []AddressAmount{{Amount: 0, Address: fas[0][:]}},
+		Error:  "no funded inputs",
+	}, {
+		Name: "duplicate input",
+		Inputs: []AddressAmount{
+			{Amount: 5e8, Address: fas[0][:]},
+			{Amount: 3e8, Address: fas[0][:]},
+		},
+		Error: "duplicate input: " + fas[0].String(),
+	}, {
+		Name:   "insufficient balance",
+		Inputs: []AddressAmount{{Amount: 100, Address: fas[0][:]}},
+		Error:  "insufficient balance: 100, fee: 12000",
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			tx, err := NewConsolidation(test.Inputs, to, ecRate)
+			if test.Error != "" {
+				assert.EqualError(err, test.Error)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(tx.IsPopulated())
+			assert.Len(tx.FCTInputs, test.Count)
+			require.Len(t, tx.FCTOutputs, 1)
+			assert.Equal(to, tx.FCTOutputs[0].FAAddress())
+
+			// Unsigned, RequiredFee assumes RCDType01 signatures.
+			tx.Signatures = nil
+			fee, err := tx.RequiredFee(ecRate)
+			require.NoError(t, err)
+			assert.Equal(test.Total-fee, tx.FCTOutputs[0].Amount)
+		})
+	}
+}
+
+func TestSweepFCT(t *testing.T) {
+	const ecRate = 1000
+	var fss [3]FsAddress
+	balances := make(map[string]uint64)
+	for i := range fss {
+		fs, err := GenerateFsAddress()
+		require.NoError(t, err)
+		fss[i] = fs
+		balances[fs.FAAddress().String()] = uint64(i) * 1e8
+	}
+	to := FAAddress{1}
+
+	c := NewClient()
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		var jReq jsonrpc2.Request
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		var result interface{}
+		switch jReq.Method {
+		case "factoid-balance":
+			var params struct{ Address string }
+			data, _ := json.Marshal(jReq.Params)
+			_ = json.Unmarshal(data, &params)
+			result = map[string]uint64{"balance": balances[params.Address]}
+		case "entry-credit-rate":
+			result = map[string]uint64{"rate": ecRate}
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: result,
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+	c.Factomd.Client = *httpClient
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tx, data, err := SweepFCT(context.Background(), c, fss[:], to)
+	require.NoError(err)
+
+	var signed Transaction
+	require.NoError(signed.UnmarshalBinary(data))
+	assert.Equal(tx.ID, signed.ID)
+
+	// The empty address is skipped.
+	require.Len(signed.FCTInputs, 2)
+	assert.Equal(fss[1].FAAddress(), signed.FCTInputs[0].FAAddress())
+	assert.Equal(fss[2].FAAddress(), signed.FCTInputs[1].FAAddress())
+	assert.Equal(uint64(3e8), signed.TotalIn)
+
+	require.Len(signed.FCTOutputs, 1)
+	assert.Equal(to, signed.FCTOutputs[0].FAAddress())
+
+	fee, err := signed.RequiredFee(ecRate)
+	require.NoError(err)
+	assert.Equal(fee, signed.TotalBurn)
+
+	_, _, err = SweepFCT(context.Background(), c, fss[:1], to)
+	assert.EqualError(err, "no funded inputs")
+}
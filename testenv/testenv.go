@@ -0,0 +1,356 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package testenv runs a disposable single node factomd, and optionally
+// factom-walletd, in Docker for integration tests.
+//
+// The factomd node is started on the LOCAL network, where the genesis Factoid
+// Block pays out to GenesisFsAddress, which may be used to fund test
+// addresses. This removes the need for tests to depend on external courtesy
+// nodes.
+//
+// Docker is driven through the docker command line client, which must be in
+// the PATH or set in Config.Docker. A typical TestMain looks like:
+//
+//	var env *testenv.Env
+//
+//	func TestMain(m *testing.M) {
+//	        cfg := testenv.Config{}
+//	        if !testenv.Available(cfg) {
+//	                os.Exit(m.Run())
+//	        }
+//	        var err error
+//	        env, err = testenv.Start(context.Background(), cfg)
+//	        if err != nil {
+//	                log.Fatal(err)
+//	        }
+//	        code := m.Run()
+//	        env.Close()
+//	        os.Exit(code)
+//	}
+package testenv
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// GenesisFsAddress is the private Factoid address funded by the genesis
+// Factoid Block of the LOCAL network.
+const GenesisFsAddress = "Fs3E9gV6DXsYzf7Fqx1fVBQPQXV695eP3k5XbmHEZVRLkMdD9qCK"
+
+// Default Docker images.
+const (
+	FactomdImageDefault = "factominc/factomd:v6.7.0-alpine"
+	WalletdImageDefault = "factominc/factom-walletd:v2.2.14-alpine"
+)
+
+// Config configures the containers started by Start. The zero value is valid
+// and starts only factomd with the default image.
+type Config struct {
+	// Docker is the path to the docker binary. If empty, "docker" is
+	// looked up in the PATH.
+	Docker string
+
+	// FactomdImage and WalletdImage override the default images.
+	FactomdImage string
+	WalletdImage string
+
+	// Walletd, if true, also starts factom-walletd, connected to factomd.
+	Walletd bool
+
+	// BlockTime is the DBlock period of factomd. It is rounded down to
+	// whole seconds. If zero, 10 seconds is used.
+	BlockTime time.Duration
+
+	// ReadyTimeout is how long to wait for the API of each container to
+	// respond. If zero, 2 minutes is used.
+	ReadyTimeout time.Duration
+}
+
+// Env is a running test environment. Call Close to tear it down.
+type Env struct {
+	// Client is configured with the endpoints of the containers and the
+	// factom.Localnet Network.
+	Client *factom.Client
+
+	// Genesis is GenesisFsAddress.
+	Genesis factom.FsAddress
+
+	docker     string
+	network    string
+	containers []string
+}
+
+// Available returns true if the docker client named by cfg.Docker can reach a
+// Docker daemon. Pass the same Config that will be given to Start.
+func Available(cfg Config) bool {
+	cfg.setDefaults()
+	return exec.Command(cfg.Docker, "version").Run() == nil
+}
+
+// Start starts the containers described by cfg and waits for their APIs to be
+// ready. If any step fails, everything already started is torn down.
+func Start(ctx context.Context, cfg Config) (_ *Env, err error) {
+	cfg.setDefaults()
+	env := Env{docker: cfg.Docker, Client: factom.Localnet().NewClient()}
+	if err := env.Genesis.Set(GenesisFsAddress); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			env.Close()
+		}
+	}()
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := env.run(ctx, "network", "create", name); err != nil {
+		return nil, err
+	}
+	env.network = name
+
+	factomd := name + "-factomd"
+	if err := env.start(ctx, factomd, cfg.FactomdImage, 8088,
+		"-network=LOCAL",
+		fmt.Sprintf("-blktime=%v", int(cfg.BlockTime/time.Second)),
+		"-startdelay=0",
+		"-sim_stdin=false",
+		"-checkheads=false",
+	); err != nil {
+		return nil, err
+	}
+	if env.Client.FactomdServer, err = env.url(ctx, factomd, 8088); err != nil {
+		return nil, err
+	}
+
+	if cfg.Walletd {
+		walletd := name + "-walletd"
+		if err := env.start(ctx, walletd, cfg.WalletdImage, 8089,
+			"-s", factomd+":8088"); err != nil {
+			return nil, err
+		}
+		if env.Client.WalletdServer, err =
+			env.url(ctx, walletd, 8089); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ReadyTimeout)
+	defer cancel()
+	if err := env.waitFactomd(ctx); err != nil {
+		return nil, fmt.Errorf("factomd: %w", err)
+	}
+	if cfg.Walletd {
+		if err := env.waitWalletd(ctx); err != nil {
+			return nil, fmt.Errorf("factom-walletd: %w", err)
+		}
+	}
+	return &env, nil
+}
+
+func (cfg *Config) setDefaults() {
+	if cfg.Docker == "" {
+		cfg.Docker = "docker"
+	}
+	if cfg.FactomdImage == "" {
+		cfg.FactomdImage = FactomdImageDefault
+	}
+	if cfg.WalletdImage == "" {
+		cfg.WalletdImage = WalletdImageDefault
+	}
+	if cfg.BlockTime < time.Second {
+		cfg.BlockTime = 10 * time.Second
+	}
+	if cfg.ReadyTimeout == 0 {
+		cfg.ReadyTimeout = 2 * time.Minute
+	}
+}
+
+// Close removes all containers and the Docker network created by Start.
+func (env *Env) Close() error {
+	ctx := context.Background()
+	var errs []string
+	for i := len(env.containers) - 1; i >= 0; i-- {
+		if _, err := env.run(ctx, "rm", "-f", "-v",
+			env.containers[i]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	env.containers = nil
+	if env.network != "" {
+		if _, err := env.run(ctx, "network", "rm", env.network); err != nil {
+			errs = append(errs, err.Error())
+		}
+		env.network = ""
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// FundEC purchases amount Entry Credits for ec using the Genesis address and
+// waits until the balance of ec reflects the purchase.
+func (env *Env) FundEC(ctx context.Context,
+	ec factom.ECAddress, amount uint64) (*factom.TxID, error) {
+	c := env.Client
+	balance, err := ec.GetBalance(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := c.GetECRate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fa := env.Genesis.FAAddress()
+	tx := factom.Transaction{
+		TimestampSalt: time.Now(),
+		FCTInputs: []factom.AddressAmount{
+			{Address: fa[:], Amount: amount * rate}},
+		ECOutputs: []factom.AddressAmount{
+			{Address: ec[:], Amount: amount * rate}},
+	}
+	fee, err := tx.RequiredFee(rate)
+	if err != nil {
+		return nil, err
+	}
+	tx.FCTInputs[0].Amount += fee
+	tx.Signatures = make([]factom.RCDSignature, 1)
+	data, err := tx.Sign(env.Genesis)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.FactoidSubmit(ctx, data); err != nil {
+		return nil, err
+	}
+
+	return tx.ID, poll(ctx, func() error {
+		b, err := ec.GetBalance(ctx, c)
+		if err != nil {
+			return err
+		}
+		if b < balance+amount {
+			return fmt.Errorf("balance not yet updated")
+		}
+		return nil
+	})
+}
+
+func (env *Env) waitFactomd(ctx context.Context) error {
+	return poll(ctx, func() error {
+		var h factom.Heights
+		if err := h.Get(ctx, env.Client); err != nil {
+			return err
+		}
+		if h.DirectoryBlock < 1 {
+			return fmt.Errorf("genesis DBlock not yet saved")
+		}
+		return nil
+	})
+}
+
+func (env *Env) waitWalletd(ctx context.Context) error {
+	return poll(ctx, func() error {
+		return env.Client.WalletdRequest(ctx, "properties", nil, nil)
+	})
+}
+
+// poll calls f every half second until it returns nil or ctx is done, in
+// which case the last error from f is returned.
+func poll(ctx context.Context, f func() error) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v: %w", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// start runs image detached as the container name on env.network, publishing
+// the API port on a random localhost port.
+func (env *Env) start(ctx context.Context,
+	name, image string, port int, args ...string) error {
+	runArgs := append([]string{"run", "-d",
+		"--name", name,
+		"--network", env.network,
+		"-p", fmt.Sprintf("127.0.0.1::%v", port),
+		image}, args...)
+	if _, err := env.run(ctx, runArgs...); err != nil {
+		return err
+	}
+	env.containers = append(env.containers, name)
+	return nil
+}
+
+// url returns the v2 API URL for the host port published for the container
+// port.
+func (env *Env) url(ctx context.Context,
+	container string, port int) (string, error) {
+	out, err := env.run(ctx, "port", container, fmt.Sprintf("%v/tcp", port))
+	if err != nil {
+		return "", err
+	}
+	// The output may contain one line per address family.
+	hostPort := strings.Fields(out)
+	if len(hostPort) == 0 {
+		return "", fmt.Errorf("%v: port %v not published", container, port)
+	}
+	return fmt.Sprintf("http://%v/v2", hostPort[0]), nil
+}
+
+func (env *Env) run(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, env.docker, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %v: %w: %v", args[0], err,
+			strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func randomName() (string, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("factom-testenv-%x", b), nil
+}
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package testenv_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/testenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker writes a shell script that logs its arguments to a file and
+// reports addr for all published ports.
+func fakeDocker(t *testing.T, dir, addr string) (docker, log string) {
+	docker = filepath.Join(dir, "docker")
+	log = filepath.Join(dir, "log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %q
+if [ "$1" = "port" ]; then
+	echo %q
+fi
+`, log, addr)
+	require.NoError(t, ioutil.WriteFile(docker, []byte(script), 0755))
+	return
+}
+
+// localGenesis returns the raw data of a minimal height 0 DBlock on the LOCAL
+// network.
+func localGenesis(t *testing.T) factom.Bytes {
+	body := make([]byte, 3*factom.DBlockEBlockSize)
+	elements := make([][]byte, 3)
+	for i, id := range []byte{0x0a, 0x0c, 0x0f} {
+		elements[i] = body[i*factom.DBlockEBlockSize:][:factom.DBlockEBlockSize]
+		elements[i][31] = id
+	}
+	bodyMR, err := factom.ComputeDBlockBodyMR(elements)
+	require.NoError(t, err)
+
+	data := make([]byte, factom.DBlockHeaderSize)
+	networkID := factom.LocalnetID()
+	copy(data[1:], networkID[:])
+	copy(data[5:], bodyMR[:])
+	binary.BigEndian.PutUint32(data[factom.DBlockHeaderSize-4:], 3)
+	return append(data, body...)
+}
+
+func TestAvailable(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "testenv")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	docker, _ := fakeDocker(t, dir, "")
+
+	require.True(testenv.Available(testenv.Config{Docker: docker}))
+	require.False(testenv.Available(testenv.Config{
+		Docker: filepath.Join(dir, "missing")}))
+}
+
+func TestStart(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var mu sync.Mutex
+	var balance uint64
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var req jsonrpc2.Request
+			data, _ := ioutil.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &req)
+			mu.Lock()
+			defer mu.Unlock()
+			var result interface{}
+			switch req.Method {
+			case "heights":
+				result = factom.Heights{DirectoryBlock: 1}
+			case "dblock-by-height":
+				result = map[string]factom.Bytes{
+					"rawdata": localGenesis(t)}
+			case "properties":
+				result = struct{}{}
+			case "entry-credit-rate":
+				result = map[string]int{"rate": 1000}
+			case "entry-credit-balance":
+				result = map[string]uint64{"balance": balance}
+			case "factoid-submit":
+				balance += 10
+				result = struct{}{}
+			default:
+				t.Errorf("unexpected method: %v", req.Method)
+			}
+			data, _ = json.Marshal(jsonrpc2.Response{
+				ID: req.ID, Result: result})
+			w.Write(data)
+		}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "testenv")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	docker, log := fakeDocker(t, dir,
+		strings.TrimPrefix(srv.URL, "http://"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	env, err := testenv.Start(ctx, testenv.Config{
+		Docker:    docker,
+		Walletd:   true,
+		BlockTime: time.Minute,
+	})
+	require.NoError(err)
+	assert.Equal(srv.URL+"/v2", env.Client.FactomdServer)
+	assert.Equal(srv.URL+"/v2", env.Client.WalletdServer)
+	assert.Equal(factom.LocalnetID(), env.Client.Network.ID)
+
+	es, err := factom.GenerateEsAddress()
+	require.NoError(err)
+	txID, err := env.FundEC(ctx, es.ECAddress(), 10)
+	require.NoError(err)
+	assert.NotNil(txID)
+
+	require.NoError(env.Close())
+
+	data, err := ioutil.ReadFile(log)
+	require.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(lines, 8)
+	network := strings.Fields(lines[0])[2]
+	assert.Equal("network create "+network, lines[0])
+	assert.Equal("run -d --name "+network+"-factomd --network "+network+
+		" -p 127.0.0.1::8088 "+testenv.FactomdImageDefault+
+		" -network=LOCAL -blktime=60 -startdelay=0 -sim_stdin=false"+
+		" -checkheads=false", lines[1])
+	assert.Equal("port "+network+"-factomd 8088/tcp", lines[2])
+	assert.Equal("run -d --name "+network+"-walletd --network "+network+
+		" -p 127.0.0.1::8089 "+testenv.WalletdImageDefault+
+		" -s "+network+"-factomd:8088", lines[3])
+	assert.Equal("port "+network+"-walletd 8089/tcp", lines[4])
+	assert.Equal("rm -f -v "+network+"-walletd", lines[5])
+	assert.Equal("rm -f -v "+network+"-factomd", lines[6])
+	assert.Equal("network rm "+network, lines[7])
+}
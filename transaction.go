@@ -24,7 +24,7 @@ package factom
 
 import (
 	"bytes"
-	"crypto/sha256"
+	"context"
 	"fmt"
 	"time"
 
@@ -40,7 +40,7 @@ import (
 type Transaction struct {
 	// ID the sha256 hash of the binary Transaction ledger, which includes
 	// the header, timestamp salt, and all inputs and outputs.
-	ID *Bytes32
+	ID *TxID
 
 	// Timestamp is established by the FBlock. It is only populated if the
 	// Transaction was unmarshaled from within an FBlock
@@ -167,6 +167,10 @@ const (
 		32 + 1 + // 1 Input
 		32 + 1 + // 1 Output
 		33 + 32 // RCD01 and Signature
+
+	// TransactionMaxTotalSize is the maximum size of a Binary Transaction
+	// accepted by factomd.
+	TransactionMaxTotalSize = 10240
 )
 
 // UnmarshalBinary unmarshals and validates the first Transaction from data,
@@ -176,21 +180,88 @@ const (
 // Use MarshalBinaryLen to efficiently determine the number of bytes read from
 // data.
 func (tx *Transaction) UnmarshalBinary(data []byte) error {
+	signed, err := tx.unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	for _, s := range signed {
+		if !s.Verify() {
+			return fmt.Errorf("invalid signature")
+		}
+	}
+	return nil
+}
+
+// unmarshalBinary is UnmarshalBinary without verifying the Signatures, which
+// are returned so that the caller may verify them.
+func (tx *Transaction) unmarshalBinary(data []byte) ([]Signed, error) {
+	i, err := tx.unmarshalBinaryLedger(data)
+	if err != nil {
+		return nil, err
+	}
+	ledger := data[:i]
+
+	// Each Signature requires at least an RCD type byte, so reject inputs
+	// that data could never satisfy before allocating.
+	if len(tx.FCTInputs) > len(data[i:]) {
+		return nil, fmt.Errorf("insufficient length")
+	}
+	tx.Signatures = make([]RCDSignature, len(tx.FCTInputs))
+	signed := make([]Signed, len(tx.Signatures))
+
+	for j := range tx.Signatures {
+		rcdSig := &tx.Signatures[j]
+		if err := rcdSig.UnmarshalBinary(data[i:]); err != nil {
+			return nil, err
+		}
+		i += rcdSig.Len()
+
+		// Validate RCD
+		rcdHash := rcdSig.RCD.Hash()
+		if bytes.Compare(tx.FCTInputs[j].Address, rcdHash[:]) != 0 {
+			return nil, fmt.Errorf("invalid RCD hash")
+		}
+		if err := rcdSig.RCD.validType01(rcdSig.Signature); err != nil {
+			return nil, err
+		}
+		signed[j] = Signed{PublicKey: rcdSig.RCD.publicKeyType01(),
+			Message: ledger, Signature: rcdSig.Signature}
+	}
+
+	if i > TransactionMaxTotalSize {
+		return nil, fmt.Errorf("invalid length")
+	}
+
+	txID := TxID(sha256Sum(ledger))
+	if tx.ID == nil {
+		tx.ID = &txID
+	} else if *tx.ID != txID {
+		return nil, fmt.Errorf("invalid TxID")
+	}
+
+	tx.marshalBinaryCache = data[:i]
+
+	return signed, nil
+}
+
+// unmarshalBinaryLedger unmarshals the header, inputs, outputs, and EC outputs
+// of the Transaction from data, and returns the length of the ledger. Any
+// Signatures that follow are not read.
+func (tx *Transaction) unmarshalBinaryLedger(data []byte) (int, error) {
 	// Parse header
 	if len(data) < TransactionHeaderSize {
-		return fmt.Errorf("insufficient length")
+		return 0, fmt.Errorf("insufficient length")
 	}
 
 	// Only Version 0x02 is supported.
 	if data[0] != TransactionVersion {
-		return fmt.Errorf("invalid version")
+		return 0, fmt.Errorf("invalid version")
 	}
 
 	i := 1
 
-	msTsSalt := getInt48BE(data[i:])
-	tx.TimestampSalt = time.Unix(0, msTsSalt*1e6)
-	i += 6
+	tx.TimestampSalt = DecodeTimestamp(data[i:])
+	i += TimestampSize
 
 	fctInputCount := uint(data[i])
 	i++
@@ -199,7 +270,14 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 	ecOutputCount := uint(data[i])
 	i++
 
-	adrs := make([]AddressAmount, fctInputCount+fctOutputCount+ecOutputCount)
+	// Each address requires at least a 1 byte amount and a 32 byte
+	// address, so reject counts that data could never satisfy before
+	// allocating.
+	adrCount := fctInputCount + fctOutputCount + ecOutputCount
+	if uint64(adrCount)*(1+32) > uint64(len(data[i:])) {
+		return 0, fmt.Errorf("insufficient length")
+	}
+	adrs := make([]AddressAmount, adrCount)
 
 	var totalIn uint64
 	var totalOut uint64
@@ -207,15 +285,15 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 	for j := range adrs {
 		amount, size := varintf.Decode(data[i:])
 		if size == 0 {
-			return fmt.Errorf("insufficient length")
+			return 0, fmt.Errorf("insufficient length")
 		}
 		if size < 0 {
-			return fmt.Errorf("invalid amount")
+			return 0, fmt.Errorf("invalid amount")
 		}
 		i += size
 
 		if len(data[i:]) < 32 {
-			return fmt.Errorf("insufficient length")
+			return 0, fmt.Errorf("insufficient length")
 		}
 
 		adr := &adrs[j]
@@ -224,8 +302,14 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 		i += 32
 
 		if uint(j) < fctInputCount {
+			if totalIn+amount < totalIn {
+				return 0, fmt.Errorf("total inputs overflow")
+			}
 			totalIn += amount
 		} else {
+			if totalOut+amount < totalOut {
+				return 0, fmt.Errorf("total outputs overflow")
+			}
 			totalOut += amount
 			if uint(j) >= fctInputCount+fctOutputCount {
 				totalECOut += amount
@@ -235,7 +319,7 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 
 	isCoinbaseTx := fctInputCount == 0 && ecOutputCount == 0
 	if !isCoinbaseTx && totalOut > totalIn {
-		return fmt.Errorf("outputs exceed inputs")
+		return 0, fmt.Errorf("outputs exceed inputs")
 	}
 
 	tx.TotalIn = totalIn
@@ -243,42 +327,12 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 	tx.TotalECOut = totalECOut
 	tx.TotalBurn = totalIn - totalOut
 
-	ledger := data[:i]
-
 	tx.FCTInputs = adrs[:fctInputCount]
 	adrs = adrs[fctInputCount:]
 	tx.FCTOutputs = adrs[:fctOutputCount]
 	tx.ECOutputs = adrs[fctOutputCount:]
 
-	tx.Signatures = make([]RCDSignature, fctInputCount)
-
-	for j := range tx.Signatures {
-		rcdSig := &tx.Signatures[j]
-		if err := rcdSig.UnmarshalBinary(data[i:]); err != nil {
-			return err
-		}
-		i += rcdSig.Len()
-
-		// Validate RCD
-		rcdHash := rcdSig.RCD.Hash()
-		if bytes.Compare(tx.FCTInputs[j].Address, rcdHash[:]) != 0 {
-			return fmt.Errorf("invalid RCD hash")
-		}
-		if err := rcdSig.ValidateType01(ledger); err != nil {
-			return err
-		}
-	}
-
-	txID := Bytes32(sha256.Sum256(ledger))
-	if tx.ID == nil {
-		tx.ID = &txID
-	} else if *tx.ID != txID {
-		return fmt.Errorf("invalid TxID")
-	}
-
-	tx.marshalBinaryCache = data[:i]
-
-	return nil
+	return i, nil
 }
 
 // MarshalBinaryLedger marshals the header, inputs, outputs, and EC outputs of
@@ -299,14 +353,30 @@ func (tx Transaction) MarshalBinaryLedger() ([]byte, error) {
 		return nil, fmt.Errorf("number of inputs and signatures differ")
 	}
 
+	return tx.marshalBinaryLedger()
+}
+
+// marshalBinaryLedger marshals the ledger of the Transaction without regard to
+// its Signatures, which is all that is required to compute the TxID.
+func (tx Transaction) marshalBinaryLedger() ([]byte, error) {
+	if len(tx.FCTInputs) == 0 || tx.TimestampSalt.IsZero() {
+		return nil, fmt.Errorf("not populated")
+	}
+
+	if len(tx.FCTInputs) > 256 ||
+		len(tx.FCTOutputs) > 256 ||
+		len(tx.ECOutputs) > 256 {
+		return nil, fmt.Errorf("too many inputs or outputs")
+	}
+
 	data := make([]byte, tx.MarshalBinaryLen())
 
 	var i int
 	data[i] = TransactionVersion
 	i++
 
-	putInt48BE(data[i:], tx.TimestampSalt.Unix()*1e3)
-	i += 6
+	PutTimestamp(data[i:], tx.TimestampSalt)
+	i += TimestampSize
 
 	data[i] = byte(len(tx.FCTInputs))
 	i++
@@ -382,7 +452,7 @@ func (tx *Transaction) Sign(signingSet ...RCDSigner) ([]byte, error) {
 		data = append(data, rcdSig.Signature...)
 	}
 
-	txID := Bytes32(sha256.Sum256(ledger))
+	txID := TxID(sha256Sum(ledger))
 	tx.ID = &txID
 
 	tx.marshalBinaryCache = data
@@ -390,6 +460,28 @@ func (tx *Transaction) Sign(signingSet ...RCDSigner) ([]byte, error) {
 	return data, nil
 }
 
+// TxID computes the TxID of tx, which is the sha256 hash of the binary
+// Transaction ledger. Since the ledger excludes the Signatures, the TxID is
+// known before tx is signed or submitted.
+func (tx Transaction) TxID() (TxID, error) {
+	ledger, err := tx.marshalBinaryLedger()
+	if err != nil {
+		return TxID{}, err
+	}
+	return TxID(sha256Sum(ledger)), nil
+}
+
+// LedgerHash computes the sha256 hash of the complete binary Transaction,
+// including all Signatures. This is the leaf of tx in the FBlock BodyMR, and
+// is sometimes called the full hash of the Transaction.
+func (tx Transaction) LedgerHash() (Bytes32, error) {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return Bytes32{}, err
+	}
+	return sha256Sum(data), nil
+}
+
 // MarshalBinaryLen efficiently calculates the full Transaction size. The
 // cached binary marshal data is used if populated.
 func (tx Transaction) MarshalBinaryLen() int {
@@ -415,3 +507,40 @@ func (tx Transaction) MarshalBinaryLen() int {
 
 	return size
 }
+
+// RequiredFee returns the minimum fee in factoshis that factomd requires for
+// tx at the given ecRate, in factoshis per Entry Credit.
+//
+// The fee is 1 EC per KiB of the binary Transaction, plus 10 EC per FCT or EC
+// output, plus 1 EC per signature. If tx is not yet signed, an RCDType01
+// signature is assumed for each FCTInput.
+func (tx Transaction) RequiredFee(ecRate uint64) (uint64, error) {
+	size := tx.MarshalBinaryLen()
+	numSigs := len(tx.Signatures)
+	if numSigs == 0 {
+		numSigs = len(tx.FCTInputs)
+		size += numSigs * (RCDType01Size + RCDType01SigSize)
+	}
+	if size > TransactionMaxTotalSize {
+		return 0, fmt.Errorf("length exceeds %v", TransactionMaxTotalSize)
+	}
+	ecCost := uint64((size+1023)/1024) +
+		10*uint64(len(tx.FCTOutputs)+len(tx.ECOutputs)) +
+		uint64(numSigs)
+	return ecCost * ecRate, nil
+}
+
+// FactoidSubmit submits the binary marshaled and signed Transaction tx to
+// factomd.
+func (c *Client) FactoidSubmit(ctx context.Context, tx []byte) error {
+	if err := c.checkNetwork(ctx); err != nil {
+		return err
+	}
+	params := struct {
+		Transaction Bytes `json:"transaction"`
+	}{Transaction: tx}
+	if err := c.FactomdRequest(ctx, "factoid-submit", params, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package vcr records the JSON-RPC 2.0 interactions of a factom.Client with
+// factomd and factom-walletd to golden files, and replays them, so that tests
+// may run offline and deterministically.
+//
+// A Recorder is an http.RoundTripper. In ModeRecord, requests are passed on
+// to the real server and each interaction is saved. In ModeReplay, no
+// requests are made and responses are served from the golden file. A typical
+// test looks like:
+//
+//	func TestSomething(t *testing.T) {
+//	        r, err := vcr.New("testdata/something.json", vcr.ModeFromEnv())
+//	        require.NoError(t, err)
+//	        defer func() { require.NoError(t, r.Close()) }()
+//
+//	        c := factom.NewClient()
+//	        r.Use(c)
+//	        ...
+//	}
+//
+// Run the test once with FACTOM_VCR=record against live servers to create
+// the golden file, and commit it.
+//
+// Interactions are matched by RPC method and params. Since the JSON-RPC ID
+// changes with every request, it is not recorded, and the ID of each replayed
+// response is set to that of its request. Interactions with the same method
+// and params are replayed in the order they were recorded, and the last one
+// is repeated once they are exhausted, so polling for a changing result, like
+// "heights", replays as it happened.
+package vcr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+// Modes of a Recorder.
+const (
+	// ModeReplay serves responses from the golden file and never makes
+	// requests. Requests with no recorded interaction fail.
+	ModeReplay Mode = iota
+
+	// ModeRecord passes requests to the server and saves all interactions
+	// to the golden file on Close, replacing its contents.
+	ModeRecord
+)
+
+// EnvMode is the environment variable read by ModeFromEnv.
+const EnvMode = "FACTOM_VCR"
+
+// ModeFromEnv returns ModeRecord if the EnvMode environment variable is
+// "record", and ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(EnvMode) == "record" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Interaction is a recorded request and response.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+
+	// Response is the JSON-RPC 2.0 response without its "id", or, if the
+	// response is not a JSON object, the response body as a JSON string.
+	Response json.RawMessage `json:"response"`
+}
+
+func (i Interaction) key() string {
+	return i.Method + " " + string(i.Params)
+}
+
+// Cassette is the format of a golden file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records or replays interactions. It is safe for concurrent use.
+type Recorder struct {
+	// Path is the golden file.
+	Path string
+	Mode Mode
+
+	// Transport is used to make requests in ModeRecord. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	// replay holds the indexes into cassette.Interactions of the
+	// interactions for each key, and next the index into replay of the
+	// next one to serve.
+	replay map[string][]int
+	next   map[string]int
+}
+
+// New returns a Recorder for the golden file at path. In ModeReplay, the
+// golden file is loaded and must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := Recorder{Path: path, Mode: mode}
+	if mode == ModeRecord {
+		return &r, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	r.replay = make(map[string][]int)
+	r.next = make(map[string]int)
+	for i, in := range r.cassette.Interactions {
+		key := in.key()
+		r.replay[key] = append(r.replay[key], i)
+	}
+	return &r, nil
+}
+
+// Use sets r as the Transport of the Factomd and Walletd clients of c.
+func (r *Recorder) Use(c *factom.Client) {
+	c.Factomd.Client.Transport = r
+	c.Walletd.Client.Transport = r
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Close saves the golden file in ModeRecord. It does nothing in ModeReplay.
+func (r *Recorder) Close() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.Path, append(data, '\n'), 0644)
+}
+
+// RoundTrip records or replays req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqData, err := readBody(req.Header, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var jReq struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		ID     json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(reqData, &jReq); err != nil {
+		return nil, fmt.Errorf("vcr: invalid request: %w", err)
+	}
+	in := Interaction{Method: jReq.Method, Params: compact(jReq.Params)}
+
+	if r.Mode == ModeRecord {
+		if err := r.record(req, reqData, &in); err != nil {
+			return nil, err
+		}
+	} else if in, err = r.replayNext(in); err != nil {
+		return nil, err
+	}
+
+	body := []byte(in.Response)
+	var res map[string]json.RawMessage
+	if json.Unmarshal(in.Response, &res) == nil && res != nil {
+		res["id"] = jReq.ID
+		if body, err = json.Marshal(res); err != nil {
+			return nil, err
+		}
+	} else {
+		var str string
+		if err := json.Unmarshal(in.Response, &str); err != nil {
+			return nil, fmt.Errorf("vcr: invalid response: %w", err)
+		}
+		body = []byte(str)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%v %v", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, reqData []byte,
+	in *Interaction) error {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(reqData))
+	req.ContentLength = int64(len(reqData))
+	req.Header.Del("Content-Encoding")
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	resData, err := readBody(res.Header, res.Body)
+	if err != nil {
+		return err
+	}
+
+	in.Status = res.StatusCode
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(resData, &obj) == nil && obj != nil {
+		delete(obj, "id")
+		in.Response, err = json.Marshal(obj)
+	} else {
+		in.Response, err = json.Marshal(string(resData))
+	}
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, *in)
+	return nil
+}
+
+func (r *Recorder) replayNext(in Interaction) (Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := in.key()
+	indexes := r.replay[key]
+	if len(indexes) == 0 {
+		return Interaction{}, fmt.Errorf(
+			"vcr: no recorded interaction for %v %s in %v",
+			in.Method, in.Params, r.Path)
+	}
+	n := r.next[key]
+	if n < len(indexes)-1 {
+		r.next[key] = n + 1
+	}
+	return r.cassette.Interactions[indexes[n]], nil
+}
+
+// readBody reads and closes body, and decompresses it if header declares a
+// gzip Content-Encoding.
+func readBody(header http.Header, body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	var rd io.Reader = body
+	if strings.EqualFold(header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		rd = gz
+	}
+	return ioutil.ReadAll(rd)
+}
+
+// compact returns params without insignificant whitespace, or nil if params
+// is empty or null.
+func compact(params json.RawMessage) json.RawMessage {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return params
+	}
+	return buf.Bytes()
+}
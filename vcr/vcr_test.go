@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package vcr_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/vcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var height uint32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			var jReq struct {
+				Method string
+				ID     json.RawMessage
+			}
+			data, _ := ioutil.ReadAll(req.Body)
+			_ = json.Unmarshal(data, &jReq)
+			height++
+			res := map[string]interface{}{"jsonrpc": "2.0", "id": jReq.ID,
+				"result": factom.Heights{DirectoryBlock: height}}
+			if jReq.Method != "heights" {
+				delete(res, "result")
+				res["error"] = map[string]interface{}{
+					"code": -32601, "message": "Method not found"}
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "vcr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "heights.json")
+	ctx := context.Background()
+
+	r, err := New(path, ModeRecord)
+	require.NoError(t, err)
+	c := factom.NewClient()
+	c.FactomdServer = srv.URL
+	r.Use(c)
+	for i := uint32(1); i <= 2; i++ {
+		var h factom.Heights
+		require.NoError(t, h.Get(ctx, c))
+		assert.Equal(t, i, h.DirectoryBlock)
+	}
+	_, err = c.GetECRate(ctx)
+	assert.Error(t, err)
+	require.NoError(t, r.Close())
+	assert.Len(t, r.Interactions(), 3)
+
+	srv.Close()
+	r, err = New(path, ModeReplay)
+	require.NoError(t, err)
+	c = factom.NewClient()
+	c.FactomdServer = srv.URL
+	r.Use(c)
+	for _, i := range []uint32{1, 2, 2} {
+		var h factom.Heights
+		require.NoError(t, h.Get(ctx, c))
+		assert.Equal(t, i, h.DirectoryBlock)
+	}
+	_, err = c.GetECRate(ctx)
+	assert.Error(t, err)
+
+	var db factom.DBlock
+	err = db.Get(ctx, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction")
+}
+
+func TestGoldenFile(t *testing.T) {
+	r, err := New("testdata/heights.json", ModeReplay)
+	require.NoError(t, err)
+	c := factom.NewClient()
+	r.Use(c)
+	var h factom.Heights
+	require.NoError(t, h.Get(context.Background(), c))
+	assert.Equal(t, uint32(250000), h.DirectoryBlock)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("testdata/missing.json", ModeReplay)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"crypto/ed25519"
+	"runtime"
+	"sync"
+)
+
+// Signed is an ed25519 signature of a message.
+type Signed struct {
+	PublicKey ed25519.PublicKey
+	Message   []byte
+	Signature []byte
+}
+
+// Verify returns true if s.Signature is a valid signature of s.Message by
+// s.PublicKey.
+func (s Signed) Verify() bool {
+	return len(s.PublicKey) == ed25519.PublicKeySize &&
+		ed25519.Verify(s.PublicKey, s.Message, s.Signature)
+}
+
+// verifyAllMinParallel is the fewest signatures VerifyAll verifies in
+// parallel. Below this, starting goroutines costs more than it saves.
+const verifyAllMinParallel = 16
+
+// VerifyAll verifies all signed concurrently, using up to runtime.NumCPU
+// goroutines, and returns whether each is valid.
+//
+// FBlock.UnmarshalBinary and ECBlock.UnmarshalBinary use VerifyAll for the
+// signatures of all of their Transactions and Commits, which dominates the
+// time it takes to validate them.
+func VerifyAll(signed []Signed) []bool {
+	valid := make([]bool, len(signed))
+	n := runtime.NumCPU()
+	if len(signed) < verifyAllMinParallel || n == 1 {
+		for i, s := range signed {
+			valid[i] = s.Verify()
+		}
+		return valid
+	}
+	if len(signed) < n {
+		n = len(signed)
+	}
+
+	// Each goroutine verifies a contiguous chunk so that no
+	// synchronization is needed to write to valid.
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for g := 0; g < n; g++ {
+		start, end := g*len(signed)/n, (g+1)*len(signed)/n
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				valid[i] = signed[i].Verify()
+			}
+		}()
+	}
+	wg.Wait()
+	return valid
+}
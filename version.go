@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version of factomd.
+type Version struct {
+	Major, Minor, Patch int
+
+	// Pre is the pre-release suffix, such as "rc1", if any.
+	Pre string
+}
+
+// ParseVersion parses a version of the form "[v]MAJOR.MINOR.PATCH[-PRE]". The
+// PATCH may be omitted.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(str, '-'); i >= 0 {
+		v.Pre = str[i+1:]
+		str = str[:i]
+	}
+	parts := strings.Split(str, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version: %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version: %q", s)
+		}
+		*nums[i] = int(n)
+	}
+	return v, nil
+}
+
+// String returns v in the form "MAJOR.MINOR.PATCH[-PRE]".
+func (v Version) String() string {
+	s := fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0, or 1 if v is less than, equal to, or greater than
+// w. A pre-release is less than the release of the same MAJOR.MINOR.PATCH.
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor,
+		v.Patch - w.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == w.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case w.Pre == "":
+		return -1
+	case v.Pre < w.Pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Less returns true if v is less than w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// Properties are the versions reported by factomd.
+type Properties struct {
+	FactomdVersion    string `json:"factomdversion"`
+	FactomdAPIVersion string `json:"factomdapiversion"`
+}
+
+// Get uses c to call the "properties" RPC method and populates p with the
+// result.
+func (p *Properties) Get(ctx context.Context, c *Client) error {
+	return c.FactomdRequest(ctx, "properties", nil, p)
+}
+
+// Version parses p.FactomdVersion.
+func (p Properties) Version() (Version, error) {
+	return ParseVersion(p.FactomdVersion)
+}
@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"testing"
+
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, test := range []struct {
+		Str     string
+		Version Version
+		Error   string
+	}{
+		{Str: "6.7.0", Version: Version{6, 7, 0, ""}},
+		{Str: "v6.1.1-rc2", Version: Version{6, 1, 1, "rc2"}},
+		{Str: "6.2", Version: Version{6, 2, 0, ""}},
+		{Str: "BuiltWithoutVersion",
+			Error: `invalid version: "BuiltWithoutVersion"`},
+		{Str: "6.x.0", Error: `invalid version: "6.x.0"`},
+		{Str: "6.1.2.3", Error: `invalid version: "6.1.2.3"`},
+	} {
+		t.Run(test.Str, func(t *testing.T) {
+			v, err := ParseVersion(test.Str)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.Version, v)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"5.4.9", "6.0.0-rc1", "6.0.0-rc2", "6.0.0", "6.1.0",
+		"6.1.10", "7.0.0"}
+	for i, a := range ordered {
+		va, _ := ParseVersion(a)
+		assert.Equal(t, 0, va.Compare(va), a)
+		for _, b := range ordered[i+1:] {
+			vb, _ := ParseVersion(b)
+			assert.True(t, va.Less(vb), "%v < %v", a, b)
+			assert.Equal(t, 1, vb.Compare(va), "%v > %v", b, a)
+		}
+	}
+	assert.Equal(t, "6.0.0-rc1", Version{6, 0, 0, "rc1"}.String())
+}
@@ -0,0 +1,582 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package vote implements on-chain polls with weighted, secret ballots, based
+// on the Factom Open Voting specification.
+//
+// A poll chain is created by an Entry with the ExtIDs
+//
+//	["factom-vote", <initiator ChainID (32 bytes)>, <RCD>, <signature>]
+//
+// where the signature is of the Content, which is the JSON Definition of the
+// poll. The Definition includes the eligibility list of voter identity
+// ChainIDs and their weights, the options, and the times of the phases.
+//
+// During the commit phase, each voter commits to their vote, without revealing
+// it, with an Entry with the ExtIDs
+//
+//	["factom-vote-commit", <voter ChainID (32 bytes)>, <RCD>, <signature>]
+//
+// and the Content
+//
+//	{"commitment":"<SHA-256 of the secret followed by the vote JSON>"}
+//
+// where the signature is of the hex encoded poll ChainID concatenated with the
+// voter ChainID and the Content. A later commit by the same voter replaces an
+// earlier one.
+//
+// During the reveal phase, which begins when the commit phase ends, each voter
+// reveals their vote with an Entry with the ExtIDs
+//
+//	["factom-vote-reveal", <voter ChainID (32 bytes)>]
+//
+// and the Content
+//
+//	{"vote":["<option>", ...],"secret":"<32 byte hex secret>"}
+//
+// A reveal needs no signature, since only the voter knows the secret that
+// matches their signed commitment.
+//
+// Phases are determined by Entry Timestamps, so a poll can be tallied from
+// the Entries of its chain alone. Identities are not resolved by this package.
+// A KeyChecker, such as one returned by IdentityKeys, decides whether a key
+// may sign for an identity.
+//
+// Anyone may write to the chain, so Tally ignores all invalid Entries.
+package vote
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+)
+
+var (
+	// ChainTag is the first ExtID of the first Entry of a poll chain.
+	ChainTag = factom.Bytes("factom-vote")
+
+	// CommitTag is the first ExtID of a vote commit Entry.
+	CommitTag = factom.Bytes("factom-vote-commit")
+
+	// RevealTag is the first ExtID of a vote reveal Entry.
+	RevealTag = factom.Bytes("factom-vote-reveal")
+)
+
+// Version is the only supported version of the poll Definition.
+const Version = 1
+
+// KeyChecker returns true if key may sign for the identity with the ChainID
+// identity.
+type KeyChecker func(identity factom.Bytes32, key factom.ID1Key) bool
+
+// IdentityKeys returns a KeyChecker that accepts the active keys of ids.
+func IdentityKeys(ids ...appidentity.Identity) KeyChecker {
+	keys := make(map[factom.Bytes32]appidentity.Identity, len(ids))
+	for _, id := range ids {
+		keys[id.ChainID] = id
+	}
+	return func(identity factom.Bytes32, key factom.ID1Key) bool {
+		id, ok := keys[identity]
+		return ok && id.Priority(key) >= 0
+	}
+}
+
+// Voter is an eligible voter identity and the weight of its vote.
+type Voter struct {
+	ID     factom.Bytes32 `json:"id"`
+	Weight uint64         `json:"weight"`
+}
+
+// Definition defines a poll.
+type Definition struct {
+	Version int    `json:"version"`
+	Title   string `json:"title"`
+	Text    string `json:"text,omitempty"`
+
+	// Options are the choices of the poll. Each vote selects between
+	// MinOptions and MaxOptions distinct Options, or none if
+	// AllowAbstention is true. A yes or no poll has the Options "yes"
+	// and "no", and MinOptions and MaxOptions of 1.
+	Options         []string `json:"options"`
+	MinOptions      int      `json:"minoptions"`
+	MaxOptions      int      `json:"maxoptions"`
+	AllowAbstention bool     `json:"allowabstention,omitempty"`
+
+	// The commit phase is from CommitStart until CommitEnd, when the
+	// reveal phase starts, which lasts until RevealEnd.
+	CommitStart time.Time `json:"commitstart"`
+	CommitEnd   time.Time `json:"commitend"`
+	RevealEnd   time.Time `json:"revealend"`
+
+	// Voters is the eligibility list.
+	Voters []Voter `json:"voters"`
+}
+
+// Valid returns an error if d is not a valid Definition.
+func (d Definition) Valid() error {
+	if d.Version != Version {
+		return fmt.Errorf("unsupported version: %v", d.Version)
+	}
+	if d.Title == "" {
+		return fmt.Errorf("missing title")
+	}
+	if len(d.Options) < 2 {
+		return fmt.Errorf("too few options")
+	}
+	options := make(map[string]struct{}, len(d.Options))
+	for _, option := range d.Options {
+		if option == "" {
+			return fmt.Errorf("empty option")
+		}
+		if _, ok := options[option]; ok {
+			return fmt.Errorf("duplicate option: %q", option)
+		}
+		options[option] = struct{}{}
+	}
+	if d.MinOptions < 1 || d.MinOptions > d.MaxOptions ||
+		d.MaxOptions > len(d.Options) {
+		return fmt.Errorf("invalid min or max options")
+	}
+	if !d.CommitStart.Before(d.CommitEnd) ||
+		!d.CommitEnd.Before(d.RevealEnd) {
+		return fmt.Errorf("invalid phases")
+	}
+	if len(d.Voters) == 0 {
+		return fmt.Errorf("no voters")
+	}
+	voters := make(map[factom.Bytes32]struct{}, len(d.Voters))
+	for _, v := range d.Voters {
+		if v.Weight == 0 {
+			return fmt.Errorf("zero weight voter: %v", v.ID)
+		}
+		if _, ok := voters[v.ID]; ok {
+			return fmt.Errorf("duplicate voter: %v", v.ID)
+		}
+		voters[v.ID] = struct{}{}
+	}
+	return nil
+}
+
+// ValidVote returns an error if vote is not a valid vote for d.
+func (d Definition) ValidVote(vote []string) error {
+	if len(vote) == 0 && d.AllowAbstention {
+		return nil
+	}
+	if len(vote) < d.MinOptions || len(vote) > d.MaxOptions {
+		return fmt.Errorf("invalid number of options: %v", len(vote))
+	}
+	selected := make(map[string]struct{}, len(vote))
+	for _, option := range vote {
+		if _, ok := selected[option]; ok {
+			return fmt.Errorf("duplicate option: %q", option)
+		}
+		selected[option] = struct{}{}
+	}
+	for _, option := range d.Options {
+		delete(selected, option)
+	}
+	for option := range selected {
+		return fmt.Errorf("unknown option: %q", option)
+	}
+	return nil
+}
+
+func (d Definition) voter(id factom.Bytes32) (Voter, bool) {
+	for _, v := range d.Voters {
+		if v.ID == id {
+			return v, true
+		}
+	}
+	return Voter{}, false
+}
+
+// Poll is a poll and the ChainID of its chain.
+type Poll struct {
+	Definition
+	ChainID   factom.Bytes32
+	Initiator factom.Bytes32
+}
+
+// New returns a new Poll for d, initiated by the identity initiator and signed
+// by signer, along with the first Entry of its chain, which must be submitted
+// to create the chain.
+func New(d Definition, initiator factom.Bytes32,
+	signer factom.RCDSigner) (Poll, factom.Entry, error) {
+	if err := d.Valid(); err != nil {
+		return Poll{}, factom.Entry{}, err
+	}
+	content, err := json.Marshal(d)
+	if err != nil {
+		return Poll{}, factom.Entry{}, err
+	}
+	extIDs := []factom.Bytes{ChainTag, initiator[:],
+		factom.Bytes(signer.RCD()), signer.Sign(content)}
+	chainID := factom.ComputeChainID(extIDs)
+	e := factom.Entry{ChainID: &chainID, ExtIDs: extIDs, Content: content}
+	p, err := parseFirst(e, nil)
+	if err != nil {
+		return Poll{}, factom.Entry{}, err
+	}
+	return p, e, nil
+}
+
+// parseFirst parses the first Entry of a poll chain. If keys is nil, the
+// initiator's key is not checked.
+func parseFirst(e factom.Entry, keys KeyChecker) (Poll, error) {
+	if len(e.ExtIDs) != 4 || !bytes.Equal(e.ExtIDs[0], ChainTag) {
+		return Poll{}, fmt.Errorf("first entry: invalid ExtIDs")
+	}
+	if e.ChainID == nil || *e.ChainID != factom.ComputeChainID(e.ExtIDs) {
+		return Poll{}, fmt.Errorf("first entry: invalid ChainID")
+	}
+	if len(e.ExtIDs[1]) != len(factom.Bytes32{}) {
+		return Poll{}, fmt.Errorf("first entry: invalid initiator")
+	}
+	var p Poll
+	copy(p.Initiator[:], e.ExtIDs[1])
+	key, err := validate(e.ExtIDs[2], e.ExtIDs[3], e.Content)
+	if err != nil {
+		return Poll{}, fmt.Errorf("first entry: %w", err)
+	}
+	if keys != nil && !keys(p.Initiator, key) {
+		return Poll{}, fmt.Errorf("first entry: key is not active")
+	}
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p.Definition); err != nil {
+		return Poll{}, fmt.Errorf("first entry: invalid content: %w", err)
+	}
+	if err := p.Definition.Valid(); err != nil {
+		return Poll{}, fmt.Errorf("first entry: %w", err)
+	}
+	p.ChainID = *e.ChainID
+	return p, nil
+}
+
+// validate the signature of msg by the RCD data and return its ID1Key.
+func validate(data, sig, msg []byte) (factom.ID1Key, error) {
+	var rcd factom.RCD
+	if err := rcd.UnmarshalBinary(data); err != nil {
+		return factom.ID1Key{}, fmt.Errorf("invalid RCD: %w", err)
+	}
+	if err := rcd.Validate(sig, msg); err != nil {
+		return factom.ID1Key{}, err
+	}
+	return factom.ID1Key(rcd.Hash()), nil
+}
+
+// Ballot is a vote that is committed to during the commit phase and revealed
+// during the reveal phase. The Secret must be kept by the voter until then.
+type Ballot struct {
+	Voter  factom.Bytes32 `json:"voter"`
+	Vote   []string       `json:"vote"`
+	Secret factom.Bytes32 `json:"secret"`
+}
+
+// NewBallot returns a Ballot with a random Secret for vote by voter.
+func (p Poll) NewBallot(voter factom.Bytes32, vote []string) (Ballot, error) {
+	if _, ok := p.voter(voter); !ok {
+		return Ballot{}, fmt.Errorf("voter is not eligible")
+	}
+	if err := p.ValidVote(vote); err != nil {
+		return Ballot{}, err
+	}
+	b := Ballot{Voter: voter, Vote: vote}
+	if _, err := rand.Read(b.Secret[:]); err != nil {
+		return Ballot{}, err
+	}
+	return b, nil
+}
+
+// Commitment returns the SHA-256 hash of b.Secret followed by the JSON of
+// b.Vote.
+func (b Ballot) Commitment() factom.Bytes32 {
+	vote := b.Vote
+	if vote == nil {
+		vote = []string{}
+	}
+	data, _ := json.Marshal(vote)
+	return sha256.Sum256(append(b.Secret[:], data...))
+}
+
+type commitContent struct {
+	Commitment factom.Bytes32 `json:"commitment"`
+}
+
+type revealContent struct {
+	Vote   []string       `json:"vote"`
+	Secret factom.Bytes32 `json:"secret"`
+}
+
+// commitMsg returns the data signed by a commit of voter with content.
+func (p Poll) commitMsg(voter, content []byte) []byte {
+	msg := []byte(p.ChainID.String())
+	msg = append(msg, voter...)
+	return append(msg, content...)
+}
+
+// CommitEntry returns the commit Entry for b, signed by signer.
+func (p Poll) CommitEntry(b Ballot, signer factom.RCDSigner) factom.Entry {
+	content, _ := json.Marshal(commitContent{Commitment: b.Commitment()})
+	chainID := p.ChainID
+	return factom.Entry{
+		ChainID: &chainID,
+		ExtIDs: []factom.Bytes{CommitTag, b.Voter[:],
+			factom.Bytes(signer.RCD()),
+			signer.Sign(p.commitMsg(b.Voter[:], content))},
+		Content: content,
+	}
+}
+
+// RevealEntry returns the reveal Entry for b.
+func (p Poll) RevealEntry(b Ballot) factom.Entry {
+	vote := b.Vote
+	if vote == nil {
+		vote = []string{}
+	}
+	content, _ := json.Marshal(revealContent{Vote: vote, Secret: b.Secret})
+	chainID := p.ChainID
+	return factom.Entry{
+		ChainID: &chainID,
+		ExtIDs:  []factom.Bytes{RevealTag, b.Voter[:]},
+		Content: content,
+	}
+}
+
+// OptionResult is the tally of an option.
+type OptionResult struct {
+	Option string
+	Count  int
+	Weight uint64
+}
+
+// Result is the tally of a poll.
+type Result struct {
+	// Options are the tallies of each option, in the order of the
+	// Definition.
+	Options []OptionResult
+
+	// Abstentions are the number and total weight of revealed empty
+	// votes.
+	Abstentions       int
+	AbstentionsWeight uint64
+
+	// Participants and ParticipantsWeight are the number and total
+	// weight of voters who revealed a valid vote, out of the TotalWeight
+	// of all eligible voters.
+	Participants       int
+	ParticipantsWeight uint64
+	TotalWeight        uint64
+
+	// Votes are the revealed votes of each voter.
+	Votes map[factom.Bytes32][]string
+}
+
+// Winner returns the option with the greatest weight, or false if no option
+// received any weight or the greatest weight is tied.
+func (r Result) Winner() (string, bool) {
+	var winner OptionResult
+	var tied bool
+	for _, o := range r.Options {
+		switch {
+		case o.Weight > winner.Weight:
+			winner, tied = o, false
+		case o.Weight == winner.Weight:
+			tied = true
+		}
+	}
+	if winner.Weight == 0 || tied {
+		return "", false
+	}
+	return winner.Option, true
+}
+
+// Turnout returns the fraction of the total weight that participated.
+func (r Result) Turnout() float64 {
+	if r.TotalWeight == 0 {
+		return 0
+	}
+	return float64(r.ParticipantsWeight) / float64(r.TotalWeight)
+}
+
+// tally tracks the commits and reveals of a poll.
+type tally struct {
+	Poll
+	keys        KeyChecker
+	commitments map[factom.Bytes32]factom.Bytes32
+	votes       map[factom.Bytes32][]string
+}
+
+func (t *tally) commit(e factom.Entry) error {
+	if len(e.ExtIDs) != 4 || len(e.ExtIDs[1]) != len(factom.Bytes32{}) {
+		return fmt.Errorf("invalid ExtIDs")
+	}
+	if e.Timestamp.Before(t.CommitStart) || !e.Timestamp.Before(t.CommitEnd) {
+		return fmt.Errorf("not in commit phase")
+	}
+	var voter factom.Bytes32
+	copy(voter[:], e.ExtIDs[1])
+	if _, ok := t.voter(voter); !ok {
+		return fmt.Errorf("voter is not eligible")
+	}
+	key, err := validate(e.ExtIDs[2], e.ExtIDs[3],
+		t.commitMsg(e.ExtIDs[1], e.Content))
+	if err != nil {
+		return err
+	}
+	if !t.keys(voter, key) {
+		return fmt.Errorf("key is not active")
+	}
+	var content commitContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return fmt.Errorf("invalid content: %w", err)
+	}
+	t.commitments[voter] = content.Commitment
+	return nil
+}
+
+func (t *tally) reveal(e factom.Entry) error {
+	if len(e.ExtIDs) != 2 || len(e.ExtIDs[1]) != len(factom.Bytes32{}) {
+		return fmt.Errorf("invalid ExtIDs")
+	}
+	if e.Timestamp.Before(t.CommitEnd) || !e.Timestamp.Before(t.RevealEnd) {
+		return fmt.Errorf("not in reveal phase")
+	}
+	var voter factom.Bytes32
+	copy(voter[:], e.ExtIDs[1])
+	commitment, ok := t.commitments[voter]
+	if !ok {
+		return fmt.Errorf("no commitment")
+	}
+	if _, ok := t.votes[voter]; ok {
+		return fmt.Errorf("already revealed")
+	}
+	var content revealContent
+	d := json.NewDecoder(bytes.NewReader(e.Content))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&content); err != nil {
+		return fmt.Errorf("invalid content: %w", err)
+	}
+	b := Ballot{Voter: voter, Vote: content.Vote, Secret: content.Secret}
+	if b.Commitment() != commitment {
+		return fmt.Errorf("vote does not match commitment")
+	}
+	if err := t.ValidVote(b.Vote); err != nil {
+		return err
+	}
+	if b.Vote == nil {
+		b.Vote = []string{}
+	}
+	t.votes[voter] = b.Vote
+	return nil
+}
+
+func (t *tally) result() Result {
+	r := Result{
+		Options: make([]OptionResult, len(t.Options)),
+		Votes:   t.votes,
+	}
+	index := make(map[string]int, len(t.Options))
+	for i, option := range t.Options {
+		r.Options[i].Option = option
+		index[option] = i
+	}
+	for _, v := range t.Voters {
+		r.TotalWeight += v.Weight
+		vote, ok := t.votes[v.ID]
+		if !ok {
+			continue
+		}
+		r.Participants++
+		r.ParticipantsWeight += v.Weight
+		if len(vote) == 0 {
+			r.Abstentions++
+			r.AbstentionsWeight += v.Weight
+		}
+		for _, option := range vote {
+			o := &r.Options[index[option]]
+			o.Count++
+			o.Weight += v.Weight
+		}
+	}
+	return r
+}
+
+// Tally validates all of the entries of a poll chain, in order from the first
+// Entry, using keys to validate signatures, and returns the Poll and its
+// Result. The Entries must have their Timestamps.
+//
+// The first Entry must be a valid poll chain Entry. All subsequent Entries
+// that are not valid commits or reveals are ignored. Votes are only counted
+// once revealed, so the Result is partial until the reveal phase ends.
+func Tally(entries []factom.Entry, keys KeyChecker) (Poll, Result, error) {
+	if len(entries) == 0 {
+		return Poll{}, Result{}, fmt.Errorf("no entries")
+	}
+	p, err := parseFirst(entries[0], keys)
+	if err != nil {
+		return Poll{}, Result{}, err
+	}
+	t := tally{
+		Poll:        p,
+		keys:        keys,
+		commitments: make(map[factom.Bytes32]factom.Bytes32),
+		votes:       make(map[factom.Bytes32][]string),
+	}
+	for _, e := range entries[1:] {
+		if len(e.ExtIDs) == 0 {
+			continue
+		}
+		// Invalid entries are ignored.
+		switch {
+		case bytes.Equal(e.ExtIDs[0], CommitTag):
+			_ = t.commit(e)
+		case bytes.Equal(e.ExtIDs[0], RevealTag):
+			_ = t.reveal(e)
+		}
+	}
+	return p, t.result(), nil
+}
+
+// Get downloads all Entries of the poll chain with the given chainID and
+// tallies the poll. See Tally.
+func Get(ctx context.Context, c *factom.Client, chainID factom.Bytes32,
+	keys KeyChecker) (Poll, Result, error) {
+	typed, err := factom.Chain{ID: chainID}.GetAllEntries(ctx, c)
+	if err != nil {
+		return Poll{}, Result{}, err
+	}
+	entries := make([]factom.Entry, len(typed))
+	for i := range typed {
+		entries[i] = typed[i].Entry
+	}
+	return Tally(entries, keys)
+}
@@ -0,0 +1,208 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package vote_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	"github.com/Factom-Asset-Tokens/factom/appidentity"
+	. "github.com/Factom-Asset-Tokens/factom/vote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdentity(t *testing.T, name string) (appidentity.Identity, factom.SK1Key) {
+	sk, err := factom.GenerateSK1Key()
+	require.NoError(t, err)
+	id, _, err := appidentity.New([]factom.ID1Key{sk.ID1Key()},
+		factom.Bytes(name))
+	require.NoError(t, err)
+	return id, sk
+}
+
+func at(e factom.Entry, ts time.Time) factom.Entry {
+	e.Timestamp = ts
+	return e
+}
+
+func TestVote(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	council, councilSK := newIdentity(t, "council")
+	alice, aliceSK := newIdentity(t, "alice")
+	bob, bobSK := newIdentity(t, "bob")
+	carol, carolSK := newIdentity(t, "carol")
+	mallory, mallorySK := newIdentity(t, "mallory")
+	keys := IdentityKeys(council, alice, bob, carol, mallory)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := Definition{
+		Version:         Version,
+		Title:           "Upgrade",
+		Options:         []string{"yes", "no"},
+		MinOptions:      1,
+		MaxOptions:      1,
+		AllowAbstention: true,
+		CommitStart:     start,
+		CommitEnd:       start.Add(time.Hour),
+		RevealEnd:       start.Add(2 * time.Hour),
+		Voters: []Voter{
+			{ID: alice.ChainID, Weight: 3},
+			{ID: bob.ChainID, Weight: 2},
+			{ID: carol.ChainID, Weight: 2},
+		},
+	}
+	p, first, err := New(d, council.ChainID, councilSK)
+	require.NoError(err)
+	assert.Equal(factom.ComputeChainID(first.ExtIDs), p.ChainID)
+	entries := []factom.Entry{at(first, start.Add(-time.Hour))}
+	commit := start.Add(time.Minute)
+	reveal := start.Add(time.Hour + time.Minute)
+
+	_, err = p.NewBallot(mallory.ChainID, []string{"yes"})
+	assert.EqualError(err, "voter is not eligible")
+	_, err = p.NewBallot(alice.ChainID, []string{"yes", "no"})
+	assert.EqualError(err, "invalid number of options: 2")
+	_, err = p.NewBallot(alice.ChainID, []string{"maybe"})
+	assert.EqualError(err, `unknown option: "maybe"`)
+
+	aliceBallot, err := p.NewBallot(alice.ChainID, []string{"no"})
+	require.NoError(err)
+	bobBallot, err := p.NewBallot(bob.ChainID, []string{"yes"})
+	require.NoError(err)
+	carolBallot, err := p.NewBallot(carol.ChainID, nil)
+	require.NoError(err)
+
+	// Alice changes her vote before the commit phase ends.
+	aliceFirst := aliceBallot
+	aliceFirst.Vote = []string{"yes"}
+	entries = append(entries,
+		at(p.CommitEntry(aliceFirst, aliceSK), commit),
+		at(p.CommitEntry(aliceBallot, aliceSK), commit),
+		at(p.CommitEntry(bobBallot, bobSK), commit),
+		at(p.CommitEntry(carolBallot, carolSK), commit),
+		// Commits must be signed by the voter's own key.
+		at(p.CommitEntry(Ballot{Voter: bob.ChainID}, mallorySK), commit),
+		// Reveals during the commit phase are ignored.
+		at(p.RevealEntry(bobBallot), commit),
+	)
+
+	// Votes are not counted until they are revealed.
+	_, result, err := Tally(entries, keys)
+	require.NoError(err)
+	assert.Equal(0, result.Participants)
+
+	entries = append(entries,
+		at(p.RevealEntry(aliceFirst), reveal),
+		at(p.RevealEntry(aliceBallot), reveal),
+		at(p.RevealEntry(bobBallot), reveal),
+		at(p.RevealEntry(carolBallot), reveal),
+		// Commits during the reveal phase are ignored.
+		at(p.CommitEntry(aliceFirst, aliceSK), reveal),
+		at(p.RevealEntry(aliceFirst), reveal),
+	)
+	tallied, result, err := Tally(entries, keys)
+	require.NoError(err)
+	assert.Equal(p, tallied)
+	assert.Equal([]OptionResult{
+		{Option: "yes", Count: 1, Weight: 2},
+		{Option: "no", Count: 1, Weight: 3},
+	}, result.Options)
+	assert.Equal(1, result.Abstentions)
+	assert.Equal(uint64(2), result.AbstentionsWeight)
+	assert.Equal(3, result.Participants)
+	assert.Equal(uint64(7), result.TotalWeight)
+	assert.Equal(1.0, result.Turnout())
+	assert.Equal([]string{"no"}, result.Votes[alice.ChainID])
+	winner, ok := result.Winner()
+	assert.True(ok)
+	assert.Equal("no", winner)
+
+	// Polls must be initiated with the initiator's own key.
+	_, first, err = New(d, council.ChainID, mallorySK)
+	require.NoError(err)
+	_, _, err = Tally([]factom.Entry{first}, keys)
+	assert.EqualError(err, "first entry: key is not active")
+}
+
+func TestDefinitionValid(t *testing.T) {
+	start := time.Now()
+	valid := func() Definition {
+		return Definition{
+			Version:     Version,
+			Title:       "t",
+			Options:     []string{"a", "b", "c"},
+			MinOptions:  1,
+			MaxOptions:  2,
+			CommitStart: start,
+			CommitEnd:   start.Add(time.Hour),
+			RevealEnd:   start.Add(2 * time.Hour),
+			Voters:      []Voter{{Weight: 1}},
+		}
+	}
+	require.NoError(t, valid().Valid())
+	for _, test := range []struct {
+		Name   string
+		Modify func(*Definition)
+		Error  string
+	}{{
+		Name:   "version",
+		Modify: func(d *Definition) { d.Version = 2 },
+		Error:  "unsupported version: 2",
+	}, {
+		Name:   "options",
+		Modify: func(d *Definition) { d.Options = d.Options[:1] },
+		Error:  "too few options",
+	}, {
+		Name:   "duplicate option",
+		Modify: func(d *Definition) { d.Options[1] = "a" },
+		Error:  `duplicate option: "a"`,
+	}, {
+		Name:   "max options",
+		Modify: func(d *Definition) { d.MaxOptions = 4 },
+		Error:  "invalid min or max options",
+	}, {
+		Name:   "phases",
+		Modify: func(d *Definition) { d.RevealEnd = d.CommitEnd },
+		Error:  "invalid phases",
+	}, {
+		Name:   "no voters",
+		Modify: func(d *Definition) { d.Voters = nil },
+		Error:  "no voters",
+	}, {
+		Name: "duplicate voter",
+		Modify: func(d *Definition) {
+			d.Voters = append(d.Voters, d.Voters[0])
+		},
+		Error: "duplicate voter: " + factom.Bytes32{}.String(),
+	}} {
+		t.Run(test.Name, func(t *testing.T) {
+			d := valid()
+			test.Modify(&d)
+			assert.EqualError(t, d.Valid(), test.Error)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet
+
+import (
+	"fmt"
+	"io"
+)
+
+// Encrypter encrypts backups to one or more recipients.
+//
+// The method matches the shape of age.Encrypt and openpgp.Encrypt, so either
+// may be used with a small adapter, e.g.
+//
+//	enc := wallet.EncrypterFunc(func(out io.Writer) (io.WriteCloser, error) {
+//		return age.Encrypt(out, recipients...)
+//	})
+type Encrypter interface {
+	// Encrypt returns a WriteCloser that writes the encryption of all
+	// data written to it to out. The encryption is complete only after
+	// Close returns, which must not close out.
+	Encrypt(out io.Writer) (io.WriteCloser, error)
+}
+
+// Decrypter decrypts backups encrypted by an Encrypter.
+//
+//	dec := wallet.DecrypterFunc(func(in io.Reader) (io.Reader, error) {
+//		return age.Decrypt(in, identities...)
+//	})
+type Decrypter interface {
+	// Decrypt returns a Reader of the decryption of in.
+	Decrypt(in io.Reader) (io.Reader, error)
+}
+
+// EncrypterFunc adapts a function to an Encrypter.
+type EncrypterFunc func(out io.Writer) (io.WriteCloser, error)
+
+// Encrypt calls f(out).
+func (f EncrypterFunc) Encrypt(out io.Writer) (io.WriteCloser, error) {
+	return f(out)
+}
+
+// DecrypterFunc adapts a function to a Decrypter.
+type DecrypterFunc func(in io.Reader) (io.Reader, error)
+
+// Decrypt calls f(in).
+func (f DecrypterFunc) Decrypt(in io.Reader) (io.Reader, error) {
+	return f(in)
+}
+
+// BackupEncrypted writes a Backup of w to out, encrypted with enc, so that it
+// may be stored, e.g. in cloud storage, without exposing the private keys.
+func (w *Wallet) BackupEncrypted(out io.Writer, enc Encrypter) error {
+	plaintext, err := enc.Encrypt(out)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+	if err := w.Backup(plaintext); err != nil {
+		plaintext.Close()
+		return err
+	}
+	if err := plaintext.Close(); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+	return nil
+}
+
+// RestoreEncrypted decrypts in with dec and then Restores the backup.
+func RestoreEncrypted(in io.Reader, dec Decrypter) (*Wallet, error) {
+	plaintext, err := dec.Decrypt(in)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return Restore(plaintext)
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// aesCTR returns a symmetric Encrypter and Decrypter standing in for age or
+// OpenPGP recipients.
+func aesCTR(key byte) (Encrypter, Decrypter) {
+	stream := func() cipher.Stream {
+		block, _ := aes.NewCipher(bytes.Repeat([]byte{key}, 32))
+		return cipher.NewCTR(block, make([]byte, aes.BlockSize))
+	}
+	enc := EncrypterFunc(func(out io.Writer) (io.WriteCloser, error) {
+		return cipher.StreamWriter{S: stream(), W: out}, nil
+	})
+	dec := DecrypterFunc(func(in io.Reader) (io.Reader, error) {
+		return cipher.StreamReader{S: stream(), R: in}, nil
+	})
+	return enc, dec
+}
+
+func TestWalletBackupEncrypted(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	w, err := New(fs)
+	require.NoError(err)
+	enc, dec := aesCTR(1)
+
+	var buf bytes.Buffer
+	require.NoError(w.BackupEncrypted(&buf, enc))
+	assert.NotContains(buf.String(), fs.String())
+	assert.NotContains(buf.String(), "origin")
+
+	ciphertext := buf.Bytes()
+	restored, err := RestoreEncrypted(bytes.NewReader(ciphertext), dec)
+	require.NoError(err)
+	assert.Equal(w.Keys()[0].Address, restored.Keys()[0].Address)
+
+	_, wrongDec := aesCTR(2)
+	_, err = RestoreEncrypted(bytes.NewReader(ciphertext), wrongDec)
+	assert.Error(err)
+
+	failEnc := EncrypterFunc(func(io.Writer) (io.WriteCloser, error) {
+		return nil, fmt.Errorf("no recipients")
+	})
+	assert.EqualError(w.BackupEncrypted(&buf, failEnc),
+		"encrypt: no recipients")
+	failDec := DecrypterFunc(func(io.Reader) (io.Reader, error) {
+		return nil, fmt.Errorf("no identity")
+	})
+	_, err = RestoreEncrypted(&buf, failDec)
+	assert.EqualError(err, "decrypt: no identity")
+}
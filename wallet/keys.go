@@ -0,0 +1,258 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Origin records how a key came to be held by a Wallet.
+type Origin int
+
+// Origins of keys.
+const (
+	// OriginImported keys were created elsewhere and added to the Wallet.
+	OriginImported Origin = iota
+
+	// OriginGenerated keys were randomly generated by the Wallet.
+	OriginGenerated
+
+	// OriginDerived keys were deterministically derived from a seed, at
+	// the KeyInfo.DerivationPath.
+	OriginDerived
+)
+
+// String returns the name of o.
+func (o Origin) String() string {
+	switch o {
+	case OriginImported:
+		return "imported"
+	case OriginGenerated:
+		return "generated"
+	case OriginDerived:
+		return "derived"
+	default:
+		return fmt.Sprintf("Origin(%d)", int(o))
+	}
+}
+
+// MarshalText encodes o as its name.
+func (o Origin) MarshalText() ([]byte, error) {
+	switch o {
+	case OriginImported, OriginGenerated, OriginDerived:
+		return []byte(o.String()), nil
+	}
+	return nil, fmt.Errorf("invalid origin: %d", int(o))
+}
+
+// UnmarshalText decodes the name of an Origin into o.
+func (o *Origin) UnmarshalText(text []byte) error {
+	for _, origin := range []Origin{
+		OriginImported, OriginGenerated, OriginDerived} {
+		if string(text) == origin.String() {
+			*o = origin
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid origin: %q", text)
+}
+
+// KeyInfo is the metadata that a Wallet records for each key, so that the
+// custody of every key can be audited.
+type KeyInfo struct {
+	Address factom.FAAddress `json:"address"`
+
+	// Label is a free form, user assigned description of the key.
+	Label string `json:"label,omitempty"`
+
+	Origin Origin `json:"origin"`
+
+	// DerivationPath is set if, and only if, Origin is OriginDerived,
+	// e.g. "m/44'/131'/0'/0'/0'".
+	DerivationPath string `json:"derivationpath,omitempty"`
+
+	// Created is when the key was added to the Wallet, or the original
+	// creation time if it was restored or supplied by the caller.
+	Created time.Time `json:"created"`
+}
+
+type key struct {
+	fs   factom.FsAddress
+	info KeyInfo
+}
+
+// GenerateKey generates a new random key with the given label and adds it to
+// w as OriginGenerated.
+//
+// If w is public, ErrorPublicWallet is returned.
+func (w *Wallet) GenerateKey(label string) (KeyInfo, error) {
+	if w.IsPublic() {
+		return KeyInfo{}, ErrorPublicWallet{Op: "generate key"}
+	}
+	fs, err := factom.GenerateFsAddress()
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return w.AddKey(fs, KeyInfo{Label: label, Origin: OriginGenerated})
+}
+
+// AddKey adds fs to w with the metadata in info. The info.Address is set from
+// fs, and the info.Created time defaults to the current time. The
+// info.DerivationPath must be set if, and only if, info.Origin is
+// OriginDerived. An error is returned if fs is already held by w.
+//
+// If w is public, ErrorPublicWallet is returned.
+func (w *Wallet) AddKey(fs factom.FsAddress, info KeyInfo) (KeyInfo, error) {
+	if w.IsPublic() {
+		return KeyInfo{}, ErrorPublicWallet{Op: "add key"}
+	}
+	info.Address = fs.FAAddress()
+	if info.Created.IsZero() {
+		info.Created = time.Now()
+	}
+	if err := info.validate(); err != nil {
+		return KeyInfo{}, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if k, ok := w.keys[info.Address]; ok {
+		return KeyInfo{}, fmt.Errorf("key already exists: %v",
+			k.info.Address)
+	}
+	w.keys[info.Address] = key{fs, info}
+	return info, nil
+}
+
+func (info KeyInfo) validate() error {
+	if _, err := info.Origin.MarshalText(); err != nil {
+		return err
+	}
+	if (info.Origin == OriginDerived) != (len(info.DerivationPath) > 0) {
+		return fmt.Errorf("derivation path must be set only for %v keys",
+			OriginDerived)
+	}
+	return nil
+}
+
+// Key returns the KeyInfo for adr, if adr is held by w.
+func (w *Wallet) Key(adr factom.FAAddress) (KeyInfo, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	k, ok := w.keys[adr]
+	return k.info, ok
+}
+
+// Keys returns the KeyInfo of all keys held by w, ordered by their Created
+// time.
+func (w *Wallet) Keys() []KeyInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	keys := make([]KeyInfo, 0, len(w.keys))
+	for _, k := range w.keys {
+		keys = append(keys, k.info)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].Created.Equal(keys[j].Created) {
+			return keys[i].Created.Before(keys[j].Created)
+		}
+		return keys[i].Address.String() < keys[j].Address.String()
+	})
+	return keys
+}
+
+// SetLabel replaces the Label of the key for adr.
+func (w *Wallet) SetLabel(adr factom.FAAddress, label string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	k, ok := w.keys[adr]
+	if !ok {
+		return fmt.Errorf("address not found in wallet: %v", adr)
+	}
+	k.info.Label = label
+	w.keys[adr] = k
+	return nil
+}
+
+// BackupVersion is the version of the format written by Backup.
+const BackupVersion = 1
+
+type backup struct {
+	Version uint        `json:"version"`
+	Keys    []backupKey `json:"keys"`
+}
+
+type backupKey struct {
+	Secret factom.FsAddress `json:"secret"`
+	KeyInfo
+}
+
+// Backup writes all keys held by w, along with their KeyInfo, to out as JSON.
+// The backup contains the private keys in the clear and must be protected
+// accordingly. Temporary transactions are not included.
+//
+// If w is public, ErrorPublicWallet is returned.
+func (w *Wallet) Backup(out io.Writer) error {
+	if w.IsPublic() {
+		return ErrorPublicWallet{Op: "backup"}
+	}
+	infos := w.Keys()
+	b := backup{Version: BackupVersion, Keys: make([]backupKey, len(infos))}
+	w.mu.Lock()
+	for i, info := range infos {
+		b.Keys[i] = backupKey{Secret: w.keys[info.Address].fs, KeyInfo: info}
+	}
+	w.mu.Unlock()
+	return json.NewEncoder(out).Encode(b)
+}
+
+// Restore returns a new Wallet holding the keys, and their KeyInfo, read from
+// a backup written by Backup.
+func Restore(in io.Reader) (*Wallet, error) {
+	var b backup
+	if err := json.NewDecoder(in).Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	if b.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version: %v", b.Version)
+	}
+	w := newWallet()
+	for i, k := range b.Keys {
+		if k.Address != k.Secret.FAAddress() {
+			return nil, fmt.Errorf(
+				"keys[%v]: address does not match secret", i)
+		}
+		if k.Created.IsZero() {
+			return nil, fmt.Errorf("keys[%v]: missing created time", i)
+		}
+		if _, err := w.AddKey(k.Secret, k.KeyInfo); err != nil {
+			return nil, fmt.Errorf("keys[%v]: %w", i, err)
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletKeys(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	imported, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	w, err := New(imported)
+	require.NoError(err)
+
+	info, ok := w.Key(imported.FAAddress())
+	require.True(ok)
+	assert.Equal(OriginImported, info.Origin)
+	assert.False(info.Created.IsZero())
+
+	generated, err := w.GenerateKey("hot")
+	require.NoError(err)
+	assert.Equal(OriginGenerated, generated.Origin)
+	assert.Equal("hot", generated.Label)
+
+	derived, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	_, err = w.AddKey(derived, KeyInfo{Origin: OriginDerived})
+	assert.EqualError(err,
+		"derivation path must be set only for derived keys")
+	_, err = w.AddKey(derived, KeyInfo{Origin: Origin(5)})
+	assert.EqualError(err, "invalid origin: 5")
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	info, err = w.AddKey(derived, KeyInfo{
+		Origin:         OriginDerived,
+		DerivationPath: "m/44'/131'/0'/0'/0'",
+		Created:        created,
+	})
+	require.NoError(err)
+	assert.Equal(derived.FAAddress(), info.Address)
+	_, err = w.AddKey(derived, KeyInfo{})
+	assert.EqualError(err, "key already exists: "+
+		derived.FAAddress().String())
+
+	require.NoError(w.SetLabel(derived.FAAddress(), "cold"))
+	assert.Error(w.SetLabel(factom.FAAddress{}, "none"))
+
+	keys := w.Keys()
+	require.Len(keys, 3)
+	assert.Equal(derived.FAAddress(), keys[0].Address)
+	assert.Equal("cold", keys[0].Label)
+
+	var buf bytes.Buffer
+	require.NoError(w.Backup(&buf))
+	assert.Contains(buf.String(), `"origin":"derived"`)
+	restored, err := Restore(&buf)
+	require.NoError(err)
+	restoredKeys := restored.Keys()
+	require.Len(restoredKeys, 3)
+	for i := range keys {
+		assert.Equal(keys[i].Address, restoredKeys[i].Address)
+		assert.Equal(keys[i].Label, restoredKeys[i].Label)
+		assert.Equal(keys[i].Origin, restoredKeys[i].Origin)
+		assert.Equal(keys[i].DerivationPath,
+			restoredKeys[i].DerivationPath)
+		assert.True(keys[i].Created.Equal(restoredKeys[i].Created))
+	}
+
+	// Restored keys may be used to sign.
+	_, err = restored.NewTransaction("tx")
+	require.NoError(err)
+	_, err = restored.AddInput("tx", imported.FAAddress(), 1)
+	assert.NoError(err)
+
+	for _, test := range []struct {
+		Name   string
+		Backup string
+		Error  string
+	}{{
+		Name:   "version",
+		Backup: `{"version":2}`,
+		Error:  "unsupported backup version: 2",
+	}, {
+		Name: "address",
+		Backup: `{"version":1,"keys":[{"secret":"` + imported.String() +
+			`","address":"` + derived.FAAddress().String() + `"}]}`,
+		Error: "keys[0]: address does not match secret",
+	}, {
+		Name: "created",
+		Backup: `{"version":1,"keys":[{"secret":"` + imported.String() +
+			`","address":"` + imported.FAAddress().String() +
+			`","origin":"imported"}]}`,
+		Error: "keys[0]: missing created time",
+	}} {
+		_, err := Restore(strings.NewReader(test.Backup))
+		assert.EqualError(err, test.Error, test.Name)
+	}
+}
@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// ErrorPublicWallet is returned by the operations of a public Wallet that
+// require private keys, such as signing.
+type ErrorPublicWallet struct {
+	// Op is the refused operation, e.g. "sign".
+	Op string
+}
+
+// Error implements error.
+func (err ErrorPublicWallet) Error() string {
+	return fmt.Sprintf("%v: public wallet holds no private keys", err.Op)
+}
+
+// NewPublic returns a public Wallet holding only the addresses in keys. The
+// Created time of each KeyInfo defaults to the current time.
+//
+// A public Wallet can build temporary transactions from its addresses, but
+// refuses to sign, generate or add keys, or Backup, with ErrorPublicWallet.
+// This allows front end services to share wallet code with the signers
+// without ever holding a private key.
+func NewPublic(keys ...KeyInfo) (*Wallet, error) {
+	w := newWallet()
+	w.public = true
+	now := time.Now()
+	for _, info := range keys {
+		if info.Address == (factom.FAAddress{}) {
+			return nil, fmt.Errorf("missing address")
+		}
+		if err := info.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := w.keys[info.Address]; ok {
+			return nil, fmt.Errorf("key already exists: %v",
+				info.Address)
+		}
+		if info.Created.IsZero() {
+			info.Created = now
+		}
+		w.keys[info.Address] = key{info: info}
+	}
+	return w, nil
+}
+
+// Public returns a public Wallet holding the addresses and KeyInfo of all keys
+// of w, but none of the private keys. Temporary transactions are not copied.
+func (w *Wallet) Public() *Wallet {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	public := newWallet()
+	public.public = true
+	for adr, k := range w.keys {
+		public.keys[adr] = key{info: k.info}
+	}
+	return public
+}
+
+// IsPublic returns true if w holds no private keys because it was returned by
+// NewPublic or Wallet.Public.
+func (w *Wallet) IsPublic() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.public
+}
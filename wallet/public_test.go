@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicWallet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	private, err := New(fs)
+	require.NoError(err)
+	require.NoError(private.SetLabel(fs.FAAddress(), "treasury"))
+	assert.False(private.IsPublic())
+
+	w := private.Public()
+	assert.True(w.IsPublic())
+	assert.Equal(private.Keys(), w.Keys())
+
+	// Unsigned transactions may still be built.
+	_, err = w.NewTransaction("tx")
+	require.NoError(err)
+	tx, err := w.AddInput("tx", fs.FAAddress(), 10)
+	require.NoError(err)
+	assert.Equal(factom.Amount(10), tx.TotalInputs)
+
+	_, err = w.Sign(context.Background(), nil, "tx", true)
+	assert.Equal(ErrorPublicWallet{Op: "sign"}, err)
+	_, err = w.Compose("tx")
+	assert.EqualError(err, "transaction is not signed")
+	_, err = w.GenerateKey("")
+	assert.Equal(ErrorPublicWallet{Op: "generate key"}, err)
+	_, err = w.AddKey(fs, KeyInfo{})
+	assert.Equal(ErrorPublicWallet{Op: "add key"}, err)
+	assert.Equal(ErrorPublicWallet{Op: "add keys"}, w.AddKeys(fs))
+	err = w.Backup(&bytes.Buffer{})
+	var errPublic ErrorPublicWallet
+	require.True(errors.As(err, &errPublic))
+	assert.EqualError(err,
+		"backup: public wallet holds no private keys")
+
+	w, err = NewPublic(KeyInfo{Address: fs.FAAddress(), Label: "hot"})
+	require.NoError(err)
+	assert.True(w.IsPublic())
+	info, ok := w.Key(fs.FAAddress())
+	require.True(ok)
+	assert.Equal("hot", info.Label)
+	assert.False(info.Created.IsZero())
+
+	_, err = NewPublic(KeyInfo{})
+	assert.EqualError(err, "missing address")
+	_, err = NewPublic(KeyInfo{Address: fs.FAAddress()},
+		KeyInfo{Address: fs.FAAddress()})
+	assert.EqualError(err, "key already exists: "+
+		fs.FAAddress().String())
+}
@@ -0,0 +1,415 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package wallet implements the temporary transaction workflow of
+// factom-walletd locally, so that tools built on factom.WalletTransaction can
+// hold their keys in process without changing how users build transactions.
+//
+// A Wallet holds Factoid private keys, along with the KeyInfo metadata that
+// records where each key came from, and a set of named temporary
+// transactions. As in factom-walletd, a transaction is created with
+// NewTransaction, built up with AddInput, AddOutput, AddECOutput, and AddFee
+// or SubFee, signed with Sign, and then composed into its binary form with
+// Compose for submission with factom.Client.FactoidSubmit. Transactions are
+// listed with Transactions and removed with DeleteTransaction.
+//
+// Temporary transactions live only in memory, exactly as they do in
+// factom-walletd, and are lost when the Wallet is discarded. The keys and their
+// KeyInfo may be saved with Backup and restored with Restore, or encrypted to
+// age, OpenPGP, or other recipients with BackupEncrypted and
+// RestoreEncrypted.
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// Wallet holds Factoid private keys and named temporary transactions. It is
+// safe for concurrent use.
+//
+// A public Wallet, returned by NewPublic or Wallet.Public, holds only the
+// addresses and KeyInfo of its keys.
+type Wallet struct {
+	mu     sync.Mutex
+	keys   map[factom.FAAddress]key
+	txs    map[string]*factom.Transaction
+	public bool
+}
+
+// New returns a Wallet holding keys.
+func New(keys ...factom.FsAddress) (*Wallet, error) {
+	w := newWallet()
+	if err := w.AddKeys(keys...); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func newWallet() *Wallet {
+	return &Wallet{
+		keys: make(map[factom.FAAddress]key),
+		txs:  make(map[string]*factom.Transaction),
+	}
+}
+
+// AddKeys adds keys to w, which allows their FAAddresses to be used as inputs.
+// The keys are recorded as OriginImported at the current time. Keys already in
+// w are ignored. Use AddKey to record other KeyInfo.
+//
+// If w is public, ErrorPublicWallet is returned.
+func (w *Wallet) AddKeys(keys ...factom.FsAddress) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.public {
+		return ErrorPublicWallet{Op: "add keys"}
+	}
+	now := time.Now()
+	for _, fs := range keys {
+		if _, ok := w.keys[fs.FAAddress()]; ok {
+			continue
+		}
+		w.keys[fs.FAAddress()] = key{fs, KeyInfo{
+			Address: fs.FAAddress(),
+			Origin:  OriginImported,
+			Created: now,
+		}}
+	}
+	return nil
+}
+
+// NewTransaction creates a new empty temporary transaction called name. The
+// name must not be empty or already in use.
+func (w *Wallet) NewTransaction(name string) (factom.WalletTransaction, error) {
+	if len(name) == 0 {
+		return factom.WalletTransaction{},
+			fmt.Errorf("missing transaction name")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.txs[name]; ok {
+		return factom.WalletTransaction{},
+			fmt.Errorf("transaction name already exists: %q", name)
+	}
+	tx := factom.Transaction{TimestampSalt: time.Now()}
+	w.txs[name] = &tx
+	return summary(name, &tx), nil
+}
+
+// DeleteTransaction removes the temporary transaction called name.
+func (w *Wallet) DeleteTransaction(name string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.txs[name]; !ok {
+		return fmt.Errorf("transaction not found: %q", name)
+	}
+	delete(w.txs, name)
+	return nil
+}
+
+// Transactions returns a summary of all temporary transactions, sorted by
+// name.
+func (w *Wallet) Transactions() []factom.WalletTransaction {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	txs := make([]factom.WalletTransaction, 0, len(w.txs))
+	for name, tx := range w.txs {
+		txs = append(txs, summary(name, tx))
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Name < txs[j].Name })
+	return txs
+}
+
+// AddInput adds adr as an input of amount factoshis to the transaction called
+// name. If adr is already an input, its amount is replaced. The private key
+// for adr must be held by w, or if w is public, its address.
+func (w *Wallet) AddInput(name string, adr factom.FAAddress,
+	amount uint64) (factom.WalletTransaction, error) {
+	return w.modify(name, func(tx *factom.Transaction) error {
+		if _, ok := w.keys[adr]; !ok {
+			return fmt.Errorf("address not found in wallet: %v", adr)
+		}
+		if i := index(tx.FCTInputs, adr[:]); i >= 0 {
+			tx.FCTInputs[i].Amount = amount
+			return nil
+		}
+		tx.FCTInputs = append(tx.FCTInputs,
+			factom.AddressAmount{Address: adr[:], Amount: amount})
+		return nil
+	})
+}
+
+// AddOutput adds adr as an output of amount factoshis to the transaction
+// called name.
+func (w *Wallet) AddOutput(name string, adr factom.FAAddress,
+	amount uint64) (factom.WalletTransaction, error) {
+	return w.modify(name, func(tx *factom.Transaction) error {
+		tx.FCTOutputs = append(tx.FCTOutputs,
+			factom.AddressAmount{Address: adr[:], Amount: amount})
+		return nil
+	})
+}
+
+// AddECOutput adds adr as an Entry Credit output, purchased with amount
+// factoshis, to the transaction called name.
+func (w *Wallet) AddECOutput(name string, adr factom.ECAddress,
+	amount uint64) (factom.WalletTransaction, error) {
+	return w.modify(name, func(tx *factom.Transaction) error {
+		tx.ECOutputs = append(tx.ECOutputs,
+			factom.AddressAmount{Address: adr[:], Amount: amount})
+		return nil
+	})
+}
+
+// AddFee increases the input from adr by the fee required at the current
+// Entry Credit rate. As in factom-walletd, adr must be an input and the inputs
+// and outputs of the transaction must balance before the fee is added.
+func (w *Wallet) AddFee(ctx context.Context, c *factom.Client,
+	name string, adr factom.FAAddress) (factom.WalletTransaction, error) {
+	return w.modifyFee(ctx, c, name, func(tx *factom.Transaction,
+		fee uint64) error {
+		i := index(tx.FCTInputs, adr[:])
+		if i < 0 {
+			return fmt.Errorf("%v is not an input", adr)
+		}
+		tx.FCTInputs[i].Amount += fee
+		return nil
+	})
+}
+
+// SubFee decreases the output to adr by the fee required at the current Entry
+// Credit rate. As in factom-walletd, adr must be an output and the inputs and
+// outputs of the transaction must balance before the fee is subtracted.
+func (w *Wallet) SubFee(ctx context.Context, c *factom.Client,
+	name string, adr factom.FAAddress) (factom.WalletTransaction, error) {
+	return w.modifyFee(ctx, c, name, func(tx *factom.Transaction,
+		fee uint64) error {
+		i := index(tx.FCTOutputs, adr[:])
+		if i < 0 {
+			return fmt.Errorf("%v is not an output", adr)
+		}
+		if tx.FCTOutputs[i].Amount <= fee {
+			return fmt.Errorf("output %v does not cover fee %v",
+				tx.FCTOutputs[i].Amount, fee)
+		}
+		tx.FCTOutputs[i].Amount -= fee
+		return nil
+	})
+}
+
+// Sign signs the transaction called name with the keys held by w. Unless
+// force is true, the transaction must pay at least the fee required at the
+// current Entry Credit rate.
+//
+// If w is public, ErrorPublicWallet is returned.
+func (w *Wallet) Sign(ctx context.Context, c *factom.Client,
+	name string, force bool) (factom.WalletTransaction, error) {
+	if w.IsPublic() {
+		return factom.WalletTransaction{}, ErrorPublicWallet{Op: "sign"}
+	}
+	var fee uint64
+	if !force {
+		ecRate, err := c.GetECRate(ctx)
+		if err != nil {
+			return factom.WalletTransaction{}, err
+		}
+		if fee, err = w.requiredFee(name, ecRate); err != nil {
+			return factom.WalletTransaction{}, err
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, ok := w.txs[name]
+	if !ok {
+		return factom.WalletTransaction{},
+			fmt.Errorf("transaction not found: %q", name)
+	}
+	in, out, err := totals(tx)
+	if err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	if !force && (in < out || in-out < fee) {
+		return factom.WalletTransaction{},
+			fmt.Errorf("insufficient fee: %v, required: %v", in-out, fee)
+	}
+
+	signers := make([]factom.RCDSigner, len(tx.FCTInputs))
+	for i, input := range tx.FCTInputs {
+		k, ok := w.keys[input.FAAddress()]
+		if !ok {
+			return factom.WalletTransaction{}, fmt.Errorf(
+				"address not found in wallet: %v", input.FAAddress())
+		}
+		signers[i] = k.fs
+	}
+	signed := *tx
+	signed.Signatures = make([]factom.RCDSignature, len(tx.FCTInputs))
+	if _, err := signed.Sign(signers...); err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	*tx = signed
+	sum := summary(name, tx)
+	sum.FeesRequired = factom.Amount(fee)
+	return sum, nil
+}
+
+// Compose returns the binary signed transaction called name, which may be
+// submitted with factom.Client.FactoidSubmit.
+func (w *Wallet) Compose(name string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, ok := w.txs[name]
+	if !ok {
+		return nil, fmt.Errorf("transaction not found: %q", name)
+	}
+	if !tx.IsPopulated() {
+		return nil, fmt.Errorf("transaction is not signed")
+	}
+	return tx.MarshalBinary()
+}
+
+// modify applies f to the transaction called name and discards any
+// signatures, since they no longer match.
+func (w *Wallet) modify(name string,
+	f func(*factom.Transaction) error) (factom.WalletTransaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, ok := w.txs[name]
+	if !ok {
+		return factom.WalletTransaction{},
+			fmt.Errorf("transaction not found: %q", name)
+	}
+	modified := *tx
+	modified.FCTInputs = append([]factom.AddressAmount(nil), tx.FCTInputs...)
+	modified.FCTOutputs = append([]factom.AddressAmount(nil), tx.FCTOutputs...)
+	modified.ECOutputs = append([]factom.AddressAmount(nil), tx.ECOutputs...)
+	modified.Signatures = nil
+	modified.ID = nil
+	modified.ClearMarshalBinaryCache()
+	if err := f(&modified); err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	if _, _, err := totals(&modified); err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	*tx = modified
+	return summary(name, tx), nil
+}
+
+// modifyFee applies f with the required fee to the transaction called name,
+// which must be balanced.
+func (w *Wallet) modifyFee(ctx context.Context, c *factom.Client, name string,
+	f func(*factom.Transaction, uint64) error) (factom.WalletTransaction, error) {
+	ecRate, err := c.GetECRate(ctx)
+	if err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	fee, err := w.requiredFee(name, ecRate)
+	if err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	sum, err := w.modify(name, func(tx *factom.Transaction) error {
+		in, out, err := totals(tx)
+		if err != nil {
+			return err
+		}
+		if in != out {
+			return fmt.Errorf("inputs and outputs do not balance: "+
+				"%v, %v", in, out)
+		}
+		return f(tx, fee)
+	})
+	if err != nil {
+		return factom.WalletTransaction{}, err
+	}
+	sum.FeesRequired = factom.Amount(fee)
+	return sum, nil
+}
+
+func (w *Wallet) requiredFee(name string, ecRate uint64) (uint64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, ok := w.txs[name]
+	if !ok {
+		return 0, fmt.Errorf("transaction not found: %q", name)
+	}
+	unsigned := *tx
+	unsigned.Signatures = nil
+	unsigned.ClearMarshalBinaryCache()
+	return unsigned.RequiredFee(ecRate)
+}
+
+func summary(name string, tx *factom.Transaction) factom.WalletTransaction {
+	sum := factom.WalletTransaction{
+		Name:   name,
+		Signed: tx.IsPopulated(),
+	}
+	// modify ensures that the totals of all transactions in a Wallet do
+	// not overflow.
+	in, out, _ := totals(tx)
+	sum.TotalInputs, sum.TotalOutputs = factom.Amount(in), factom.Amount(out)
+	for _, output := range tx.ECOutputs {
+		sum.TotalECOutputs += factom.Amount(output.Amount)
+	}
+	sum.TotalOutputs -= sum.TotalECOutputs
+	if txID, err := tx.TxID(); err == nil {
+		sum.TxID = &txID
+	}
+	return sum
+}
+
+// totals returns the total of the inputs, and of the FCT and EC outputs, of
+// tx. An error is returned if either total overflows a uint64.
+func totals(tx *factom.Transaction) (in, out uint64, err error) {
+	for _, input := range tx.FCTInputs {
+		if in+input.Amount < in {
+			return 0, 0, fmt.Errorf("total inputs overflow")
+		}
+		in += input.Amount
+	}
+	for _, outputs := range [][]factom.AddressAmount{
+		tx.FCTOutputs, tx.ECOutputs} {
+		for _, output := range outputs {
+			if out+output.Amount < out {
+				return 0, 0, fmt.Errorf("total outputs overflow")
+			}
+			out += output.Amount
+		}
+	}
+	return in, out, nil
+}
+
+func index(adrs []factom.AddressAmount, adr []byte) int {
+	for i, a := range adrs {
+		if bytes.Equal(a.Address, adr) {
+			return i
+		}
+	}
+	return -1
+}
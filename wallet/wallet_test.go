@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ecRate = 1000
+
+func TestWallet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	c := newMockFactomd(t)
+
+	fs, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	fa := fs.FAAddress()
+	other, err := factom.GenerateFsAddress()
+	require.NoError(err)
+	to := other.FAAddress()
+	ec, err := factom.GenerateEsAddress()
+	require.NoError(err)
+
+	w, err := New(fs)
+	require.NoError(err)
+
+	_, err = w.NewTransaction("")
+	assert.EqualError(err, "missing transaction name")
+	tx, err := w.NewTransaction("a")
+	require.NoError(err)
+	assert.Equal(factom.WalletTransaction{Name: "a"}, tx)
+	_, err = w.NewTransaction("a")
+	assert.EqualError(err, `transaction name already exists: "a"`)
+
+	_, err = w.AddInput("b", fa, 1)
+	assert.EqualError(err, `transaction not found: "b"`)
+	_, err = w.AddInput("a", to, 1)
+	assert.EqualError(err, "address not found in wallet: "+to.String())
+
+	_, err = w.AddInput("a", fa, 1)
+	require.NoError(err)
+	tx, err = w.AddInput("a", fa, 15000)
+	require.NoError(err)
+	assert.Equal(factom.Amount(15000), tx.TotalInputs)
+	assert.NotNil(tx.TxID)
+	_, err = w.AddOutput("a", to, 10000)
+	require.NoError(err)
+	tx, err = w.AddECOutput("a", ec.ECAddress(), 5000)
+	require.NoError(err)
+	assert.Equal(factom.Amount(10000), tx.TotalOutputs)
+	assert.Equal(factom.Amount(5000), tx.TotalECOutputs)
+
+	_, err = w.Sign(ctx, c, "a", false)
+	assert.EqualError(err, "insufficient fee: 0, required: 22000")
+
+	_, err = w.SubFee(ctx, c, "a", fa)
+	assert.EqualError(err, fa.String()+" is not an output")
+	tx, err = w.AddFee(ctx, c, "a", fa)
+	require.NoError(err)
+	assert.Equal(factom.Amount(37000), tx.TotalInputs)
+	assert.Equal(factom.Amount(22000), tx.FeesRequired)
+	_, err = w.AddFee(ctx, c, "a", fa)
+	assert.EqualError(err,
+		"inputs and outputs do not balance: 37000, 15000")
+
+	_, err = w.Compose("a")
+	assert.EqualError(err, "transaction is not signed")
+	tx, err = w.Sign(ctx, c, "a", false)
+	require.NoError(err)
+	assert.True(tx.Signed)
+	data, err := w.Compose("a")
+	require.NoError(err)
+	var signed factom.Transaction
+	require.NoError(signed.UnmarshalBinary(data))
+	assert.Equal(*tx.TxID, *signed.ID)
+
+	// Any modification discards the signatures.
+	tx, err = w.AddOutput("a", to, 0)
+	require.NoError(err)
+	assert.False(tx.Signed)
+
+	_, err = w.NewTransaction("b")
+	require.NoError(err)
+	_, err = w.AddInput("b", fa, 20000)
+	require.NoError(err)
+	_, err = w.AddOutput("b", to, 20000)
+	require.NoError(err)
+	tx, err = w.SubFee(ctx, c, "b", to)
+	require.NoError(err)
+	assert.Equal(20000-tx.FeesRequired, tx.TotalOutputs)
+
+	txs := w.Transactions()
+	require.Len(txs, 2)
+	assert.Equal("a", txs[0].Name)
+	assert.Equal("b", txs[1].Name)
+
+	require.NoError(w.DeleteTransaction("a"))
+	assert.EqualError(w.DeleteTransaction("a"),
+		`transaction not found: "a"`)
+	assert.Len(w.Transactions(), 1)
+}
+
+func TestWalletTotalsOverflow(t *testing.T) {
+	require := require.New(t)
+	w, err := New()
+	require.NoError(err)
+	_, err = w.NewTransaction("a")
+	require.NoError(err)
+
+	_, err = w.AddOutput("a", factom.FAAddress{1}, math.MaxUint64)
+	require.NoError(err)
+	_, err = w.AddECOutput("a", factom.ECAddress{2}, 1)
+	require.EqualError(err, "total outputs overflow")
+
+	// The failed modification is not applied.
+	txs := w.Transactions()
+	require.Len(txs, 1)
+	require.Equal(factom.Amount(math.MaxUint64), txs[0].TotalOutputs)
+	require.Equal(factom.Amount(0), txs[0].TotalECOutputs)
+}
+
+func newMockFactomd(t *testing.T) *factom.Client {
+	c := factom.NewClient()
+	c.Factomd.Client.Transport = roundTripFunc(
+		func(req *http.Request) *http.Response {
+			var jReq struct {
+				Method string      `json:"method"`
+				ID     interface{} `json:"id"`
+			}
+			reqData, _ := ioutil.ReadAll(req.Body)
+			_ = json.Unmarshal(reqData, &jReq)
+			res := jsonrpc2.Response{ID: jReq.ID}
+			switch jReq.Method {
+			case "entry-credit-rate":
+				res.Result = map[string]interface{}{"rate": ecRate}
+			default:
+				t.Errorf("unexpected request: %v", jReq.Method)
+			}
+			respData, _ := json.Marshal(res)
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewBuffer(respData)),
+				Header: make(http.Header),
+			}
+		})
+	return c
+}
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
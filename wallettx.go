@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"context"
+	"fmt"
+)
+
+// WalletTransaction is a Factoid Transaction under construction in
+// factom-walletd.
+//
+// factom-walletd identifies each temporary transaction by a unique name, the
+// "tx-name", which is chosen by the caller of Create and is used for all
+// subsequent requests. The name is local to the wallet and is never included
+// in the Transaction. Use it as a label for the purpose of the Transaction,
+// and use the memo package to record a memo on chain.
+type WalletTransaction struct {
+	Name string `json:"name"`
+
+	// The following are populated by every successful request, except
+	// Delete.
+	TxID           *TxID  `json:"txid,omitempty"`
+	TotalInputs    Amount `json:"totalinputs"`
+	TotalOutputs   Amount `json:"totaloutputs"`
+	TotalECOutputs Amount `json:"totalecoutputs"`
+	FeesRequired   Amount `json:"feesrequired,omitempty"`
+	Signed         bool   `json:"signed"`
+}
+
+type walletTxParams struct {
+	Name    string      `json:"tx-name"`
+	Address interface{} `json:"address,omitempty"`
+	Amount  uint64      `json:"amount,omitempty"`
+}
+
+func (tx *WalletTransaction) request(ctx context.Context, c *Client,
+	method string, params walletTxParams) error {
+	if len(tx.Name) == 0 {
+		return fmt.Errorf("missing transaction name")
+	}
+	params.Name = tx.Name
+	return c.WalletdRequest(ctx, method, params, tx)
+}
+
+// Create a new temporary transaction named tx.Name in factom-walletd.
+func (tx *WalletTransaction) Create(ctx context.Context, c *Client) error {
+	return tx.request(ctx, c, "new-transaction", walletTxParams{})
+}
+
+// Delete the temporary transaction named tx.Name from factom-walletd.
+func (tx WalletTransaction) Delete(ctx context.Context, c *Client) error {
+	params := walletTxParams{Name: tx.Name}
+	return c.WalletdRequest(ctx, "delete-transaction", params, nil)
+}
+
+// AddInput adds adr as an input of amount factoshis.
+func (tx *WalletTransaction) AddInput(ctx context.Context, c *Client,
+	adr FAAddress, amount uint64) error {
+	return tx.request(ctx, c, "add-input",
+		walletTxParams{Address: adr, Amount: amount})
+}
+
+// AddOutput adds adr as an output of amount factoshis.
+func (tx *WalletTransaction) AddOutput(ctx context.Context, c *Client,
+	adr FAAddress, amount uint64) error {
+	return tx.request(ctx, c, "add-output",
+		walletTxParams{Address: adr, Amount: amount})
+}
+
+// AddECOutput adds adr as an Entry Credit output that is purchased with
+// amount factoshis.
+func (tx *WalletTransaction) AddECOutput(ctx context.Context, c *Client,
+	adr ECAddress, amount uint64) error {
+	return tx.request(ctx, c, "add-ec-output",
+		walletTxParams{Address: adr, Amount: amount})
+}
+
+// AddFee increases the input from adr to pay the required fee.
+func (tx *WalletTransaction) AddFee(ctx context.Context, c *Client,
+	adr FAAddress) error {
+	return tx.request(ctx, c, "add-fee", walletTxParams{Address: adr})
+}
+
+// SubFee decreases the output to adr to pay the required fee.
+func (tx *WalletTransaction) SubFee(ctx context.Context, c *Client,
+	adr FAAddress) error {
+	return tx.request(ctx, c, "sub-fee", walletTxParams{Address: adr})
+}
+
+// Sign the transaction with the keys of its inputs in factom-walletd.
+func (tx *WalletTransaction) Sign(ctx context.Context, c *Client) error {
+	return tx.request(ctx, c, "sign-transaction", walletTxParams{})
+}
+
+// Compose returns the signed binary Transaction, which may be submitted with
+// Client.FactoidSubmit.
+func (tx WalletTransaction) Compose(ctx context.Context, c *Client) ([]byte, error) {
+	params := walletTxParams{Name: tx.Name}
+	var result struct {
+		Params struct {
+			Transaction Bytes `json:"transaction"`
+		} `json:"params"`
+	}
+	if err := c.WalletdRequest(ctx, "compose-transaction",
+		params, &result); err != nil {
+		return nil, err
+	}
+	return result.Params.Transaction, nil
+}
+
+// SignWith composes the unsigned transaction in factom-walletd and signs it
+// locally with the signingSet, such as a hardware wallet or HSM, rather than
+// with keys held by factom-walletd. The signingSet may be in any order, but
+// must include an RCDSigner for each input. The returned Transaction is
+// signed and may be submitted with Client.FactoidSubmit.
+//
+// Only the inputs, outputs, and timestamp of the composed transaction are
+// used. Any RCDs or signatures added by factom-walletd are discarded.
+func (tx WalletTransaction) SignWith(ctx context.Context, c *Client,
+	signingSet ...RCDSigner) (Transaction, []byte, error) {
+	data, err := tx.Compose(ctx, c)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+
+	var signed Transaction
+	if _, err := signed.unmarshalBinaryLedger(data); err != nil {
+		return Transaction{}, nil, fmt.Errorf("compose-transaction: %w", err)
+	}
+	signed.Signatures = make([]RCDSignature, len(signed.FCTInputs))
+
+	signers := make([]RCDSigner, len(signed.FCTInputs))
+	for i, input := range signed.FCTInputs {
+		adr := input.FAAddress()
+		for _, signer := range signingSet {
+			if signer.RCD().Hash() == Bytes32(adr) {
+				signers[i] = signer
+				break
+			}
+		}
+		if signers[i] == nil {
+			return Transaction{}, nil,
+				fmt.Errorf("missing RCDSigner for input %v", adr)
+		}
+	}
+
+	if data, err = signed.Sign(signers...); err != nil {
+		return Transaction{}, nil, err
+	}
+	return signed, data, nil
+}
+
+// SubmitWith signs the transaction with SignWith and submits it with
+// Client.FactoidSubmit, so factom-walletd never signs the transaction. The
+// TxID of the submitted Transaction is returned.
+func (tx WalletTransaction) SubmitWith(ctx context.Context, c *Client,
+	signingSet ...RCDSigner) (TxID, error) {
+	signed, data, err := tx.SignWith(ctx, c, signingSet...)
+	if err != nil {
+		return TxID{}, err
+	}
+	if err := c.FactoidSubmit(ctx, data); err != nil {
+		return TxID{}, err
+	}
+	return *signed.ID, nil
+}
+
+// GetWalletTransactions returns all temporary transactions in factom-walletd.
+func (c *Client) GetWalletTransactions(
+	ctx context.Context) ([]WalletTransaction, error) {
+	var result struct {
+		Transactions []struct {
+			Name string `json:"tx-name"`
+			WalletTransaction
+		} `json:"transactions"`
+	}
+	if err := c.WalletdRequest(ctx, "tmp-transactions",
+		nil, &result); err != nil {
+		return nil, err
+	}
+	txs := make([]WalletTransaction, len(result.Transactions))
+	for i, tx := range result.Transactions {
+		txs[i] = tx.WalletTransaction
+		if len(tx.Name) > 0 {
+			txs[i].Name = tx.Name
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,193 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletTransaction(t *testing.T) {
+	ctx := context.Background()
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	fa := fs.FAAddress()
+	var ec ECAddress
+
+	type request struct {
+		Method string
+		Params map[string]interface{}
+	}
+	var requests []request
+	c := NewClient()
+	c.Walletd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			ID     interface{}            `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		data, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(data, &jReq)
+		requests = append(requests, request{jReq.Method, jReq.Params})
+
+		var result interface{} = map[string]interface{}{
+			"name": jReq.Params["tx-name"], "totalinputs": 10,
+			"signed": jReq.Method == "sign-transaction"}
+		switch jReq.Method {
+		case "compose-transaction":
+			result = map[string]interface{}{
+				"method": "factoid-submit",
+				"params": map[string]interface{}{
+					"transaction": "0102"}}
+		case "tmp-transactions":
+			result = map[string]interface{}{
+				"transactions": []interface{}{
+					map[string]interface{}{
+						"tx-name":     "rent",
+						"totalinputs": 10}}}
+		}
+		data, _ = json.Marshal(jsonrpc2.Response{ID: jReq.ID,
+			Result: result})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(data)),
+			Header:     make(http.Header),
+		}
+	})
+
+	tx := WalletTransaction{Name: "rent"}
+	require.NoError(t, tx.Create(ctx, c))
+	require.NoError(t, tx.AddInput(ctx, c, fa, 10))
+	require.NoError(t, tx.AddOutput(ctx, c, fa, 5))
+	require.NoError(t, tx.AddECOutput(ctx, c, ec, 5))
+	require.NoError(t, tx.AddFee(ctx, c, fa))
+	require.NoError(t, tx.SubFee(ctx, c, fa))
+	assert.False(t, tx.Signed)
+	require.NoError(t, tx.Sign(ctx, c))
+	assert.True(t, tx.Signed)
+	assert.Equal(t, Amount(10), tx.TotalInputs)
+	assert.Equal(t, "rent", tx.Name)
+
+	raw, err := tx.Compose(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, raw)
+
+	txs, err := c.GetWalletTransactions(ctx)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, "rent", txs[0].Name)
+	assert.Equal(t, Amount(10), txs[0].TotalInputs)
+
+	require.NoError(t, tx.Delete(ctx, c))
+
+	methods := []string{"new-transaction", "add-input", "add-output",
+		"add-ec-output", "add-fee", "sub-fee", "sign-transaction",
+		"compose-transaction", "tmp-transactions", "delete-transaction"}
+	require.Len(t, requests, len(methods))
+	for i, method := range methods {
+		assert.Equal(t, method, requests[i].Method)
+		if method == "tmp-transactions" {
+			continue
+		}
+		assert.Equal(t, "rent", requests[i].Params["tx-name"])
+	}
+	assert.Equal(t, fa.String(), requests[1].Params["address"])
+	assert.Equal(t, float64(10), requests[1].Params["amount"])
+	assert.Equal(t, ec.String(), requests[3].Params["address"])
+
+	assert.EqualError(t, new(WalletTransaction).Create(ctx, c),
+		"missing transaction name")
+}
+
+func TestWalletTransactionSignWith(t *testing.T) {
+	ctx := context.Background()
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	fa := fs.FAAddress()
+	other, err := GenerateFsAddress()
+	require.NoError(t, err)
+	out := other.FAAddress()
+
+	unsigned := Transaction{
+		TimestampSalt: time.Unix(1500000000, 0),
+		FCTInputs:     []AddressAmount{{Address: fa[:], Amount: 1000}},
+		FCTOutputs:    []AddressAmount{{Address: out[:], Amount: 900}},
+		Signatures:    make([]RCDSignature, 1),
+	}
+	_, err = unsigned.Sign(fs)
+	require.NoError(t, err)
+	ledger, err := unsigned.MarshalBinaryLedger()
+	require.NoError(t, err)
+
+	var submitted Bytes
+	c := newMockClient(t, map[string]interface{}{
+		"compose-transaction": map[string]interface{}{
+			"params": map[string]interface{}{
+				"transaction": Bytes(ledger)}},
+		"factoid-submit": map[string]interface{}{},
+	})
+	tx := WalletTransaction{Name: "hsm"}
+
+	_, _, err = tx.SignWith(ctx, c, other)
+	assert.EqualError(t, err, "missing RCDSigner for input "+fa.String())
+
+	signed, data, err := tx.SignWith(ctx, c, other, fs)
+	require.NoError(t, err)
+	assert.Equal(t, unsigned.ID, signed.ID)
+	var parsed Transaction
+	require.NoError(t, parsed.UnmarshalBinary(data))
+	assert.Equal(t, *unsigned.ID, *parsed.ID)
+
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq struct {
+			ID     interface{} `json:"id"`
+			Params struct {
+				Transaction Bytes `json:"transaction"`
+			} `json:"params"`
+		}
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+		submitted = jReq.Params.Transaction
+		respData, _ := json.Marshal(jsonrpc2.Response{ID: jReq.ID,
+			Result: map[string]interface{}{}})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+	txID, err := tx.SubmitWith(ctx, c, fs)
+	require.NoError(t, err)
+	assert.Equal(t, *unsigned.ID, txID)
+	assert.Equal(t, Bytes(data), submitted)
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// ActivityKind distinguishes credits to and debits from an address.
+type ActivityKind int
+
+const (
+	// ActivityCredit is an FCTOutput to the address.
+	ActivityCredit ActivityKind = iota
+
+	// ActivityDebit is an FCTInput from the address.
+	ActivityDebit
+)
+
+// String returns "credit" or "debit".
+func (k ActivityKind) String() string {
+	switch k {
+	case ActivityCredit:
+		return "credit"
+	case ActivityDebit:
+		return "debit"
+	default:
+		return "unknown"
+	}
+}
+
+// AddressActivity is a single credit to or debit from an FAAddress by a
+// Factoid Transaction.
+type AddressActivity struct {
+	Kind ActivityKind
+
+	// Height of the FBlock that includes the Transaction.
+	Height      uint32
+	Transaction Transaction
+
+	// Amount in factoshis credited to or debited from the address.
+	Amount uint64
+
+	// Counterparties are the FCTInputs of a credit, or the FCTOutputs and
+	// ECOutputs of a debit. The Counterparties of a coinbase credit are
+	// empty.
+	Counterparties []AddressAmount
+}
+
+// AddressActivity returns all credits to and debits from adr by the
+// Transactions in fb, in order. A Transaction that both debits and credits
+// adr, such as one that returns change, yields a debit followed by a credit.
+func (fb FBlock) AddressActivity(adr FAAddress) []AddressActivity {
+	var activity []AddressActivity
+	for _, tx := range fb.Transactions {
+		for _, input := range tx.FCTInputs {
+			if !bytes.Equal(input.Address, adr[:]) {
+				continue
+			}
+			counterparties := make([]AddressAmount, 0,
+				len(tx.FCTOutputs)+len(tx.ECOutputs))
+			counterparties = append(counterparties, tx.FCTOutputs...)
+			counterparties = append(counterparties, tx.ECOutputs...)
+			activity = append(activity, AddressActivity{
+				Kind:           ActivityDebit,
+				Height:         fb.Height,
+				Transaction:    tx,
+				Amount:         input.Amount,
+				Counterparties: counterparties,
+			})
+		}
+		for _, output := range tx.FCTOutputs {
+			if !bytes.Equal(output.Address, adr[:]) {
+				continue
+			}
+			activity = append(activity, AddressActivity{
+				Kind:           ActivityCredit,
+				Height:         fb.Height,
+				Transaction:    tx,
+				Amount:         output.Amount,
+				Counterparties: tx.FCTInputs,
+			})
+		}
+	}
+	return activity
+}
+
+// BlockPollInterval is how often Client.WatchAddress and
+// Client.WaitForConfirmations poll factomd for new blocks.
+const BlockPollInterval = 10 * time.Second
+
+// WatchAddress calls handler with each credit to and debit from adr in every
+// FBlock from height onward, as the FBlocks are saved by factomd. This is the
+// basis for detecting deposits.
+//
+// WatchAddress blocks until ctx is done or handler returns an error, and
+// returns that error. Since every FBlock from height onward is handled in
+// order, a caller may resume after a restart by passing one more than the
+// last Height it handled.
+func (c *Client) WatchAddress(ctx context.Context, adr FAAddress,
+	height uint32, handler func(AddressActivity) error) error {
+	for {
+		var heights Heights
+		if err := heights.Get(ctx, c); err != nil {
+			return err
+		}
+		for ; height <= heights.DirectoryBlock; height++ {
+			fb, err := c.FBlockByHeight(ctx, height)
+			if err != nil {
+				return err
+			}
+			for _, activity := range fb.AddressActivity(adr) {
+				if err := handler(activity); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock().After(BlockPollInterval):
+		}
+	}
+}
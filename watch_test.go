@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package factom_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdamSLevy/jsonrpc2/v14"
+	. "github.com/Factom-Asset-Tokens/factom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFBlockData returns the binary FBlock at height with txs.
+func mockFBlockData(t *testing.T, height uint32, txs ...Transaction) []byte {
+	data := make([]byte, 32*4+8+4+1+4+4)
+	data[31] = 0x0f // Factoid ChainID
+	binary.BigEndian.PutUint64(data[128:], 1000)
+	binary.BigEndian.PutUint32(data[136:], height)
+	binary.BigEndian.PutUint32(data[141:], uint32(len(txs)))
+	var body []byte
+	for _, tx := range txs {
+		txData, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		body = append(body, txData...)
+	}
+	body = append(body, bytes.Repeat([]byte{FBlockMinuteMarker}, 10)...)
+	binary.BigEndian.PutUint32(data[145:], uint32(len(body)))
+	return append(data, body...)
+}
+
+func newMockTransaction(t *testing.T, fs FsAddress,
+	outputs ...AddressAmount) Transaction {
+	var total uint64
+	for _, output := range outputs {
+		total += output.Amount
+	}
+	fa := fs.FAAddress()
+	tx := Transaction{
+		TimestampSalt: time.Now(),
+		FCTInputs:     []AddressAmount{{Amount: total, Address: fa[:]}},
+		FCTOutputs:    outputs,
+		Signatures:    make([]RCDSignature, 1),
+	}
+	_, err := tx.Sign(fs)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestWatchAddress(t *testing.T) {
+	fs, err := GenerateFsAddress()
+	require.NoError(t, err)
+	fa := fs.FAAddress()
+	other, err := GenerateFsAddress()
+	require.NoError(t, err)
+	otherFA := other.FAAddress()
+
+	deposit := newMockTransaction(t, other,
+		AddressAmount{Amount: 5e8, Address: fa[:]})
+	unrelated := newMockTransaction(t, other,
+		AddressAmount{Amount: 1e8, Address: otherFA[:]})
+	withdrawal := newMockTransaction(t, fs,
+		AddressAmount{Amount: 2e8, Address: otherFA[:]},
+		AddressAmount{Amount: 1e8, Address: fa[:]})
+
+	fblocks := map[uint32][]byte{
+		10: mockFBlockData(t, 10, deposit, unrelated),
+		11: mockFBlockData(t, 11),
+		12: mockFBlockData(t, 12, withdrawal),
+	}
+
+	c := NewClient()
+	c.Factomd.Client = *NewTestClient(func(req *http.Request) *http.Response {
+		var jReq jsonrpc2.Request
+		reqData, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(reqData, &jReq)
+
+		var result interface{}
+		switch jReq.Method {
+		case "heights":
+			result = Heights{DirectoryBlock: 12}
+		case "fblock-by-height":
+			var params struct{ Height uint32 }
+			data, _ := json.Marshal(jReq.Params)
+			_ = json.Unmarshal(data, &params)
+			result = map[string]Bytes{"rawdata": fblocks[params.Height]}
+		default:
+			t.Errorf("unexpected request: %v", jReq.Method)
+		}
+		respData, _ := json.Marshal(jsonrpc2.Response{
+			Result: result,
+			ID:     jReq.ID,
+		})
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBuffer(respData)),
+			Header:     make(http.Header),
+		}
+	})
+
+	t.Run("activity", func(t *testing.T) {
+		assert := assert.New(t)
+		var activity []AddressActivity
+		ctx, cancel := context.WithTimeout(context.Background(),
+			100*time.Millisecond)
+		defer cancel()
+		err := c.WatchAddress(ctx, fa, 10,
+			func(a AddressActivity) error {
+				activity = append(activity, a)
+				return nil
+			})
+		assert.Equal(context.DeadlineExceeded, err)
+
+		require.Len(t, activity, 3)
+
+		assert.Equal(ActivityCredit, activity[0].Kind)
+		assert.Equal(uint32(10), activity[0].Height)
+		assert.Equal(uint64(5e8), activity[0].Amount)
+		assert.Equal(deposit.ID, activity[0].Transaction.ID)
+		assert.Equal(deposit.FCTInputs, activity[0].Counterparties)
+
+		assert.Equal(ActivityDebit, activity[1].Kind)
+		assert.Equal(uint32(12), activity[1].Height)
+		assert.Equal(uint64(3e8), activity[1].Amount)
+		assert.Equal(withdrawal.FCTOutputs, activity[1].Counterparties)
+
+		assert.Equal(ActivityCredit, activity[2].Kind)
+		assert.Equal(uint64(1e8), activity[2].Amount)
+		assert.Equal(withdrawal.ID, activity[2].Transaction.ID)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		errStop := fmt.Errorf("stop")
+		var heights []uint32
+		err := c.WatchAddress(context.Background(), fa, 11,
+			func(a AddressActivity) error {
+				heights = append(heights, a.Height)
+				return errStop
+			})
+		assert.Equal(t, errStop, err)
+		assert.Equal(t, []uint32{12}, heights)
+	})
+}
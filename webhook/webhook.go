@@ -0,0 +1,331 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+// Package webhook delivers the Events of a factom.EventBus to HTTP endpoints,
+// so that services that do not use this package may receive chain events.
+//
+// Each Event is encoded with factom.MarshalEventJSON and sent as the body of
+// an HTTP POST with the headers
+//
+//	Content-Type: application/json
+//	X-Factom-Delivery: <unique hex delivery ID>
+//	X-Factom-Timestamp: <unix seconds>
+//	X-Factom-Signature: sha256=<hex HMAC-SHA256>
+//
+// where the signature is the HMAC-SHA256, keyed by the shared secret, of the
+// timestamp, a ".", and the body. Receivers should check it with
+// VerifySignature and reject stale timestamps to prevent replays, and may use
+// the delivery ID to ignore retried deliveries they have already processed.
+//
+// Deliveries that fail are retried with exponential backoff. Deliveries that
+// still fail, or that the endpoint rejects with a 4xx status other than 408
+// or 429, are added to the DeadLetters.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+)
+
+// HTTP headers of each delivery.
+const (
+	HeaderDelivery  = "X-Factom-Delivery"
+	HeaderTimestamp = "X-Factom-Timestamp"
+	HeaderSignature = "X-Factom-Signature"
+)
+
+// Defaults used for zero Dispatcher fields.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+	DefaultQueueSize   = 1024
+)
+
+// Delivery is a single webhook request body and its delivery state.
+type Delivery struct {
+	ID   string
+	Body []byte
+
+	// Attempts is the number of failed attempts, and Err is the error
+	// from the last attempt.
+	Attempts int
+	Err      error
+}
+
+// DeadLetters receives the Deliveries that a Dispatcher gave up on, so that
+// they may be inspected or redelivered with Dispatcher.Redeliver.
+type DeadLetters interface {
+	Put(d Delivery) error
+}
+
+// MemoryDeadLetters holds dead Deliveries in memory. It is safe for
+// concurrent use. The zero value is ready to use.
+type MemoryDeadLetters struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+var _ DeadLetters = &MemoryDeadLetters{}
+
+// Put appends d.
+func (l *MemoryDeadLetters) Put(d Delivery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.deliveries = append(l.deliveries, d)
+	return nil
+}
+
+// Take returns and removes all dead Deliveries.
+func (l *MemoryDeadLetters) Take() []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	deliveries := l.deliveries
+	l.deliveries = nil
+	return deliveries
+}
+
+// Len returns the number of dead Deliveries.
+func (l *MemoryDeadLetters) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.deliveries)
+}
+
+// Dispatcher queues Events and POSTs them to URL, one at a time and in order,
+// from Run.
+//
+// The exported fields must not be changed after the first call to Dispatch.
+type Dispatcher struct {
+	URL    string
+	Secret []byte
+
+	// Client is used to send requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// MaxAttempts is the number of attempts per Delivery. If zero,
+	// DefaultMaxAttempts is used.
+	MaxAttempts int
+
+	// Backoff is the wait after the first failed attempt, which doubles
+	// after each further failure. If zero, DefaultBackoff is used.
+	Backoff time.Duration
+
+	// QueueSize is the number of Deliveries that may wait for Run. Events
+	// dispatched while the queue is full go to DeadLetters. If zero,
+	// DefaultQueueSize is used.
+	QueueSize int
+
+	// DeadLetters, if not nil, receives Deliveries that failed.
+	DeadLetters DeadLetters
+
+	// Clock, if not nil, is used instead of package time for timestamps
+	// and backoff.
+	Clock factom.Clock
+
+	once  sync.Once
+	queue chan Delivery
+}
+
+// Subscribe dispatches all Events published on bus. Call the returned func to
+// unsubscribe.
+func (d *Dispatcher) Subscribe(bus *factom.EventBus) (unsubscribe func()) {
+	return bus.OnEvent(d.Dispatch)
+}
+
+// Dispatch encodes ev and queues it for delivery by Run. If the queue is
+// full, the Delivery is put in DeadLetters instead.
+func (d *Dispatcher) Dispatch(ev factom.Event) error {
+	body, err := factom.MarshalEventJSON(ev)
+	if err != nil {
+		return err
+	}
+	id, err := newDeliveryID()
+	if err != nil {
+		return err
+	}
+	delivery := Delivery{ID: id, Body: body}
+	select {
+	case d.getQueue() <- delivery:
+		return nil
+	default:
+		delivery.Err = fmt.Errorf("queue full")
+		return d.dead(delivery)
+	}
+}
+
+// Run delivers queued Deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	queue := d.getQueue()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case delivery := <-queue:
+			if err := d.deliver(ctx, delivery); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Redeliver makes a single attempt to deliver a Delivery taken from
+// DeadLetters, and returns the error from the attempt.
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery Delivery) error {
+	_, err := d.post(ctx, delivery)
+	return err
+}
+
+// deliver attempts delivery until it succeeds, or it fails MaxAttempts times
+// or permanently, in which case it is put in DeadLetters. Only errors from ctx
+// or DeadLetters are returned.
+func (d *Dispatcher) deliver(ctx context.Context, delivery Delivery) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	backoff := d.Backoff
+	if backoff == 0 {
+		backoff = DefaultBackoff
+	}
+	for {
+		retry, err := d.post(ctx, delivery)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		delivery.Attempts++
+		delivery.Err = err
+		if !retry || delivery.Attempts >= maxAttempts {
+			return d.dead(delivery)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.clock().After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes a single delivery attempt, and returns whether a failure may be
+// retried.
+func (d *Dispatcher) post(ctx context.Context, delivery Delivery) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, d.URL,
+		bytes.NewReader(delivery.Body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	ts := strconv.FormatInt(d.clock().Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, Sign(d.Secret, ts, delivery.Body))
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	// Drain the body so the connection may be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 1<<16))
+	res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return true, nil
+	}
+	err = fmt.Errorf("http status: %v", res.Status)
+	switch {
+	case res.StatusCode == http.StatusRequestTimeout,
+		res.StatusCode == http.StatusTooManyRequests,
+		res.StatusCode >= 500:
+		return true, err
+	}
+	return false, err
+}
+
+func (d *Dispatcher) dead(delivery Delivery) error {
+	if d.DeadLetters == nil {
+		return nil
+	}
+	return d.DeadLetters.Put(delivery)
+}
+
+func (d *Dispatcher) getQueue() chan Delivery {
+	d.once.Do(func() {
+		size := d.QueueSize
+		if size == 0 {
+			size = DefaultQueueSize
+		}
+		d.queue = make(chan Delivery, size)
+	})
+	return d.queue
+}
+
+func (d *Dispatcher) clock() factom.Clock {
+	if d.Clock == nil {
+		return factom.SystemClock{}
+	}
+	return d.Clock
+}
+
+func newDeliveryID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// Sign returns the X-Factom-Signature header value for body sent with the
+// X-Factom-Timestamp header value ts.
+func Sign(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature returns true if sig is the X-Factom-Signature header value
+// for body sent with the X-Factom-Timestamp header value ts.
+func VerifySignature(secret []byte, ts string, body []byte, sig string) bool {
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(sig))
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright 2018 Canonical Ledgers, LLC
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or
+// sell copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS
+// IN THE SOFTWARE.
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Factom-Asset-Tokens/factom"
+	. "github.com/Factom-Asset-Tokens/factom/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	secret := []byte("secret")
+	var mu sync.Mutex
+	var requests int
+	statuses := []int{500, 429, 200, 400}
+	received := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal("application/json",
+				r.Header.Get("Content-Type"))
+			assert.NotEmpty(r.Header.Get(HeaderDelivery))
+			assert.True(VerifySignature(secret,
+				r.Header.Get(HeaderTimestamp), body,
+				r.Header.Get(HeaderSignature)))
+
+			mu.Lock()
+			status := statuses[requests]
+			requests++
+			mu.Unlock()
+			w.WriteHeader(status)
+			if status == 200 {
+				var ev map[string]interface{}
+				assert.NoError(json.Unmarshal(body, &ev))
+				received <- ev
+			}
+		}))
+	defer server.Close()
+
+	var dead MemoryDeadLetters
+	d := Dispatcher{
+		URL:         server.URL,
+		Secret:      secret,
+		Backoff:     time.Millisecond,
+		QueueSize:   2,
+		DeadLetters: &dead,
+	}
+	var bus factom.EventBus
+	unsubscribe := d.Subscribe(&bus)
+	defer unsubscribe()
+
+	// The first Event is delivered after two retries, and the second is
+	// rejected.
+	require.NoError(bus.Publish(factom.AckEvent{
+		Hash: factom.EntryHash{1}, Status: "TransactionACK"}))
+	require.NoError(bus.Publish(factom.AckEvent{
+		Hash: factom.EntryHash{2}, Status: "TransactionACK"}))
+	// The queue is full.
+	require.NoError(bus.Publish(factom.AckEvent{Hash: factom.EntryHash{3}}))
+	require.Equal(1, dead.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+
+	select {
+	case ev := <-received:
+		assert.Equal("ack", ev["type"])
+		assert.Equal(factom.Bytes32{1}.String(), ev["entryhash"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+	for dead.Len() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assert.Equal(context.Canceled, <-done)
+
+	deliveries := dead.Take()
+	require.Len(deliveries, 2)
+	assert.EqualError(deliveries[0].Err, "queue full")
+	assert.Equal(1, deliveries[1].Attempts)
+	assert.EqualError(deliveries[1].Err, "http status: 400 Bad Request")
+
+	// Dead Deliveries may be redelivered.
+	mu.Lock()
+	statuses = append(statuses, 200)
+	mu.Unlock()
+	require.NoError(d.Redeliver(context.Background(), deliveries[1]))
+	ev := <-received
+	assert.Equal(factom.Bytes32{2}.String(), ev["entryhash"])
+}
+
+func TestDispatcherMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(503)
+		}))
+	defer server.Close()
+
+	var dead MemoryDeadLetters
+	d := Dispatcher{URL: server.URL, MaxAttempts: 3,
+		Backoff: time.Millisecond, DeadLetters: &dead}
+	require.NoError(t, d.Dispatch(factom.AckEvent{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+	for dead.Len() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 3, dead.Take()[0].Attempts)
+}